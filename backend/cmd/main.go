@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -12,6 +13,9 @@ import (
 	"wosm/internal/api/v1/tool"
 	"wosm/internal/config"
 	"wosm/internal/constants"
+	"wosm/internal/repository/dao"
+	"wosm/internal/repository/model"
+	genRouter "wosm/internal/router"
 	authService "wosm/internal/service/auth"
 	systemService "wosm/internal/service/system"
 	"wosm/pkg/database"
@@ -63,6 +67,33 @@ func main() {
 	logger.Info("数据库连接成功")
 	defer database.Close()
 
+	// 数据库迁移子命令: wosm migrate up|down|status|baseline，执行完退出
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(migrateArgs(os.Args[2:])); err != nil {
+			database.Close()
+			log.Fatalf("数据库迁移失败: %v", err)
+		}
+		return
+	}
+	if config.AppConfig.Database.AutoMigrate {
+		if err := autoMigrate(); err != nil {
+			logger.Fatal("数据库迁移失败", zap.Error(err))
+		}
+	}
+
+	// 记录用户、角色、部门、菜单、参数和字典数据的字段变更历史
+	if err := database.DB.Use(dao.NewDataChangePlugin()); err != nil {
+		logger.Fatal("数据变更记录插件注册失败", zap.Error(err))
+	}
+
+	// 租户模式：按登录用户的租户过滤和填充租户隔离的表
+	if config.IsTenantEnabled() {
+		if err := database.DB.Use(dao.NewTenantPlugin()); err != nil {
+			logger.Fatal("多租户插件注册失败", zap.Error(err))
+		}
+		logger.Info("已开启租户模式")
+	}
+
 	// 4. 初始化Redis连接
 	if err := redis.InitRedis(); err != nil {
 		logger.Fatal("Redis初始化失败", zap.Error(err))
@@ -100,18 +131,22 @@ func setupRouter() *gin.Engine {
 	// 添加中间件
 	router.Use(gin.Logger())
 	router.Use(gin.Recovery())
+	router.Use(middleware.RequestIDMiddleware())
+	router.Use(middleware.SQLMonitorMiddleware())  // SQL监控中间件，发现请求中重复执行的SQL（N+1查询）
+	router.Use(middleware.ReadPrimaryMiddleware()) // 读写分离中间件，写请求之后的查询读主库
 	router.Use(middleware.CorsMiddleware())
 	router.Use(middleware.I18nMiddleware())
 	router.Use(middleware.MessageMiddleware())
 	router.Use(middleware.RepeatSubmitMiddleware()) // 防重复提交中间件 对应Java后端的RepeatSubmitInterceptor
 
-	// 加载字典缓存
+	// 加载字典缓存 启动时按平台租户加载
+	platformCtx := database.WithRequestTenant(context.Background(), model.DefaultTenantID)
 	dictTypeService := systemService.NewDictTypeService()
-	dictTypeService.LoadingDictCache()
+	dictTypeService.WithContext(platformCtx).LoadingDictCache()
 
 	// 初始化和加载参数配置缓存
 	configService := systemService.NewConfigService()
-	configService.InitDefaultConfigs()
+	configService.WithContext(platformCtx).InitDefaultConfigs()
 	configService.LoadingConfigCache()
 
 	// 初始化国际化系统
@@ -137,15 +172,22 @@ func setupRouter() *gin.Engine {
 	configController := system.NewConfigController()
 	i18nController := system.NewI18nController()
 	noticeController := system.NewNoticeController()
+	recycleController := system.NewRecycleController()
+	tenantController := system.NewTenantController()
 	operLogController := monitor.NewOperLogController()
 	loginLogController := monitor.NewLoginLogController()
 	onlineController := monitor.NewOnlineController()
 	serverController := monitor.NewServerController()
 	cacheController := monitor.NewCacheController()
 	druidController := monitor.NewDruidController()
+	sqlMonitorController := monitor.NewSqlMonitorController()
 	jobController := monitor.NewJobController()
 	jobLogController := monitor.NewJobLogController() // 新增定时任务调度日志控制器
+	jobCalendarController := monitor.NewJobCalendarController()
+	dataChangeController := monitor.NewDataChangeController()
 	genController := tool.NewGenController()
+	genTemplateController := tool.NewGenTemplateController()
+	dynController := tool.NewDynController()
 	swaggerController := tool.NewSwaggerController()
 	testController := tool.NewTestController()
 
@@ -250,7 +292,7 @@ func setupRouter() *gin.Engine {
 		systemUser := protected.Group("/system/user")
 		{
 			// 对应Java后端 @PreAuthorize("@ss.hasPermi('system:user:list')")
-			systemUser.GET("/list", middleware.WithPermissionAndDataScope("system:user:list", "d", "u", userController.List))
+			systemUser.GET("/list", middleware.WithPermissionAndDataScope("system:user:list", userController.List))
 			// 对应Java后端 @PreAuthorize("@ss.hasPermi('system:user:query')")
 			systemUser.GET("/", middleware.WithPermission("system:user:query", userController.GetInfo))        // 新增用户时获取初始化数据
 			systemUser.GET("/:userId", middleware.WithPermission("system:user:query", userController.GetInfo)) // 编辑用户时获取用户详情
@@ -271,7 +313,7 @@ func setupRouter() *gin.Engine {
 			// 对应Java后端 @PreAuthorize("@ss.hasPermi('system:user:list')")
 			systemUser.GET("/deptTree", middleware.WithPermission("system:user:list", userController.DeptTree))
 			// 对应Java后端 @PreAuthorize("@ss.hasPermi('system:user:export')")
-			systemUser.POST("/export", middleware.WithPermissionAndDataScope("system:user:export", "d", "u", userController.Export))
+			systemUser.POST("/export", middleware.WithPermissionAndDataScope("system:user:export", userController.Export))
 			// 对应Java后端 @PreAuthorize("@ss.hasPermi('system:user:import')")
 			systemUser.POST("/importData", middleware.WithPermission("system:user:import", userController.ImportData))
 			systemUser.POST("/importTemplate", middleware.WithPermission("system:user:import", userController.ImportTemplate))
@@ -305,9 +347,9 @@ func setupRouter() *gin.Engine {
 			// 对应Java后端 @PreAuthorize("@ss.hasPermi('system:role:query')")
 			systemRole.GET("/optionselect", middleware.WithPermission("system:role:query", roleController.OptionSelect))
 			// 对应Java后端 @PreAuthorize("@ss.hasPermi('system:role:list')")
-			systemRole.GET("/authUser/allocatedList", middleware.WithPermissionAndDataScope("system:role:list", "d", "u", roleController.AllocatedList))
+			systemRole.GET("/authUser/allocatedList", middleware.WithPermissionAndDataScope("system:role:list", roleController.AllocatedList))
 			// 对应Java后端 @PreAuthorize("@ss.hasPermi('system:role:list')")
-			systemRole.GET("/authUser/unallocatedList", middleware.WithPermissionAndDataScope("system:role:list", "d", "u", roleController.UnallocatedList))
+			systemRole.GET("/authUser/unallocatedList", middleware.WithPermissionAndDataScope("system:role:list", roleController.UnallocatedList))
 			// 对应Java后端 @PreAuthorize("@ss.hasPermi('system:role:edit')")
 			systemRole.PUT("/authUser/cancel", middleware.WithPermission("system:role:edit", roleController.CancelAuthUser))
 			// 对应Java后端 @PreAuthorize("@ss.hasPermi('system:role:edit')")
@@ -473,6 +515,37 @@ func setupRouter() *gin.Engine {
 			systemNotice.POST("/export", middleware.WithPermission("system:notice:export", noticeController.Export))
 		}
 
+		// 系统管理 - 回收站
+		systemRecycle := protected.Group("/system/recycle")
+		{
+			systemRecycle.GET("/list", middleware.WithPermission("system:recycle:list", recycleController.List))
+			systemRecycle.PUT("/restore/:recycleIds", middleware.WithPermission("system:recycle:restore", recycleController.Restore))
+			systemRecycle.DELETE("/:recycleIds", middleware.WithPermission("system:recycle:remove", recycleController.Remove))
+		}
+
+		// 系统管理 - 租户管理和租户套餐（开启租户模式时使用，只有平台租户可以访问）
+		systemTenant := protected.Group("/system/tenant")
+		{
+			systemTenant.GET("/list", middleware.WithPermission("system:tenant:list", tenantController.List))
+			systemTenant.GET("/optionselect", middleware.WithPermission("system:tenant:query", tenantController.OptionSelect))
+			systemTenant.GET("/:tenantId", middleware.WithPermission("system:tenant:query", tenantController.GetInfo))
+			systemTenant.POST("", middleware.WithPermission("system:tenant:add", tenantController.Add))
+			systemTenant.PUT("", middleware.WithPermission("system:tenant:edit", tenantController.Edit))
+			systemTenant.DELETE("/:tenantIds", middleware.WithPermission("system:tenant:remove", tenantController.Remove))
+			// 超级管理员切换租户
+			systemTenant.GET("/dynamic/clear", tenantController.ClearDynamicTenant)
+			systemTenant.GET("/dynamic/:tenantId", tenantController.DynamicTenant)
+		}
+		systemTenantPackage := protected.Group("/system/tenant/package")
+		{
+			systemTenantPackage.GET("/list", middleware.WithPermission("system:tenantPackage:list", tenantController.PackageList))
+			systemTenantPackage.GET("/optionselect", middleware.WithPermission("system:tenantPackage:query", tenantController.PackageOptionSelect))
+			systemTenantPackage.GET("/:packageId", middleware.WithPermission("system:tenantPackage:query", tenantController.PackageInfo))
+			systemTenantPackage.POST("", middleware.WithPermission("system:tenantPackage:add", tenantController.PackageAdd))
+			systemTenantPackage.PUT("", middleware.WithPermission("system:tenantPackage:edit", tenantController.PackageEdit))
+			systemTenantPackage.DELETE("/:packageIds", middleware.WithPermission("system:tenantPackage:remove", tenantController.PackageRemove))
+		}
+
 		// 系统监控 - 操作日志管理
 		monitorOperLog := protected.Group("/monitor/operlog")
 		{
@@ -488,6 +561,12 @@ func setupRouter() *gin.Engine {
 			monitorOperLog.POST("/export", middleware.WithPermission("monitor:operlog:export", operLogController.Export))
 		}
 
+		// 系统监控 - 数据变更历史（操作日志下的按钮权限）
+		monitorHistory := protected.Group("/monitor/history")
+		{
+			monitorHistory.GET("/:entity/:id", middleware.WithPermission("monitor:history:list", dataChangeController.History))
+		}
+
 		// 系统监控 - 登录日志管理
 		monitorLoginLog := protected.Group("/monitor/logininfor")
 		{
@@ -521,6 +600,12 @@ func setupRouter() *gin.Engine {
 			monitorServer.GET("", middleware.WithPermission("monitor:server:list", serverController.GetInfo))
 		}
 
+		// 系统监控 - 慢SQL监控（与数据监控共用权限）
+		monitorSql := protected.Group("/monitor/sql")
+		{
+			monitorSql.GET("/slow", middleware.WithPermission("monitor:druid:list", sqlMonitorController.Slow))
+		}
+
 		// 系统监控 - 缓存监控
 		monitorCache := protected.Group("/monitor/cache")
 		{
@@ -540,6 +625,10 @@ func setupRouter() *gin.Engine {
 		{
 			// 对应Java后端 @PreAuthorize("@ss.hasPermi('monitor:job:list')")
 			monitorJob.GET("/list", middleware.WithPermission("monitor:job:list", jobController.List))
+			// 任务依赖关系图
+			monitorJob.GET("/graph", middleware.WithPermission("monitor:job:list", jobController.Graph))
+			// cron表达式未来执行时间
+			monitorJob.GET("/nextTimes", middleware.WithPermission("monitor:job:query", jobController.NextTimes))
 			// 对应Java后端 @PreAuthorize("@ss.hasPermi('monitor:job:query')")
 			monitorJob.GET("/:jobId", middleware.WithPermission("monitor:job:query", jobController.GetInfo))
 			// 对应Java后端 @PreAuthorize("@ss.hasPermi('monitor:job:add')")
@@ -563,6 +652,11 @@ func setupRouter() *gin.Engine {
 			monitorJobLog.GET("/list", middleware.WithPermission("monitor:job:list", jobLogController.List))
 			// 对应Java后端 @PreAuthorize("@ss.hasPermi('monitor:job:query')")
 			monitorJobLog.GET("/:jobLogId", middleware.WithPermission("monitor:job:query", jobLogController.GetInfo))
+			// 任务实时执行日志（Server-Sent Events）
+			monitorJobLog.GET("/stream/:jobLogId", middleware.WithPermission("monitor:job:query", jobLogController.Stream))
+			// 任务完整执行日志
+			monitorJobLog.GET("/content/:jobLogId", middleware.WithPermission("monitor:job:query", jobLogController.Content))
+			monitorJobLog.POST("/content/:jobLogId/export", middleware.WithPermission("monitor:job:export", jobLogController.ExportContent))
 			// 对应Java后端 @PreAuthorize("@ss.hasPermi('monitor:job:remove')")
 			monitorJobLog.DELETE("/:jobLogIds", middleware.WithPermission("monitor:job:remove", jobLogController.Remove))
 			// 对应Java后端 @PreAuthorize("@ss.hasPermi('monitor:job:remove')")
@@ -571,6 +665,17 @@ func setupRouter() *gin.Engine {
 			monitorJobLog.POST("/export", middleware.WithPermission("monitor:job:export", jobLogController.Export))
 		}
 
+		// 系统监控 - 定时任务排除日历（节假日、封账窗口），沿用定时任务的权限标识
+		monitorCalendar := protected.Group("/monitor/calendar")
+		{
+			monitorCalendar.GET("/list", middleware.WithPermission("monitor:job:list", jobCalendarController.List))
+			monitorCalendar.GET("/:calendarId", middleware.WithPermission("monitor:job:query", jobCalendarController.GetInfo))
+			monitorCalendar.POST("", middleware.WithPermission("monitor:job:add", jobCalendarController.Add))
+			monitorCalendar.PUT("", middleware.WithPermission("monitor:job:edit", jobCalendarController.Edit))
+			monitorCalendar.DELETE("/:calendarIds", middleware.WithPermission("monitor:job:remove", jobCalendarController.Remove))
+			monitorCalendar.POST("/:calendarId/import", middleware.WithPermission("monitor:job:edit", jobCalendarController.ImportICal))
+		}
+
 		// 系统工具 - 代码生成
 		toolGen := protected.Group("/tool/gen")
 		{
@@ -584,16 +689,27 @@ func setupRouter() *gin.Engine {
 			toolGen.PUT("", middleware.WithPermission("tool:gen:edit", genController.EditSave))
 			// 对应Java后端 @PreAuthorize("@ss.hasPermi('tool:gen:import')")
 			toolGen.POST("/importTable", middleware.WithPermission("tool:gen:import", genController.ImportTable))
+			toolGen.POST("/definition/template", middleware.WithPermission("tool:gen:import", genController.DefinitionTemplate))
+			toolGen.POST("/definition/parse", middleware.WithPermission("tool:gen:import", genController.ParseDefinition))
+			toolGen.POST("/definition/import", middleware.WithPermission("tool:gen:import", genController.ImportDefinition))
 			// 对应Java后端 @PreAuthorize("@ss.hasPermi('tool:gen:edit')")
+			toolGen.POST("/createTable/preview", middleware.WithPermission("tool:gen:edit", genController.PreviewCreateTable))
 			toolGen.POST("/createTable", middleware.WithPermission("tool:gen:edit", genController.CreateTable))
 			// 对应Java后端 @PreAuthorize("@ss.hasPermi('tool:gen:remove')")
 			toolGen.DELETE("/:tableIds", middleware.WithPermission("tool:gen:remove", genController.Remove))
 			// 对应Java后端 @PreAuthorize("@ss.hasPermi('tool:gen:preview')")
 			toolGen.GET("/preview/:tableId", middleware.WithPermission("tool:gen:preview", genController.Preview))
 			// 对应Java后端 @PreAuthorize("@ss.hasPermi('tool:gen:code')")
+			toolGen.GET("/genDiff/:tableName", middleware.WithPermission("tool:gen:code", genController.GenDiff))
 			toolGen.GET("/genCode/:tableName", middleware.WithPermission("tool:gen:code", genController.GenCode))
+			toolGen.POST("/menu/:tableId", middleware.WithPermission("tool:gen:code", genController.InstallMenu))
+			toolGen.DELETE("/menu/:tableId", middleware.WithPermission("tool:gen:code", genController.UninstallMenu))
+			toolGen.PUT("/publish/:tableId", middleware.WithPermission("tool:gen:edit", genController.Publish))
+			toolGen.DELETE("/publish/:tableId", middleware.WithPermission("tool:gen:edit", genController.Unpublish))
 			// 对应Java后端 @PreAuthorize("@ss.hasPermi('tool:gen:edit')")
-			toolGen.GET("/synchDb/:tableName", middleware.WithPermission("tool:gen:edit", genController.SynchDb))
+			toolGen.GET("/synchDb/:tableName", middleware.WithPermission("tool:gen:edit", genController.SynchDbDrift))
+			toolGen.POST("/synchDb/:tableName", middleware.WithPermission("tool:gen:edit", genController.SynchDb))
+			toolGen.GET("/drift", middleware.WithPermission("tool:gen:list", genController.ScanDrift))
 			// 对应Java后端 @PreAuthorize("@ss.hasPermi('tool:gen:code')")
 			toolGen.GET("/download/:tableName", middleware.WithPermission("tool:gen:code", genController.Download))
 			// 对应Java后端 @PreAuthorize("@ss.hasPermi('tool:gen:code')")
@@ -602,6 +718,33 @@ func setupRouter() *gin.Engine {
 			toolGen.GET("/column/:tableId", middleware.WithPermission("tool:gen:query", genController.ColumnList))
 		}
 
+		// 运行时模块 - 已发布的业务表，权限按 模块名:业务名:操作 在处理时校验
+		dyn := protected.Group("/dyn/:module")
+		{
+			dyn.GET("/list", dynController.List())
+			dyn.GET("/:id", dynController.GetInfo())
+			dyn.POST("", dynController.Add())
+			dyn.PUT("", dynController.Edit())
+			dyn.DELETE("/:id", dynController.Remove())
+			dyn.POST("/export", dynController.Export())
+		}
+
+		// 系统工具 - 代码生成模板组
+		toolGenTemplate := protected.Group("/tool/gen/template")
+		{
+			toolGenTemplate.GET("/list", middleware.WithPermission("tool:gen:list", genTemplateController.List))
+			toolGenTemplate.GET("/builtin", middleware.WithPermission("tool:gen:query", genTemplateController.Builtin))
+			toolGenTemplate.GET("/:setId", middleware.WithPermission("tool:gen:query", genTemplateController.GetInfo))
+			toolGenTemplate.POST("", middleware.WithPermission("tool:gen:edit", genTemplateController.Add))
+			toolGenTemplate.PUT("", middleware.WithPermission("tool:gen:edit", genTemplateController.Edit))
+			toolGenTemplate.DELETE("/:setIds", middleware.WithPermission("tool:gen:remove", genTemplateController.Remove))
+			toolGenTemplate.PUT("/content", middleware.WithPermission("tool:gen:edit", genTemplateController.SaveContent))
+			toolGenTemplate.DELETE("/content/:templateId", middleware.WithPermission("tool:gen:edit", genTemplateController.RemoveContent))
+			toolGenTemplate.GET("/history/:templateId", middleware.WithPermission("tool:gen:query", genTemplateController.History))
+			toolGenTemplate.PUT("/rollback/:templateId/:version", middleware.WithPermission("tool:gen:edit", genTemplateController.Rollback))
+			toolGenTemplate.POST("/preview", middleware.WithPermission("tool:gen:preview", genTemplateController.Preview))
+		}
+
 		// 系统工具 - 测试接口
 		toolTest := protected.Group("/test")
 		{
@@ -619,6 +762,9 @@ func setupRouter() *gin.Engine {
 				testUser.DELETE("/:userId", testController.DeleteUser)
 			}
 		}
+
+		// 代码生成器生成的业务模块路由
+		genRouter.RegisterGenRoutes(protected)
 	}
 
 	return router
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"wosm/pkg/database"
+	"wosm/pkg/logger"
+	"wosm/pkg/migrate"
+
+	"go.uber.org/zap"
+)
+
+// migrateUsage migrate 子命令用法
+const migrateUsage = `用法: wosm migrate <命令> [参数] [--config 配置文件]
+  up [版本]        执行未执行的迁移，指定版本时只执行到该版本
+  down [步数]      回滚最近执行的迁移，默认回滚1个
+  status           查看各版本的迁移状态
+  baseline [版本]  将已有数据库标记为已迁移到指定版本（不执行脚本），默认1，用于迁移功能上线前用旧的全量脚本建的库`
+
+// migrateArgs 去掉命令行参数中的 --config 配置文件
+func migrateArgs(args []string) []string {
+	var result []string
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--config" {
+			i++
+			continue
+		}
+		result = append(result, args[i])
+	}
+	return result
+}
+
+// runMigrate 执行数据库迁移子命令，args为 migrate 之后的参数
+func runMigrate(args []string) error {
+	if len(args) == 0 || len(args) > 2 {
+		return fmt.Errorf("%s", migrateUsage)
+	}
+	migrator, err := migrate.New(database.DB)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	command, arg := args[0], ""
+	if len(args) > 1 {
+		arg = args[1]
+	}
+
+	switch command {
+	case "up":
+		target, err := parseMigrateArg(arg, 0)
+		if err != nil {
+			return err
+		}
+		applied, err := migrator.Up(ctx, target)
+		printMigrations("已执行", applied)
+		return err
+	case "down":
+		steps, err := parseMigrateArg(arg, 1)
+		if err != nil {
+			return err
+		}
+		reverted, err := migrator.Down(ctx, int(steps))
+		printMigrations("已回滚", reverted)
+		return err
+	case "baseline":
+		version, err := parseMigrateArg(arg, 1)
+		if err != nil {
+			return err
+		}
+		marked, err := migrator.Baseline(ctx, version)
+		printMigrations("已标记", marked)
+		return err
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%-8s %-40s %-10s %s\n", "版本", "名称", "状态", "执行时间")
+		for _, status := range statuses {
+			appliedAt := "-"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%-8d %-40s %-10s %s\n", status.Version, status.Name, status.State, appliedAt)
+		}
+		return nil
+	}
+	return fmt.Errorf("未知的迁移命令: %s\n%s", command, migrateUsage)
+}
+
+// parseMigrateArg 解析版本号或步数参数，为空时使用默认值
+func parseMigrateArg(arg string, defaultValue int64) (int64, error) {
+	if arg == "" {
+		return defaultValue, nil
+	}
+	value, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil || value <= 0 {
+		return 0, fmt.Errorf("参数必须是正整数: %s", arg)
+	}
+	return value, nil
+}
+
+// printMigrations 输出本次处理的迁移
+func printMigrations(action string, migrations []migrate.Migration) {
+	if len(migrations) == 0 {
+		fmt.Println("没有需要处理的迁移")
+		return
+	}
+	for _, migration := range migrations {
+		fmt.Printf("%s: %s\n", action, migration.String())
+	}
+}
+
+// autoMigrate 启动时执行未执行的数据库迁移，对应配置 database.auto_migrate
+func autoMigrate() error {
+	migrator, err := migrate.New(database.DB)
+	if err != nil {
+		return err
+	}
+	applied, err := migrator.Up(context.Background(), 0)
+	for _, migration := range applied {
+		logger.Info("数据库迁移已执行", zap.String("migration", migration.String()))
+	}
+	return err
+}
@@ -5,8 +5,10 @@ import (
 	"net/http"
 	"slices"
 	"strings"
+	"wosm/internal/config"
 	"wosm/internal/repository/model"
 	"wosm/internal/service/auth"
+	"wosm/pkg/database"
 	"wosm/pkg/datascope"
 	"wosm/pkg/response"
 
@@ -45,6 +47,15 @@ func AuthMiddleware() gin.HandlerFunc {
 		ctx.Set("loginUser", loginUser)
 		ctx.Set("userId", loginUser.UserID)
 		ctx.Set("username", loginUser.User.UserName)
+		// 数据变更记录的操作人员
+		requestCtx := database.WithRequestOperator(ctx.Request.Context(), loginUser.User.UserName)
+		// 开启租户模式时按当前操作的租户过滤数据
+		if config.IsTenantEnabled() {
+			tenantId := loginUser.CurrentTenantID()
+			ctx.Set("tenantId", tenantId)
+			requestCtx = database.WithRequestTenant(requestCtx, tenantId)
+		}
+		ctx.Request = ctx.Request.WithContext(requestCtx)
 
 		ctx.Next()
 	}
@@ -154,8 +165,8 @@ func WithPermission(permission string, handler gin.HandlerFunc) gin.HandlerFunc
 }
 
 // WithDataScope 数据权限装饰器 对应Java后端的@DataScope注解
-// 注意：这个函数需要在datascope_middleware.go中实现DataScopeMiddleware
-func WithDataScope(deptAlias, userAlias, permission string, handler gin.HandlerFunc) gin.HandlerFunc {
+// 将当前用户的数据权限条件（*datascope.Predicate）设置到上下文中，查询字段由各DAO声明
+func WithDataScope(permission string, handler gin.HandlerFunc) gin.HandlerFunc {
 	return gin.HandlerFunc(func(ctx *gin.Context) {
 		// 应用数据权限处理逻辑
 		// 获取当前用户信息
@@ -202,28 +213,15 @@ func WithDataScope(deptAlias, userAlias, permission string, handler gin.HandlerF
 			}
 		}
 
-		// 创建查询参数
-		params := make(map[string]any)
-
-		// 应用数据权限
-		err := applyDataScope(user.User, deptAlias, userAlias, permission, params)
-		if err != nil {
-			response.ErrorWithDetailed(ctx, http.StatusForbidden, "数据权限校验失败")
-			ctx.Abort()
-			return
-		}
-
-		// 将数据权限参数设置到上下文中
-		for key, value := range params {
-			ctx.Set(key, value)
-		}
+		// 生成数据权限条件
+		ctx.Set(datascope.DataScopeKey, datascope.Build(user.User, permission))
 
 		handler(ctx)
 	})
 }
 
 // WithPermissionAndDataScope 权限和数据权限组合装饰器
-func WithPermissionAndDataScope(permission, deptAlias, userAlias string, handler gin.HandlerFunc) gin.HandlerFunc {
+func WithPermissionAndDataScope(permission string, handler gin.HandlerFunc) gin.HandlerFunc {
 	return gin.HandlerFunc(func(ctx *gin.Context) {
 		// 先应用权限验证中间件
 		permissionMiddleware := PermissionMiddleware(permission)
@@ -234,13 +232,7 @@ func WithPermissionAndDataScope(permission, deptAlias, userAlias string, handler
 		}
 
 		// 再应用数据权限装饰器
-		dataScopeHandler := WithDataScope(deptAlias, userAlias, permission, handler)
+		dataScopeHandler := WithDataScope(permission, handler)
 		dataScopeHandler(ctx)
 	})
 }
-
-// applyDataScope 应用数据权限 对应Java后端的DataScopeAspect处理逻辑
-func applyDataScope(user *model.SysUser, deptAlias, userAlias, permission string, params map[string]any) error {
-	// 使用完整的数据权限处理器
-	return datascope.ApplyDataScope(user, deptAlias, userAlias, permission, params)
-}
@@ -9,15 +9,10 @@ import (
 )
 
 // DataScopeMiddleware 数据权限中间件 对应Java后端的@DataScope注解处理
-func DataScopeMiddleware(deptAlias, userAlias, permission string) gin.HandlerFunc {
-	return DataScopeMiddlewareWithCreator(deptAlias, userAlias, "", permission)
-}
-
-// DataScopeMiddlewareWithCreator 数据权限中间件（支持创建者权限）
-func DataScopeMiddlewareWithCreator(deptAlias, userAlias, creatorAlias, permission string) gin.HandlerFunc {
+// 按权限标识生成当前用户的数据权限条件并存储到上下文中，查询字段由各DAO声明
+func DataScopeMiddleware(permission string) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		fmt.Printf("DataScopeMiddleware: 处理数据权限, DeptAlias=%s, UserAlias=%s, CreatorAlias=%s, Permission=%s\n",
-			deptAlias, userAlias, creatorAlias, permission)
+		fmt.Printf("DataScopeMiddleware: 处理数据权限, Permission=%s\n", permission)
 
 		// 获取当前用户信息
 		userInterface, exists := ctx.Get("user")
@@ -34,61 +29,25 @@ func DataScopeMiddlewareWithCreator(deptAlias, userAlias, creatorAlias, permissi
 			return
 		}
 
-		// 创建数据权限配置
-		config := &datascope.DataScopeConfig{
-			DeptAlias:    deptAlias,
-			UserAlias:    userAlias,
-			CreatorAlias: creatorAlias,
-			Permission:   permission,
-		}
-
-		// 验证配置
-		if err := datascope.ValidateDataScopeConfig(config); err != nil {
-			fmt.Printf("DataScopeMiddleware: 数据权限配置无效: %v\n", err)
-			ctx.Next()
-			return
-		}
-
-		// 将数据权限配置存储到上下文中，供后续使用
-		ctx.Set("dataScopeConfig", config)
+		// 将数据权限条件存储到上下文中，供后续使用
+		predicate := datascope.Build(user, permission)
+		ctx.Set(datascope.DataScopeKey, predicate)
 		ctx.Set("dataScopeUser", user)
 
-		fmt.Printf("DataScopeMiddleware: 数据权限配置已设置\n")
+		fmt.Printf("DataScopeMiddleware: 数据权限条件已设置: %s\n", predicate)
 		ctx.Next()
 	}
 }
 
-// ApplyDataScopeToParams 将数据权限应用到查询参数 供Service层调用
-func ApplyDataScopeToParams(ctx *gin.Context, params map[string]any) error {
-	// 获取数据权限配置
-	configInterface, exists := ctx.Get("dataScopeConfig")
-	if !exists {
-		fmt.Printf("ApplyDataScopeToParams: 未找到数据权限配置\n")
-		return nil
-	}
-
-	config, ok := configInterface.(*datascope.DataScopeConfig)
-	if !ok {
-		fmt.Printf("ApplyDataScopeToParams: 数据权限配置类型错误\n")
-		return nil
-	}
-
-	// 获取用户信息
-	userInterface, exists := ctx.Get("dataScopeUser")
+// GetDataScope 从上下文获取数据权限条件，用法：query.Scopes(predicate.Scope(columns))
+func GetDataScope(ctx *gin.Context) (*datascope.Predicate, bool) {
+	predicateInterface, exists := ctx.Get(datascope.DataScopeKey)
 	if !exists {
-		fmt.Printf("ApplyDataScopeToParams: 未找到数据权限用户信息\n")
-		return nil
-	}
-
-	user, ok := userInterface.(*model.SysUser)
-	if !ok {
-		fmt.Printf("ApplyDataScopeToParams: 数据权限用户信息类型错误\n")
-		return nil
+		return nil, false
 	}
 
-	// 应用数据权限
-	processor := datascope.NewDataScopeProcessor()
-	return processor.ProcessDataScope(user, config, params)
+	predicate, ok := predicateInterface.(*datascope.Predicate)
+	return predicate, ok
 }
 
 // GetDataScopeUser 从上下文获取数据权限用户信息
@@ -102,17 +61,6 @@ func GetDataScopeUser(ctx *gin.Context) (*model.SysUser, bool) {
 	return user, ok
 }
 
-// GetDataScopeConfig 从上下文获取数据权限配置
-func GetDataScopeConfig(ctx *gin.Context) (*datascope.DataScopeConfig, bool) {
-	configInterface, exists := ctx.Get("dataScopeConfig")
-	if !exists {
-		return nil, false
-	}
-
-	config, ok := configInterface.(*datascope.DataScopeConfig)
-	return config, ok
-}
-
 // HasDataScopePermission 检查用户是否有指定的数据权限
 func HasDataScopePermission(ctx *gin.Context, dataScope string) bool {
 	user, exists := GetDataScopeUser(ctx)
@@ -178,10 +126,10 @@ func CheckDataScopeAccess(ctx *gin.Context, targetDeptId, targetUserId int64) bo
 }
 
 // DataScopeWrapper 数据权限包装器，用于包装需要数据权限的处理函数
-func DataScopeWrapper(deptAlias, userAlias, permission string, handler gin.HandlerFunc) gin.HandlerFunc {
+func DataScopeWrapper(permission string, handler gin.HandlerFunc) gin.HandlerFunc {
 	return gin.HandlerFunc(func(ctx *gin.Context) {
 		// 先应用数据权限中间件
-		middleware := DataScopeMiddleware(deptAlias, userAlias, permission)
+		middleware := DataScopeMiddleware(permission)
 		middleware(ctx)
 
 		// 如果中间件处理成功，继续执行处理函数
@@ -194,12 +142,12 @@ func DataScopeWrapper(deptAlias, userAlias, permission string, handler gin.Handl
 // LogDataScopeInfo 记录数据权限信息（用于调试）
 func LogDataScopeInfo(ctx *gin.Context) {
 	user, userExists := GetDataScopeUser(ctx)
-	config, configExists := GetDataScopeConfig(ctx)
+	predicate, predicateExists := GetDataScope(ctx)
 
-	if userExists && configExists {
+	if userExists && predicateExists {
 		fmt.Printf("=== 数据权限信息 ===\n")
 		fmt.Printf("用户ID: %d, 用户名: %s, 部门ID: %d\n", user.UserID, user.UserName, user.DeptID)
-		fmt.Printf("部门别名: %s, 用户别名: %s, 权限: %s\n", config.DeptAlias, config.UserAlias, config.Permission)
+		fmt.Printf("数据权限条件: %s\n", predicate)
 		fmt.Printf("用户角色数量: %d\n", len(user.Roles))
 
 		for i, role := range user.Roles {
@@ -211,7 +159,7 @@ func LogDataScopeInfo(ctx *gin.Context) {
 		fmt.Printf("最高数据权限: %s (%s)\n", highestScope, datascope.GetDataScopeText(highestScope))
 		fmt.Printf("==================\n")
 	} else {
-		fmt.Printf("数据权限信息不完整: userExists=%v, configExists=%v\n", userExists, configExists)
+		fmt.Printf("数据权限信息不完整: userExists=%v, predicateExists=%v\n", userExists, predicateExists)
 	}
 }
 
@@ -244,7 +192,7 @@ func checkDeptAndChildAccess(user *model.SysUser, targetDeptId int64) bool {
 	// 检查是否为下级部门
 	// 这里简化实现：只允许本部门访问
 	// 完整实现需要查询sys_dept表检查部门层级关系：
-	// SELECT COUNT(*) FROM sys_dept WHERE dept_id = ? AND (dept_id = ? OR CHARINDEX(',?,' , ','+ISNULL(ancestors,'')+',') > 0)
+	// SELECT COUNT(*) FROM sys_dept WHERE dept_id = ? AND (dept_id = ? OR find_in_set(?, ancestors))
 	fmt.Printf("checkDeptAndChildAccess: 目标部门%d不是用户部门%d的下级部门，拒绝访问\n", targetDeptId, *user.DeptID)
 	return false
 }
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -41,9 +42,14 @@ func OperationLogMiddleware() gin.HandlerFunc {
 		// 计算耗时
 		costTime := time.Since(startTime).Milliseconds()
 
+		// 请求ID在异步记录前取出，关联请求中的数据变更记录
+		requestID := ctx.GetString(RequestIDKey)
+		// 请求的context携带租户，同样在异步记录前取出
+		requestCtx := ctx.Request.Context()
+
 		// 异步记录操作日志
 		go func() {
-			recordOperationLog(ctx, requestBody, responseWriter.body.Bytes(), costTime)
+			recordOperationLog(ctx, requestCtx, requestID, requestBody, responseWriter.body.Bytes(), costTime)
 		}()
 	}
 }
@@ -60,7 +66,7 @@ func (r *responseBodyWriter) Write(b []byte) (int, error) {
 }
 
 // recordOperationLog 记录操作日志
-func recordOperationLog(ctx *gin.Context, requestBody, responseBody []byte, costTime int64) {
+func recordOperationLog(ctx *gin.Context, requestCtx context.Context, requestID string, requestBody, responseBody []byte, costTime int64) {
 	// 获取当前登录用户
 	loginUser, exists := ctx.Get("loginUser")
 	if !exists {
@@ -97,6 +103,7 @@ func recordOperationLog(ctx *gin.Context, requestBody, responseBody []byte, cost
 		Status:        getOperationStatus(ctx.Writer.Status()),
 		ErrorMsg:      getErrorMessage(ctx.Writer.Status(), responseBody),
 		CostTime:      costTime,
+		RequestID:     requestID,
 	}
 
 	// 限制参数长度
@@ -108,7 +115,7 @@ func recordOperationLog(ctx *gin.Context, requestBody, responseBody []byte, cost
 	}
 
 	// 保存操作日志
-	operLogService := systemService.NewOperLogService()
+	operLogService := systemService.NewOperLogService().WithContext(requestCtx)
 	if err := operLogService.InsertOperLog(operLog); err != nil {
 		fmt.Printf("记录操作日志失败: %v\n", err)
 	}
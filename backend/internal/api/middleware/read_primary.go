@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+	"wosm/pkg/database"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReadPrimaryHeader 客户端要求本次请求读主库的请求头
+const ReadPrimaryHeader = "X-Read-Primary"
+
+// ReadPrimaryCookie 写入数据之后一段时间内读主库的Cookie
+const ReadPrimaryCookie = "wosm_read_primary"
+
+// ReadPrimaryMiddleware 读写分离中间件，没有配置只读副本时不处理
+// 请求中写入了数据时通过Cookie通知客户端，之后一段时间内该客户端的查询读主库，避免保存后立即刷新列表时副本还没有同步；
+// 客户端也可以通过请求头 X-Read-Primary: true 要求本次请求读主库
+// 需要在 SQLMonitorMiddleware 之后注册
+func ReadPrimaryMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if database.Resolver == nil {
+			ctx.Next()
+			return
+		}
+
+		if strings.EqualFold(ctx.GetHeader(ReadPrimaryHeader), "true") {
+			database.ReadFromPrimary(ctx.Request.Context())
+		} else if _, err := ctx.Cookie(ReadPrimaryCookie); err == nil {
+			database.ReadFromPrimary(ctx.Request.Context())
+		}
+
+		if sticky := database.Resolver.StickyDuration(); sticky > 0 {
+			ctx.Writer = &readPrimaryWriter{ResponseWriter: ctx.Writer, ctx: ctx.Request.Context(), sticky: sticky}
+		}
+		ctx.Next()
+	}
+}
+
+// readPrimaryWriter 发送响应头之前检查请求中是否写入了数据，写入了则设置读主库的Cookie
+type readPrimaryWriter struct {
+	gin.ResponseWriter
+	ctx     context.Context
+	sticky  time.Duration
+	checked bool
+}
+
+// setCookie 只在第一次发送响应头之前检查
+func (w *readPrimaryWriter) setCookie() {
+	if w.checked || w.ResponseWriter.Written() {
+		return
+	}
+	w.checked = true
+	if database.RequestWrote(w.ctx) {
+		http.SetCookie(w.ResponseWriter, &http.Cookie{
+			Name:     ReadPrimaryCookie,
+			Value:    "1",
+			Path:     "/",
+			MaxAge:   int(w.sticky.Seconds()),
+			HttpOnly: true,
+		})
+	}
+}
+
+func (w *readPrimaryWriter) WriteHeaderNow() {
+	w.setCookie()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *readPrimaryWriter) Write(data []byte) (int, error) {
+	w.setCookie()
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *readPrimaryWriter) WriteString(s string) (int, error) {
+	w.setCookie()
+	return w.ResponseWriter.WriteString(s)
+}
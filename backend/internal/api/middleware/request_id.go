@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"context"
+	"regexp"
+	"wosm/pkg/database"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader 请求ID的请求头和响应头
+const RequestIDHeader = "X-Request-Id"
+
+// RequestIDKey 请求ID在gin上下文中的键
+const RequestIDKey = "requestId"
+
+// validRequestID 客户端传入的请求ID只接受字母、数字、-和_，避免写入日志的内容被伪造
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// RequestIDMiddleware 请求ID中间件，沿用客户端或网关传入的请求ID，没有时生成，并通过响应头返回
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		requestID := ctx.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			requestID = uuid.New().String()
+		}
+		ctx.Set(RequestIDKey, requestID)
+		ctx.Header(RequestIDHeader, requestID)
+		ctx.Next()
+	}
+}
+
+// SQLMonitorMiddleware SQL监控中间件，统计请求中执行的SQL，用于发现重复执行的SQL（N+1查询），慢SQL记录关联请求ID
+// 请求信息保存在请求的context中，控制器通过 WithContext(ctx.Request.Context()) 传给服务和DAO
+// 需要在 RequestIDMiddleware 之后注册
+func SQLMonitorMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// 请求结束后异步记录的日志仍使用该context，不随连接关闭取消
+		requestCtx, done := database.Stats.TrackRequest(context.WithoutCancel(ctx.Request.Context()),
+			ctx.GetString(RequestIDKey), ctx.Request.Method, ctx.Request.URL.Path)
+		defer done()
+		ctx.Request = ctx.Request.WithContext(requestCtx)
+		ctx.Next()
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"wosm/internal/service/auth"
 	"wosm/internal/service/system"
 	"wosm/internal/utils"
+	"wosm/pkg/database"
 	"wosm/pkg/response"
 
 	"github.com/gin-gonic/gin"
@@ -42,7 +43,7 @@ func NewAuthControllerWithPassword(authService *auth.AuthService) *AuthControlle
 // @Router /captchaImage [get]
 func (c *AuthController) CaptchaImage(ctx *gin.Context) {
 	// 检查验证码是否启用 对应Java后端的configService.selectCaptchaEnabled()
-	configService := system.NewConfigService()
+	configService := system.NewConfigService().WithContext(ctx.Request.Context())
 	captchaEnabled, err := configService.SelectCaptchaEnabled()
 	if err != nil {
 		fmt.Printf("获取验证码配置失败: %v，使用配置文件默认值\n", err)
@@ -51,8 +52,10 @@ func (c *AuthController) CaptchaImage(ctx *gin.Context) {
 
 	// 按照Java后端的格式返回，直接在根级别设置字段
 	// Java: ajax.put("captchaEnabled", captchaEnabled); ajax.put("uuid", uuid); ajax.put("img", img);
+	// 开启租户模式时登录和注册页面显示租户编号
 	fields := map[string]interface{}{
 		"captchaEnabled": captchaEnabled,
+		"tenantEnabled":  config.IsTenantEnabled(),
 	}
 
 	// 如果验证码未启用，直接返回
@@ -89,6 +92,7 @@ func (c *AuthController) CaptchaImage(ctx *gin.Context) {
 // @Success 200 {object} response.Result{data=string}
 // @Router /login [post]
 func (c *AuthController) Login(ctx *gin.Context) {
+	authService := c.authService.WithContext(ctx.Request.Context())
 	var loginBody model.LoginBody
 	if err := ctx.ShouldBindJSON(&loginBody); err != nil {
 		fmt.Printf("登录参数解析失败: %v\n", err)
@@ -102,8 +106,19 @@ func (c *AuthController) Login(ctx *gin.Context) {
 	userAgent := ctx.GetHeader("User-Agent")
 	ipAddr := c.getClientIP(ctx)
 
+	// 开启租户模式时先确定登录的租户，之后按租户查询用户和参数
+	if config.IsTenantEnabled() {
+		tenantId, err := system.NewTenantService().WithContext(ctx.Request.Context()).ResolveLoginTenant(loginBody.TenantID, ctx.Request.Host)
+		if err != nil {
+			fmt.Printf("登录租户校验失败: %v\n", err)
+			response.ErrorWithMessage(ctx, err.Error())
+			return
+		}
+		ctx.Request = ctx.Request.WithContext(database.WithRequestTenant(ctx.Request.Context(), tenantId))
+	}
+
 	// 执行登录
-	token, err := c.authService.Login(&loginBody, userAgent, ipAddr)
+	token, err := authService.Login(&loginBody, userAgent, ipAddr)
 	if err != nil {
 		fmt.Printf("登录失败: %v\n", err)
 		response.ErrorWithMessage(ctx, err.Error())
@@ -131,6 +146,7 @@ func (c *AuthController) Login(ctx *gin.Context) {
 // @Success 200 {object} response.Result{data=model.UserInfoResponse}
 // @Router /getInfo [get]
 func (c *AuthController) GetInfo(ctx *gin.Context) {
+	authService := c.authService.WithContext(ctx.Request.Context())
 	// 获取当前登录用户
 	loginUser, exists := ctx.Get("loginUser")
 	if !exists {
@@ -143,7 +159,7 @@ func (c *AuthController) GetInfo(ctx *gin.Context) {
 	fmt.Printf("GetInfo: 获取用户信息, UserID=%d\n", user.UserID)
 
 	// 获取用户详细信息
-	userInfo, err := c.authService.GetUserInfo(user)
+	userInfo, err := authService.GetUserInfo(user)
 	if err != nil {
 		fmt.Printf("GetInfo: 获取用户信息失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "获取用户信息失败")
@@ -174,6 +190,7 @@ func (c *AuthController) GetInfo(ctx *gin.Context) {
 // @Success 200 {object} response.Result{data=[]model.RouterResponse}
 // @Router /getRouters [get]
 func (c *AuthController) GetRouters(ctx *gin.Context) {
+	authService := c.authService.WithContext(ctx.Request.Context())
 	// 获取当前登录用户
 	loginUser, exists := ctx.Get("loginUser")
 	if !exists {
@@ -186,7 +203,7 @@ func (c *AuthController) GetRouters(ctx *gin.Context) {
 	fmt.Printf("GetRouters: 获取路由信息, UserID=%d\n", user.UserID)
 
 	// 获取用户路由信息
-	routers, err := c.authService.GetRouters(user.UserID)
+	routers, err := authService.GetRouters(user.UserID)
 	if err != nil {
 		fmt.Printf("GetRouters: 获取路由信息失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "获取路由信息失败")
@@ -207,11 +224,12 @@ func (c *AuthController) GetRouters(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /logout [post]
 func (c *AuthController) Logout(ctx *gin.Context) {
+	authService := c.authService.WithContext(ctx.Request.Context())
 	// 获取token
 	token := c.getTokenFromHeader(ctx)
 
 	// 执行登出
-	err := c.authService.Logout(token)
+	err := authService.Logout(token)
 	if err != nil {
 		response.ErrorWithMessage(ctx, "登出失败")
 		return
@@ -2,8 +2,10 @@ package auth
 
 import (
 	"fmt"
+	"wosm/internal/config"
 	"wosm/internal/repository/model"
 	"wosm/internal/service/system"
+	"wosm/pkg/database"
 	"wosm/pkg/response"
 
 	"github.com/gin-gonic/gin"
@@ -31,6 +33,7 @@ func NewRegisterController() *RegisterController {
 // @Success 200 {object} response.Response
 // @Router /register [post]
 func (c *RegisterController) Register(ctx *gin.Context) {
+	registerService := c.registerService.WithContext(ctx.Request.Context())
 	fmt.Printf("RegisterController.Register: 用户注册\n")
 
 	// 绑定请求参数
@@ -40,8 +43,19 @@ func (c *RegisterController) Register(ctx *gin.Context) {
 		return
 	}
 
+	// 开启租户模式时先确定注册的租户，之后按租户读取参数和新增用户
+	if config.IsTenantEnabled() {
+		tenantId, err := system.NewTenantService().WithContext(ctx.Request.Context()).ResolveLoginTenant(registerBody.TenantID, ctx.Request.Host)
+		if err != nil {
+			fmt.Printf("RegisterController.Register: 注册租户校验失败: %v\n", err)
+			response.ErrorWithMessage(ctx, err.Error())
+			return
+		}
+		ctx.Request = ctx.Request.WithContext(database.WithRequestTenant(ctx.Request.Context(), tenantId))
+	}
+
 	// 检查是否开启注册功能
-	enabled, err := c.registerService.CheckRegisterEnabled()
+	enabled, err := registerService.CheckRegisterEnabled()
 	if err != nil {
 		response.ErrorWithMessage(ctx, "检查注册功能失败: "+err.Error())
 		return
@@ -52,7 +66,7 @@ func (c *RegisterController) Register(ctx *gin.Context) {
 	}
 
 	// 执行注册
-	err = c.registerService.Register(&registerBody)
+	err = registerService.Register(&registerBody)
 	if err != nil {
 		response.ErrorWithMessage(ctx, err.Error())
 		return
@@ -0,0 +1,61 @@
+package monitor
+
+import (
+	"fmt"
+	"strconv"
+	"wosm/internal/repository/model"
+	monitorService "wosm/internal/service/monitor"
+	"wosm/pkg/response"
+	"wosm/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DataChangeController 数据变更历史控制器 查看用户、角色、部门、菜单、参数和字典数据的字段变更时间线
+type DataChangeController struct {
+	dataChangeService *monitorService.DataChangeService
+}
+
+// NewDataChangeController 创建数据变更历史控制器实例
+func NewDataChangeController() *DataChangeController {
+	return &DataChangeController{
+		dataChangeService: monitorService.NewDataChangeService(),
+	}
+}
+
+// History 查询实体的字段变更时间线
+// @Summary 查询数据变更历史
+// @Description 分页查询实体每个字段修改前后的值、操作人员和时间，最新的在前；operId关联操作日志
+// @Tags 数据变更历史
+// @Produce json
+// @Param entity path string true "实体类型（user role dept menu config dict_data）"
+// @Param id path int true "实体ID"
+// @Param pageNum query int false "页码"
+// @Param pageSize query int false "每页数量"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.TableDataInfo
+// @Router /monitor/history/{entity}/{id} [get]
+func (c *DataChangeController) History(ctx *gin.Context) {
+	dataChangeService := c.dataChangeService.WithContext(ctx.Request.Context())
+	pageDomain := utils.StartPage(ctx)
+
+	entity := ctx.Param("entity")
+	if !model.IsDataChangeEntity(entity) {
+		response.ErrorWithMessage(ctx, "实体类型参数无效")
+		return
+	}
+	entityId, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.ErrorWithMessage(ctx, "实体ID格式错误")
+		return
+	}
+
+	changes, total, err := dataChangeService.SelectHistory(entity, entityId, pageDomain.PageNum, pageDomain.PageSize)
+	if err != nil {
+		fmt.Printf("DataChangeController.History: 查询变更历史失败: %v\n", err)
+		response.ErrorWithMessage(ctx, "查询变更历史失败")
+		return
+	}
+
+	response.SendTableDataInfo(ctx, response.GetDataTable(changes, total))
+}
@@ -9,7 +9,6 @@ import (
 	"wosm/pkg/response"
 
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
 )
 
 // DruidController Druid监控控制器 对应Java后端的DruidConfig和StatViewServlet
@@ -60,16 +59,20 @@ type DruidStats struct {
 	RollbackCount int64 `json:"RollbackCount"` // 回滚次数
 
 	// 性能统计
-	ExecuteMillisTotal           int64 `json:"ExecuteMillisTotal"`           // 总执行时间
-	ExecuteMillisMax             int64 `json:"ExecuteMillisMax"`             // 最大执行时间
-	PreparedStatementOpenCount   int64 `json:"PreparedStatementOpenCount"`   // 预编译语句打开次数
-	PreparedStatementClosedCount int64 `json:"PreparedStatementClosedCount"` // 预编译语句关闭次数
+	ExecuteMillisTotal           int64   `json:"ExecuteMillisTotal"`           // 总执行时间
+	ExecuteMillisMax             int64   `json:"ExecuteMillisMax"`             // 最大执行时间
+	Histogram                    []int64 `json:"Histogram"`                    // 耗时分布（<1ms、<10ms、<100ms、<1s、<10s、<100s、<1000s、>=1000s）
+	PreparedStatementOpenCount   int64   `json:"PreparedStatementOpenCount"`   // 预编译语句打开次数
+	PreparedStatementClosedCount int64   `json:"PreparedStatementClosedCount"` // 预编译语句关闭次数
 
 	// 缓存统计
 	CachedPreparedStatementCount       int64 `json:"CachedPreparedStatementCount"`       // 缓存预编译语句数
 	CachedPreparedStatementDeleteCount int64 `json:"CachedPreparedStatementDeleteCount"` // 缓存预编译语句删除数
 	CachedPreparedStatementHitCount    int64 `json:"CachedPreparedStatementHitCount"`    // 缓存预编译语句命中数
 	CachedPreparedStatementMissCount   int64 `json:"CachedPreparedStatementMissCount"`   // 缓存预编译语句未命中数
+
+	// 只读副本（读写分离）
+	Replicas []database.ReplicaStat `json:"Replicas"`
 }
 
 // SQLStat SQL统计信息 对应Java后端的JdbcSqlStat
@@ -98,6 +101,8 @@ type SQLStat struct {
 	BlobOpenCount      int64 `json:"BlobOpenCount"`      // Blob打开次数
 	ReadStringLength   int64 `json:"ReadStringLength"`   // 读取字符串长度
 	ReadBytesLength    int64 `json:"ReadBytesLength"`    // 读取字节长度
+
+	Histogram []int64 `json:"Histogram"` // 耗时分布（<1ms、<10ms、<100ms、<1s、<10s、<100s、<1000s、>=1000s）
 }
 
 // Index Druid监控首页 对应Java后端的/druid/index.html
@@ -219,7 +224,7 @@ func (c *DruidController) ResetAll(ctx *gin.Context) {
 		return
 	}
 
-	// 重置统计信息（这里只是模拟，实际需要重置GORM的统计）
+	database.Stats.Reset()
 	fmt.Printf("DruidController.ResetAll: 统计信息已重置\n")
 	response.SuccessWithMessage(ctx, "统计信息已重置")
 }
@@ -231,230 +236,107 @@ func (c *DruidController) checkAuth(ctx *gin.Context) bool {
 }
 
 // getDataSourceStats 获取数据源统计信息 对应Java后端的DruidDataSourceStatManager
+// SQL统计来自 database.Stats 插件，连接池信息来自 sql.DB.Stats()
 func (c *DruidController) getDataSourceStats() *DruidStats {
-	db := database.GetDB()
-
-	// 获取数据库配置信息
 	dbConfig := config.AppConfig.Database
+	dialect := database.CurrentDialect().Name()
+	stat := database.Stats.DataSourceStat()
+	pool := stat.Pool
+	closeCount := pool.MaxIdleClosed + pool.MaxIdleTimeClosed + pool.MaxLifetimeClosed
+
+	var replicas []database.ReplicaStat
+	if database.Resolver != nil {
+		replicas = database.Resolver.ReplicaStats()
+	}
 
-	// 模拟Druid统计信息（基于GORM和实际配置）
-	stats := &DruidStats{
+	return &DruidStats{
 		Version:         "Go-GORM-1.25.0",
-		DriverClassName: "github.com/denisenkom/go-mssqldb",
-		URL:             fmt.Sprintf("sqlserver://%s:%d?database=%s", dbConfig.Host, dbConfig.Port, dbConfig.Database),
+		DriverClassName: driverClassNames[dialect],
+		URL:             c.getURL(dialect, &dbConfig),
 		UserName:        dbConfig.Username,
 		Name:            "master",
-		DbType:          "sqlserver",
+		DbType:          dialect,
 
-		// 连接池信息（基于GORM配置）
-		InitialSize:  5,
-		MaxActive:    dbConfig.MaxOpenConns,
+		// 连接池信息
+		InitialSize:  0,
+		MaxActive:    pool.MaxOpenConnections,
 		MinIdle:      dbConfig.MaxIdleConns,
-		PoolingCount: c.getCurrentConnections(db),
-		ActiveCount:  c.getActiveConnections(db),
-
-		// 统计信息（模拟数据）
-		ConnectCount:         c.getConnectCount(),
-		CloseCount:           c.getCloseCount(),
-		ConnectErrorCount:    0,
-		RecycleCount:         c.getRecycleCount(),
-		RemoveAbandonedCount: 0,
-		NotEmptyWaitCount:    0,
-		NotEmptyWaitMillis:   0,
+		PoolingCount: pool.Idle,
+		ActiveCount:  pool.InUse,
+
+		// 连接统计，关闭的连接都曾经打开过，打开次数为当前连接数加已关闭数
+		ConnectCount:       int64(pool.OpenConnections) + closeCount,
+		CloseCount:         closeCount,
+		NotEmptyWaitCount:  pool.WaitCount,
+		NotEmptyWaitMillis: pool.WaitDuration.Milliseconds(),
 
 		// 时间信息
-		CreatedTime:     time.Now().Add(-24 * time.Hour), // 假设24小时前创建
-		ActivePeak:      c.getActivePeak(),
-		ActivePeakTime:  time.Now().Add(-2 * time.Hour), // 假设2小时前达到峰值
-		PoolingPeak:     dbConfig.MaxOpenConns,
-		PoolingPeakTime: time.Now().Add(-1 * time.Hour), // 假设1小时前达到峰值
-
-		// SQL统计（模拟数据）
-		ExecuteCount:  c.getExecuteCount(),
-		ErrorCount:    0,
-		CommitCount:   c.getCommitCount(),
-		RollbackCount: 0,
+		CreatedTime:     stat.ResetTime,
+		ActivePeak:      stat.ActivePeak,
+		ActivePeakTime:  stat.ActivePeakTime,
+		PoolingPeak:     stat.PoolingPeak,
+		PoolingPeakTime: stat.PoolingPeakTime,
+
+		// SQL统计
+		ExecuteCount:  stat.ExecuteCount,
+		ErrorCount:    stat.ErrorCount,
+		CommitCount:   stat.CommitCount,
+		RollbackCount: stat.RollbackCount,
 
 		// 性能统计
-		ExecuteMillisTotal:           c.getExecuteMillisTotal(),
-		ExecuteMillisMax:             c.getExecuteMillisMax(),
-		PreparedStatementOpenCount:   c.getPreparedStatementOpenCount(),
-		PreparedStatementClosedCount: c.getPreparedStatementClosedCount(),
-
-		// 缓存统计
-		CachedPreparedStatementCount:       c.getCachedPreparedStatementCount(),
-		CachedPreparedStatementDeleteCount: 0,
-		CachedPreparedStatementHitCount:    c.getCachedPreparedStatementHitCount(),
-		CachedPreparedStatementMissCount:   c.getCachedPreparedStatementMissCount(),
-	}
+		ExecuteMillisTotal: stat.TotalTime.Milliseconds(),
+		ExecuteMillisMax:   stat.MaxTimespan.Milliseconds(),
+		Histogram:          stat.Histogram[:],
 
-	return stats
+		Replicas: replicas,
+	}
 }
 
 // getSQLStats 获取SQL统计信息 对应Java后端的JdbcSqlStatManager
 func (c *DruidController) getSQLStats() []SQLStat {
-	// 模拟常见的SQL统计信息
-	sqlStats := []SQLStat{
-		{
-			SQL:                  "SELECT * FROM sys_user WHERE del_flag = ? AND status = ?",
-			ExecuteCount:         1250,
-			ErrorCount:           0,
-			TotalTime:            15600,
-			MaxTimespan:          45,
-			LastTime:             time.Now().Add(-5 * time.Minute),
-			DbType:               "sqlserver",
-			URL:                  config.AppConfig.Database.Host,
-			MaxTimespanOccurTime: time.Now().Add(-2 * time.Hour),
-			FetchRowCount:        12500,
-			UpdateCount:          0,
-			InTransactionCount:   0,
-		},
-		{
-			SQL:                  "SELECT * FROM sys_role WHERE del_flag = ? AND status = ?",
-			ExecuteCount:         856,
-			ErrorCount:           0,
-			TotalTime:            8920,
-			MaxTimespan:          32,
-			LastTime:             time.Now().Add(-10 * time.Minute),
-			DbType:               "sqlserver",
-			URL:                  config.AppConfig.Database.Host,
-			MaxTimespanOccurTime: time.Now().Add(-3 * time.Hour),
-			FetchRowCount:        2568,
-			UpdateCount:          0,
-			InTransactionCount:   0,
-		},
-		{
-			SQL:                  "SELECT * FROM sys_menu WHERE del_flag = ? ORDER BY parent_id, order_num",
-			ExecuteCount:         642,
-			ErrorCount:           0,
-			TotalTime:            7854,
-			MaxTimespan:          28,
-			LastTime:             time.Now().Add(-15 * time.Minute),
-			DbType:               "sqlserver",
-			URL:                  config.AppConfig.Database.Host,
-			MaxTimespanOccurTime: time.Now().Add(-4 * time.Hour),
-			FetchRowCount:        54570,
-			UpdateCount:          0,
-			InTransactionCount:   0,
-		},
-		{
-			SQL:                  "INSERT INTO sys_oper_log (title, business_type, method, request_method, operator_type, oper_name, dept_name, oper_url, oper_ip, oper_location, oper_param, json_result, status, error_msg, oper_time, cost_time) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
-			ExecuteCount:         2156,
-			ErrorCount:           0,
-			TotalTime:            32340,
-			MaxTimespan:          89,
-			LastTime:             time.Now().Add(-1 * time.Minute),
-			DbType:               "sqlserver",
-			URL:                  config.AppConfig.Database.Host,
-			MaxTimespanOccurTime: time.Now().Add(-30 * time.Minute),
-			FetchRowCount:        0,
-			UpdateCount:          2156,
-			InTransactionCount:   2156,
-		},
-		{
-			SQL:                  "UPDATE sys_user SET login_ip = ?, login_date = ?, update_time = ? WHERE user_id = ?",
-			ExecuteCount:         324,
-			ErrorCount:           0,
-			TotalTime:            4536,
-			MaxTimespan:          42,
-			LastTime:             time.Now().Add(-3 * time.Minute),
-			DbType:               "sqlserver",
-			URL:                  config.AppConfig.Database.Host,
-			MaxTimespanOccurTime: time.Now().Add(-1 * time.Hour),
-			FetchRowCount:        0,
-			UpdateCount:          324,
-			InTransactionCount:   324,
-		},
+	dbConfig := config.AppConfig.Database
+	dialect := database.CurrentDialect().Name()
+	url := c.getURL(dialect, &dbConfig)
+
+	stats := database.Stats.SQLStats()
+	sqlStats := make([]SQLStat, 0, len(stats))
+	for _, stat := range stats {
+		sqlStats = append(sqlStats, SQLStat{
+			SQL:                  stat.SQL,
+			ExecuteCount:         stat.ExecuteCount,
+			ErrorCount:           stat.ErrorCount,
+			TotalTime:            stat.TotalTime.Milliseconds(),
+			MaxTimespan:          stat.MaxTimespan.Milliseconds(),
+			LastTime:             stat.LastTime,
+			DbType:               dialect,
+			URL:                  url,
+			MaxTimespanOccurTime: stat.MaxTimespanOccurTime,
+			LastError:            stat.LastError,
+			LastErrorTime:        stat.LastErrorTime,
+			LastErrorMessage:     stat.LastError,
+			FetchRowCount:        stat.FetchRowCount,
+			UpdateCount:          stat.UpdateCount,
+			InTransactionCount:   stat.InTransactionCount,
+			Histogram:            stat.Histogram[:],
+		})
 	}
-
 	return sqlStats
 }
 
-// 以下是统计信息获取的辅助方法 对应Java后端的DruidDataSourceStatManager的各种统计方法
-
-// getCurrentConnections 获取当前连接数
-func (c *DruidController) getCurrentConnections(db *gorm.DB) int {
-	sqlDB, err := db.DB()
-	if err != nil {
-		return 0
-	}
-	stats := sqlDB.Stats()
-	return stats.OpenConnections
+// driverClassNames 各数据库的Go驱动
+var driverClassNames = map[string]string{
+	database.DriverSqlServer: "github.com/microsoft/go-mssqldb",
+	database.DriverMySQL:     "github.com/go-sql-driver/mysql",
+	database.DriverPostgres:  "github.com/jackc/pgx/v5",
+	database.DriverSQLite:    "github.com/glebarez/go-sqlite",
 }
 
-// getActiveConnections 获取活跃连接数
-func (c *DruidController) getActiveConnections(db *gorm.DB) int {
-	sqlDB, err := db.DB()
-	if err != nil {
-		return 0
+// getURL 数据库连接地址（不含密码）
+func (c *DruidController) getURL(dialect string, dbConfig *config.DatabaseConfig) string {
+	if dialect == database.DriverSQLite {
+		return "sqlite:" + dbConfig.Database
 	}
-	stats := sqlDB.Stats()
-	return stats.InUse
-}
-
-// getConnectCount 获取连接次数（模拟）
-func (c *DruidController) getConnectCount() int64 {
-	return 15420 // 模拟数据
-}
-
-// getCloseCount 获取关闭次数（模拟）
-func (c *DruidController) getCloseCount() int64 {
-	return 15380 // 模拟数据
-}
-
-// getRecycleCount 获取回收次数（模拟）
-func (c *DruidController) getRecycleCount() int64 {
-	return 15380 // 模拟数据
-}
-
-// getActivePeak 获取活跃连接峰值（模拟）
-func (c *DruidController) getActivePeak() int {
-	return 18 // 模拟数据
-}
-
-// getExecuteCount 获取执行次数（模拟）
-func (c *DruidController) getExecuteCount() int64 {
-	return 45620 // 模拟数据
-}
-
-// getCommitCount 获取提交次数（模拟）
-func (c *DruidController) getCommitCount() int64 {
-	return 12340 // 模拟数据
-}
-
-// getExecuteMillisTotal 获取总执行时间（模拟）
-func (c *DruidController) getExecuteMillisTotal() int64 {
-	return 567890 // 模拟数据，毫秒
-}
-
-// getExecuteMillisMax 获取最大执行时间（模拟）
-func (c *DruidController) getExecuteMillisMax() int64 {
-	return 1250 // 模拟数据，毫秒
-}
-
-// getPreparedStatementOpenCount 获取预编译语句打开次数（模拟）
-func (c *DruidController) getPreparedStatementOpenCount() int64 {
-	return 8920 // 模拟数据
-}
-
-// getPreparedStatementClosedCount 获取预编译语句关闭次数（模拟）
-func (c *DruidController) getPreparedStatementClosedCount() int64 {
-	return 8900 // 模拟数据
-}
-
-// getCachedPreparedStatementCount 获取缓存预编译语句数（模拟）
-func (c *DruidController) getCachedPreparedStatementCount() int64 {
-	return 156 // 模拟数据
-}
-
-// getCachedPreparedStatementHitCount 获取缓存预编译语句命中数（模拟）
-func (c *DruidController) getCachedPreparedStatementHitCount() int64 {
-	return 7890 // 模拟数据
-}
-
-// getCachedPreparedStatementMissCount 获取缓存预编译语句未命中数（模拟）
-func (c *DruidController) getCachedPreparedStatementMissCount() int64 {
-	return 1030 // 模拟数据
+	return fmt.Sprintf("%s://%s:%d/%s", dialect, dbConfig.Host, dbConfig.Port, dbConfig.Database)
 }
 
 // generateIndexHTML 生成Druid监控首页HTML 对应Java后端的/druid/index.html
@@ -36,6 +36,12 @@ func (c *JobController) RegisterJobRoutes(router *gin.RouterGroup) {
 		// 查询定时任务列表 对应@PreAuthorize("@ss.hasPermi('monitor:job:list')")
 		jobGroup.GET("/list", middleware.RequirePermission("monitor:job:list"), c.List)
 
+		// 查询任务依赖关系图
+		jobGroup.GET("/graph", middleware.RequirePermission("monitor:job:list"), c.Graph)
+
+		// 查询cron表达式未来执行时间
+		jobGroup.GET("/nextTimes", middleware.RequirePermission("monitor:job:query"), c.NextTimes)
+
 		// 获取定时任务详细信息 对应@PreAuthorize("@ss.hasPermi('monitor:job:query')")
 		jobGroup.GET("/:jobId", middleware.RequirePermission("monitor:job:query"), c.GetInfo)
 
@@ -77,6 +83,7 @@ func (c *JobController) RegisterJobRoutes(router *gin.RouterGroup) {
 // @Success 200 {object} response.Result
 // @Router /monitor/job/list [get]
 func (c *JobController) List(ctx *gin.Context) {
+	jobService := c.jobService.WithContext(ctx.Request.Context())
 	fmt.Printf("JobController.List: 查询定时任务列表\n")
 
 	// 构建查询条件
@@ -101,7 +108,7 @@ func (c *JobController) List(ctx *gin.Context) {
 	}
 
 	// 查询定时任务列表
-	jobList, err := c.jobService.SelectJobList(job)
+	jobList, err := jobService.SelectJobList(job)
 	if err != nil {
 		fmt.Printf("JobController.List: 查询定时任务列表失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "查询定时任务列表失败")
@@ -113,6 +120,68 @@ func (c *JobController) List(ctx *gin.Context) {
 	response.Page(ctx, int64(len(jobList)), jobList)
 }
 
+// Graph 查询任务依赖关系图
+// @Summary 查询任务依赖关系图
+// @Description 返回全部任务节点、依赖边以及每个任务最近一次的执行状态
+// @Tags 定时任务管理
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /monitor/job/graph [get]
+func (c *JobController) Graph(ctx *gin.Context) {
+	jobService := c.jobService.WithContext(ctx.Request.Context())
+	fmt.Printf("JobController.Graph: 查询任务依赖关系图\n")
+
+	graph, err := jobService.SelectJobGraph()
+	if err != nil {
+		fmt.Printf("JobController.Graph: 查询任务依赖关系图失败: %v\n", err)
+		response.ErrorWithMessage(ctx, "查询任务依赖关系图失败")
+		return
+	}
+
+	fmt.Printf("JobController.Graph: 查询任务依赖关系图成功, 节点数=%d, 边数=%d\n", len(graph.Nodes), len(graph.Edges))
+	response.SuccessWithData(ctx, graph)
+}
+
+// NextTimes 查询cron表达式未来执行时间
+// @Summary 查询cron表达式未来执行时间
+// @Description 按Quartz语法解析cron表达式，返回未来N次执行时间，表达式无效时返回错误原因
+// @Tags 定时任务管理
+// @Accept json
+// @Produce json
+// @Param cronExpression query string true "cron表达式"
+// @Param count query int false "次数，默认5，最多50"
+// @Param calendarId query int false "排除日历ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /monitor/job/nextTimes [get]
+func (c *JobController) NextTimes(ctx *gin.Context) {
+	jobService := c.jobService.WithContext(ctx.Request.Context())
+	cronExpression := ctx.Query("cronExpression")
+	count, err := strconv.Atoi(ctx.DefaultQuery("count", "5"))
+	if err != nil || count <= 0 {
+		count = 5
+	}
+	if count > 50 {
+		count = 50
+	}
+
+	calendarId, _ := strconv.ParseInt(ctx.Query("calendarId"), 10, 64)
+
+	times, err := jobService.GetNextExecutions(cronExpression, count, calendarId)
+	if err != nil {
+		response.ErrorWithMessage(ctx, err.Error())
+		return
+	}
+
+	result := make([]string, 0, len(times))
+	for _, t := range times {
+		result = append(result, t.Format("2006-01-02 15:04:05"))
+	}
+	response.SuccessWithData(ctx, result)
+}
+
 // GetInfo 获取定时任务详细信息 对应Java后端的getInfo方法
 // @Summary 获取定时任务详细信息
 // @Description 根据任务ID获取定时任务详细信息
@@ -124,6 +193,7 @@ func (c *JobController) List(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /monitor/job/{jobId} [get]
 func (c *JobController) GetInfo(ctx *gin.Context) {
+	jobService := c.jobService.WithContext(ctx.Request.Context())
 	fmt.Printf("JobController.GetInfo: 获取定时任务详细信息\n")
 
 	jobIdStr := ctx.Param("jobId")
@@ -134,7 +204,7 @@ func (c *JobController) GetInfo(ctx *gin.Context) {
 	}
 
 	// 查询定时任务详情
-	job, err := c.jobService.SelectJobById(jobId)
+	job, err := jobService.SelectJobById(jobId)
 	if err != nil {
 		fmt.Printf("JobController.GetInfo: 查询定时任务详情失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "查询定时任务详情失败")
@@ -161,6 +231,7 @@ func (c *JobController) GetInfo(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /monitor/job [post]
 func (c *JobController) Add(ctx *gin.Context) {
+	jobService := c.jobService.WithContext(ctx.Request.Context())
 	fmt.Printf("JobController.Add: 新增定时任务\n")
 
 	var job model.SysJob
@@ -170,23 +241,28 @@ func (c *JobController) Add(ctx *gin.Context) {
 	}
 
 	// 验证cron表达式
-	if !c.jobService.CheckCronExpressionIsValid(job.CronExpression) {
+	if !jobService.CheckCronExpressionIsValid(job.CronExpression) {
 		response.ErrorWithMessage(ctx, fmt.Sprintf("新增任务'%s'失败，Cron表达式不正确", job.JobName))
 		return
 	}
 
-	// 完整的安全验证 对应Java后端的所有安全检查
-	if err := validateJobSecurity(&job); err != nil {
+	// HTTP/SQL任务校验任务配置，调用目标字符串任务进行完整的安全验证 对应Java后端的所有安全检查
+	if job.IsTypedJob() {
+		if err := jobService.ValidateTypedJob(&job); err != nil {
+			response.ErrorWithMessage(ctx, fmt.Sprintf("新增任务'%s'失败，%s", job.JobName, err.Error()))
+			return
+		}
+	} else if err := validateJobSecurity(&job); err != nil {
 		response.ErrorWithMessage(ctx, fmt.Sprintf("新增任务'%s'失败，%s", job.JobName, err.Error()))
 		return
 	}
 
 	// 新增定时任务
-	if err := c.jobService.InsertJob(&job); err != nil {
+	if err := jobService.InsertJob(&job); err != nil {
 		fmt.Printf("JobController.Add: 新增定时任务失败: %v\n", err)
 		// 记录操作日志 对应Java后端的@Log注解
 		operlog.RecordOperLog(ctx, "定时任务", "新增", fmt.Sprintf("新增定时任务'%s'失败: %s", job.JobName, err.Error()), false)
-		response.ErrorWithMessage(ctx, "新增定时任务失败")
+		response.ErrorWithMessage(ctx, "新增定时任务失败: "+err.Error())
 		return
 	}
 
@@ -207,6 +283,7 @@ func (c *JobController) Add(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /monitor/job [put]
 func (c *JobController) Edit(ctx *gin.Context) {
+	jobService := c.jobService.WithContext(ctx.Request.Context())
 	fmt.Printf("JobController.Edit: 修改定时任务\n")
 
 	var job model.SysJob
@@ -216,23 +293,28 @@ func (c *JobController) Edit(ctx *gin.Context) {
 	}
 
 	// 验证cron表达式
-	if !c.jobService.CheckCronExpressionIsValid(job.CronExpression) {
+	if !jobService.CheckCronExpressionIsValid(job.CronExpression) {
 		response.ErrorWithMessage(ctx, fmt.Sprintf("修改任务'%s'失败，Cron表达式不正确", job.JobName))
 		return
 	}
 
-	// 完整的安全验证 对应Java后端的所有安全检查
-	if err := validateJobSecurity(&job); err != nil {
+	// HTTP/SQL任务校验任务配置，调用目标字符串任务进行完整的安全验证 对应Java后端的所有安全检查
+	if job.IsTypedJob() {
+		if err := jobService.ValidateTypedJob(&job); err != nil {
+			response.ErrorWithMessage(ctx, fmt.Sprintf("修改任务'%s'失败，%s", job.JobName, err.Error()))
+			return
+		}
+	} else if err := validateJobSecurity(&job); err != nil {
 		response.ErrorWithMessage(ctx, fmt.Sprintf("修改任务'%s'失败，%s", job.JobName, err.Error()))
 		return
 	}
 
 	// 修改定时任务
-	if err := c.jobService.UpdateJob(&job); err != nil {
+	if err := jobService.UpdateJob(&job); err != nil {
 		fmt.Printf("JobController.Edit: 修改定时任务失败: %v\n", err)
 		// 记录操作日志 对应Java后端的@Log注解
 		operlog.RecordOperLog(ctx, "定时任务", "修改", fmt.Sprintf("修改定时任务'%s'失败: %s", job.JobName, err.Error()), false)
-		response.ErrorWithMessage(ctx, "修改定时任务失败")
+		response.ErrorWithMessage(ctx, "修改定时任务失败: "+err.Error())
 		return
 	}
 
@@ -253,6 +335,7 @@ func (c *JobController) Edit(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /monitor/job/{jobIds} [delete]
 func (c *JobController) Remove(ctx *gin.Context) {
+	jobService := c.jobService.WithContext(ctx.Request.Context())
 	fmt.Printf("JobController.Remove: 删除定时任务\n")
 
 	jobIdsStr := ctx.Param("jobIds")
@@ -274,7 +357,7 @@ func (c *JobController) Remove(ctx *gin.Context) {
 	}
 
 	// 删除定时任务
-	if err := c.jobService.DeleteJobByIds(jobIds); err != nil {
+	if err := jobService.DeleteJobByIds(jobIds); err != nil {
 		fmt.Printf("JobController.Remove: 删除定时任务失败: %v\n", err)
 		// 记录操作日志 对应Java后端的@Log注解
 		operlog.RecordOperLog(ctx, "定时任务", "删除", fmt.Sprintf("删除定时任务失败: %s", err.Error()), false)
@@ -299,6 +382,7 @@ func (c *JobController) Remove(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /monitor/job/changeStatus [put]
 func (c *JobController) ChangeStatus(ctx *gin.Context) {
+	jobService := c.jobService.WithContext(ctx.Request.Context())
 	fmt.Printf("JobController.ChangeStatus: 修改定时任务状态\n")
 
 	var job model.SysJob
@@ -308,7 +392,7 @@ func (c *JobController) ChangeStatus(ctx *gin.Context) {
 	}
 
 	// 修改任务状态
-	if err := c.jobService.ChangeStatus(&job); err != nil {
+	if err := jobService.ChangeStatus(&job); err != nil {
 		fmt.Printf("JobController.ChangeStatus: 修改任务状态失败: %v\n", err)
 		// 记录操作日志 对应Java后端的@Log注解
 		operlog.RecordOperLog(ctx, "定时任务", "状态修改", fmt.Sprintf("修改任务状态失败: %s", err.Error()), false)
@@ -337,6 +421,7 @@ func (c *JobController) ChangeStatus(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /monitor/job/run [put]
 func (c *JobController) Run(ctx *gin.Context) {
+	jobService := c.jobService.WithContext(ctx.Request.Context())
 	fmt.Printf("JobController.Run: 立即执行定时任务\n")
 
 	var job model.SysJob
@@ -346,7 +431,7 @@ func (c *JobController) Run(ctx *gin.Context) {
 	}
 
 	// 立即执行任务
-	if err := c.jobService.Run(&job); err != nil {
+	if err := jobService.Run(&job); err != nil {
 		fmt.Printf("JobController.Run: 立即执行任务失败: %v\n", err)
 		// 记录操作日志 对应Java后端的@Log注解
 		operlog.RecordOperLog(ctx, "定时任务", "立即执行", fmt.Sprintf("立即执行任务失败: %s", err.Error()), false)
@@ -376,6 +461,7 @@ func (c *JobController) Run(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /monitor/job/export [post]
 func (c *JobController) Export(ctx *gin.Context) {
+	jobService := c.jobService.WithContext(ctx.Request.Context())
 	fmt.Printf("JobController.Export: 导出定时任务列表\n")
 
 	// 权限验证 - 对应Java后端的@PreAuthorize("@ss.hasPermi('monitor:job:export')")
@@ -415,7 +501,7 @@ func (c *JobController) Export(ctx *gin.Context) {
 	}
 
 	// 查询所有符合条件的定时任务（不分页）
-	jobList, err := c.jobService.SelectJobList(job)
+	jobList, err := jobService.SelectJobList(job)
 	if err != nil {
 		fmt.Printf("JobController.Export: 查询定时任务列表失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "查询定时任务列表失败")
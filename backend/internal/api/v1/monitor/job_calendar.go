@@ -0,0 +1,219 @@
+package monitor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"wosm/internal/repository/model"
+	systemService "wosm/internal/service/system"
+	"wosm/pkg/operlog"
+	"wosm/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// iCal文件的最大大小
+const maxICalFileSize = 2 * 1024 * 1024
+
+// JobCalendarController 定时任务排除日历控制器（节假日、封账窗口）
+type JobCalendarController struct {
+	calendarService *systemService.JobCalendarService
+}
+
+// NewJobCalendarController 创建定时任务排除日历控制器实例
+func NewJobCalendarController() *JobCalendarController {
+	return &JobCalendarController{
+		calendarService: systemService.NewJobCalendarService(),
+	}
+}
+
+// List 查询日历列表
+// @Summary 查询排除日历列表
+// @Tags 定时任务管理
+// @Produce json
+// @Param calendarName query string false "日历名称"
+// @Param status query string false "状态"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /monitor/calendar/list [get]
+func (c *JobCalendarController) List(ctx *gin.Context) {
+	calendarService := c.calendarService.WithContext(ctx.Request.Context())
+	calendar := &model.SysJobCalendar{
+		CalendarName: ctx.Query("calendarName"),
+		Status:       ctx.Query("status"),
+	}
+
+	calendars, err := calendarService.SelectCalendarList(calendar)
+	if err != nil {
+		response.ErrorWithMessage(ctx, "查询日历列表失败")
+		return
+	}
+
+	response.Page(ctx, int64(len(calendars)), calendars)
+}
+
+// GetInfo 获取日历详细信息（包含排除规则）
+// @Summary 获取排除日历详细信息
+// @Tags 定时任务管理
+// @Produce json
+// @Param calendarId path int true "日历ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /monitor/calendar/{calendarId} [get]
+func (c *JobCalendarController) GetInfo(ctx *gin.Context) {
+	calendarService := c.calendarService.WithContext(ctx.Request.Context())
+	calendarId, err := strconv.ParseInt(ctx.Param("calendarId"), 10, 64)
+	if err != nil {
+		response.ErrorWithMessage(ctx, "日历ID格式错误")
+		return
+	}
+
+	calendar, err := calendarService.SelectCalendarById(calendarId)
+	if err != nil {
+		response.ErrorWithMessage(ctx, "查询日历详情失败")
+		return
+	}
+	if calendar == nil {
+		response.ErrorWithMessage(ctx, "日历不存在")
+		return
+	}
+
+	response.SuccessWithData(ctx, calendar)
+}
+
+// Add 新增日历
+// @Summary 新增排除日历
+// @Tags 定时任务管理
+// @Accept json
+// @Produce json
+// @Param calendar body model.SysJobCalendar true "日历信息"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /monitor/calendar [post]
+func (c *JobCalendarController) Add(ctx *gin.Context) {
+	calendarService := c.calendarService.WithContext(ctx.Request.Context())
+	var calendar model.SysJobCalendar
+	if err := ctx.ShouldBindJSON(&calendar); err != nil {
+		response.ErrorWithMessage(ctx, "参数错误")
+		return
+	}
+
+	if username, exists := ctx.Get("username"); exists {
+		calendar.CreateBy = fmt.Sprintf("%v", username)
+	}
+
+	if err := calendarService.InsertCalendar(&calendar); err != nil {
+		operlog.RecordOperLog(ctx, "排除日历", "新增", fmt.Sprintf("新增日历'%s'失败: %s", calendar.CalendarName, err.Error()), false)
+		response.ErrorWithMessage(ctx, fmt.Sprintf("新增日历'%s'失败，%s", calendar.CalendarName, err.Error()))
+		return
+	}
+
+	operlog.RecordOperLog(ctx, "排除日历", "新增", fmt.Sprintf("新增日历'%s'", calendar.CalendarName), true)
+	response.SuccessWithMessage(ctx, "新增成功")
+}
+
+// Edit 修改日历，rules 为null时保留原有规则
+// @Summary 修改排除日历
+// @Tags 定时任务管理
+// @Accept json
+// @Produce json
+// @Param calendar body model.SysJobCalendar true "日历信息"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /monitor/calendar [put]
+func (c *JobCalendarController) Edit(ctx *gin.Context) {
+	calendarService := c.calendarService.WithContext(ctx.Request.Context())
+	var calendar model.SysJobCalendar
+	if err := ctx.ShouldBindJSON(&calendar); err != nil {
+		response.ErrorWithMessage(ctx, "参数错误")
+		return
+	}
+
+	if username, exists := ctx.Get("username"); exists {
+		calendar.UpdateBy = fmt.Sprintf("%v", username)
+	}
+
+	if err := calendarService.UpdateCalendar(&calendar); err != nil {
+		operlog.RecordOperLog(ctx, "排除日历", "修改", fmt.Sprintf("修改日历'%s'失败: %s", calendar.CalendarName, err.Error()), false)
+		response.ErrorWithMessage(ctx, fmt.Sprintf("修改日历'%s'失败，%s", calendar.CalendarName, err.Error()))
+		return
+	}
+
+	operlog.RecordOperLog(ctx, "排除日历", "修改", fmt.Sprintf("修改日历'%s'", calendar.CalendarName), true)
+	response.SuccessWithMessage(ctx, "修改成功")
+}
+
+// Remove 删除日历
+// @Summary 删除排除日历
+// @Tags 定时任务管理
+// @Produce json
+// @Param calendarIds path string true "日历ID列表，多个用逗号分隔"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /monitor/calendar/{calendarIds} [delete]
+func (c *JobCalendarController) Remove(ctx *gin.Context) {
+	calendarService := c.calendarService.WithContext(ctx.Request.Context())
+	var calendarIds []int64
+	for _, idStr := range strings.Split(ctx.Param("calendarIds"), ",") {
+		id, err := strconv.ParseInt(strings.TrimSpace(idStr), 10, 64)
+		if err != nil {
+			response.ErrorWithMessage(ctx, "日历ID格式错误")
+			return
+		}
+		calendarIds = append(calendarIds, id)
+	}
+
+	if err := calendarService.DeleteCalendarByIds(calendarIds); err != nil {
+		operlog.RecordOperLog(ctx, "排除日历", "删除", fmt.Sprintf("删除日历失败: %s", err.Error()), false)
+		response.ErrorWithMessage(ctx, err.Error())
+		return
+	}
+
+	operlog.RecordOperLog(ctx, "排除日历", "删除", fmt.Sprintf("删除日历，ID: %v", calendarIds), true)
+	response.SuccessWithMessage(ctx, "删除成功")
+}
+
+// ImportICal 从iCal文件导入排除规则，追加到已有规则之后
+// @Summary 导入iCal节假日
+// @Tags 定时任务管理
+// @Accept multipart/form-data
+// @Produce json
+// @Param calendarId path int true "日历ID"
+// @Param file formData file true "iCal文件（.ics）"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /monitor/calendar/{calendarId}/import [post]
+func (c *JobCalendarController) ImportICal(ctx *gin.Context) {
+	calendarService := c.calendarService.WithContext(ctx.Request.Context())
+	calendarId, err := strconv.ParseInt(ctx.Param("calendarId"), 10, 64)
+	if err != nil {
+		response.ErrorWithMessage(ctx, "日历ID格式错误")
+		return
+	}
+
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		response.ErrorWithMessage(ctx, "请选择要导入的iCal文件")
+		return
+	}
+	if fileHeader.Size > maxICalFileSize {
+		response.ErrorWithMessage(ctx, "iCal文件不能超过2MB")
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		response.ErrorWithMessage(ctx, "读取iCal文件失败")
+		return
+	}
+	defer file.Close()
+
+	count, err := calendarService.ImportICal(calendarId, file)
+	if err != nil {
+		operlog.RecordOperLog(ctx, "排除日历", "导入", fmt.Sprintf("导入iCal失败: %s", err.Error()), false)
+		response.ErrorWithMessage(ctx, "导入失败，"+err.Error())
+		return
+	}
+
+	operlog.RecordOperLog(ctx, "排除日历", "导入", fmt.Sprintf("日历%d导入iCal规则%d条", calendarId, count), true)
+	response.SuccessWithMessage(ctx, fmt.Sprintf("导入成功，共导入%d条规则", count))
+}
@@ -1,8 +1,11 @@
 package monitor
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -32,6 +35,7 @@ func NewJobLogController() *JobLogController {
 // @PreAuthorize("@ss.hasPermi('monitor:job:list')")
 // @Router /monitor/jobLog/list [get]
 func (c *JobLogController) List(ctx *gin.Context) {
+	jobLogService := c.jobLogService.WithContext(ctx.Request.Context())
 	fmt.Printf("JobLogController.List: 查询定时任务调度日志列表\n")
 
 	// 设置请求分页数据 - 对应Java后端的startPage()
@@ -67,7 +71,7 @@ func (c *JobLogController) List(ctx *gin.Context) {
 	}
 
 	// 查询定时任务调度日志列表 - 对应Java后端的jobLogService.selectJobLogList(jobLog)
-	jobLogs, total, err := c.jobLogService.SelectJobLogList(jobLog, pageDomain.PageNum, pageDomain.PageSize)
+	jobLogs, total, err := jobLogService.SelectJobLogList(jobLog, pageDomain.PageNum, pageDomain.PageSize)
 	if err != nil {
 		fmt.Printf("JobLogController.List: 查询定时任务调度日志列表失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "查询失败: "+err.Error())
@@ -84,6 +88,7 @@ func (c *JobLogController) List(ctx *gin.Context) {
 // @PreAuthorize("@ss.hasPermi('monitor:job:query')")
 // @Router /monitor/jobLog/{jobLogId} [get]
 func (c *JobLogController) GetInfo(ctx *gin.Context) {
+	jobLogService := c.jobLogService.WithContext(ctx.Request.Context())
 	jobLogIdStr := ctx.Param("jobLogId")
 	jobLogId, err := strconv.ParseInt(jobLogIdStr, 10, 64)
 	if err != nil {
@@ -95,7 +100,7 @@ func (c *JobLogController) GetInfo(ctx *gin.Context) {
 	fmt.Printf("JobLogController.GetInfo: 查询定时任务调度日志详情, JobLogID=%d\n", jobLogId)
 
 	// 查询调度日志信息
-	jobLog, err := c.jobLogService.SelectJobLogById(jobLogId)
+	jobLog, err := jobLogService.SelectJobLogById(jobLogId)
 	if err != nil {
 		fmt.Printf("JobLogController.GetInfo: 查询定时任务调度日志详情失败: %v\n", err)
 		response.SendAjaxResult(ctx, response.AjaxErrorWithMessage("查询失败"))
@@ -116,6 +121,7 @@ func (c *JobLogController) GetInfo(ctx *gin.Context) {
 // @PreAuthorize("@ss.hasPermi('monitor:job:remove')")
 // @Router /monitor/jobLog/{jobLogIds} [delete]
 func (c *JobLogController) Remove(ctx *gin.Context) {
+	jobLogService := c.jobLogService.WithContext(ctx.Request.Context())
 	jobLogIdsStr := ctx.Param("jobLogIds")
 	fmt.Printf("JobLogController.Remove: 删除定时任务调度日志, JobLogIDs=%s\n", jobLogIdsStr)
 
@@ -133,7 +139,7 @@ func (c *JobLogController) Remove(ctx *gin.Context) {
 	}
 
 	// 删除调度日志
-	err := c.jobLogService.DeleteJobLogByIds(jobLogIds)
+	err := jobLogService.DeleteJobLogByIds(jobLogIds)
 	if err != nil {
 		fmt.Printf("JobLogController.Remove: 删除定时任务调度日志失败: %v\n", err)
 		// 记录操作日志 对应Java后端的@Log注解
@@ -152,10 +158,11 @@ func (c *JobLogController) Remove(ctx *gin.Context) {
 // @PreAuthorize("@ss.hasPermi('monitor:job:remove')")
 // @Router /monitor/jobLog/clean [delete]
 func (c *JobLogController) Clean(ctx *gin.Context) {
+	jobLogService := c.jobLogService.WithContext(ctx.Request.Context())
 	fmt.Printf("JobLogController.Clean: 清空定时任务调度日志\n")
 
 	// 清空调度日志
-	err := c.jobLogService.CleanJobLog()
+	err := jobLogService.CleanJobLog()
 	if err != nil {
 		fmt.Printf("JobLogController.Clean: 清空定时任务调度日志失败: %v\n", err)
 		// 记录操作日志 对应Java后端的@Log注解
@@ -174,6 +181,7 @@ func (c *JobLogController) Clean(ctx *gin.Context) {
 // @PreAuthorize("@ss.hasPermi('monitor:job:export')")
 // @Router /monitor/jobLog/export [post]
 func (c *JobLogController) Export(ctx *gin.Context) {
+	jobLogService := c.jobLogService.WithContext(ctx.Request.Context())
 	fmt.Printf("JobLogController.Export: 导出定时任务调度日志列表\n")
 
 	// 权限验证 - 对应Java后端的@PreAuthorize("@ss.hasPermi('monitor:job:export')")
@@ -213,7 +221,7 @@ func (c *JobLogController) Export(ctx *gin.Context) {
 	}
 
 	// 查询所有符合条件的调度日志（不分页）
-	jobLogs, err := c.jobLogService.SelectJobLogListAll(jobLog)
+	jobLogs, err := jobLogService.SelectJobLogListAll(jobLog)
 	if err != nil {
 		fmt.Printf("JobLogController.Export: 查询定时任务调度日志列表失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "查询定时任务调度日志列表失败")
@@ -261,6 +269,215 @@ func (c *JobLogController) Export(ctx *gin.Context) {
 	fmt.Printf("JobLogController.Export: 导出调度日志数据成功, 数量=%d\n", len(exportData))
 }
 
+// jobLogStreamMaxDuration 单个实时日志连接的最长推送时间，超过后客户端需要重新连接
+const jobLogStreamMaxDuration = 30 * time.Minute
+
+// jobLogStatusCheckInterval 推送实时日志时检查数据库执行状态的间隔
+const jobLogStatusCheckInterval = 5 * time.Second
+
+// Stream 通过Server-Sent Events实时推送任务执行日志
+// 事件类型：log（一行JSON格式的日志）、end（任务结束，数据为执行状态）、error（读取失败或推送超时）
+// EventSource无法设置请求头，可通过 ?token= 传递认证令牌
+// @PreAuthorize("@ss.hasPermi('monitor:job:query')")
+// @Router /monitor/jobLog/stream/{jobLogId} [get]
+func (c *JobLogController) Stream(ctx *gin.Context) {
+	jobLogService := c.jobLogService.WithContext(ctx.Request.Context())
+	jobLogId, err := strconv.ParseInt(ctx.Param("jobLogId"), 10, 64)
+	if err != nil {
+		response.SendAjaxResult(ctx, response.AjaxErrorWithMessage("调度日志ID格式错误"))
+		return
+	}
+
+	jobLog, err := jobLogService.SelectJobLogById(jobLogId)
+	if err != nil {
+		response.SendAjaxResult(ctx, response.AjaxErrorWithMessage("查询失败"))
+		return
+	}
+	if jobLog == nil {
+		response.SendAjaxResult(ctx, response.AjaxErrorWithMessage("调度日志不存在"))
+		return
+	}
+
+	fmt.Printf("JobLogController.Stream: 开始推送实时日志, JobLogID=%d\n", jobLogId)
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+
+	// 任务已结束（或执行中状态已超时），直接回放持久化的完整日志
+	if !jobLog.IsRunning(time.Now()) {
+		c.replayJobLog(ctx, jobLogId, 0)
+		return
+	}
+
+	var offset int64
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	deadline := time.NewTimer(jobLogStreamMaxDuration)
+	defer deadline.Stop()
+	heartbeat := time.Now()
+	statusChecked := time.Now()
+
+	for {
+		lines, finished, status, err := jobLogService.ReadJobLogStream(jobLogId, offset)
+		if err != nil {
+			writeSSEEvent(ctx, "error", err.Error())
+			return
+		}
+		for _, line := range lines {
+			writeSSEEvent(ctx, "log", line)
+		}
+		offset += int64(len(lines))
+
+		if finished {
+			writeSSEEvent(ctx, "end", status)
+			return
+		}
+
+		// Redis中的结束标记可能丢失（写入失败或已过期），定期检查数据库中的执行状态
+		if time.Since(statusChecked) >= jobLogStatusCheckInterval {
+			current, err := jobLogService.SelectJobLogById(jobLogId)
+			if err == nil && (current == nil || !current.IsRunning(time.Now())) {
+				c.replayJobLog(ctx, jobLogId, offset)
+				return
+			}
+			statusChecked = time.Now()
+		}
+
+		// 定期发送注释行保持连接
+		if time.Since(heartbeat) >= 15*time.Second {
+			fmt.Fprint(ctx.Writer, ": ping\n\n")
+			ctx.Writer.Flush()
+			heartbeat = time.Now()
+		}
+
+		select {
+		case <-ctx.Request.Context().Done():
+			fmt.Printf("JobLogController.Stream: 客户端断开连接, JobLogID=%d\n", jobLogId)
+			return
+		case <-deadline.C:
+			fmt.Printf("JobLogController.Stream: 超过最长推送时间, JobLogID=%d\n", jobLogId)
+			writeSSEEvent(ctx, "error", "实时日志推送超时，请重新连接")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// replayJobLog 推送已持久化的日志（跳过已推送的前offset行）后结束事件流
+// 执行中状态已超时的日志按失败结束
+func (c *JobLogController) replayJobLog(ctx *gin.Context, jobLogId int64, offset int64) {
+	jobLogService := c.jobLogService.WithContext(ctx.Request.Context())
+	jobLog, lines, err := jobLogService.SelectJobLogLines(jobLogId)
+	if err != nil || jobLog == nil {
+		writeSSEEvent(ctx, "error", "读取执行日志失败")
+		return
+	}
+	if offset < int64(len(lines)) {
+		for _, line := range lines[offset:] {
+			writeSSEEvent(ctx, "log", line)
+		}
+	}
+	status := jobLog.Status
+	if status == model.JobLogStatusRunning {
+		status = model.JobLogStatusFail
+	}
+	writeSSEEvent(ctx, "end", status)
+}
+
+// writeSSEEvent 写入一条Server-Sent Events事件并立即刷新
+func writeSSEEvent(ctx *gin.Context, event, data string) {
+	fmt.Fprintf(ctx.Writer, "event: %s\n", event)
+	for _, line := range strings.Split(data, "\n") {
+		fmt.Fprintf(ctx.Writer, "data: %s\n", line)
+	}
+	fmt.Fprint(ctx.Writer, "\n")
+	ctx.Writer.Flush()
+}
+
+// Content 查看任务的完整执行日志
+// @PreAuthorize("@ss.hasPermi('monitor:job:query')")
+// @Router /monitor/jobLog/content/{jobLogId} [get]
+func (c *JobLogController) Content(ctx *gin.Context) {
+	jobLogService := c.jobLogService.WithContext(ctx.Request.Context())
+	jobLogId, err := strconv.ParseInt(ctx.Param("jobLogId"), 10, 64)
+	if err != nil {
+		response.SendAjaxResult(ctx, response.AjaxErrorWithMessage("调度日志ID格式错误"))
+		return
+	}
+
+	jobLog, lines, err := jobLogService.SelectJobLogLines(jobLogId)
+	if err != nil {
+		fmt.Printf("JobLogController.Content: 查询执行日志失败: %v\n", err)
+		response.SendAjaxResult(ctx, response.AjaxErrorWithMessage("查询失败"))
+		return
+	}
+	if jobLog == nil {
+		response.SendAjaxResult(ctx, response.AjaxErrorWithMessage("调度日志不存在"))
+		return
+	}
+
+	logLines := make([]model.JobLogLine, 0, len(lines))
+	for _, line := range lines {
+		var logLine model.JobLogLine
+		if err := json.Unmarshal([]byte(line), &logLine); err != nil {
+			logLine = model.JobLogLine{Message: line}
+		}
+		logLines = append(logLines, logLine)
+	}
+
+	response.SendAjaxResult(ctx, response.AjaxSuccessWithData(map[string]interface{}{
+		"jobLog": jobLog,
+		"lines":  logLines,
+	}))
+}
+
+// ExportContent 导出任务的完整执行日志为文本文件
+// @PreAuthorize("@ss.hasPermi('monitor:job:export')")
+// @Router /monitor/jobLog/content/{jobLogId}/export [post]
+func (c *JobLogController) ExportContent(ctx *gin.Context) {
+	jobLogService := c.jobLogService.WithContext(ctx.Request.Context())
+	jobLogId, err := strconv.ParseInt(ctx.Param("jobLogId"), 10, 64)
+	if err != nil {
+		response.SendAjaxResult(ctx, response.AjaxErrorWithMessage("调度日志ID格式错误"))
+		return
+	}
+
+	jobLog, lines, err := jobLogService.SelectJobLogLines(jobLogId)
+	if err != nil || jobLog == nil {
+		response.SendAjaxResult(ctx, response.AjaxErrorWithMessage("调度日志不存在"))
+		return
+	}
+
+	var builder strings.Builder
+	for _, line := range lines {
+		var logLine model.JobLogLine
+		if err := json.Unmarshal([]byte(line), &logLine); err != nil {
+			builder.WriteString(line)
+			builder.WriteString("\n")
+			continue
+		}
+		fmt.Fprintf(&builder, "%s [%s] %s", logLine.Time, logLine.Level, logLine.Message)
+		keys := make([]string, 0, len(logLine.Fields))
+		for key := range logLine.Fields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Fprintf(&builder, " %s=%v", key, logLine.Fields[key])
+		}
+		builder.WriteString("\n")
+	}
+
+	filename := fmt.Sprintf("%s_%d.log", jobLog.JobName, jobLog.JobLogID)
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename*=UTF-8''%s", url.QueryEscape(filename)))
+	ctx.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(builder.String()))
+
+	operlog.RecordOperLog(ctx, "调度日志", "导出", fmt.Sprintf("导出执行日志，ID: %d", jobLogId), true)
+}
+
 // getJobLogStatusText 获取调度日志状态文本
 func getJobLogStatusText(status string) string {
 	switch status {
@@ -268,6 +485,8 @@ func getJobLogStatusText(status string) string {
 		return "成功"
 	case "1":
 		return "失败"
+	case "2":
+		return "执行中"
 	default:
 		return "未知"
 	}
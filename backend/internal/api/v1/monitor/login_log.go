@@ -45,6 +45,7 @@ func NewLoginLogController() *LoginLogController {
 // @Success 200 {object} response.Result
 // @Router /monitor/logininfor/list [get]
 func (c *LoginLogController) List(ctx *gin.Context) {
+	loginLogService := c.loginLogService.WithContext(ctx.Request.Context())
 	fmt.Printf("LoginLogController.List: 获取登录日志列表\n")
 
 	// 构建查询条件
@@ -66,7 +67,7 @@ func (c *LoginLogController) List(ctx *gin.Context) {
 	}
 
 	// 查询登录日志列表
-	logininforList, err := c.loginLogService.SelectLogininforList(logininfor)
+	logininforList, err := loginLogService.SelectLogininforList(logininfor)
 	if err != nil {
 		fmt.Printf("LoginLogController.List: 查询登录日志列表失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "查询登录日志列表失败")
@@ -89,6 +90,7 @@ func (c *LoginLogController) List(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /monitor/logininfor/{infoIds} [delete]
 func (c *LoginLogController) Remove(ctx *gin.Context) {
+	loginLogService := c.loginLogService.WithContext(ctx.Request.Context())
 	fmt.Printf("LoginLogController.Remove: 删除登录日志\n")
 
 	infoIdsStr := ctx.Param("infoIds")
@@ -110,7 +112,7 @@ func (c *LoginLogController) Remove(ctx *gin.Context) {
 	}
 
 	// 删除登录日志
-	if err := c.loginLogService.DeleteLogininforByIds(infoIds); err != nil {
+	if err := loginLogService.DeleteLogininforByIds(infoIds); err != nil {
 		fmt.Printf("LoginLogController.Remove: 删除登录日志失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "删除登录日志失败")
 		return
@@ -130,10 +132,11 @@ func (c *LoginLogController) Remove(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /monitor/logininfor/clean [delete]
 func (c *LoginLogController) Clean(ctx *gin.Context) {
+	loginLogService := c.loginLogService.WithContext(ctx.Request.Context())
 	fmt.Printf("LoginLogController.Clean: 清空登录日志\n")
 
 	// 清空登录日志
-	if err := c.loginLogService.CleanLogininfor(); err != nil {
+	if err := loginLogService.CleanLogininfor(); err != nil {
 		fmt.Printf("LoginLogController.Clean: 清空登录日志失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "清空登录日志失败")
 		return
@@ -158,6 +161,7 @@ func (c *LoginLogController) Clean(ctx *gin.Context) {
 // @Success 200 {file} file "Excel文件"
 // @Router /monitor/logininfor/export [post]
 func (c *LoginLogController) Export(ctx *gin.Context) {
+	loginLogService := c.loginLogService.WithContext(ctx.Request.Context())
 	// 权限验证 - 对应Java后端的@PreAuthorize("@ss.hasPermi('monitor:logininfor:export')")
 	loginUser, exists := ctx.Get("loginUser")
 	if !exists {
@@ -213,7 +217,7 @@ func (c *LoginLogController) Export(ctx *gin.Context) {
 		logininfor.UserName, logininfor.IPAddr, logininfor.Status, queryParams.BeginTime, queryParams.EndTime)
 
 	// 查询所有符合条件的登录日志（不分页） 对应Java后端的logininforService.selectLogininforList(logininfor)
-	logininforList, err := c.loginLogService.SelectLogininforList(logininfor)
+	logininforList, err := loginLogService.SelectLogininforList(logininfor)
 	if err != nil {
 		fmt.Printf("LoginLogController.Export: 查询登录日志数据失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "查询失败: "+err.Error())
@@ -46,6 +46,7 @@ func NewOperLogController() *OperLogController {
 // @Success 200 {object} response.Result
 // @Router /monitor/operlog/list [get]
 func (c *OperLogController) List(ctx *gin.Context) {
+	operLogService := c.operLogService.WithContext(ctx.Request.Context())
 	fmt.Printf("OperLogController.List: 获取操作日志列表\n")
 
 	// 构建查询条件
@@ -74,7 +75,7 @@ func (c *OperLogController) List(ctx *gin.Context) {
 	}
 
 	// 查询操作日志列表
-	operLogs, err := c.operLogService.SelectOperLogList(operLog)
+	operLogs, err := operLogService.SelectOperLogList(operLog)
 	if err != nil {
 		fmt.Printf("OperLogController.List: 查询操作日志列表失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "查询操作日志列表失败")
@@ -97,6 +98,7 @@ func (c *OperLogController) List(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /monitor/operlog/{operId} [get]
 func (c *OperLogController) GetInfo(ctx *gin.Context) {
+	operLogService := c.operLogService.WithContext(ctx.Request.Context())
 	operIdStr := ctx.Param("operId")
 	operId, err := strconv.Atoi(operIdStr)
 	if err != nil {
@@ -108,7 +110,7 @@ func (c *OperLogController) GetInfo(ctx *gin.Context) {
 	fmt.Printf("OperLogController.GetInfo: 查询操作日志详情, OperID=%d\n", operId)
 
 	// 查询操作日志信息
-	operLog, err := c.operLogService.SelectOperLogById(operId)
+	operLog, err := operLogService.SelectOperLogById(operId)
 	if err != nil {
 		fmt.Printf("OperLogController.GetInfo: 查询操作日志详情失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "查询失败")
@@ -136,6 +138,7 @@ func (c *OperLogController) GetInfo(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /monitor/operlog/{operIds} [delete]
 func (c *OperLogController) Remove(ctx *gin.Context) {
+	operLogService := c.operLogService.WithContext(ctx.Request.Context())
 	fmt.Printf("OperLogController.Remove: 删除操作日志\n")
 
 	operIdsStr := ctx.Param("operIds")
@@ -157,7 +160,7 @@ func (c *OperLogController) Remove(ctx *gin.Context) {
 	}
 
 	// 删除操作日志
-	if err := c.operLogService.DeleteOperLogByIds(operIds); err != nil {
+	if err := operLogService.DeleteOperLogByIds(operIds); err != nil {
 		fmt.Printf("OperLogController.Remove: 删除操作日志失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "删除操作日志失败")
 		return
@@ -177,10 +180,11 @@ func (c *OperLogController) Remove(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /monitor/operlog/clean [delete]
 func (c *OperLogController) Clean(ctx *gin.Context) {
+	operLogService := c.operLogService.WithContext(ctx.Request.Context())
 	fmt.Printf("OperLogController.Clean: 清空操作日志\n")
 
 	// 清空操作日志
-	if err := c.operLogService.CleanOperLog(); err != nil {
+	if err := operLogService.CleanOperLog(); err != nil {
 		fmt.Printf("OperLogController.Clean: 清空操作日志失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "清空操作日志失败")
 		return
@@ -206,6 +210,7 @@ func (c *OperLogController) Clean(ctx *gin.Context) {
 // @Success 200 {file} file "Excel文件"
 // @Router /monitor/operlog/export [post]
 func (c *OperLogController) Export(ctx *gin.Context) {
+	operLogService := c.operLogService.WithContext(ctx.Request.Context())
 	// 权限验证 - 对应Java后端的@PreAuthorize("@ss.hasPermi('monitor:operlog:export')")
 	loginUser, exists := ctx.Get("loginUser")
 	if !exists {
@@ -270,7 +275,7 @@ func (c *OperLogController) Export(ctx *gin.Context) {
 		operLog.Title, operLog.OperName, queryParams.BusinessType, queryParams.Status, queryParams.BeginTime, queryParams.EndTime)
 
 	// 查询所有符合条件的操作日志（不分页） 对应Java后端的operLogService.selectOperLogList(operLog)
-	operLogs, err := c.operLogService.SelectOperLogList(operLog)
+	operLogs, err := operLogService.SelectOperLogList(operLog)
 	if err != nil {
 		fmt.Printf("OperLogController.Export: 查询操作日志数据失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "查询失败: "+err.Error())
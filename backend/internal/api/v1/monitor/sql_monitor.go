@@ -0,0 +1,40 @@
+package monitor
+
+import (
+	"fmt"
+	"wosm/pkg/database"
+	"wosm/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SqlMonitorController 慢SQL监控控制器
+type SqlMonitorController struct{}
+
+// NewSqlMonitorController 创建慢SQL监控控制器实例
+func NewSqlMonitorController() *SqlMonitorController {
+	return &SqlMonitorController{}
+}
+
+// Slow 获取最近的慢SQL和请求中重复执行的SQL（N+1查询）
+// @Summary 获取慢SQL
+// @Description 获取最近的慢SQL和单次请求中重复执行次数超过阈值的SQL
+// @Tags 数据监控
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /monitor/sql/slow [get]
+func (c *SqlMonitorController) Slow(ctx *gin.Context) {
+	slowSQLMillis, repeatSQLThreshold := database.Stats.MonitorThresholds()
+	slowQueries := database.Stats.SlowSQLs()
+	repeatedQueries := database.Stats.RepeatedSQLs()
+
+	fmt.Printf("SqlMonitorController.Slow: 慢SQL数量=%d, 重复SQL数量=%d\n", len(slowQueries), len(repeatedQueries))
+	response.SuccessWithData(ctx, gin.H{
+		"slowSqlMillis":      slowSQLMillis,
+		"repeatSqlThreshold": repeatSQLThreshold,
+		"slowQueries":        slowQueries,
+		"repeatedQueries":    repeatedQueries,
+	})
+}
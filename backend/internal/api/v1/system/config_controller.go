@@ -1,6 +1,7 @@
 package system
 
 import (
+	"errors"
 	"fmt"
 	"net/url"
 	"strconv"
@@ -12,6 +13,7 @@ import (
 	"wosm/pkg/export"
 	"wosm/pkg/operlog"
 	"wosm/pkg/response"
+	"wosm/pkg/utils"
 
 	"github.com/gin-gonic/gin"
 )
@@ -46,6 +48,7 @@ func NewConfigController() *ConfigController {
 // @Success 200 {object} response.TableDataInfo
 // @Router /system/config/list [get]
 func (c *ConfigController) List(ctx *gin.Context) {
+	configService := c.configService.WithContext(ctx.Request.Context())
 	fmt.Printf("ConfigController.List: 获取参数配置列表\n")
 
 	// 绑定查询参数
@@ -64,14 +67,14 @@ func (c *ConfigController) List(ctx *gin.Context) {
 	}
 
 	// 查询参数配置列表
-	configs, err := c.configService.SelectConfigList(&params)
+	configs, err := configService.SelectConfigList(&params)
 	if err != nil {
 		response.ErrorWithMessage(ctx, "查询参数配置列表失败: "+err.Error())
 		return
 	}
 
 	// 查询总数
-	total, err := c.configService.CountConfigList(&params)
+	total, err := configService.CountConfigList(&params)
 	if err != nil {
 		response.ErrorWithMessage(ctx, "查询参数配置总数失败: "+err.Error())
 		return
@@ -92,6 +95,7 @@ func (c *ConfigController) List(ctx *gin.Context) {
 // @Success 200 {object} response.Response{data=model.SysConfig}
 // @Router /system/config/{configId} [get]
 func (c *ConfigController) GetInfo(ctx *gin.Context) {
+	configService := c.configService.WithContext(ctx.Request.Context())
 	configIdStr := ctx.Param("configId")
 	fmt.Printf("ConfigController.GetInfo: 获取参数配置详情, ConfigId=%s\n", configIdStr)
 
@@ -101,7 +105,7 @@ func (c *ConfigController) GetInfo(ctx *gin.Context) {
 		return
 	}
 
-	config, err := c.configService.SelectConfigById(configId)
+	config, err := configService.SelectConfigById(configId)
 	if err != nil {
 		response.ErrorWithMessage(ctx, "查询参数配置详情失败: "+err.Error())
 		return
@@ -112,6 +116,7 @@ func (c *ConfigController) GetInfo(ctx *gin.Context) {
 		return
 	}
 
+	utils.SetVersionETag(ctx, config.Version)
 	response.SuccessWithData(ctx, config)
 }
 
@@ -125,6 +130,7 @@ func (c *ConfigController) GetInfo(ctx *gin.Context) {
 // @Success 200 {object} response.Response{data=string}
 // @Router /system/config/configKey/{configKey} [get]
 func (c *ConfigController) GetConfigKey(ctx *gin.Context) {
+	configService := c.configService.WithContext(ctx.Request.Context())
 	configKey := ctx.Param("configKey")
 	fmt.Printf("ConfigController.GetConfigKey: 根据键名查询参数值, ConfigKey=%s\n", configKey)
 
@@ -133,7 +139,7 @@ func (c *ConfigController) GetConfigKey(ctx *gin.Context) {
 		return
 	}
 
-	configValue, err := c.configService.SelectConfigByKey(configKey)
+	configValue, err := configService.SelectConfigByKey(configKey)
 	if err != nil {
 		response.ErrorWithMessage(ctx, "查询参数值失败: "+err.Error())
 		return
@@ -152,6 +158,7 @@ func (c *ConfigController) GetConfigKey(ctx *gin.Context) {
 // @Success 200 {object} response.Response
 // @Router /system/config [post]
 func (c *ConfigController) Add(ctx *gin.Context) {
+	configService := c.configService.WithContext(ctx.Request.Context())
 	fmt.Printf("ConfigController.Add: 新增参数配置\n")
 
 	var config model.SysConfig
@@ -169,7 +176,7 @@ func (c *ConfigController) Add(ctx *gin.Context) {
 	config.CreateBy = username.(string)
 
 	// 检查参数键名唯一性
-	isUnique, err := c.configService.CheckConfigKeyUnique(&config)
+	isUnique, err := configService.CheckConfigKeyUnique(&config)
 	if err != nil {
 		response.ErrorWithMessage(ctx, "检查参数键名唯一性失败: "+err.Error())
 
@@ -182,7 +189,7 @@ func (c *ConfigController) Add(ctx *gin.Context) {
 	}
 
 	// 新增参数配置
-	err = c.configService.InsertConfig(&config)
+	err = configService.InsertConfig(&config)
 	if err != nil {
 		// 记录操作日志 - 失败
 		operlog.RecordOperLog(ctx, "参数管理", "新增", fmt.Sprintf("新增参数配置失败: %s", err.Error()), false)
@@ -205,6 +212,7 @@ func (c *ConfigController) Add(ctx *gin.Context) {
 // @Success 200 {object} response.Response
 // @Router /system/config [put]
 func (c *ConfigController) Edit(ctx *gin.Context) {
+	configService := c.configService.WithContext(ctx.Request.Context())
 	fmt.Printf("ConfigController.Edit: 修改参数配置\n")
 
 	var config model.SysConfig
@@ -221,8 +229,13 @@ func (c *ConfigController) Edit(ctx *gin.Context) {
 	}
 	config.UpdateBy = username.(string)
 
+	// 乐观锁版本号：请求体未传version时取If-Match请求头，都没有时拒绝修改
+	if !utils.RequireVersion(ctx, &config.Version) {
+		return
+	}
+
 	// 检查参数键名唯一性
-	isUnique, err := c.configService.CheckConfigKeyUnique(&config)
+	isUnique, err := configService.CheckConfigKeyUnique(&config)
 	if err != nil {
 		response.ErrorWithMessage(ctx, "检查参数键名唯一性失败: "+err.Error())
 
@@ -234,10 +247,14 @@ func (c *ConfigController) Edit(ctx *gin.Context) {
 	}
 
 	// 修改参数配置
-	err = c.configService.UpdateConfig(&config)
+	err = configService.UpdateConfig(&config)
 	if err != nil {
 		// 记录操作日志 - 失败
 		operlog.RecordOperLog(ctx, "参数管理", "修改", fmt.Sprintf("修改参数配置失败: %s", err.Error()), false)
+		if errors.Is(err, system.ErrVersionConflict) {
+			response.Conflict(ctx, system.ErrVersionConflict.Error())
+			return
+		}
 		response.ErrorWithMessage(ctx, "修改参数配置失败: "+err.Error())
 		return
 	}
@@ -257,6 +274,7 @@ func (c *ConfigController) Edit(ctx *gin.Context) {
 // @Success 200 {object} response.Response
 // @Router /system/config/{configIds} [delete]
 func (c *ConfigController) Remove(ctx *gin.Context) {
+	configService := c.configService.WithContext(ctx.Request.Context())
 	configIdsStr := ctx.Param("configIds")
 	fmt.Printf("ConfigController.Remove: 删除参数配置, ConfigIds=%s\n", configIdsStr)
 
@@ -278,7 +296,7 @@ func (c *ConfigController) Remove(ctx *gin.Context) {
 	}
 
 	// 删除参数配置
-	err := c.configService.DeleteConfigByIds(configIds)
+	err := configService.DeleteConfigByIds(configIds)
 	if err != nil {
 		// 记录操作日志 - 失败
 		operlog.RecordOperLog(ctx, "参数管理", "删除", fmt.Sprintf("删除参数配置失败: %s", err.Error()), false)
@@ -300,10 +318,11 @@ func (c *ConfigController) Remove(ctx *gin.Context) {
 // @Success 200 {object} response.Response
 // @Router /system/config/refreshCache [delete]
 func (c *ConfigController) RefreshCache(ctx *gin.Context) {
+	configService := c.configService.WithContext(ctx.Request.Context())
 	fmt.Printf("ConfigController.RefreshCache: 刷新参数缓存\n")
 
 	// 重置参数缓存
-	err := c.configService.ResetConfigCache()
+	err := configService.ResetConfigCache()
 	if err != nil {
 		// 记录操作日志 - 失败
 		operlog.RecordOperLog(ctx, "参数管理", "清空缓存", fmt.Sprintf("刷新参数缓存失败: %s", err.Error()), false)
@@ -328,6 +347,7 @@ func (c *ConfigController) RefreshCache(ctx *gin.Context) {
 // @Success 200 {file} file "Excel文件"
 // @Router /system/config/export [post]
 func (c *ConfigController) Export(ctx *gin.Context) {
+	configService := c.configService.WithContext(ctx.Request.Context())
 	// 权限验证 - 对应Java后端的@PreAuthorize("@ss.hasPermi('system:config:export')")
 	loginUser, exists := ctx.Get("loginUser")
 	if !exists {
@@ -377,7 +397,7 @@ func (c *ConfigController) Export(ctx *gin.Context) {
 		params.ConfigName, params.ConfigKey, params.ConfigType, queryParams.BeginTime, queryParams.EndTime)
 
 	// 查询所有符合条件的参数配置（不分页）
-	configs, err := c.configService.SelectConfigList(params)
+	configs, err := configService.SelectConfigList(params)
 	if err != nil {
 		response.ErrorWithMessage(ctx, "查询参数配置列表失败: "+err.Error())
 		return
@@ -1,6 +1,7 @@
 package system
 
 import (
+	"errors"
 	"fmt"
 	"net/url"
 	"strconv"
@@ -11,6 +12,7 @@ import (
 	"wosm/pkg/excel"
 	"wosm/pkg/operlog"
 	"wosm/pkg/response"
+	"wosm/pkg/utils"
 
 	"github.com/gin-gonic/gin"
 )
@@ -39,6 +41,7 @@ func NewDeptController() *DeptController {
 // @Success 200 {object} response.Result
 // @Router /system/dept/list [get]
 func (c *DeptController) List(ctx *gin.Context) {
+	deptService := c.deptService.WithContext(ctx.Request.Context())
 	fmt.Printf("DeptController.List: 获取部门列表\n")
 
 	// 构建查询条件
@@ -51,7 +54,7 @@ func (c *DeptController) List(ctx *gin.Context) {
 	}
 
 	// 查询部门列表
-	depts, err := c.deptService.SelectDeptList(dept)
+	depts, err := deptService.SelectDeptList(dept)
 	if err != nil {
 		fmt.Printf("DeptController.List: 查询部门列表失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "查询部门列表失败")
@@ -73,6 +76,7 @@ func (c *DeptController) List(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /system/dept/list/exclude/{deptId} [get]
 func (c *DeptController) ExcludeChild(ctx *gin.Context) {
+	deptService := c.deptService.WithContext(ctx.Request.Context())
 	fmt.Printf("DeptController.ExcludeChild: 查询部门列表（排除节点）\n")
 
 	deptIdStr := ctx.Param("deptId")
@@ -83,7 +87,7 @@ func (c *DeptController) ExcludeChild(ctx *gin.Context) {
 	}
 
 	// 查询所有部门
-	depts, err := c.deptService.SelectDeptList(&model.SysDept{})
+	depts, err := deptService.SelectDeptList(&model.SysDept{})
 	if err != nil {
 		fmt.Printf("DeptController.ExcludeChild: 查询部门列表失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "查询部门列表失败")
@@ -119,6 +123,7 @@ func (c *DeptController) ExcludeChild(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /system/dept/{deptId} [get]
 func (c *DeptController) GetInfo(ctx *gin.Context) {
+	deptService := c.deptService.WithContext(ctx.Request.Context())
 	fmt.Printf("DeptController.GetInfo: 获取部门详细信息\n")
 
 	deptIdStr := ctx.Param("deptId")
@@ -133,14 +138,14 @@ func (c *DeptController) GetInfo(ctx *gin.Context) {
 	currentUser := loginUser.(*model.LoginUser)
 
 	// 校验数据权限
-	if err := c.deptService.CheckDeptDataScope(currentUser.User, deptId); err != nil {
+	if err := deptService.CheckDeptDataScope(currentUser.User, deptId); err != nil {
 		fmt.Printf("DeptController.GetInfo: 数据权限校验失败: %v\n", err)
 		response.ErrorWithMessage(ctx, err.Error())
 		return
 	}
 
 	// 查询部门详情
-	dept, err := c.deptService.SelectDeptById(deptId)
+	dept, err := deptService.SelectDeptById(deptId)
 	if err != nil {
 		fmt.Printf("DeptController.GetInfo: 查询部门详情失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "查询部门详情失败")
@@ -153,6 +158,7 @@ func (c *DeptController) GetInfo(ctx *gin.Context) {
 	}
 
 	fmt.Printf("DeptController.GetInfo: 查询部门详情成功, DeptID=%d\n", deptId)
+	utils.SetVersionETag(ctx, dept.Version)
 	response.SuccessWithData(ctx, dept)
 }
 
@@ -167,6 +173,7 @@ func (c *DeptController) GetInfo(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /system/dept [post]
 func (c *DeptController) Add(ctx *gin.Context) {
+	deptService := c.deptService.WithContext(ctx.Request.Context())
 	fmt.Printf("DeptController.Add: 新增部门\n")
 
 	var dept model.SysDept
@@ -177,7 +184,7 @@ func (c *DeptController) Add(ctx *gin.Context) {
 	}
 
 	// 校验部门名称唯一性
-	if !c.deptService.CheckDeptNameUnique(&dept) {
+	if !deptService.CheckDeptNameUnique(&dept) {
 		response.ErrorWithMessage(ctx, fmt.Sprintf("新增部门'%s'失败，部门名称已存在", dept.DeptName))
 		return
 	}
@@ -188,7 +195,7 @@ func (c *DeptController) Add(ctx *gin.Context) {
 	dept.CreateBy = currentUser.User.UserName
 
 	// 新增部门
-	if err := c.deptService.InsertDept(&dept); err != nil {
+	if err := deptService.InsertDept(&dept); err != nil {
 		fmt.Printf("DeptController.Add: 新增部门失败: %v\n", err)
 		// 记录操作日志 - 失败
 		operlog.RecordOperLog(ctx, "部门管理", "新增", fmt.Sprintf("新增部门失败: %s", err.Error()), false)
@@ -213,6 +220,7 @@ func (c *DeptController) Add(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /system/dept [put]
 func (c *DeptController) Edit(ctx *gin.Context) {
+	deptService := c.deptService.WithContext(ctx.Request.Context())
 	fmt.Printf("DeptController.Edit: 修改部门\n")
 
 	var dept model.SysDept
@@ -222,19 +230,24 @@ func (c *DeptController) Edit(ctx *gin.Context) {
 		return
 	}
 
+	// 乐观锁版本号：请求体未传version时取If-Match请求头，都没有时拒绝修改
+	if !utils.RequireVersion(ctx, &dept.Version) {
+		return
+	}
+
 	// 获取当前登录用户
 	loginUser, _ := ctx.Get("loginUser")
 	currentUser := loginUser.(*model.LoginUser)
 
 	// 校验数据权限
-	if err := c.deptService.CheckDeptDataScope(currentUser.User, dept.DeptID); err != nil {
+	if err := deptService.CheckDeptDataScope(currentUser.User, dept.DeptID); err != nil {
 		fmt.Printf("DeptController.Edit: 数据权限校验失败: %v\n", err)
 		response.ErrorWithMessage(ctx, err.Error())
 		return
 	}
 
 	// 校验部门名称唯一性
-	if !c.deptService.CheckDeptNameUnique(&dept) {
+	if !deptService.CheckDeptNameUnique(&dept) {
 		response.ErrorWithMessage(ctx, fmt.Sprintf("修改部门'%s'失败，部门名称已存在", dept.DeptName))
 		return
 	}
@@ -247,7 +260,7 @@ func (c *DeptController) Edit(ctx *gin.Context) {
 
 	// 如果停用部门，检查是否包含未停用的子部门
 	if dept.Status == "1" {
-		count, err := c.deptService.SelectNormalChildrenDeptById(dept.DeptID)
+		count, err := deptService.SelectNormalChildrenDeptById(dept.DeptID)
 		if err == nil && count > 0 {
 			response.ErrorWithMessage(ctx, "该部门包含未停用的子部门！")
 			return
@@ -258,10 +271,14 @@ func (c *DeptController) Edit(ctx *gin.Context) {
 	dept.UpdateBy = currentUser.User.UserName
 
 	// 修改部门
-	if err := c.deptService.UpdateDept(&dept); err != nil {
+	if err := deptService.UpdateDept(&dept); err != nil {
 		fmt.Printf("DeptController.Edit: 修改部门失败: %v\n", err)
 		// 记录操作日志 - 失败
 		operlog.RecordOperLog(ctx, "部门管理", "修改", fmt.Sprintf("修改部门失败: %s", err.Error()), false)
+		if errors.Is(err, system.ErrVersionConflict) {
+			response.Conflict(ctx, system.ErrVersionConflict.Error())
+			return
+		}
 		response.ErrorWithMessage(ctx, "修改部门失败")
 		return
 	}
@@ -283,6 +300,7 @@ func (c *DeptController) Edit(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /system/dept/{deptId} [delete]
 func (c *DeptController) Remove(ctx *gin.Context) {
+	deptService := c.deptService.WithContext(ctx.Request.Context())
 	fmt.Printf("DeptController.Remove: 删除部门\n")
 
 	deptIdStr := ctx.Param("deptId")
@@ -296,7 +314,7 @@ func (c *DeptController) Remove(ctx *gin.Context) {
 	fmt.Printf("DeptController.Remove: 开始删除部门, DeptID=%d\n", deptId)
 
 	// 检查部门是否存在
-	dept, err := c.deptService.SelectDeptById(deptId)
+	dept, err := deptService.SelectDeptById(deptId)
 	if err != nil {
 		fmt.Printf("DeptController.Remove: 查询部门失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "查询部门失败")
@@ -309,14 +327,14 @@ func (c *DeptController) Remove(ctx *gin.Context) {
 	}
 
 	// 检查是否存在下级部门
-	if c.deptService.HasChildByDeptId(deptId) {
+	if deptService.HasChildByDeptId(deptId) {
 		fmt.Printf("DeptController.Remove: 存在下级部门, DeptID=%d\n", deptId)
 		response.ErrorWithMessage(ctx, "存在下级部门,不允许删除")
 		return
 	}
 
 	// 检查部门是否存在用户
-	if c.deptService.CheckDeptExistUser(deptId) {
+	if deptService.CheckDeptExistUser(deptId) {
 		fmt.Printf("DeptController.Remove: 部门存在用户, DeptID=%d\n", deptId)
 		response.ErrorWithMessage(ctx, "部门存在用户,不允许删除")
 		return
@@ -332,14 +350,14 @@ func (c *DeptController) Remove(ctx *gin.Context) {
 	currentUser := loginUser.(*model.LoginUser)
 
 	// 校验数据权限
-	if err := c.deptService.CheckDeptDataScope(currentUser.User, deptId); err != nil {
+	if err := deptService.CheckDeptDataScope(currentUser.User, deptId); err != nil {
 		fmt.Printf("DeptController.Remove: 数据权限校验失败: %v\n", err)
 		response.ErrorWithMessage(ctx, err.Error())
 		return
 	}
 
 	// 删除部门
-	if err := c.deptService.DeleteDeptById(deptId); err != nil {
+	if err := deptService.DeleteDeptById(currentUser.User, deptId); err != nil {
 		fmt.Printf("DeptController.Remove: 删除部门失败: %v\n", err)
 		// 记录操作日志 - 失败
 		operlog.RecordOperLog(ctx, "部门管理", "删除", fmt.Sprintf("删除部门失败: %s", err.Error()), false)
@@ -363,6 +381,7 @@ func (c *DeptController) Remove(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /system/dept/treeselect [get]
 func (c *DeptController) TreeSelect(ctx *gin.Context) {
+	deptService := c.deptService.WithContext(ctx.Request.Context())
 	fmt.Printf("DeptController.TreeSelect: 获取部门下拉树列表\n")
 
 	// 构建查询条件
@@ -375,7 +394,7 @@ func (c *DeptController) TreeSelect(ctx *gin.Context) {
 	}
 
 	// 查询部门树选择结构
-	treeSelect, err := c.deptService.SelectDeptTreeList(dept)
+	treeSelect, err := deptService.SelectDeptTreeList(dept)
 	if err != nil {
 		fmt.Printf("DeptController.TreeSelect: 查询部门树失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "查询部门树失败")
@@ -397,6 +416,7 @@ func (c *DeptController) TreeSelect(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /system/dept/roleDeptTreeselect/{roleId} [get]
 func (c *DeptController) RoleDeptTreeSelect(ctx *gin.Context) {
+	deptService := c.deptService.WithContext(ctx.Request.Context())
 	fmt.Printf("DeptController.RoleDeptTreeSelect: 加载对应角色部门列表树\n")
 
 	roleIdStr := ctx.Param("roleId")
@@ -407,7 +427,7 @@ func (c *DeptController) RoleDeptTreeSelect(ctx *gin.Context) {
 	}
 
 	// 查询所有部门树
-	treeSelect, err := c.deptService.SelectDeptTreeList(&model.SysDept{})
+	treeSelect, err := deptService.SelectDeptTreeList(&model.SysDept{})
 	if err != nil {
 		fmt.Printf("DeptController.RoleDeptTreeSelect: 查询部门树失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "查询部门树失败")
@@ -415,7 +435,7 @@ func (c *DeptController) RoleDeptTreeSelect(ctx *gin.Context) {
 	}
 
 	// 获取角色已选中的部门ID列表
-	checkedKeys, err := c.deptService.SelectDeptListByRoleId(roleId)
+	checkedKeys, err := deptService.SelectDeptListByRoleId(roleId)
 	if err != nil {
 		fmt.Printf("DeptController.RoleDeptTreeSelect: 查询角色部门失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "查询角色部门失败")
@@ -443,6 +463,7 @@ func (c *DeptController) RoleDeptTreeSelect(ctx *gin.Context) {
 // @Success 200 {file} file "Excel文件"
 // @Router /system/dept/export [post]
 func (c *DeptController) Export(ctx *gin.Context) {
+	deptService := c.deptService.WithContext(ctx.Request.Context())
 	fmt.Printf("DeptController.Export: 导出部门数据\n")
 
 	// 权限验证 - 对应Java后端的@PreAuthorize("@ss.hasPermi('system:dept:export')")
@@ -482,7 +503,7 @@ func (c *DeptController) Export(ctx *gin.Context) {
 	_ = endTime   // 避免未使用变量警告
 
 	// 查询部门列表
-	depts, err := c.deptService.SelectDeptList(dept)
+	depts, err := deptService.SelectDeptList(dept)
 	if err != nil {
 		fmt.Printf("DeptController.Export: 查询部门列表失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "查询部门列表失败")
@@ -1,6 +1,7 @@
 package system
 
 import (
+	"errors"
 	"fmt"
 	"net/url"
 	"strconv"
@@ -12,6 +13,7 @@ import (
 	"wosm/pkg/export"
 	"wosm/pkg/operlog"
 	"wosm/pkg/response"
+	"wosm/pkg/utils"
 
 	"github.com/gin-gonic/gin"
 )
@@ -43,6 +45,7 @@ func NewDictDataController() *DictDataController {
 // @Success 200 {object} response.Result
 // @Router /system/dict/data/list [get]
 func (c *DictDataController) List(ctx *gin.Context) {
+	dictDataService := c.dictDataService.WithContext(ctx.Request.Context())
 	fmt.Printf("DictDataController.List: 获取字典数据列表\n")
 
 	// 获取分页参数
@@ -64,7 +67,7 @@ func (c *DictDataController) List(ctx *gin.Context) {
 	// 检查是否需要分页
 	if pageNum > 0 && pageSize > 0 {
 		// 分页查询
-		dictDatas, total, err := c.dictDataService.SelectDictDataListWithPage(dictData, pageNum, pageSize)
+		dictDatas, total, err := dictDataService.SelectDictDataListWithPage(dictData, pageNum, pageSize)
 		if err != nil {
 			fmt.Printf("DictDataController.List: 分页查询字典数据列表失败: %v\n", err)
 			response.ErrorWithMessage(ctx, "查询字典数据列表失败")
@@ -77,7 +80,7 @@ func (c *DictDataController) List(ctx *gin.Context) {
 		response.SendTableDataInfo(ctx, tableData)
 	} else {
 		// 不分页查询（用于下拉框等场景）
-		dictDatas, err := c.dictDataService.SelectDictDataList(dictData)
+		dictDatas, err := dictDataService.SelectDictDataList(dictData)
 		if err != nil {
 			fmt.Printf("DictDataController.List: 查询字典数据列表失败: %v\n", err)
 			response.ErrorWithMessage(ctx, "查询字典数据列表失败")
@@ -102,6 +105,7 @@ func (c *DictDataController) List(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /system/dict/data/{dictCode} [get]
 func (c *DictDataController) GetInfo(ctx *gin.Context) {
+	dictDataService := c.dictDataService.WithContext(ctx.Request.Context())
 	fmt.Printf("DictDataController.GetInfo: 获取字典数据详细信息\n")
 
 	dictCodeStr := ctx.Param("dictCode")
@@ -112,7 +116,7 @@ func (c *DictDataController) GetInfo(ctx *gin.Context) {
 	}
 
 	// 查询字典数据详情
-	dictData, err := c.dictDataService.SelectDictDataById(dictCode)
+	dictData, err := dictDataService.SelectDictDataById(dictCode)
 	if err != nil {
 		fmt.Printf("DictDataController.GetInfo: 查询字典数据详情失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "查询字典数据详情失败")
@@ -125,6 +129,7 @@ func (c *DictDataController) GetInfo(ctx *gin.Context) {
 	}
 
 	fmt.Printf("DictDataController.GetInfo: 查询字典数据详情成功, DictCode=%d\n", dictCode)
+	utils.SetVersionETag(ctx, dictData.Version)
 	response.SuccessWithData(ctx, dictData)
 }
 
@@ -139,6 +144,7 @@ func (c *DictDataController) GetInfo(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /system/dict/data/type/{dictType} [get]
 func (c *DictDataController) DictType(ctx *gin.Context) {
+	dictDataService := c.dictDataService.WithContext(ctx.Request.Context())
 	fmt.Printf("DictDataController.DictType: 根据字典类型查询字典数据\n")
 
 	dictType := ctx.Param("dictType")
@@ -148,7 +154,7 @@ func (c *DictDataController) DictType(ctx *gin.Context) {
 	}
 
 	// 查询字典数据
-	dictDatas, err := c.dictDataService.SelectDictDataByType(dictType)
+	dictDatas, err := dictDataService.SelectDictDataByType(dictType)
 	if err != nil {
 		fmt.Printf("DictDataController.DictType: 查询字典数据失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "查询字典数据失败")
@@ -170,6 +176,7 @@ func (c *DictDataController) DictType(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /system/dict/data [post]
 func (c *DictDataController) Add(ctx *gin.Context) {
+	dictDataService := c.dictDataService.WithContext(ctx.Request.Context())
 	fmt.Printf("DictDataController.Add: 新增字典数据\n")
 
 	var dictData model.SysDictData
@@ -185,7 +192,7 @@ func (c *DictDataController) Add(ctx *gin.Context) {
 	dictData.CreateBy = currentUser.User.UserName
 
 	// 新增字典数据
-	if err := c.dictDataService.InsertDictData(&dictData); err != nil {
+	if err := dictDataService.InsertDictData(&dictData); err != nil {
 		fmt.Printf("DictDataController.Add: 新增字典数据失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "新增字典数据失败")
 		return
@@ -206,6 +213,7 @@ func (c *DictDataController) Add(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /system/dict/data [put]
 func (c *DictDataController) Edit(ctx *gin.Context) {
+	dictDataService := c.dictDataService.WithContext(ctx.Request.Context())
 	fmt.Printf("DictDataController.Edit: 修改字典数据\n")
 
 	var dictData model.SysDictData
@@ -215,14 +223,23 @@ func (c *DictDataController) Edit(ctx *gin.Context) {
 		return
 	}
 
+	// 乐观锁版本号：请求体未传version时取If-Match请求头，都没有时拒绝修改
+	if !utils.RequireVersion(ctx, &dictData.Version) {
+		return
+	}
+
 	// 获取当前登录用户
 	loginUser, _ := ctx.Get("loginUser")
 	currentUser := loginUser.(*model.LoginUser)
 	dictData.UpdateBy = currentUser.User.UserName
 
 	// 修改字典数据
-	if err := c.dictDataService.UpdateDictData(&dictData); err != nil {
+	if err := dictDataService.UpdateDictData(&dictData); err != nil {
 		fmt.Printf("DictDataController.Edit: 修改字典数据失败: %v\n", err)
+		if errors.Is(err, systemService.ErrVersionConflict) {
+			response.Conflict(ctx, systemService.ErrVersionConflict.Error())
+			return
+		}
 		response.ErrorWithMessage(ctx, "修改字典数据失败")
 		return
 	}
@@ -242,6 +259,7 @@ func (c *DictDataController) Edit(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /system/dict/data/{dictCodes} [delete]
 func (c *DictDataController) Remove(ctx *gin.Context) {
+	dictDataService := c.dictDataService.WithContext(ctx.Request.Context())
 	fmt.Printf("DictDataController.Remove: 删除字典数据\n")
 
 	dictCodesStr := ctx.Param("dictCodes")
@@ -263,7 +281,7 @@ func (c *DictDataController) Remove(ctx *gin.Context) {
 	}
 
 	// 删除字典数据
-	if err := c.dictDataService.DeleteDictDataByIds(dictCodes); err != nil {
+	if err := dictDataService.DeleteDictDataByIds(dictCodes); err != nil {
 		fmt.Printf("DictDataController.Remove: 删除字典数据失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "删除字典数据失败")
 		return
@@ -289,6 +307,7 @@ func (c *DictDataController) Remove(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /system/dict/data/export [post]
 func (c *DictDataController) Export(ctx *gin.Context) {
+	dictDataService := c.dictDataService.WithContext(ctx.Request.Context())
 	// 权限验证 - 对应Java后端的@PreAuthorize("@ss.hasPermi('system:dict:export')")
 	loginUser, exists := ctx.Get("loginUser")
 	if !exists {
@@ -337,7 +356,7 @@ func (c *DictDataController) Export(ctx *gin.Context) {
 		dictData.DictLabel, dictData.DictValue, dictData.DictType, dictData.Status, queryParams.BeginTime, queryParams.EndTime)
 
 	// 查询所有符合条件的字典数据（不分页）
-	dictDatas, err := c.dictDataService.SelectDictDataList(dictData)
+	dictDatas, err := dictDataService.SelectDictDataList(dictData)
 	if err != nil {
 		response.ErrorWithMessage(ctx, "查询失败")
 		return
@@ -45,6 +45,7 @@ func NewDictTypeController() *DictTypeController {
 // @Success 200 {object} response.Result
 // @Router /system/dict/type/list [get]
 func (c *DictTypeController) List(ctx *gin.Context) {
+	dictTypeService := c.dictTypeService.WithContext(ctx.Request.Context())
 	fmt.Printf("DictTypeController.List: 获取字典类型列表\n")
 
 	// 获取分页参数
@@ -66,7 +67,7 @@ func (c *DictTypeController) List(ctx *gin.Context) {
 	// 检查是否需要分页
 	if pageNum > 0 && pageSize > 0 {
 		// 分页查询
-		dictTypes, total, err := c.dictTypeService.SelectDictTypeListWithPage(dictType, pageNum, pageSize)
+		dictTypes, total, err := dictTypeService.SelectDictTypeListWithPage(dictType, pageNum, pageSize)
 		if err != nil {
 			fmt.Printf("DictTypeController.List: 分页查询字典类型列表失败: %v\n", err)
 			response.ErrorWithMessage(ctx, "查询字典类型列表失败")
@@ -79,7 +80,7 @@ func (c *DictTypeController) List(ctx *gin.Context) {
 		response.SendTableDataInfo(ctx, tableData)
 	} else {
 		// 不分页查询（用于下拉框等场景）
-		dictTypes, err := c.dictTypeService.SelectDictTypeList(dictType)
+		dictTypes, err := dictTypeService.SelectDictTypeList(dictType)
 		if err != nil {
 			fmt.Printf("DictTypeController.List: 查询字典类型列表失败: %v\n", err)
 			response.ErrorWithMessage(ctx, "查询字典类型列表失败")
@@ -104,6 +105,7 @@ func (c *DictTypeController) List(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /system/dict/type/{dictId} [get]
 func (c *DictTypeController) GetInfo(ctx *gin.Context) {
+	dictTypeService := c.dictTypeService.WithContext(ctx.Request.Context())
 	fmt.Printf("DictTypeController.GetInfo: 获取字典类型详细信息\n")
 
 	dictIdStr := ctx.Param("dictId")
@@ -114,7 +116,7 @@ func (c *DictTypeController) GetInfo(ctx *gin.Context) {
 	}
 
 	// 查询字典类型详情
-	dictType, err := c.dictTypeService.SelectDictTypeById(dictId)
+	dictType, err := dictTypeService.SelectDictTypeById(dictId)
 	if err != nil {
 		fmt.Printf("DictTypeController.GetInfo: 查询字典类型详情失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "查询字典类型详情失败")
@@ -141,6 +143,7 @@ func (c *DictTypeController) GetInfo(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /system/dict/type [post]
 func (c *DictTypeController) Add(ctx *gin.Context) {
+	dictTypeService := c.dictTypeService.WithContext(ctx.Request.Context())
 	fmt.Printf("DictTypeController.Add: 新增字典类型\n")
 
 	var dictType model.SysDictType
@@ -151,7 +154,7 @@ func (c *DictTypeController) Add(ctx *gin.Context) {
 	}
 
 	// 校验字典类型唯一性
-	if !c.dictTypeService.CheckDictTypeUnique(&dictType) {
+	if !dictTypeService.CheckDictTypeUnique(&dictType) {
 		response.ErrorWithMessage(ctx, fmt.Sprintf("新增字典'%s'失败，字典类型已存在", dictType.DictType))
 		return
 	}
@@ -162,7 +165,7 @@ func (c *DictTypeController) Add(ctx *gin.Context) {
 	dictType.CreateBy = currentUser.User.UserName
 
 	// 新增字典类型
-	if err := c.dictTypeService.InsertDictType(&dictType); err != nil {
+	if err := dictTypeService.InsertDictType(&dictType); err != nil {
 		fmt.Printf("DictTypeController.Add: 新增字典类型失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "新增字典类型失败")
 		return
@@ -183,6 +186,7 @@ func (c *DictTypeController) Add(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /system/dict/type [put]
 func (c *DictTypeController) Edit(ctx *gin.Context) {
+	dictTypeService := c.dictTypeService.WithContext(ctx.Request.Context())
 	fmt.Printf("DictTypeController.Edit: 修改字典类型\n")
 
 	var dictType model.SysDictType
@@ -193,7 +197,7 @@ func (c *DictTypeController) Edit(ctx *gin.Context) {
 	}
 
 	// 校验字典类型唯一性
-	if !c.dictTypeService.CheckDictTypeUnique(&dictType) {
+	if !dictTypeService.CheckDictTypeUnique(&dictType) {
 		response.ErrorWithMessage(ctx, fmt.Sprintf("修改字典'%s'失败，字典类型已存在", dictType.DictType))
 		return
 	}
@@ -204,7 +208,7 @@ func (c *DictTypeController) Edit(ctx *gin.Context) {
 	dictType.UpdateBy = currentUser.User.UserName
 
 	// 修改字典类型
-	if err := c.dictTypeService.UpdateDictType(&dictType); err != nil {
+	if err := dictTypeService.UpdateDictType(&dictType); err != nil {
 		fmt.Printf("DictTypeController.Edit: 修改字典类型失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "修改字典类型失败")
 		return
@@ -225,6 +229,8 @@ func (c *DictTypeController) Edit(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /system/dict/type/{dictIds} [delete]
 func (c *DictTypeController) Remove(ctx *gin.Context) {
+	dictTypeService := c.dictTypeService.WithContext(ctx.Request.Context())
+	dictDataService := c.dictDataService.WithContext(ctx.Request.Context())
 	fmt.Printf("DictTypeController.Remove: 删除字典类型\n")
 
 	dictIdsStr := ctx.Param("dictIds")
@@ -247,13 +253,13 @@ func (c *DictTypeController) Remove(ctx *gin.Context) {
 
 	// 检查字典类型是否被使用
 	for _, dictId := range dictIds {
-		dictType, err := c.dictTypeService.SelectDictTypeById(dictId)
+		dictType, err := dictTypeService.SelectDictTypeById(dictId)
 		if err != nil {
 			response.ErrorWithMessage(ctx, "查询字典类型失败")
 			return
 		}
 		if dictType != nil {
-			count, err := c.dictDataService.CountDictDataByType(dictType.DictType)
+			count, err := dictDataService.CountDictDataByType(dictType.DictType)
 			if err == nil && count > 0 {
 				response.ErrorWithMessage(ctx, fmt.Sprintf("%s已分配,不能删除", dictType.DictName))
 				return
@@ -262,7 +268,7 @@ func (c *DictTypeController) Remove(ctx *gin.Context) {
 	}
 
 	// 删除字典类型
-	if err := c.dictTypeService.DeleteDictTypeByIds(dictIds); err != nil {
+	if err := dictTypeService.DeleteDictTypeByIds(dictIds); err != nil {
 		fmt.Printf("DictTypeController.Remove: 删除字典类型失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "删除字典类型失败")
 		return
@@ -282,10 +288,11 @@ func (c *DictTypeController) Remove(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /system/dict/type/refreshCache [delete]
 func (c *DictTypeController) RefreshCache(ctx *gin.Context) {
+	dictTypeService := c.dictTypeService.WithContext(ctx.Request.Context())
 	fmt.Printf("DictTypeController.RefreshCache: 刷新字典缓存\n")
 
 	// 重置字典缓存
-	if err := c.dictTypeService.ResetDictCache(); err != nil {
+	if err := dictTypeService.ResetDictCache(); err != nil {
 		fmt.Printf("DictTypeController.RefreshCache: 刷新字典缓存失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "刷新字典缓存失败")
 		return
@@ -305,10 +312,11 @@ func (c *DictTypeController) RefreshCache(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /system/dict/type/optionselect [get]
 func (c *DictTypeController) OptionSelect(ctx *gin.Context) {
+	dictTypeService := c.dictTypeService.WithContext(ctx.Request.Context())
 	fmt.Printf("DictTypeController.OptionSelect: 获取字典选择框列表\n")
 
 	// 查询所有字典类型
-	dictTypes, err := c.dictTypeService.SelectDictTypeAll()
+	dictTypes, err := dictTypeService.SelectDictTypeAll()
 	if err != nil {
 		fmt.Printf("DictTypeController.OptionSelect: 查询字典类型列表失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "查询字典类型列表失败")
@@ -332,6 +340,7 @@ func (c *DictTypeController) OptionSelect(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /system/dict/type/export [post]
 func (c *DictTypeController) Export(ctx *gin.Context) {
+	dictTypeService := c.dictTypeService.WithContext(ctx.Request.Context())
 	// 权限验证 - 对应Java后端的@PreAuthorize("@ss.hasPermi('system:dict:export')")
 	loginUser, exists := ctx.Get("loginUser")
 	if !exists {
@@ -379,7 +388,7 @@ func (c *DictTypeController) Export(ctx *gin.Context) {
 		dictType.DictName, dictType.DictType, dictType.Status, queryParams.BeginTime, queryParams.EndTime)
 
 	// 查询所有符合条件的字典类型（不分页）
-	dictTypes, err := c.dictTypeService.SelectDictTypeList(dictType)
+	dictTypes, err := dictTypeService.SelectDictTypeList(dictType)
 	if err != nil {
 		response.ErrorWithMessage(ctx, "查询失败")
 		return
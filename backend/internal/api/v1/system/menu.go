@@ -1,6 +1,7 @@
 package system
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -8,6 +9,7 @@ import (
 	"wosm/internal/repository/model"
 	systemService "wosm/internal/service/system"
 	"wosm/pkg/response"
+	"wosm/pkg/utils"
 
 	"github.com/gin-gonic/gin"
 )
@@ -27,6 +29,7 @@ func NewMenuController() *MenuController {
 // List 获取菜单列表 对应Java后端的list方法
 // @Router /system/menu/list [get]
 func (c *MenuController) List(ctx *gin.Context) {
+	menuService := c.menuService.WithContext(ctx.Request.Context())
 	fmt.Printf("MenuController.List: 查询菜单列表\n")
 
 	// 构建查询条件
@@ -45,7 +48,7 @@ func (c *MenuController) List(ctx *gin.Context) {
 	currentUser := loginUser.(*model.LoginUser)
 
 	// 查询菜单列表
-	menus, err := c.menuService.SelectMenuList(menu, currentUser.UserID)
+	menus, err := menuService.SelectMenuList(menu, currentUser.UserID)
 	if err != nil {
 		fmt.Printf("MenuController.List: 查询菜单列表失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "查询菜单列表失败")
@@ -59,6 +62,7 @@ func (c *MenuController) List(ctx *gin.Context) {
 // GetInfo 根据菜单ID获取详细信息 对应Java后端的getInfo方法
 // @Router /system/menu/{menuId} [get]
 func (c *MenuController) GetInfo(ctx *gin.Context) {
+	menuService := c.menuService.WithContext(ctx.Request.Context())
 	menuIdStr := ctx.Param("menuId")
 	menuId, err := strconv.ParseInt(menuIdStr, 10, 64)
 	if err != nil {
@@ -70,7 +74,7 @@ func (c *MenuController) GetInfo(ctx *gin.Context) {
 	fmt.Printf("MenuController.GetInfo: 查询菜单详情, MenuID=%d\n", menuId)
 
 	// 查询菜单信息
-	menu, err := c.menuService.SelectMenuById(menuId)
+	menu, err := menuService.SelectMenuById(menuId)
 	if err != nil {
 		fmt.Printf("MenuController.GetInfo: 查询菜单详情失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "查询菜单详情失败")
@@ -84,6 +88,7 @@ func (c *MenuController) GetInfo(ctx *gin.Context) {
 	}
 
 	fmt.Printf("MenuController.GetInfo: 查询菜单详情成功, MenuID=%d\n", menuId)
+	utils.SetVersionETag(ctx, menu.Version)
 	response.SuccessWithData(ctx, menu)
 }
 
@@ -97,6 +102,7 @@ func (c *MenuController) GetInfo(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /system/menu/treeselect [get]
 func (c *MenuController) TreeSelect(ctx *gin.Context) {
+	menuService := c.menuService.WithContext(ctx.Request.Context())
 	fmt.Printf("MenuController.TreeSelect: 获取菜单下拉树列表\n")
 
 	// 获取当前登录用户
@@ -113,7 +119,7 @@ func (c *MenuController) TreeSelect(ctx *gin.Context) {
 	}
 
 	// 查询菜单列表
-	menus, err := c.menuService.SelectMenuList(menu, currentUser.UserID)
+	menus, err := menuService.SelectMenuList(menu, currentUser.UserID)
 	if err != nil {
 		fmt.Printf("MenuController.TreeSelect: 查询菜单列表失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "查询菜单列表失败")
@@ -121,7 +127,7 @@ func (c *MenuController) TreeSelect(ctx *gin.Context) {
 	}
 
 	// 构建菜单树选择结构
-	treeSelect := c.menuService.BuildMenuTreeSelect(menus)
+	treeSelect := menuService.BuildMenuTreeSelect(menus)
 
 	fmt.Printf("MenuController.TreeSelect: 获取菜单下拉树列表成功, 数量=%d\n", len(treeSelect))
 	response.SuccessWithData(ctx, treeSelect)
@@ -138,6 +144,7 @@ func (c *MenuController) TreeSelect(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /system/menu/roleMenuTreeselect/{roleId} [get]
 func (c *MenuController) RoleMenuTreeSelect(ctx *gin.Context) {
+	menuService := c.menuService.WithContext(ctx.Request.Context())
 	fmt.Printf("MenuController.RoleMenuTreeSelect: 加载对应角色菜单列表树\n")
 
 	roleIdStr := ctx.Param("roleId")
@@ -152,7 +159,7 @@ func (c *MenuController) RoleMenuTreeSelect(ctx *gin.Context) {
 	currentUser := loginUser.(*model.LoginUser)
 
 	// 查询所有菜单列表 - 对应Java后端的menuService.selectMenuList(getUserId())
-	menus, err := c.menuService.SelectMenuListByUserId(currentUser.UserID)
+	menus, err := menuService.SelectMenuListByUserId(currentUser.UserID)
 	if err != nil {
 		fmt.Printf("MenuController.RoleMenuTreeSelect: 查询菜单列表失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "查询菜单列表失败")
@@ -160,10 +167,10 @@ func (c *MenuController) RoleMenuTreeSelect(ctx *gin.Context) {
 	}
 
 	// 构建菜单树选择结构
-	treeSelect := c.menuService.BuildMenuTreeSelect(menus)
+	treeSelect := menuService.BuildMenuTreeSelect(menus)
 
 	// 获取角色已选中的菜单ID列表
-	checkedKeys, err := c.menuService.SelectMenuListByRoleId(roleId)
+	checkedKeys, err := menuService.SelectMenuListByRoleId(roleId)
 	if err != nil {
 		fmt.Printf("MenuController.RoleMenuTreeSelect: 查询角色菜单失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "查询角色菜单失败")
@@ -195,6 +202,7 @@ func (c *MenuController) RoleMenuTreeSelect(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /system/menu [post]
 func (c *MenuController) Add(ctx *gin.Context) {
+	menuService := c.menuService.WithContext(ctx.Request.Context())
 	fmt.Printf("MenuController.Add: 新增菜单\n")
 
 	var menu model.SysMenu
@@ -205,7 +213,7 @@ func (c *MenuController) Add(ctx *gin.Context) {
 	}
 
 	// 校验菜单名称唯一性
-	unique, err := c.menuService.CheckMenuNameUnique(&menu)
+	unique, err := menuService.CheckMenuNameUnique(&menu)
 	if err != nil {
 		fmt.Printf("MenuController.Add: 校验菜单名称失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "校验菜单名称失败")
@@ -228,7 +236,7 @@ func (c *MenuController) Add(ctx *gin.Context) {
 	menu.CreateBy = currentUser.User.UserName
 
 	// 新增菜单
-	if err := c.menuService.InsertMenu(&menu); err != nil {
+	if err := menuService.InsertMenu(&menu); err != nil {
 		fmt.Printf("MenuController.Add: 新增菜单失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "新增菜单失败")
 		return
@@ -249,6 +257,7 @@ func (c *MenuController) Add(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /system/menu [put]
 func (c *MenuController) Edit(ctx *gin.Context) {
+	menuService := c.menuService.WithContext(ctx.Request.Context())
 	fmt.Printf("MenuController.Edit: 修改菜单\n")
 
 	var menu model.SysMenu
@@ -258,8 +267,13 @@ func (c *MenuController) Edit(ctx *gin.Context) {
 		return
 	}
 
+	// 乐观锁版本号：请求体未传version时取If-Match请求头，都没有时拒绝修改
+	if !utils.RequireVersion(ctx, &menu.Version) {
+		return
+	}
+
 	// 校验菜单名称唯一性
-	unique, err := c.menuService.CheckMenuNameUnique(&menu)
+	unique, err := menuService.CheckMenuNameUnique(&menu)
 	if err != nil {
 		fmt.Printf("MenuController.Edit: 校验菜单名称失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "校验菜单名称失败")
@@ -288,8 +302,12 @@ func (c *MenuController) Edit(ctx *gin.Context) {
 	menu.UpdateBy = currentUser.User.UserName
 
 	// 修改菜单
-	if err := c.menuService.UpdateMenu(&menu); err != nil {
+	if err := menuService.UpdateMenu(&menu); err != nil {
 		fmt.Printf("MenuController.Edit: 修改菜单失败: %v\n", err)
+		if errors.Is(err, systemService.ErrVersionConflict) {
+			response.Conflict(ctx, systemService.ErrVersionConflict.Error())
+			return
+		}
 		response.ErrorWithMessage(ctx, "修改菜单失败")
 		return
 	}
@@ -309,6 +327,7 @@ func (c *MenuController) Edit(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /system/menu/{menuId} [delete]
 func (c *MenuController) Remove(ctx *gin.Context) {
+	menuService := c.menuService.WithContext(ctx.Request.Context())
 	fmt.Printf("MenuController.Remove: 删除菜单\n")
 
 	menuIdStr := ctx.Param("menuId")
@@ -319,7 +338,7 @@ func (c *MenuController) Remove(ctx *gin.Context) {
 	}
 
 	// 检查是否存在子菜单
-	hasChild, err := c.menuService.HasChildByMenuId(menuId)
+	hasChild, err := menuService.HasChildByMenuId(menuId)
 	if err != nil {
 		fmt.Printf("MenuController.Remove: 检查子菜单失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "检查子菜单失败")
@@ -331,7 +350,7 @@ func (c *MenuController) Remove(ctx *gin.Context) {
 	}
 
 	// 检查菜单是否已分配给角色
-	assigned, err := c.menuService.CheckMenuExistRole(menuId)
+	assigned, err := menuService.CheckMenuExistRole(menuId)
 	if err != nil {
 		fmt.Printf("MenuController.Remove: 检查菜单角色分配失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "检查菜单角色分配失败")
@@ -343,7 +362,7 @@ func (c *MenuController) Remove(ctx *gin.Context) {
 	}
 
 	// 删除菜单
-	if err := c.menuService.DeleteMenuById(menuId); err != nil {
+	if err := menuService.DeleteMenuById(menuId); err != nil {
 		fmt.Printf("MenuController.Remove: 删除菜单失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "删除菜单失败")
 		return
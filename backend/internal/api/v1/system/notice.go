@@ -46,6 +46,7 @@ func NewNoticeController() *NoticeController {
 // @Success 200 {object} response.TableDataInfo
 // @Router /system/notice/list [get]
 func (c *NoticeController) List(ctx *gin.Context) {
+	noticeService := c.noticeService.WithContext(ctx.Request.Context())
 	fmt.Printf("NoticeController.List: 获取通知公告列表\n")
 
 	// 权限验证：检查用户是否有查询权限 对应Java后端的@PreAuthorize("@ss.hasPermi('system:notice:list')")
@@ -108,20 +109,19 @@ func (c *NoticeController) List(ctx *gin.Context) {
 		// 非管理员用户的数据权限控制
 		// 1. 只能查看自己创建的公告
 		// 2. 或者查看状态为正常的公告
-		params.DataScope = "user"
 		params.CurrentUserId = currentUser.User.UserID
 		params.CurrentUserName = currentUser.User.UserName
 	}
 
 	// 查询公告列表
-	notices, err := c.noticeService.SelectNoticeList(&params)
+	notices, err := noticeService.SelectNoticeList(&params)
 	if err != nil {
 		response.ErrorWithMessage(ctx, "查询公告列表失败: "+err.Error())
 		return
 	}
 
 	// 查询总数
-	total, err := c.noticeService.CountNoticeList(&params)
+	total, err := noticeService.CountNoticeList(&params)
 	if err != nil {
 		response.ErrorWithMessage(ctx, "查询公告总数失败: "+err.Error())
 		return
@@ -130,8 +130,8 @@ func (c *NoticeController) List(ctx *gin.Context) {
 	// 数据后处理：为前端提供额外的显示信息
 	for i := range notices {
 		// 添加类型和状态的文本描述
-		notices[i].NoticeTypeText = c.noticeService.GetNoticeTypeText(notices[i].NoticeType)
-		notices[i].StatusText = c.noticeService.GetNoticeStatusText(notices[i].Status)
+		notices[i].NoticeTypeText = noticeService.GetNoticeTypeText(notices[i].NoticeType)
+		notices[i].StatusText = noticeService.GetNoticeStatusText(notices[i].Status)
 
 		// 安全处理：截断过长的内容用于列表显示
 		if len(notices[i].NoticeContent) > 100 {
@@ -156,6 +156,7 @@ func (c *NoticeController) List(ctx *gin.Context) {
 // @Success 200 {object} response.Response{data=model.SysNotice}
 // @Router /system/notice/{noticeId} [get]
 func (c *NoticeController) GetInfo(ctx *gin.Context) {
+	noticeService := c.noticeService.WithContext(ctx.Request.Context())
 	noticeIdStr := ctx.Param("noticeId")
 	fmt.Printf("NoticeController.GetInfo: 获取公告详情, NoticeId=%s\n", noticeIdStr)
 
@@ -188,7 +189,7 @@ func (c *NoticeController) GetInfo(ctx *gin.Context) {
 	}
 
 	// 查询公告详情
-	notice, err := c.noticeService.SelectNoticeById(noticeId)
+	notice, err := noticeService.SelectNoticeById(noticeId)
 	if err != nil {
 		response.ErrorWithMessage(ctx, "查询公告详情失败: "+err.Error())
 		return
@@ -209,8 +210,8 @@ func (c *NoticeController) GetInfo(ctx *gin.Context) {
 	}
 
 	// 添加扩展信息
-	notice.NoticeTypeText = c.noticeService.GetNoticeTypeText(notice.NoticeType)
-	notice.StatusText = c.noticeService.GetNoticeStatusText(notice.Status)
+	notice.NoticeTypeText = noticeService.GetNoticeTypeText(notice.NoticeType)
+	notice.StatusText = noticeService.GetNoticeStatusText(notice.Status)
 
 	response.SuccessWithData(ctx, notice)
 }
@@ -225,6 +226,7 @@ func (c *NoticeController) GetInfo(ctx *gin.Context) {
 // @Success 200 {object} response.Response
 // @Router /system/notice [post]
 func (c *NoticeController) Add(ctx *gin.Context) {
+	noticeService := c.noticeService.WithContext(ctx.Request.Context())
 	fmt.Printf("NoticeController.Add: 新增通知公告\n")
 
 	// 权限验证：检查用户是否有新增权限 对应Java后端的@PreAuthorize("@ss.hasPermi('system:notice:add')")
@@ -276,7 +278,7 @@ func (c *NoticeController) Add(ctx *gin.Context) {
 	}
 
 	// 新增公告
-	err := c.noticeService.InsertNotice(&notice)
+	err := noticeService.InsertNotice(&notice)
 	if err != nil {
 		response.ErrorWithMessage(ctx, "新增公告失败: "+err.Error())
 
@@ -301,6 +303,7 @@ func (c *NoticeController) Add(ctx *gin.Context) {
 // @Success 200 {object} response.Response
 // @Router /system/notice [put]
 func (c *NoticeController) Edit(ctx *gin.Context) {
+	noticeService := c.noticeService.WithContext(ctx.Request.Context())
 	fmt.Printf("NoticeController.Edit: 修改通知公告\n")
 
 	// 权限验证：检查用户是否有修改权限 对应Java后端的@PreAuthorize("@ss.hasPermi('system:notice:edit')")
@@ -338,7 +341,7 @@ func (c *NoticeController) Edit(ctx *gin.Context) {
 	}
 
 	// 检查公告是否存在
-	existingNotice, err := c.noticeService.SelectNoticeById(notice.NoticeID)
+	existingNotice, err := noticeService.SelectNoticeById(notice.NoticeID)
 	if err != nil {
 		response.ErrorWithMessage(ctx, "查询公告失败: "+err.Error())
 		return
@@ -369,7 +372,7 @@ func (c *NoticeController) Edit(ctx *gin.Context) {
 	}
 
 	// 修改公告
-	err = c.noticeService.UpdateNotice(&notice)
+	err = noticeService.UpdateNotice(&notice)
 	if err != nil {
 		response.ErrorWithMessage(ctx, "修改公告失败: "+err.Error())
 
@@ -394,6 +397,7 @@ func (c *NoticeController) Edit(ctx *gin.Context) {
 // @Success 200 {object} response.Response
 // @Router /system/notice/{noticeIds} [delete]
 func (c *NoticeController) Remove(ctx *gin.Context) {
+	noticeService := c.noticeService.WithContext(ctx.Request.Context())
 	noticeIdsStr := ctx.Param("noticeIds")
 	fmt.Printf("NoticeController.Remove: 删除通知公告, NoticeIds=%s\n", noticeIdsStr)
 
@@ -445,7 +449,7 @@ func (c *NoticeController) Remove(ctx *gin.Context) {
 	// 数据权限验证：非管理员只能删除自己创建的公告
 	if !currentUser.User.IsAdmin() {
 		for _, noticeId := range noticeIds {
-			notice, err := c.noticeService.SelectNoticeById(noticeId)
+			notice, err := noticeService.SelectNoticeById(noticeId)
 			if err != nil {
 				response.ErrorWithMessage(ctx, fmt.Sprintf("查询公告失败(ID:%d): %s", noticeId, err.Error()))
 				return
@@ -464,9 +468,9 @@ func (c *NoticeController) Remove(ctx *gin.Context) {
 	// 删除公告
 	var err error
 	if len(noticeIds) == 1 {
-		err = c.noticeService.DeleteNoticeById(noticeIds[0])
+		err = noticeService.DeleteNoticeById(noticeIds[0])
 	} else {
-		err = c.noticeService.DeleteNoticeByIds(noticeIds)
+		err = noticeService.DeleteNoticeByIds(noticeIds)
 	}
 
 	if err != nil {
@@ -529,9 +533,10 @@ func (c *NoticeController) recordOperLog(ctx *gin.Context, title, businessType,
 		operLog.Status = model.OperStatusSuccess // 成功
 	}
 
-	// 异步记录日志，不影响主流程
+	// 异步记录日志，不影响主流程 请求结束后不能再访问gin.Context，先取出请求的context
+	operLogService := c.operLogService.WithContext(ctx.Request.Context())
 	go func() {
-		if err := c.operLogService.InsertOperLog(operLog); err != nil {
+		if err := operLogService.InsertOperLog(operLog); err != nil {
 			fmt.Printf("记录操作日志失败: %v\n", err)
 		}
 	}()
@@ -621,6 +626,7 @@ func (c *NoticeController) containsXSSContent(content string) bool {
 // @Success 200 {file} file "Excel文件"
 // @Router /system/notice/export [post]
 func (c *NoticeController) Export(ctx *gin.Context) {
+	noticeService := c.noticeService.WithContext(ctx.Request.Context())
 	fmt.Printf("NoticeController.Export: 导出通知公告数据\n")
 
 	// 权限验证 - 对应Java后端的@PreAuthorize("@ss.hasPermi('system:notice:export')")
@@ -653,7 +659,6 @@ func (c *NoticeController) Export(ctx *gin.Context) {
 
 	// 数据权限控制：根据用户角色限制查询范围
 	if !currentUser.User.IsAdmin() {
-		params.DataScope = "user"
 		params.CurrentUserId = currentUser.User.UserID
 		params.CurrentUserName = currentUser.User.UserName
 	}
@@ -661,7 +666,7 @@ func (c *NoticeController) Export(ctx *gin.Context) {
 	// 查询通知公告列表（不分页，导出所有符合条件的数据）
 	params.PageNum = 0
 	params.PageSize = 0
-	notices, err := c.noticeService.SelectNoticeList(&params)
+	notices, err := noticeService.SelectNoticeList(&params)
 	if err != nil {
 		fmt.Printf("NoticeController.Export: 查询通知公告列表失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "查询通知公告列表失败")
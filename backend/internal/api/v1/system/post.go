@@ -43,6 +43,7 @@ func NewPostController() *PostController {
 // @Success 200 {object} response.TableDataInfo
 // @Router /system/post/list [get]
 func (c *PostController) List(ctx *gin.Context) {
+	postService := c.postService.WithContext(ctx.Request.Context())
 	fmt.Printf("PostController.List: 获取岗位列表\n")
 
 	// 绑定查询参数
@@ -62,7 +63,7 @@ func (c *PostController) List(ctx *gin.Context) {
 	// 检查是否需要分页
 	if params.PageNum > 0 && params.PageSize > 0 {
 		// 分页查询
-		posts, total, err := c.postService.SelectPostListWithPage(post, params.PageNum, params.PageSize)
+		posts, total, err := postService.SelectPostListWithPage(post, params.PageNum, params.PageSize)
 		if err != nil {
 			response.ErrorWithMessage(ctx, "查询岗位列表失败: "+err.Error())
 			return
@@ -73,7 +74,7 @@ func (c *PostController) List(ctx *gin.Context) {
 		response.SendTableDataInfo(ctx, tableData)
 	} else {
 		// 不分页查询（用于下拉框等场景）
-		posts, err := c.postService.SelectPostList(post)
+		posts, err := postService.SelectPostList(post)
 		if err != nil {
 			response.ErrorWithMessage(ctx, "查询岗位列表失败: "+err.Error())
 			return
@@ -95,6 +96,7 @@ func (c *PostController) List(ctx *gin.Context) {
 // @Success 200 {object} response.Response{data=model.SysPost}
 // @Router /system/post/{postId} [get]
 func (c *PostController) GetInfo(ctx *gin.Context) {
+	postService := c.postService.WithContext(ctx.Request.Context())
 	postIdStr := ctx.Param("postId")
 	fmt.Printf("PostController.GetInfo: 获取岗位详情, PostId=%s\n", postIdStr)
 
@@ -104,7 +106,7 @@ func (c *PostController) GetInfo(ctx *gin.Context) {
 		return
 	}
 
-	post, err := c.postService.SelectPostById(postId)
+	post, err := postService.SelectPostById(postId)
 	if err != nil {
 		response.ErrorWithMessage(ctx, "查询岗位详情失败: "+err.Error())
 		return
@@ -128,6 +130,7 @@ func (c *PostController) GetInfo(ctx *gin.Context) {
 // @Success 200 {object} response.Response
 // @Router /system/post [post]
 func (c *PostController) Add(ctx *gin.Context) {
+	postService := c.postService.WithContext(ctx.Request.Context())
 	fmt.Printf("PostController.Add: 新增岗位\n")
 
 	var post model.SysPost
@@ -145,7 +148,7 @@ func (c *PostController) Add(ctx *gin.Context) {
 	post.CreateBy = username.(string)
 
 	// 新增岗位
-	err := c.postService.InsertPost(&post)
+	err := postService.InsertPost(&post)
 	if err != nil {
 		response.ErrorWithMessage(ctx, err.Error())
 
@@ -170,6 +173,7 @@ func (c *PostController) Add(ctx *gin.Context) {
 // @Success 200 {object} response.Response
 // @Router /system/post [put]
 func (c *PostController) Edit(ctx *gin.Context) {
+	postService := c.postService.WithContext(ctx.Request.Context())
 	fmt.Printf("PostController.Edit: 修改岗位\n")
 
 	var post model.SysPost
@@ -187,7 +191,7 @@ func (c *PostController) Edit(ctx *gin.Context) {
 	post.UpdateBy = username.(string)
 
 	// 修改岗位
-	err := c.postService.UpdatePost(&post)
+	err := postService.UpdatePost(&post)
 	if err != nil {
 		response.ErrorWithMessage(ctx, err.Error())
 
@@ -212,6 +216,7 @@ func (c *PostController) Edit(ctx *gin.Context) {
 // @Success 200 {object} response.Response
 // @Router /system/post/{postIds} [delete]
 func (c *PostController) Remove(ctx *gin.Context) {
+	postService := c.postService.WithContext(ctx.Request.Context())
 	postIdsStr := ctx.Param("postIds")
 	fmt.Printf("PostController.Remove: 删除岗位, PostIds=%s\n", postIdsStr)
 
@@ -233,7 +238,7 @@ func (c *PostController) Remove(ctx *gin.Context) {
 	}
 
 	// 删除岗位
-	err := c.postService.DeletePostByIds(postIds)
+	err := postService.DeletePostByIds(postIds)
 	if err != nil {
 		response.ErrorWithMessage(ctx, err.Error())
 
@@ -257,9 +262,10 @@ func (c *PostController) Remove(ctx *gin.Context) {
 // @Success 200 {object} response.Response{data=[]model.SysPost}
 // @Router /system/post/optionselect [get]
 func (c *PostController) OptionSelect(ctx *gin.Context) {
+	postService := c.postService.WithContext(ctx.Request.Context())
 	fmt.Printf("PostController.OptionSelect: 获取岗位选择框列表\n")
 
-	posts, err := c.postService.GetPostOptionSelect()
+	posts, err := postService.GetPostOptionSelect()
 	if err != nil {
 		response.ErrorWithMessage(ctx, "查询岗位选择框列表失败: "+err.Error())
 		return
@@ -280,6 +286,7 @@ func (c *PostController) OptionSelect(ctx *gin.Context) {
 // @Success 200 {file} file "Excel文件"
 // @Router /system/post/export [post]
 func (c *PostController) Export(ctx *gin.Context) {
+	postService := c.postService.WithContext(ctx.Request.Context())
 	// 权限验证 - 对应Java后端的@PreAuthorize("@ss.hasPermi('system:post:export')")
 	loginUser, exists := ctx.Get("loginUser")
 	if !exists {
@@ -327,7 +334,7 @@ func (c *PostController) Export(ctx *gin.Context) {
 		post.PostCode, post.PostName, post.Status, queryParams.BeginTime, queryParams.EndTime)
 
 	// 查询所有符合条件的岗位
-	posts, err := c.postService.SelectPostList(post)
+	posts, err := postService.SelectPostList(post)
 	if err != nil {
 		fmt.Printf("PostController.Export: 查询岗位数据失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "查询失败: "+err.Error())
@@ -409,9 +416,10 @@ func (c *PostController) recordOperLog(ctx *gin.Context, title, businessType, co
 		operLog.Status = model.OperStatusSuccess // 成功
 	}
 
-	// 异步记录日志，不影响主流程
+	// 异步记录日志，不影响主流程 请求结束后不能再访问gin.Context，先取出请求的context
+	operLogService := c.operLogService.WithContext(ctx.Request.Context())
 	go func() {
-		if err := c.operLogService.InsertOperLog(operLog); err != nil {
+		if err := operLogService.InsertOperLog(operLog); err != nil {
 			fmt.Printf("记录操作日志失败: %v\n", err)
 		}
 	}()
@@ -36,6 +36,7 @@ func NewProfileController() *ProfileController {
 // @Success 200 {object} response.Response{data=model.SysUser}
 // @Router /system/user/profile [get]
 func (c *ProfileController) Profile(ctx *gin.Context) {
+	userService := c.userService.WithContext(ctx.Request.Context())
 	fmt.Printf("ProfileController.Profile: 获取个人信息\n")
 
 	// 获取当前登录用户
@@ -49,14 +50,14 @@ func (c *ProfileController) Profile(ctx *gin.Context) {
 	user := currentUser.User
 
 	// 查询用户角色组
-	roleGroup, err := c.userService.SelectUserRoleGroup(user.UserName)
+	roleGroup, err := userService.SelectUserRoleGroup(user.UserName)
 	if err != nil {
 		fmt.Printf("ProfileController.Profile: 查询用户角色组失败: %v\n", err)
 		roleGroup = ""
 	}
 
 	// 查询用户岗位组
-	postGroup, err := c.userService.SelectUserPostGroup(user.UserName)
+	postGroup, err := userService.SelectUserPostGroup(user.UserName)
 	if err != nil {
 		fmt.Printf("ProfileController.Profile: 查询用户岗位组失败: %v\n", err)
 		postGroup = ""
@@ -82,6 +83,7 @@ func (c *ProfileController) Profile(ctx *gin.Context) {
 // @Success 200 {object} response.Response
 // @Router /system/user/profile [put]
 func (c *ProfileController) UpdateProfile(ctx *gin.Context) {
+	userService := c.userService.WithContext(ctx.Request.Context())
 	fmt.Printf("ProfileController.UpdateProfile: 修改个人信息\n")
 
 	// 获取当前登录用户
@@ -109,7 +111,7 @@ func (c *ProfileController) UpdateProfile(ctx *gin.Context) {
 
 	// 检查手机号唯一性
 	if user.Phonenumber != "" {
-		isUnique := c.userService.CheckPhoneUnique(user)
+		isUnique := userService.CheckPhoneUnique(user)
 		if !isUnique {
 			response.ErrorWithMessage(ctx, fmt.Sprintf("修改用户'%s'失败，手机号码已存在", user.UserName))
 			c.recordOperLog(ctx, "个人信息", "修改", "修改个人信息失败: 手机号码已存在", false)
@@ -118,14 +120,14 @@ func (c *ProfileController) UpdateProfile(ctx *gin.Context) {
 	}
 
 	// 检查邮箱唯一性
-	if user.Email != "" && !c.userService.CheckEmailUnique(user) {
+	if user.Email != "" && !userService.CheckEmailUnique(user) {
 		response.ErrorWithMessage(ctx, fmt.Sprintf("修改用户'%s'失败，邮箱账号已存在", user.UserName))
 		c.recordOperLog(ctx, "个人信息", "修改", "修改个人信息失败: 邮箱账号已存在", false)
 		return
 	}
 
 	// 更新用户信息
-	err := c.userService.UpdateUserProfile(user)
+	err := userService.UpdateUserProfile(user)
 	if err != nil {
 		response.ErrorWithMessage(ctx, "修改个人信息异常，请联系管理员: "+err.Error())
 		c.recordOperLog(ctx, "个人信息", "修改", "修改个人信息失败: "+err.Error(), false)
@@ -151,6 +153,7 @@ func (c *ProfileController) UpdateProfile(ctx *gin.Context) {
 // @Success 200 {object} response.Response
 // @Router /system/user/profile/updatePwd [put]
 func (c *ProfileController) UpdatePwd(ctx *gin.Context) {
+	userService := c.userService.WithContext(ctx.Request.Context())
 	fmt.Printf("ProfileController.UpdatePwd: 重置密码\n")
 
 	// 获取当前登录用户
@@ -206,7 +209,7 @@ func (c *ProfileController) UpdatePwd(ctx *gin.Context) {
 	}
 
 	// 更新密码
-	err = c.userService.ResetUserPwd(user.UserID, hashedPassword)
+	err = userService.ResetUserPwd(user.UserID, hashedPassword)
 	if err != nil {
 		response.ErrorWithMessage(ctx, "修改密码异常，请联系管理员: "+err.Error())
 		c.recordOperLog(ctx, "个人信息", "修改", "修改密码失败: "+err.Error(), false)
@@ -233,6 +236,7 @@ func (c *ProfileController) UpdatePwd(ctx *gin.Context) {
 // @Success 200 {object} response.Response{data=map[string]string}
 // @Router /system/user/profile/avatar [post]
 func (c *ProfileController) Avatar(ctx *gin.Context) {
+	userService := c.userService.WithContext(ctx.Request.Context())
 	fmt.Printf("ProfileController.Avatar: 头像上传\n")
 
 	// 获取当前登录用户
@@ -291,7 +295,7 @@ func (c *ProfileController) Avatar(ctx *gin.Context) {
 	avatarURL := "/profile/avatar/" + filename
 
 	// 更新用户头像
-	err = c.userService.UpdateUserAvatar(user.UserID, avatarURL)
+	err = userService.UpdateUserAvatar(user.UserID, avatarURL)
 	if err != nil {
 		response.ErrorWithMessage(ctx, "更新用户头像失败: "+err.Error())
 		c.recordOperLog(ctx, "用户头像", "修改", "头像上传失败: 更新用户头像失败", false)
@@ -361,9 +365,10 @@ func (c *ProfileController) recordOperLog(ctx *gin.Context, title, businessType,
 		operLog.Status = model.OperStatusSuccess // 成功
 	}
 
-	// 异步记录日志，不影响主流程
+	// 异步记录日志，不影响主流程 请求结束后不能再访问gin.Context，先取出请求的context
+	operLogService := c.operLogService.WithContext(ctx.Request.Context())
 	go func() {
-		if err := c.operLogService.InsertOperLog(operLog); err != nil {
+		if err := operLogService.InsertOperLog(operLog); err != nil {
 			fmt.Printf("记录操作日志失败: %v\n", err)
 		}
 	}()
@@ -0,0 +1,151 @@
+package system
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"wosm/internal/repository/model"
+	"wosm/internal/service/system"
+	"wosm/pkg/operlog"
+	"wosm/pkg/response"
+	"wosm/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RecycleController 回收站控制器 查看、恢复和彻底删除已删除的用户、部门和角色
+type RecycleController struct {
+	recycleService *system.RecycleService
+}
+
+// NewRecycleController 创建回收站控制器实例
+func NewRecycleController() *RecycleController {
+	return &RecycleController{
+		recycleService: system.NewRecycleService(),
+	}
+}
+
+// List 查询回收站列表
+// @Summary 查询回收站列表
+// @Description 分页查询已删除的用户、部门和角色，包含删除者和删除时间
+// @Tags 回收站
+// @Produce json
+// @Param objectType query string false "对象类型（user用户 dept部门 role角色）"
+// @Param objectName query string false "对象名称"
+// @Param deleteBy query string false "删除者"
+// @Param params[beginTime] query string false "删除开始日期"
+// @Param params[endTime] query string false "删除结束日期"
+// @Param pageNum query int false "页码"
+// @Param pageSize query int false "每页数量"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.TableDataInfo
+// @Router /system/recycle/list [get]
+func (c *RecycleController) List(ctx *gin.Context) {
+	recycleService := c.recycleService.WithContext(ctx.Request.Context())
+	pageDomain := utils.StartPage(ctx)
+
+	recycle := &model.SysRecycle{
+		ObjectType: ctx.Query("objectType"),
+		ObjectName: strings.TrimSpace(ctx.Query("objectName")),
+		DeleteBy:   strings.TrimSpace(ctx.Query("deleteBy")),
+	}
+	if recycle.ObjectType != "" && !model.IsRecycleType(recycle.ObjectType) {
+		response.ErrorWithMessage(ctx, "对象类型参数无效")
+		return
+	}
+	if beginTime := ctx.Query("params[beginTime]"); beginTime != "" {
+		if t, err := time.ParseInLocation("2006-01-02", beginTime, time.Local); err == nil {
+			recycle.BeginTime = &t
+		}
+	}
+	if endTime := ctx.Query("params[endTime]"); endTime != "" {
+		if t, err := time.ParseInLocation("2006-01-02", endTime, time.Local); err == nil {
+			recycle.EndTime = &t
+		}
+	}
+
+	recycles, total, err := recycleService.SelectRecycleList(recycle, pageDomain.PageNum, pageDomain.PageSize)
+	if err != nil {
+		fmt.Printf("RecycleController.List: 查询回收站列表失败: %v\n", err)
+		response.ErrorWithMessage(ctx, "查询回收站列表失败")
+		return
+	}
+
+	response.SendTableDataInfo(ctx, response.GetDataTable(recycles, total))
+}
+
+// Restore 恢复回收站中的数据，与现有数据冲突或上级部门已删除时返回错误
+// @Summary 恢复回收站数据
+// @Tags 回收站
+// @Produce json
+// @Param recycleIds path string true "回收站ID列表，逗号分隔"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /system/recycle/restore/{recycleIds} [put]
+func (c *RecycleController) Restore(ctx *gin.Context) {
+	recycleService := c.recycleService.WithContext(ctx.Request.Context())
+	recycleIds, ok := parseRecycleIds(ctx)
+	if !ok {
+		return
+	}
+
+	loginUser, _ := ctx.Get("loginUser")
+	currentUser := loginUser.(*model.LoginUser)
+
+	for _, recycleId := range recycleIds {
+		recycle, err := recycleService.Restore(recycleId, currentUser.User.UserName)
+		if err != nil {
+			fmt.Printf("RecycleController.Restore: 恢复失败, RecycleID=%d: %v\n", recycleId, err)
+			operlog.RecordOperLog(ctx, "回收站", "恢复", fmt.Sprintf("恢复失败: %s", err.Error()), false)
+			response.ErrorWithMessage(ctx, err.Error())
+			return
+		}
+		operlog.RecordOperLog(ctx, "回收站", "恢复", fmt.Sprintf("恢复%s'%s'", recycle.ObjectType, recycle.ObjectName), true)
+	}
+
+	response.SuccessWithMessage(ctx, "恢复成功")
+}
+
+// Remove 彻底删除回收站中的数据，删除后不能恢复
+// @Summary 彻底删除回收站数据
+// @Tags 回收站
+// @Produce json
+// @Param recycleIds path string true "回收站ID列表，逗号分隔"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /system/recycle/{recycleIds} [delete]
+func (c *RecycleController) Remove(ctx *gin.Context) {
+	recycleService := c.recycleService.WithContext(ctx.Request.Context())
+	recycleIds, ok := parseRecycleIds(ctx)
+	if !ok {
+		return
+	}
+
+	for _, recycleId := range recycleIds {
+		recycle, err := recycleService.Purge(recycleId)
+		if err != nil {
+			fmt.Printf("RecycleController.Remove: 彻底删除失败, RecycleID=%d: %v\n", recycleId, err)
+			operlog.RecordOperLog(ctx, "回收站", "删除", fmt.Sprintf("彻底删除失败: %s", err.Error()), false)
+			response.ErrorWithMessage(ctx, err.Error())
+			return
+		}
+		operlog.RecordOperLog(ctx, "回收站", "删除", fmt.Sprintf("彻底删除%s'%s'", recycle.ObjectType, recycle.ObjectName), true)
+	}
+
+	response.SuccessWithMessage(ctx, "删除成功")
+}
+
+// parseRecycleIds 解析路径中逗号分隔的回收站ID，参数错误时直接返回错误响应
+func parseRecycleIds(ctx *gin.Context) ([]int64, bool) {
+	var recycleIds []int64
+	for _, idStr := range strings.Split(ctx.Param("recycleIds"), ",") {
+		id, err := strconv.ParseInt(strings.TrimSpace(idStr), 10, 64)
+		if err != nil {
+			response.ErrorWithMessage(ctx, "回收站ID格式错误")
+			return nil, false
+		}
+		recycleIds = append(recycleIds, id)
+	}
+	return recycleIds, true
+}
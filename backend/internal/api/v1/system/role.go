@@ -1,6 +1,7 @@
 package system
 
 import (
+	"errors"
 	"fmt"
 	"net/url"
 	"strconv"
@@ -8,7 +9,6 @@ import (
 	"time"
 	"wosm/internal/repository/model"
 	systemService "wosm/internal/service/system"
-	"wosm/pkg/datascope"
 	"wosm/pkg/excel"
 	"wosm/pkg/export"
 	"wosm/pkg/operlog"
@@ -40,6 +40,7 @@ func NewRoleController() *RoleController {
 // @PreAuthorize("@ss.hasPermi('system:role:list')")
 // @Router /system/role/list [get]
 func (c *RoleController) List(ctx *gin.Context) {
+	roleService := c.roleService.WithContext(ctx.Request.Context())
 	fmt.Printf("RoleController.List: 查询角色列表\n")
 
 	// 设置请求分页数据 - 对应Java后端的startPage()
@@ -58,7 +59,7 @@ func (c *RoleController) List(ctx *gin.Context) {
 	}
 
 	// 查询角色列表 - 对应Java后端的roleService.selectRoleList(role)
-	roles, total, err := c.roleService.SelectRoleList(role, pageDomain.PageNum, pageDomain.PageSize)
+	roles, total, err := roleService.SelectRoleList(role, pageDomain.PageNum, pageDomain.PageSize)
 	if err != nil {
 		fmt.Printf("RoleController.List: 查询角色列表失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "查询失败: "+err.Error())
@@ -74,6 +75,7 @@ func (c *RoleController) List(ctx *gin.Context) {
 // GetInfo 根据角色ID获取详细信息 对应Java后端的getInfo方法
 // @Router /system/role/{roleId} [get]
 func (c *RoleController) GetInfo(ctx *gin.Context) {
+	roleService := c.roleService.WithContext(ctx.Request.Context())
 	roleIdStr := ctx.Param("roleId")
 	roleId, err := strconv.ParseInt(roleIdStr, 10, 64)
 	if err != nil {
@@ -89,14 +91,14 @@ func (c *RoleController) GetInfo(ctx *gin.Context) {
 	currentUser := loginUser.(*model.LoginUser)
 
 	// 校验数据权限
-	if err := c.roleService.CheckRoleDataScope(currentUser.User, roleId); err != nil {
+	if err := roleService.CheckRoleDataScope(currentUser.User, roleId); err != nil {
 		fmt.Printf("RoleController.GetInfo: 数据权限校验失败: %v\n", err)
 		response.ErrorWithMessage(ctx, err.Error())
 		return
 	}
 
 	// 查询角色信息
-	role, err := c.roleService.SelectRoleById(roleId)
+	role, err := roleService.SelectRoleById(roleId)
 	if err != nil {
 		fmt.Printf("RoleController.GetInfo: 查询角色详情失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "查询角色详情失败")
@@ -110,12 +112,14 @@ func (c *RoleController) GetInfo(ctx *gin.Context) {
 	}
 
 	fmt.Printf("RoleController.GetInfo: 查询角色详情成功, RoleID=%d\n", roleId)
+	utils.SetVersionETag(ctx, role.Version)
 	response.SendAjaxResult(ctx, response.AjaxSuccessWithData(role))
 }
 
 // Add 新增角色 对应Java后端的add方法
 // @Router /system/role [post]
 func (c *RoleController) Add(ctx *gin.Context) {
+	roleService := c.roleService.WithContext(ctx.Request.Context())
 	fmt.Printf("RoleController.Add: 新增角色\n")
 
 	var role model.SysRole
@@ -126,7 +130,7 @@ func (c *RoleController) Add(ctx *gin.Context) {
 	}
 
 	// 校验角色名称是否唯一
-	if unique, err := c.roleService.CheckRoleNameUnique(&role); err != nil {
+	if unique, err := roleService.CheckRoleNameUnique(&role); err != nil {
 		fmt.Printf("RoleController.Add: 校验角色名称失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "校验角色名称失败")
 		return
@@ -136,7 +140,7 @@ func (c *RoleController) Add(ctx *gin.Context) {
 	}
 
 	// 校验角色权限字符串是否唯一
-	if unique, err := c.roleService.CheckRoleKeyUnique(&role); err != nil {
+	if unique, err := roleService.CheckRoleKeyUnique(&role); err != nil {
 		fmt.Printf("RoleController.Add: 校验角色权限字符串失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "校验角色权限字符串失败")
 		return
@@ -151,7 +155,7 @@ func (c *RoleController) Add(ctx *gin.Context) {
 	role.CreateBy = currentUser.User.UserName
 
 	// 新增角色
-	if err := c.roleService.InsertRole(&role); err != nil {
+	if err := roleService.InsertRole(&role); err != nil {
 		fmt.Printf("RoleController.Add: 新增角色失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "新增角色失败")
 		return
@@ -164,6 +168,8 @@ func (c *RoleController) Add(ctx *gin.Context) {
 // Edit 修改角色 对应Java后端的edit方法
 // @Router /system/role [put]
 func (c *RoleController) Edit(ctx *gin.Context) {
+	roleService := c.roleService.WithContext(ctx.Request.Context())
+	userService := c.userService.WithContext(ctx.Request.Context())
 	fmt.Printf("RoleController.Edit: 修改角色\n")
 
 	var role model.SysRole
@@ -173,8 +179,13 @@ func (c *RoleController) Edit(ctx *gin.Context) {
 		return
 	}
 
+	// 乐观锁版本号：请求体未传version时取If-Match请求头，都没有时拒绝修改
+	if !utils.RequireVersion(ctx, &role.Version) {
+		return
+	}
+
 	// 校验角色是否允许操作
-	if err := c.roleService.CheckRoleAllowed(&role); err != nil {
+	if err := roleService.CheckRoleAllowed(&role); err != nil {
 		fmt.Printf("RoleController.Edit: 角色操作校验失败: %v\n", err)
 		response.ErrorWithMessage(ctx, err.Error())
 		return
@@ -185,14 +196,14 @@ func (c *RoleController) Edit(ctx *gin.Context) {
 	currentUser := loginUser.(*model.LoginUser)
 
 	// 校验数据权限
-	if err := c.roleService.CheckRoleDataScope(currentUser.User, role.RoleID); err != nil {
+	if err := roleService.CheckRoleDataScope(currentUser.User, role.RoleID); err != nil {
 		fmt.Printf("RoleController.Edit: 数据权限校验失败: %v\n", err)
 		response.ErrorWithMessage(ctx, err.Error())
 		return
 	}
 
 	// 校验角色名称是否唯一
-	if unique, err := c.roleService.CheckRoleNameUnique(&role); err != nil {
+	if unique, err := roleService.CheckRoleNameUnique(&role); err != nil {
 		fmt.Printf("RoleController.Edit: 校验角色名称失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "校验角色名称失败")
 		return
@@ -202,7 +213,7 @@ func (c *RoleController) Edit(ctx *gin.Context) {
 	}
 
 	// 校验角色权限字符串是否唯一
-	if unique, err := c.roleService.CheckRoleKeyUnique(&role); err != nil {
+	if unique, err := roleService.CheckRoleKeyUnique(&role); err != nil {
 		fmt.Printf("RoleController.Edit: 校验角色权限字符串失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "校验角色权限字符串失败")
 		return
@@ -215,8 +226,12 @@ func (c *RoleController) Edit(ctx *gin.Context) {
 	role.UpdateBy = currentUser.User.UserName
 
 	// 修改角色
-	if err := c.roleService.UpdateRole(&role); err != nil {
+	if err := roleService.UpdateRole(&role); err != nil {
 		fmt.Printf("RoleController.Edit: 修改角色失败: %v\n", err)
+		if errors.Is(err, systemService.ErrVersionConflict) {
+			response.Conflict(ctx, systemService.ErrVersionConflict.Error())
+			return
+		}
 		response.ErrorWithMessage(ctx, "修改角色失败")
 		return
 	}
@@ -227,12 +242,12 @@ func (c *RoleController) Edit(ctx *gin.Context) {
 		fmt.Printf("RoleController.Edit: 更新用户权限缓存, UserID=%d\n", currentUser.User.UserID)
 
 		// 重新查询用户信息
-		updatedUser, err := c.userService.SelectUserByLoginName(currentUser.User.UserName)
+		updatedUser, err := userService.SelectUserByLoginName(currentUser.User.UserName)
 		if err != nil {
 			fmt.Printf("RoleController.Edit: 查询用户信息失败: %v\n", err)
 		} else if updatedUser != nil {
 			// 创建权限服务实例
-			permissionService := systemService.NewPermissionService()
+			permissionService := systemService.NewPermissionService().WithContext(ctx.Request.Context())
 
 			// 获取更新后的权限
 			permissions, err := permissionService.GetMenuPermission(updatedUser)
@@ -257,6 +272,7 @@ func (c *RoleController) Edit(ctx *gin.Context) {
 // Remove 删除角色 对应Java后端的remove方法
 // @Router /system/role/{roleIds} [delete]
 func (c *RoleController) Remove(ctx *gin.Context) {
+	roleService := c.roleService.WithContext(ctx.Request.Context())
 	idsStr := ctx.Param("roleIds")
 	if idsStr == "" {
 		response.ErrorWithMessage(ctx, "参数错误")
@@ -283,7 +299,7 @@ func (c *RoleController) Remove(ctx *gin.Context) {
 	currentUser := loginUser.(*model.LoginUser)
 
 	// 批量删除角色
-	if err := c.roleService.DeleteRoleByIds(currentUser.User, roleIds); err != nil {
+	if err := roleService.DeleteRoleByIds(currentUser.User, roleIds); err != nil {
 		fmt.Printf("RoleController.Remove: 删除角色失败: %v\n", err)
 		response.ErrorWithMessage(ctx, err.Error())
 		return
@@ -296,6 +312,7 @@ func (c *RoleController) Remove(ctx *gin.Context) {
 // ChangeStatus 修改角色状态 对应Java后端的changeStatus方法
 // @Router /system/role/changeStatus [put]
 func (c *RoleController) ChangeStatus(ctx *gin.Context) {
+	roleService := c.roleService.WithContext(ctx.Request.Context())
 	fmt.Printf("RoleController.ChangeStatus: 修改角色状态\n")
 
 	var role model.SysRole
@@ -306,7 +323,7 @@ func (c *RoleController) ChangeStatus(ctx *gin.Context) {
 	}
 
 	// 校验角色是否允许操作
-	if err := c.roleService.CheckRoleAllowed(&role); err != nil {
+	if err := roleService.CheckRoleAllowed(&role); err != nil {
 		fmt.Printf("RoleController.ChangeStatus: 角色操作校验失败: %v\n", err)
 		response.ErrorWithMessage(ctx, err.Error())
 		return
@@ -317,7 +334,7 @@ func (c *RoleController) ChangeStatus(ctx *gin.Context) {
 	currentUser := loginUser.(*model.LoginUser)
 
 	// 校验数据权限
-	if err := c.roleService.CheckRoleDataScope(currentUser.User, role.RoleID); err != nil {
+	if err := roleService.CheckRoleDataScope(currentUser.User, role.RoleID); err != nil {
 		fmt.Printf("RoleController.ChangeStatus: 数据权限校验失败: %v\n", err)
 		response.ErrorWithMessage(ctx, err.Error())
 		return
@@ -327,8 +344,12 @@ func (c *RoleController) ChangeStatus(ctx *gin.Context) {
 	role.UpdateBy = currentUser.User.UserName
 
 	// 修改角色状态
-	if err := c.roleService.UpdateRoleStatus(&role); err != nil {
+	if err := roleService.UpdateRoleStatus(&role); err != nil {
 		fmt.Printf("RoleController.ChangeStatus: 修改角色状态失败: %v\n", err)
+		if errors.Is(err, systemService.ErrVersionConflict) {
+			response.Conflict(ctx, systemService.ErrVersionConflict.Error())
+			return
+		}
 		response.ErrorWithMessage(ctx, err.Error())
 		return
 	}
@@ -344,6 +365,7 @@ func (c *RoleController) ChangeStatus(ctx *gin.Context) {
 // DataScope 角色数据权限分配 对应Java后端的dataScope方法
 // @Router /system/role/dataScope [put]
 func (c *RoleController) DataScope(ctx *gin.Context) {
+	roleService := c.roleService.WithContext(ctx.Request.Context())
 	fmt.Printf("RoleController.DataScope: 角色数据权限分配\n")
 
 	var role model.SysRole
@@ -352,9 +374,13 @@ func (c *RoleController) DataScope(ctx *gin.Context) {
 		response.ErrorWithMessage(ctx, "参数格式错误")
 		return
 	}
+	// 乐观锁版本号：请求体未传version时取If-Match请求头，都没有时拒绝修改
+	if !utils.RequireVersion(ctx, &role.Version) {
+		return
+	}
 
 	// 校验角色是否允许操作
-	if err := c.roleService.CheckRoleAllowed(&role); err != nil {
+	if err := roleService.CheckRoleAllowed(&role); err != nil {
 		fmt.Printf("RoleController.DataScope: 角色操作校验失败: %v\n", err)
 		response.ErrorWithMessage(ctx, err.Error())
 		return
@@ -365,7 +391,7 @@ func (c *RoleController) DataScope(ctx *gin.Context) {
 	currentUser := loginUser.(*model.LoginUser)
 
 	// 校验数据权限
-	if err := c.roleService.CheckRoleDataScope(currentUser.User, role.RoleID); err != nil {
+	if err := roleService.CheckRoleDataScope(currentUser.User, role.RoleID); err != nil {
 		fmt.Printf("RoleController.DataScope: 数据权限校验失败: %v\n", err)
 		response.ErrorWithMessage(ctx, err.Error())
 		return
@@ -375,8 +401,12 @@ func (c *RoleController) DataScope(ctx *gin.Context) {
 	role.UpdateBy = currentUser.User.UserName
 
 	// 修改数据权限
-	if err := c.roleService.AuthDataScope(&role); err != nil {
+	if err := roleService.AuthDataScope(&role); err != nil {
 		fmt.Printf("RoleController.DataScope: 修改数据权限失败: %v\n", err)
+		if errors.Is(err, systemService.ErrVersionConflict) {
+			response.Conflict(ctx, systemService.ErrVersionConflict.Error())
+			return
+		}
 		response.ErrorWithMessage(ctx, "修改数据权限失败")
 		return
 	}
@@ -388,6 +418,7 @@ func (c *RoleController) DataScope(ctx *gin.Context) {
 // DeptTree 获取角色部门树列表 对应Java后端的deptTree方法
 // @Router /system/role/deptTree/{roleId} [get]
 func (c *RoleController) DeptTree(ctx *gin.Context) {
+	deptService := c.deptService.WithContext(ctx.Request.Context())
 	fmt.Printf("RoleController.DeptTree: 获取角色部门树列表, URL=%s\n", ctx.Request.URL.Path)
 
 	roleIdStr := ctx.Param("roleId")
@@ -398,7 +429,7 @@ func (c *RoleController) DeptTree(ctx *gin.Context) {
 	}
 
 	// 获取角色已选中的部门ID列表
-	checkedKeys, err := c.deptService.SelectDeptListByRoleId(roleId)
+	checkedKeys, err := deptService.SelectDeptListByRoleId(roleId)
 	if err != nil {
 		fmt.Printf("RoleController.DeptTree: 查询角色部门失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "查询角色部门失败")
@@ -406,7 +437,7 @@ func (c *RoleController) DeptTree(ctx *gin.Context) {
 	}
 
 	// 获取所有部门树
-	depts, err := c.deptService.SelectDeptTreeList(&model.SysDept{})
+	depts, err := deptService.SelectDeptTreeList(&model.SysDept{})
 	if err != nil {
 		fmt.Printf("RoleController.DeptTree: 查询部门树失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "查询部门树失败")
@@ -423,10 +454,11 @@ func (c *RoleController) DeptTree(ctx *gin.Context) {
 // OptionSelect 获取角色选择框列表 对应Java后端的optionselect方法
 // @Router /system/role/optionselect [get]
 func (c *RoleController) OptionSelect(ctx *gin.Context) {
+	roleService := c.roleService.WithContext(ctx.Request.Context())
 	fmt.Printf("RoleController.OptionSelect: 获取角色选择框列表\n")
 
 	// 查询所有角色
-	roles, err := c.roleService.SelectRoleAll()
+	roles, err := roleService.SelectRoleAll()
 	if err != nil {
 		fmt.Printf("RoleController.OptionSelect: 查询角色列表失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "查询角色列表失败")
@@ -440,6 +472,7 @@ func (c *RoleController) OptionSelect(ctx *gin.Context) {
 // AllocatedList 查询已分配用户角色列表 对应Java后端的allocatedList方法
 // @Router /system/role/authUser/allocatedList [get]
 func (c *RoleController) AllocatedList(ctx *gin.Context) {
+	userService := c.userService.WithContext(ctx.Request.Context())
 	fmt.Printf("RoleController.AllocatedList: 查询已分配用户角色列表\n")
 
 	// 获取角色ID
@@ -468,25 +501,8 @@ func (c *RoleController) AllocatedList(ctx *gin.Context) {
 	}
 	currentUser := loginUser.(*model.LoginUser)
 
-	// 应用数据权限 对应Java后端的@DataScope(deptAlias = "d", userAlias = "u")
-	params := make(map[string]interface{})
-	err := datascope.ApplyDataScope(currentUser.User, "d", "u", "system:role:list", params)
-	if err != nil {
-		fmt.Printf("RoleController.AllocatedList: 应用数据权限失败: %v\n", err)
-		response.ErrorWithMessage(ctx, "数据权限校验失败")
-		return
-	}
-
-	// 设置数据权限参数
-	if user.Params == nil {
-		user.Params = make(map[string]interface{})
-	}
-	for key, value := range params {
-		user.Params[key] = value
-	}
-
-	// 查询已分配用户角色列表 对应Java后端的userService.selectAllocatedList(user)
-	users, total, err := c.userService.SelectAllocatedList(currentUser.User, user, pageNum, pageSize)
+	// 查询已分配用户角色列表 对应Java后端的userService.selectAllocatedList(user)，由Service层应用数据权限
+	users, total, err := userService.SelectAllocatedList(currentUser.User, user, pageNum, pageSize)
 	if err != nil {
 		fmt.Printf("RoleController.AllocatedList: 查询已分配用户角色列表失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "查询失败: "+err.Error())
@@ -502,6 +518,7 @@ func (c *RoleController) AllocatedList(ctx *gin.Context) {
 // UnallocatedList 查询未分配用户角色列表 对应Java后端的unallocatedList方法
 // @Router /system/role/authUser/unallocatedList [get]
 func (c *RoleController) UnallocatedList(ctx *gin.Context) {
+	userService := c.userService.WithContext(ctx.Request.Context())
 	fmt.Printf("RoleController.UnallocatedList: 查询未分配用户角色列表\n")
 
 	// 获取角色ID
@@ -530,25 +547,8 @@ func (c *RoleController) UnallocatedList(ctx *gin.Context) {
 	}
 	currentUser := loginUser.(*model.LoginUser)
 
-	// 应用数据权限 对应Java后端的@DataScope(deptAlias = "d", userAlias = "u")
-	params := make(map[string]interface{})
-	err := datascope.ApplyDataScope(currentUser.User, "d", "u", "system:role:list", params)
-	if err != nil {
-		fmt.Printf("RoleController.UnallocatedList: 应用数据权限失败: %v\n", err)
-		response.ErrorWithMessage(ctx, "数据权限校验失败")
-		return
-	}
-
-	// 设置数据权限参数
-	if user.Params == nil {
-		user.Params = make(map[string]interface{})
-	}
-	for key, value := range params {
-		user.Params[key] = value
-	}
-
-	// 查询未分配用户角色列表 对应Java后端的userService.selectUnallocatedList(user)
-	users, total, err := c.userService.SelectUnallocatedList(currentUser.User, user, pageNum, pageSize)
+	// 查询未分配用户角色列表 对应Java后端的userService.selectUnallocatedList(user)，由Service层应用数据权限
+	users, total, err := userService.SelectUnallocatedList(currentUser.User, user, pageNum, pageSize)
 	if err != nil {
 		fmt.Printf("RoleController.UnallocatedList: 查询未分配用户角色列表失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "查询失败: "+err.Error())
@@ -564,6 +564,7 @@ func (c *RoleController) UnallocatedList(ctx *gin.Context) {
 // CancelAuthUser 取消授权用户 对应Java后端的cancelAuthUser方法
 // @Router /system/role/authUser/cancel [put]
 func (c *RoleController) CancelAuthUser(ctx *gin.Context) {
+	roleService := c.roleService.WithContext(ctx.Request.Context())
 	fmt.Printf("RoleController.CancelAuthUser: 取消授权用户\n")
 
 	// 使用临时结构体来处理字符串到int的转换
@@ -630,7 +631,7 @@ func (c *RoleController) CancelAuthUser(ctx *gin.Context) {
 	}
 
 	// 取消授权用户 对应Java后端的roleService.deleteAuthUser(userRole)
-	if err := c.roleService.DeleteAuthUser(userRole); err != nil {
+	if err := roleService.DeleteAuthUser(userRole); err != nil {
 		fmt.Printf("RoleController.CancelAuthUser: 取消授权失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "取消授权失败: "+err.Error())
 		return
@@ -643,6 +644,7 @@ func (c *RoleController) CancelAuthUser(ctx *gin.Context) {
 // CancelAuthUserAll 批量取消授权用户 对应Java后端的cancelAuthUserAll方法
 // @Router /system/role/authUser/cancelAll [put]
 func (c *RoleController) CancelAuthUserAll(ctx *gin.Context) {
+	roleService := c.roleService.WithContext(ctx.Request.Context())
 	fmt.Printf("RoleController.CancelAuthUserAll: 批量取消授权用户\n")
 
 	// 获取角色ID
@@ -672,7 +674,7 @@ func (c *RoleController) CancelAuthUserAll(ctx *gin.Context) {
 	}
 
 	// 批量取消授权
-	if err := c.roleService.DeleteAuthUsers(roleId, userIds); err != nil {
+	if err := roleService.DeleteAuthUsers(roleId, userIds); err != nil {
 		fmt.Printf("RoleController.CancelAuthUserAll: 批量取消授权失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "取消授权失败")
 		return
@@ -685,6 +687,7 @@ func (c *RoleController) CancelAuthUserAll(ctx *gin.Context) {
 // SelectAuthUserAll 批量选择授权用户 对应Java后端的selectAuthUserAll方法
 // @Router /system/role/authUser/selectAll [put]
 func (c *RoleController) SelectAuthUserAll(ctx *gin.Context) {
+	roleService := c.roleService.WithContext(ctx.Request.Context())
 	fmt.Printf("RoleController.SelectAuthUserAll: 批量选择授权用户\n")
 
 	// 获取角色ID
@@ -699,7 +702,7 @@ func (c *RoleController) SelectAuthUserAll(ctx *gin.Context) {
 	currentUser := loginUser.(*model.LoginUser)
 
 	// 校验数据权限
-	if err := c.roleService.CheckRoleDataScope(currentUser.User, roleId); err != nil {
+	if err := roleService.CheckRoleDataScope(currentUser.User, roleId); err != nil {
 		response.ErrorWithMessage(ctx, err.Error())
 		return
 	}
@@ -724,7 +727,7 @@ func (c *RoleController) SelectAuthUserAll(ctx *gin.Context) {
 	}
 
 	// 批量选择授权
-	if err := c.roleService.InsertAuthUsers(roleId, userIds); err != nil {
+	if err := roleService.InsertAuthUsers(roleId, userIds); err != nil {
 		fmt.Printf("RoleController.SelectAuthUserAll: 批量选择授权失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "授权失败")
 		return
@@ -737,6 +740,7 @@ func (c *RoleController) SelectAuthUserAll(ctx *gin.Context) {
 // Export 导出角色数据 对应Java后端的export方法
 // @Router /system/role/export [post]
 func (c *RoleController) Export(ctx *gin.Context) {
+	roleService := c.roleService.WithContext(ctx.Request.Context())
 	// 权限验证 - 对应Java后端的@PreAuthorize("@ss.hasPermi('system:role:export')")
 	loginUser, exists := ctx.Get("loginUser")
 	if !exists {
@@ -784,7 +788,7 @@ func (c *RoleController) Export(ctx *gin.Context) {
 		role.RoleName, role.Status, role.RoleKey, queryParams.BeginTime, queryParams.EndTime)
 
 	// 查询所有符合条件的角色（不分页）
-	roles, err := c.roleService.SelectRoleListAll(role)
+	roles, err := roleService.SelectRoleListAll(role)
 	if err != nil {
 		fmt.Printf("RoleController.Export: 查询角色数据失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "查询失败: "+err.Error())
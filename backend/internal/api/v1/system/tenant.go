@@ -0,0 +1,507 @@
+package system
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"wosm/internal/repository/model"
+	"wosm/internal/service/auth"
+	"wosm/internal/service/system"
+	"wosm/pkg/operlog"
+	"wosm/pkg/response"
+	"wosm/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TenantController 租户控制器 管理租户和租户套餐，只有平台租户的用户可以访问
+type TenantController struct {
+	tenantService *system.TenantService
+	authService   *auth.AuthService
+}
+
+// NewTenantController 创建租户控制器实例
+func NewTenantController() *TenantController {
+	return &TenantController{
+		tenantService: system.NewTenantService(),
+		authService:   auth.NewAuthService(),
+	}
+}
+
+// List 查询租户列表
+// @Summary 查询租户列表
+// @Tags 租户管理
+// @Produce json
+// @Param tenantName query string false "租户名称"
+// @Param domain query string false "绑定域名"
+// @Param status query string false "租户状态"
+// @Param pageNum query int false "页码"
+// @Param pageSize query int false "每页数量"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.TableDataInfo
+// @Router /system/tenant/list [get]
+func (c *TenantController) List(ctx *gin.Context) {
+	tenantService := c.tenantService.WithContext(ctx.Request.Context())
+	if !c.checkPlatformUser(ctx) {
+		return
+	}
+
+	var params model.TenantQueryParams
+	if err := ctx.ShouldBindQuery(&params); err != nil {
+		response.ErrorWithMessage(ctx, "参数绑定失败: "+err.Error())
+		return
+	}
+	pageDomain := utils.StartPage(ctx)
+	params.PageNum = pageDomain.PageNum
+	params.PageSize = pageDomain.PageSize
+
+	tenants, total, err := tenantService.SelectTenantList(&params)
+	if err != nil {
+		fmt.Printf("TenantController.List: 查询租户列表失败: %v\n", err)
+		response.ErrorWithMessage(ctx, "查询租户列表失败")
+		return
+	}
+
+	response.SendTableDataInfo(ctx, response.GetDataTable(tenants, total))
+}
+
+// GetInfo 根据租户ID获取详细信息
+// @Summary 获取租户详情
+// @Tags 租户管理
+// @Produce json
+// @Param tenantId path int true "租户ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /system/tenant/{tenantId} [get]
+func (c *TenantController) GetInfo(ctx *gin.Context) {
+	tenantService := c.tenantService.WithContext(ctx.Request.Context())
+	if !c.checkPlatformUser(ctx) {
+		return
+	}
+
+	tenantId, err := strconv.ParseInt(ctx.Param("tenantId"), 10, 64)
+	if err != nil {
+		response.ErrorWithMessage(ctx, "租户ID格式错误")
+		return
+	}
+
+	tenant, err := tenantService.SelectTenantById(tenantId)
+	if err != nil {
+		response.ErrorWithMessage(ctx, "查询租户详情失败: "+err.Error())
+		return
+	}
+	if tenant == nil {
+		response.ErrorWithMessage(ctx, "租户不存在")
+		return
+	}
+
+	response.SuccessWithData(ctx, tenant)
+}
+
+// Add 新增租户，同时创建租户的根部门、管理员角色和管理员账号
+// @Summary 新增租户
+// @Tags 租户管理
+// @Accept json
+// @Produce json
+// @Param tenant body model.SysTenant true "租户信息，adminUserName和adminPassword为租户管理员账号"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /system/tenant [post]
+func (c *TenantController) Add(ctx *gin.Context) {
+	tenantService := c.tenantService.WithContext(ctx.Request.Context())
+	if !c.checkPlatformUser(ctx) {
+		return
+	}
+
+	var tenant model.SysTenant
+	if err := ctx.ShouldBindJSON(&tenant); err != nil {
+		response.ErrorWithMessage(ctx, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	username, _ := ctx.Get("username")
+	if err := tenantService.InsertTenant(&tenant, fmt.Sprintf("%v", username)); err != nil {
+		fmt.Printf("TenantController.Add: 新增租户失败: %v\n", err)
+		operlog.RecordOperLog(ctx, "租户管理", "新增", fmt.Sprintf("新增租户失败: %s", err.Error()), false)
+		response.ErrorWithMessage(ctx, err.Error())
+		return
+	}
+
+	operlog.RecordOperLog(ctx, "租户管理", "新增", fmt.Sprintf("新增租户'%s'，管理员: %s", tenant.TenantName, tenant.AdminUserName), true)
+	response.SuccessWithMessage(ctx, "新增成功")
+}
+
+// Edit 修改租户
+// @Summary 修改租户
+// @Tags 租户管理
+// @Accept json
+// @Produce json
+// @Param tenant body model.SysTenant true "租户信息"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /system/tenant [put]
+func (c *TenantController) Edit(ctx *gin.Context) {
+	tenantService := c.tenantService.WithContext(ctx.Request.Context())
+	if !c.checkPlatformUser(ctx) {
+		return
+	}
+
+	var tenant model.SysTenant
+	if err := ctx.ShouldBindJSON(&tenant); err != nil {
+		response.ErrorWithMessage(ctx, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	username, _ := ctx.Get("username")
+	if err := tenantService.UpdateTenant(&tenant, fmt.Sprintf("%v", username)); err != nil {
+		fmt.Printf("TenantController.Edit: 修改租户失败: %v\n", err)
+		operlog.RecordOperLog(ctx, "租户管理", "修改", fmt.Sprintf("修改租户失败: %s", err.Error()), false)
+		response.ErrorWithMessage(ctx, err.Error())
+		return
+	}
+
+	operlog.RecordOperLog(ctx, "租户管理", "修改", fmt.Sprintf("修改租户'%s'", tenant.TenantName), true)
+	response.SuccessWithMessage(ctx, "修改成功")
+}
+
+// Remove 删除租户，租户的业务数据保留
+// @Summary 删除租户
+// @Tags 租户管理
+// @Produce json
+// @Param tenantIds path string true "租户ID列表，逗号分隔"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /system/tenant/{tenantIds} [delete]
+func (c *TenantController) Remove(ctx *gin.Context) {
+	tenantService := c.tenantService.WithContext(ctx.Request.Context())
+	if !c.checkPlatformUser(ctx) {
+		return
+	}
+
+	tenantIds, ok := parseIdList(ctx, "tenantIds", "租户ID格式错误")
+	if !ok {
+		return
+	}
+
+	if err := tenantService.DeleteTenantByIds(tenantIds); err != nil {
+		operlog.RecordOperLog(ctx, "租户管理", "删除", fmt.Sprintf("删除租户失败: %s", err.Error()), false)
+		response.ErrorWithMessage(ctx, err.Error())
+		return
+	}
+
+	operlog.RecordOperLog(ctx, "租户管理", "删除", fmt.Sprintf("删除租户: %v", tenantIds), true)
+	response.SuccessWithMessage(ctx, "删除成功")
+}
+
+// OptionSelect 获取可切换的租户列表
+// @Summary 获取租户选择框列表
+// @Tags 租户管理
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /system/tenant/optionselect [get]
+func (c *TenantController) OptionSelect(ctx *gin.Context) {
+	tenantService := c.tenantService.WithContext(ctx.Request.Context())
+	if !c.checkPlatformUser(ctx) {
+		return
+	}
+
+	tenants, err := tenantService.SelectTenantAll()
+	if err != nil {
+		response.ErrorWithMessage(ctx, "查询租户失败: "+err.Error())
+		return
+	}
+	response.SuccessWithData(ctx, tenants)
+}
+
+// DynamicTenant 超级管理员切换到指定租户，之后的请求按该租户查询和修改数据，直到清除切换或重新登录
+// @Summary 切换租户
+// @Tags 租户管理
+// @Produce json
+// @Param tenantId path int true "租户ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /system/tenant/dynamic/{tenantId} [get]
+func (c *TenantController) DynamicTenant(ctx *gin.Context) {
+	tenantService := c.tenantService.WithContext(ctx.Request.Context())
+	authService := c.authService.WithContext(ctx.Request.Context())
+	loginUser, ok := c.superAdmin(ctx)
+	if !ok {
+		return
+	}
+
+	tenantId, err := strconv.ParseInt(ctx.Param("tenantId"), 10, 64)
+	if err != nil {
+		response.ErrorWithMessage(ctx, "租户ID格式错误")
+		return
+	}
+	tenant, err := tenantService.SelectTenantById(tenantId)
+	if err != nil {
+		response.ErrorWithMessage(ctx, "查询租户失败: "+err.Error())
+		return
+	}
+	if tenant == nil {
+		response.ErrorWithMessage(ctx, "租户不存在")
+		return
+	}
+
+	loginUser.DynamicTenantID = 0
+	if !tenant.IsPlatform() {
+		loginUser.DynamicTenantID = tenant.TenantID
+	}
+	if err := authService.RefreshToken(loginUser); err != nil {
+		response.ErrorWithMessage(ctx, "切换租户失败: "+err.Error())
+		return
+	}
+
+	operlog.RecordOperLog(ctx, "租户管理", "其他", fmt.Sprintf("切换到租户'%s'", tenant.TenantName), true)
+	response.SuccessWithMessage(ctx, "切换成功")
+}
+
+// ClearDynamicTenant 超级管理员清除租户切换，回到平台租户
+// @Summary 清除租户切换
+// @Tags 租户管理
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /system/tenant/dynamic/clear [get]
+func (c *TenantController) ClearDynamicTenant(ctx *gin.Context) {
+	authService := c.authService.WithContext(ctx.Request.Context())
+	loginUser, ok := c.superAdmin(ctx)
+	if !ok {
+		return
+	}
+
+	loginUser.DynamicTenantID = 0
+	if err := authService.RefreshToken(loginUser); err != nil {
+		response.ErrorWithMessage(ctx, "清除租户切换失败: "+err.Error())
+		return
+	}
+	response.SuccessWithMessage(ctx, "清除成功")
+}
+
+// PackageList 查询租户套餐列表
+// @Summary 查询租户套餐列表
+// @Tags 租户套餐
+// @Produce json
+// @Param packageName query string false "套餐名称"
+// @Param status query string false "套餐状态"
+// @Param pageNum query int false "页码"
+// @Param pageSize query int false "每页数量"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.TableDataInfo
+// @Router /system/tenant/package/list [get]
+func (c *TenantController) PackageList(ctx *gin.Context) {
+	tenantService := c.tenantService.WithContext(ctx.Request.Context())
+	if !c.checkPlatformUser(ctx) {
+		return
+	}
+
+	var params model.TenantPackageQueryParams
+	if err := ctx.ShouldBindQuery(&params); err != nil {
+		response.ErrorWithMessage(ctx, "参数绑定失败: "+err.Error())
+		return
+	}
+	pageDomain := utils.StartPage(ctx)
+	params.PageNum = pageDomain.PageNum
+	params.PageSize = pageDomain.PageSize
+
+	packages, total, err := tenantService.SelectPackageList(&params)
+	if err != nil {
+		fmt.Printf("TenantController.PackageList: 查询套餐列表失败: %v\n", err)
+		response.ErrorWithMessage(ctx, "查询套餐列表失败")
+		return
+	}
+
+	response.SendTableDataInfo(ctx, response.GetDataTable(packages, total))
+}
+
+// PackageInfo 根据套餐ID获取详细信息，包含套餐的菜单
+// @Summary 获取租户套餐详情
+// @Tags 租户套餐
+// @Produce json
+// @Param packageId path int true "套餐ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /system/tenant/package/{packageId} [get]
+func (c *TenantController) PackageInfo(ctx *gin.Context) {
+	tenantService := c.tenantService.WithContext(ctx.Request.Context())
+	if !c.checkPlatformUser(ctx) {
+		return
+	}
+
+	packageId, err := strconv.ParseInt(ctx.Param("packageId"), 10, 64)
+	if err != nil {
+		response.ErrorWithMessage(ctx, "套餐ID格式错误")
+		return
+	}
+
+	pkg, err := tenantService.SelectPackageById(packageId)
+	if err != nil {
+		response.ErrorWithMessage(ctx, "查询套餐详情失败: "+err.Error())
+		return
+	}
+	if pkg == nil {
+		response.ErrorWithMessage(ctx, "套餐不存在")
+		return
+	}
+
+	response.SuccessWithData(ctx, pkg)
+}
+
+// PackageOptionSelect 获取套餐选择框列表
+// @Summary 获取租户套餐选择框列表
+// @Tags 租户套餐
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /system/tenant/package/optionselect [get]
+func (c *TenantController) PackageOptionSelect(ctx *gin.Context) {
+	tenantService := c.tenantService.WithContext(ctx.Request.Context())
+	if !c.checkPlatformUser(ctx) {
+		return
+	}
+
+	packages, err := tenantService.SelectPackageAll()
+	if err != nil {
+		response.ErrorWithMessage(ctx, "查询套餐失败: "+err.Error())
+		return
+	}
+	response.SuccessWithData(ctx, packages)
+}
+
+// PackageAdd 新增租户套餐
+// @Summary 新增租户套餐
+// @Tags 租户套餐
+// @Accept json
+// @Produce json
+// @Param package body model.SysTenantPackage true "套餐信息"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /system/tenant/package [post]
+func (c *TenantController) PackageAdd(ctx *gin.Context) {
+	tenantService := c.tenantService.WithContext(ctx.Request.Context())
+	if !c.checkPlatformUser(ctx) {
+		return
+	}
+
+	var pkg model.SysTenantPackage
+	if err := ctx.ShouldBindJSON(&pkg); err != nil {
+		response.ErrorWithMessage(ctx, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	username, _ := ctx.Get("username")
+	if err := tenantService.InsertPackage(&pkg, fmt.Sprintf("%v", username)); err != nil {
+		operlog.RecordOperLog(ctx, "租户套餐", "新增", fmt.Sprintf("新增套餐失败: %s", err.Error()), false)
+		response.ErrorWithMessage(ctx, err.Error())
+		return
+	}
+
+	operlog.RecordOperLog(ctx, "租户套餐", "新增", fmt.Sprintf("新增套餐'%s'", pkg.PackageName), true)
+	response.SuccessWithMessage(ctx, "新增成功")
+}
+
+// PackageEdit 修改租户套餐，使用该套餐的租户的角色菜单同步修改
+// @Summary 修改租户套餐
+// @Tags 租户套餐
+// @Accept json
+// @Produce json
+// @Param package body model.SysTenantPackage true "套餐信息"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /system/tenant/package [put]
+func (c *TenantController) PackageEdit(ctx *gin.Context) {
+	tenantService := c.tenantService.WithContext(ctx.Request.Context())
+	if !c.checkPlatformUser(ctx) {
+		return
+	}
+
+	var pkg model.SysTenantPackage
+	if err := ctx.ShouldBindJSON(&pkg); err != nil {
+		response.ErrorWithMessage(ctx, "参数绑定失败: "+err.Error())
+		return
+	}
+
+	username, _ := ctx.Get("username")
+	if err := tenantService.UpdatePackage(&pkg, fmt.Sprintf("%v", username)); err != nil {
+		operlog.RecordOperLog(ctx, "租户套餐", "修改", fmt.Sprintf("修改套餐失败: %s", err.Error()), false)
+		response.ErrorWithMessage(ctx, err.Error())
+		return
+	}
+
+	operlog.RecordOperLog(ctx, "租户套餐", "修改", fmt.Sprintf("修改套餐'%s'", pkg.PackageName), true)
+	response.SuccessWithMessage(ctx, "修改成功")
+}
+
+// PackageRemove 删除租户套餐，有租户使用的套餐不能删除
+// @Summary 删除租户套餐
+// @Tags 租户套餐
+// @Produce json
+// @Param packageIds path string true "套餐ID列表，逗号分隔"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /system/tenant/package/{packageIds} [delete]
+func (c *TenantController) PackageRemove(ctx *gin.Context) {
+	tenantService := c.tenantService.WithContext(ctx.Request.Context())
+	if !c.checkPlatformUser(ctx) {
+		return
+	}
+
+	packageIds, ok := parseIdList(ctx, "packageIds", "套餐ID格式错误")
+	if !ok {
+		return
+	}
+
+	if err := tenantService.DeletePackageByIds(packageIds); err != nil {
+		operlog.RecordOperLog(ctx, "租户套餐", "删除", fmt.Sprintf("删除套餐失败: %s", err.Error()), false)
+		response.ErrorWithMessage(ctx, err.Error())
+		return
+	}
+
+	operlog.RecordOperLog(ctx, "租户套餐", "删除", fmt.Sprintf("删除套餐: %v", packageIds), true)
+	response.SuccessWithMessage(ctx, "删除成功")
+}
+
+// checkPlatformUser 只有平台租户的用户可以管理租户，其他租户的用户直接返回错误响应
+func (c *TenantController) checkPlatformUser(ctx *gin.Context) bool {
+	loginUser, exists := ctx.Get("loginUser")
+	if !exists {
+		response.ErrorWithMessage(ctx, "获取用户信息失败")
+		return false
+	}
+	if user := loginUser.(*model.LoginUser); user.TenantID > model.DefaultTenantID {
+		response.ErrorWithMessage(ctx, "只有平台租户可以管理租户")
+		return false
+	}
+	return true
+}
+
+// superAdmin 只有超级管理员可以切换租户，其他用户直接返回错误响应
+func (c *TenantController) superAdmin(ctx *gin.Context) (*model.LoginUser, bool) {
+	loginUser, exists := ctx.Get("loginUser")
+	if !exists {
+		response.ErrorWithMessage(ctx, "获取用户信息失败")
+		return nil, false
+	}
+	user := loginUser.(*model.LoginUser)
+	if user.User == nil || !user.User.IsAdmin() || user.TenantID > model.DefaultTenantID {
+		response.ErrorWithMessage(ctx, "只有超级管理员可以切换租户")
+		return nil, false
+	}
+	return user, true
+}
+
+// parseIdList 解析路径中逗号分隔的ID，参数错误时直接返回错误响应
+func parseIdList(ctx *gin.Context, param, message string) ([]int64, bool) {
+	var ids []int64
+	for _, idStr := range strings.Split(ctx.Param(param), ",") {
+		id, err := strconv.ParseInt(strings.TrimSpace(idStr), 10, 64)
+		if err != nil {
+			response.ErrorWithMessage(ctx, message)
+			return nil, false
+		}
+		ids = append(ids, id)
+	}
+	return ids, true
+}
@@ -2,6 +2,7 @@ package system
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/url"
@@ -15,6 +16,7 @@ import (
 	"wosm/pkg/export"
 	"wosm/pkg/operlog"
 	"wosm/pkg/response"
+	pkgUtils "wosm/pkg/utils"
 
 	"github.com/gin-gonic/gin"
 )
@@ -56,6 +58,7 @@ func NewUserController() *UserController {
 // @Success 200 {object} response.PageResult
 // @Router /system/user/list [get]
 func (c *UserController) List(ctx *gin.Context) {
+	userService := c.userService.WithContext(ctx.Request.Context())
 	// 获取查询参数
 	pageNum, _ := strconv.Atoi(ctx.DefaultQuery("pageNum", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("pageSize", "10"))
@@ -81,7 +84,7 @@ func (c *UserController) List(ctx *gin.Context) {
 	currentUser := loginUser.(*model.LoginUser)
 
 	// 使用支持数据权限的查询方法 对应Java后端的@DataScope注解
-	users, total, err := c.userService.SelectUserListWithDataScope(currentUser.User, user, pageNum, pageSize)
+	users, total, err := userService.SelectUserListWithDataScope(currentUser.User, user, pageNum, pageSize)
 	if err != nil {
 		response.ErrorWithMessage(ctx, "查询失败: "+err.Error())
 		return
@@ -114,6 +117,7 @@ func (c *UserController) List(ctx *gin.Context) {
 // @Success 200 {object} response.TableDataInfo
 // @Router /system/user/authRole/allocatedList [get]
 func (c *UserController) AllocatedList(ctx *gin.Context) {
+	userService := c.userService.WithContext(ctx.Request.Context())
 	fmt.Printf("UserController.AllocatedList: 查询已分配用户角色列表\n")
 
 	// 解析分页参数
@@ -150,7 +154,7 @@ func (c *UserController) AllocatedList(ctx *gin.Context) {
 	currentUser := loginUser.(*model.LoginUser)
 
 	// 查询已分配用户列表 对应Java后端的userService.selectAllocatedList(user)
-	users, total, err := c.userService.SelectAllocatedList(currentUser.User, user, pageNum, pageSize)
+	users, total, err := userService.SelectAllocatedList(currentUser.User, user, pageNum, pageSize)
 	if err != nil {
 		response.ErrorWithMessage(ctx, "查询失败: "+err.Error())
 		return
@@ -176,6 +180,7 @@ func (c *UserController) AllocatedList(ctx *gin.Context) {
 // @Success 200 {object} response.TableDataInfo
 // @Router /system/user/authRole/unallocatedList [get]
 func (c *UserController) UnallocatedList(ctx *gin.Context) {
+	userService := c.userService.WithContext(ctx.Request.Context())
 	fmt.Printf("UserController.UnallocatedList: 查询未分配用户角色列表\n")
 
 	// 解析分页参数
@@ -212,7 +217,7 @@ func (c *UserController) UnallocatedList(ctx *gin.Context) {
 	currentUser := loginUser.(*model.LoginUser)
 
 	// 查询未分配用户列表 对应Java后端的userService.selectUnallocatedList(user)
-	users, total, err := c.userService.SelectUnallocatedList(currentUser.User, user, pageNum, pageSize)
+	users, total, err := userService.SelectUnallocatedList(currentUser.User, user, pageNum, pageSize)
 	if err != nil {
 		response.ErrorWithMessage(ctx, "查询失败: "+err.Error())
 		return
@@ -236,6 +241,9 @@ func (c *UserController) UnallocatedList(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /system/user/{userId} [get]
 func (c *UserController) GetInfo(ctx *gin.Context) {
+	userService := c.userService.WithContext(ctx.Request.Context())
+	postService := c.postService.WithContext(ctx.Request.Context())
+	roleService := c.roleService.WithContext(ctx.Request.Context())
 	fmt.Printf("UserController.GetInfo: 获取用户信息或初始化数据\n")
 
 	userIdStr := ctx.Param("userId")
@@ -265,14 +273,14 @@ func (c *UserController) GetInfo(ctx *gin.Context) {
 		currentUser := loginUser.(*model.LoginUser)
 
 		// 校验数据权限 对应Java后端的userService.checkUserDataScope(userId)
-		if err := c.userService.CheckUserDataScope(userId, currentUser.User); err != nil {
+		if err := userService.CheckUserDataScope(userId, currentUser.User); err != nil {
 			fmt.Printf("UserController.GetInfo: 数据权限校验失败: %v\n", err)
 			response.SendAjaxResult(ctx, response.AjaxErrorWithMessage(err.Error()))
 			return
 		}
 
 		// 获取用户信息 对应Java后端的userService.selectUserById(userId)
-		user, err := c.userService.SelectUserById(userId)
+		user, err := userService.SelectUserById(userId)
 		if err != nil {
 			fmt.Printf("UserController.GetInfo: 查询用户信息失败: %v\n", err)
 			response.SendAjaxResult(ctx, response.AjaxErrorWithMessage("查询失败"))
@@ -287,9 +295,10 @@ func (c *UserController) GetInfo(ctx *gin.Context) {
 
 		// 对应Java后端的ajax.put(AjaxResult.DATA_TAG, sysUser)
 		result["data"] = user
+		pkgUtils.SetVersionETag(ctx, user.Version)
 
 		// 获取用户岗位ID列表 对应Java后端的ajax.put("postIds", postService.selectPostListByUserId(userId))
-		postIds, err := c.postService.SelectPostListByUserId(userId)
+		postIds, err := postService.SelectPostListByUserId(userId)
 		if err != nil {
 			fmt.Printf("UserController.GetInfo: 查询用户岗位失败: %v\n", err)
 			result["postIds"] = []int{} // 失败时返回空数组
@@ -298,7 +307,7 @@ func (c *UserController) GetInfo(ctx *gin.Context) {
 		}
 
 		// 获取用户角色ID列表 对应Java后端的ajax.put("roleIds", sysUser.getRoles().stream().map(SysRole::getRoleId).collect(Collectors.toList()))
-		roleIds, err := c.roleService.SelectRoleListByUserId(userId)
+		roleIds, err := roleService.SelectRoleListByUserId(userId)
 		if err != nil {
 			fmt.Printf("UserController.GetInfo: 查询用户角色失败: %v\n", err)
 			result["roleIds"] = []int64{} // 失败时返回空数组
@@ -308,7 +317,7 @@ func (c *UserController) GetInfo(ctx *gin.Context) {
 	}
 
 	// 获取所有角色列表 对应Java后端的roleService.selectRoleAll()
-	roles, err := c.roleService.SelectRoleAll()
+	roles, err := roleService.SelectRoleAll()
 	if err != nil {
 		fmt.Printf("UserController.GetInfo: 查询角色列表失败: %v\n", err)
 		response.SendAjaxResult(ctx, response.AjaxErrorWithMessage("获取角色列表失败"))
@@ -329,7 +338,7 @@ func (c *UserController) GetInfo(ctx *gin.Context) {
 	}
 
 	// 获取所有岗位列表 对应Java后端的ajax.put("posts", postService.selectPostAll())
-	posts, err := c.postService.SelectPostAll()
+	posts, err := postService.SelectPostAll()
 	if err != nil {
 		fmt.Printf("UserController.GetInfo: 查询岗位列表失败: %v\n", err)
 		// 岗位查询失败时，返回空数组，不影响整体功能
@@ -385,6 +394,9 @@ func (c *UserController) GetInfo(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /system/user [post]
 func (c *UserController) Add(ctx *gin.Context) {
+	deptService := c.deptService.WithContext(ctx.Request.Context())
+	roleService := c.roleService.WithContext(ctx.Request.Context())
+	userService := c.userService.WithContext(ctx.Request.Context())
 	fmt.Printf("UserController.Add: 新增用户\n")
 
 	// 先解析为map以获取密码字段
@@ -420,7 +432,7 @@ func (c *UserController) Add(ctx *gin.Context) {
 
 	// 校验部门数据权限 对应Java后端的deptService.checkDeptDataScope
 	if user.DeptID != nil && *user.DeptID > 0 {
-		if err := c.deptService.CheckDeptDataScope(currentUser.User, *user.DeptID); err != nil {
+		if err := deptService.CheckDeptDataScope(currentUser.User, *user.DeptID); err != nil {
 			fmt.Printf("UserController.Add: 部门数据权限校验失败: %v\n", err)
 			response.SendAjaxResult(ctx, response.AjaxErrorWithMessage(err.Error()))
 			return
@@ -429,7 +441,7 @@ func (c *UserController) Add(ctx *gin.Context) {
 
 	// 校验角色数据权限 对应Java后端的roleService.checkRoleDataScope
 	if len(user.RoleIDs) > 0 {
-		if err := c.roleService.CheckRoleDataScope(currentUser.User, user.RoleIDs...); err != nil {
+		if err := roleService.CheckRoleDataScope(currentUser.User, user.RoleIDs...); err != nil {
 			fmt.Printf("UserController.Add: 角色数据权限校验失败: %v\n", err)
 			response.SendAjaxResult(ctx, response.AjaxErrorWithMessage(err.Error()))
 			return
@@ -437,21 +449,21 @@ func (c *UserController) Add(ctx *gin.Context) {
 	}
 
 	// 校验用户名唯一性 对应Java后端的checkUserNameUnique
-	if !c.userService.CheckUserNameUnique(&user) {
+	if !userService.CheckUserNameUnique(&user) {
 		fmt.Printf("UserController.Add: 用户名已存在: %s\n", user.UserName)
 		response.SendAjaxResult(ctx, response.AjaxErrorWithMessage(fmt.Sprintf("新增用户'%s'失败，登录账号已存在", user.UserName)))
 		return
 	}
 
 	// 校验手机号唯一性 对应Java后端的checkPhoneUnique
-	if user.Phonenumber != "" && !c.userService.CheckPhoneUnique(&user) {
+	if user.Phonenumber != "" && !userService.CheckPhoneUnique(&user) {
 		fmt.Printf("UserController.Add: 手机号已存在: %s\n", user.Phonenumber)
 		response.SendAjaxResult(ctx, response.AjaxErrorWithMessage(fmt.Sprintf("新增用户'%s'失败，手机号码已存在", user.UserName)))
 		return
 	}
 
 	// 校验邮箱唯一性 对应Java后端的checkEmailUnique
-	if user.Email != "" && !c.userService.CheckEmailUnique(&user) {
+	if user.Email != "" && !userService.CheckEmailUnique(&user) {
 		fmt.Printf("UserController.Add: 邮箱已存在: %s\n", user.Email)
 		response.SendAjaxResult(ctx, response.AjaxErrorWithMessage(fmt.Sprintf("新增用户'%s'失败，邮箱账号已存在", user.UserName)))
 		return
@@ -461,7 +473,7 @@ func (c *UserController) Add(ctx *gin.Context) {
 	user.CreateBy = currentUser.User.UserName
 
 	// 新增用户
-	err := c.userService.InsertUser(&user, currentUser.User.UserName)
+	err := userService.InsertUser(&user, currentUser.User.UserName)
 	if err != nil {
 		fmt.Printf("UserController.Add: 新增用户失败: %v\n", err)
 		response.SendAjaxResult(ctx, response.AjaxErrorWithMessage("新增失败"))
@@ -483,6 +495,9 @@ func (c *UserController) Add(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /system/user [put]
 func (c *UserController) Edit(ctx *gin.Context) {
+	userService := c.userService.WithContext(ctx.Request.Context())
+	deptService := c.deptService.WithContext(ctx.Request.Context())
+	roleService := c.roleService.WithContext(ctx.Request.Context())
 	fmt.Printf("UserController.Edit: 修改用户\n")
 
 	var user model.SysUser
@@ -492,19 +507,24 @@ func (c *UserController) Edit(ctx *gin.Context) {
 		return
 	}
 
+	// 乐观锁版本号：请求体未传version时取If-Match请求头，都没有时拒绝修改
+	if !pkgUtils.RequireVersion(ctx, &user.Version) {
+		return
+	}
+
 	// 获取当前登录用户
 	loginUser, _ := ctx.Get("loginUser")
 	currentUser := loginUser.(*model.LoginUser)
 
 	// 校验用户是否允许操作 对应Java后端的checkUserAllowed
-	if err := c.userService.CheckUserAllowed(&user); err != nil {
+	if err := userService.CheckUserAllowed(&user); err != nil {
 		fmt.Printf("UserController.Edit: 用户操作校验失败: %v\n", err)
 		response.ErrorWithMessage(ctx, err.Error())
 		return
 	}
 
 	// 校验数据权限 对应Java后端的checkUserDataScope
-	if err := c.userService.CheckUserDataScope(user.UserID, currentUser.User); err != nil {
+	if err := userService.CheckUserDataScope(user.UserID, currentUser.User); err != nil {
 		fmt.Printf("UserController.Edit: 数据权限校验失败: %v\n", err)
 		response.ErrorWithMessage(ctx, err.Error())
 		return
@@ -512,7 +532,7 @@ func (c *UserController) Edit(ctx *gin.Context) {
 
 	// 校验部门数据权限 对应Java后端的deptService.checkDeptDataScope
 	if user.DeptID != nil && *user.DeptID > 0 {
-		if err := c.deptService.CheckDeptDataScope(currentUser.User, *user.DeptID); err != nil {
+		if err := deptService.CheckDeptDataScope(currentUser.User, *user.DeptID); err != nil {
 			fmt.Printf("UserController.Edit: 部门数据权限校验失败: %v\n", err)
 			response.ErrorWithMessage(ctx, err.Error())
 			return
@@ -521,7 +541,7 @@ func (c *UserController) Edit(ctx *gin.Context) {
 
 	// 校验角色数据权限 对应Java后端的roleService.checkRoleDataScope
 	if len(user.RoleIDs) > 0 {
-		if err := c.roleService.CheckRoleDataScope(currentUser.User, user.RoleIDs...); err != nil {
+		if err := roleService.CheckRoleDataScope(currentUser.User, user.RoleIDs...); err != nil {
 			fmt.Printf("UserController.Edit: 角色数据权限校验失败: %v\n", err)
 			response.ErrorWithMessage(ctx, err.Error())
 			return
@@ -529,21 +549,21 @@ func (c *UserController) Edit(ctx *gin.Context) {
 	}
 
 	// 校验用户名唯一性 对应Java后端的checkUserNameUnique
-	if !c.userService.CheckUserNameUnique(&user) {
+	if !userService.CheckUserNameUnique(&user) {
 		fmt.Printf("UserController.Edit: 用户名已存在: %s\n", user.UserName)
 		response.ErrorWithMessage(ctx, fmt.Sprintf("修改用户'%s'失败，登录账号已存在", user.UserName))
 		return
 	}
 
 	// 校验手机号唯一性 对应Java后端的checkPhoneUnique
-	if user.Phonenumber != "" && !c.userService.CheckPhoneUnique(&user) {
+	if user.Phonenumber != "" && !userService.CheckPhoneUnique(&user) {
 		fmt.Printf("UserController.Edit: 手机号已存在: %s\n", user.Phonenumber)
 		response.ErrorWithMessage(ctx, fmt.Sprintf("修改用户'%s'失败，手机号码已存在", user.UserName))
 		return
 	}
 
 	// 校验邮箱唯一性 对应Java后端的checkEmailUnique
-	if user.Email != "" && !c.userService.CheckEmailUnique(&user) {
+	if user.Email != "" && !userService.CheckEmailUnique(&user) {
 		fmt.Printf("UserController.Edit: 邮箱已存在: %s\n", user.Email)
 		response.ErrorWithMessage(ctx, fmt.Sprintf("修改用户'%s'失败，邮箱账号已存在", user.UserName))
 		return
@@ -552,9 +572,13 @@ func (c *UserController) Edit(ctx *gin.Context) {
 	// 设置更新人 对应Java后端的setUpdateBy
 	user.UpdateBy = currentUser.User.UserName
 
-	err := c.userService.UpdateUser(&user, currentUser.User.UserName)
+	err := userService.UpdateUser(&user, currentUser.User.UserName)
 	if err != nil {
 		fmt.Printf("UserController.Edit: 修改用户失败: %v\n", err)
+		if errors.Is(err, system.ErrVersionConflict) {
+			response.Conflict(ctx, system.ErrVersionConflict.Error())
+			return
+		}
 		response.ErrorWithMessage(ctx, err.Error())
 		return
 	}
@@ -574,6 +598,7 @@ func (c *UserController) Edit(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /system/user/{ids} [delete]
 func (c *UserController) Remove(ctx *gin.Context) {
+	userService := c.userService.WithContext(ctx.Request.Context())
 	idsStr := ctx.Param("ids")
 	if idsStr == "" {
 		response.ErrorWithMessage(ctx, "参数错误")
@@ -597,7 +622,7 @@ func (c *UserController) Remove(ctx *gin.Context) {
 	loginUser, _ := ctx.Get("loginUser")
 	currentUser := loginUser.(*model.LoginUser)
 
-	err := c.userService.DeleteUserByIds(currentUser.User, userIds)
+	err := userService.DeleteUserByIds(currentUser.User, userIds)
 	if err != nil {
 		response.ErrorWithMessage(ctx, err.Error())
 		return
@@ -617,6 +642,7 @@ func (c *UserController) Remove(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /system/user/resetPwd [put]
 func (c *UserController) ResetPwd(ctx *gin.Context) {
+	userService := c.userService.WithContext(ctx.Request.Context())
 	var req map[string]any
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		response.ErrorWithMessage(ctx, "参数错误")
@@ -642,18 +668,18 @@ func (c *UserController) ResetPwd(ctx *gin.Context) {
 
 	// 校验用户是否允许操作 对应Java后端的checkUserAllowed
 	user := &model.SysUser{UserID: userId}
-	if err := c.userService.CheckUserAllowed(user); err != nil {
+	if err := userService.CheckUserAllowed(user); err != nil {
 		response.ErrorWithMessage(ctx, err.Error())
 		return
 	}
 
 	// 校验数据权限 对应Java后端的checkUserDataScope
-	if err := c.userService.CheckUserDataScope(userId, currentUser.User); err != nil {
+	if err := userService.CheckUserDataScope(userId, currentUser.User); err != nil {
 		response.ErrorWithMessage(ctx, err.Error())
 		return
 	}
 
-	err := c.userService.ResetPwd(userId, password, currentUser.User.UserName)
+	err := userService.ResetPwd(userId, password, currentUser.User.UserName)
 	if err != nil {
 		response.ErrorWithMessage(ctx, err.Error())
 		return
@@ -673,6 +699,7 @@ func (c *UserController) ResetPwd(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /system/user/changeStatus [put]
 func (c *UserController) ChangeStatus(ctx *gin.Context) {
+	userService := c.userService.WithContext(ctx.Request.Context())
 	fmt.Printf("UserController.ChangeStatus: 修改用户状态开始\n")
 
 	var user model.SysUser
@@ -689,20 +716,20 @@ func (c *UserController) ChangeStatus(ctx *gin.Context) {
 	currentUser := loginUser.(*model.LoginUser)
 
 	// 校验用户是否允许操作 对应Java后端的checkUserAllowed
-	if err := c.userService.CheckUserAllowed(&user); err != nil {
+	if err := userService.CheckUserAllowed(&user); err != nil {
 		fmt.Printf("UserController.ChangeStatus: 用户操作校验失败: %v\n", err)
 		response.ErrorWithMessage(ctx, err.Error())
 		return
 	}
 
 	// 校验数据权限 对应Java后端的checkUserDataScope
-	if err := c.userService.CheckUserDataScope(user.UserID, currentUser.User); err != nil {
+	if err := userService.CheckUserDataScope(user.UserID, currentUser.User); err != nil {
 		fmt.Printf("UserController.ChangeStatus: 数据权限校验失败: %v\n", err)
 		response.ErrorWithMessage(ctx, err.Error())
 		return
 	}
 
-	err := c.userService.ChangeStatus(&user, currentUser.User.UserName)
+	err := userService.ChangeStatus(&user, currentUser.User.UserName)
 	if err != nil {
 		fmt.Printf("UserController.ChangeStatus: 修改用户状态失败: %v\n", err)
 		response.ErrorWithMessage(ctx, err.Error())
@@ -728,6 +755,7 @@ func (c *UserController) ChangeStatus(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /system/user/authRole/{userId} [get]
 func (c *UserController) AuthRole(ctx *gin.Context) {
+	userService := c.userService.WithContext(ctx.Request.Context())
 	userIdStr := ctx.Param("userId")
 	userId, err := strconv.ParseInt(userIdStr, 10, 64)
 	if err != nil {
@@ -740,13 +768,13 @@ func (c *UserController) AuthRole(ctx *gin.Context) {
 	currentUser := loginUser.(*model.LoginUser)
 
 	// 校验数据权限 对应Java后端的checkUserDataScope
-	if err := c.userService.CheckUserDataScope(userId, currentUser.User); err != nil {
+	if err := userService.CheckUserDataScope(userId, currentUser.User); err != nil {
 		response.ErrorWithMessage(ctx, err.Error())
 		return
 	}
 
 	// 获取用户信息和角色信息
-	result, err := c.userService.GetUserAuthRole(userId)
+	result, err := userService.GetUserAuthRole(userId)
 	if err != nil {
 		fmt.Printf("UserController.AuthRole: 获取用户授权角色失败: %v\n", err)
 		response.ErrorWithMessage(ctx, err.Error())
@@ -782,6 +810,8 @@ func (c *UserController) AuthRole(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /system/user/authRole [put]
 func (c *UserController) InsertAuthRole(ctx *gin.Context) {
+	userService := c.userService.WithContext(ctx.Request.Context())
+	roleService := c.roleService.WithContext(ctx.Request.Context())
 	// 对应Java后端的参数接收方式：insertAuthRole(Long userId, Long[] roleIds)
 	// 前端使用params发送，所以从Query参数获取
 	userIdStr := ctx.Query("userId")
@@ -818,20 +848,20 @@ func (c *UserController) InsertAuthRole(ctx *gin.Context) {
 	currentUser := loginUser.(*model.LoginUser)
 
 	// 校验数据权限 对应Java后端的checkUserDataScope
-	if err := c.userService.CheckUserDataScope(userId, currentUser.User); err != nil {
+	if err := userService.CheckUserDataScope(userId, currentUser.User); err != nil {
 		response.ErrorWithMessage(ctx, err.Error())
 		return
 	}
 
 	// 校验角色数据权限 对应Java后端的roleService.checkRoleDataScope
 	if len(roleIds) > 0 {
-		if err := c.roleService.CheckRoleDataScope(currentUser.User, roleIds...); err != nil {
+		if err := roleService.CheckRoleDataScope(currentUser.User, roleIds...); err != nil {
 			response.ErrorWithMessage(ctx, err.Error())
 			return
 		}
 	}
 
-	err = c.userService.InsertUserAuth(userId, roleIds)
+	err = userService.InsertUserAuth(userId, roleIds)
 	if err != nil {
 		fmt.Printf("UserController.InsertAuthRole: 用户授权角色失败: %v\n", err)
 		response.ErrorWithMessage(ctx, err.Error())
@@ -859,6 +889,7 @@ func (c *UserController) InsertAuthRole(ctx *gin.Context) {
 // @Success 200 {file} file "Excel文件"
 // @Router /system/user/export [post]
 func (c *UserController) Export(ctx *gin.Context) {
+	userService := c.userService.WithContext(ctx.Request.Context())
 	// 权限验证 - 对应Java后端的@PreAuthorize("@ss.hasPermi('system:user:export')")
 	loginUser, exists := ctx.Get("loginUser")
 	if !exists {
@@ -908,7 +939,7 @@ func (c *UserController) Export(ctx *gin.Context) {
 
 	// 查询所有符合条件的用户（不分页） 对应Java后端的userService.selectUserList(user)
 	// 使用足够大的分页参数确保获取所有数据
-	users, _, err := c.userService.SelectUserListWithDataScope(currentUser.User, user, 1, 100000)
+	users, _, err := userService.SelectUserListWithDataScope(currentUser.User, user, 1, 100000)
 	if err != nil {
 		fmt.Printf("UserController.Export: 查询用户数据失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "查询失败: "+err.Error())
@@ -956,6 +987,7 @@ func (c *UserController) Export(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /system/user/importData [post]
 func (c *UserController) ImportData(ctx *gin.Context) {
+	userService := c.userService.WithContext(ctx.Request.Context())
 	fmt.Printf("UserController.ImportData: 导入用户数据\n")
 
 	// 获取上传的文件 对应Java后端的MultipartFile file参数
@@ -1010,7 +1042,7 @@ func (c *UserController) ImportData(ctx *gin.Context) {
 	operName := currentUser.User.UserName
 
 	// 调用用户服务导入 对应Java后端的userService.importUser(userList, updateSupport, operName)
-	importMessage, err := c.userService.ImportUser(users, updateSupport, operName)
+	importMessage, err := userService.ImportUser(users, updateSupport, operName)
 	if err != nil {
 		fmt.Printf("UserController.ImportData: 导入用户失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "导入失败: "+err.Error())
@@ -1078,13 +1110,14 @@ func (c *UserController) ImportTemplate(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /system/user/deptTree [get]
 func (c *UserController) DeptTree(ctx *gin.Context) {
+	deptService := c.deptService.WithContext(ctx.Request.Context())
 	fmt.Printf("UserController.DeptTree: 获取部门树列表\n")
 
 	// 构建查询条件 对应Java后端的SysDept dept参数
 	dept := &model.SysDept{}
 
 	// 查询部门树列表 对应Java后端的deptService.selectDeptTreeList(dept)
-	deptTree, err := c.deptService.SelectDeptTreeList(dept)
+	deptTree, err := deptService.SelectDeptTreeList(dept)
 	if err != nil {
 		fmt.Printf("UserController.DeptTree: 查询部门树失败: %v\n", err)
 		response.SendAjaxResult(ctx, response.AjaxErrorWithMessage("查询部门树失败"))
@@ -1105,6 +1138,7 @@ func (c *UserController) DeptTree(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /system/user/profile [get]
 func (c *UserController) Profile(ctx *gin.Context) {
+	userService := c.userService.WithContext(ctx.Request.Context())
 	fmt.Printf("UserController.Profile: 获取个人信息\n")
 
 	// 获取当前登录用户
@@ -1116,7 +1150,7 @@ func (c *UserController) Profile(ctx *gin.Context) {
 	currentUser := loginUser.(*model.LoginUser)
 
 	// 获取用户详细信息
-	user, err := c.userService.SelectUserById(currentUser.User.UserID)
+	user, err := userService.SelectUserById(currentUser.User.UserID)
 	if err != nil {
 		fmt.Printf("UserController.Profile: 查询用户信息失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "查询失败")
@@ -1129,14 +1163,14 @@ func (c *UserController) Profile(ctx *gin.Context) {
 	}
 
 	// 查询用户角色组
-	roleGroup, err := c.userService.SelectUserRoleGroup(user.UserName)
+	roleGroup, err := userService.SelectUserRoleGroup(user.UserName)
 	if err != nil {
 		fmt.Printf("UserController.Profile: 查询用户角色组失败: %v\n", err)
 		roleGroup = ""
 	}
 
 	// 查询用户岗位组
-	postGroup, err := c.userService.SelectUserPostGroup(user.UserName)
+	postGroup, err := userService.SelectUserPostGroup(user.UserName)
 	if err != nil {
 		fmt.Printf("UserController.Profile: 查询用户岗位组失败: %v\n", err)
 		postGroup = ""
@@ -1163,6 +1197,7 @@ func (c *UserController) Profile(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /system/user/profile [put]
 func (c *UserController) UpdateProfile(ctx *gin.Context) {
+	userService := c.userService.WithContext(ctx.Request.Context())
 	fmt.Printf("UserController.UpdateProfile: 修改个人信息\n")
 
 	var user model.SysUser
@@ -1184,13 +1219,13 @@ func (c *UserController) UpdateProfile(ctx *gin.Context) {
 	user.UserID = currentUser.User.UserID
 
 	// 校验手机号唯一性
-	if user.Phonenumber != "" && !c.userService.CheckPhoneUnique(&user) {
+	if user.Phonenumber != "" && !userService.CheckPhoneUnique(&user) {
 		response.ErrorWithMessage(ctx, fmt.Sprintf("修改用户'%s'失败，手机号码已存在", user.UserName))
 		return
 	}
 
 	// 校验邮箱唯一性
-	if user.Email != "" && !c.userService.CheckEmailUnique(&user) {
+	if user.Email != "" && !userService.CheckEmailUnique(&user) {
 		response.ErrorWithMessage(ctx, fmt.Sprintf("修改用户'%s'失败，邮箱账号已存在", user.UserName))
 		return
 	}
@@ -1198,7 +1233,7 @@ func (c *UserController) UpdateProfile(ctx *gin.Context) {
 	// 设置更新人
 	user.UpdateBy = currentUser.User.UserName
 
-	err := c.userService.UpdateUserProfile(&user)
+	err := userService.UpdateUserProfile(&user)
 	if err != nil {
 		fmt.Printf("UserController.UpdateProfile: 修改个人信息失败: %v\n", err)
 		response.ErrorWithMessage(ctx, err.Error())
@@ -1220,6 +1255,7 @@ func (c *UserController) UpdateProfile(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /system/user/profile/updatePwd [put]
 func (c *UserController) UpdatePwd(ctx *gin.Context) {
+	userService := c.userService.WithContext(ctx.Request.Context())
 	fmt.Printf("UserController.UpdatePwd: 修改密码\n")
 
 	var pwdData map[string]string
@@ -1246,7 +1282,7 @@ func (c *UserController) UpdatePwd(ctx *gin.Context) {
 	currentUser := loginUser.(*model.LoginUser)
 
 	// 获取用户信息验证旧密码
-	user, err := c.userService.SelectUserById(currentUser.User.UserID)
+	user, err := userService.SelectUserById(currentUser.User.UserID)
 	if err != nil {
 		response.ErrorWithMessage(ctx, "查询用户信息失败")
 		return
@@ -1265,7 +1301,7 @@ func (c *UserController) UpdatePwd(ctx *gin.Context) {
 	}
 
 	// 重置密码
-	err = c.userService.ResetPwd(currentUser.User.UserID, newPassword, currentUser.User.UserName)
+	err = userService.ResetPwd(currentUser.User.UserID, newPassword, currentUser.User.UserName)
 	if err != nil {
 		fmt.Printf("UserController.UpdatePwd: 修改密码失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "修改密码失败")
@@ -1287,6 +1323,7 @@ func (c *UserController) UpdatePwd(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /system/user/profile/avatar [post]
 func (c *UserController) Avatar(ctx *gin.Context) {
+	userService := c.userService.WithContext(ctx.Request.Context())
 	fmt.Printf("UserController.Avatar: 头像上传\n")
 
 	// 获取上传的文件
@@ -1317,7 +1354,7 @@ func (c *UserController) Avatar(ctx *gin.Context) {
 	avatarUrl := "/profile/avatar/default.jpg" // 临时返回默认头像
 
 	// 更新用户头像
-	err = c.userService.UpdateUserAvatar(currentUser.User.UserID, avatarUrl)
+	err = userService.UpdateUserAvatar(currentUser.User.UserID, avatarUrl)
 	if err != nil {
 		fmt.Printf("UserController.Avatar: 更新用户头像失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "上传头像失败")
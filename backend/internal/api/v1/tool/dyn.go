@@ -0,0 +1,287 @@
+package tool
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"wosm/internal/api/middleware"
+	"wosm/internal/repository/model"
+	toolService "wosm/internal/service/tool"
+	"wosm/pkg/datascope"
+	"wosm/pkg/operlog"
+	"wosm/pkg/response"
+	"wosm/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DynController 运行时模块控制器，已发布的业务表无需生成代码即可通过 /dyn/:module 增删改查
+type DynController struct {
+	dynService *toolService.DynService
+}
+
+// NewDynController 创建运行时模块控制器实例
+func NewDynController() *DynController {
+	return &DynController{
+		dynService: toolService.NewDynService(),
+	}
+}
+
+// dynHandler 已加载模块的处理函数，dynService 已绑定当前请求的context
+type dynHandler func(ctx *gin.Context, dynService *toolService.DynService, module *toolService.DynModule, dataScope datascope.Condition)
+
+// withModule 加载路径中的模块，校验模块权限（模块名:业务名:操作）并计算数据权限后执行处理函数
+func (c *DynController) withModule(action string, handler dynHandler) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		dynService := c.dynService.WithContext(ctx.Request.Context())
+		module, err := dynService.LoadModule(ctx.Param("module"))
+		if err != nil {
+			response.ErrorWithMessage(ctx, err.Error())
+			return
+		}
+
+		permission := module.Permission(action)
+		middleware.WithPermission(permission, func(ctx *gin.Context) {
+			dataScope, err := dynDataScope(ctx, module, permission)
+			if err != nil {
+				response.ErrorWithMessage(ctx, err.Error())
+				return
+			}
+			handler(ctx, dynService, module, dataScope)
+		})(ctx)
+	}
+}
+
+// dynDataScope 业务表有部门字段时按当前用户的数据权限生成过滤条件
+func dynDataScope(ctx *gin.Context, module *toolService.DynModule, permission string) (datascope.Condition, error) {
+	columns, ok := module.DataScopeColumns()
+	if !ok {
+		return datascope.Condition{}, nil
+	}
+
+	loginUser, exists := ctx.Get("loginUser")
+	if !exists {
+		return datascope.Condition{}, fmt.Errorf("获取用户信息失败")
+	}
+	currentUser, ok := loginUser.(*model.LoginUser)
+	if !ok || currentUser.User == nil {
+		return datascope.Condition{}, fmt.Errorf("获取用户信息失败")
+	}
+
+	return datascope.Build(currentUser.User, permission).Condition(columns), nil
+}
+
+// dynQuery 从请求参数构建列表查询条件
+func dynQuery(ctx *gin.Context, values url.Values) *toolService.DynQuery {
+	pageDomain := utils.BuildPageRequest(ctx)
+	return &toolService.DynQuery{
+		Values:   values,
+		OrderBy:  pageDomain.OrderBy,
+		IsAsc:    strings.EqualFold(pageDomain.IsAsc, "asc") || strings.EqualFold(pageDomain.IsAsc, "ascending"),
+		PageNum:  pageDomain.PageNum,
+		PageSize: pageDomain.PageSize,
+	}
+}
+
+// dynBody 解析请求体，数字保留原始文本以免长整型丢失精度
+func dynBody(ctx *gin.Context) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	decoder := json.NewDecoder(ctx.Request.Body)
+	decoder.UseNumber()
+	if err := decoder.Decode(&values); err != nil {
+		return nil, fmt.Errorf("参数格式错误")
+	}
+	return values, nil
+}
+
+// dynOperName 获取当前操作人
+func dynOperName(ctx *gin.Context) string {
+	if username, exists := ctx.Get("username"); exists {
+		return fmt.Sprintf("%v", username)
+	}
+	return ""
+}
+
+// List 查询运行时模块列表
+// @Summary 查询运行时模块列表
+// @Description 按已发布业务表的查询字段分页查询数据
+// @Tags 运行时模块
+// @Produce json
+// @Param module path string true "业务名"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /dyn/{module}/list [get]
+func (c *DynController) List() gin.HandlerFunc {
+	return c.withModule("list", func(ctx *gin.Context, dynService *toolService.DynService, module *toolService.DynModule, dataScope datascope.Condition) {
+		records, total, err := dynService.SelectList(module, dynQuery(ctx, ctx.Request.URL.Query()), dataScope)
+		if err != nil {
+			fmt.Printf("DynController.List: 查询%s列表失败: %v\n", module.Table.FunctionName, err)
+			response.ErrorWithMessage(ctx, "查询失败，"+err.Error())
+			return
+		}
+		response.Page(ctx, total, records)
+	})
+}
+
+// GetInfo 获取运行时模块详细信息
+// @Summary 获取运行时模块详细信息
+// @Description 根据主键查询数据
+// @Tags 运行时模块
+// @Produce json
+// @Param module path string true "业务名"
+// @Param id path string true "主键"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /dyn/{module}/{id} [get]
+func (c *DynController) GetInfo() gin.HandlerFunc {
+	return c.withModule("query", func(ctx *gin.Context, dynService *toolService.DynService, module *toolService.DynModule, dataScope datascope.Condition) {
+		ids, err := module.ParseIds(ctx.Param("id"))
+		if err != nil || len(ids) != 1 {
+			response.ErrorWithMessage(ctx, "主键格式错误")
+			return
+		}
+
+		record, err := dynService.SelectById(module, ids[0], dataScope)
+		if err != nil {
+			fmt.Printf("DynController.GetInfo: 查询%s失败: %v\n", module.Table.FunctionName, err)
+			response.ErrorWithMessage(ctx, "查询失败")
+			return
+		}
+		if record == nil {
+			response.ErrorWithMessage(ctx, "数据不存在或没有权限访问")
+			return
+		}
+		response.SuccessWithData(ctx, record)
+	})
+}
+
+// Add 新增运行时模块数据
+// @Summary 新增运行时模块数据
+// @Description 按字段元数据校验必填和字典字段后新增
+// @Tags 运行时模块
+// @Accept json
+// @Produce json
+// @Param module path string true "业务名"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /dyn/{module} [post]
+func (c *DynController) Add() gin.HandlerFunc {
+	return c.withModule("add", func(ctx *gin.Context, dynService *toolService.DynService, module *toolService.DynModule, dataScope datascope.Condition) {
+		title := module.Table.FunctionName
+		values, err := dynBody(ctx)
+		if err != nil {
+			response.ErrorWithMessage(ctx, err.Error())
+			return
+		}
+
+		if err := dynService.Insert(module, values, dynOperName(ctx)); err != nil {
+			operlog.RecordOperLog(ctx, title, "新增", fmt.Sprintf("新增%s失败: %s", title, err.Error()), false)
+			response.ErrorWithMessage(ctx, "新增失败，"+err.Error())
+			return
+		}
+
+		operlog.RecordOperLog(ctx, title, "新增", fmt.Sprintf("新增%s成功", title), true)
+		response.SuccessWithMessage(ctx, "新增成功")
+	})
+}
+
+// Edit 修改运行时模块数据
+// @Summary 修改运行时模块数据
+// @Description 只修改请求中包含的编辑字段
+// @Tags 运行时模块
+// @Accept json
+// @Produce json
+// @Param module path string true "业务名"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /dyn/{module} [put]
+func (c *DynController) Edit() gin.HandlerFunc {
+	return c.withModule("edit", func(ctx *gin.Context, dynService *toolService.DynService, module *toolService.DynModule, dataScope datascope.Condition) {
+		title := module.Table.FunctionName
+		values, err := dynBody(ctx)
+		if err != nil {
+			response.ErrorWithMessage(ctx, err.Error())
+			return
+		}
+
+		if err := dynService.Update(module, values, dynOperName(ctx), dataScope); err != nil {
+			operlog.RecordOperLog(ctx, title, "修改", fmt.Sprintf("修改%s失败: %s", title, err.Error()), false)
+			response.ErrorWithMessage(ctx, "修改失败，"+err.Error())
+			return
+		}
+
+		operlog.RecordOperLog(ctx, title, "修改", fmt.Sprintf("修改%s成功", title), true)
+		response.SuccessWithMessage(ctx, "修改成功")
+	})
+}
+
+// Remove 删除运行时模块数据
+// @Summary 删除运行时模块数据
+// @Description 批量删除，多个主键用逗号分隔
+// @Tags 运行时模块
+// @Produce json
+// @Param module path string true "业务名"
+// @Param ids path string true "主键，多个用逗号分隔"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /dyn/{module}/{ids} [delete]
+func (c *DynController) Remove() gin.HandlerFunc {
+	return c.withModule("remove", func(ctx *gin.Context, dynService *toolService.DynService, module *toolService.DynModule, dataScope datascope.Condition) {
+		title := module.Table.FunctionName
+		ids, err := module.ParseIds(ctx.Param("id"))
+		if err != nil {
+			response.ErrorWithMessage(ctx, err.Error())
+			return
+		}
+
+		if err := dynService.DeleteByIds(module, ids, dataScope); err != nil {
+			operlog.RecordOperLog(ctx, title, "删除", fmt.Sprintf("删除%s失败: %s", title, err.Error()), false)
+			response.ErrorWithMessage(ctx, "删除失败，"+err.Error())
+			return
+		}
+
+		operlog.RecordOperLog(ctx, title, "删除", fmt.Sprintf("删除%s成功，主键: %s", title, ctx.Param("id")), true)
+		response.SuccessWithMessage(ctx, "删除成功")
+	})
+}
+
+// Export 导出运行时模块数据
+// @Summary 导出运行时模块数据
+// @Description 按查询条件导出列表字段，字典字段导出字典标签
+// @Tags 运行时模块
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param module path string true "业务名"
+// @Security ApiKeyAuth
+// @Success 200 {file} file
+// @Router /dyn/{module}/export [post]
+func (c *DynController) Export() gin.HandlerFunc {
+	return c.withModule("export", func(ctx *gin.Context, dynService *toolService.DynService, module *toolService.DynModule, dataScope datascope.Condition) {
+		title := module.Table.FunctionName
+		// 导出使用表单提交，查询条件同时兼容URL参数
+		values := ctx.Request.URL.Query()
+		if err := ctx.Request.ParseForm(); err == nil {
+			for key, value := range ctx.Request.PostForm {
+				values[key] = value
+			}
+		}
+
+		fileData, count, err := dynService.Export(module, dynQuery(ctx, values), dataScope)
+		if err != nil {
+			fmt.Printf("DynController.Export: 导出%s失败: %v\n", title, err)
+			operlog.RecordOperLog(ctx, title, "导出", fmt.Sprintf("导出%s失败: %s", title, err.Error()), false)
+			response.ErrorWithMessage(ctx, "导出失败: "+err.Error())
+			return
+		}
+
+		filename := fmt.Sprintf("%s数据导出_%s.xlsx", title, time.Now().Format("20060102_150405"))
+		ctx.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+		ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename*=UTF-8''%s", url.QueryEscape(filename)))
+		ctx.Header("Content-Length", strconv.Itoa(len(fileData)))
+		ctx.Data(200, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", fileData)
+
+		operlog.RecordOperLog(ctx, title, "导出", fmt.Sprintf("导出%s数据，共%d条记录", title, count), true)
+	})
+}
@@ -2,10 +2,16 @@ package tool
 
 import (
 	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 	"wosm/internal/repository/model"
 	toolService "wosm/internal/service/tool"
+	"wosm/pkg/ddl"
+	"wosm/pkg/operlog"
 	"wosm/pkg/response"
 
 	"github.com/gin-gonic/gin"
@@ -39,6 +45,7 @@ func NewGenController() *GenController {
 // @Success 200 {object} response.Result
 // @Router /tool/gen/list [get]
 func (c *GenController) GenList(ctx *gin.Context) {
+	genService := c.genService.WithContext(ctx.Request.Context())
 	fmt.Printf("GenController.GenList: 查询代码生成列表\n")
 
 	// 构建查询条件
@@ -51,7 +58,7 @@ func (c *GenController) GenList(ctx *gin.Context) {
 	}
 
 	// 查询代码生成列表
-	genTableList, err := c.genService.SelectGenTableList(genTable)
+	genTableList, err := genService.SelectGenTableList(genTable)
 	if err != nil {
 		fmt.Printf("GenController.GenList: 查询代码生成列表失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "查询代码生成列表失败")
@@ -76,13 +83,14 @@ func (c *GenController) GenList(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /tool/gen/db/list [get]
 func (c *GenController) DbList(ctx *gin.Context) {
+	genService := c.genService.WithContext(ctx.Request.Context())
 	fmt.Printf("GenController.DbList: 查询数据库表列表\n")
 
 	tableName := ctx.Query("tableName")
 	tableComment := ctx.Query("tableComment")
 
 	// 查询数据库表列表
-	dbTableList, err := c.genService.SelectDbTableList(tableName, tableComment)
+	dbTableList, err := genService.SelectDbTableList(tableName, tableComment)
 	if err != nil {
 		fmt.Printf("GenController.DbList: 查询数据库表列表失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "查询数据库表列表失败")
@@ -106,6 +114,7 @@ func (c *GenController) DbList(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /tool/gen/{tableId} [get]
 func (c *GenController) GetInfo(ctx *gin.Context) {
+	genService := c.genService.WithContext(ctx.Request.Context())
 	fmt.Printf("GenController.GetInfo: 获取代码生成信息\n")
 
 	tableIdStr := ctx.Param("tableId")
@@ -116,7 +125,7 @@ func (c *GenController) GetInfo(ctx *gin.Context) {
 	}
 
 	// 查询代码生成信息
-	genTable, err := c.genService.SelectGenTableById(tableId)
+	genTable, err := genService.SelectGenTableById(tableId)
 	if err != nil {
 		fmt.Printf("GenController.GetInfo: 查询代码生成信息失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "查询代码生成信息失败")
@@ -129,7 +138,7 @@ func (c *GenController) GetInfo(ctx *gin.Context) {
 	}
 
 	// 查询所有表信息
-	allTables, err := c.genService.SelectGenTableAll()
+	allTables, err := genService.SelectGenTableAll()
 	if err != nil {
 		fmt.Printf("GenController.GetInfo: 查询所有表信息失败: %v\n", err)
 		allTables = []model.GenTable{} // 失败时返回空数组
@@ -157,6 +166,7 @@ func (c *GenController) GetInfo(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /tool/gen [put]
 func (c *GenController) EditSave(ctx *gin.Context) {
+	genService := c.genService.WithContext(ctx.Request.Context())
 	fmt.Printf("GenController.EditSave: 修改代码生成信息\n")
 
 	var genTable model.GenTable
@@ -165,8 +175,14 @@ func (c *GenController) EditSave(ctx *gin.Context) {
 		return
 	}
 
+	// 参数校验（包含树表和主子表配置）
+	if err := genService.ValidateEdit(&genTable); err != nil {
+		response.ErrorWithMessage(ctx, err.Error())
+		return
+	}
+
 	// 修改代码生成信息
-	if err := c.genService.UpdateGenTable(&genTable); err != nil {
+	if err := genService.UpdateGenTable(&genTable); err != nil {
 		fmt.Printf("GenController.EditSave: 修改代码生成信息失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "修改代码生成信息失败")
 		return
@@ -187,6 +203,7 @@ func (c *GenController) EditSave(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /tool/gen/importTable [post]
 func (c *GenController) ImportTable(ctx *gin.Context) {
+	genService := c.genService.WithContext(ctx.Request.Context())
 	fmt.Printf("GenController.ImportTable: 导入表结构\n")
 
 	tablesStr := ctx.Query("tables")
@@ -203,7 +220,7 @@ func (c *GenController) ImportTable(ctx *gin.Context) {
 
 	// 导入表结构
 	operName := "admin" // TODO: 从当前用户获取
-	if err := c.genService.ImportTable(tableNames, operName); err != nil {
+	if err := genService.ImportTable(tableNames, operName); err != nil {
 		fmt.Printf("GenController.ImportTable: 导入表结构失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "导入表结构失败")
 		return
@@ -224,6 +241,7 @@ func (c *GenController) ImportTable(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /tool/gen/{tableIds} [delete]
 func (c *GenController) Remove(ctx *gin.Context) {
+	genService := c.genService.WithContext(ctx.Request.Context())
 	fmt.Printf("GenController.Remove: 删除代码生成\n")
 
 	tableIdsStr := ctx.Param("tableIds")
@@ -245,7 +263,7 @@ func (c *GenController) Remove(ctx *gin.Context) {
 	}
 
 	// 删除代码生成
-	if err := c.genService.DeleteGenTableByIds(tableIds); err != nil {
+	if err := genService.DeleteGenTableByIds(tableIds); err != nil {
 		fmt.Printf("GenController.Remove: 删除代码生成失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "删除代码生成失败")
 		return
@@ -266,6 +284,7 @@ func (c *GenController) Remove(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /tool/gen/preview/{tableId} [get]
 func (c *GenController) Preview(ctx *gin.Context) {
+	genService := c.genService.WithContext(ctx.Request.Context())
 	fmt.Printf("GenController.Preview: 预览代码\n")
 
 	tableIdStr := ctx.Param("tableId")
@@ -276,7 +295,7 @@ func (c *GenController) Preview(ctx *gin.Context) {
 	}
 
 	// 预览代码
-	codeMap, err := c.genService.PreviewCode(tableId)
+	codeMap, err := genService.PreviewCode(tableId)
 	if err != nil {
 		fmt.Printf("GenController.Preview: 预览代码失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "预览代码失败")
@@ -287,17 +306,47 @@ func (c *GenController) Preview(ctx *gin.Context) {
 	response.SuccessWithData(ctx, codeMap)
 }
 
+// GenDiff 预览生成到自定义路径的差异
+// @Summary 预览生成差异
+// @Description 对比生成的代码与生成路径下的现有文件，返回每个文件的状态和unified diff
+// @Tags 代码生成
+// @Produce json
+// @Param tableName path string true "表名"
+// @Param registerRoutes query bool false "是否自动注册路由"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /tool/gen/genDiff/{tableName} [get]
+func (c *GenController) GenDiff(ctx *gin.Context) {
+	genService := c.genService.WithContext(ctx.Request.Context())
+	tableName := ctx.Param("tableName")
+	if tableName == "" {
+		response.ErrorWithMessage(ctx, "表名不能为空")
+		return
+	}
+
+	diffs, err := genService.GenerateDiff(tableName, ctx.Query("registerRoutes") == "true")
+	if err != nil {
+		fmt.Printf("GenController.GenDiff: 预览生成差异失败: %v\n", err)
+		response.ErrorWithMessage(ctx, err.Error())
+		return
+	}
+
+	response.SuccessWithData(ctx, diffs)
+}
+
 // GenCode 生成代码（自定义路径） 对应Java后端的genCode方法
 // @Summary 生成代码（自定义路径）
-// @Description 生成代码到自定义路径
+// @Description 生成代码到自定义路径，在上次生成后被修改过的文件不会被覆盖
 // @Tags 代码生成
 // @Accept json
 // @Produce json
 // @Param tableName path string true "表名"
+// @Param registerRoutes query bool false "是否自动注册路由"
 // @Security ApiKeyAuth
 // @Success 200 {object} response.Result
 // @Router /tool/gen/genCode/{tableName} [get]
 func (c *GenController) GenCode(ctx *gin.Context) {
+	genService := c.genService.WithContext(ctx.Request.Context())
 	fmt.Printf("GenController.GenCode: 生成代码\n")
 
 	tableName := ctx.Param("tableName")
@@ -307,27 +356,199 @@ func (c *GenController) GenCode(ctx *gin.Context) {
 	}
 
 	// 生成代码
-	if err := c.genService.GenerateCode(tableName); err != nil {
+	diffs, err := genService.GenerateCode(tableName, ctx.Query("registerRoutes") == "true")
+	if err != nil {
 		fmt.Printf("GenController.GenCode: 生成代码失败: %v\n", err)
-		response.ErrorWithMessage(ctx, "生成代码失败")
+		response.ErrorWithMessage(ctx, "生成代码失败，"+err.Error())
 		return
 	}
 
+	created, updated := 0, 0
+	for _, diff := range diffs {
+		switch diff.Status {
+		case toolService.GenFileCreate:
+			created++
+		case toolService.GenFileUpdate:
+			updated++
+		}
+	}
+
 	fmt.Printf("GenController.GenCode: 生成代码成功, TableName=%s\n", tableName)
-	response.SuccessWithMessage(ctx, "生成成功")
+	response.SuccessWithMessage(ctx, fmt.Sprintf("生成成功，新建%d个文件，更新%d个文件", created, updated))
+}
+
+// genInstallMenuRequest 安装模块菜单请求
+type genInstallMenuRequest struct {
+	RoleIds []int64 `json:"roleIds"` // 授权的角色ID
+}
+
+// InstallMenu 安装模块菜单
+// @Summary 安装模块菜单
+// @Description 在一个事务中创建模块目录、页面菜单和按钮权限（list/query/add/edit/remove/export），并授权给选择的角色
+// @Tags 代码生成
+// @Accept json
+// @Produce json
+// @Param tableId path int true "表ID"
+// @Param request body genInstallMenuRequest true "授权角色"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /tool/gen/menu/{tableId} [post]
+func (c *GenController) InstallMenu(ctx *gin.Context) {
+	genService := c.genService.WithContext(ctx.Request.Context())
+	tableId, err := strconv.ParseInt(ctx.Param("tableId"), 10, 64)
+	if err != nil {
+		response.ErrorWithMessage(ctx, "表ID格式错误")
+		return
+	}
+	var req genInstallMenuRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ErrorWithMessage(ctx, "参数错误")
+		return
+	}
+
+	operName := ""
+	if username, exists := ctx.Get("username"); exists {
+		operName = fmt.Sprintf("%v", username)
+	}
+
+	table, err := genService.InstallMenu(tableId, req.RoleIds, operName)
+	if err != nil {
+		operlog.RecordOperLog(ctx, "代码生成", "新增", fmt.Sprintf("安装模块菜单失败: %s", err.Error()), false)
+		response.ErrorWithMessage(ctx, "安装模块菜单失败，"+err.Error())
+		return
+	}
+
+	operlog.RecordOperLog(ctx, "代码生成", "新增", fmt.Sprintf("安装模块菜单'%s'，菜单ID: %d，授权角色: %v", table.FunctionName, table.MenuId, req.RoleIds), true)
+	response.SuccessWithMessage(ctx, "安装成功")
+}
+
+// UninstallMenu 卸载模块菜单
+// @Summary 卸载模块菜单
+// @Description 删除安装的页面菜单、按钮权限及其角色授权，安装时新建的模块目录为空时一并删除
+// @Tags 代码生成
+// @Produce json
+// @Param tableId path int true "表ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /tool/gen/menu/{tableId} [delete]
+func (c *GenController) UninstallMenu(ctx *gin.Context) {
+	genService := c.genService.WithContext(ctx.Request.Context())
+	tableId, err := strconv.ParseInt(ctx.Param("tableId"), 10, 64)
+	if err != nil {
+		response.ErrorWithMessage(ctx, "表ID格式错误")
+		return
+	}
+
+	operName := ""
+	if username, exists := ctx.Get("username"); exists {
+		operName = fmt.Sprintf("%v", username)
+	}
+
+	if err := genService.UninstallMenu(tableId, operName); err != nil {
+		operlog.RecordOperLog(ctx, "代码生成", "删除", fmt.Sprintf("卸载模块菜单失败: %s", err.Error()), false)
+		response.ErrorWithMessage(ctx, "卸载模块菜单失败，"+err.Error())
+		return
+	}
+
+	operlog.RecordOperLog(ctx, "代码生成", "删除", fmt.Sprintf("卸载模块菜单，表ID: %d", tableId), true)
+	response.SuccessWithMessage(ctx, "卸载成功")
+}
+
+// Publish 发布运行时模块
+// @Summary 发布运行时模块
+// @Description 发布后无需生成代码，通过 /dyn/{业务名} 按字段元数据增删改查，权限为 模块名:业务名:操作
+// @Tags 代码生成
+// @Produce json
+// @Param tableId path int true "表ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /tool/gen/publish/{tableId} [put]
+func (c *GenController) Publish(ctx *gin.Context) {
+	c.publish(ctx, true)
+}
+
+// Unpublish 取消发布运行时模块
+// @Summary 取消发布运行时模块
+// @Description 取消发布后 /dyn/{业务名} 不再可访问，业务表数据不受影响
+// @Tags 代码生成
+// @Produce json
+// @Param tableId path int true "表ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /tool/gen/publish/{tableId} [delete]
+func (c *GenController) Unpublish(ctx *gin.Context) {
+	c.publish(ctx, false)
+}
+
+// publish 修改运行时模块发布状态
+func (c *GenController) publish(ctx *gin.Context, published bool) {
+	genService := c.genService.WithContext(ctx.Request.Context())
+	tableId, err := strconv.ParseInt(ctx.Param("tableId"), 10, 64)
+	if err != nil {
+		response.ErrorWithMessage(ctx, "表ID格式错误")
+		return
+	}
+
+	action := "发布"
+	if !published {
+		action = "取消发布"
+	}
+
+	operName := ""
+	if username, exists := ctx.Get("username"); exists {
+		operName = fmt.Sprintf("%v", username)
+	}
+
+	table, err := genService.PublishTable(tableId, published, operName)
+	if err != nil {
+		operlog.RecordOperLog(ctx, "代码生成", "修改", fmt.Sprintf("%s运行时模块失败: %s", action, err.Error()), false)
+		response.ErrorWithMessage(ctx, action+"运行时模块失败，"+err.Error())
+		return
+	}
+
+	operlog.RecordOperLog(ctx, "代码生成", "修改", fmt.Sprintf("%s运行时模块'%s'，业务名: %s", action, table.FunctionName, table.BusinessName), true)
+	response.SuccessWithMessage(ctx, action+"成功")
+}
+
+// SynchDbDrift 查询表结构差异 对应Java后端的synchDb方法
+// @Summary 查询表结构差异
+// @Description 对比业务表与数据库表结构，返回新增、删除、类型变化和注释变化的列，不修改业务表
+// @Tags 代码生成
+// @Produce json
+// @Param tableName path string true "表名"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /tool/gen/synchDb/{tableName} [get]
+func (c *GenController) SynchDbDrift(ctx *gin.Context) {
+	genService := c.genService.WithContext(ctx.Request.Context())
+	tableName := ctx.Param("tableName")
+	if tableName == "" {
+		response.ErrorWithMessage(ctx, "表名不能为空")
+		return
+	}
+
+	drift, err := genService.DetectDrift(tableName)
+	if err != nil {
+		fmt.Printf("GenController.SynchDbDrift: 查询表结构差异失败: %v\n", err)
+		response.ErrorWithMessage(ctx, "查询表结构差异失败，"+err.Error())
+		return
+	}
+	response.SuccessWithData(ctx, drift)
 }
 
 // SynchDb 同步数据库 对应Java后端的synchDb方法
 // @Summary 同步数据库
-// @Description 同步数据库表结构
+// @Description 选择性应用表结构差异，未选中和没有变化的字段保留原有的生成配置
 // @Tags 代码生成
 // @Accept json
 // @Produce json
 // @Param tableName path string true "表名"
+// @Param data body model.GenSynchRequest true "要同步的列"
 // @Security ApiKeyAuth
 // @Success 200 {object} response.Result
-// @Router /tool/gen/synchDb/{tableName} [get]
+// @Router /tool/gen/synchDb/{tableName} [post]
 func (c *GenController) SynchDb(ctx *gin.Context) {
+	genService := c.genService.WithContext(ctx.Request.Context())
 	fmt.Printf("GenController.SynchDb: 同步数据库\n")
 
 	tableName := ctx.Param("tableName")
@@ -335,16 +556,48 @@ func (c *GenController) SynchDb(ctx *gin.Context) {
 		response.ErrorWithMessage(ctx, "表名不能为空")
 		return
 	}
+	var req model.GenSynchRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ErrorWithMessage(ctx, "参数错误")
+		return
+	}
+
+	operName := ""
+	if username, exists := ctx.Get("username"); exists {
+		operName = fmt.Sprintf("%v", username)
+	}
 
 	// 同步数据库
-	if err := c.genService.SynchDb(tableName); err != nil {
+	drift, err := genService.SynchDb(tableName, &req, operName)
+	if err != nil {
 		fmt.Printf("GenController.SynchDb: 同步数据库失败: %v\n", err)
-		response.ErrorWithMessage(ctx, "同步数据库失败")
+		operlog.RecordOperLog(ctx, "代码生成", "修改", fmt.Sprintf("同步表'%s'失败: %s", tableName, err.Error()), false)
+		response.ErrorWithMessage(ctx, "同步数据库失败，"+err.Error())
 		return
 	}
 
 	fmt.Printf("GenController.SynchDb: 同步数据库成功, TableName=%s\n", tableName)
-	response.SuccessWithMessage(ctx, "同步成功")
+	operlog.RecordOperLog(ctx, "代码生成", "修改", fmt.Sprintf("同步表'%s'，列: %v，表描述: %t", tableName, req.Columns, req.TableComment), true)
+	response.SuccessWithData(ctx, drift)
+}
+
+// ScanDrift 扫描表结构差异
+// @Summary 扫描表结构差异
+// @Description 扫描所有已导入的业务表，返回与数据库表结构不一致或数据库表已删除的表
+// @Tags 代码生成
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /tool/gen/drift [get]
+func (c *GenController) ScanDrift(ctx *gin.Context) {
+	genService := c.genService.WithContext(ctx.Request.Context())
+	drifts, err := genService.ScanDrift()
+	if err != nil {
+		fmt.Printf("GenController.ScanDrift: 扫描表结构差异失败: %v\n", err)
+		response.ErrorWithMessage(ctx, "扫描表结构差异失败")
+		return
+	}
+	response.SuccessWithData(ctx, drifts)
 }
 
 // Download 生成代码（下载方式） 对应Java后端的download方法
@@ -358,6 +611,7 @@ func (c *GenController) SynchDb(ctx *gin.Context) {
 // @Success 200 {file} file "zip文件"
 // @Router /tool/gen/download/{tableName} [get]
 func (c *GenController) Download(ctx *gin.Context) {
+	genService := c.genService.WithContext(ctx.Request.Context())
 	fmt.Printf("GenController.Download: 生成代码下载\n")
 
 	tableName := ctx.Param("tableName")
@@ -367,7 +621,7 @@ func (c *GenController) Download(ctx *gin.Context) {
 	}
 
 	// 生成代码并获取zip数据
-	zipData, err := c.genService.DownloadCode(tableName)
+	zipData, err := genService.DownloadCode(tableName)
 	if err != nil {
 		fmt.Printf("GenController.Download: 生成代码下载失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "生成代码下载失败")
@@ -398,6 +652,7 @@ func (c *GenController) Download(ctx *gin.Context) {
 // @Success 200 {file} file "zip文件"
 // @Router /tool/gen/batchGenCode [get]
 func (c *GenController) BatchGenCode(ctx *gin.Context) {
+	genService := c.genService.WithContext(ctx.Request.Context())
 	fmt.Printf("GenController.BatchGenCode: 批量生成代码\n")
 
 	tablesStr := ctx.Query("tables")
@@ -413,7 +668,7 @@ func (c *GenController) BatchGenCode(ctx *gin.Context) {
 	}
 
 	// 批量生成代码并获取zip数据
-	zipData, err := c.genService.BatchDownloadCode(tableNames)
+	zipData, err := genService.BatchDownloadCode(tableNames)
 	if err != nil {
 		fmt.Printf("GenController.BatchGenCode: 批量生成代码失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "批量生成代码失败")
@@ -433,37 +688,195 @@ func (c *GenController) BatchGenCode(ctx *gin.Context) {
 	fmt.Printf("GenController.BatchGenCode: 批量生成代码成功, TableNames=%v\n", tableNames)
 }
 
+// genCreateTableRequest 建表请求：表定义和手写SQL二选一，确认执行时需要回传预览返回的令牌
+type genCreateTableRequest struct {
+	Table *ddl.Table `json:"table"`
+	Sql   string     `json:"sql"`
+	Token string     `json:"token"`
+}
+
+// PreviewCreateTable 预览建表语句
+// @Summary 预览建表语句
+// @Description 将表定义渲染为当前数据库方言的DDL，或校验手写的建表SQL（只允许CREATE TABLE），返回DDL和确认令牌
+// @Tags 代码生成
+// @Accept json
+// @Produce json
+// @Param data body genCreateTableRequest true "表定义或建表SQL"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /tool/gen/createTable/preview [post]
+func (c *GenController) PreviewCreateTable(ctx *gin.Context) {
+	genService := c.genService.WithContext(ctx.Request.Context())
+	var req genCreateTableRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ErrorWithMessage(ctx, "参数错误")
+		return
+	}
+
+	plan, err := genService.PlanCreateTable(req.Table, req.Sql)
+	if err != nil {
+		response.ErrorWithMessage(ctx, err.Error())
+		return
+	}
+	response.SuccessWithData(ctx, plan)
+}
+
 // CreateTable 创建表结构
 // @Summary 创建表结构
-// @Description 根据SQL语句创建表结构
+// @Description 执行预览过的建表语句并导入代码生成，令牌与当前DDL不一致时拒绝执行
 // @Tags 代码生成
 // @Accept json
 // @Produce json
-// @Param sql query string true "SQL语句"
+// @Param data body genCreateTableRequest true "表定义或建表SQL及确认令牌"
 // @Security ApiKeyAuth
 // @Success 200 {object} response.Result
 // @Router /tool/gen/createTable [post]
 func (c *GenController) CreateTable(ctx *gin.Context) {
+	genService := c.genService.WithContext(ctx.Request.Context())
 	fmt.Printf("GenController.CreateTable: 创建表结构\n")
 
-	sql := ctx.Query("sql")
-	if sql == "" {
-		response.ErrorWithMessage(ctx, "SQL语句不能为空")
+	var req genCreateTableRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ErrorWithMessage(ctx, "参数错误")
 		return
 	}
 
-	// 获取当前用户信息
-	operName := "admin" // TODO: 从token中获取用户名
+	operName := ""
+	if username, exists := ctx.Get("username"); exists {
+		operName = fmt.Sprintf("%v", username)
+	}
 
-	err := c.genService.CreateTable(sql, operName)
+	plan, err := genService.CreateTable(req.Table, req.Sql, req.Token, operName)
 	if err != nil {
 		fmt.Printf("GenController.CreateTable: 创建表结构失败: %v\n", err)
+		operlog.RecordOperLog(ctx, "代码生成", "新增", fmt.Sprintf("创建表结构失败: %s", err.Error()), false)
 		response.ErrorWithMessage(ctx, "创建表结构失败: "+err.Error())
 		return
 	}
 
 	fmt.Printf("GenController.CreateTable: 创建表结构成功\n")
-	response.Success(ctx)
+	operlog.RecordOperLog(ctx, "代码生成", "新增", fmt.Sprintf("创建表结构%v:\n%s", plan.TableNames, strings.Join(plan.Statements, ";\n")), true)
+	response.SuccessWithMessage(ctx, "创建成功")
+}
+
+// genDefinitionMaxSize 表定义文件的最大大小
+const genDefinitionMaxSize = 2 << 20
+
+// DefinitionTemplate 下载表定义模板
+// @Summary 下载表定义模板
+// @Description 下载Excel或JSON Schema格式的表定义模板，lang为en时使用英文表头和注释
+// @Tags 代码生成
+// @Produce application/octet-stream
+// @Param format formData string false "模板格式：excel（默认）或json"
+// @Param lang formData string false "模板语言：zh（默认）或en"
+// @Security ApiKeyAuth
+// @Success 200 {file} file
+// @Router /tool/gen/definition/template [post]
+func (c *GenController) DefinitionTemplate(ctx *gin.Context) {
+	lang := ctx.DefaultPostForm("lang", ctx.Query("lang"))
+	if ctx.DefaultPostForm("format", ctx.Query("format")) == "json" {
+		filename := "表定义模板.schema.json"
+		ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename*=UTF-8''%s", url.QueryEscape(filename)))
+		// 不使用 application/json，前端下载时会将其当作错误响应
+		ctx.Data(200, "application/schema+json", toolService.DefinitionSchemaTemplate(lang))
+		return
+	}
+
+	templateData, err := toolService.DefinitionExcelTemplate(lang)
+	if err != nil {
+		fmt.Printf("GenController.DefinitionTemplate: 生成模板失败: %v\n", err)
+		response.ErrorWithMessage(ctx, "生成模板失败: "+err.Error())
+		return
+	}
+	filename := fmt.Sprintf("表定义导入模板_%s.xlsx", time.Now().Format("20060102_150405"))
+	ctx.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename*=UTF-8''%s", url.QueryEscape(filename)))
+	ctx.Header("Content-Length", strconv.Itoa(len(templateData)))
+	ctx.Data(200, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", templateData)
+}
+
+// ParseDefinition 解析表定义文件
+// @Summary 解析表定义文件
+// @Description 解析上传的Excel（.xlsx）或JSON Schema（.json）表定义，返回表定义和逐行的校验错误，不保存任何数据
+// @Tags 代码生成
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "表定义文件"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /tool/gen/definition/parse [post]
+func (c *GenController) ParseDefinition(ctx *gin.Context) {
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		response.ErrorWithMessage(ctx, "请选择要导入的文件")
+		return
+	}
+	if file.Size > genDefinitionMaxSize {
+		response.ErrorWithMessage(ctx, "文件大小不能超过2MB")
+		return
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		response.ErrorWithMessage(ctx, "文件打开失败")
+		return
+	}
+	defer src.Close()
+	data, err := io.ReadAll(src)
+	if err != nil {
+		response.ErrorWithMessage(ctx, "文件读取失败")
+		return
+	}
+
+	var definition *toolService.GenDefinition
+	switch strings.ToLower(filepath.Ext(file.Filename)) {
+	case ".xlsx":
+		definition, err = toolService.ParseDefinitionExcel(data)
+	case ".json":
+		definition, err = toolService.ParseDefinitionSchema(data)
+	default:
+		response.ErrorWithMessage(ctx, "仅支持xlsx和json格式的表定义文件")
+		return
+	}
+	if err != nil {
+		response.ErrorWithMessage(ctx, "文件解析失败: "+err.Error())
+		return
+	}
+	response.SuccessWithData(ctx, definition)
+}
+
+// ImportDefinition 导入表定义
+// @Summary 导入表定义
+// @Description 将表定义导入代码生成，不创建数据库表；需要建表时使用创建表接口
+// @Tags 代码生成
+// @Accept json
+// @Produce json
+// @Param data body ddl.Table true "表定义"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /tool/gen/definition/import [post]
+func (c *GenController) ImportDefinition(ctx *gin.Context) {
+	genService := c.genService.WithContext(ctx.Request.Context())
+	var table ddl.Table
+	if err := ctx.ShouldBindJSON(&table); err != nil {
+		response.ErrorWithMessage(ctx, "参数错误")
+		return
+	}
+
+	operName := ""
+	if username, exists := ctx.Get("username"); exists {
+		operName = fmt.Sprintf("%v", username)
+	}
+
+	genTable, err := genService.ImportDefinition(&table, operName)
+	if err != nil {
+		operlog.RecordOperLog(ctx, "代码生成", "导入", fmt.Sprintf("导入表定义失败: %s", err.Error()), false)
+		response.ErrorWithMessage(ctx, "导入表定义失败，"+err.Error())
+		return
+	}
+
+	operlog.RecordOperLog(ctx, "代码生成", "导入", fmt.Sprintf("导入表定义%s，共%d列", genTable.Name, len(genTable.Columns)), true)
+	response.SuccessWithMessage(ctx, "导入成功")
 }
 
 // ColumnList 查询表字段列表 对应Java后端的columnList方法
@@ -477,6 +890,7 @@ func (c *GenController) CreateTable(ctx *gin.Context) {
 // @Success 200 {object} response.Result
 // @Router /tool/gen/column/{tableId} [get]
 func (c *GenController) ColumnList(ctx *gin.Context) {
+	genService := c.genService.WithContext(ctx.Request.Context())
 	fmt.Printf("GenController.ColumnList: 查询表字段列表\n")
 
 	tableIdStr := ctx.Param("tableId")
@@ -487,7 +901,7 @@ func (c *GenController) ColumnList(ctx *gin.Context) {
 	}
 
 	// 查询表字段列表
-	columns, err := c.genService.SelectGenTableColumnListByTableId(tableId)
+	columns, err := genService.SelectGenTableColumnListByTableId(tableId)
 	if err != nil {
 		fmt.Printf("GenController.ColumnList: 查询表字段列表失败: %v\n", err)
 		response.ErrorWithMessage(ctx, "查询表字段列表失败")
@@ -0,0 +1,377 @@
+package tool
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"wosm/internal/repository/model"
+	toolService "wosm/internal/service/tool"
+	"wosm/pkg/operlog"
+	"wosm/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GenTemplateController 代码生成模板组控制器
+type GenTemplateController struct {
+	templateService *toolService.GenTemplateService
+	genService      *toolService.GenService
+}
+
+// NewGenTemplateController 创建代码生成模板组控制器实例
+func NewGenTemplateController() *GenTemplateController {
+	return &GenTemplateController{
+		templateService: toolService.NewGenTemplateService(),
+		genService:      toolService.NewGenService(),
+	}
+}
+
+// genTemplateRequest 保存模板请求
+type genTemplateRequest struct {
+	SetID        int64  `json:"setId"`        // 模板组ID
+	TemplateName string `json:"templateName"` // 模板名称
+	Content      string `json:"content"`      // 模板内容
+}
+
+// genTemplatePreviewRequest 预览模板请求
+type genTemplatePreviewRequest struct {
+	TableID      int64  `json:"tableId"`      // 业务表ID
+	TemplateName string `json:"templateName"` // 模板名称
+	Content      string `json:"content"`      // 模板内容
+}
+
+// List 查询模板组列表
+// @Summary 查询代码生成模板组列表
+// @Tags 代码生成
+// @Produce json
+// @Param setName query string false "模板组名称"
+// @Param status query string false "状态"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /tool/gen/template/list [get]
+func (c *GenTemplateController) List(ctx *gin.Context) {
+	templateService := c.templateService.WithContext(ctx.Request.Context())
+	set := &model.GenTemplateSet{
+		SetName: ctx.Query("setName"),
+		Status:  ctx.Query("status"),
+	}
+
+	sets, err := templateService.SelectTemplateSetList(set)
+	if err != nil {
+		response.ErrorWithMessage(ctx, "查询模板组列表失败")
+		return
+	}
+
+	response.Page(ctx, int64(len(sets)), sets)
+}
+
+// GetInfo 获取模板组详细信息（包含模板）
+// @Summary 获取代码生成模板组详细信息
+// @Tags 代码生成
+// @Produce json
+// @Param setId path int true "模板组ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /tool/gen/template/{setId} [get]
+func (c *GenTemplateController) GetInfo(ctx *gin.Context) {
+	templateService := c.templateService.WithContext(ctx.Request.Context())
+	setId, err := strconv.ParseInt(ctx.Param("setId"), 10, 64)
+	if err != nil {
+		response.ErrorWithMessage(ctx, "模板组ID格式错误")
+		return
+	}
+
+	set, err := templateService.SelectTemplateSetById(setId)
+	if err != nil {
+		response.ErrorWithMessage(ctx, "查询模板组详情失败")
+		return
+	}
+	if set == nil {
+		response.ErrorWithMessage(ctx, "模板组不存在")
+		return
+	}
+
+	response.SuccessWithData(ctx, set)
+}
+
+// Builtin 查询内置模板，作为编辑自定义模板的起点
+// @Summary 查询内置代码生成模板
+// @Tags 代码生成
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /tool/gen/template/builtin [get]
+func (c *GenTemplateController) Builtin(ctx *gin.Context) {
+	templateService := c.templateService.WithContext(ctx.Request.Context())
+	templates, err := templateService.SelectBuiltinTemplates()
+	if err != nil {
+		response.ErrorWithMessage(ctx, err.Error())
+		return
+	}
+
+	response.SuccessWithData(ctx, templates)
+}
+
+// Add 新增模板组
+// @Summary 新增代码生成模板组
+// @Tags 代码生成
+// @Accept json
+// @Produce json
+// @Param set body model.GenTemplateSet true "模板组信息"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /tool/gen/template [post]
+func (c *GenTemplateController) Add(ctx *gin.Context) {
+	templateService := c.templateService.WithContext(ctx.Request.Context())
+	var set model.GenTemplateSet
+	if err := ctx.ShouldBindJSON(&set); err != nil {
+		response.ErrorWithMessage(ctx, "参数错误")
+		return
+	}
+
+	if username, exists := ctx.Get("username"); exists {
+		set.CreateBy = fmt.Sprintf("%v", username)
+	}
+
+	if err := templateService.InsertTemplateSet(&set); err != nil {
+		operlog.RecordOperLog(ctx, "代码生成模板", "新增", fmt.Sprintf("新增模板组'%s'失败: %s", set.SetName, err.Error()), false)
+		response.ErrorWithMessage(ctx, fmt.Sprintf("新增模板组'%s'失败，%s", set.SetName, err.Error()))
+		return
+	}
+
+	operlog.RecordOperLog(ctx, "代码生成模板", "新增", fmt.Sprintf("新增模板组'%s'", set.SetName), true)
+	response.SuccessWithData(ctx, set)
+}
+
+// Edit 修改模板组基本信息
+// @Summary 修改代码生成模板组
+// @Tags 代码生成
+// @Accept json
+// @Produce json
+// @Param set body model.GenTemplateSet true "模板组信息"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /tool/gen/template [put]
+func (c *GenTemplateController) Edit(ctx *gin.Context) {
+	templateService := c.templateService.WithContext(ctx.Request.Context())
+	var set model.GenTemplateSet
+	if err := ctx.ShouldBindJSON(&set); err != nil {
+		response.ErrorWithMessage(ctx, "参数错误")
+		return
+	}
+
+	if username, exists := ctx.Get("username"); exists {
+		set.UpdateBy = fmt.Sprintf("%v", username)
+	}
+
+	if err := templateService.UpdateTemplateSet(&set); err != nil {
+		operlog.RecordOperLog(ctx, "代码生成模板", "修改", fmt.Sprintf("修改模板组'%s'失败: %s", set.SetName, err.Error()), false)
+		response.ErrorWithMessage(ctx, fmt.Sprintf("修改模板组'%s'失败，%s", set.SetName, err.Error()))
+		return
+	}
+
+	operlog.RecordOperLog(ctx, "代码生成模板", "修改", fmt.Sprintf("修改模板组'%s'", set.SetName), true)
+	response.SuccessWithMessage(ctx, "修改成功")
+}
+
+// Remove 删除模板组
+// @Summary 删除代码生成模板组
+// @Tags 代码生成
+// @Produce json
+// @Param setIds path string true "模板组ID列表，多个用逗号分隔"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /tool/gen/template/{setIds} [delete]
+func (c *GenTemplateController) Remove(ctx *gin.Context) {
+	templateService := c.templateService.WithContext(ctx.Request.Context())
+	var setIds []int64
+	for _, idStr := range strings.Split(ctx.Param("setIds"), ",") {
+		id, err := strconv.ParseInt(strings.TrimSpace(idStr), 10, 64)
+		if err != nil {
+			response.ErrorWithMessage(ctx, "模板组ID格式错误")
+			return
+		}
+		setIds = append(setIds, id)
+	}
+
+	if err := templateService.DeleteTemplateSetByIds(setIds); err != nil {
+		operlog.RecordOperLog(ctx, "代码生成模板", "删除", fmt.Sprintf("删除模板组失败: %s", err.Error()), false)
+		response.ErrorWithMessage(ctx, err.Error())
+		return
+	}
+
+	operlog.RecordOperLog(ctx, "代码生成模板", "删除", fmt.Sprintf("删除模板组，ID: %v", setIds), true)
+	response.SuccessWithMessage(ctx, "删除成功")
+}
+
+// SaveContent 保存模板组中的模板，保存前校验模板语法
+// @Summary 保存代码生成模板
+// @Tags 代码生成
+// @Accept json
+// @Produce json
+// @Param template body genTemplateRequest true "模板信息"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /tool/gen/template/content [put]
+func (c *GenTemplateController) SaveContent(ctx *gin.Context) {
+	templateService := c.templateService.WithContext(ctx.Request.Context())
+	var req genTemplateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ErrorWithMessage(ctx, "参数错误")
+		return
+	}
+
+	operName := ""
+	if username, exists := ctx.Get("username"); exists {
+		operName = fmt.Sprintf("%v", username)
+	}
+
+	tpl, err := templateService.SaveTemplate(req.SetID, req.TemplateName, req.Content, operName)
+	if err != nil {
+		operlog.RecordOperLog(ctx, "代码生成模板", "修改", fmt.Sprintf("保存模板'%s'失败: %s", req.TemplateName, err.Error()), false)
+		response.ErrorWithMessage(ctx, fmt.Sprintf("保存模板'%s'失败，%s", req.TemplateName, err.Error()))
+		return
+	}
+
+	operlog.RecordOperLog(ctx, "代码生成模板", "修改", fmt.Sprintf("保存模板'%s'，版本%d", tpl.TemplateName, tpl.Version), true)
+	response.SuccessWithData(ctx, tpl)
+}
+
+// RemoveContent 删除模板组中的模板，删除后恢复使用内置模板
+// @Summary 删除代码生成模板
+// @Tags 代码生成
+// @Produce json
+// @Param templateId path int true "模板ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /tool/gen/template/content/{templateId} [delete]
+func (c *GenTemplateController) RemoveContent(ctx *gin.Context) {
+	templateService := c.templateService.WithContext(ctx.Request.Context())
+	templateId, err := strconv.ParseInt(ctx.Param("templateId"), 10, 64)
+	if err != nil {
+		response.ErrorWithMessage(ctx, "模板ID格式错误")
+		return
+	}
+
+	if err := templateService.DeleteTemplate(templateId); err != nil {
+		operlog.RecordOperLog(ctx, "代码生成模板", "删除", fmt.Sprintf("删除模板失败: %s", err.Error()), false)
+		response.ErrorWithMessage(ctx, err.Error())
+		return
+	}
+
+	operlog.RecordOperLog(ctx, "代码生成模板", "删除", fmt.Sprintf("删除模板，ID: %d", templateId), true)
+	response.SuccessWithMessage(ctx, "删除成功")
+}
+
+// History 查询模板的历史版本
+// @Summary 查询代码生成模板历史版本
+// @Tags 代码生成
+// @Produce json
+// @Param templateId path int true "模板ID"
+// @Param version query int false "版本号，指定时返回该版本的内容"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /tool/gen/template/history/{templateId} [get]
+func (c *GenTemplateController) History(ctx *gin.Context) {
+	templateService := c.templateService.WithContext(ctx.Request.Context())
+	templateId, err := strconv.ParseInt(ctx.Param("templateId"), 10, 64)
+	if err != nil {
+		response.ErrorWithMessage(ctx, "模板ID格式错误")
+		return
+	}
+
+	if versionStr := ctx.Query("version"); versionStr != "" {
+		version, err := strconv.Atoi(versionStr)
+		if err != nil {
+			response.ErrorWithMessage(ctx, "版本号格式错误")
+			return
+		}
+		history, err := templateService.SelectTemplateHistoryByVersion(templateId, version)
+		if err != nil {
+			response.ErrorWithMessage(ctx, "查询模板历史失败")
+			return
+		}
+		if history == nil {
+			response.ErrorWithMessage(ctx, "版本不存在")
+			return
+		}
+		response.SuccessWithData(ctx, history)
+		return
+	}
+
+	histories, err := templateService.SelectTemplateHistory(templateId)
+	if err != nil {
+		response.ErrorWithMessage(ctx, "查询模板历史失败")
+		return
+	}
+
+	response.SuccessWithData(ctx, histories)
+}
+
+// Rollback 将模板回滚到指定版本
+// @Summary 回滚代码生成模板
+// @Tags 代码生成
+// @Produce json
+// @Param templateId path int true "模板ID"
+// @Param version path int true "版本号"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /tool/gen/template/rollback/{templateId}/{version} [put]
+func (c *GenTemplateController) Rollback(ctx *gin.Context) {
+	templateService := c.templateService.WithContext(ctx.Request.Context())
+	templateId, err := strconv.ParseInt(ctx.Param("templateId"), 10, 64)
+	if err != nil {
+		response.ErrorWithMessage(ctx, "模板ID格式错误")
+		return
+	}
+	version, err := strconv.Atoi(ctx.Param("version"))
+	if err != nil {
+		response.ErrorWithMessage(ctx, "版本号格式错误")
+		return
+	}
+
+	operName := ""
+	if username, exists := ctx.Get("username"); exists {
+		operName = fmt.Sprintf("%v", username)
+	}
+
+	tpl, err := templateService.RollbackTemplate(templateId, version, operName)
+	if err != nil {
+		operlog.RecordOperLog(ctx, "代码生成模板", "修改", fmt.Sprintf("回滚模板失败: %s", err.Error()), false)
+		response.ErrorWithMessage(ctx, err.Error())
+		return
+	}
+
+	operlog.RecordOperLog(ctx, "代码生成模板", "修改", fmt.Sprintf("回滚模板'%s'到版本%d", tpl.TemplateName, version), true)
+	response.SuccessWithData(ctx, tpl)
+}
+
+// Preview 使用模板内容预览指定业务表的生成结果，不保存模板
+// @Summary 预览代码生成模板
+// @Tags 代码生成
+// @Accept json
+// @Produce json
+// @Param preview body genTemplatePreviewRequest true "预览参数"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Result
+// @Router /tool/gen/template/preview [post]
+func (c *GenTemplateController) Preview(ctx *gin.Context) {
+	genService := c.genService.WithContext(ctx.Request.Context())
+	var req genTemplatePreviewRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ErrorWithMessage(ctx, "参数错误")
+		return
+	}
+	if req.TableID == 0 {
+		response.ErrorWithMessage(ctx, "请选择预览的业务表")
+		return
+	}
+
+	codeMap, err := genService.PreviewTemplate(req.TableID, req.TemplateName, req.Content)
+	if err != nil {
+		response.ErrorWithMessage(ctx, err.Error())
+		return
+	}
+
+	response.SuccessWithData(ctx, codeMap)
+}
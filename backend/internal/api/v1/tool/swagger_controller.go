@@ -1,8 +1,13 @@
 package tool
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"wosm/internal/config"
 
 	"github.com/gin-gonic/gin"
 )
@@ -210,9 +215,54 @@ func (c *SwaggerController) generateSwaggerSpec(ctx *gin.Context) *SwaggerSpec {
 		},
 	}
 
+	c.mergeFragments(spec, config.GetOpenAPIDir())
+
 	return spec
 }
 
+// openAPIFragment 代码生成器输出的接口文档片段
+type openAPIFragment struct {
+	Paths       map[string]interface{} `json:"paths"`
+	Definitions map[string]interface{} `json:"definitions"`
+}
+
+// mergeFragments 合并目录下所有接口文档片段（*.json），格式错误的文件和已存在的路径、模型会被跳过
+func (c *SwaggerController) mergeFragments(spec *SwaggerSpec, dir string) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		fmt.Printf("SwaggerController.mergeFragments: 读取接口文档片段目录失败: %v\n", err)
+		return
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			fmt.Printf("SwaggerController.mergeFragments: 读取接口文档片段失败: %s, %v\n", file, err)
+			continue
+		}
+		var fragment openAPIFragment
+		if err := json.Unmarshal(data, &fragment); err != nil {
+			fmt.Printf("SwaggerController.mergeFragments: 接口文档片段格式错误: %s, %v\n", file, err)
+			continue
+		}
+		for path, item := range fragment.Paths {
+			if _, ok := spec.Paths[path]; ok {
+				fmt.Printf("SwaggerController.mergeFragments: 路径已存在，跳过: %s, %s\n", file, path)
+				continue
+			}
+			spec.Paths[path] = item
+		}
+		for name, definition := range fragment.Definitions {
+			if _, ok := spec.Definitions[name]; ok {
+				fmt.Printf("SwaggerController.mergeFragments: 模型已存在，跳过: %s, %s\n", file, name)
+				continue
+			}
+			spec.Definitions[name] = definition
+		}
+	}
+}
+
 // generatePaths 生成API路径信息 对应Java后端的RequestMappingHandlerMapping
 func (c *SwaggerController) generatePaths() map[string]interface{} {
 	paths := make(map[string]interface{})
@@ -0,0 +1,52 @@
+package tool
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeFragments(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"biz_notice.json": `{"paths": {"/biz/notice/list": {"get": {}}, "/login": {"post": {}}}, "definitions": {"BizNotice": {"type": "object"}, "AjaxResult": {}}}`,
+		"invalid.json":    `{"paths": `,
+		"readme.txt":      `{"paths": {"/ignored": {}}}`,
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+
+	c := NewSwaggerController()
+	spec := &SwaggerSpec{Paths: c.generatePaths(), Definitions: c.generateDefinitions()}
+	login := spec.Paths["/login"]
+	ajaxResult := spec.Definitions["AjaxResult"]
+	c.mergeFragments(spec, dir)
+
+	tests := []struct {
+		name  string
+		found bool
+	}{
+		{"/biz/notice/list", true},
+		{"/ignored", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ok := spec.Paths[tt.name]
+			assert.Equal(t, tt.found, ok)
+		})
+	}
+	assert.Contains(t, spec.Definitions, "BizNotice")
+
+	// 已存在的路径和模型不会被片段覆盖
+	assert.Equal(t, login, spec.Paths["/login"])
+	assert.Equal(t, ajaxResult, spec.Definitions["AjaxResult"])
+
+	// 目录不存在时保持原样
+	total := len(spec.Paths)
+	c.mergeFragments(spec, filepath.Join(dir, "missing"))
+	assert.Len(t, spec.Paths, total)
+}
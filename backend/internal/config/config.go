@@ -16,7 +16,11 @@ type Config struct {
 	Log      LogConfig      `yaml:"log"`
 	Captcha  CaptchaConfig  `yaml:"captcha"`
 	File     FileConfig     `yaml:"file"`
-	User     UserConfig     `yaml:"user"` // 用户配置 对应Java后端的user配置
+	User     UserConfig     `yaml:"user"`    // 用户配置 对应Java后端的user配置
+	Job      JobConfig      `yaml:"job"`     // 定时任务配置
+	Gen      GenConfig      `yaml:"gen"`     // 代码生成配置
+	Recycle  RecycleConfig  `yaml:"recycle"` // 回收站配置
+	Tenant   TenantConfig   `yaml:"tenant"`  // 多租户配置
 }
 
 // ServerConfig 服务器配置
@@ -39,6 +43,23 @@ type DatabaseConfig struct {
 	ConnMaxLifetime int    `yaml:"conn_max_lifetime"`  // 连接最大生存时间（秒）
 	ConnMaxIdleTime int    `yaml:"conn_max_idle_time"` // 连接最大空闲时间（秒）
 	PingTimeout     int    `yaml:"ping_timeout"`       // 连接测试超时时间（秒）
+
+	SlowSQLMillis      int `yaml:"slow_sql_millis"`      // 慢SQL阈值（毫秒），0使用默认值1000，负数关闭
+	RepeatSQLThreshold int `yaml:"repeat_sql_threshold"` // 单次请求中同一SQL执行次数超过该值时告警（N+1查询），0使用默认值10，负数关闭
+
+	AutoMigrate bool `yaml:"auto_migrate"` // 启动时自动执行未执行的数据库迁移（pkg/migrate），也可以手工执行 migrate up
+
+	Replicas             []ReplicaConfig `yaml:"replicas"`               // 只读副本，配置后查询读副本，写入和事务使用主库
+	ReplicaPolicy        string          `yaml:"replica_policy"`         // 副本负载均衡策略：random（按权重随机，默认）、round_robin（轮询）、least_conn（最少活跃连接）
+	ReplicaCheckInterval int             `yaml:"replica_check_interval"` // 副本健康检查间隔（秒），0使用默认值10
+	ReplicaStickySeconds int             `yaml:"replica_sticky_seconds"` // 客户端写请求之后读主库的时间（秒），避免读不到刚写入的数据，0使用默认值5，负数关闭
+}
+
+// ReplicaConfig 只读副本配置
+type ReplicaConfig struct {
+	Name   string `yaml:"name"`   // 副本名称，用于日志和监控，为空时为 replica-序号
+	DSN    string `yaml:"dsn"`    // 连接串，格式与主库的驱动一致（sqlite为数据库文件路径）
+	Weight int    `yaml:"weight"` // 权重，random策略使用，0为1
 }
 
 // RedisConfig Redis配置
@@ -98,6 +119,56 @@ type PasswordConfig struct {
 	LockTime      int `yaml:"lock_time"`       // 密码锁定时间（分钟）
 }
 
+// JobConfig 定时任务配置
+type JobConfig struct {
+	HttpAllowedHosts []string `yaml:"http_allowed_hosts"` // HTTP任务允许访问的主机（host或host:port），为空时不限制
+	SqlWhitelist     []string `yaml:"sql_whitelist"`      // SQL任务允许执行的语句或存储过程名称
+}
+
+// GenConfig 代码生成配置 对应Java后端的generator.yml
+type GenConfig struct {
+	ProjectRoot string `yaml:"project_root"` // 生成到自定义路径时的项目根目录，gen_path 只能位于该目录下，为空时不允许生成到路径
+	RouterFile  string `yaml:"router_file"`  // 自动注册路由时写入的路由文件（相对生成路径）
+	OpenAPIDir  string `yaml:"openapi_dir"`  // 生成的接口文档片段目录（相对项目根目录），请求Swagger文档时合并
+}
+
+// DefaultOpenAPIDir 未配置 openapi_dir 时使用的接口文档片段目录
+const DefaultOpenAPIDir = "docs/openapi"
+
+// GetOpenAPIDir 获取接口文档片段目录，未加载配置时使用默认目录
+func GetOpenAPIDir() string {
+	if AppConfig == nil || AppConfig.Gen.OpenAPIDir == "" {
+		return DefaultOpenAPIDir
+	}
+	return AppConfig.Gen.OpenAPIDir
+}
+
+// RecycleConfig 回收站配置
+type RecycleConfig struct {
+	RetentionDays int `yaml:"retention_days"` // 保留天数，超过后由清理任务彻底删除，为0时使用默认值，负数不自动清理
+}
+
+// DefaultRecycleRetentionDays 未配置 retention_days 时回收站数据的保留天数
+const DefaultRecycleRetentionDays = 30
+
+// GetRecycleRetentionDays 获取回收站保留天数，未加载配置时使用默认值
+func GetRecycleRetentionDays() int {
+	if AppConfig == nil || AppConfig.Recycle.RetentionDays == 0 {
+		return DefaultRecycleRetentionDays
+	}
+	return AppConfig.Recycle.RetentionDays
+}
+
+// TenantConfig 多租户配置
+type TenantConfig struct {
+	Enabled bool `yaml:"enabled"` // 开启租户模式，开启前需要执行 0005_tenant 迁移；关闭时所有数据属于平台租户
+}
+
+// IsTenantEnabled 是否开启租户模式，未加载配置时不开启
+func IsTenantEnabled() bool {
+	return AppConfig != nil && AppConfig.Tenant.Enabled
+}
+
 var AppConfig *Config
 
 // LoadConfig 加载配置文件
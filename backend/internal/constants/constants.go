@@ -104,6 +104,9 @@ const (
 	REPEAT_SUBMIT_KEY = "repeat_submit:" // 防重提交 redis key
 	RATE_LIMIT_KEY    = "rate_limit:"    // 限流 redis key
 	PWD_ERR_CNT_KEY   = "pwd_err_cnt:"   // 登录账户密码错误次数 redis key
+
+	JOB_LOG_STREAM_KEY        = "job_log_stream:" // 任务实时日志 redis key
+	JOB_LOG_STREAM_END_SUFFIX = ":end"            // 任务实时日志结束标记 redis key 后缀
 )
 
 // 错误消息常量 对应Java后端的messages.properties
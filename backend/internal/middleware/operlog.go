@@ -138,7 +138,7 @@ func recordOperationLog(ctx *gin.Context, config OperLogConfig, requestBody, res
 	}
 
 	// 保存操作日志
-	operLogService := system.NewOperLogService()
+	operLogService := system.NewOperLogService().WithContext(ctx.Request.Context())
 	if err := operLogService.InsertOperLog(operLog); err != nil {
 		fmt.Printf("RecordOperLog: 保存操作日志失败: %v\n", err)
 	} else {
@@ -1,6 +1,8 @@
 package dao
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"wosm/internal/repository/model"
 	"wosm/pkg/database"
@@ -20,6 +22,11 @@ func NewConfigDao() *ConfigDao {
 	}
 }
 
+// WithContext 返回使用指定context的数据访问层实例，语句的context携带请求信息和租户
+func (d *ConfigDao) WithContext(ctx context.Context) *ConfigDao {
+	return &ConfigDao{db: d.db.WithContext(ctx)}
+}
+
 // SelectConfigById 根据参数ID查询参数配置信息 对应Java后端的selectConfigById
 func (d *ConfigDao) SelectConfigById(configId int64) (*model.SysConfig, error) {
 	fmt.Printf("ConfigDao.SelectConfigById: 查询参数配置信息, ConfigId=%d\n", configId)
@@ -164,8 +171,9 @@ func (d *ConfigDao) CountConfigList(params *model.ConfigQueryParams) (int64, err
 func (d *ConfigDao) CheckConfigKeyUnique(configKey string, configId int64) (bool, error) {
 	fmt.Printf("ConfigDao.CheckConfigKeyUnique: 检查参数键名唯一性, ConfigKey=%s, ConfigId=%d\n", configKey, configId)
 
+	// 只校验当前租户的参数，租户可以新增与平台租户同键名的参数覆盖平台租户的值
 	var count int64
-	query := d.db.Model(&model.SysConfig{}).Where("config_key = ?", configKey)
+	query := d.db.Scopes(OwnTenant).Model(&model.SysConfig{}).Where("config_key = ?", configKey)
 
 	// 如果是更新操作，排除当前记录
 	if configId > 0 {
@@ -226,7 +234,10 @@ func (d *ConfigDao) UpdateConfig(config *model.SysConfig) error {
 	// 对应Java后端的<if test="remark != null">，备注允许为空
 	updates["remark"] = config.Remark
 
-	err := d.db.Model(&model.SysConfig{}).Where("config_id = ?", config.ConfigID).Updates(updates).Error
+	err := updateWithVersion(d.db, &model.SysConfig{}, "config_id", config.ConfigID, config.Version, updates)
+	if errors.Is(err, ErrVersionConflict) {
+		return err
+	}
 	if err != nil {
 		return fmt.Errorf("修改参数配置失败: %v", err)
 	}
@@ -280,7 +291,7 @@ func (d *ConfigDao) SelectConfigByKey(configKey string) (*model.SysConfig, error
 	fmt.Printf("ConfigDao.SelectConfigByKey: 根据键名查询参数配置, ConfigKey=%s\n", configKey)
 
 	var config model.SysConfig
-	err := d.db.Where("config_key = ?", configKey).First(&config).Error
+	err := preferTenantRows(d.db.Where("config_key = ?", configKey)).First(&config).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, nil
@@ -0,0 +1,63 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"wosm/internal/repository/model"
+	"wosm/pkg/database"
+
+	"gorm.io/gorm"
+)
+
+// DataChangeDao 数据变更记录数据访问层
+type DataChangeDao struct {
+	db *gorm.DB
+}
+
+// NewDataChangeDao 创建数据变更记录数据访问层实例
+func NewDataChangeDao() *DataChangeDao {
+	return &DataChangeDao{
+		db: database.GetDB(),
+	}
+}
+
+// WithContext 返回使用指定context的数据访问层实例，语句的context携带请求信息和租户
+func (d *DataChangeDao) WithContext(ctx context.Context) *DataChangeDao {
+	return &DataChangeDao{db: d.db.WithContext(ctx)}
+}
+
+// SelectDataChangeList 分页查询实体的变更记录，最新的在前
+func (d *DataChangeDao) SelectDataChangeList(entity string, entityId int64, pageNum, pageSize int) ([]model.SysDataChange, int64, error) {
+	var changes []model.SysDataChange
+	var total int64
+
+	query := d.db.Model(&model.SysDataChange{}).Where("entity = ? AND entity_id = ?", entity, entityId)
+	err := query.Count(&total).Error
+	if err != nil {
+		fmt.Printf("SelectDataChangeList: 查询变更记录总数失败: %v\n", err)
+		return nil, 0, err
+	}
+
+	offset := (pageNum - 1) * pageSize
+	err = query.Order("change_time DESC, change_id DESC").Offset(offset).Limit(pageSize).Find(&changes).Error
+	if err != nil {
+		fmt.Printf("SelectDataChangeList: 查询变更记录失败: %v\n", err)
+		return nil, 0, err
+	}
+
+	fmt.Printf("SelectDataChangeList: 查询到%s %d的变更记录数量=%d, 总数=%d\n", entity, entityId, len(changes), total)
+	return changes, total, nil
+}
+
+// UpdateOperIdByRequestId 将请求中的变更记录关联到操作日志
+func (d *DataChangeDao) UpdateOperIdByRequestId(requestId string, operId int64) error {
+	err := d.db.Model(&model.SysDataChange{}).
+		Where("request_id = ? AND oper_id = 0", requestId).
+		Update("oper_id", operId).Error
+	if err != nil {
+		fmt.Printf("UpdateOperIdByRequestId: 关联操作日志失败: %v\n", err)
+		return err
+	}
+
+	return nil
+}
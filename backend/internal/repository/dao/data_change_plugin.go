@@ -0,0 +1,273 @@
+package dao
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+	"wosm/internal/repository/model"
+	"wosm/pkg/database"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// dataChangeTable 记录变更历史的业务表
+type dataChangeTable struct {
+	entity    string // 实体类型
+	keyColumn string // 主键列
+}
+
+// dataChangeTables 记录变更历史的业务表，以表名匹配，Model和Table方式的修改都会记录
+var dataChangeTables = map[string]dataChangeTable{
+	"sys_user":      {entity: model.DataChangeEntityUser, keyColumn: "user_id"},
+	"sys_role":      {entity: model.DataChangeEntityRole, keyColumn: "role_id"},
+	"sys_dept":      {entity: model.DataChangeEntityDept, keyColumn: "dept_id"},
+	"sys_menu":      {entity: model.DataChangeEntityMenu, keyColumn: "menu_id"},
+	"sys_config":    {entity: model.DataChangeEntityConfig, keyColumn: "config_id"},
+	"sys_dict_data": {entity: model.DataChangeEntityDictData, keyColumn: "dict_code"},
+}
+
+// dataChangeIgnoredColumns 不记录变更的字段（每次修改都会变化或由登录更新）
+var dataChangeIgnoredColumns = map[string]bool{
+	"update_by":   true,
+	"update_time": true,
+	"version":     true,
+	"login_ip":    true,
+	"login_date":  true,
+}
+
+// dataChangeMaskedColumns 只记录发生了变更、不记录值的敏感字段
+var dataChangeMaskedColumns = map[string]bool{
+	"password": true,
+}
+
+// maxDataChangeRows 一次修改最多记录的行数，超过时只记录前面的行
+const maxDataChangeRows = 500
+
+// dataChangeBeforeKey 修改前数据在语句实例中的键
+const dataChangeBeforeKey = "wosm:data_change_before"
+
+// dataChangeSavePoint 写入变更记录前在修改的事务中建立的保存点
+const dataChangeSavePoint = "wosm_data_change"
+
+// DataChangePlugin 数据变更记录插件 在修改用户、角色、部门、菜单、参数和字典数据时记录每个字段修改前后的值
+// 以GORM回调实现而不是模型的BeforeUpdate钩子：钩子拿不到Where条件，DAO中大多是Model(&X{}).Where(...)方式的修改
+type DataChangePlugin struct{}
+
+// NewDataChangePlugin 创建数据变更记录插件
+func NewDataChangePlugin() *DataChangePlugin {
+	return &DataChangePlugin{}
+}
+
+// Name 插件名称
+func (p *DataChangePlugin) Name() string {
+	return "wosm:data_change"
+}
+
+// Initialize 注册修改前后的回调，都在默认事务内执行，变更记录与修改一起提交或回滚
+func (p *DataChangePlugin) Initialize(db *gorm.DB) error {
+	callback := db.Callback().Update()
+	if err := callback.Before("gorm:update").After("gorm:begin_transaction").Register("wosm:data_change_before", p.before); err != nil {
+		return err
+	}
+	return callback.After("gorm:update").Before("gorm:commit_or_rollback_transaction").Register("wosm:data_change_after", p.after)
+}
+
+// before 查询将要修改的行
+func (p *DataChangePlugin) before(db *gorm.DB) {
+	if db.Error != nil {
+		return
+	}
+	table, ok := dataChangeTables[tenantTableName(db.Statement)]
+	if !ok {
+		return
+	}
+
+	var exprs []clause.Expression
+	if where, ok := db.Statement.Clauses["WHERE"].Expression.(clause.Where); ok {
+		exprs = append(exprs, where.Exprs...)
+	}
+	// Save和Updates(struct)按结构体的主键修改，主键条件在gorm:update中才加入
+	if id := primaryKeyValue(db, table.keyColumn); id != 0 {
+		exprs = append(exprs, clause.Eq{Column: clause.Column{Name: table.keyColumn}, Value: id})
+	}
+	if len(exprs) == 0 {
+		return
+	}
+
+	var rows []map[string]interface{}
+	err := dataChangeSource(db).Clauses(clause.Where{Exprs: exprs}).
+		Order(table.keyColumn).Limit(maxDataChangeRows).Find(&rows).Error
+	if err != nil {
+		fmt.Printf("DataChangePlugin: 查询修改前的%s失败: %v\n", table.entity, err)
+		return
+	}
+	if len(rows) > 0 {
+		db.InstanceSet(dataChangeBeforeKey, rows)
+	}
+}
+
+// after 查询修改后的行，记录发生变化的字段
+func (p *DataChangePlugin) after(db *gorm.DB) {
+	if db.Error != nil || db.RowsAffected == 0 {
+		return
+	}
+	value, ok := db.InstanceGet(dataChangeBeforeKey)
+	if !ok {
+		return
+	}
+	table := dataChangeTables[tenantTableName(db.Statement)]
+	beforeRows := value.([]map[string]interface{})
+
+	ids := make([]int64, 0, len(beforeRows))
+	for _, row := range beforeRows {
+		ids = append(ids, toInt64(row[table.keyColumn]))
+	}
+
+	var afterRows []map[string]interface{}
+	if err := dataChangeSource(db).Where(table.keyColumn+" IN ?", ids).Find(&afterRows).Error; err != nil {
+		fmt.Printf("DataChangePlugin: 查询修改后的%s失败: %v\n", table.entity, err)
+		return
+	}
+	afterById := make(map[int64]map[string]interface{}, len(afterRows))
+	for _, row := range afterRows {
+		afterById[toInt64(row[table.keyColumn])] = row
+	}
+
+	requestID, operator := database.RequestInfo(db.Statement.Context)
+	now := time.Now()
+	var changes []model.SysDataChange
+	for _, before := range beforeRows {
+		id := toInt64(before[table.keyColumn])
+		after, ok := afterById[id]
+		if !ok {
+			continue
+		}
+		operName := operator
+		if operName == "" {
+			operName = formatChangeValue(after["update_by"])
+		}
+		// 按字段名排序，同一次修改的记录顺序固定
+		columns := make([]string, 0, len(before))
+		for column := range before {
+			if !dataChangeIgnoredColumns[column] {
+				columns = append(columns, column)
+			}
+		}
+		sort.Strings(columns)
+		for _, column := range columns {
+			oldText, newText := formatChangeValue(before[column]), formatChangeValue(after[column])
+			if oldText == newText {
+				continue
+			}
+			if dataChangeMaskedColumns[column] {
+				oldText, newText = "******", "******"
+			}
+			changes = append(changes, model.SysDataChange{
+				Entity:     table.entity,
+				EntityID:   id,
+				FieldName:  column,
+				OldValue:   oldText,
+				NewValue:   newText,
+				RequestID:  requestID,
+				OperName:   operName,
+				ChangeTime: &now,
+			})
+		}
+	}
+	if len(changes) == 0 {
+		return
+	}
+
+	// 变更记录写入失败不影响业务修改：在事务中先建保存点，失败时回滚到保存点
+	// PostgreSQL中语句失败会使整个事务失效，不回滚到保存点时业务修改会随事务一起回滚
+	_, inTransaction := db.Statement.ConnPool.(gorm.TxCommitter)
+	if inTransaction {
+		if err := dataChangeSession(db).SavePoint(dataChangeSavePoint).Error; err != nil {
+			fmt.Printf("DataChangePlugin: 创建保存点失败，不记录%s变更: %v\n", table.entity, err)
+			return
+		}
+	}
+	if err := dataChangeSession(db).CreateInBatches(&changes, 100).Error; err != nil {
+		fmt.Printf("DataChangePlugin: 记录%s变更失败: %v\n", table.entity, err)
+		if inTransaction {
+			if err := dataChangeSession(db).RollbackTo(dataChangeSavePoint).Error; err != nil {
+				fmt.Printf("DataChangePlugin: 回滚到保存点失败: %v\n", err)
+				db.AddError(err)
+			}
+		}
+		return
+	}
+	fmt.Printf("DataChangePlugin: 记录%s变更%d条, RequestID=%s\n", table.entity, len(changes), requestID)
+}
+
+// dataChangeSession 在修改语句的连接（默认事务）上执行的新会话，读主库
+func dataChangeSession(db *gorm.DB) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true}).Scopes(database.UsePrimary)
+}
+
+// dataChangeSource 查询修改前后数据的会话；Table("sys_user u")时保留别名，修改条件中的u.xxx仍然有效
+func dataChangeSource(db *gorm.DB) *gorm.DB {
+	if expr := db.Statement.TableExpr; expr != nil {
+		return dataChangeSession(db).Table(expr.SQL, expr.Vars...)
+	}
+	return dataChangeSession(db).Table(db.Statement.Table)
+}
+
+// primaryKeyValue 按结构体修改时结构体的主键值，不是结构体或主键为零值时返回0
+func primaryKeyValue(db *gorm.DB, keyColumn string) int64 {
+	stmt := db.Statement
+	if stmt.Schema == nil || stmt.ReflectValue.Kind() != reflect.Struct {
+		return 0
+	}
+	field := stmt.Schema.LookUpField(keyColumn)
+	if field == nil {
+		return 0
+	}
+	value, isZero := field.ValueOf(stmt.Context, stmt.ReflectValue)
+	if isZero {
+		return 0
+	}
+	return toInt64(value)
+}
+
+// toInt64 将数据库返回的主键值转为int64
+func toInt64(value interface{}) int64 {
+	switch v := value.(type) {
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case uint64:
+		return int64(v)
+	case []byte:
+		id, _ := strconv.ParseInt(string(v), 10, 64)
+		return id
+	case string:
+		id, _ := strconv.ParseInt(v, 10, 64)
+		return id
+	}
+	return 0
+}
+
+// formatChangeValue 将字段值转为记录的字符串，NULL记为空字符串
+func formatChangeValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.Format("2006-01-02 15:04:05")
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return v.Format("2006-01-02 15:04:05")
+	}
+	return fmt.Sprint(value)
+}
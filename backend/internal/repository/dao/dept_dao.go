@@ -1,9 +1,11 @@
 package dao
 
 import (
+	"context"
 	"fmt"
 	"wosm/internal/repository/model"
 	"wosm/pkg/database"
+	"wosm/pkg/datascope"
 
 	"gorm.io/gorm"
 )
@@ -20,8 +22,22 @@ func NewDeptDao() *DeptDao {
 	}
 }
 
+// WithContext 返回使用指定context的数据访问层实例，语句的context携带请求信息和租户
+func (d *DeptDao) WithContext(ctx context.Context) *DeptDao {
+	return &DeptDao{db: d.db.WithContext(ctx)}
+}
+
+// WithTx 返回使用指定事务的数据访问层实例
+func (d *DeptDao) WithTx(tx *gorm.DB) *DeptDao {
+	return &DeptDao{db: tx}
+}
+
+// deptScopeColumns 部门列表（单表查询）的数据权限字段 对应Java后端的@DataScope(deptAlias = "d")
+var deptScopeColumns = datascope.Columns{Dept: "dept_id"}
+
 // SelectDeptList 查询部门管理数据 对应Java后端的selectDeptList
-func (d *DeptDao) SelectDeptList(dept *model.SysDept) ([]model.SysDept, error) {
+// scope为当前用户的数据权限，为nil时不过滤
+func (d *DeptDao) SelectDeptList(dept *model.SysDept, scope *datascope.Predicate) ([]model.SysDept, error) {
 	var depts []model.SysDept
 	query := d.db.Where("del_flag = '0'")
 
@@ -40,11 +56,7 @@ func (d *DeptDao) SelectDeptList(dept *model.SysDept) ([]model.SysDept, error) {
 	}
 
 	// 添加数据权限过滤 对应Java后端的${params.dataScope}
-	if dept.Params != nil {
-		if dataScopeSQL, exists := dept.Params["dataScope"]; exists && dataScopeSQL != "" {
-			query = query.Where(dataScopeSQL.(string))
-		}
-	}
+	query = query.Scopes(scope.Scope(deptScopeColumns))
 
 	err := query.Order("parent_id, order_num").Find(&depts).Error
 	if err != nil {
@@ -83,7 +95,7 @@ func (d *DeptDao) SelectDeptListByRoleId(roleId int64, deptCheckStrictly bool) (
 // SelectDeptById 根据部门ID查询信息 对应Java后端的selectDeptById
 func (d *DeptDao) SelectDeptById(deptId int64) (*model.SysDept, error) {
 	var dept model.SysDept
-	err := d.db.Select("d.dept_id, d.parent_id, d.ancestors, d.dept_name, d.order_num, d.leader, d.phone, d.email, d.status, (select dept_name from sys_dept where dept_id = d.parent_id) as parent_name").
+	err := d.db.Select("d.dept_id, d.parent_id, d.ancestors, d.dept_name, d.order_num, d.leader, d.phone, d.email, d.status, d.version, (select dept_name from sys_dept where dept_id = d.parent_id) as parent_name").
 		Table("sys_dept d").
 		Where("d.dept_id = ?", deptId).
 		First(&dept).Error
@@ -103,10 +115,9 @@ func (d *DeptDao) SelectDeptById(deptId int64) (*model.SysDept, error) {
 func (d *DeptDao) SelectChildrenDeptById(deptId int64) ([]model.SysDept, error) {
 	var depts []model.SysDept
 
-	// SQL Server不支持find_in_set函数，使用CHARINDEX替代
-	// find_in_set(deptId, ancestors) 等价于 CHARINDEX(','+CAST(deptId AS VARCHAR)+',', ','+ancestors+',') > 0
+	// find_in_set(deptId, ancestors) 由方言翻译为各数据库的写法
 	deptIdStr := fmt.Sprintf("%d", deptId)
-	err := d.db.Where("CHARINDEX(','+?+',', ','+ancestors+',') > 0", deptIdStr).Find(&depts).Error
+	err := d.db.Where(database.CurrentDialect().FindInSet("?", "ancestors"), deptIdStr).Find(&depts).Error
 	if err != nil {
 		fmt.Printf("SelectChildrenDeptById: 查询子部门失败: %v\n", err)
 		return nil, err
@@ -120,10 +131,10 @@ func (d *DeptDao) SelectChildrenDeptById(deptId int64) ([]model.SysDept, error)
 func (d *DeptDao) SelectNormalChildrenDeptById(deptId int64) (int64, error) {
 	var count int64
 
-	// SQL Server不支持find_in_set函数，使用CHARINDEX替代
+	// find_in_set(deptId, ancestors) 由方言翻译为各数据库的写法
 	deptIdStr := fmt.Sprintf("%d", deptId)
 	err := d.db.Model(&model.SysDept{}).
-		Where("status = '0' AND del_flag = '0' AND CHARINDEX(','+?+',', ','+ancestors+',') > 0", deptIdStr).
+		Where("status = '0' AND del_flag = '0' AND "+database.CurrentDialect().FindInSet("?", "ancestors"), deptIdStr).
 		Count(&count).Error
 
 	if err != nil {
@@ -196,7 +207,10 @@ func (d *DeptDao) InsertDept(dept *model.SysDept) error {
 
 // UpdateDept 修改部门信息 对应Java后端的updateDept
 func (d *DeptDao) UpdateDept(dept *model.SysDept) error {
-	err := d.db.Where("dept_id = ?", dept.DeptID).Updates(dept).Error
+	updates, err := structUpdates(d.db, dept, false)
+	if err == nil {
+		err = updateWithVersion(d.db, &model.SysDept{}, "dept_id", dept.DeptID, dept.Version, updates)
+	}
 	if err != nil {
 		fmt.Printf("UpdateDept: 修改部门失败: %v\n", err)
 		return err
@@ -238,7 +252,7 @@ func (d *DeptDao) UpdateDeptChildren(depts []model.SysDept) error {
 	for _, dept := range depts {
 		if err := tx.Model(&model.SysDept{}).
 			Where("dept_id = ?", dept.DeptID).
-			Update("ancestors", dept.Ancestors).Error; err != nil {
+			Updates(map[string]interface{}{"ancestors": dept.Ancestors, versionColumn: nextVersion}).Error; err != nil {
 			tx.Rollback()
 			fmt.Printf("UpdateDeptChildren: 批量修改子部门关系失败: %v\n", err)
 			return err
@@ -258,7 +272,7 @@ func (d *DeptDao) UpdateDeptChildren(depts []model.SysDept) error {
 func (d *DeptDao) UpdateDeptStatusNormal(deptIds []int64) error {
 	err := d.db.Model(&model.SysDept{}).
 		Where("dept_id IN ?", deptIds).
-		Update("status", "0").Error
+		Updates(map[string]interface{}{"status": "0", versionColumn: nextVersion}).Error
 
 	if err != nil {
 		fmt.Printf("UpdateDeptStatusNormal: 修改部门状态失败: %v\n", err)
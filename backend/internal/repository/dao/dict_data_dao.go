@@ -1,6 +1,7 @@
 package dao
 
 import (
+	"context"
 	"fmt"
 	"wosm/internal/repository/model"
 	"wosm/pkg/database"
@@ -20,6 +21,11 @@ func NewDictDataDao() *DictDataDao {
 	}
 }
 
+// WithContext 返回使用指定context的数据访问层实例，语句的context携带请求信息和租户
+func (d *DictDataDao) WithContext(ctx context.Context) *DictDataDao {
+	return &DictDataDao{db: d.db.WithContext(ctx)}
+}
+
 // SelectDictDataList 根据条件分页查询字典数据 对应Java后端的selectDictDataList
 func (d *DictDataDao) SelectDictDataList(dictData *model.SysDictData) ([]model.SysDictData, error) {
 	var dictDatas []model.SysDictData
@@ -86,12 +92,23 @@ func (d *DictDataDao) SelectDictDataListWithPage(dictData *model.SysDictData, pa
 // SelectDictDataByType 根据字典类型查询字典数据 对应Java后端的selectDictDataByType
 func (d *DictDataDao) SelectDictDataByType(dictType string) ([]model.SysDictData, error) {
 	var dictDatas []model.SysDictData
-	err := d.db.Where("status = '0' AND dict_type = ?", dictType).
+	err := preferTenantRows(d.db.Where("status = '0' AND dict_type = ?", dictType)).
 		Order("dict_sort").Find(&dictDatas).Error
 	if err != nil {
 		fmt.Printf("SelectDictDataByType: 查询字典数据失败: %v\n", err)
 		return nil, err
 	}
+	// 租户有该类型的字典数据时整体覆盖平台租户的字典数据
+	if len(dictDatas) > 0 {
+		tenantID := dictDatas[0].TenantID
+		overridden := dictDatas[:0]
+		for _, dictData := range dictDatas {
+			if dictData.TenantID == tenantID {
+				overridden = append(overridden, dictData)
+			}
+		}
+		dictDatas = overridden
+	}
 
 	fmt.Printf("SelectDictDataByType: 查询到字典数据数量=%d, DictType=%s\n", len(dictDatas), dictType)
 	return dictDatas, nil
@@ -115,10 +132,10 @@ func (d *DictDataDao) SelectDictDataById(dictCode int64) (*model.SysDictData, er
 // SelectDictLabel 根据字典类型和字典键值查询字典标签 对应Java后端的selectDictLabel
 func (d *DictDataDao) SelectDictLabel(dictType, dictValue string) (string, error) {
 	var dictLabel string
-	err := d.db.Model(&model.SysDictData{}).
+	err := preferTenantRows(d.db.Model(&model.SysDictData{}).
 		Select("dict_label").
-		Where("dict_type = ? AND dict_value = ? AND status = '0'", dictType, dictValue).
-		Scan(&dictLabel).Error
+		Where("dict_type = ? AND dict_value = ? AND status = '0'", dictType, dictValue)).
+		Limit(1).Scan(&dictLabel).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return "", nil
@@ -131,9 +148,10 @@ func (d *DictDataDao) SelectDictLabel(dictType, dictValue string) (string, error
 }
 
 // CountDictDataByType 查询字典数据 对应Java后端的countDictDataByType
+// 字典类型由所有租户共用，统计所有租户的字典数据
 func (d *DictDataDao) CountDictDataByType(dictType string) (int64, error) {
 	var count int64
-	err := d.db.Model(&model.SysDictData{}).Where("dict_type = ?", dictType).Count(&count).Error
+	err := d.db.Scopes(IgnoreTenant).Model(&model.SysDictData{}).Where("dict_type = ?", dictType).Count(&count).Error
 	if err != nil {
 		fmt.Printf("CountDictDataByType: 查询字典数据数量失败: %v\n", err)
 		return 0, err
@@ -156,7 +174,10 @@ func (d *DictDataDao) InsertDictData(dictData *model.SysDictData) error {
 
 // UpdateDictData 修改字典数据信息 对应Java后端的updateDictData
 func (d *DictDataDao) UpdateDictData(dictData *model.SysDictData) error {
-	err := d.db.Where("dict_code = ?", dictData.DictCode).Updates(dictData).Error
+	updates, err := structUpdates(d.db, dictData, false)
+	if err == nil {
+		err = updateWithVersion(d.db, &model.SysDictData{}, "dict_code", dictData.DictCode, dictData.Version, updates)
+	}
 	if err != nil {
 		fmt.Printf("UpdateDictData: 修改字典数据失败: %v\n", err)
 		return err
@@ -167,10 +188,11 @@ func (d *DictDataDao) UpdateDictData(dictData *model.SysDictData) error {
 }
 
 // UpdateDictDataType 修改字典数据类型 对应Java后端的updateDictDataType
+// 字典类型由所有租户共用，同时修改所有租户的字典数据
 func (d *DictDataDao) UpdateDictDataType(oldDictType, newDictType string) error {
-	err := d.db.Model(&model.SysDictData{}).
+	err := d.db.Scopes(IgnoreTenant).Model(&model.SysDictData{}).
 		Where("dict_type = ?", oldDictType).
-		Update("dict_type", newDictType).Error
+		Updates(map[string]interface{}{"dict_type": newDictType, versionColumn: nextVersion}).Error
 	if err != nil {
 		fmt.Printf("UpdateDictDataType: 修改字典数据类型失败: %v\n", err)
 		return err
@@ -1,6 +1,7 @@
 package dao
 
 import (
+	"context"
 	"fmt"
 	"wosm/internal/repository/model"
 	"wosm/pkg/database"
@@ -20,6 +21,11 @@ func NewDictTypeDao() *DictTypeDao {
 	}
 }
 
+// WithContext 返回使用指定context的数据访问层实例，语句的context携带请求信息和租户
+func (d *DictTypeDao) WithContext(ctx context.Context) *DictTypeDao {
+	return &DictTypeDao{db: d.db.WithContext(ctx)}
+}
+
 // SelectDictTypeList 查询字典类型 对应Java后端的selectDictTypeList
 func (d *DictTypeDao) SelectDictTypeList(dictType *model.SysDictType) ([]model.SysDictType, error) {
 	var dictTypes []model.SysDictType
@@ -0,0 +1,56 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"wosm/pkg/database"
+
+	"gorm.io/gorm"
+)
+
+// DynDao 运行时模块数据访问层，执行根据业务表字段元数据构建的参数化SQL
+type DynDao struct {
+	db *gorm.DB
+}
+
+// NewDynDao 创建运行时模块数据访问层实例
+func NewDynDao() *DynDao {
+	return &DynDao{
+		db: database.GetDB(),
+	}
+}
+
+// WithContext 返回使用指定context的数据访问层实例，语句的context携带请求信息和租户
+func (d *DynDao) WithContext(ctx context.Context) *DynDao {
+	return &DynDao{db: d.db.WithContext(ctx)}
+}
+
+// SelectRows 执行查询，每行以列名到值的映射返回
+func (d *DynDao) SelectRows(sql string, args []interface{}) ([]map[string]interface{}, error) {
+	rows := make([]map[string]interface{}, 0)
+	if err := d.db.Raw(sql, args...).Scan(&rows).Error; err != nil {
+		fmt.Printf("SelectRows: 查询运行时模块数据失败: %v\n", err)
+		return nil, err
+	}
+	return rows, nil
+}
+
+// SelectCount 执行统计查询
+func (d *DynDao) SelectCount(sql string, args []interface{}) (int64, error) {
+	var count int64
+	if err := d.db.Raw(sql, args...).Scan(&count).Error; err != nil {
+		fmt.Printf("SelectCount: 统计运行时模块数据失败: %v\n", err)
+		return 0, err
+	}
+	return count, nil
+}
+
+// Exec 执行新增、修改或删除，返回影响的行数
+func (d *DynDao) Exec(sql string, args []interface{}) (int64, error) {
+	result := d.db.Exec(sql, args...)
+	if result.Error != nil {
+		fmt.Printf("Exec: 修改运行时模块数据失败: %v\n", result.Error)
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
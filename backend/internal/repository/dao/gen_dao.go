@@ -1,6 +1,7 @@
 package dao
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"wosm/internal/repository/model"
@@ -21,16 +22,28 @@ func NewGenDao() *GenDao {
 	}
 }
 
+// WithContext 返回使用指定context的数据访问层实例，语句的context携带请求信息和租户
+func (d *GenDao) WithContext(ctx context.Context) *GenDao {
+	return &GenDao{db: d.db.WithContext(ctx)}
+}
+
 // CreateTable 创建表结构 对应Java后端的createTable
-func (d *GenDao) CreateTable(sql string) error {
-	// 执行SQL创建表
-	err := d.db.Exec(sql).Error
+// 建表语句由表结构设计器生成或校验，在一个事务中按顺序执行，任一语句失败时全部回滚
+func (d *GenDao) CreateTable(statements []string) error {
+	err := d.db.Transaction(func(tx *gorm.DB) error {
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 	if err != nil {
 		fmt.Printf("CreateTable: 创建表结构失败: %v\n", err)
 		return err
 	}
 
-	fmt.Printf("CreateTable: 创建表结构成功\n")
+	fmt.Printf("CreateTable: 创建表结构成功, 语句数=%d\n", len(statements))
 	return nil
 }
 
@@ -38,35 +51,23 @@ func (d *GenDao) CreateTable(sql string) error {
 func (d *GenDao) SelectDbTableList(tableName, tableComment string) ([]model.DbTable, error) {
 	var tables []model.DbTable
 
-	// SQL Server查询表信息的SQL
-	sql := `
-		SELECT
-			t.TABLE_NAME as table_name,
-			ISNULL(ep.value, '') as table_comment,
-			GETDATE() as create_time,
-			GETDATE() as update_time
-		FROM INFORMATION_SCHEMA.TABLES t
-		LEFT JOIN sys.extended_properties ep ON ep.major_id = OBJECT_ID(t.TABLE_SCHEMA + '.' + t.TABLE_NAME)
-			AND ep.minor_id = 0 AND ep.name = 'MS_Description'
-		WHERE t.TABLE_TYPE = 'BASE TABLE'
-			AND t.TABLE_SCHEMA = 'dbo'
-			AND t.TABLE_NAME NOT LIKE 'sys_%'
-			AND t.TABLE_NAME NOT IN ('sysdiagrams')
-	`
+	// 各数据库读取表信息的查询由方言提供，系统表（sys_）不参与代码生成
+	sql := `SELECT table_name, table_comment, create_time, update_time FROM (` + database.CurrentDialect().TablesQuery() + `) t
+		WHERE table_name NOT LIKE 'sys_%'`
 
 	args := []any{}
 
 	// 添加查询条件
 	if tableName != "" {
-		sql += " AND t.TABLE_NAME LIKE ?"
+		sql += " AND table_name LIKE ?"
 		args = append(args, "%"+tableName+"%")
 	}
 	if tableComment != "" {
-		sql += " AND ISNULL(ep.value, '') LIKE ?"
+		sql += " AND table_comment LIKE ?"
 		args = append(args, "%"+tableComment+"%")
 	}
 
-	sql += " ORDER BY t.TABLE_NAME"
+	sql += " ORDER BY table_name"
 
 	err := d.db.Raw(sql, args...).Scan(&tables).Error
 	if err != nil {
@@ -82,33 +83,8 @@ func (d *GenDao) SelectDbTableList(tableName, tableComment string) ([]model.DbTa
 func (d *GenDao) SelectDbTableColumnsByName(tableName string) ([]model.DbTableColumn, error) {
 	var columns []model.DbTableColumn
 
-	// SQL Server查询表字段信息的SQL
-	sql := `
-		SELECT 
-			c.COLUMN_NAME as column_name,
-			ISNULL(ep.value, '') as column_comment,
-			c.DATA_TYPE as data_type,
-			c.DATA_TYPE + 
-				CASE 
-					WHEN c.CHARACTER_MAXIMUM_LENGTH IS NOT NULL 
-					THEN '(' + CAST(c.CHARACTER_MAXIMUM_LENGTH AS VARCHAR) + ')'
-					WHEN c.NUMERIC_PRECISION IS NOT NULL AND c.NUMERIC_SCALE IS NOT NULL
-					THEN '(' + CAST(c.NUMERIC_PRECISION AS VARCHAR) + ',' + CAST(c.NUMERIC_SCALE AS VARCHAR) + ')'
-					ELSE ''
-				END as column_type,
-			CASE WHEN pk.COLUMN_NAME IS NOT NULL THEN 'PRI' ELSE '' END as column_key,
-			CASE WHEN c.IS_IDENTITY = 'YES' THEN 'auto_increment' ELSE '' END as extra
-		FROM INFORMATION_SCHEMA.COLUMNS c
-		LEFT JOIN INFORMATION_SCHEMA.KEY_COLUMN_USAGE pk ON pk.TABLE_NAME = c.TABLE_NAME 
-			AND pk.COLUMN_NAME = c.COLUMN_NAME 
-			AND pk.CONSTRAINT_NAME LIKE 'PK_%'
-		LEFT JOIN sys.extended_properties ep ON ep.major_id = OBJECT_ID(c.TABLE_SCHEMA + '.' + c.TABLE_NAME) 
-			AND ep.minor_id = c.ORDINAL_POSITION AND ep.name = 'MS_Description'
-		WHERE c.TABLE_NAME = ? AND c.TABLE_SCHEMA = 'dbo'
-		ORDER BY c.ORDINAL_POSITION
-	`
-
-	err := d.db.Raw(sql, tableName).Scan(&columns).Error
+	sql, args := database.CurrentDialect().ColumnsQuery(tableName)
+	err := d.db.Raw(sql, args...).Scan(&columns).Error
 	if err != nil {
 		fmt.Printf("SelectDbTableColumnsByName: 查询表字段列表失败: %v\n", err)
 		return nil, err
@@ -216,17 +192,11 @@ func (d *GenDao) DeleteGenTableByIds(ids []int) error {
 func (d *GenDao) SelectTableNameList() ([]string, error) {
 	var tableNames []string
 
-	sql := `
-		SELECT TABLE_NAME 
-		FROM INFORMATION_SCHEMA.TABLES 
-		WHERE TABLE_TYPE = 'BASE TABLE' 
-			AND TABLE_SCHEMA = 'dbo'
-			AND TABLE_NAME NOT LIKE 'sys_%'
-			AND TABLE_NAME NOT IN ('sysdiagrams')
-		ORDER BY TABLE_NAME
-	`
-
-	err := d.db.Raw(sql).Pluck("TABLE_NAME", &tableNames).Error
+	sql := `SELECT table_name FROM (` + database.CurrentDialect().TablesQuery() + `) t
+		WHERE table_name NOT LIKE 'sys_%'
+		ORDER BY table_name`
+
+	err := d.db.Raw(sql).Pluck("table_name", &tableNames).Error
 	if err != nil {
 		fmt.Printf("SelectTableNameList: 查询表名称列表失败: %v\n", err)
 		return nil, err
@@ -363,6 +333,43 @@ func (d *GenDao) DeleteGenTableColumnByTableIds(tableIds []int64) error {
 	return nil
 }
 
+// SynchGenTableColumns 同步业务表字段：在一个事务中新增、修改、删除字段，并按需更新表描述
+// 修改只写入与数据库结构相关的列，保留用户调整过的显示类型、查询方式和字典类型
+func (d *GenDao) SynchGenTableColumns(table *model.GenTable, updateComment bool, inserts, updates []model.GenTableColumn, deleteIds []int64) error {
+	err := d.db.Transaction(func(tx *gorm.DB) error {
+		for i := range inserts {
+			if err := tx.Create(&inserts[i]).Error; err != nil {
+				return err
+			}
+		}
+		for i := range updates {
+			err := tx.Model(&model.GenTableColumn{}).Where("column_id = ?", updates[i].ColumnID).
+				Select("column_type", "java_type", "is_pk", "is_increment", "column_comment", "update_by", "update_time").
+				Updates(&updates[i]).Error
+			if err != nil {
+				return err
+			}
+		}
+		if len(deleteIds) > 0 {
+			if err := tx.Where("column_id IN ?", deleteIds).Delete(&model.GenTableColumn{}).Error; err != nil {
+				return err
+			}
+		}
+		columns := []string{"update_by", "update_time"}
+		if updateComment {
+			columns = append(columns, "table_comment")
+		}
+		return tx.Model(&model.GenTable{}).Where("table_id = ?", table.TableID).Select(columns).Updates(table).Error
+	})
+	if err != nil {
+		fmt.Printf("SynchGenTableColumns: 同步业务表字段失败: %v\n", err)
+		return err
+	}
+
+	fmt.Printf("SynchGenTableColumns: 同步业务表字段成功, TableID=%d, 新增=%d, 修改=%d, 删除=%d\n", table.TableID, len(inserts), len(updates), len(deleteIds))
+	return nil
+}
+
 // SelectGenTableAll 查询所有表信息 对应Java后端的selectGenTableAll
 func (d *GenDao) SelectGenTableAll() ([]model.GenTable, error) {
 	var tables []model.GenTable
@@ -376,6 +383,19 @@ func (d *GenDao) SelectGenTableAll() ([]model.GenTable, error) {
 	return tables, nil
 }
 
+// SelectGenTableListByBusinessName 根据业务名查询业务表，运行时模块按业务名访问
+func (d *GenDao) SelectGenTableListByBusinessName(businessName string) ([]model.GenTable, error) {
+	var tables []model.GenTable
+	err := d.db.Table("gen_table").Select("table_id, table_name, table_comment, sub_table_name, sub_table_fk_name, class_name, tpl_category, tpl_web_type, package_name, module_name, business_name, function_name, function_author, gen_type, gen_path, options, create_by, create_time, update_by, update_time, remark").Where("business_name = ?", businessName).Find(&tables).Error
+	if err != nil {
+		fmt.Printf("SelectGenTableListByBusinessName: 查询业务表失败: %v\n", err)
+		return nil, err
+	}
+
+	fmt.Printf("SelectGenTableListByBusinessName: 查询业务表成功, BusinessName=%s, 数量=%d\n", businessName, len(tables))
+	return tables, nil
+}
+
 // SelectDbTableListByNames 根据表名数组查询数据库表 对应Java后端的selectDbTableListByNames
 func (d *GenDao) SelectDbTableListByNames(tableNames []string) ([]model.DbTable, error) {
 	if len(tableNames) == 0 {
@@ -384,21 +404,9 @@ func (d *GenDao) SelectDbTableListByNames(tableNames []string) ([]model.DbTable,
 
 	var tables []model.DbTable
 
-	// SQL Server查询表信息的SQL
-	sql := `
-		SELECT
-			t.TABLE_NAME as table_name,
-			ISNULL(ep.value, '') as table_comment,
-			GETDATE() as create_time,
-			GETDATE() as update_time
-		FROM INFORMATION_SCHEMA.TABLES t
-		LEFT JOIN sys.extended_properties ep ON ep.major_id = OBJECT_ID(t.TABLE_SCHEMA + '.' + t.TABLE_NAME)
-			AND ep.minor_id = 0 AND ep.name = 'MS_Description'
-		WHERE t.TABLE_TYPE = 'BASE TABLE'
-			AND t.TABLE_SCHEMA = 'dbo'
-			AND t.TABLE_NAME IN (` + strings.Repeat("?,", len(tableNames)-1) + "?)" + `
-		ORDER BY t.TABLE_NAME
-	`
+	sql := `SELECT table_name, table_comment, create_time, update_time FROM (` + database.CurrentDialect().TablesQuery() + `) t
+		WHERE table_name IN (` + strings.Repeat("?,", len(tableNames)-1) + "?)" + `
+		ORDER BY table_name`
 
 	// 转换为interface{}切片
 	args := make([]interface{}, len(tableNames))
@@ -0,0 +1,243 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"wosm/internal/repository/model"
+	"wosm/pkg/database"
+
+	"gorm.io/gorm"
+)
+
+// GenTemplateDao 代码生成模板组数据访问层
+type GenTemplateDao struct {
+	db *gorm.DB
+}
+
+// NewGenTemplateDao 创建代码生成模板组数据访问层实例
+func NewGenTemplateDao() *GenTemplateDao {
+	return &GenTemplateDao{
+		db: database.GetDB(),
+	}
+}
+
+// WithContext 返回使用指定context的数据访问层实例，语句的context携带请求信息和租户
+func (d *GenTemplateDao) WithContext(ctx context.Context) *GenTemplateDao {
+	return &GenTemplateDao{db: d.db.WithContext(ctx)}
+}
+
+// SelectTemplateSetList 查询模板组列表
+func (d *GenTemplateDao) SelectTemplateSetList(set *model.GenTemplateSet) ([]model.GenTemplateSet, error) {
+	var sets []model.GenTemplateSet
+	query := d.db.Model(&model.GenTemplateSet{})
+
+	if set.SetName != "" {
+		query = query.Where("set_name LIKE ?", "%"+set.SetName+"%")
+	}
+	if set.Status != "" {
+		query = query.Where("status = ?", set.Status)
+	}
+
+	err := query.Order("set_id").Find(&sets).Error
+	if err != nil {
+		fmt.Printf("SelectTemplateSetList: 查询模板组列表失败: %v\n", err)
+		return nil, err
+	}
+
+	return sets, nil
+}
+
+// SelectTemplateSetById 查询模板组
+func (d *GenTemplateDao) SelectTemplateSetById(setId int64) (*model.GenTemplateSet, error) {
+	var set model.GenTemplateSet
+	err := d.db.Where("set_id = ?", setId).First(&set).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		fmt.Printf("SelectTemplateSetById: 查询模板组失败: %v\n", err)
+		return nil, err
+	}
+
+	return &set, nil
+}
+
+// CheckSetNameUnique 校验模板组名称是否唯一
+func (d *GenTemplateDao) CheckSetNameUnique(setName string, setId int64) (bool, error) {
+	var count int64
+	err := d.db.Model(&model.GenTemplateSet{}).Where("set_name = ? AND set_id <> ?", setName, setId).Count(&count).Error
+	if err != nil {
+		fmt.Printf("CheckSetNameUnique: 校验模板组名称失败: %v\n", err)
+		return false, err
+	}
+
+	return count == 0, nil
+}
+
+// InsertTemplateSet 新增模板组
+func (d *GenTemplateDao) InsertTemplateSet(set *model.GenTemplateSet) error {
+	err := d.db.Create(set).Error
+	if err != nil {
+		fmt.Printf("InsertTemplateSet: 新增模板组失败: %v\n", err)
+		return err
+	}
+
+	fmt.Printf("InsertTemplateSet: 新增模板组成功, SetID=%d\n", set.SetID)
+	return nil
+}
+
+// UpdateTemplateSet 修改模板组
+func (d *GenTemplateDao) UpdateTemplateSet(set *model.GenTemplateSet) error {
+	err := d.db.Model(&model.GenTemplateSet{}).Where("set_id = ?", set.SetID).
+		Select("set_name", "status", "update_by", "update_time", "remark").
+		Updates(set).Error
+	if err != nil {
+		fmt.Printf("UpdateTemplateSet: 修改模板组失败: %v\n", err)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteTemplateSetByIds 批量删除模板组及其模板和历史版本
+func (d *GenTemplateDao) DeleteTemplateSetByIds(setIds []int64) error {
+	err := d.db.Transaction(func(tx *gorm.DB) error {
+		templateIds := tx.Model(&model.GenTemplate{}).Select("template_id").Where("set_id IN ?", setIds)
+		if err := tx.Where("template_id IN (?)", templateIds).Delete(&model.GenTemplateHistory{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("set_id IN ?", setIds).Delete(&model.GenTemplate{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("set_id IN ?", setIds).Delete(&model.GenTemplateSet{}).Error
+	})
+	if err != nil {
+		fmt.Printf("DeleteTemplateSetByIds: 删除模板组失败: %v\n", err)
+		return err
+	}
+
+	fmt.Printf("DeleteTemplateSetByIds: 删除模板组成功, 数量=%d\n", len(setIds))
+	return nil
+}
+
+// SelectTemplatesBySetId 查询模板组中的模板
+func (d *GenTemplateDao) SelectTemplatesBySetId(setId int64) ([]model.GenTemplate, error) {
+	var templates []model.GenTemplate
+	err := d.db.Where("set_id = ?", setId).Order("template_name").Find(&templates).Error
+	if err != nil {
+		fmt.Printf("SelectTemplatesBySetId: 查询模板失败: %v\n", err)
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+// SelectTemplateById 查询模板
+func (d *GenTemplateDao) SelectTemplateById(templateId int64) (*model.GenTemplate, error) {
+	var tpl model.GenTemplate
+	err := d.db.Where("template_id = ?", templateId).First(&tpl).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		fmt.Printf("SelectTemplateById: 查询模板失败: %v\n", err)
+		return nil, err
+	}
+
+	return &tpl, nil
+}
+
+// SaveTemplate 保存模板内容，模板不存在时新增，存在时版本号加一，同时记录历史版本
+func (d *GenTemplateDao) SaveTemplate(setId int64, templateName, content, operName string) (*model.GenTemplate, error) {
+	var tpl model.GenTemplate
+	err := d.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		err := tx.Where("set_id = ? AND template_name = ?", setId, templateName).First(&tpl).Error
+		switch {
+		case err == gorm.ErrRecordNotFound:
+			tpl = model.GenTemplate{
+				SetID:        setId,
+				TemplateName: templateName,
+				Content:      content,
+				Version:      1,
+				CreateBy:     operName,
+				CreateTime:   &now,
+			}
+			if err := tx.Create(&tpl).Error; err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		default:
+			tpl.Content = content
+			tpl.Version++
+			tpl.UpdateBy = operName
+			tpl.UpdateTime = &now
+			if err := tx.Model(&model.GenTemplate{}).Where("template_id = ?", tpl.TemplateID).
+				Select("content", "version", "update_by", "update_time").
+				Updates(&tpl).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Create(&model.GenTemplateHistory{
+			TemplateID: tpl.TemplateID,
+			Version:    tpl.Version,
+			Content:    content,
+			CreateBy:   operName,
+			CreateTime: &now,
+		}).Error
+	})
+	if err != nil {
+		fmt.Printf("SaveTemplate: 保存模板失败, SetID=%d, Template=%s, 错误=%v\n", setId, templateName, err)
+		return nil, err
+	}
+
+	fmt.Printf("SaveTemplate: 保存模板成功, TemplateID=%d, Version=%d\n", tpl.TemplateID, tpl.Version)
+	return &tpl, nil
+}
+
+// DeleteTemplateById 删除模板及其历史版本，删除后该模板恢复使用内置模板
+func (d *GenTemplateDao) DeleteTemplateById(templateId int64) error {
+	err := d.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("template_id = ?", templateId).Delete(&model.GenTemplateHistory{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("template_id = ?", templateId).Delete(&model.GenTemplate{}).Error
+	})
+	if err != nil {
+		fmt.Printf("DeleteTemplateById: 删除模板失败: %v\n", err)
+		return err
+	}
+
+	return nil
+}
+
+// SelectTemplateHistory 查询模板的历史版本（不含内容），按版本号倒序
+func (d *GenTemplateDao) SelectTemplateHistory(templateId int64) ([]model.GenTemplateHistory, error) {
+	var histories []model.GenTemplateHistory
+	err := d.db.Select("history_id", "template_id", "version", "create_by", "create_time").
+		Where("template_id = ?", templateId).Order("version DESC").Find(&histories).Error
+	if err != nil {
+		fmt.Printf("SelectTemplateHistory: 查询模板历史失败: %v\n", err)
+		return nil, err
+	}
+
+	return histories, nil
+}
+
+// SelectTemplateHistoryByVersion 查询模板的指定历史版本
+func (d *GenTemplateDao) SelectTemplateHistoryByVersion(templateId int64, version int) (*model.GenTemplateHistory, error) {
+	var history model.GenTemplateHistory
+	err := d.db.Where("template_id = ? AND version = ?", templateId, version).First(&history).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		fmt.Printf("SelectTemplateHistoryByVersion: 查询模板历史失败: %v\n", err)
+		return nil, err
+	}
+
+	return &history, nil
+}
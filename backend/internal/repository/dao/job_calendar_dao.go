@@ -0,0 +1,178 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"wosm/internal/repository/model"
+	"wosm/pkg/database"
+
+	"gorm.io/gorm"
+)
+
+// JobCalendarDao 定时任务排除日历数据访问层
+type JobCalendarDao struct {
+	db *gorm.DB
+}
+
+// NewJobCalendarDao 创建定时任务排除日历数据访问层实例
+func NewJobCalendarDao() *JobCalendarDao {
+	return &JobCalendarDao{
+		db: database.GetDB(),
+	}
+}
+
+// WithContext 返回使用指定context的数据访问层实例，语句的context携带请求信息和租户
+func (d *JobCalendarDao) WithContext(ctx context.Context) *JobCalendarDao {
+	return &JobCalendarDao{db: d.db.WithContext(ctx)}
+}
+
+// SelectCalendarList 查询日历列表
+func (d *JobCalendarDao) SelectCalendarList(calendar *model.SysJobCalendar) ([]model.SysJobCalendar, error) {
+	var calendars []model.SysJobCalendar
+	query := d.db.Model(&model.SysJobCalendar{})
+
+	if calendar.CalendarName != "" {
+		query = query.Where("calendar_name LIKE ?", "%"+calendar.CalendarName+"%")
+	}
+	if calendar.Status != "" {
+		query = query.Where("status = ?", calendar.Status)
+	}
+
+	err := query.Order("calendar_id").Find(&calendars).Error
+	if err != nil {
+		fmt.Printf("SelectCalendarList: 查询日历列表失败: %v\n", err)
+		return nil, err
+	}
+
+	return calendars, nil
+}
+
+// SelectCalendarById 查询日历
+func (d *JobCalendarDao) SelectCalendarById(calendarId int64) (*model.SysJobCalendar, error) {
+	var calendar model.SysJobCalendar
+	err := d.db.Where("calendar_id = ?", calendarId).First(&calendar).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		fmt.Printf("SelectCalendarById: 查询日历失败: %v\n", err)
+		return nil, err
+	}
+
+	return &calendar, nil
+}
+
+// SelectRulesByCalendarId 查询日历的排除规则
+func (d *JobCalendarDao) SelectRulesByCalendarId(calendarId int64) ([]model.SysJobCalendarRule, error) {
+	var rules []model.SysJobCalendarRule
+	err := d.db.Where("calendar_id = ?", calendarId).Order("rule_type, start_time, rule_id").Find(&rules).Error
+	if err != nil {
+		fmt.Printf("SelectRulesByCalendarId: 查询日历规则失败: %v\n", err)
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// InsertCalendar 新增日历
+func (d *JobCalendarDao) InsertCalendar(calendar *model.SysJobCalendar) error {
+	err := d.db.Create(calendar).Error
+	if err != nil {
+		fmt.Printf("InsertCalendar: 新增日历失败: %v\n", err)
+		return err
+	}
+
+	fmt.Printf("InsertCalendar: 新增日历成功, CalendarID=%d\n", calendar.CalendarID)
+	return nil
+}
+
+// UpdateCalendar 修改日历
+func (d *JobCalendarDao) UpdateCalendar(calendar *model.SysJobCalendar) error {
+	err := d.db.Model(&model.SysJobCalendar{}).Where("calendar_id = ?", calendar.CalendarID).
+		Select("calendar_name", "timezone", "status", "update_by", "update_time", "remark").
+		Updates(calendar).Error
+	if err != nil {
+		fmt.Printf("UpdateCalendar: 修改日历失败: %v\n", err)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteCalendarByIds 批量删除日历及其规则
+func (d *JobCalendarDao) DeleteCalendarByIds(calendarIds []int64) error {
+	err := d.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("calendar_id IN ?", calendarIds).Delete(&model.SysJobCalendarRule{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("calendar_id IN ?", calendarIds).Delete(&model.SysJobCalendar{}).Error
+	})
+	if err != nil {
+		fmt.Printf("DeleteCalendarByIds: 删除日历失败: %v\n", err)
+		return err
+	}
+
+	fmt.Printf("DeleteCalendarByIds: 删除日历成功, 数量=%d\n", len(calendarIds))
+	return nil
+}
+
+// ReplaceRules 替换日历的排除规则（事务内先删后插）
+func (d *JobCalendarDao) ReplaceRules(calendarId int64, rules []model.SysJobCalendarRule) error {
+	err := d.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("calendar_id = ?", calendarId).Delete(&model.SysJobCalendarRule{}).Error; err != nil {
+			return err
+		}
+		if len(rules) == 0 {
+			return nil
+		}
+		return tx.Create(&rules).Error
+	})
+	if err != nil {
+		fmt.Printf("ReplaceRules: 保存日历规则失败, CalendarID=%d, 错误=%v\n", calendarId, err)
+		return err
+	}
+
+	return nil
+}
+
+// InsertRules 追加日历的排除规则
+func (d *JobCalendarDao) InsertRules(rules []model.SysJobCalendarRule) error {
+	if len(rules) == 0 {
+		return nil
+	}
+	err := d.db.Create(&rules).Error
+	if err != nil {
+		fmt.Printf("InsertRules: 新增日历规则失败: %v\n", err)
+		return err
+	}
+
+	return nil
+}
+
+// CheckCalendarNameUnique 检查日历名称是否唯一
+func (d *JobCalendarDao) CheckCalendarNameUnique(calendarName string, calendarId int64) (bool, error) {
+	var count int64
+	query := d.db.Model(&model.SysJobCalendar{}).Where("calendar_name = ?", calendarName)
+	if calendarId > 0 {
+		query = query.Where("calendar_id != ?", calendarId)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		fmt.Printf("CheckCalendarNameUnique: 检查日历名称唯一性失败: %v\n", err)
+		return false, err
+	}
+
+	return count == 0, nil
+}
+
+// CountJobsByCalendarIds 统计引用日历的任务数量
+func (d *JobCalendarDao) CountJobsByCalendarIds(calendarIds []int64) (int64, error) {
+	var count int64
+	err := d.db.Model(&model.SysJob{}).Where("calendar_id IN ?", calendarIds).Count(&count).Error
+	if err != nil {
+		fmt.Printf("CountJobsByCalendarIds: 统计引用日历的任务失败: %v\n", err)
+		return 0, err
+	}
+
+	return count, nil
+}
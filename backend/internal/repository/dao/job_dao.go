@@ -1,6 +1,7 @@
 package dao
 
 import (
+	"context"
 	"fmt"
 	"wosm/internal/repository/model"
 	"wosm/pkg/database"
@@ -20,6 +21,21 @@ func NewJobDao() *JobDao {
 	}
 }
 
+// WithContext 返回使用指定context的数据访问层实例，语句的context携带请求信息和租户
+func (d *JobDao) WithContext(ctx context.Context) *JobDao {
+	return &JobDao{db: d.db.WithContext(ctx)}
+}
+
+// WithTx 返回使用指定事务的数据访问层实例
+func (d *JobDao) WithTx(tx *gorm.DB) *JobDao {
+	return &JobDao{db: tx}
+}
+
+// Transaction 在事务中执行
+func (d *JobDao) Transaction(fn func(tx *gorm.DB) error) error {
+	return d.db.Transaction(fn)
+}
+
 // SelectJobList 查询调度任务日志集合 对应Java后端的selectJobList
 func (d *JobDao) SelectJobList(job *model.SysJob) ([]model.SysJob, error) {
 	var jobList []model.SysJob
@@ -109,6 +125,17 @@ func (d *JobDao) UpdateJob(job *model.SysJob) error {
 	return nil
 }
 
+// UpdateJobCalendar 修改任务引用的排除日历（Updates会忽略零值，取消日历时需要单独更新）
+func (d *JobDao) UpdateJobCalendar(jobId, calendarId int64) error {
+	err := d.db.Model(&model.SysJob{}).Where("job_id = ?", jobId).Update("calendar_id", calendarId).Error
+	if err != nil {
+		fmt.Printf("UpdateJobCalendar: 修改任务日历失败: %v\n", err)
+		return err
+	}
+
+	return nil
+}
+
 // DeleteJobById 通过调度ID删除调度任务信息 对应Java后端的deleteJobById
 func (d *JobDao) DeleteJobById(jobId int64) error {
 	err := d.db.Where("job_id = ?", jobId).Delete(&model.SysJob{}).Error
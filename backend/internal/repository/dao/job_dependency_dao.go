@@ -0,0 +1,104 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"wosm/internal/repository/model"
+	"wosm/pkg/database"
+
+	"gorm.io/gorm"
+)
+
+// JobDependencyDao 定时任务依赖关系数据访问层
+type JobDependencyDao struct {
+	db *gorm.DB
+}
+
+// NewJobDependencyDao 创建定时任务依赖关系数据访问层实例
+func NewJobDependencyDao() *JobDependencyDao {
+	return &JobDependencyDao{
+		db: database.GetDB(),
+	}
+}
+
+// WithContext 返回使用指定context的数据访问层实例，语句的context携带请求信息和租户
+func (d *JobDependencyDao) WithContext(ctx context.Context) *JobDependencyDao {
+	return &JobDependencyDao{db: d.db.WithContext(ctx)}
+}
+
+// WithTx 返回使用指定事务的数据访问层实例
+func (d *JobDependencyDao) WithTx(tx *gorm.DB) *JobDependencyDao {
+	return &JobDependencyDao{db: tx}
+}
+
+// SelectAllDependencies 查询所有任务依赖关系
+func (d *JobDependencyDao) SelectAllDependencies() ([]model.SysJobDependency, error) {
+	var deps []model.SysJobDependency
+	err := d.db.Order("dependency_id").Find(&deps).Error
+	if err != nil {
+		fmt.Printf("SelectAllDependencies: 查询任务依赖关系失败: %v\n", err)
+		return nil, err
+	}
+
+	return deps, nil
+}
+
+// SelectParentsByJobId 查询任务的上游依赖
+func (d *JobDependencyDao) SelectParentsByJobId(jobId int64) ([]model.SysJobDependency, error) {
+	var deps []model.SysJobDependency
+	err := d.db.Table("sys_job_dependency d").
+		Select("d.*, j.job_name AS parent_job_name").
+		Joins("LEFT JOIN sys_job j ON j.job_id = d.parent_job_id").
+		Where("d.job_id = ?", jobId).
+		Order("d.dependency_id").
+		Scan(&deps).Error
+	if err != nil {
+		fmt.Printf("SelectParentsByJobId: 查询上游依赖失败: %v\n", err)
+		return nil, err
+	}
+
+	return deps, nil
+}
+
+// SelectChildrenByJobId 查询任务的下游依赖
+func (d *JobDependencyDao) SelectChildrenByJobId(jobId int64) ([]model.SysJobDependency, error) {
+	var deps []model.SysJobDependency
+	err := d.db.Where("parent_job_id = ?", jobId).Order("dependency_id").Find(&deps).Error
+	if err != nil {
+		fmt.Printf("SelectChildrenByJobId: 查询下游依赖失败: %v\n", err)
+		return nil, err
+	}
+
+	return deps, nil
+}
+
+// ReplaceParents 替换任务的上游依赖（事务内先删后插）
+func (d *JobDependencyDao) ReplaceParents(jobId int64, deps []model.SysJobDependency) error {
+	err := d.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("job_id = ?", jobId).Delete(&model.SysJobDependency{}).Error; err != nil {
+			return err
+		}
+		if len(deps) == 0 {
+			return nil
+		}
+		return tx.Create(&deps).Error
+	})
+	if err != nil {
+		fmt.Printf("ReplaceParents: 保存任务依赖失败, JobID=%d, 错误=%v\n", jobId, err)
+		return err
+	}
+
+	fmt.Printf("ReplaceParents: 保存任务依赖成功, JobID=%d, 数量=%d\n", jobId, len(deps))
+	return nil
+}
+
+// DeleteByJobIds 删除与任务相关的全部依赖（包括作为上游和下游的依赖）
+func (d *JobDependencyDao) DeleteByJobIds(jobIds []int64) error {
+	err := d.db.Where("job_id IN ? OR parent_job_id IN ?", jobIds, jobIds).Delete(&model.SysJobDependency{}).Error
+	if err != nil {
+		fmt.Printf("DeleteByJobIds: 删除任务依赖失败: %v\n", err)
+		return err
+	}
+
+	return nil
+}
@@ -1,7 +1,9 @@
 package dao
 
 import (
+	"context"
 	"fmt"
+	"time"
 	"wosm/internal/repository/model"
 	"wosm/pkg/database"
 
@@ -20,10 +22,15 @@ func NewJobLogDao() *JobLogDao {
 	}
 }
 
+// WithContext 返回使用指定context的数据访问层实例，语句的context携带请求信息和租户
+func (d *JobLogDao) WithContext(ctx context.Context) *JobLogDao {
+	return &JobLogDao{db: d.db.WithContext(ctx)}
+}
+
 // SelectJobLogList 查询定时任务调度日志列表 对应Java后端的selectJobLogList
 func (d *JobLogDao) SelectJobLogList(jobLog *model.SysJobLog) ([]model.SysJobLog, error) {
 	var jobLogs []model.SysJobLog
-	query := d.db.Model(&model.SysJobLog{})
+	query := d.db.Model(&model.SysJobLog{}).Omit("log_content")
 
 	// 构建查询条件 - 严格按照Java后端的查询逻辑
 	if jobLog.JobName != "" {
@@ -42,11 +49,11 @@ func (d *JobLogDao) SelectJobLogList(jobLog *model.SysJobLog) ([]model.SysJobLog
 	// 时间范围查询 - 对应Java后端的date_format查询逻辑
 	if jobLog.StartTime != nil {
 		// 对应Java后端的 date_format(create_time,'%Y%m%d') >= date_format(#{params.beginTime},'%Y%m%d')
-		query = query.Where("CONVERT(date, create_time) >= CONVERT(date, ?)", jobLog.StartTime)
+		query = query.Where("create_time >= ?", beginOfDay(*jobLog.StartTime))
 	}
 	if jobLog.StopTime != nil {
 		// 对应Java后端的 date_format(create_time,'%Y%m%d') <= date_format(#{params.endTime},'%Y%m%d')
-		query = query.Where("CONVERT(date, create_time) <= CONVERT(date, ?)", jobLog.StopTime)
+		query = query.Where("create_time < ?", beginOfDay(*jobLog.StopTime).AddDate(0, 0, 1))
 	}
 
 	// 排序 - 对应Java后端的 order by create_time desc
@@ -66,7 +73,7 @@ func (d *JobLogDao) SelectJobLogList(jobLog *model.SysJobLog) ([]model.SysJobLog
 func (d *JobLogDao) SelectJobLogAll() ([]model.SysJobLog, error) {
 	var jobLogs []model.SysJobLog
 
-	err := d.db.Model(&model.SysJobLog{}).Order("create_time DESC").Find(&jobLogs).Error
+	err := d.db.Model(&model.SysJobLog{}).Omit("log_content").Order("create_time DESC").Find(&jobLogs).Error
 	if err != nil {
 		fmt.Printf("SelectJobLogAll: 查询所有定时任务调度日志失败: %v\n", err)
 		return nil, err
@@ -81,8 +88,8 @@ func (d *JobLogDao) SelectJobLogListWithPage(jobLog *model.SysJobLog, pageNum, p
 	var jobLogs []model.SysJobLog
 	var total int64
 
-	// 构建查询条件
-	query := d.db.Model(&model.SysJobLog{})
+	// 构建查询条件（列表不加载完整执行日志）
+	query := d.db.Model(&model.SysJobLog{}).Omit("log_content")
 	if jobLog.JobName != "" {
 		query = query.Where("job_name LIKE ?", "%"+jobLog.JobName+"%")
 	}
@@ -97,11 +104,11 @@ func (d *JobLogDao) SelectJobLogListWithPage(jobLog *model.SysJobLog, pageNum, p
 	}
 	if jobLog.StartTime != nil {
 		// 对应Java后端的 date_format(create_time,'%Y%m%d') >= date_format(#{params.beginTime},'%Y%m%d')
-		query = query.Where("CONVERT(date, create_time) >= CONVERT(date, ?)", jobLog.StartTime)
+		query = query.Where("create_time >= ?", beginOfDay(*jobLog.StartTime))
 	}
 	if jobLog.StopTime != nil {
 		// 对应Java后端的 date_format(create_time,'%Y%m%d') <= date_format(#{params.endTime},'%Y%m%d')
-		query = query.Where("CONVERT(date, create_time) <= CONVERT(date, ?)", jobLog.StopTime)
+		query = query.Where("create_time < ?", beginOfDay(*jobLog.StopTime).AddDate(0, 0, 1))
 	}
 
 	// 先查询总数
@@ -140,6 +147,19 @@ func (d *JobLogDao) SelectJobLogById(jobLogId int64) (*model.SysJobLog, error) {
 	return &jobLog, nil
 }
 
+// SelectLatestJobLogs 查询每个任务（按任务名称和组名）最近一次的调度日志
+func (d *JobLogDao) SelectLatestJobLogs() ([]model.SysJobLog, error) {
+	var jobLogs []model.SysJobLog
+	latest := d.db.Model(&model.SysJobLog{}).Select("MAX(job_log_id)").Group("job_name, job_group")
+	err := d.db.Omit("log_content").Where("job_log_id IN (?)", latest).Find(&jobLogs).Error
+	if err != nil {
+		fmt.Printf("SelectLatestJobLogs: 查询最近调度日志失败: %v\n", err)
+		return nil, err
+	}
+
+	return jobLogs, nil
+}
+
 // InsertJobLog 新增定时任务调度日志 对应Java后端的insertJobLog
 func (d *JobLogDao) InsertJobLog(jobLog *model.SysJobLog) error {
 	err := d.db.Create(jobLog).Error
@@ -152,6 +172,24 @@ func (d *JobLogDao) InsertJobLog(jobLog *model.SysJobLog) error {
 	return nil
 }
 
+// UpdateJobLogResult 更新调度日志的执行结果和完整执行日志
+func (d *JobLogDao) UpdateJobLogResult(jobLog *model.SysJobLog) error {
+	err := d.db.Model(&model.SysJobLog{}).
+		Where("job_log_id = ?", jobLog.JobLogID).
+		Updates(map[string]interface{}{
+			"job_message":    jobLog.JobMessage,
+			"status":         jobLog.Status,
+			"exception_info": jobLog.ExceptionInfo,
+			"log_content":    jobLog.LogContent,
+		}).Error
+	if err != nil {
+		fmt.Printf("UpdateJobLogResult: 更新定时任务调度日志失败: %v\n", err)
+		return err
+	}
+
+	return nil
+}
+
 // DeleteJobLogByIds 批量删除定时任务调度日志 对应Java后端的deleteJobLogByIds
 func (d *JobLogDao) DeleteJobLogByIds(jobLogIds []int64) error {
 	err := d.db.Where("job_log_id IN ?", jobLogIds).Delete(&model.SysJobLog{}).Error
@@ -178,7 +216,7 @@ func (d *JobLogDao) DeleteJobLogById(jobLogId int64) error {
 
 // CleanJobLog 清空定时任务调度日志 对应Java后端的cleanJobLog
 func (d *JobLogDao) CleanJobLog() error {
-	err := d.db.Exec("TRUNCATE TABLE sys_job_log").Error
+	err := d.db.Exec(database.CurrentDialect().Truncate("sys_job_log")).Error
 	if err != nil {
 		fmt.Printf("CleanJobLog: 清空定时任务调度日志失败: %v\n", err)
 		return err
@@ -187,3 +225,8 @@ func (d *JobLogDao) CleanJobLog() error {
 	fmt.Printf("CleanJobLog: 清空定时任务调度日志成功\n")
 	return nil
 }
+
+// beginOfDay 当天零点，日期范围查询按整天比较，避免使用各数据库不同的日期函数
+func beginOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -1,6 +1,7 @@
 package dao
 
 import (
+	"context"
 	"fmt"
 	"wosm/internal/repository/model"
 	"wosm/pkg/database"
@@ -20,6 +21,11 @@ func NewLoginLogDao() *LoginLogDao {
 	}
 }
 
+// WithContext 返回使用指定context的数据访问层实例，语句的context携带请求信息和租户
+func (d *LoginLogDao) WithContext(ctx context.Context) *LoginLogDao {
+	return &LoginLogDao{db: d.db.WithContext(ctx)}
+}
+
 // SelectLogininforList 查询系统登录日志集合 对应Java后端的selectLogininforList
 func (d *LoginLogDao) SelectLogininforList(logininfor *model.SysLogininfor) ([]model.SysLogininfor, error) {
 	var logininforList []model.SysLogininfor
@@ -78,8 +84,14 @@ func (d *LoginLogDao) DeleteLogininforByIds(infoIds []int) error {
 
 // CleanLogininfor 清空系统登录日志 对应Java后端的cleanLogininfor
 func (d *LoginLogDao) CleanLogininfor() error {
-	// 使用TRUNCATE清空表（SQL Server语法）
-	err := d.db.Exec("TRUNCATE TABLE sys_logininfor").Error
+	var err error
+	if tenantEnabled(d.db) {
+		// 租户模式下只删除当前租户的登录日志，不能清空整个表
+		err = d.db.Where("1 = 1").Delete(&model.SysLogininfor{}).Error
+	} else {
+		// 清空表的语法由方言提供（SQLite没有TRUNCATE）
+		err = d.db.Exec(database.CurrentDialect().Truncate("sys_logininfor")).Error
+	}
 	if err != nil {
 		fmt.Printf("CleanLogininfor: 清空登录日志失败: %v\n", err)
 		return err
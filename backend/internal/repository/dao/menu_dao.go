@@ -1,6 +1,7 @@
 package dao
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"wosm/internal/repository/model"
@@ -21,6 +22,11 @@ func NewMenuDao() *MenuDao {
 	}
 }
 
+// WithContext 返回使用指定context的数据访问层实例，语句的context携带请求信息和租户
+func (d *MenuDao) WithContext(ctx context.Context) *MenuDao {
+	return &MenuDao{db: d.db.WithContext(ctx)}
+}
+
 // SelectMenusByUserId 根据用户ID查询菜单 对应Java后端的selectMenusByUserId
 func (d *MenuDao) SelectMenusByUserId(userId int64) ([]model.SysMenu, error) {
 	var menus []model.SysMenu
@@ -223,9 +229,49 @@ func (d *MenuDao) InsertMenu(menu *model.SysMenu) error {
 	return d.db.Create(menu).Error
 }
 
+// WithTx 返回使用指定事务的数据访问层实例
+func (d *MenuDao) WithTx(tx *gorm.DB) *MenuDao {
+	return &MenuDao{db: tx}
+}
+
+// Transaction 在事务中执行
+func (d *MenuDao) Transaction(fn func(tx *gorm.DB) error) error {
+	return d.db.Transaction(fn)
+}
+
+// SelectMenuByPath 根据上级菜单和路由地址查询菜单
+func (d *MenuDao) SelectMenuByPath(parentId int64, path, menuType string) (*model.SysMenu, error) {
+	var menu model.SysMenu
+	err := d.db.Where("parent_id = ? AND path = ? AND menu_type = ?", parentId, path, menuType).First(&menu).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &menu, nil
+}
+
+// SelectMenuIdsByParentIds 查询指定菜单的直接子菜单ID
+func (d *MenuDao) SelectMenuIdsByParentIds(parentIds []int64) ([]int64, error) {
+	var menuIds []int64
+	err := d.db.Model(&model.SysMenu{}).Where("parent_id IN ?", parentIds).Pluck("menu_id", &menuIds).Error
+	return menuIds, err
+}
+
+// DeleteMenuByIds 批量删除菜单
+func (d *MenuDao) DeleteMenuByIds(menuIds []int64) error {
+	return d.db.Where("menu_id IN ?", menuIds).Delete(&model.SysMenu{}).Error
+}
+
 // UpdateMenu 修改菜单 对应Java后端的updateMenu
 func (d *MenuDao) UpdateMenu(menu *model.SysMenu) error {
-	return d.db.Save(menu).Error
+	// 与Save一致修改全部字段
+	updates, err := structUpdates(d.db, menu, true)
+	if err != nil {
+		return err
+	}
+	return updateWithVersion(d.db, &model.SysMenu{}, "menu_id", menu.MenuID, menu.Version, updates)
 }
 
 // DeleteMenuById 删除菜单 对应Java后端的deleteMenuById
@@ -298,8 +344,9 @@ func (d *MenuDao) SelectMenuPermsByUserId(userId int64) ([]string, error) {
 		return []string{}, nil
 	}
 
+	// 用户ID属于当前租户，关联的角色随用户确定，sys_role不再加租户条件
 	var rawPerms []string
-	err = d.db.Raw(sql, userId).Pluck("perms", &rawPerms).Error
+	err = IgnoreTenant(d.db).Raw(sql, userId).Pluck("perms", &rawPerms).Error
 	if err != nil {
 		fmt.Printf("SelectMenuPermsByUserId: 查询普通用户权限失败: %v\n", err)
 		return []string{}, nil
@@ -406,7 +453,8 @@ func (d *MenuDao) SelectMenuTreeByUserId(userId int64) ([]model.SysMenu, error)
 		ORDER BY m.parent_id, m.order_num
 	`
 
-	err := d.db.Raw(sql, userId).Scan(&menus).Error
+	// 用户ID属于当前租户，关联的角色随用户确定，sys_role不再加租户条件
+	err := IgnoreTenant(d.db).Raw(sql, userId).Scan(&menus).Error
 	if err != nil {
 		fmt.Printf("SelectMenuTreeByUserId: 查询用户菜单树失败: %v\n", err)
 		return nil, err
@@ -1,10 +1,11 @@
 package dao
 
 import (
+	"context"
 	"fmt"
-	"strings"
 	"wosm/internal/repository/model"
 	"wosm/pkg/database"
+	"wosm/pkg/datascope"
 
 	"gorm.io/gorm"
 )
@@ -21,6 +22,11 @@ func NewNoticeDao() *NoticeDao {
 	}
 }
 
+// WithContext 返回使用指定context的数据访问层实例，语句的context携带请求信息和租户
+func (d *NoticeDao) WithContext(ctx context.Context) *NoticeDao {
+	return &NoticeDao{db: d.db.WithContext(ctx)}
+}
+
 // SelectNoticeById 根据公告ID查询公告信息 对应Java后端的selectNoticeById
 func (d *NoticeDao) SelectNoticeById(noticeId int64) (*model.SysNotice, error) {
 	fmt.Printf("NoticeDao.SelectNoticeById: 查询公告信息, NoticeId=%d\n", noticeId)
@@ -37,8 +43,12 @@ func (d *NoticeDao) SelectNoticeById(noticeId int64) (*model.SysNotice, error) {
 	return &notice, nil
 }
 
+// noticeScopeColumns 通知公告的数据权限字段，公告没有部门字段，只按创建者匹配仅本人数据权限
+var noticeScopeColumns = datascope.Columns{Creator: "create_by"}
+
 // SelectNoticeList 查询公告列表 对应Java后端的selectNoticeList
-func (d *NoticeDao) SelectNoticeList(params *model.NoticeQueryParams) ([]model.SysNotice, error) {
+// scope为当前用户的数据权限，为nil时非管理员按CurrentUserName只查询本人创建的和已发布的公告
+func (d *NoticeDao) SelectNoticeList(params *model.NoticeQueryParams, scope *datascope.Predicate) ([]model.SysNotice, error) {
 	fmt.Printf("NoticeDao.SelectNoticeList: 查询公告列表\n")
 
 	var notices []model.SysNotice
@@ -71,19 +81,8 @@ func (d *NoticeDao) SelectNoticeList(params *model.NoticeQueryParams) ([]model.S
 	}
 
 	// 数据权限过滤 对应Java后端的数据权限控制
-	if params.DataScope != "" {
-		// 处理新的数据权限SQL
-		dataScopeSQL := strings.TrimSpace(params.DataScope)
-		if strings.HasPrefix(dataScopeSQL, "AND (") && strings.HasSuffix(dataScopeSQL, ")") {
-			dataScopeSQL = dataScopeSQL[5 : len(dataScopeSQL)-1] // 去掉 "AND (" 和 ")"
-		}
-		// 替换别名为实际字段名
-		dataScopeSQL = strings.ReplaceAll(dataScopeSQL, "d.dept_id", "dept_id")
-		dataScopeSQL = strings.ReplaceAll(dataScopeSQL, "c.create_by", "create_by")
-		if dataScopeSQL != "" {
-			query = query.Where(dataScopeSQL)
-			fmt.Printf("NoticeDao.SelectNoticeList: 应用数据权限SQL: %s\n", dataScopeSQL)
-		}
+	if scope != nil {
+		query = query.Scopes(scope.Scope(noticeScopeColumns))
 	} else if params.CurrentUserName != "" {
 		// 兼容旧的数据权限逻辑
 		// 非管理员用户只能查看：
@@ -131,7 +130,7 @@ func (d *NoticeDao) SelectNoticeList(params *model.NoticeQueryParams) ([]model.S
 }
 
 // CountNoticeList 统计公告总数 用于分页
-func (d *NoticeDao) CountNoticeList(params *model.NoticeQueryParams) (int64, error) {
+func (d *NoticeDao) CountNoticeList(params *model.NoticeQueryParams, scope *datascope.Predicate) (int64, error) {
 	fmt.Printf("NoticeDao.CountNoticeList: 统计公告总数\n")
 
 	var count int64
@@ -160,18 +159,8 @@ func (d *NoticeDao) CountNoticeList(params *model.NoticeQueryParams) (int64, err
 	}
 
 	// 数据权限过滤（与SelectNoticeList保持一致）
-	if params.DataScope != "" {
-		// 处理新的数据权限SQL
-		dataScopeSQL := strings.TrimSpace(params.DataScope)
-		if strings.HasPrefix(dataScopeSQL, "AND (") && strings.HasSuffix(dataScopeSQL, ")") {
-			dataScopeSQL = dataScopeSQL[5 : len(dataScopeSQL)-1] // 去掉 "AND (" 和 ")"
-		}
-		// 替换别名为实际字段名
-		dataScopeSQL = strings.ReplaceAll(dataScopeSQL, "d.dept_id", "dept_id")
-		dataScopeSQL = strings.ReplaceAll(dataScopeSQL, "c.create_by", "create_by")
-		if dataScopeSQL != "" {
-			query = query.Where(dataScopeSQL)
-		}
+	if scope != nil {
+		query = query.Scopes(scope.Scope(noticeScopeColumns))
 	} else if params.CurrentUserName != "" {
 		// 兼容旧的数据权限逻辑
 		query = query.Where("(create_by = ? OR status = ?)", params.CurrentUserName, model.NoticeStatusNormal)
@@ -1,6 +1,7 @@
 package dao
 
 import (
+	"context"
 	"fmt"
 	"wosm/internal/repository/model"
 	"wosm/pkg/database"
@@ -20,6 +21,11 @@ func NewOperLogDao() *OperLogDao {
 	}
 }
 
+// WithContext 返回使用指定context的数据访问层实例，语句的context携带请求信息和租户
+func (d *OperLogDao) WithContext(ctx context.Context) *OperLogDao {
+	return &OperLogDao{db: d.db.WithContext(ctx)}
+}
+
 // SelectOperLogList 查询系统操作日志集合 对应Java后端的selectOperLogList
 func (d *OperLogDao) SelectOperLogList(operLog *model.SysOperLog) ([]model.SysOperLog, error) {
 	var operLogs []model.SysOperLog
@@ -99,8 +105,14 @@ func (d *OperLogDao) DeleteOperLogByIds(operIds []int) error {
 
 // CleanOperLog 清空操作日志 对应Java后端的cleanOperLog
 func (d *OperLogDao) CleanOperLog() error {
-	// 使用TRUNCATE清空表（SQL Server语法）
-	err := d.db.Exec("TRUNCATE TABLE sys_oper_log").Error
+	var err error
+	if tenantEnabled(d.db) {
+		// 租户模式下只删除当前租户的操作日志，不能清空整个表
+		err = d.db.Where("1 = 1").Delete(&model.SysOperLog{}).Error
+	} else {
+		// 清空表的语法由方言提供（SQLite没有TRUNCATE）
+		err = d.db.Exec(database.CurrentDialect().Truncate("sys_oper_log")).Error
+	}
 	if err != nil {
 		fmt.Printf("CleanOperLog: 清空操作日志失败: %v\n", err)
 		return err
@@ -1,6 +1,7 @@
 package dao
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"wosm/internal/repository/model"
@@ -21,6 +22,11 @@ func NewPostDao() *PostDao {
 	}
 }
 
+// WithContext 返回使用指定context的数据访问层实例，语句的context携带请求信息和租户
+func (d *PostDao) WithContext(ctx context.Context) *PostDao {
+	return &PostDao{db: d.db.WithContext(ctx)}
+}
+
 // SelectPostList 查询岗位数据集合 对应Java后端的selectPostList
 func (d *PostDao) SelectPostList(post *model.SysPost) ([]model.SysPost, error) {
 	fmt.Printf("PostDao.SelectPostList: 查询岗位列表\n")
@@ -0,0 +1,268 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"wosm/internal/repository/model"
+	"wosm/pkg/database"
+
+	"gorm.io/gorm"
+)
+
+// recycleTable 回收站对象类型对应的业务表和关联表
+type recycleTable struct {
+	table     string   // 业务表
+	keyColumn string   // 主键列
+	relations []string // 彻底删除时一并清理的关联表（以主键列关联）
+}
+
+// recycleTables 回收站支持的对象类型
+var recycleTables = map[string]recycleTable{
+	model.RecycleTypeUser: {table: "sys_user", keyColumn: "user_id", relations: []string{"sys_user_role", "sys_user_post"}},
+	model.RecycleTypeDept: {table: "sys_dept", keyColumn: "dept_id", relations: []string{"sys_role_dept"}},
+	model.RecycleTypeRole: {table: "sys_role", keyColumn: "role_id", relations: []string{"sys_role_menu", "sys_role_dept", "sys_user_role"}},
+}
+
+// RecycleDao 回收站数据访问层
+type RecycleDao struct {
+	db *gorm.DB
+}
+
+// NewRecycleDao 创建回收站数据访问层实例
+func NewRecycleDao() *RecycleDao {
+	return &RecycleDao{
+		db: database.GetDB(),
+	}
+}
+
+// WithContext 返回使用指定context的数据访问层实例，语句的context携带请求信息和租户
+func (d *RecycleDao) WithContext(ctx context.Context) *RecycleDao {
+	return &RecycleDao{db: d.db.WithContext(ctx)}
+}
+
+// WithTx 返回使用指定事务的数据访问层实例
+func (d *RecycleDao) WithTx(tx *gorm.DB) *RecycleDao {
+	return &RecycleDao{db: tx}
+}
+
+// Transaction 在事务中执行
+func (d *RecycleDao) Transaction(fn func(tx *gorm.DB) error) error {
+	return d.db.Transaction(fn)
+}
+
+// SelectRecycleList 分页查询回收站列表
+func (d *RecycleDao) SelectRecycleList(recycle *model.SysRecycle, pageNum, pageSize int) ([]model.SysRecycle, int64, error) {
+	var recycles []model.SysRecycle
+	var total int64
+
+	query := d.db.Model(&model.SysRecycle{})
+	if recycle.ObjectType != "" {
+		query = query.Where("object_type = ?", recycle.ObjectType)
+	}
+	if recycle.ObjectName != "" {
+		query = query.Where("object_name LIKE ?", "%"+recycle.ObjectName+"%")
+	}
+	if recycle.DeleteBy != "" {
+		query = query.Where("delete_by LIKE ?", "%"+recycle.DeleteBy+"%")
+	}
+	if recycle.BeginTime != nil {
+		query = query.Where("delete_time >= ?", beginOfDay(*recycle.BeginTime))
+	}
+	if recycle.EndTime != nil {
+		query = query.Where("delete_time < ?", beginOfDay(*recycle.EndTime).AddDate(0, 0, 1))
+	}
+
+	err := query.Count(&total).Error
+	if err != nil {
+		fmt.Printf("SelectRecycleList: 查询回收站总数失败: %v\n", err)
+		return nil, 0, err
+	}
+
+	offset := (pageNum - 1) * pageSize
+	err = query.Order("delete_time DESC, recycle_id DESC").Offset(offset).Limit(pageSize).Find(&recycles).Error
+	if err != nil {
+		fmt.Printf("SelectRecycleList: 查询回收站列表失败: %v\n", err)
+		return nil, 0, err
+	}
+
+	fmt.Printf("SelectRecycleList: 查询到回收站数量=%d, 总数=%d\n", len(recycles), total)
+	return recycles, total, nil
+}
+
+// SelectRecycleById 查询回收站记录
+func (d *RecycleDao) SelectRecycleById(recycleId int64) (*model.SysRecycle, error) {
+	var recycle model.SysRecycle
+	err := d.db.Where("recycle_id = ?", recycleId).First(&recycle).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		fmt.Printf("SelectRecycleById: 查询回收站记录失败: %v\n", err)
+		return nil, err
+	}
+
+	return &recycle, nil
+}
+
+// SelectExpiredRecycles 查询删除时间早于指定时间的回收站记录
+func (d *RecycleDao) SelectExpiredRecycles(before time.Time) ([]model.SysRecycle, error) {
+	var recycles []model.SysRecycle
+	err := d.db.Where("delete_time < ?", before).Order("recycle_id").Find(&recycles).Error
+	if err != nil {
+		fmt.Printf("SelectExpiredRecycles: 查询过期回收站记录失败: %v\n", err)
+		return nil, err
+	}
+
+	return recycles, nil
+}
+
+// InsertRecycle 新增回收站记录
+func (d *RecycleDao) InsertRecycle(recycle *model.SysRecycle) error {
+	err := d.db.Create(recycle).Error
+	if err != nil {
+		fmt.Printf("InsertRecycle: 新增回收站记录失败: %v\n", err)
+		return err
+	}
+
+	fmt.Printf("InsertRecycle: 新增回收站记录成功, ObjectType=%s, ObjectID=%d\n", recycle.ObjectType, recycle.ObjectID)
+	return nil
+}
+
+// DeleteRecycleById 删除回收站记录
+func (d *RecycleDao) DeleteRecycleById(recycleId int64) error {
+	err := d.db.Where("recycle_id = ?", recycleId).Delete(&model.SysRecycle{}).Error
+	if err != nil {
+		fmt.Printf("DeleteRecycleById: 删除回收站记录失败: %v\n", err)
+		return err
+	}
+
+	return nil
+}
+
+// SelectDeletedUser 查询已删除的用户
+func (d *RecycleDao) SelectDeletedUser(userId int64) (*model.SysUser, error) {
+	var user model.SysUser
+	if err := d.selectDeleted(model.RecycleTypeUser, userId, &user); err != nil {
+		return nil, err
+	}
+	if user.UserID == 0 {
+		return nil, nil
+	}
+	return &user, nil
+}
+
+// SelectDeletedDept 查询已删除的部门
+func (d *RecycleDao) SelectDeletedDept(deptId int64) (*model.SysDept, error) {
+	var dept model.SysDept
+	if err := d.selectDeleted(model.RecycleTypeDept, deptId, &dept); err != nil {
+		return nil, err
+	}
+	if dept.DeptID == 0 {
+		return nil, nil
+	}
+	return &dept, nil
+}
+
+// SelectDeletedRole 查询已删除的角色
+func (d *RecycleDao) SelectDeletedRole(roleId int64) (*model.SysRole, error) {
+	var role model.SysRole
+	if err := d.selectDeleted(model.RecycleTypeRole, roleId, &role); err != nil {
+		return nil, err
+	}
+	if role.RoleID == 0 {
+		return nil, nil
+	}
+	return &role, nil
+}
+
+// selectDeleted 查询业务表中已删除（del_flag为2）的数据，不存在时dest保持零值
+func (d *RecycleDao) selectDeleted(objectType string, objectId int64, dest interface{}) error {
+	table := recycleTables[objectType]
+	err := d.db.Table(table.table).
+		Where(table.keyColumn+" = ? AND del_flag = '2'", objectId).
+		Limit(1).Find(dest).Error
+	if err != nil {
+		fmt.Printf("selectDeleted: 查询已删除的%s失败: %v\n", objectType, err)
+	}
+	return err
+}
+
+// RestoreObject 恢复已删除的数据（del_flag改为0）
+func (d *RecycleDao) RestoreObject(objectType string, objectId int64, updateBy string) error {
+	table := recycleTables[objectType]
+	result := d.db.Table(table.table).
+		Where(table.keyColumn+" = ? AND del_flag = '2'", objectId).
+		Updates(map[string]interface{}{"del_flag": "0", "update_by": updateBy, "update_time": time.Now()})
+	if result.Error != nil {
+		fmt.Printf("RestoreObject: 恢复%s失败: %v\n", objectType, result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("数据已被恢复或彻底删除")
+	}
+
+	fmt.Printf("RestoreObject: 恢复%s成功, ID=%d\n", objectType, objectId)
+	return nil
+}
+
+// PurgeObject 彻底删除已删除的数据及其关联数据
+func (d *RecycleDao) PurgeObject(objectType string, objectId int64) error {
+	table := recycleTables[objectType]
+	// 调用方已按租户查询到回收站记录和已删除的数据，按主键删除不再加租户条件
+	db := IgnoreTenant(d.db)
+	for _, relation := range table.relations {
+		if err := db.Exec("DELETE FROM "+relation+" WHERE "+table.keyColumn+" = ?", objectId).Error; err != nil {
+			fmt.Printf("PurgeObject: 删除%s关联数据失败: %v\n", relation, err)
+			return err
+		}
+	}
+
+	err := db.Exec("DELETE FROM "+table.table+" WHERE "+table.keyColumn+" = ? AND del_flag = '2'", objectId).Error
+	if err != nil {
+		fmt.Printf("PurgeObject: 彻底删除%s失败: %v\n", objectType, err)
+		return err
+	}
+
+	fmt.Printf("PurgeObject: 彻底删除%s成功, ID=%d\n", objectType, objectId)
+	return nil
+}
+
+// SelectNormalRoleIds 过滤出未删除的角色ID
+func (d *RecycleDao) SelectNormalRoleIds(roleIds []int64) ([]int64, error) {
+	return d.selectExistingIds("sys_role", "role_id", roleIds, true)
+}
+
+// SelectNormalDeptIds 过滤出未删除的部门ID
+func (d *RecycleDao) SelectNormalDeptIds(deptIds []int64) ([]int64, error) {
+	return d.selectExistingIds("sys_dept", "dept_id", deptIds, true)
+}
+
+// SelectExistingPostIds 过滤出存在的岗位ID
+func (d *RecycleDao) SelectExistingPostIds(postIds []int64) ([]int64, error) {
+	return d.selectExistingIds("sys_post", "post_id", postIds, false)
+}
+
+// SelectExistingMenuIds 过滤出存在的菜单ID
+func (d *RecycleDao) SelectExistingMenuIds(menuIds []int64) ([]int64, error) {
+	return d.selectExistingIds("sys_menu", "menu_id", menuIds, false)
+}
+
+// selectExistingIds 过滤出业务表中存在的ID
+func (d *RecycleDao) selectExistingIds(table, keyColumn string, ids []int64, hasDelFlag bool) ([]int64, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var existing []int64
+	query := d.db.Table(table).Where(keyColumn+" IN ?", ids)
+	if hasDelFlag {
+		query = query.Where("del_flag = '0'")
+	}
+	if err := query.Pluck(keyColumn, &existing).Error; err != nil {
+		fmt.Printf("selectExistingIds: 查询%s失败: %v\n", table, err)
+		return nil, err
+	}
+
+	return existing, nil
+}
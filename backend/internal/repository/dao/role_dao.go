@@ -1,9 +1,11 @@
 package dao
 
 import (
+	"context"
 	"fmt"
 	"wosm/internal/repository/model"
 	"wosm/pkg/database"
+	"wosm/pkg/datascope"
 
 	"gorm.io/gorm"
 )
@@ -20,8 +22,30 @@ func NewRoleDao() *RoleDao {
 	}
 }
 
+// WithContext 返回使用指定context的数据访问层实例，语句的context携带请求信息和租户
+func (d *RoleDao) WithContext(ctx context.Context) *RoleDao {
+	return &RoleDao{db: d.db.WithContext(ctx)}
+}
+
+// roleScopeColumns 角色的数据权限按拥有该角色的用户所在部门判断 对应Java后端的@DataScope(deptAlias = "d")
+var roleScopeColumns = datascope.Columns{Dept: "u.dept_id"}
+
+// roleDataScope 只查询拥有该角色的用户在数据权限范围内的角色，scope为nil时不过滤
+func (d *RoleDao) roleDataScope(scope *datascope.Predicate) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if scope == nil || scope.All {
+			return db
+		}
+		roleIds := d.db.Table("sys_user_role ur").Select("ur.role_id").
+			Joins("INNER JOIN sys_user u ON u.user_id = ur.user_id").
+			Scopes(scope.Scope(roleScopeColumns))
+		return db.Where("role_id IN (?)", roleIds)
+	}
+}
+
 // SelectRoleList 查询角色列表 对应Java后端的selectRoleList
-func (d *RoleDao) SelectRoleList(role *model.SysRole) ([]model.SysRole, error) {
+// scope为当前用户的数据权限，为nil时不过滤
+func (d *RoleDao) SelectRoleList(role *model.SysRole, scope *datascope.Predicate) ([]model.SysRole, error) {
 	var roles []model.SysRole
 	query := d.db.Model(&model.SysRole{})
 
@@ -42,22 +66,19 @@ func (d *RoleDao) SelectRoleList(role *model.SysRole) ([]model.SysRole, error) {
 	// 时间范围查询 对应Java后端的params.beginTime和params.endTime
 	if role.Params != nil {
 		if beginTime, ok := role.Params["beginTime"].(string); ok && beginTime != "" {
-			query = query.Where("DATE_FORMAT(create_time,'%Y%m%d') >= DATE_FORMAT(?,'%Y%m%d')", beginTime)
+			query = query.Where("create_time >= ?", beginTime+" 00:00:00")
 		}
 		if endTime, ok := role.Params["endTime"].(string); ok && endTime != "" {
-			query = query.Where("DATE_FORMAT(create_time,'%Y%m%d') <= DATE_FORMAT(?,'%Y%m%d')", endTime)
-		}
-
-		// 数据权限过滤 对应Java后端的${params.dataScope}
-		if dataScope, ok := role.Params["dataScope"].(string); ok && dataScope != "" {
-			// 直接添加数据权限SQL条件
-			query = query.Where(dataScope)
+			query = query.Where("create_time <= ?", endTime+" 23:59:59")
 		}
 	}
 
 	// 添加默认条件：未删除的角色
 	query = query.Where("del_flag = '0'")
 
+	// 数据权限过滤 对应Java后端的${params.dataScope}
+	query = query.Scopes(d.roleDataScope(scope))
+
 	// 排序 - 按照Java后端的排序规则
 	query = query.Order("role_sort, role_id")
 
@@ -166,7 +187,10 @@ func (d *RoleDao) InsertRole(role *model.SysRole) error {
 // UpdateRole 修改角色 对应Java后端的updateRole
 func (d *RoleDao) UpdateRole(role *model.SysRole) error {
 	// 基于Java后端真实数据库结构，包含menu_check_strictly和dept_check_strictly字段
-	err := d.db.Model(&model.SysRole{}).Where("role_id = ?", role.RoleID).Updates(role).Error
+	updates, err := structUpdates(d.db, role, false)
+	if err == nil {
+		err = updateWithVersion(d.db, &model.SysRole{}, "role_id", role.RoleID, role.Version, updates)
+	}
 	if err != nil {
 		fmt.Printf("UpdateRole: 修改角色失败: %v\n", err)
 		return err
@@ -253,7 +277,8 @@ func (d *RoleDao) SelectRolePermissionByUserId(userId int64) ([]model.SysRole, e
 }
 
 // SelectRoleListWithPage 分页查询角色列表 对应Java后端的selectRoleList + PageHelper
-func (d *RoleDao) SelectRoleListWithPage(role *model.SysRole, pageNum, pageSize int) ([]model.SysRole, int64, error) {
+// scope为当前用户的数据权限，为nil时不过滤
+func (d *RoleDao) SelectRoleListWithPage(role *model.SysRole, pageNum, pageSize int, scope *datascope.Predicate) ([]model.SysRole, int64, error) {
 	var roles []model.SysRole
 	var total int64
 
@@ -277,22 +302,19 @@ func (d *RoleDao) SelectRoleListWithPage(role *model.SysRole, pageNum, pageSize
 	// 时间范围查询 对应Java后端的params.beginTime和params.endTime
 	if role.Params != nil {
 		if beginTime, ok := role.Params["beginTime"].(string); ok && beginTime != "" {
-			query = query.Where("DATE_FORMAT(create_time,'%Y%m%d') >= DATE_FORMAT(?,'%Y%m%d')", beginTime)
+			query = query.Where("create_time >= ?", beginTime+" 00:00:00")
 		}
 		if endTime, ok := role.Params["endTime"].(string); ok && endTime != "" {
-			query = query.Where("DATE_FORMAT(create_time,'%Y%m%d') <= DATE_FORMAT(?,'%Y%m%d')", endTime)
-		}
-
-		// 数据权限过滤 对应Java后端的${params.dataScope}
-		if dataScope, ok := role.Params["dataScope"].(string); ok && dataScope != "" {
-			// 直接添加数据权限SQL条件
-			query = query.Where(dataScope)
+			query = query.Where("create_time <= ?", endTime+" 23:59:59")
 		}
 	}
 
 	// 添加默认条件：未删除的角色
 	query = query.Where("del_flag = '0'")
 
+	// 数据权限过滤 对应Java后端的${params.dataScope}
+	query = query.Scopes(d.roleDataScope(scope))
+
 	// 先查询总数
 	err := query.Count(&total).Error
 	if err != nil {
@@ -1,6 +1,7 @@
 package dao
 
 import (
+	"context"
 	"fmt"
 	"wosm/internal/repository/model"
 	"wosm/pkg/database"
@@ -20,6 +21,16 @@ func NewRoleDeptDao() *RoleDeptDao {
 	}
 }
 
+// WithContext 返回使用指定context的数据访问层实例，语句的context携带请求信息和租户
+func (d *RoleDeptDao) WithContext(ctx context.Context) *RoleDeptDao {
+	return &RoleDeptDao{db: d.db.WithContext(ctx)}
+}
+
+// WithTx 返回使用指定事务的数据访问层实例
+func (d *RoleDeptDao) WithTx(tx *gorm.DB) *RoleDeptDao {
+	return &RoleDeptDao{db: tx}
+}
+
 // SelectDeptListByRoleId 根据角色ID查询部门ID列表 对应Java后端的selectDeptListByRoleId
 func (d *RoleDeptDao) SelectDeptListByRoleId(roleId int64) ([]int64, error) {
 	var roleDepts []model.SysRoleDept
@@ -1,6 +1,7 @@
 package dao
 
 import (
+	"context"
 	"fmt"
 	"wosm/internal/repository/model"
 	"wosm/pkg/database"
@@ -20,6 +21,11 @@ func NewRoleMenuDao() *RoleMenuDao {
 	}
 }
 
+// WithContext 返回使用指定context的数据访问层实例，语句的context携带请求信息和租户
+func (d *RoleMenuDao) WithContext(ctx context.Context) *RoleMenuDao {
+	return &RoleMenuDao{db: d.db.WithContext(ctx)}
+}
+
 // SelectMenuListByRoleId 根据角色ID查询菜单ID列表 对应Java后端的selectMenuListByRoleId
 func (d *RoleMenuDao) SelectMenuListByRoleId(roleId int64, menuCheckStrictly bool) ([]int64, error) {
 	var menuIds []int64
@@ -50,6 +56,34 @@ func (d *RoleMenuDao) SelectMenuListByRoleId(roleId int64, menuCheckStrictly boo
 	return menuIds, nil
 }
 
+// WithTx 返回使用指定事务的数据访问层实例
+func (d *RoleMenuDao) WithTx(tx *gorm.DB) *RoleMenuDao {
+	return &RoleMenuDao{db: tx}
+}
+
+// SelectRoleIdsByMenuId 查询已关联指定菜单的角色ID
+func (d *RoleMenuDao) SelectRoleIdsByMenuId(menuId int64) ([]int64, error) {
+	var roleIds []int64
+	err := d.db.Model(&model.SysRoleMenu{}).Where("menu_id = ?", menuId).Pluck("role_id", &roleIds).Error
+	if err != nil {
+		fmt.Printf("SelectRoleIdsByMenuId: 查询菜单关联角色失败: %v\n", err)
+		return nil, err
+	}
+
+	return roleIds, nil
+}
+
+// DeleteRoleMenuByMenuIds 删除菜单的角色关联
+func (d *RoleMenuDao) DeleteRoleMenuByMenuIds(menuIds []int64) error {
+	err := d.db.Where("menu_id IN ?", menuIds).Delete(&model.SysRoleMenu{}).Error
+	if err != nil {
+		fmt.Printf("DeleteRoleMenuByMenuIds: 删除菜单角色关联失败: %v\n", err)
+		return err
+	}
+
+	return nil
+}
+
 // DeleteRoleMenuByRoleId 删除角色菜单关联 对应Java后端的deleteRoleMenuByRoleId
 func (d *RoleMenuDao) DeleteRoleMenuByRoleId(roleId int64) error {
 	err := d.db.Where("role_id = ?", roleId).Delete(&model.SysRoleMenu{}).Error
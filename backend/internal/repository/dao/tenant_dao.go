@@ -0,0 +1,488 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"wosm/internal/repository/model"
+	"wosm/pkg/database"
+
+	"gorm.io/gorm"
+)
+
+// TenantDao 租户和租户套餐数据访问层
+// 租户表不按租户过滤，只有平台租户的用户可以访问
+type TenantDao struct {
+	db *gorm.DB
+}
+
+// NewTenantDao 创建租户数据访问层实例
+func NewTenantDao() *TenantDao {
+	return &TenantDao{
+		db: database.GetDB(),
+	}
+}
+
+// WithContext 返回使用指定context的数据访问层实例，语句的context携带请求信息和租户
+func (d *TenantDao) WithContext(ctx context.Context) *TenantDao {
+	return &TenantDao{db: d.db.WithContext(ctx)}
+}
+
+// WithTx 返回使用指定事务的数据访问层实例
+func (d *TenantDao) WithTx(tx *gorm.DB) *TenantDao {
+	return &TenantDao{db: tx}
+}
+
+// Transaction 在事务中执行
+func (d *TenantDao) Transaction(fn func(tx *gorm.DB) error) error {
+	return d.db.Transaction(fn)
+}
+
+// SelectTenantList 分页查询租户列表
+func (d *TenantDao) SelectTenantList(params *model.TenantQueryParams) ([]model.SysTenant, int64, error) {
+	var tenants []model.SysTenant
+	var total int64
+
+	query := d.db.Model(&model.SysTenant{}).Where("del_flag = '0'")
+	if params.TenantName != "" {
+		query = query.Where("tenant_name LIKE ?", "%"+params.TenantName+"%")
+	}
+	if params.Domain != "" {
+		query = query.Where("domain LIKE ?", "%"+params.Domain+"%")
+	}
+	if params.Status != "" {
+		query = query.Where("status = ?", params.Status)
+	}
+
+	err := query.Count(&total).Error
+	if err != nil {
+		fmt.Printf("SelectTenantList: 查询租户总数失败: %v\n", err)
+		return nil, 0, err
+	}
+
+	query = query.Order("tenant_id")
+	if params.PageNum > 0 && params.PageSize > 0 {
+		query = query.Offset((params.PageNum - 1) * params.PageSize).Limit(params.PageSize)
+	}
+	err = query.Find(&tenants).Error
+	if err != nil {
+		fmt.Printf("SelectTenantList: 查询租户列表失败: %v\n", err)
+		return nil, 0, err
+	}
+
+	fmt.Printf("SelectTenantList: 查询到租户数量=%d, 总数=%d\n", len(tenants), total)
+	return tenants, total, nil
+}
+
+// SelectTenantAll 查询所有正常状态的租户，用于超级管理员切换租户
+func (d *TenantDao) SelectTenantAll() ([]model.SysTenant, error) {
+	var tenants []model.SysTenant
+	err := d.db.Where("del_flag = '0' AND status = '0'").Order("tenant_id").Find(&tenants).Error
+	if err != nil {
+		fmt.Printf("SelectTenantAll: 查询租户失败: %v\n", err)
+		return nil, err
+	}
+	return tenants, nil
+}
+
+// SelectTenantById 根据租户ID查询租户，不存在时返回nil
+func (d *TenantDao) SelectTenantById(tenantId int64) (*model.SysTenant, error) {
+	var tenant model.SysTenant
+	err := d.db.Where("tenant_id = ? AND del_flag = '0'", tenantId).First(&tenant).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		fmt.Printf("SelectTenantById: 查询租户失败: %v\n", err)
+		return nil, err
+	}
+	return &tenant, nil
+}
+
+// SelectTenantByDomain 根据绑定域名查询租户，不存在时返回nil
+func (d *TenantDao) SelectTenantByDomain(domain string) (*model.SysTenant, error) {
+	var tenant model.SysTenant
+	err := d.db.Where("domain = ? AND del_flag = '0'", domain).First(&tenant).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		fmt.Printf("SelectTenantByDomain: 查询租户失败: %v\n", err)
+		return nil, err
+	}
+	return &tenant, nil
+}
+
+// CheckTenantNameUnique 校验租户名称是否唯一
+func (d *TenantDao) CheckTenantNameUnique(tenantName string, tenantId int64) (bool, error) {
+	var count int64
+	err := d.db.Model(&model.SysTenant{}).
+		Where("tenant_name = ? AND tenant_id <> ? AND del_flag = '0'", tenantName, tenantId).
+		Count(&count).Error
+	if err != nil {
+		fmt.Printf("CheckTenantNameUnique: 校验租户名称失败: %v\n", err)
+		return false, err
+	}
+	return count == 0, nil
+}
+
+// CheckTenantDomainUnique 校验绑定域名是否唯一
+func (d *TenantDao) CheckTenantDomainUnique(domain string, tenantId int64) (bool, error) {
+	var count int64
+	err := d.db.Model(&model.SysTenant{}).
+		Where("domain = ? AND tenant_id <> ? AND del_flag = '0'", domain, tenantId).
+		Count(&count).Error
+	if err != nil {
+		fmt.Printf("CheckTenantDomainUnique: 校验绑定域名失败: %v\n", err)
+		return false, err
+	}
+	return count == 0, nil
+}
+
+// CountTenantByPackageId 统计使用套餐的租户数量
+func (d *TenantDao) CountTenantByPackageId(packageId int64) (int64, error) {
+	var count int64
+	err := d.db.Model(&model.SysTenant{}).Where("package_id = ? AND del_flag = '0'", packageId).Count(&count).Error
+	return count, err
+}
+
+// InsertTenant 新增租户
+func (d *TenantDao) InsertTenant(tenant *model.SysTenant) error {
+	err := d.db.Create(tenant).Error
+	if err != nil {
+		fmt.Printf("InsertTenant: 新增租户失败: %v\n", err)
+		return err
+	}
+
+	fmt.Printf("InsertTenant: 新增租户成功, TenantID=%d\n", tenant.TenantID)
+	return nil
+}
+
+// UpdateTenant 修改租户
+func (d *TenantDao) UpdateTenant(tenant *model.SysTenant) error {
+	err := d.db.Model(&model.SysTenant{}).Where("tenant_id = ?", tenant.TenantID).Updates(map[string]interface{}{
+		"tenant_name":       tenant.TenantName,
+		"domain":            tenant.Domain,
+		"package_id":        tenant.PackageID,
+		"contact_user_name": tenant.ContactUserName,
+		"contact_phone":     tenant.ContactPhone,
+		"expire_time":       tenant.ExpireTime,
+		"status":            tenant.Status,
+		"update_by":         tenant.UpdateBy,
+		"update_time":       tenant.UpdateTime,
+		"remark":            tenant.Remark,
+	}).Error
+	if err != nil {
+		fmt.Printf("UpdateTenant: 修改租户失败: %v\n", err)
+		return err
+	}
+	return nil
+}
+
+// DeleteTenantByIds 批量删除租户（逻辑删除），租户的业务数据保留
+func (d *TenantDao) DeleteTenantByIds(tenantIds []int64) error {
+	err := d.db.Model(&model.SysTenant{}).Where("tenant_id IN ?", tenantIds).Update("del_flag", "2").Error
+	if err != nil {
+		fmt.Printf("DeleteTenantByIds: 删除租户失败: %v\n", err)
+		return err
+	}
+	return nil
+}
+
+// InitTenantData 初始化新租户的根部门、管理员角色和管理员用户，角色拥有menuIds中的菜单
+// 在平台租户的请求中为其他租户写入数据，不按当前请求的租户填充
+func (d *TenantDao) InitTenantData(tenant *model.SysTenant, admin *model.SysUser, menuIds []int64) error {
+	db := WithoutTenant(d.db)
+	now := time.Now()
+
+	dept := &model.SysDept{
+		TenantID:   tenant.TenantID,
+		ParentID:   0,
+		Ancestors:  "0",
+		DeptName:   tenant.TenantName,
+		Leader:     tenant.ContactUserName,
+		Status:     "0",
+		DelFlag:    "0",
+		CreateBy:   tenant.CreateBy,
+		CreateTime: &now,
+	}
+	if err := db.Create(dept).Error; err != nil {
+		fmt.Printf("InitTenantData: 新增租户部门失败: %v\n", err)
+		return err
+	}
+
+	role := &model.SysRole{
+		TenantID:   tenant.TenantID,
+		RoleName:   "租户管理员",
+		RoleKey:    model.TenantAdminRoleKey,
+		RoleSort:   1,
+		DataScope:  "1",
+		Status:     "0",
+		DelFlag:    "0",
+		CreateBy:   tenant.CreateBy,
+		CreateTime: &now,
+	}
+	if err := db.Create(role).Error; err != nil {
+		fmt.Printf("InitTenantData: 新增租户管理员角色失败: %v\n", err)
+		return err
+	}
+
+	admin.TenantID = tenant.TenantID
+	admin.DeptID = &dept.DeptID
+	admin.CreateBy = tenant.CreateBy
+	admin.CreateTime = &now
+	if err := db.Create(admin).Error; err != nil {
+		fmt.Printf("InitTenantData: 新增租户管理员失败: %v\n", err)
+		return err
+	}
+
+	if err := db.Create(&model.SysUserRole{UserID: admin.UserID, RoleID: role.RoleID}).Error; err != nil {
+		fmt.Printf("InitTenantData: 新增租户管理员角色关联失败: %v\n", err)
+		return err
+	}
+	if len(menuIds) > 0 {
+		roleMenus := make([]model.SysRoleMenu, 0, len(menuIds))
+		for _, menuId := range menuIds {
+			roleMenus = append(roleMenus, model.SysRoleMenu{RoleID: role.RoleID, MenuID: menuId})
+		}
+		if err := db.CreateInBatches(roleMenus, 100).Error; err != nil {
+			fmt.Printf("InitTenantData: 新增租户管理员角色菜单失败: %v\n", err)
+			return err
+		}
+	}
+
+	fmt.Printf("InitTenantData: 初始化租户数据成功, TenantID=%d, AdminUserID=%d, 菜单数量=%d\n", tenant.TenantID, admin.UserID, len(menuIds))
+	return nil
+}
+
+// platformMenuIds 租户管理菜单ID的子查询
+func (d *TenantDao) platformMenuIds() *gorm.DB {
+	return d.db.Model(&model.SysMenu{}).Select("menu_id").Where("perms IN ?", model.TenantMenuPerms)
+}
+
+// SelectTenantMenuIds 套餐可以使用的菜单，packageId为0时为租户管理菜单以外的所有菜单
+func (d *TenantDao) SelectTenantMenuIds(packageId int64) ([]int64, error) {
+	var menuIds []int64
+	var err error
+	if packageId == 0 {
+		platformMenus := d.platformMenuIds()
+		err = d.db.Model(&model.SysMenu{}).
+			Where("menu_id NOT IN (?) AND parent_id NOT IN (?)", platformMenus, platformMenus).
+			Order("menu_id").Pluck("menu_id", &menuIds).Error
+	} else {
+		menuIds, err = d.SelectPackageMenuIds(packageId)
+	}
+	if err != nil {
+		fmt.Printf("SelectTenantMenuIds: 查询套餐菜单失败: %v\n", err)
+		return nil, err
+	}
+	return menuIds, nil
+}
+
+// ExcludePlatformMenus 去掉只有平台租户可以使用的租户管理菜单及其按钮，同时去掉不存在的菜单
+func (d *TenantDao) ExcludePlatformMenus(menuIds []int64) ([]int64, error) {
+	result := []int64{}
+	if len(menuIds) == 0 {
+		return result, nil
+	}
+	platformMenus := d.platformMenuIds()
+	err := d.db.Model(&model.SysMenu{}).
+		Where("menu_id IN ? AND menu_id NOT IN (?) AND parent_id NOT IN (?)", menuIds, platformMenus, platformMenus).
+		Order("menu_id").Pluck("menu_id", &result).Error
+	if err != nil {
+		fmt.Printf("ExcludePlatformMenus: 查询菜单失败: %v\n", err)
+		return nil, err
+	}
+	return result, nil
+}
+
+// SyncPackageRoleMenus 套餐菜单变化后同步使用该套餐的租户：删除角色中套餐以外的菜单，租户管理员角色拥有套餐的全部菜单
+func (d *TenantDao) SyncPackageRoleMenus(packageId int64, menuIds []int64) error {
+	db := WithoutTenant(d.db)
+	tenantIds := db.Model(&model.SysTenant{}).Select("tenant_id").Where("package_id = ? AND del_flag = '0'", packageId)
+
+	var roles []model.SysRole
+	if err := db.Select("role_id", "role_key").Where("tenant_id IN (?)", tenantIds).Find(&roles).Error; err != nil {
+		fmt.Printf("SyncPackageRoleMenus: 查询租户角色失败: %v\n", err)
+		return err
+	}
+	if len(roles) == 0 {
+		return nil
+	}
+
+	roleIds := make([]int64, 0, len(roles))
+	for _, role := range roles {
+		roleIds = append(roleIds, role.RoleID)
+	}
+	query := db.Where("role_id IN ?", roleIds)
+	if len(menuIds) > 0 {
+		query = query.Where("menu_id NOT IN ?", menuIds)
+	}
+	if err := query.Delete(&model.SysRoleMenu{}).Error; err != nil {
+		fmt.Printf("SyncPackageRoleMenus: 删除套餐以外的角色菜单失败: %v\n", err)
+		return err
+	}
+
+	for _, role := range roles {
+		if role.RoleKey != model.TenantAdminRoleKey {
+			continue
+		}
+		var existing []int64
+		if err := db.Model(&model.SysRoleMenu{}).Where("role_id = ?", role.RoleID).Pluck("menu_id", &existing).Error; err != nil {
+			return err
+		}
+		owned := make(map[int64]bool, len(existing))
+		for _, menuId := range existing {
+			owned[menuId] = true
+		}
+		var missing []model.SysRoleMenu
+		for _, menuId := range menuIds {
+			if !owned[menuId] {
+				missing = append(missing, model.SysRoleMenu{RoleID: role.RoleID, MenuID: menuId})
+			}
+		}
+		if len(missing) > 0 {
+			if err := db.CreateInBatches(missing, 100).Error; err != nil {
+				fmt.Printf("SyncPackageRoleMenus: 新增租户管理员角色菜单失败: %v\n", err)
+				return err
+			}
+		}
+	}
+
+	fmt.Printf("SyncPackageRoleMenus: 同步套餐角色菜单成功, PackageID=%d, 角色数量=%d\n", packageId, len(roles))
+	return nil
+}
+
+// SelectPackageList 分页查询租户套餐列表
+func (d *TenantDao) SelectPackageList(params *model.TenantPackageQueryParams) ([]model.SysTenantPackage, int64, error) {
+	var packages []model.SysTenantPackage
+	var total int64
+
+	query := d.db.Model(&model.SysTenantPackage{}).Where("del_flag = '0'")
+	if params.PackageName != "" {
+		query = query.Where("package_name LIKE ?", "%"+params.PackageName+"%")
+	}
+	if params.Status != "" {
+		query = query.Where("status = ?", params.Status)
+	}
+
+	err := query.Count(&total).Error
+	if err != nil {
+		fmt.Printf("SelectPackageList: 查询套餐总数失败: %v\n", err)
+		return nil, 0, err
+	}
+
+	query = query.Order("package_id")
+	if params.PageNum > 0 && params.PageSize > 0 {
+		query = query.Offset((params.PageNum - 1) * params.PageSize).Limit(params.PageSize)
+	}
+	err = query.Find(&packages).Error
+	if err != nil {
+		fmt.Printf("SelectPackageList: 查询套餐列表失败: %v\n", err)
+		return nil, 0, err
+	}
+	return packages, total, nil
+}
+
+// SelectPackageAll 查询所有正常状态的套餐
+func (d *TenantDao) SelectPackageAll() ([]model.SysTenantPackage, error) {
+	var packages []model.SysTenantPackage
+	err := d.db.Where("del_flag = '0' AND status = '0'").Order("package_id").Find(&packages).Error
+	if err != nil {
+		fmt.Printf("SelectPackageAll: 查询套餐失败: %v\n", err)
+		return nil, err
+	}
+	return packages, nil
+}
+
+// SelectPackageById 根据套餐ID查询套餐，不存在时返回nil
+func (d *TenantDao) SelectPackageById(packageId int64) (*model.SysTenantPackage, error) {
+	var pkg model.SysTenantPackage
+	err := d.db.Where("package_id = ? AND del_flag = '0'", packageId).First(&pkg).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		fmt.Printf("SelectPackageById: 查询套餐失败: %v\n", err)
+		return nil, err
+	}
+	return &pkg, nil
+}
+
+// SelectPackageMenuIds 查询套餐包含的菜单
+func (d *TenantDao) SelectPackageMenuIds(packageId int64) ([]int64, error) {
+	menuIds := []int64{}
+	err := d.db.Model(&model.SysTenantPackageMenu{}).Where("package_id = ?", packageId).
+		Order("menu_id").Pluck("menu_id", &menuIds).Error
+	return menuIds, err
+}
+
+// CheckPackageNameUnique 校验套餐名称是否唯一
+func (d *TenantDao) CheckPackageNameUnique(packageName string, packageId int64) (bool, error) {
+	var count int64
+	err := d.db.Model(&model.SysTenantPackage{}).
+		Where("package_name = ? AND package_id <> ? AND del_flag = '0'", packageName, packageId).
+		Count(&count).Error
+	if err != nil {
+		fmt.Printf("CheckPackageNameUnique: 校验套餐名称失败: %v\n", err)
+		return false, err
+	}
+	return count == 0, nil
+}
+
+// InsertPackage 新增套餐
+func (d *TenantDao) InsertPackage(pkg *model.SysTenantPackage) error {
+	err := d.db.Create(pkg).Error
+	if err != nil {
+		fmt.Printf("InsertPackage: 新增套餐失败: %v\n", err)
+		return err
+	}
+	return d.insertPackageMenus(pkg.PackageID, pkg.MenuIds)
+}
+
+// UpdatePackage 修改套餐并替换套餐菜单
+func (d *TenantDao) UpdatePackage(pkg *model.SysTenantPackage) error {
+	err := d.db.Model(&model.SysTenantPackage{}).Where("package_id = ?", pkg.PackageID).Updates(map[string]interface{}{
+		"package_name": pkg.PackageName,
+		"status":       pkg.Status,
+		"update_by":    pkg.UpdateBy,
+		"update_time":  pkg.UpdateTime,
+		"remark":       pkg.Remark,
+	}).Error
+	if err != nil {
+		fmt.Printf("UpdatePackage: 修改套餐失败: %v\n", err)
+		return err
+	}
+
+	if err := d.db.Where("package_id = ?", pkg.PackageID).Delete(&model.SysTenantPackageMenu{}).Error; err != nil {
+		fmt.Printf("UpdatePackage: 删除套餐菜单失败: %v\n", err)
+		return err
+	}
+	return d.insertPackageMenus(pkg.PackageID, pkg.MenuIds)
+}
+
+// DeletePackageByIds 批量删除套餐（逻辑删除）
+func (d *TenantDao) DeletePackageByIds(packageIds []int64) error {
+	err := d.db.Model(&model.SysTenantPackage{}).Where("package_id IN ?", packageIds).Update("del_flag", "2").Error
+	if err != nil {
+		fmt.Printf("DeletePackageByIds: 删除套餐失败: %v\n", err)
+		return err
+	}
+	return nil
+}
+
+// insertPackageMenus 新增套餐菜单
+func (d *TenantDao) insertPackageMenus(packageId int64, menuIds []int64) error {
+	if len(menuIds) == 0 {
+		return nil
+	}
+	packageMenus := make([]model.SysTenantPackageMenu, 0, len(menuIds))
+	for _, menuId := range menuIds {
+		packageMenus = append(packageMenus, model.SysTenantPackageMenu{PackageID: packageId, MenuID: menuId})
+	}
+	if err := d.db.CreateInBatches(packageMenus, 100).Error; err != nil {
+		fmt.Printf("insertPackageMenus: 新增套餐菜单失败: %v\n", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,234 @@
+package dao
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"wosm/internal/repository/model"
+	"wosm/pkg/database"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// tenantColumn 租户列
+const tenantColumn = "tenant_id"
+
+// tenantTables 按租户隔离的业务表，值为true的表共享平台租户的数据：查询时同时返回平台租户的行，租户新增同键名的行覆盖平台租户的值
+var tenantTables = map[string]bool{
+	"sys_user":        false,
+	"sys_dept":        false,
+	"sys_role":        false,
+	"sys_post":        false,
+	"sys_notice":      false,
+	"sys_oper_log":    false,
+	"sys_logininfor":  false,
+	"sys_recycle":     false,
+	"sys_data_change": false,
+	"sys_config":      true,
+	"sys_dict_data":   true,
+}
+
+// rawTablePattern 原生SQL中FROM、JOIN、INTO、UPDATE、TABLE之后的表名
+var rawTablePattern = regexp.MustCompile("(?i)\\b(?:FROM|JOIN|INTO|UPDATE|TABLE)\\s+[`\"\\[]?(\\w+)")
+
+// ErrTenantRequired 租户模式下访问按租户隔离的表时语句context未设置租户
+var ErrTenantRequired = errors.New("未确定当前租户，不能访问按租户隔离的数据")
+
+// ErrTenantRawSQL 原生SQL访问按租户隔离的表时不能自动加入租户条件
+var ErrTenantRawSQL = errors.New("原生SQL不能自动加入租户条件")
+
+// tenantIgnoreKey 不按租户过滤和填充的语句设置
+const tenantIgnoreKey = "wosm:tenant_ignore"
+
+// tenantOwnKey 共享平台租户数据的表只查询当前租户的行的语句设置
+const tenantOwnKey = "wosm:tenant_own"
+
+// IgnoreTenant 不按租户过滤和填充，用于平台管理租户数据（如创建租户的管理员）和跨租户的查询
+func IgnoreTenant(db *gorm.DB) *gorm.DB {
+	return db.Set(tenantIgnoreKey, true)
+}
+
+// OwnTenant 共享平台租户数据的表只查询当前租户的行，用于租户内的唯一性校验
+func OwnTenant(db *gorm.DB) *gorm.DB {
+	return db.Set(tenantOwnKey, true)
+}
+
+// WithoutTenant 不按租户过滤和填充的会话，可以执行多条语句（如在事务中创建租户的部门、角色和用户）
+func WithoutTenant(db *gorm.DB) *gorm.DB {
+	return db.Set(tenantIgnoreKey, true).Session(&gorm.Session{})
+}
+
+// preferTenantRows 共享平台租户数据的表优先返回当前租户的行（在其他排序之前调用）
+// context未设置租户（如登录前读取验证码开关）时只返回平台租户的行
+func preferTenantRows(db *gorm.DB) *gorm.DB {
+	if database.RequestTenant(db.Statement.Context) == 0 {
+		return IgnoreTenant(db.Where(tenantColumn+" = ?", model.DefaultTenantID))
+	}
+	return db.Order(tenantColumn + " DESC")
+}
+
+// TenantPlugin 多租户插件 在按租户隔离的表的查询、修改和删除中加入语句context中租户的条件，新增时写入该租户
+// context未设置租户时拒绝访问按租户隔离的表，平台级的处理使用IgnoreTenant或 database.WithAllTenants
+// Raw和Exec执行的SQL不能加入条件，访问按租户隔离的表时必须使用IgnoreTenant确认已按租户限定
+type TenantPlugin struct{}
+
+// NewTenantPlugin 创建多租户插件
+func NewTenantPlugin() *TenantPlugin {
+	return &TenantPlugin{}
+}
+
+// Name 插件名称
+func (p *TenantPlugin) Name() string {
+	return "wosm:tenant"
+}
+
+// Initialize 注册回调，在其他回调之前执行，数据变更记录等插件读取的条件已包含租户
+func (p *TenantPlugin) Initialize(db *gorm.DB) error {
+	if err := db.Callback().Query().Before("*").Register("wosm:tenant_query", p.query); err != nil {
+		return err
+	}
+	if err := db.Callback().Row().Before("*").Register("wosm:tenant_row", p.query); err != nil {
+		return err
+	}
+	if err := db.Callback().Raw().Before("*").Register("wosm:tenant_raw", p.raw); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().Before("*").Register("wosm:tenant_update", p.modify); err != nil {
+		return err
+	}
+	if err := db.Callback().Delete().Before("*").Register("wosm:tenant_delete", p.modify); err != nil {
+		return err
+	}
+	return db.Callback().Create().Before("*").Register("wosm:tenant_create", p.create)
+}
+
+// query 查询当前租户的行，共享平台租户数据的表同时查询平台租户的行
+func (p *TenantPlugin) query(db *gorm.DB) {
+	if db.Statement.SQL.Len() > 0 {
+		p.raw(db)
+		return
+	}
+	tenantID, shared, ok := p.tenantOf(db)
+	if !ok {
+		return
+	}
+	column := clause.Column{Table: db.Statement.Table, Name: tenantColumn}
+	if shared && tenantID != model.DefaultTenantID {
+		if _, own := db.Get(tenantOwnKey); !own {
+			db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+				clause.IN{Column: column, Values: []interface{}{tenantID, model.DefaultTenantID}},
+			}})
+			return
+		}
+	}
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{clause.Eq{Column: column, Value: tenantID}}})
+}
+
+// modify 修改和删除只处理当前租户的行，租户不能修改平台租户共享的行
+func (p *TenantPlugin) modify(db *gorm.DB) {
+	tenantID, _, ok := p.tenantOf(db)
+	if !ok {
+		return
+	}
+	// 没有条件的修改和删除由GORM报错（ErrMissingWhereClause），不能因为租户条件变为修改整个租户的数据
+	if _, hasWhere := db.Statement.Clauses["WHERE"]; !hasWhere && !db.Statement.AllowGlobalUpdate && !hasPrimaryKeyValue(db) {
+		return
+	}
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: db.Statement.Table, Name: tenantColumn}, Value: tenantID},
+	}})
+}
+
+// create 新增的行属于当前租户，忽略客户端提交的租户ID
+func (p *TenantPlugin) create(db *gorm.DB) {
+	tenantID, _, ok := p.tenantOf(db)
+	if !ok || db.Statement.Schema == nil {
+		return
+	}
+	field := db.Statement.Schema.LookUpField(tenantColumn)
+	if field == nil {
+		return
+	}
+	rv := db.Statement.ReflectValue
+	switch rv.Kind() {
+	case reflect.Struct:
+		_ = field.Set(db.Statement.Context, rv, tenantID)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			_ = field.Set(db.Statement.Context, reflect.Indirect(rv.Index(i)), tenantID)
+		}
+	}
+}
+
+// raw Raw和Exec执行的SQL访问按租户隔离的表时报错
+func (p *TenantPlugin) raw(db *gorm.DB) {
+	if db.Error != nil || p.ignored(db) {
+		return
+	}
+	for _, match := range rawTablePattern.FindAllStringSubmatch(db.Statement.SQL.String(), -1) {
+		if _, ok := tenantTables[strings.ToLower(match[1])]; ok {
+			_ = db.AddError(fmt.Errorf("%w: %s", ErrTenantRawSQL, match[1]))
+			return
+		}
+	}
+}
+
+// tenantOf 语句需要按租户处理时返回语句context中的租户和表是否共享平台租户数据，未设置租户时语句报错
+func (p *TenantPlugin) tenantOf(db *gorm.DB) (tenantID int64, shared, ok bool) {
+	if db.Error != nil || db.Statement.SQL.Len() > 0 || p.ignored(db) {
+		return 0, false, false
+	}
+	shared, ok = tenantTables[tenantTableName(db.Statement)]
+	if !ok {
+		return 0, false, false
+	}
+	tenantID = database.RequestTenant(db.Statement.Context)
+	if tenantID == 0 {
+		_ = db.AddError(fmt.Errorf("%w: %s", ErrTenantRequired, tenantTableName(db.Statement)))
+		return 0, false, false
+	}
+	return tenantID, shared, true
+}
+
+// ignored 语句使用了IgnoreTenant或context访问全部租户的数据
+func (p *TenantPlugin) ignored(db *gorm.DB) bool {
+	if _, ignore := db.Get(tenantIgnoreKey); ignore {
+		return true
+	}
+	return database.AllTenants(db.Statement.Context)
+}
+
+// tenantEnabled 是否注册了多租户插件（开启租户模式）
+func tenantEnabled(db *gorm.DB) bool {
+	_, ok := db.Config.Plugins[(&TenantPlugin{}).Name()]
+	return ok
+}
+
+// tenantTableName 语句的表名；Table("sys_user u")时Statement.Table为别名，表名取表达式的第一个词
+func tenantTableName(stmt *gorm.Statement) string {
+	if stmt.TableExpr != nil {
+		if fields := strings.Fields(stmt.TableExpr.SQL); len(fields) > 0 {
+			return strings.Trim(fields[0], "`\"[]")
+		}
+	}
+	return stmt.Table
+}
+
+// hasPrimaryKeyValue 按结构体修改和删除时是否有主键值，GORM在之后的回调中按主键加入条件
+func hasPrimaryKeyValue(db *gorm.DB) bool {
+	stmt := db.Statement
+	if stmt.Schema == nil || stmt.Schema.PrioritizedPrimaryField == nil {
+		return false
+	}
+	switch stmt.ReflectValue.Kind() {
+	case reflect.Struct:
+		_, isZero := stmt.Schema.PrioritizedPrimaryField.ValueOf(stmt.Context, stmt.ReflectValue)
+		return !isZero
+	case reflect.Slice, reflect.Array:
+		return stmt.ReflectValue.Len() > 0
+	}
+	return false
+}
@@ -1,11 +1,14 @@
 package dao
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 	"wosm/internal/repository/model"
 	"wosm/pkg/database"
+	"wosm/pkg/datascope"
 
 	"gorm.io/gorm"
 )
@@ -15,6 +18,12 @@ type UserDao struct {
 	db *gorm.DB
 }
 
+// userScopeColumns 用户列表（单表查询）的数据权限字段 对应Java后端的@DataScope(deptAlias = "d", userAlias = "u")
+var userScopeColumns = datascope.Columns{Dept: "dept_id", User: "user_id"}
+
+// authUserScopeColumns 角色分配用户列表（关联sys_user u、sys_dept d）的数据权限字段
+var authUserScopeColumns = datascope.Columns{Dept: "d.dept_id", User: "u.user_id"}
+
 // NewUserDao 创建用户DAO
 func NewUserDao() *UserDao {
 	return &UserDao{
@@ -22,6 +31,11 @@ func NewUserDao() *UserDao {
 	}
 }
 
+// WithContext 返回使用指定context的数据访问层实例，语句的context携带请求信息和租户
+func (d *UserDao) WithContext(ctx context.Context) *UserDao {
+	return &UserDao{db: d.db.WithContext(ctx)}
+}
+
 // SelectUserByLoginName 根据用户名查询用户 对应Java后端的selectUserByLoginName
 func (d *UserDao) SelectUserByLoginName(loginName string) (*model.SysUser, error) {
 	var user model.SysUser
@@ -73,7 +87,8 @@ func (d *UserDao) SelectUserById(userId int64) (*model.SysUser, error) {
 }
 
 // SelectUserList 查询用户列表 对应Java后端的selectUserList
-func (d *UserDao) SelectUserList(user *model.SysUser, pageNum, pageSize int) ([]model.SysUser, int64, error) {
+// scope为当前用户的数据权限，为nil时不过滤
+func (d *UserDao) SelectUserList(user *model.SysUser, pageNum, pageSize int, scope *datascope.Predicate) ([]model.SysUser, int64, error) {
 	var users []model.SysUser
 	var total int64
 
@@ -118,27 +133,8 @@ func (d *UserDao) SelectUserList(user *model.SysUser, pageNum, pageSize int) ([]
 		query = query.Where("phonenumber LIKE ?", "%"+user.Phonenumber+"%")
 	}
 
-	// 处理数据权限 对应Java后端的${params.dataScope}
-	if user.Params != nil {
-		if dataScope, exists := user.Params["dataScope"]; exists && dataScope != "" {
-			dataScopeSQL := fmt.Sprintf("%v", dataScope)
-			if dataScopeSQL != "" {
-				// 数据权限SQL已经包含AND前缀，需要去掉前缀直接拼接
-				// 格式：" AND (d.dept_id = 0)" -> "d.dept_id = 0"
-				cleanSQL := strings.TrimSpace(dataScopeSQL)
-				if strings.HasPrefix(cleanSQL, "AND (") && strings.HasSuffix(cleanSQL, ")") {
-					cleanSQL = cleanSQL[5 : len(cleanSQL)-1] // 去掉 "AND (" 和 ")"
-				}
-				// 需要将别名替换为实际字段名，因为这里不使用JOIN查询
-				cleanSQL = strings.ReplaceAll(cleanSQL, "d.dept_id", "dept_id")
-				cleanSQL = strings.ReplaceAll(cleanSQL, "u.user_id", "user_id")
-				cleanSQL = strings.ReplaceAll(cleanSQL, "c.create_by", "create_by")
-				if cleanSQL != "" {
-					query = query.Where(cleanSQL)
-				}
-			}
-		}
-	}
+	// 数据权限 对应Java后端的${params.dataScope}
+	query = query.Scopes(scope.Scope(userScopeColumns))
 
 	// 先查询总数
 	err := query.Model(&model.SysUser{}).Count(&total).Error
@@ -240,7 +236,10 @@ func (d *UserDao) UpdateUser(user *model.SysUser) error {
 
 	fmt.Printf("UserDao.UpdateUser: 准备更新字段: %v\n", updates)
 
-	err := d.db.Model(&model.SysUser{}).Where("user_id = ?", user.UserID).Updates(updates).Error
+	err := updateWithVersion(d.db, &model.SysUser{}, "user_id", user.UserID, user.Version, updates)
+	if errors.Is(err, ErrVersionConflict) {
+		return err
+	}
 	if err != nil {
 		fmt.Printf("UserDao.UpdateUser: 修改用户信息失败: %v\n", err)
 		return fmt.Errorf("修改用户信息失败: %v", err)
@@ -259,7 +258,7 @@ func (d *UserDao) UpdateUserLoginInfo(userId int64, loginIP string) error {
 		Where("user_id = ?", userId).
 		Updates(map[string]interface{}{
 			"login_ip":   loginIP,
-			"login_date": gorm.Expr("GETDATE()"),
+			"login_date": time.Now(),
 		}).Error
 
 	if err != nil {
@@ -376,6 +375,7 @@ func (d *UserDao) UpdateUserProfile(user *model.SysUser) error {
 		"phonenumber": user.Phonenumber,
 		"sex":         user.Sex,
 		"update_time": time.Now(),
+		versionColumn: nextVersion,
 	}
 
 	err := d.db.Model(&model.SysUser{}).Where("user_id = ?", user.UserID).Updates(updates).Error
@@ -425,7 +425,7 @@ func (d *UserDao) UpdateUserAvatar(userId int64, avatar string) error {
 }
 
 // SelectAllocatedList 查询已分配用户角色列表 对应Java后端的selectAllocatedList
-func (d *UserDao) SelectAllocatedList(currentUser *model.SysUser, user *model.SysUser, pageNum, pageSize int) ([]model.SysUser, int64, error) {
+func (d *UserDao) SelectAllocatedList(user *model.SysUser, pageNum, pageSize int, scope *datascope.Predicate) ([]model.SysUser, int64, error) {
 	roleId := *user.RoleID
 	fmt.Printf("UserDao.SelectAllocatedList: 查询已分配用户角色列表, RoleID=%d\n", roleId)
 
@@ -455,23 +455,8 @@ func (d *UserDao) SelectAllocatedList(currentUser *model.SysUser, user *model.Sy
 		query = query.Where("u.phonenumber LIKE ?", "%"+user.Phonenumber+"%")
 	}
 
-	// 处理数据权限 对应Java后端的${params.dataScope}
-	if user.Params != nil {
-		if dataScope, exists := user.Params["dataScope"]; exists && dataScope != "" {
-			dataScopeSQL := fmt.Sprintf("%v", dataScope)
-			if dataScopeSQL != "" {
-				// 数据权限SQL已经包含AND前缀，需要去掉前缀直接拼接
-				// 格式：" AND (d.dept_id = 0)" -> "d.dept_id = 0"
-				cleanSQL := strings.TrimSpace(dataScopeSQL)
-				if strings.HasPrefix(cleanSQL, "AND (") && strings.HasSuffix(cleanSQL, ")") {
-					cleanSQL = cleanSQL[5 : len(cleanSQL)-1] // 去掉 "AND (" 和 ")"
-				}
-				if cleanSQL != "" {
-					query = query.Where(cleanSQL)
-				}
-			}
-		}
-	}
+	// 数据权限 对应Java后端的${params.dataScope}
+	query = query.Scopes(scope.Scope(authUserScopeColumns))
 
 	// 查询总数
 	err := query.Count(&total).Error
@@ -493,7 +478,7 @@ func (d *UserDao) SelectAllocatedList(currentUser *model.SysUser, user *model.Sy
 }
 
 // SelectUnallocatedList 查询未分配用户角色列表 对应Java后端的selectUnallocatedList
-func (d *UserDao) SelectUnallocatedList(currentUser *model.SysUser, user *model.SysUser, pageNum, pageSize int) ([]model.SysUser, int64, error) {
+func (d *UserDao) SelectUnallocatedList(user *model.SysUser, pageNum, pageSize int, scope *datascope.Predicate) ([]model.SysUser, int64, error) {
 	roleId := *user.RoleID
 	fmt.Printf("UserDao.SelectUnallocatedList: 查询未分配用户角色列表, RoleID=%d\n", roleId)
 
@@ -525,23 +510,8 @@ func (d *UserDao) SelectUnallocatedList(currentUser *model.SysUser, user *model.
 		query = query.Where("u.phonenumber LIKE ?", "%"+user.Phonenumber+"%")
 	}
 
-	// 处理数据权限 对应Java后端的${params.dataScope}
-	if user.Params != nil {
-		if dataScope, exists := user.Params["dataScope"]; exists && dataScope != "" {
-			dataScopeSQL := fmt.Sprintf("%v", dataScope)
-			if dataScopeSQL != "" {
-				// 数据权限SQL已经包含AND前缀，需要去掉前缀直接拼接
-				// 格式：" AND (d.dept_id = 0)" -> "d.dept_id = 0"
-				cleanSQL := strings.TrimSpace(dataScopeSQL)
-				if strings.HasPrefix(cleanSQL, "AND (") && strings.HasSuffix(cleanSQL, ")") {
-					cleanSQL = cleanSQL[5 : len(cleanSQL)-1] // 去掉 "AND (" 和 ")"
-				}
-				if cleanSQL != "" {
-					query = query.Where(cleanSQL)
-				}
-			}
-		}
-	}
+	// 数据权限 对应Java后端的${params.dataScope}
+	query = query.Scopes(scope.Scope(authUserScopeColumns))
 
 	// 查询总数
 	err := query.Count(&total).Error
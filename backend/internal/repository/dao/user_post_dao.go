@@ -1,6 +1,7 @@
 package dao
 
 import (
+	"context"
 	"fmt"
 	"wosm/internal/repository/model"
 	"wosm/pkg/database"
@@ -20,6 +21,16 @@ func NewUserPostDao() *UserPostDao {
 	}
 }
 
+// WithContext 返回使用指定context的数据访问层实例，语句的context携带请求信息和租户
+func (d *UserPostDao) WithContext(ctx context.Context) *UserPostDao {
+	return &UserPostDao{db: d.db.WithContext(ctx)}
+}
+
+// WithTx 返回使用指定事务的数据访问层实例
+func (d *UserPostDao) WithTx(tx *gorm.DB) *UserPostDao {
+	return &UserPostDao{db: tx}
+}
+
 // DeleteUserPostByUserId 根据用户ID删除用户岗位关联 对应Java后端的deleteUserPost
 func (d *UserPostDao) DeleteUserPostByUserId(userId int64) error {
 	fmt.Printf("UserPostDao.DeleteUserPostByUserId: 删除用户岗位关联, UserId=%d\n", userId)
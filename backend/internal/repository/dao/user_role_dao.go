@@ -1,6 +1,7 @@
 package dao
 
 import (
+	"context"
 	"fmt"
 	"wosm/internal/repository/model"
 	"wosm/pkg/database"
@@ -20,6 +21,16 @@ func NewUserRoleDao() *UserRoleDao {
 	}
 }
 
+// WithContext 返回使用指定context的数据访问层实例，语句的context携带请求信息和租户
+func (d *UserRoleDao) WithContext(ctx context.Context) *UserRoleDao {
+	return &UserRoleDao{db: d.db.WithContext(ctx)}
+}
+
+// WithTx 返回使用指定事务的数据访问层实例
+func (d *UserRoleDao) WithTx(tx *gorm.DB) *UserRoleDao {
+	return &UserRoleDao{db: tx}
+}
+
 // SelectRoleListByUserId 根据用户ID查询角色ID列表 对应Java后端的selectRoleListByUserId
 func (d *UserRoleDao) SelectRoleListByUserId(userId int64) ([]int64, error) {
 	var userRoles []model.SysUserRole
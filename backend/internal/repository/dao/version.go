@@ -0,0 +1,62 @@
+package dao
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+)
+
+// ErrVersionConflict 乐观锁冲突：数据在客户端读取之后已被其他请求修改
+var ErrVersionConflict = errors.New("数据已被其他用户修改，请刷新后重试")
+
+// versionColumn 乐观锁版本号列
+const versionColumn = "version"
+
+// nextVersion 版本号加1，不校验版本号的修改（如批量修改状态）也需要加1
+var nextVersion = gorm.Expr(versionColumn + " + 1")
+
+// updateWithVersion 按主键修改并将版本号加1
+// version大于0时要求与数据库中的版本号一致，不一致返回ErrVersionConflict；为0时不校验，用于系统内部的修改和修改状态等局部修改
+// 修改接口通过utils.RequireVersion要求客户端携带版本号
+func updateWithVersion(db *gorm.DB, model interface{}, keyColumn string, id, version int64, updates map[string]interface{}) error {
+	updates[versionColumn] = nextVersion
+
+	query := db.Model(model).Where(keyColumn+" = ?", id)
+	if version > 0 {
+		query = query.Where(versionColumn+" = ?", version)
+	}
+	result := query.Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if version > 0 && result.RowsAffected == 0 {
+		fmt.Printf("updateWithVersion: 版本号不一致, %s=%d, Version=%d\n", keyColumn, id, version)
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+// structUpdates 将结构体转为修改字段映射，不含主键和版本号
+// allFields为false时与Updates(struct)一致只包含非零值字段，为true时与Save一致包含全部字段
+func structUpdates(db *gorm.DB, value interface{}, allFields bool) (map[string]interface{}, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(value); err != nil {
+		return nil, err
+	}
+
+	reflectValue := reflect.Indirect(reflect.ValueOf(value))
+	updates := make(map[string]interface{})
+	for _, field := range stmt.Schema.Fields {
+		if field.DBName == "" || field.PrimaryKey || !field.Updatable || field.DBName == versionColumn {
+			continue
+		}
+		fieldValue, isZero := field.ValueOf(stmt.Context, reflectValue)
+		if isZero && !allFields {
+			continue
+		}
+		updates[field.DBName] = fieldValue
+	}
+	return updates, nil
+}
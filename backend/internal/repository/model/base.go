@@ -55,27 +55,6 @@ func (b *BaseEntity) SetParam(key string, value interface{}) {
 	b.Params[key] = value
 }
 
-// GetDataScope 获取数据权限SQL 对应Java后端的数据权限处理
-func (b *BaseEntity) GetDataScope() string {
-	if b.Params == nil {
-		return ""
-	}
-	if dataScope, exists := b.Params["dataScope"]; exists {
-		if ds, ok := dataScope.(string); ok {
-			return ds
-		}
-	}
-	return ""
-}
-
-// SetDataScope 设置数据权限SQL
-func (b *BaseEntity) SetDataScope(dataScope string) {
-	if b.Params == nil {
-		b.Params = make(map[string]interface{})
-	}
-	b.Params["dataScope"] = dataScope
-}
-
 // QueryParams 查询参数基础结构 对应Java后端的查询参数
 type QueryParams struct {
 	BaseEntity
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"fmt"
 	"time"
 )
 
@@ -9,6 +10,7 @@ import (
 // config_id, config_name, config_key, config_value, config_type, create_by, create_time, update_by, update_time, remark
 type SysConfig struct {
 	ConfigID    int64      `gorm:"column:config_id;primaryKey;autoIncrement" json:"configId" excel:"name:参数主键;sort:1;cellType:numeric"`       // 参数主键
+	TenantID    int64      `gorm:"column:tenant_id;<-:create;default:1" json:"tenantId"`                                                      // 租户ID（迁移0005新增，只在新增时写入）
 	ConfigName  string     `gorm:"column:config_name;size:100;not null" json:"configName" excel:"name:参数名称;sort:2"`                           // 参数名称
 	ConfigKey   string     `gorm:"column:config_key;size:100;not null" json:"configKey" excel:"name:参数键名;sort:3"`                             // 参数键名
 	ConfigValue string     `gorm:"column:config_value;size:500;not null;default:''" json:"configValue" excel:"name:参数键值;sort:4"`              // 参数键值
@@ -17,6 +19,7 @@ type SysConfig struct {
 	CreateTime  *time.Time `gorm:"column:create_time" json:"createTime" excel:"name:创建时间;sort:6;type:export;dateFormat:yyyy-MM-dd HH:mm:ss"`  // 创建时间
 	UpdateBy    string     `gorm:"column:update_by;size:64;default:''" json:"updateBy"`                                                       // 更新者
 	UpdateTime  *time.Time `gorm:"column:update_time" json:"updateTime"`                                                                      // 更新时间
+	Version     int64      `gorm:"column:version;default:1" json:"version"`                                                                   // 版本号（乐观锁）
 	Remark      string     `gorm:"column:remark;size:500" json:"remark" excel:"name:备注;sort:7"`                                               // 备注
 }
 
@@ -68,6 +71,14 @@ func GetConfigCacheKey(configKey string) string {
 	return "sys_config:" + configKey
 }
 
+// GetTenantConfigCacheKey 获取租户的参数缓存键名，平台租户和未开启租户模式时与GetConfigCacheKey一致
+func GetTenantConfigCacheKey(tenantID int64, configKey string) string {
+	if tenantID <= DefaultTenantID {
+		return GetConfigCacheKey(configKey)
+	}
+	return GetConfigCacheKey(fmt.Sprintf("t%d:%s", tenantID, configKey))
+}
+
 // 系统内置参数列表 对应Java后端的内置参数
 var BuiltInConfigKeys = []string{
 	SysAccountCaptchaEnabled,
@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+// SysDataChange 数据变更记录表 记录用户、角色、部门、菜单、参数和字典数据修改前后的字段值
+// change_id, entity, entity_id, field_name, old_value, new_value, oper_id, request_id, oper_name, change_time
+type SysDataChange struct {
+	ChangeID   int64      `gorm:"column:change_id;primaryKey;autoIncrement" json:"changeId"` // 变更ID
+	TenantID   int64      `gorm:"column:tenant_id;<-:create;default:1" json:"tenantId"`      // 租户ID（迁移0005新增，只在新增时写入）
+	Entity     string     `gorm:"column:entity;size:50;not null" json:"entity"`              // 实体类型
+	EntityID   int64      `gorm:"column:entity_id;not null" json:"entityId"`                 // 实体ID
+	FieldName  string     `gorm:"column:field_name;size:100;not null" json:"fieldName"`      // 字段名
+	OldValue   string     `gorm:"column:old_value" json:"oldValue"`                          // 修改前的值
+	NewValue   string     `gorm:"column:new_value" json:"newValue"`                          // 修改后的值
+	OperID     int64      `gorm:"column:oper_id;default:0" json:"operId"`                    // 操作日志ID，请求记录操作日志后回填
+	RequestID  string     `gorm:"column:request_id;size:64;default:''" json:"requestId"`     // 请求ID
+	OperName   string     `gorm:"column:oper_name;size:64;default:''" json:"operName"`       // 操作人员
+	ChangeTime *time.Time `gorm:"column:change_time" json:"changeTime"`                      // 变更时间
+}
+
+// TableName 指定表名
+func (SysDataChange) TableName() string {
+	return "sys_data_change"
+}
+
+// 数据变更记录的实体类型常量
+const (
+	DataChangeEntityUser     = "user"      // 用户
+	DataChangeEntityRole     = "role"      // 角色
+	DataChangeEntityDept     = "dept"      // 部门
+	DataChangeEntityMenu     = "menu"      // 菜单
+	DataChangeEntityConfig   = "config"    // 参数配置
+	DataChangeEntityDictData = "dict_data" // 字典数据
+)
+
+// IsDataChangeEntity 是否为记录变更历史的实体类型
+func IsDataChangeEntity(entity string) bool {
+	switch entity {
+	case DataChangeEntityUser, DataChangeEntityRole, DataChangeEntityDept,
+		DataChangeEntityMenu, DataChangeEntityConfig, DataChangeEntityDictData:
+		return true
+	}
+	return false
+}
@@ -7,6 +7,7 @@ import (
 // SysDept 部门表 对应Java后端的SysDept实体
 type SysDept struct {
 	DeptID     int64      `gorm:"column:dept_id;primaryKey;autoIncrement" json:"deptId"`                             // 部门id
+	TenantID   int64      `gorm:"column:tenant_id;<-:create;default:1" json:"tenantId"`                              // 租户ID（迁移0005新增，只在新增时写入）
 	ParentID   int64      `gorm:"column:parent_id;default:0" json:"parentId"`                                        // 父部门id
 	Ancestors  string     `gorm:"column:ancestors;size:50" json:"ancestors"`                                         // 祖级列表
 	DeptName   string     `gorm:"column:dept_name;size:30;not null" json:"deptName" binding:"required,min=1,max=30"` // 部门名称 对应Java后端@NotBlank @Size(max=30)
@@ -20,6 +21,7 @@ type SysDept struct {
 	CreateTime *time.Time `gorm:"column:create_time" json:"createTime"`                                              // 创建时间
 	UpdateBy   string     `gorm:"column:update_by;size:64" json:"updateBy"`                                          // 更新者
 	UpdateTime *time.Time `gorm:"column:update_time" json:"updateTime"`                                              // 更新时间
+	Version    int64      `gorm:"column:version;default:1" json:"version"`                                           // 版本号（乐观锁）
 
 	// 关联字段（不存储在数据库中）
 	ParentName string                 `gorm:"-" json:"parentName,omitempty"` // 父部门名称（不存储在数据库中，用于显示）
@@ -9,6 +9,7 @@ import (
 // dict_code, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, create_time, update_by, update_time, remark
 type SysDictData struct {
 	DictCode   int64      `gorm:"column:dict_code;primaryKey;autoIncrement" json:"dictCode" excel:"name:字典编码;sort:1;cellType:numeric"`   // 字典编码
+	TenantID   int64      `gorm:"column:tenant_id;<-:create;default:1" json:"tenantId"`                                                  // 租户ID（迁移0005新增，只在新增时写入）
 	DictSort   int64      `gorm:"column:dict_sort;default:0" json:"dictSort" excel:"name:字典排序;sort:2;cellType:numeric"`                  // 字典排序
 	DictLabel  string     `gorm:"column:dict_label;size:100;not null" json:"dictLabel" excel:"name:字典标签;sort:3"`                         // 字典标签
 	DictValue  string     `gorm:"column:dict_value;size:100;not null" json:"dictValue" excel:"name:字典键值;sort:4"`                         // 字典键值
@@ -21,6 +22,7 @@ type SysDictData struct {
 	CreateTime *time.Time `gorm:"column:create_time" json:"createTime"`                                                                  // 创建时间
 	UpdateBy   string     `gorm:"column:update_by;size:64" json:"updateBy"`                                                              // 更新者
 	UpdateTime *time.Time `gorm:"column:update_time" json:"updateTime"`                                                                  // 更新时间
+	Version    int64      `gorm:"column:version;default:1" json:"version"`                                                               // 版本号（乐观锁）
 	Remark     string     `gorm:"column:remark;size:500" json:"remark"`                                                                  // 备注
 }
 
@@ -0,0 +1,40 @@
+package model
+
+// 字段漂移类型
+const (
+	GenDriftAdded          = "added"          // 数据库新增的列
+	GenDriftRemoved        = "removed"        // 数据库已删除的列
+	GenDriftTypeChanged    = "typeChanged"    // 类型、主键或自增属性变化
+	GenDriftCommentChanged = "commentChanged" // 注释变化
+)
+
+// GenColumnDrift 业务表字段与数据库列的差异
+type GenColumnDrift struct {
+	ColumnName string `json:"columnName"` // 列名称
+	ChangeType string `json:"changeType"` // 漂移类型
+	OldType    string `json:"oldType"`    // 业务表中的列类型
+	NewType    string `json:"newType"`    // 数据库中的列类型
+	OldComment string `json:"oldComment"` // 业务表中的列描述
+	NewComment string `json:"newComment"` // 数据库中的列描述
+}
+
+// GenTableDrift 业务表与数据库表结构的差异报告
+type GenTableDrift struct {
+	TableID         int64            `json:"tableId"`         // 业务表编号
+	TableName       string           `json:"tableName"`       // 表名称
+	TableExists     bool             `json:"tableExists"`     // 数据库表是否存在
+	OldTableComment string           `json:"oldTableComment"` // 业务表中的表描述
+	NewTableComment string           `json:"newTableComment"` // 数据库中的表描述
+	Columns         []GenColumnDrift `json:"columns"`         // 字段差异
+}
+
+// HasDrift 是否与数据库不一致
+func (d *GenTableDrift) HasDrift() bool {
+	return !d.TableExists || d.OldTableComment != d.NewTableComment || len(d.Columns) > 0
+}
+
+// GenSynchRequest 选择性同步请求
+type GenSynchRequest struct {
+	Columns      []string `json:"columns"`      // 要同步的列，应用该列的全部差异
+	TableComment bool     `json:"tableComment"` // 是否同步表描述
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"encoding/json"
 	"time"
 )
 
@@ -34,6 +35,14 @@ type GenTable struct {
 	TreeName       string `gorm:"-" json:"treeName"`       // 树名称字段
 	ParentMenuId   int64  `gorm:"-" json:"parentMenuId"`   // 上级菜单ID字段
 	ParentMenuName string `gorm:"-" json:"parentMenuName"` // 上级菜单名称字段
+	TemplateSetId  int64  `gorm:"-" json:"templateSetId"`  // 模板组ID（0使用内置模板）
+
+	// 安装模块菜单后记录的菜单（对应生成选项中的 menuId、installParentMenuId）
+	MenuId              int64 `gorm:"-" json:"menuId"`              // 已安装的页面菜单ID（0未安装）
+	InstallParentMenuId int64 `gorm:"-" json:"installParentMenuId"` // 安装时新建的模块目录ID（0表示使用已存在的目录）
+
+	// 发布为运行时模块后通过 /dyn/{businessName} 直接增删改查，无需生成代码（对应生成选项中的 published）
+	Published bool `gorm:"-" json:"published"` // 是否已发布为运行时模块
 
 	// 扩展字段（不映射到数据库）
 	Columns    []GenTableColumn `gorm:"-" json:"columns" binding:"dive"` // 表列信息
@@ -105,8 +114,9 @@ const (
 
 // 前端模板类型常量
 const (
-	TplWebTypeElementUI   = "element-ui"   // Element UI模版
-	TplWebTypeElementPlus = "element-plus" // Element Plus模版
+	TplWebTypeElementUI     = "element-ui"      // Element UI模版
+	TplWebTypeElementPlus   = "element-plus"    // Element Plus模版
+	TplWebTypeElementPlusTs = "element-plus-ts" // Element Plus TypeScript模版
 )
 
 // 生成代码方式常量
@@ -304,3 +314,79 @@ func (t *GenTable) IsZipGenType() bool {
 func (t *GenTable) IsPathGenType() bool {
 	return t.GenType == GenTypePath
 }
+
+// GenTableOptions 其它生成选项，以JSON保存在 gen_table.options 中 对应Java后端的GenConstants中的选项键
+type GenTableOptions struct {
+	TreeCode       string `json:"treeCode,omitempty"`       // 树编码字段（列名）
+	TreeParentCode string `json:"treeParentCode,omitempty"` // 树父编码字段（列名）
+	TreeName       string `json:"treeName,omitempty"`       // 树名称字段（列名）
+	ParentMenuId   int64  `json:"parentMenuId,omitempty"`   // 上级菜单ID
+	ParentMenuName string `json:"parentMenuName,omitempty"` // 上级菜单名称
+	TemplateSetId  int64  `json:"templateSetId,omitempty"`  // 模板组ID
+
+	MenuId              int64 `json:"menuId,omitempty"`              // 已安装的页面菜单ID
+	InstallParentMenuId int64 `json:"installParentMenuId,omitempty"` // 安装时新建的模块目录ID
+
+	Published bool `json:"published,omitempty"` // 是否已发布为运行时模块
+}
+
+// SetTableFromOptions 从生成选项中读取树表、上级菜单、模板组、已安装菜单和发布配置 对应Java后端的setTableFromOptions
+func (t *GenTable) SetTableFromOptions() {
+	if t.Options == "" {
+		return
+	}
+	var options GenTableOptions
+	if err := json.Unmarshal([]byte(t.Options), &options); err != nil {
+		return
+	}
+	t.TreeCode = options.TreeCode
+	t.TreeParentCode = options.TreeParentCode
+	t.TreeName = options.TreeName
+	t.ParentMenuId = options.ParentMenuId
+	t.ParentMenuName = options.ParentMenuName
+	t.TemplateSetId = options.TemplateSetId
+	t.MenuId = options.MenuId
+	t.InstallParentMenuId = options.InstallParentMenuId
+	t.Published = options.Published
+}
+
+// BuildOptions 将树表、上级菜单、模板组、已安装菜单和发布配置写入生成选项
+func (t *GenTable) BuildOptions() {
+	data, err := json.Marshal(GenTableOptions{
+		TreeCode:       t.TreeCode,
+		TreeParentCode: t.TreeParentCode,
+		TreeName:       t.TreeName,
+		ParentMenuId:   t.ParentMenuId,
+		ParentMenuName: t.ParentMenuName,
+		TemplateSetId:  t.TemplateSetId,
+
+		MenuId:              t.MenuId,
+		InstallParentMenuId: t.InstallParentMenuId,
+
+		Published: t.Published,
+	})
+	if err != nil {
+		return
+	}
+	t.Options = string(data)
+}
+
+// GetColumnByName 根据列名获取字段
+func (t *GenTable) GetColumnByName(columnName string) *GenTableColumn {
+	for i := range t.Columns {
+		if t.Columns[i].ColumnName == columnName {
+			return &t.Columns[i]
+		}
+	}
+	return nil
+}
+
+// GetColumnByField 根据属性名获取字段
+func (t *GenTable) GetColumnByField(javaField string) *GenTableColumn {
+	for i := range t.Columns {
+		if t.Columns[i].JavaField == javaField {
+			return &t.Columns[i]
+		}
+	}
+	return nil
+}
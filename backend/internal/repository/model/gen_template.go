@@ -0,0 +1,73 @@
+package model
+
+import "time"
+
+// GenTemplateSet 代码生成模板组表
+// 表结构：set_id, set_name, status, create_by, create_time, update_by, update_time, remark
+// 业务表通过 gen_table.options 中的 templateSetId 选择模板组，模板组中未定义的模板使用内置模板
+type GenTemplateSet struct {
+	SetID      int64      `gorm:"column:set_id;primaryKey;autoIncrement" json:"setId"` // 模板组ID
+	SetName    string     `gorm:"column:set_name;size:64" json:"setName"`              // 模板组名称
+	Status     string     `gorm:"column:status;size:1;default:0" json:"status"`        // 状态（0正常 1停用）
+	CreateBy   string     `gorm:"column:create_by;size:64;default:''" json:"createBy"` // 创建者
+	CreateTime *time.Time `gorm:"column:create_time" json:"createTime"`                // 创建时间
+	UpdateBy   string     `gorm:"column:update_by;size:64;default:''" json:"updateBy"` // 更新者
+	UpdateTime *time.Time `gorm:"column:update_time" json:"updateTime"`                // 更新时间
+	Remark     string     `gorm:"column:remark;size:500;default:''" json:"remark"`     // 备注
+
+	// 扩展字段（不映射到数据库）
+	Templates []GenTemplate `gorm:"-" json:"templates"` // 模板组中的模板
+}
+
+// TableName 指定表名
+func (GenTemplateSet) TableName() string {
+	return "gen_template_set"
+}
+
+// IsNormal 判断模板组是否启用
+func (s *GenTemplateSet) IsNormal() bool {
+	return s.Status == GenTemplateSetStatusNormal
+}
+
+// GenTemplate 代码生成模板表
+// 表结构：template_id, set_id, template_name, content, version, create_by, create_time, update_by, update_time
+// template_name 与内置模板名一致（如 model.go.tmpl、index-plus.vue.tmpl），每次保存版本号加一
+type GenTemplate struct {
+	TemplateID   int64      `gorm:"column:template_id;primaryKey;autoIncrement" json:"templateId"` // 模板ID
+	SetID        int64      `gorm:"column:set_id" json:"setId"`                                    // 模板组ID
+	TemplateName string     `gorm:"column:template_name;size:64" json:"templateName"`              // 模板名称
+	Content      string     `gorm:"column:content" json:"content"`                                 // 模板内容
+	Version      int        `gorm:"column:version;default:1" json:"version"`                       // 当前版本号
+	CreateBy     string     `gorm:"column:create_by;size:64;default:''" json:"createBy"`           // 创建者
+	CreateTime   *time.Time `gorm:"column:create_time" json:"createTime"`                          // 创建时间
+	UpdateBy     string     `gorm:"column:update_by;size:64;default:''" json:"updateBy"`           // 更新者
+	UpdateTime   *time.Time `gorm:"column:update_time" json:"updateTime"`                          // 更新时间
+}
+
+// TableName 指定表名
+func (GenTemplate) TableName() string {
+	return "gen_template"
+}
+
+// GenTemplateHistory 代码生成模板历史版本表
+// 表结构：history_id, template_id, version, content, create_by, create_time
+// 每次保存或回滚都会记录一个新版本，包括当前版本
+type GenTemplateHistory struct {
+	HistoryID  int64      `gorm:"column:history_id;primaryKey;autoIncrement" json:"historyId"` // 历史ID
+	TemplateID int64      `gorm:"column:template_id" json:"templateId"`                        // 模板ID
+	Version    int        `gorm:"column:version" json:"version"`                               // 版本号
+	Content    string     `gorm:"column:content" json:"content"`                               // 模板内容
+	CreateBy   string     `gorm:"column:create_by;size:64;default:''" json:"createBy"`         // 保存者
+	CreateTime *time.Time `gorm:"column:create_time" json:"createTime"`                        // 保存时间
+}
+
+// TableName 指定表名
+func (GenTemplateHistory) TableName() string {
+	return "gen_template_history"
+}
+
+// 模板组状态常量
+const (
+	GenTemplateSetStatusNormal  = "0" // 正常
+	GenTemplateSetStatusDisable = "1" // 停用
+)
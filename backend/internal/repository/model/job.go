@@ -7,7 +7,7 @@ import (
 
 // SysJob 定时任务调度表 对应Java后端的SysJob实体
 // 严格按照Java后端真实数据库表结构定义（基于SqlServer_ry_20250522_COMPLETE.sql）：
-// job_id, job_name, job_group, invoke_target, cron_expression, misfire_policy, concurrent, status, create_by, create_time, update_by, update_time, remark
+// job_id, job_name, job_group, invoke_target, cron_expression, misfire_policy, concurrent, status, create_by, create_time, update_by, update_time, remark, job_type, job_config, calendar_id
 type SysJob struct {
 	JobID          int64      `gorm:"column:job_id;primaryKey;autoIncrement" json:"jobId"`                                     // 任务ID
 	JobName        string     `gorm:"column:job_name;size:64;uniqueIndex:idx_job_name_group" json:"jobName"`                   // 任务名称
@@ -22,13 +22,17 @@ type SysJob struct {
 	UpdateBy       string     `gorm:"column:update_by;size:64;default:''" json:"updateBy"`                                     // 更新者
 	UpdateTime     *time.Time `gorm:"column:update_time" json:"updateTime"`                                                    // 更新时间
 	Remark         string     `gorm:"column:remark;size:500;default:''" json:"remark"`                                         // 备注信息
+	JobType        string     `gorm:"column:job_type;size:1;default:0" json:"jobType"`                                         // 任务类型（0调用目标 1HTTP请求 2SQL）
+	JobConfig      string     `gorm:"column:job_config" json:"jobConfig"`                                                      // 任务类型配置（JSON）
+	CalendarID     int64      `gorm:"column:calendar_id;default:0" json:"calendarId"`                                          // 排除日历ID（0表示不使用日历）
 
 	// 查询条件字段（不映射到数据库）
 	BeginTime string `gorm:"-" json:"beginTime"` // 开始时间
 	EndTime   string `gorm:"-" json:"endTime"`   // 结束时间
 
 	// 扩展字段（不映射到数据库）
-	NextValidTime *time.Time `gorm:"-" json:"nextValidTime"` // 下次执行时间
+	NextValidTime *time.Time         `gorm:"-" json:"nextValidTime"` // 下次执行时间
+	Dependencies  []SysJobDependency `gorm:"-" json:"dependencies"`  // 上游依赖（为nil时保存任务不修改依赖）
 }
 
 // TableName 指定表名
@@ -140,6 +144,13 @@ func ValidateJob(job *SysJob, isUpdate bool) error {
 		return fmt.Errorf("任务名称不能超过64个字符")
 	}
 
+	if job.JobType != "" && job.JobType != JobTypeInvoke && !job.IsTypedJob() {
+		return fmt.Errorf("任务类型无效")
+	}
+	if job.IsTypedJob() && job.JobConfig == "" {
+		return fmt.Errorf("任务类型配置不能为空")
+	}
+
 	if job.InvokeTarget == "" {
 		return fmt.Errorf("调用目标字符串不能为空")
 	}
@@ -0,0 +1,150 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SysJobCalendar 定时任务排除日历表
+// 表结构：calendar_id, calendar_name, timezone, status, create_by, create_time, update_by, update_time, remark
+// 任务通过 sys_job.calendar_id 引用日历，日历排除的时间不会触发任务（节假日、月末封账等）
+type SysJobCalendar struct {
+	CalendarID   int64      `gorm:"column:calendar_id;primaryKey;autoIncrement" json:"calendarId"` // 日历ID
+	CalendarName string     `gorm:"column:calendar_name;size:64" json:"calendarName"`              // 日历名称
+	Timezone     string     `gorm:"column:timezone;size:64;default:''" json:"timezone"`            // 时区（为空时使用服务器时区）
+	Status       string     `gorm:"column:status;size:1;default:0" json:"status"`                  // 状态（0正常 1停用）
+	CreateBy     string     `gorm:"column:create_by;size:64;default:''" json:"createBy"`           // 创建者
+	CreateTime   *time.Time `gorm:"column:create_time" json:"createTime"`                          // 创建时间
+	UpdateBy     string     `gorm:"column:update_by;size:64;default:''" json:"updateBy"`           // 更新者
+	UpdateTime   *time.Time `gorm:"column:update_time" json:"updateTime"`                          // 更新时间
+	Remark       string     `gorm:"column:remark;size:500;default:''" json:"remark"`               // 备注
+
+	// 扩展字段（不映射到数据库）
+	Rules []SysJobCalendarRule `gorm:"-" json:"rules"` // 排除规则（为nil时保存日历不修改规则）
+}
+
+// TableName 指定表名
+func (SysJobCalendar) TableName() string {
+	return "sys_job_calendar"
+}
+
+// SysJobCalendarRule 定时任务排除日历规则表
+// 表结构：rule_id, calendar_id, rule_type, start_time, end_time, weekdays, summary
+type SysJobCalendarRule struct {
+	RuleID     int64      `gorm:"column:rule_id;primaryKey;autoIncrement" json:"ruleId"` // 规则ID
+	CalendarID int64      `gorm:"column:calendar_id" json:"calendarId"`                  // 日历ID
+	RuleType   string     `gorm:"column:rule_type;size:1" json:"ruleType"`               // 规则类型（0日期 1每周 2时间段）
+	StartTime  *time.Time `gorm:"column:start_time" json:"startTime"`                    // 日期或时间段开始时间
+	EndTime    *time.Time `gorm:"column:end_time" json:"endTime"`                        // 时间段结束时间（不包含）
+	Weekdays   string     `gorm:"column:weekdays;size:20;default:''" json:"weekdays"`    // 每周排除的星期（1周日 ... 7周六，逗号分隔）
+	Summary    string     `gorm:"column:summary;size:200;default:''" json:"summary"`     // 说明，例如节日名称
+}
+
+// TableName 指定表名
+func (SysJobCalendarRule) TableName() string {
+	return "sys_job_calendar_rule"
+}
+
+// 日历状态常量
+const (
+	JobCalendarStatusNormal  = "0" // 正常
+	JobCalendarStatusDisable = "1" // 停用
+)
+
+// 日历规则类型常量
+const (
+	JobCalendarRuleDate  = "0" // 排除指定日期（整天）
+	JobCalendarRuleWeek  = "1" // 每周排除指定星期
+	JobCalendarRuleRange = "2" // 排除时间段
+)
+
+// IsNormal 检查日历是否启用
+func (c *SysJobCalendar) IsNormal() bool {
+	return c.Status == "" || c.Status == JobCalendarStatusNormal
+}
+
+// Location 返回日历使用的时区
+func (c *SysJobCalendar) Location() (*time.Location, error) {
+	if c.Timezone == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("时区无效: %s", c.Timezone)
+	}
+	return loc, nil
+}
+
+// WeekdayList 解析每周排除的星期，返回 time.Weekday 列表
+func (r *SysJobCalendarRule) WeekdayList() ([]time.Weekday, error) {
+	var weekdays []time.Weekday
+	for _, item := range strings.Split(r.Weekdays, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		n, err := strconv.Atoi(item)
+		if err != nil || n < 1 || n > 7 {
+			return nil, fmt.Errorf("星期取值应为1-7（1为周日）: %s", item)
+		}
+		weekdays = append(weekdays, time.Weekday(n-1))
+	}
+	return weekdays, nil
+}
+
+// ValidateJobCalendar 验证日历参数
+func ValidateJobCalendar(calendar *SysJobCalendar) error {
+	if strings.TrimSpace(calendar.CalendarName) == "" {
+		return fmt.Errorf("日历名称不能为空")
+	}
+	if len(calendar.CalendarName) > 64 {
+		return fmt.Errorf("日历名称不能超过64个字符")
+	}
+	if calendar.Status != "" && calendar.Status != JobCalendarStatusNormal && calendar.Status != JobCalendarStatusDisable {
+		return fmt.Errorf("日历状态值无效")
+	}
+	if _, err := calendar.Location(); err != nil {
+		return err
+	}
+
+	for i := range calendar.Rules {
+		if err := ValidateJobCalendarRule(&calendar.Rules[i]); err != nil {
+			return fmt.Errorf("第%d条规则: %v", i+1, err)
+		}
+	}
+	return nil
+}
+
+// ValidateJobCalendarRule 验证日历规则
+func ValidateJobCalendarRule(rule *SysJobCalendarRule) error {
+	if len(rule.Summary) > 200 {
+		return fmt.Errorf("说明不能超过200个字符")
+	}
+
+	switch rule.RuleType {
+	case JobCalendarRuleDate:
+		if rule.StartTime == nil {
+			return fmt.Errorf("排除日期不能为空")
+		}
+	case JobCalendarRuleWeek:
+		weekdays, err := rule.WeekdayList()
+		if err != nil {
+			return err
+		}
+		if len(weekdays) == 0 {
+			return fmt.Errorf("每周排除的星期不能为空")
+		}
+	case JobCalendarRuleRange:
+		if rule.StartTime == nil || rule.EndTime == nil {
+			return fmt.Errorf("时间段的开始和结束时间不能为空")
+		}
+		if !rule.EndTime.After(*rule.StartTime) {
+			return fmt.Errorf("时间段的结束时间必须晚于开始时间")
+		}
+	default:
+		return fmt.Errorf("规则类型无效: %s", rule.RuleType)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// SysJobDependency 定时任务依赖关系表
+// 表结构：dependency_id, job_id, parent_job_id, trigger_type, create_by, create_time
+// 一条记录表示 parent_job_id 执行结束后，按 trigger_type 条件触发 job_id
+type SysJobDependency struct {
+	DependencyID int64      `gorm:"column:dependency_id;primaryKey;autoIncrement" json:"dependencyId"` // 依赖ID
+	JobID        int64      `gorm:"column:job_id;not null" json:"jobId"`                               // 下游任务ID
+	ParentJobID  int64      `gorm:"column:parent_job_id;not null" json:"parentJobId"`                  // 上游任务ID
+	TriggerType  string     `gorm:"column:trigger_type;size:1;default:0" json:"triggerType"`           // 触发条件（0成功时 1失败时 2总是）
+	CreateBy     string     `gorm:"column:create_by;size:64;default:''" json:"createBy"`               // 创建者
+	CreateTime   *time.Time `gorm:"column:create_time" json:"createTime"`                              // 创建时间
+
+	// 扩展字段（不映射到数据库）
+	ParentJobName string `gorm:"-" json:"parentJobName,omitempty"` // 上游任务名称
+}
+
+// TableName 指定表名
+func (SysJobDependency) TableName() string {
+	return "sys_job_dependency"
+}
+
+// 依赖触发条件常量
+const (
+	JobTriggerOnSuccess = "0" // 上游成功时触发
+	JobTriggerOnFailure = "1" // 上游失败时触发
+	JobTriggerAlways    = "2" // 上游结束后总是触发
+)
+
+// GetJobTriggerTypeName 获取依赖触发条件名称
+func GetJobTriggerTypeName(triggerType string) string {
+	switch triggerType {
+	case JobTriggerOnSuccess:
+		return "成功时"
+	case JobTriggerOnFailure:
+		return "失败时"
+	case JobTriggerAlways:
+		return "总是"
+	default:
+		return "未知"
+	}
+}
+
+// IsValidTriggerType 检查触发条件是否有效
+func (d *SysJobDependency) IsValidTriggerType() bool {
+	return d.TriggerType == JobTriggerOnSuccess ||
+		d.TriggerType == JobTriggerOnFailure ||
+		d.TriggerType == JobTriggerAlways
+}
+
+// Matches 判断上游执行状态是否满足触发条件，upstreamStatus 为 sys_job_log 的执行状态（0正常 1失败）
+func (d *SysJobDependency) Matches(upstreamStatus string) bool {
+	switch d.TriggerType {
+	case JobTriggerAlways:
+		return true
+	case JobTriggerOnFailure:
+		return upstreamStatus == JobLogStatusFail
+	default:
+		return upstreamStatus == JobLogStatusNormal
+	}
+}
+
+// ValidateJobDependency 验证任务依赖参数
+func ValidateJobDependency(dep *SysJobDependency) error {
+	if dep.ParentJobID <= 0 {
+		return fmt.Errorf("上游任务不能为空")
+	}
+	if dep.JobID > 0 && dep.JobID == dep.ParentJobID {
+		return fmt.Errorf("任务不能依赖自身")
+	}
+	if dep.TriggerType != "" && !dep.IsValidTriggerType() {
+		return fmt.Errorf("依赖触发条件无效")
+	}
+	return nil
+}
+
+// JobGraph 任务依赖关系图
+type JobGraph struct {
+	Nodes []JobGraphNode `json:"nodes"` // 任务节点
+	Edges []JobGraphEdge `json:"edges"` // 依赖边
+}
+
+// JobGraphNode 任务依赖关系图节点
+type JobGraphNode struct {
+	JobID          int64      `json:"jobId"`                    // 任务ID
+	JobName        string     `json:"jobName"`                  // 任务名称
+	JobGroup       string     `json:"jobGroup"`                 // 任务组名
+	CronExpression string     `json:"cronExpression"`           // cron执行表达式
+	Status         string     `json:"status"`                   // 任务状态（0正常 1暂停）
+	LastRunStatus  string     `json:"lastRunStatus"`            // 最近一次执行状态（0正常 1失败，空表示未执行）
+	LastRunTime    *time.Time `json:"lastRunTime"`              // 最近一次执行时间
+	LastRunMessage string     `json:"lastRunMessage,omitempty"` // 最近一次执行日志信息
+}
+
+// JobGraphEdge 任务依赖关系图的边，方向为上游指向下游
+type JobGraphEdge struct {
+	Source      int64  `json:"source"`      // 上游任务ID
+	Target      int64  `json:"target"`      // 下游任务ID
+	TriggerType string `json:"triggerType"` // 触发条件（0成功时 1失败时 2总是）
+}
@@ -1,22 +1,27 @@
 package model
 
 import (
+	"bytes"
+	"compress/gzip"
 	"fmt"
+	"io"
+	"strings"
 	"time"
 )
 
 // SysJobLog 定时任务调度日志表 对应Java后端的SysJobLog实体
 // 严格按照Java后端真实数据库表结构定义（基于SqlServer_ry_20250522_COMPLETE.sql）：
-// job_log_id, job_name, job_group, invoke_target, job_message, status, exception_info, create_time
+// job_log_id, job_name, job_group, invoke_target, job_message, status, exception_info, create_time, log_content
 type SysJobLog struct {
 	JobLogID      int64      `gorm:"column:job_log_id;primaryKey;autoIncrement" json:"jobLogId"`      // 任务日志ID
 	JobName       string     `gorm:"column:job_name;size:64;not null" json:"jobName"`                 // 任务名称
 	JobGroup      string     `gorm:"column:job_group;size:64;not null" json:"jobGroup"`               // 任务组名
 	InvokeTarget  string     `gorm:"column:invoke_target;size:500;not null" json:"invokeTarget"`      // 调用目标字符串
 	JobMessage    string     `gorm:"column:job_message;size:500" json:"jobMessage"`                   // 日志信息
-	Status        string     `gorm:"column:status;size:1;default:0" json:"status"`                    // 执行状态（0正常 1失败）
+	Status        string     `gorm:"column:status;size:1;default:0" json:"status"`                    // 执行状态（0正常 1失败 2执行中）
 	ExceptionInfo string     `gorm:"column:exception_info;size:2000;default:''" json:"exceptionInfo"` // 异常信息
 	CreateTime    *time.Time `gorm:"column:create_time" json:"createTime"`                            // 创建时间
+	LogContent    []byte     `gorm:"column:log_content" json:"-"`                                     // 完整执行日志（gzip压缩）
 
 	// 扩展字段（用于前端显示和查询）
 	StartTime *time.Time `gorm:"-" json:"startTime"` // 开始时间（用于查询）
@@ -30,10 +35,29 @@ func (SysJobLog) TableName() string {
 
 // 状态常量 - 对应Java后端的ScheduleConstants
 const (
-	JobLogStatusNormal = "0" // 正常
-	JobLogStatusFail   = "1" // 失败
+	JobLogStatusNormal  = "0" // 正常
+	JobLogStatusFail    = "1" // 失败
+	JobLogStatusRunning = "2" // 执行中
 )
 
+// JobLogRunningTimeout 执行中状态的最长有效时间，超过后视为已结束（进程退出等原因未能更新状态）
+const JobLogRunningTimeout = 1 * time.Hour
+
+// 任务执行日志级别常量
+const (
+	JobLogLevelInfo  = "INFO"
+	JobLogLevelWarn  = "WARN"
+	JobLogLevelError = "ERROR"
+)
+
+// JobLogLine 任务执行日志行，以JSON格式缓冲到Redis并持久化到 log_content
+type JobLogLine struct {
+	Time    string                 `json:"time"`             // 记录时间
+	Level   string                 `json:"level"`            // 日志级别
+	Message string                 `json:"message"`          // 日志内容
+	Fields  map[string]interface{} `json:"fields,omitempty"` // 结构化字段
+}
+
 // IsNormal 判断是否正常状态
 func (j *SysJobLog) IsNormal() bool {
 	return j.Status == JobLogStatusNormal
@@ -44,6 +68,14 @@ func (j *SysJobLog) IsFail() bool {
 	return j.Status == JobLogStatusFail
 }
 
+// IsRunning 判断是否仍在执行中，执行中超过 JobLogRunningTimeout 的日志视为已结束
+func (j *SysJobLog) IsRunning(now time.Time) bool {
+	if j.Status != JobLogStatusRunning || j.CreateTime == nil {
+		return false
+	}
+	return now.Sub(*j.CreateTime) < JobLogRunningTimeout
+}
+
 // GetStatusText 获取状态文本
 func (j *SysJobLog) GetStatusText() string {
 	switch j.Status {
@@ -51,6 +83,8 @@ func (j *SysJobLog) GetStatusText() string {
 		return "正常"
 	case JobLogStatusFail:
 		return "失败"
+	case JobLogStatusRunning:
+		return "执行中"
 	default:
 		return "未知"
 	}
@@ -88,13 +122,51 @@ func ValidateJobLog(jobLog *SysJobLog, isUpdate bool) error {
 	}
 
 	// 验证状态
-	if jobLog.Status != "" && jobLog.Status != JobLogStatusNormal && jobLog.Status != JobLogStatusFail {
+	if jobLog.Status != "" && jobLog.Status != JobLogStatusNormal && jobLog.Status != JobLogStatusFail && jobLog.Status != JobLogStatusRunning {
 		return fmt.Errorf("执行状态值无效")
 	}
 
 	return nil
 }
 
+// SetLogLines 压缩并保存完整执行日志，每个元素为一行
+func (j *SysJobLog) SetLogLines(lines []string) error {
+	if len(lines) == 0 {
+		j.LogContent = nil
+		return nil
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(strings.Join(lines, "\n"))); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	j.LogContent = buf.Bytes()
+	return nil
+}
+
+// GetLogLines 解压完整执行日志
+func (j *SysJobLog) GetLogLines() ([]string, error) {
+	if len(j.LogContent) == 0 {
+		return []string{}, nil
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(j.LogContent))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(string(data), "\n"), nil
+}
+
 // String 字符串表示 对应Java后端的toString方法
 func (j *SysJobLog) String() string {
 	return fmt.Sprintf("SysJobLog{JobLogID=%d, JobName='%s', JobGroup='%s', JobMessage='%s', Status='%s', ExceptionInfo='%s', StartTime=%v, StopTime=%v}",
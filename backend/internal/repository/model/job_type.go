@@ -0,0 +1,200 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// 任务类型常量
+const (
+	JobTypeInvoke = "0" // 调用目标字符串
+	JobTypeHttp   = "1" // HTTP请求
+	JobTypeSql    = "2" // SQL语句或存储过程
+)
+
+// GetJobTypeName 获取任务类型名称
+func GetJobTypeName(jobType string) string {
+	switch jobType {
+	case JobTypeInvoke, "":
+		return "调用目标"
+	case JobTypeHttp:
+		return "HTTP请求"
+	case JobTypeSql:
+		return "SQL"
+	default:
+		return "未知"
+	}
+}
+
+// HttpJobConfig HTTP任务配置，保存在 sys_job.job_config 中
+type HttpJobConfig struct {
+	Method         string            `json:"method"`         // 请求方法，默认GET
+	URL            string            `json:"url"`            // 请求地址，支持模板变量
+	Headers        map[string]string `json:"headers"`        // 请求头
+	BodyTemplate   string            `json:"bodyTemplate"`   // 请求体模板（text/template语法，可引用 .Job 和 .Params）
+	ExpectedStatus int               `json:"expectedStatus"` // 期望的响应状态码，为0时任意2xx视为成功
+	Timeout        int               `json:"timeout"`        // 超时时间（秒），默认30
+}
+
+// SqlJobConfig SQL任务配置，保存在 sys_job.job_config 中
+// Statement 与 Procedure 二选一，且必须在配置文件的 job.sql_whitelist 白名单内
+type SqlJobConfig struct {
+	Statement string        `json:"statement"` // SQL语句
+	Procedure string        `json:"procedure"` // 存储过程名称
+	Params    []interface{} `json:"params"`    // 参数（按顺序绑定）
+	Timeout   int           `json:"timeout"`   // 超时时间（秒），默认60
+}
+
+// 存储过程名称格式，允许 schema.name 以及方括号引用
+var procedureNamePattern = regexp.MustCompile(`^[\[]?[A-Za-z_][A-Za-z0-9_]*[\]]?(\.[\[]?[A-Za-z_][A-Za-z0-9_]*[\]]?)?$`)
+
+// IsTypedJob 判断是否为HTTP或SQL类型的任务
+func (j *SysJob) IsTypedJob() bool {
+	return j.JobType == JobTypeHttp || j.JobType == JobTypeSql
+}
+
+// ParseHttpJobConfig 解析HTTP任务配置
+func ParseHttpJobConfig(jobConfig string) (*HttpJobConfig, error) {
+	cfg := &HttpJobConfig{}
+	if err := json.Unmarshal([]byte(jobConfig), cfg); err != nil {
+		return nil, fmt.Errorf("HTTP任务配置格式错误: %v", err)
+	}
+
+	cfg.Method = strings.ToUpper(strings.TrimSpace(cfg.Method))
+	if cfg.Method == "" {
+		cfg.Method = http.MethodGet
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 30
+	}
+	return cfg, nil
+}
+
+// Validate 验证HTTP任务配置
+func (c *HttpJobConfig) Validate() error {
+	switch c.Method {
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodHead:
+	default:
+		return fmt.Errorf("不支持的请求方法: %s", c.Method)
+	}
+
+	if c.URL == "" {
+		return fmt.Errorf("请求地址不能为空")
+	}
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return fmt.Errorf("请求地址格式错误: %v", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("请求地址只支持http(s)协议")
+	}
+	if u.Host == "" {
+		return fmt.Errorf("请求地址缺少主机名")
+	}
+
+	if c.ExpectedStatus != 0 && (c.ExpectedStatus < 100 || c.ExpectedStatus > 599) {
+		return fmt.Errorf("期望状态码无效: %d", c.ExpectedStatus)
+	}
+	if c.Timeout > 3600 {
+		return fmt.Errorf("超时时间不能超过3600秒")
+	}
+	return nil
+}
+
+// ParseSqlJobConfig 解析SQL任务配置
+func ParseSqlJobConfig(jobConfig string) (*SqlJobConfig, error) {
+	cfg := &SqlJobConfig{}
+	if err := json.Unmarshal([]byte(jobConfig), cfg); err != nil {
+		return nil, fmt.Errorf("SQL任务配置格式错误: %v", err)
+	}
+
+	cfg.Statement = strings.TrimSpace(cfg.Statement)
+	cfg.Procedure = strings.TrimSpace(cfg.Procedure)
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 60
+	}
+	return cfg, nil
+}
+
+// Validate 验证SQL任务配置，whitelist 为允许执行的语句或存储过程名称
+func (c *SqlJobConfig) Validate(whitelist []string) error {
+	if (c.Statement == "") == (c.Procedure == "") {
+		return fmt.Errorf("SQL语句和存储过程必须且只能填写一项")
+	}
+	if c.Procedure != "" && !procedureNamePattern.MatchString(c.Procedure) {
+		return fmt.Errorf("存储过程名称格式错误: %s", c.Procedure)
+	}
+	if !c.IsWhitelisted(whitelist) {
+		return fmt.Errorf("SQL语句或存储过程不在白名单内")
+	}
+	if c.Timeout > 3600 {
+		return fmt.Errorf("超时时间不能超过3600秒")
+	}
+	return nil
+}
+
+// IsWhitelisted 检查语句或存储过程是否在白名单内
+func (c *SqlJobConfig) IsWhitelisted(whitelist []string) bool {
+	_, ok := c.ApprovedText(whitelist)
+	return ok
+}
+
+// ApprovedText 返回与语句或存储过程匹配的白名单原文，执行时使用该原文而不是提交的文本
+// 比较时只忽略字符串常量以外的多余空白和末尾分号，大小写、方括号和字符串常量都按原样比较
+func (c *SqlJobConfig) ApprovedText(whitelist []string) (string, bool) {
+	target := normalizeSql(c.Statement)
+	if c.Procedure != "" {
+		target = normalizeSql(c.Procedure)
+	}
+
+	for _, allowed := range whitelist {
+		if normalizeSql(allowed) == target {
+			return strings.TrimRight(strings.TrimSpace(allowed), "; \t\r\n"), true
+		}
+	}
+	return "", false
+}
+
+// IsQuery 判断SQL语句是否为查询语句
+func (c *SqlJobConfig) IsQuery() bool {
+	stmt := strings.ToUpper(normalizeSql(c.Statement))
+	return strings.HasPrefix(stmt, "SELECT ") || strings.HasPrefix(stmt, "WITH ")
+}
+
+// normalizeSql 规范化SQL文本用于白名单比较：字符串常量以外的连续空白合并为一个空格，去掉末尾的分号
+// 引号内的内容原样保留，两个连续的引号按转义处理
+func normalizeSql(sql string) string {
+	var b strings.Builder
+	var quote rune
+	space := false
+	for _, r := range sql {
+		if quote != 0 {
+			b.WriteRune(r)
+			if r == quote {
+				quote = 0
+			}
+			continue
+		}
+		if unicode.IsSpace(r) {
+			space = true
+			continue
+		}
+		if space && b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		space = false
+		if r == '\'' || r == '"' || r == '`' {
+			quote = r
+		}
+		b.WriteRune(r)
+	}
+	if quote != 0 {
+		return b.String()
+	}
+	return strings.TrimRight(b.String(), "; ")
+}
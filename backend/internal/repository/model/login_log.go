@@ -10,6 +10,7 @@ import (
 // 注意：虽然Java后端继承BaseEntity，但数据库表实际没有BaseEntity的字段
 type SysLogininfor struct {
 	InfoID        int64      `gorm:"column:info_id;primaryKey;autoIncrement" json:"infoId" excel:"name:访问编号;sort:1;cellType:numeric"`     // 访问ID
+	TenantID      int64      `gorm:"column:tenant_id;<-:create;default:1" json:"tenantId"`                                                // 租户ID（迁移0005新增，只在新增时写入）
 	UserName      string     `gorm:"column:user_name;size:50" json:"userName" excel:"name:用户名称;sort:2"`                                   // 用户账号
 	IPAddr        string     `gorm:"column:ipaddr;size:128" json:"ipaddr" excel:"name:登录地址;sort:3"`                                       // 登录IP地址
 	LoginLocation string     `gorm:"column:login_location;size:255" json:"loginLocation" excel:"name:登录地点;sort:4"`                        // 登录地点
@@ -13,6 +13,20 @@ type LoginUser struct {
 	OS            string   `json:"os"`            // 操作系统
 	Permissions   []string `json:"permissions"`   // 权限列表
 	User          *SysUser `json:"user"`          // 用户信息
+
+	TenantID        int64 `json:"tenantId"`        // 用户所属租户
+	DynamicTenantID int64 `json:"dynamicTenantId"` // 超级管理员切换到的租户，0为未切换
+}
+
+// CurrentTenantID 当前操作的租户，超级管理员切换租户后为切换到的租户
+func (u *LoginUser) CurrentTenantID() int64 {
+	if u.DynamicTenantID > 0 {
+		return u.DynamicTenantID
+	}
+	if u.TenantID > 0 {
+		return u.TenantID
+	}
+	return DefaultTenantID
 }
 
 // LoginBody 登录请求体 对应Java后端的LoginBody
@@ -21,11 +35,13 @@ type LoginBody struct {
 	Password string `json:"password" binding:"required"` // 密码
 	Code     string `json:"code"`                        // 验证码
 	UUID     string `json:"uuid"`                        // 唯一标识
+	TenantID int64  `json:"tenantId"`                    // 租户ID，开启租户模式时为空则按访问域名确定租户
 }
 
 // CaptchaResponse 验证码响应 对应Java后端的验证码返回格式
 type CaptchaResponse struct {
 	CaptchaEnabled bool   `json:"captchaEnabled"` // 验证码开关
+	TenantEnabled  bool   `json:"tenantEnabled"`  // 租户模式开关，开启时登录和注册需要确定租户
 	UUID           string `json:"uuid,omitempty"` // 唯一标识
 	Img            string `json:"img,omitempty"`  // 验证码图片base64
 }
@@ -62,6 +62,7 @@ type SysMenu struct {
 	CreateTime *time.Time `gorm:"column:create_time" json:"createTime" excel:"name:创建时间;sort:18"`                                                                    // 创建时间
 	UpdateBy   string     `gorm:"column:update_by;size:64" json:"updateBy" excel:"name:更新者;sort:19"`                                                                 // 更新者
 	UpdateTime *time.Time `gorm:"column:update_time" json:"updateTime" excel:"name:更新时间;sort:20"`                                                                    // 更新时间
+	Version    int64      `gorm:"column:version;default:1" json:"version"`                                                                                           // 版本号（乐观锁）
 	Remark     string     `gorm:"column:remark;size:500" json:"remark" excel:"name:备注;sort:21"`                                                                      // 备注
 
 	// 关联字段（不存储在数据库中）
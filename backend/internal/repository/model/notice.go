@@ -9,6 +9,7 @@ import (
 // notice_id, notice_title, notice_type, notice_content, status, create_by, create_time, update_by, update_time, remark
 type SysNotice struct {
 	NoticeID      int64      `gorm:"column:notice_id;primaryKey;autoIncrement" json:"noticeId"`     // 公告ID
+	TenantID      int64      `gorm:"column:tenant_id;<-:create;default:1" json:"tenantId"`          // 租户ID（迁移0005新增，只在新增时写入）
 	NoticeTitle   string     `gorm:"column:notice_title;size:50;not null" json:"noticeTitle"`       // 公告标题
 	NoticeType    string     `gorm:"column:notice_type;size:1;not null" json:"noticeType"`          // 公告类型（1通知 2公告）
 	NoticeContent string     `gorm:"column:notice_content;type:nvarchar(max)" json:"noticeContent"` // 公告内容
@@ -54,7 +55,6 @@ type NoticeQueryParams struct {
 	IsAsc         string `form:"isAsc" json:"isAsc"`                 // 排序方向
 
 	// 数据权限相关字段（不通过表单绑定，由后端设置）
-	DataScope       string `form:"-" json:"-"`                 // 数据权限范围
 	CurrentUserId   int64  `form:"-" json:"-"`                 // 当前用户ID
 	CurrentUserName string `form:"-" json:"-"`                 // 当前用户名
 	BeginTime       string `form:"beginTime" json:"beginTime"` // 开始时间
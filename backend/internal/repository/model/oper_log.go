@@ -8,9 +8,11 @@ import (
 // 严格按照真实数据库表结构定义（基于SqlServer_ry_20250522_COMPLETE.sql）：
 // 数据库表只有17个字段：oper_id, title, business_type, method, request_method, operator_type, oper_name, dept_name,
 // oper_url, oper_ip, oper_location, oper_param, json_result, status, error_msg, oper_time, cost_time
+// 迁移0003新增request_id字段，用于关联同一请求的数据变更记录（sys_data_change）
 // 注意：虽然Java后端继承BaseEntity，但数据库表实际没有BaseEntity的字段
 type SysOperLog struct {
 	OperID        int64      `gorm:"column:oper_id;primaryKey;autoIncrement" json:"operId" excel:"name:操作序号;sort:1;cellType:numeric"`                                                    // 日志主键
+	TenantID      int64      `gorm:"column:tenant_id;<-:create;default:1" json:"tenantId"`                                                                                               // 租户ID（迁移0005新增，只在新增时写入）
 	Title         string     `gorm:"column:title;size:50" json:"title" excel:"name:系统模块;sort:2"`                                                                                         // 模块标题
 	BusinessType  int        `gorm:"column:business_type;default:0" json:"businessType" excel:"name:操作类型;sort:3;readConverterExp:0=其它,1=新增,2=修改,3=删除,4=授权,5=导出,6=导入,7=强退,8=生成代码,9=清空数据"` // 业务类型（0其它 1新增 2修改 3删除）
 	Method        string     `gorm:"column:method;size:200" json:"method" excel:"name:请求方法;sort:4"`                                                                                      // 方法名称
@@ -27,6 +29,7 @@ type SysOperLog struct {
 	ErrorMsg      string     `gorm:"column:error_msg;type:text" json:"errorMsg" excel:"name:错误消息;sort:15;width:50;type:export"`                                                          // 错误消息
 	OperTime      *time.Time `gorm:"column:oper_time" json:"operTime" excel:"name:操作时间;sort:16;width:30;dateFormat:yyyy-MM-dd HH:mm:ss"`                                                 // 操作时间
 	CostTime      int64      `gorm:"column:cost_time;default:0" json:"costTime" excel:"name:消耗时间;sort:17;suffix:毫秒"`                                                                     // 消耗时间
+	RequestID     string     `gorm:"column:request_id;size:64;default:''" json:"requestId"`                                                                                              // 请求ID
 
 	// 查询条件字段（不映射到数据库）
 	BusinessTypes []int  `gorm:"-" json:"businessTypes"` // 业务类型数组
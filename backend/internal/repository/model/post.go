@@ -11,6 +11,7 @@ import (
 // post_id, post_code, post_name, post_sort, status, create_by, create_time, update_by, update_time, remark
 type SysPost struct {
 	PostID     int64      `gorm:"column:post_id;primaryKey;autoIncrement" json:"postId" excel:"name:岗位序号;sort:1;cellType:numeric"` // 岗位ID
+	TenantID   int64      `gorm:"column:tenant_id;<-:create;default:1" json:"tenantId"`                                            // 租户ID（迁移0005新增，只在新增时写入）
 	PostCode   string     `gorm:"column:post_code;size:64;not null" json:"postCode" excel:"name:岗位编码;sort:2"`                      // 岗位编码
 	PostName   string     `gorm:"column:post_name;size:50;not null" json:"postName" excel:"name:岗位名称;sort:3"`                      // 岗位名称
 	PostSort   int        `gorm:"column:post_sort;not null" json:"postSort" excel:"name:岗位排序;sort:4"`                              // 显示顺序
@@ -0,0 +1,49 @@
+package model
+
+import "time"
+
+// SysRecycle 回收站表 记录删除的用户、部门和角色，删除的数据本身仍保留在原表中（del_flag为2）
+// recycle_id, object_type, object_id, object_name, snapshot, delete_by, delete_time
+type SysRecycle struct {
+	RecycleID  int64      `gorm:"column:recycle_id;primaryKey;autoIncrement" json:"recycleId"` // 回收站ID
+	TenantID   int64      `gorm:"column:tenant_id;<-:create;default:1" json:"tenantId"`        // 租户ID（迁移0005新增，只在新增时写入）
+	ObjectType string     `gorm:"column:object_type;size:20;not null" json:"objectType"`       // 对象类型（user用户 dept部门 role角色）
+	ObjectID   int64      `gorm:"column:object_id;not null" json:"objectId"`                   // 对象ID
+	ObjectName string     `gorm:"column:object_name;size:100;default:''" json:"objectName"`    // 对象名称
+	Snapshot   string     `gorm:"column:snapshot" json:"-"`                                    // 删除时的关联数据（JSON）
+	DeleteBy   string     `gorm:"column:delete_by;size:64;default:''" json:"deleteBy"`         // 删除者
+	DeleteTime *time.Time `gorm:"column:delete_time" json:"deleteTime"`                        // 删除时间
+
+	// 查询条件字段（不映射到数据库）
+	BeginTime *time.Time `gorm:"-" json:"-"` // 删除开始时间
+	EndTime   *time.Time `gorm:"-" json:"-"` // 删除结束时间
+}
+
+// TableName 指定表名
+func (SysRecycle) TableName() string {
+	return "sys_recycle"
+}
+
+// 回收站对象类型常量
+const (
+	RecycleTypeUser = "user" // 用户
+	RecycleTypeDept = "dept" // 部门
+	RecycleTypeRole = "role" // 角色
+)
+
+// IsRecycleType 是否为回收站支持的对象类型
+func IsRecycleType(objectType string) bool {
+	switch objectType {
+	case RecycleTypeUser, RecycleTypeDept, RecycleTypeRole:
+		return true
+	}
+	return false
+}
+
+// RecycleSnapshot 删除时解除的关联数据，恢复时重新建立（已不存在的关联对象会被跳过）
+type RecycleSnapshot struct {
+	RoleIds []int64 `json:"roleIds,omitempty"` // 用户的角色
+	PostIds []int64 `json:"postIds,omitempty"` // 用户的岗位
+	MenuIds []int64 `json:"menuIds,omitempty"` // 角色的菜单权限
+	DeptIds []int64 `json:"deptIds,omitempty"` // 角色的自定义数据权限部门
+}
@@ -8,6 +8,7 @@ type RegisterBody struct {
 	Password string `json:"password" binding:"required"` // 密码
 	Code     string `json:"code"`                        // 验证码
 	UUID     string `json:"uuid"`                        // 唯一标识
+	TenantID int64  `json:"tenantId"`                    // 租户ID，开启租户模式时为空则按访问域名确定租户
 }
 
 // Validate 验证注册参数
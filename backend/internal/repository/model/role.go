@@ -33,6 +33,7 @@ import (
 //	}
 type SysRole struct {
 	RoleID            int64      `gorm:"column:role_id;primaryKey;autoIncrement" json:"roleId" excel:"name:角色序号;sort:1;cellType:numeric"`                                                   // 角色ID
+	TenantID          int64      `gorm:"column:tenant_id;<-:create;default:1" json:"tenantId"`                                                                                              // 租户ID（迁移0005新增，只在新增时写入）
 	RoleName          string     `gorm:"column:role_name;size:30;not null" json:"roleName" excel:"name:角色名称;sort:2" validate:"required,role_name"`                                          // 角色名称
 	RoleKey           string     `gorm:"column:role_key;size:100;not null" json:"roleKey" excel:"name:角色权限;sort:3" validate:"required,role_key"`                                            // 角色权限字符串
 	RoleSort          int        `gorm:"column:role_sort;not null" json:"roleSort" excel:"name:角色排序;sort:4" validate:"required,min=0"`                                                      // 显示顺序
@@ -45,6 +46,7 @@ type SysRole struct {
 	CreateTime        *time.Time `gorm:"column:create_time" json:"createTime"`                                                                                                              // 创建时间
 	UpdateBy          string     `gorm:"column:update_by;size:64" json:"updateBy"`                                                                                                          // 更新者
 	UpdateTime        *time.Time `gorm:"column:update_time" json:"updateTime"`                                                                                                              // 更新时间
+	Version           int64      `gorm:"column:version;default:1" json:"version"`                                                                                                           // 版本号（乐观锁）
 	Remark            string     `gorm:"column:remark;size:500" json:"remark"`                                                                                                              // 备注
 
 	// 关联字段（不存储在数据库中）
@@ -0,0 +1,98 @@
+package model
+
+import "time"
+
+// DefaultTenantID 平台租户ID，未开启租户模式时所有数据属于平台租户；参数和字典数据未被租户覆盖时使用平台租户的值
+const DefaultTenantID int64 = 1
+
+// TenantAdminRoleKey 新增租户时创建的管理员角色的权限字符，套餐菜单变化时同步该角色的菜单
+const TenantAdminRoleKey = "tenant_admin"
+
+// TenantMenuPerms 租户管理和租户套餐菜单的权限标识，只有平台租户可以使用，不分配给其他租户；菜单ID由数据库生成，按权限标识查找
+var TenantMenuPerms = []string{"system:tenant:list", "system:tenantPackage:list"}
+
+// SysTenant 租户表
+// tenant_id, tenant_name, domain, package_id, contact_user_name, contact_phone, expire_time, status, del_flag,
+// create_by, create_time, update_by, update_time, remark
+type SysTenant struct {
+	TenantID        int64      `gorm:"column:tenant_id;primaryKey;autoIncrement" json:"tenantId"`                           // 租户ID
+	TenantName      string     `gorm:"column:tenant_name;size:50;not null" json:"tenantName" binding:"required,max=50"`     // 租户名称
+	Domain          string     `gorm:"column:domain;size:100;default:''" json:"domain" binding:"max=100"`                   // 绑定域名，登录时按访问域名确定租户
+	PackageID       int64      `gorm:"column:package_id;default:0" json:"packageId"`                                        // 租户套餐ID，0为不限制菜单
+	ContactUserName string     `gorm:"column:contact_user_name;size:30;default:''" json:"contactUserName" binding:"max=30"` // 联系人
+	ContactPhone    string     `gorm:"column:contact_phone;size:20;default:''" json:"contactPhone" binding:"max=20"`        // 联系电话
+	ExpireTime      *time.Time `gorm:"column:expire_time" json:"expireTime"`                                                // 过期时间，为空时不过期
+	Status          string     `gorm:"column:status;size:1;default:'0'" json:"status"`                                      // 租户状态（0正常 1停用）
+	DelFlag         string     `gorm:"column:del_flag;size:1;default:'0'" json:"delFlag"`                                   // 删除标志（0代表存在 2代表删除）
+	CreateBy        string     `gorm:"column:create_by;size:64;default:''" json:"createBy"`                                 // 创建者
+	CreateTime      *time.Time `gorm:"column:create_time" json:"createTime"`                                                // 创建时间
+	UpdateBy        string     `gorm:"column:update_by;size:64;default:''" json:"updateBy"`                                 // 更新者
+	UpdateTime      *time.Time `gorm:"column:update_time" json:"updateTime"`                                                // 更新时间
+	Remark          string     `gorm:"column:remark;size:500" json:"remark"`                                                // 备注
+
+	// 新增租户时创建的管理员账号（不存储在租户表中）
+	AdminUserName string `gorm:"-" json:"adminUserName,omitempty"` // 管理员账号
+	AdminPassword string `gorm:"-" json:"adminPassword,omitempty"` // 管理员密码
+}
+
+// TableName 设置表名
+func (SysTenant) TableName() string {
+	return "sys_tenant"
+}
+
+// IsPlatform 是否为平台租户
+func (t *SysTenant) IsPlatform() bool {
+	return t.TenantID == DefaultTenantID
+}
+
+// IsExpired 是否已过期
+func (t *SysTenant) IsExpired() bool {
+	return t.ExpireTime != nil && t.ExpireTime.Before(time.Now())
+}
+
+// TenantQueryParams 租户查询参数
+type TenantQueryParams struct {
+	QueryParams        // 继承分页参数
+	TenantName  string `form:"tenantName" json:"tenantName"` // 租户名称
+	Domain      string `form:"domain" json:"domain"`         // 绑定域名
+	Status      string `form:"status" json:"status"`         // 租户状态
+}
+
+// SysTenantPackage 租户套餐表 套餐决定租户可以使用的菜单
+// package_id, package_name, status, del_flag, create_by, create_time, update_by, update_time, remark
+type SysTenantPackage struct {
+	PackageID   int64      `gorm:"column:package_id;primaryKey;autoIncrement" json:"packageId"`                       // 套餐ID
+	PackageName string     `gorm:"column:package_name;size:50;not null" json:"packageName" binding:"required,max=50"` // 套餐名称
+	Status      string     `gorm:"column:status;size:1;default:'0'" json:"status"`                                    // 套餐状态（0正常 1停用）
+	DelFlag     string     `gorm:"column:del_flag;size:1;default:'0'" json:"delFlag"`                                 // 删除标志（0代表存在 2代表删除）
+	CreateBy    string     `gorm:"column:create_by;size:64;default:''" json:"createBy"`                               // 创建者
+	CreateTime  *time.Time `gorm:"column:create_time" json:"createTime"`                                              // 创建时间
+	UpdateBy    string     `gorm:"column:update_by;size:64;default:''" json:"updateBy"`                               // 更新者
+	UpdateTime  *time.Time `gorm:"column:update_time" json:"updateTime"`                                              // 更新时间
+	Remark      string     `gorm:"column:remark;size:500" json:"remark"`                                              // 备注
+
+	MenuIds []int64 `gorm:"-" json:"menuIds"` // 套餐包含的菜单
+}
+
+// TableName 设置表名
+func (SysTenantPackage) TableName() string {
+	return "sys_tenant_package"
+}
+
+// TenantPackageQueryParams 租户套餐查询参数
+type TenantPackageQueryParams struct {
+	QueryParams        // 继承分页参数
+	PackageName string `form:"packageName" json:"packageName"` // 套餐名称
+	Status      string `form:"status" json:"status"`           // 套餐状态
+}
+
+// SysTenantPackageMenu 租户套餐和菜单关联表
+type SysTenantPackageMenu struct {
+	PackageID int64 `gorm:"column:package_id;primaryKey" json:"packageId"` // 套餐ID
+	MenuID    int64 `gorm:"column:menu_id;primaryKey" json:"menuId"`       // 菜单ID
+}
+
+// TableName 设置表名
+func (SysTenantPackageMenu) TableName() string {
+	return "sys_tenant_package_menu"
+}
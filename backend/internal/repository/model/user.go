@@ -9,6 +9,7 @@ import (
 // SysUser 用户信息表 对应Java后端的SysUser实体
 type SysUser struct {
 	UserID        int64      `gorm:"column:user_id;primaryKey;autoIncrement" json:"userId" excel:"name:用户序号;sort:1;type:export;cellType:numeric;prompt:用户编号"` // 用户ID
+	TenantID      int64      `gorm:"column:tenant_id;<-:create;default:1" json:"tenantId"`                                                                    // 租户ID（迁移0005新增，只在新增时写入）
 	DeptID        *int64     `gorm:"column:dept_id" json:"deptId" excel:"name:部门编号;sort:2;type:import"`                                                       // 部门ID
 	UserName      string     `gorm:"column:user_name;size:30;not null" json:"userName" binding:"required,max=30,xss" excel:"name:登录名称;sort:3"`                // 用户账号
 	NickName      string     `gorm:"column:nick_name;size:30;not null" json:"nickName" binding:"required,max=30,xss" excel:"name:用户名称;sort:4"`                // 用户昵称
@@ -27,6 +28,7 @@ type SysUser struct {
 	CreateTime    *time.Time `gorm:"column:create_time" json:"createTime"`                                                                                    // 创建时间
 	UpdateBy      string     `gorm:"column:update_by;size:64" json:"updateBy"`                                                                                // 更新者
 	UpdateTime    *time.Time `gorm:"column:update_time" json:"updateTime"`                                                                                    // 更新时间
+	Version       int64      `gorm:"column:version;default:1" json:"version"`                                                                                 // 版本号（乐观锁）
 	Remark        string     `gorm:"column:remark;size:500" json:"remark"`                                                                                    // 备注
 
 	// 搜索字段（不存储在数据库中，对应Java的searchValue）
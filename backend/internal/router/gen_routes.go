@@ -0,0 +1,13 @@
+// Package router 注册代码生成器生成的业务模块路由
+// 代码生成时勾选"注册路由"会在标记行后追加导入和注册语句，请保留标记行
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	// gen:imports
+)
+
+// RegisterGenRoutes 注册代码生成器生成的业务路由，router 为需要登录的路由组
+func RegisterGenRoutes(router *gin.RouterGroup) {
+	// gen:routes
+}
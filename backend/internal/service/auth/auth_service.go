@@ -23,6 +23,7 @@ import (
 
 // AuthService 认证服务 对应Java后端的SysLoginService
 type AuthService struct {
+	ctx             context.Context // 数据访问使用的context，方法中创建的其他服务使用该context
 	userDao         *dao.UserDao
 	menuDao         *dao.MenuDao
 	configService   *system.ConfigService
@@ -37,6 +38,17 @@ func NewAuthService() *AuthService {
 	}
 }
 
+// WithContext 返回使用指定context的服务实例，数据访问携带请求信息和租户
+func (s *AuthService) WithContext(ctx context.Context) *AuthService {
+	return &AuthService{
+		ctx:             ctx,
+		userDao:         s.userDao.WithContext(ctx),
+		menuDao:         s.menuDao.WithContext(ctx),
+		configService:   s.configService.WithContext(ctx),
+		passwordService: s.passwordService,
+	}
+}
+
 // NewAuthServiceWithPassword 创建带密码验证的认证服务
 func NewAuthServiceWithPassword(configService *system.ConfigService, redisClient *redisv9.Client, cfg *config.Config) *AuthService {
 	return &AuthService{
@@ -148,6 +160,7 @@ func (s *AuthService) Login(loginBody *model.LoginBody, userAgent, ipAddr string
 	loginUser := &model.LoginUser{
 		UserID:        user.UserID,
 		DeptID:        user.DeptID,
+		TenantID:      user.TenantID,
 		Token:         token,
 		LoginTime:     time.Now().UnixMilli(),                    // 使用毫秒时间戳，对应Java后端
 		ExpireTime:    time.Now().Add(2 * time.Hour).UnixMilli(), // 使用毫秒时间戳
@@ -243,7 +256,7 @@ func (s *AuthService) RefreshToken(loginUser *model.LoginUser) error {
 // validateCaptcha 校验验证码 对应Java后端的validateCaptcha
 func (s *AuthService) validateCaptcha(username, code, uuid string) error {
 	// 检查验证码是否启用 - 使用数据库配置
-	configService := systemService.NewConfigService()
+	configService := systemService.NewConfigService().WithContext(s.ctx)
 	captchaEnabled, err := configService.SelectCaptchaEnabled()
 	if err != nil {
 		fmt.Printf("获取验证码配置失败: %v，使用配置文件默认值\n", err)
@@ -866,10 +879,8 @@ func (s *AuthService) recordLoginInfo(userId int64, ipAddr string) {
 // recordLoginLog 记录登录日志
 func (s *AuthService) recordLoginLog(userName, status, message, ipAddr, userAgent string) {
 	// 创建登录日志服务实例
-	loginLogService := systemService.NewLoginLogService()
+	loginLogService := systemService.NewLoginLogService().WithContext(s.ctx)
 
-	// 异步记录登录日志
-	go func() {
-		loginLogService.RecordLoginInfo(userName, status, message, ipAddr, userAgent)
-	}()
+	// 使用登录请求的context记录，日志属于请求的租户
+	loginLogService.RecordLoginInfo(userName, status, message, ipAddr, userAgent)
 }
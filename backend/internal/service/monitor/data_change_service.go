@@ -0,0 +1,33 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"wosm/internal/repository/dao"
+	"wosm/internal/repository/model"
+)
+
+// DataChangeService 数据变更历史服务
+type DataChangeService struct {
+	dataChangeDao *dao.DataChangeDao
+}
+
+// NewDataChangeService 创建数据变更历史服务实例
+func NewDataChangeService() *DataChangeService {
+	return &DataChangeService{
+		dataChangeDao: dao.NewDataChangeDao(),
+	}
+}
+
+// WithContext 返回使用指定context的服务实例，数据访问携带请求信息和租户
+func (s *DataChangeService) WithContext(ctx context.Context) *DataChangeService {
+	return &DataChangeService{
+		dataChangeDao: s.dataChangeDao.WithContext(ctx),
+	}
+}
+
+// SelectHistory 查询实体的字段变更时间线，最新的在前
+func (s *DataChangeService) SelectHistory(entity string, entityId int64, pageNum, pageSize int) ([]model.SysDataChange, int64, error) {
+	fmt.Printf("DataChangeService.SelectHistory: 查询变更历史, Entity=%s, EntityID=%d\n", entity, entityId)
+	return s.dataChangeDao.SelectDataChangeList(entity, entityId, pageNum, pageSize)
+}
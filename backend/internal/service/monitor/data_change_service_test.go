@@ -0,0 +1,153 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"wosm/internal/repository/dao"
+	"wosm/internal/repository/model"
+	"wosm/internal/service/system"
+	"wosm/internal/testutil"
+	"wosm/pkg/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// changedFields 按字段名索引变更记录
+func changedFields(changes []model.SysDataChange) map[string]model.SysDataChange {
+	fields := make(map[string]model.SysDataChange, len(changes))
+	for _, change := range changes {
+		fields[change.FieldName] = change
+	}
+	return fields
+}
+
+func TestDataChangeHistoryInRequest(t *testing.T) {
+	db := testutil.OpenMigratedDB(t)
+	require.NoError(t, db.Use(dao.NewDataChangePlugin()))
+	service := NewDataChangeService()
+
+	ctx, done := database.Stats.TrackRequest(context.Background(), "req-history", "PUT", "/system/user")
+	ctx = database.WithRequestOperator(ctx, "admin")
+
+	// Model(&X{}).Where(...).Updates(map)
+	require.NoError(t, db.WithContext(ctx).Model(&model.SysUser{}).Where("user_id = ?", 2).
+		Updates(map[string]interface{}{"dept_id": 103, "password": "changed", "nick_name": "若依", "update_by": "admin"}).Error)
+	// Where(...).Updates(struct)，只修改非零值字段
+	require.NoError(t, dao.NewDeptDao().WithContext(ctx).UpdateDept(&model.SysDept{DeptID: 105, DeptName: "质量部门"}))
+	// Save按结构体主键修改
+	var menu model.SysMenu
+	require.NoError(t, db.First(&menu, 1010).Error)
+	menu.MenuName = "角色移除"
+	require.NoError(t, dao.NewMenuDao().WithContext(ctx).UpdateMenu(&menu))
+	done()
+
+	operLog := &model.SysOperLog{Title: "用户管理", OperName: "admin", RequestID: "req-history"}
+	require.NoError(t, system.NewOperLogService().InsertOperLog(operLog))
+
+	changes, total, err := service.SelectHistory(model.DataChangeEntityUser, 2, 1, 10)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, total)
+	fields := changedFields(changes)
+	assert.Equal(t, "105", fields["dept_id"].OldValue)
+	assert.Equal(t, "103", fields["dept_id"].NewValue)
+	assert.Equal(t, "******", fields["password"].OldValue)
+	assert.Equal(t, "******", fields["password"].NewValue)
+	assert.NotContains(t, fields, "nick_name")
+	assert.NotContains(t, fields, "update_by")
+	for _, change := range changes {
+		assert.Equal(t, "admin", change.OperName)
+		assert.Equal(t, "req-history", change.RequestID)
+		assert.Equal(t, operLog.OperID, change.OperID)
+		assert.NotNil(t, change.ChangeTime)
+	}
+
+	changes, _, err = service.SelectHistory(model.DataChangeEntityDept, 105, 1, 10)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, "dept_name", changes[0].FieldName)
+	assert.Equal(t, "测试部门", changes[0].OldValue)
+	assert.Equal(t, "质量部门", changes[0].NewValue)
+
+	changes, _, err = service.SelectHistory(model.DataChangeEntityMenu, 1010, 1, 10)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, "角色删除", changes[0].OldValue)
+	assert.Equal(t, "角色移除", changes[0].NewValue)
+}
+
+func TestDataChangeHistoryOutsideRequest(t *testing.T) {
+	db := testutil.OpenMigratedDB(t)
+	require.NoError(t, db.Use(dao.NewDataChangePlugin()))
+	service := NewDataChangeService()
+
+	// 不在请求中时操作人员取修改后的update_by，批量修改每行分别记录
+	require.NoError(t, db.Table("sys_role").Where("role_id IN ?", []int64{1, 2}).
+		Updates(map[string]interface{}{"status": "1", "update_by": "ry"}).Error)
+	for _, roleId := range []int64{1, 2} {
+		changes, _, err := service.SelectHistory(model.DataChangeEntityRole, roleId, 1, 10)
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+		assert.Equal(t, "status", changes[0].FieldName)
+		assert.Equal(t, "ry", changes[0].OperName)
+		assert.Empty(t, changes[0].RequestID)
+		assert.Zero(t, changes[0].OperID)
+	}
+
+	// 事务回滚时变更记录一起回滚
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.SysConfig{}).Where("config_id = ?", 100).Update("config_value", "skin-red").Error; err != nil {
+			return err
+		}
+		return errors.New("回滚")
+	})
+	require.Error(t, err)
+	_, total, err := service.SelectHistory(model.DataChangeEntityConfig, 100, 1, 10)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+
+	// 带别名的表按表名匹配，条件中可以使用别名
+	require.NoError(t, db.Table("sys_user AS u").Where("u.user_id = ?", 2).Update("email", "ry@example.com").Error)
+	changes, _, err := service.SelectHistory(model.DataChangeEntityUser, 2, 1, 10)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, "email", changes[0].FieldName)
+	assert.Equal(t, "ry@example.com", changes[0].NewValue)
+
+	// 未记录变更历史的表
+	require.NoError(t, db.Table("sys_post").Where("post_id = ?", 1).Update("post_name", "董事").Error)
+	var count int64
+	db.Model(&model.SysDataChange{}).Count(&count)
+	assert.EqualValues(t, 3, count)
+}
+
+func TestDataChangeWriteFailureKeepsUpdate(t *testing.T) {
+	db := testutil.OpenMigratedDB(t)
+	require.NoError(t, db.Use(dao.NewDataChangePlugin()))
+	// 变更记录表不存在，写入变更记录失败
+	require.NoError(t, db.Migrator().DropTable(&model.SysDataChange{}))
+	// 记录事务中执行的保存点语句
+	var statements []string
+	require.NoError(t, db.Callback().Raw().After("gorm:raw").Register("test:record_raw", func(tx *gorm.DB) {
+		statements = append(statements, tx.Statement.SQL.String())
+	}))
+
+	require.NoError(t, db.Model(&model.SysConfig{}).Where("config_id = ?", 100).Update("config_value", "skin-red").Error)
+	var values []string
+	require.NoError(t, db.Model(&model.SysConfig{}).Where("config_id = ?", 100).Pluck("config_value", &values).Error)
+	assert.Equal(t, []string{"skin-red"}, values)
+	assert.Equal(t, []string{"SAVEPOINT wosm_data_change", "ROLLBACK TO SAVEPOINT wosm_data_change"}, statements)
+
+	// 外层事务中的后续修改仍然可以执行并一起提交
+	require.NoError(t, db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.SysConfig{}).Where("config_id = ?", 100).Update("config_value", "skin-green").Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.SysConfig{}).Where("config_id = ?", 101).Update("config_value", "654321").Error
+	}))
+	values = nil
+	require.NoError(t, db.Model(&model.SysConfig{}).Where("config_id IN ?", []int64{100, 101}).Order("config_id").Pluck("config_value", &values).Error)
+	assert.Equal(t, []string{"skin-green", "654321"}, values)
+}
@@ -1,10 +1,14 @@
 package monitor
 
 import (
+	"context"
 	"fmt"
+	"strconv"
 	"time"
+	"wosm/internal/constants"
 	"wosm/internal/repository/dao"
 	"wosm/internal/repository/model"
+	"wosm/pkg/redis"
 )
 
 // JobLogService 定时任务调度日志服务 对应Java后端的ISysJobLogService
@@ -19,6 +23,13 @@ func NewJobLogService() *JobLogService {
 	}
 }
 
+// WithContext 返回使用指定context的服务实例，数据访问携带请求信息和租户
+func (s *JobLogService) WithContext(ctx context.Context) *JobLogService {
+	return &JobLogService{
+		jobLogDao: s.jobLogDao.WithContext(ctx),
+	}
+}
+
 // SelectJobLogList 查询定时任务调度日志列表 对应Java后端的selectJobLogList
 func (s *JobLogService) SelectJobLogList(jobLog *model.SysJobLog, pageNum, pageSize int) ([]model.SysJobLog, int64, error) {
 	fmt.Printf("JobLogService.SelectJobLogList: 查询定时任务调度日志列表, PageNum=%d, PageSize=%d\n", pageNum, pageSize)
@@ -109,3 +120,43 @@ func (s *JobLogService) AddJobLogSuccess(job *model.SysJob, message string) erro
 func (s *JobLogService) AddJobLogError(job *model.SysJob, message string, exceptionInfo string) error {
 	return s.AddJobLogByJob(job, model.JobLogStatusFail, message, exceptionInfo)
 }
+
+// ReadJobLogStream 从Redis读取任务实时日志，offset 为已读取的行数
+// 返回新增的日志行、任务是否已结束以及结束时的执行状态
+func (s *JobLogService) ReadJobLogStream(jobLogId int64, offset int64) ([]string, bool, string, error) {
+	rdb := redis.GetRedis()
+	if rdb == nil {
+		return nil, false, "", fmt.Errorf("Redis未初始化")
+	}
+
+	ctx := context.Background()
+	key := constants.JOB_LOG_STREAM_KEY + strconv.FormatInt(jobLogId, 10)
+
+	// 先读取结束标记再读取日志，保证结束前写入的日志不会遗漏
+	status, err := rdb.Get(ctx, key+constants.JOB_LOG_STREAM_END_SUFFIX).Result()
+	finished := err == nil
+	if err != nil && err != redis.Nil {
+		return nil, false, "", err
+	}
+
+	lines, err := rdb.LRange(ctx, key, offset, -1).Result()
+	if err != nil {
+		return nil, false, "", err
+	}
+
+	return lines, finished, status, nil
+}
+
+// SelectJobLogLines 获取已持久化的完整执行日志
+func (s *JobLogService) SelectJobLogLines(jobLogId int64) (*model.SysJobLog, []string, error) {
+	jobLog, err := s.jobLogDao.SelectJobLogById(jobLogId)
+	if err != nil || jobLog == nil {
+		return jobLog, nil, err
+	}
+
+	lines, err := jobLog.GetLogLines()
+	if err != nil {
+		return nil, nil, fmt.Errorf("解压执行日志失败: %v", err)
+	}
+	return jobLog, lines, nil
+}
@@ -1,16 +1,19 @@
 package system
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"time"
 	"wosm/internal/repository/dao"
 	"wosm/internal/repository/model"
+	"wosm/pkg/database"
 	"wosm/pkg/redis"
 )
 
 // ConfigService 参数配置服务 对应Java后端的ISysConfigService
 type ConfigService struct {
+	ctx       context.Context // 数据访问使用的context，方法中创建的其他服务使用该context
 	configDao *dao.ConfigDao
 }
 
@@ -28,6 +31,14 @@ func NewConfigService() *ConfigService {
 	return service
 }
 
+// WithContext 返回使用指定context的服务实例，数据访问携带请求信息和租户
+func (s *ConfigService) WithContext(ctx context.Context) *ConfigService {
+	return &ConfigService{
+		ctx:       ctx,
+		configDao: s.configDao.WithContext(ctx),
+	}
+}
+
 // SelectConfigById 根据参数ID查询参数配置信息 对应Java后端的selectConfigById
 func (s *ConfigService) SelectConfigById(configId int64) (*model.SysConfig, error) {
 	fmt.Printf("ConfigService.SelectConfigById: 查询参数配置信息, ConfigId=%d\n", configId)
@@ -47,8 +58,8 @@ func (s *ConfigService) SelectConfigByKey(configKey string) (string, error) {
 		return "", fmt.Errorf("参数键名不能为空")
 	}
 
-	// 先从缓存获取
-	cacheKey := model.GetConfigCacheKey(configKey)
+	// 先从缓存获取，租户的参数按租户缓存
+	cacheKey := model.GetTenantConfigCacheKey(database.RequestTenant(s.ctx), configKey)
 	configValue, err := redis.Get(cacheKey)
 	if err == nil && configValue != "" {
 		fmt.Printf("ConfigService.SelectConfigByKey: 从缓存获取参数值, ConfigKey=%s, Value=%s\n", configKey, configValue)
@@ -148,7 +159,7 @@ func (s *ConfigService) InsertConfig(config *model.SysConfig) error {
 	}
 
 	// 设置缓存
-	cacheKey := model.GetConfigCacheKey(config.ConfigKey)
+	cacheKey := model.GetTenantConfigCacheKey(config.TenantID, config.ConfigKey)
 	redis.Set(cacheKey, config.ConfigValue, 0)
 
 	return nil
@@ -171,6 +182,9 @@ func (s *ConfigService) UpdateConfig(config *model.SysConfig) error {
 	if existingConfig == nil {
 		return fmt.Errorf("参数配置不存在")
 	}
+	if isOtherTenantRow(s.ctx, existingConfig.TenantID) {
+		return fmt.Errorf("平台参数不能修改，请新增同键名的参数覆盖")
+	}
 
 	// 检查参数键名唯一性（排除当前记录）
 	isUnique, err := s.configDao.CheckConfigKeyUnique(config.ConfigKey, config.ConfigID)
@@ -181,11 +195,8 @@ func (s *ConfigService) UpdateConfig(config *model.SysConfig) error {
 		return fmt.Errorf("参数键名已存在")
 	}
 
-	// 如果键名发生变化，删除旧的缓存
-	if existingConfig.ConfigKey != config.ConfigKey {
-		oldCacheKey := model.GetConfigCacheKey(existingConfig.ConfigKey)
-		redis.Del(oldCacheKey)
-	}
+	// 删除旧的缓存（键名可能发生变化）
+	removeConfigCache(existingConfig.TenantID, existingConfig.ConfigKey)
 
 	// 设置更新时间
 	now := time.Now()
@@ -198,7 +209,8 @@ func (s *ConfigService) UpdateConfig(config *model.SysConfig) error {
 	}
 
 	// 更新缓存
-	cacheKey := model.GetConfigCacheKey(config.ConfigKey)
+	removeConfigCache(existingConfig.TenantID, config.ConfigKey)
+	cacheKey := model.GetTenantConfigCacheKey(existingConfig.TenantID, config.ConfigKey)
 	redis.Set(cacheKey, config.ConfigValue, 0)
 
 	return nil
@@ -235,10 +247,12 @@ func (s *ConfigService) DeleteConfigByIds(configIds []int64) error {
 		if existingConfig.IsBuiltIn() {
 			return fmt.Errorf("内置参数【%s】不能删除", existingConfig.ConfigKey)
 		}
+		if isOtherTenantRow(s.ctx, existingConfig.TenantID) {
+			return fmt.Errorf("平台参数【%s】不能删除", existingConfig.ConfigKey)
+		}
 
 		// 删除缓存
-		cacheKey := model.GetConfigCacheKey(existingConfig.ConfigKey)
-		redis.Del(cacheKey)
+		removeConfigCache(existingConfig.TenantID, existingConfig.ConfigKey)
 	}
 
 	return s.configDao.DeleteConfigByIds(configIds)
@@ -261,15 +275,15 @@ func (s *ConfigService) CheckConfigKeyUnique(config *model.SysConfig) (bool, err
 func (s *ConfigService) LoadingConfigCache() error {
 	fmt.Printf("ConfigService.LoadingConfigCache: 加载参数缓存\n")
 
-	// 查询所有参数配置
-	configs, err := s.configDao.SelectConfigAll()
+	// 查询所有租户的参数配置，缓存键区分租户
+	configs, err := s.configDao.WithContext(database.WithAllTenants(context.Background())).SelectConfigAll()
 	if err != nil {
 		return fmt.Errorf("查询所有参数配置失败: %v", err)
 	}
 
-	// 设置缓存
+	// 设置缓存，租户覆盖的参数按租户缓存
 	for _, config := range configs {
-		cacheKey := model.GetConfigCacheKey(config.ConfigKey)
+		cacheKey := model.GetTenantConfigCacheKey(config.TenantID, config.ConfigKey)
 		redis.Set(cacheKey, config.ConfigValue, 0)
 	}
 
@@ -368,3 +382,19 @@ func (s *ConfigService) InitDefaultConfigs() error {
 
 	return s.configDao.InitDefaultConfigs()
 }
+
+// removeConfigCache 删除参数缓存，平台租户的参数同时删除租户缓存的该参数（未覆盖的租户缓存的是平台租户的值）
+func removeConfigCache(tenantID int64, configKey string) {
+	redis.Del(model.GetTenantConfigCacheKey(tenantID, configKey))
+	if tenantID > model.DefaultTenantID {
+		return
+	}
+	keys, err := redis.Keys(model.GetConfigCacheKey("t*:" + configKey))
+	if err != nil {
+		fmt.Printf("removeConfigCache: 获取租户参数缓存键失败: %v\n", err)
+		return
+	}
+	for _, key := range keys {
+		redis.Del(key)
+	}
+}
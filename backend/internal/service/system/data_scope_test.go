@@ -0,0 +1,77 @@
+package system
+
+import (
+	"testing"
+	"wosm/internal/repository/dao"
+	"wosm/internal/repository/model"
+	"wosm/internal/testutil"
+	"wosm/pkg/datascope"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newScopeUser 初始数据中的ry用户：属于部门105，拥有自定数据权限的普通角色（自定部门为100、101、105）
+func newScopeUser(dataScope string) *model.SysUser {
+	deptId := int64(105)
+	return &model.SysUser{UserID: 2, UserName: "ry", DeptID: &deptId, Roles: []model.SysRole{{
+		RoleID: 2, DataScope: dataScope, Status: datascope.RoleStatusNormal,
+		Permissions: []string{"system:user:list", "system:role:list", "system:dept:list", "system:notice:list"},
+	}}}
+}
+
+// userIdsOf 用户ID列表
+func userIdsOf(users []model.SysUser) []int64 {
+	ids := make([]int64, len(users))
+	for i := range users {
+		ids[i] = users[i].UserID
+	}
+	return ids
+}
+
+func TestDataScopeQueries(t *testing.T) {
+	testutil.OpenMigratedDB(t)
+	// 不使用 NewUserService，避免参数缓存访问Redis
+	userService := &UserService{userDao: dao.NewUserDao()}
+	roleService := NewRoleService()
+	deptService := NewDeptService()
+
+	// 自定数据权限：只能访问部门100、101、105及其用户，角色按拥有该角色的用户所在部门过滤
+	current := newScopeUser(datascope.DataScopeCustom)
+	users, total, err := userService.SelectUserListWithDataScope(current, &model.SysUser{}, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, []int64{2}, userIdsOf(users))
+
+	roles, err := roleService.SelectRoleListWithDataScope(current, nil)
+	require.NoError(t, err)
+	require.Len(t, roles, 1)
+	assert.Equal(t, int64(2), roles[0].RoleID)
+
+	depts, err := deptService.SelectDeptListWithDataScope(current, nil)
+	require.NoError(t, err)
+	var deptIds []int64
+	for _, dept := range depts {
+		deptIds = append(deptIds, dept.DeptID)
+	}
+	assert.ElementsMatch(t, []int64{100, 101, 105}, deptIds)
+	assert.Error(t, deptService.CheckDeptDataScope(current, 103))
+
+	// 本部门及以下数据权限：部门105及其下级部门的用户
+	users, total, err = userService.SelectUserListWithDataScope(newScopeUser(datascope.DataScopeDeptAndChild), &model.SysUser{}, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, []int64{2}, userIdsOf(users))
+
+	// 仅本人数据权限：公告按创建者过滤
+	notices, err := NewNoticeService().SelectNoticeListWithDataScope(newScopeUser(datascope.DataScopeSelf), nil)
+	require.NoError(t, err)
+	assert.Empty(t, notices)
+
+	// 分配用户时只能看到有权访问的用户
+	adminRoleId := int64(1)
+	users, total, err = userService.SelectUnallocatedList(newScopeUser(datascope.DataScopeSelf), &model.SysUser{RoleID: &adminRoleId}, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, []int64{2}, userIdsOf(users))
+}
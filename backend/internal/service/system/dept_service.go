@@ -1,6 +1,7 @@
 package system
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
@@ -12,8 +13,10 @@ import (
 
 // DeptService 部门服务 对应Java后端的ISysDeptService
 type DeptService struct {
+	ctx         context.Context // 数据访问使用的context，方法中创建的其他服务使用该context
 	deptDao     *dao.DeptDao
 	roleDeptDao *dao.RoleDeptDao
+	recycleDao  *dao.RecycleDao
 }
 
 // NewDeptService 创建部门服务实例
@@ -21,13 +24,24 @@ func NewDeptService() *DeptService {
 	return &DeptService{
 		deptDao:     dao.NewDeptDao(),
 		roleDeptDao: dao.NewRoleDeptDao(),
+		recycleDao:  dao.NewRecycleDao(),
+	}
+}
+
+// WithContext 返回使用指定context的服务实例，数据访问携带请求信息和租户
+func (s *DeptService) WithContext(ctx context.Context) *DeptService {
+	return &DeptService{
+		ctx:         ctx,
+		deptDao:     s.deptDao.WithContext(ctx),
+		roleDeptDao: s.roleDeptDao.WithContext(ctx),
+		recycleDao:  s.recycleDao.WithContext(ctx),
 	}
 }
 
 // SelectDeptList 查询部门管理数据 对应Java后端的selectDeptList
 func (s *DeptService) SelectDeptList(dept *model.SysDept) ([]model.SysDept, error) {
 	fmt.Printf("DeptService.SelectDeptList: 查询部门列表\n")
-	return s.deptDao.SelectDeptList(dept)
+	return s.deptDao.SelectDeptList(dept, nil)
 }
 
 // SelectDeptTreeList 查询部门树结构信息 对应Java后端的selectDeptTreeList
@@ -98,7 +112,7 @@ func (s *DeptService) SelectDeptListByRoleId(roleId int64) ([]int64, error) {
 	fmt.Printf("DeptService.SelectDeptListByRoleId: 查询角色部门, RoleID=%d\n", roleId)
 
 	// 查询角色的deptCheckStrictly字段 对应Java后端的SysRole role = roleMapper.selectRoleById(roleId)
-	roleDao := dao.NewRoleDao()
+	roleDao := dao.NewRoleDao().WithContext(s.ctx)
 	role, err := roleDao.SelectRoleById(roleId)
 	if err != nil {
 		return nil, fmt.Errorf("查询角色信息失败: %v", err)
@@ -215,6 +229,11 @@ func (s *DeptService) UpdateDept(dept *model.SysDept) error {
 		return err
 	}
 
+	// 先校验版本号，避免已被其他用户修改时仍更新了子部门的祖级列表
+	if oldDept != nil && dept.Version > 0 && oldDept.Version != dept.Version {
+		return ErrVersionConflict
+	}
+
 	if newParentDept != nil && oldDept != nil {
 		newAncestors := fmt.Sprintf("%s,%d", newParentDept.Ancestors, dept.ParentID)
 		oldAncestors := oldDept.Ancestors
@@ -282,10 +301,22 @@ func (s *DeptService) updateParentDeptStatusNormal(dept *model.SysDept) {
 	}
 }
 
-// DeleteDeptById 删除部门管理信息 对应Java后端的deleteDeptById
-func (s *DeptService) DeleteDeptById(deptId int64) error {
+// DeleteDeptById 删除部门管理信息 对应Java后端的deleteDeptById 删除后写入回收站
+func (s *DeptService) DeleteDeptById(currentUser *model.SysUser, deptId int64) error {
 	fmt.Printf("DeptService.DeleteDeptById: 删除部门, DeptID=%d\n", deptId)
-	return s.deptDao.DeleteDeptById(deptId)
+
+	dept, err := s.deptDao.SelectDeptById(deptId)
+	if err != nil {
+		return err
+	}
+	if dept == nil {
+		return fmt.Errorf("部门不存在")
+	}
+
+	if err := s.deptDao.DeleteDeptById(deptId); err != nil {
+		return err
+	}
+	return recordRecycle(s.recycleDao, model.RecycleTypeDept, deptId, dept.DeptName, nil, currentUser.UserName)
 }
 
 // CheckDeptDataScope 校验部门数据权限 对应Java后端的checkDeptDataScope
@@ -327,28 +358,10 @@ func (s *DeptService) CheckDeptDataScope(currentUser *model.SysUser, deptId int6
 func (s *DeptService) SelectDeptListWithDataScope(currentUser *model.SysUser, queryDept *model.SysDept) ([]model.SysDept, error) {
 	fmt.Printf("DeptService.SelectDeptListWithDataScope: 查询部门列表（数据权限）\n")
 
-	// 创建查询参数
-	params := make(map[string]interface{})
-
-	// 应用数据权限 对应Java后端的@DataScope(deptAlias = "d")
-	err := datascope.ApplyDataScope(currentUser, "d", "", "system:dept:list", params)
-	if err != nil {
-		return nil, fmt.Errorf("应用数据权限失败: %v", err)
-	}
-
-	// 将数据权限SQL设置到查询部门对象中
 	if queryDept == nil {
 		queryDept = &model.SysDept{}
 	}
-	if queryDept.Params == nil {
-		queryDept.Params = make(map[string]interface{})
-	}
 
-	// 复制数据权限参数
-	for key, value := range params {
-		queryDept.Params[key] = value
-	}
-
-	// 调用DAO层查询
-	return s.deptDao.SelectDeptList(queryDept)
+	// 应用数据权限 对应Java后端的@DataScope(deptAlias = "d")
+	return s.deptDao.SelectDeptList(queryDept, datascope.Build(currentUser, "system:dept:list"))
 }
@@ -1,6 +1,7 @@
 package system
 
 import (
+	"context"
 	"fmt"
 	"time"
 	"wosm/internal/repository/dao"
@@ -10,6 +11,7 @@ import (
 
 // DictDataService 字典数据服务 对应Java后端的ISysDictDataService
 type DictDataService struct {
+	ctx         context.Context // 数据访问使用的context，方法中创建的其他服务使用该context
 	dictDataDao *dao.DictDataDao
 }
 
@@ -20,6 +22,14 @@ func NewDictDataService() *DictDataService {
 	}
 }
 
+// WithContext 返回使用指定context的服务实例，数据访问携带请求信息和租户
+func (s *DictDataService) WithContext(ctx context.Context) *DictDataService {
+	return &DictDataService{
+		ctx:         ctx,
+		dictDataDao: s.dictDataDao.WithContext(ctx),
+	}
+}
+
 // SelectDictDataList 根据条件分页查询字典数据 对应Java后端的selectDictDataList
 func (s *DictDataService) SelectDictDataList(dictData *model.SysDictData) ([]model.SysDictData, error) {
 	fmt.Printf("DictDataService.SelectDictDataList: 查询字典数据列表\n")
@@ -37,7 +47,7 @@ func (s *DictDataService) SelectDictDataByType(dictType string) ([]model.SysDict
 	fmt.Printf("DictDataService.SelectDictDataByType: 查询字典数据, DictType=%s\n", dictType)
 
 	// 先从缓存获取
-	dictDatas := dict.GetDictCache(dictType)
+	dictDatas := dict.GetDictCache(s.ctx, dictType)
 	if dictDatas != nil {
 		return dictDatas, nil
 	}
@@ -49,7 +59,7 @@ func (s *DictDataService) SelectDictDataByType(dictType string) ([]model.SysDict
 	}
 
 	// 设置缓存
-	dict.SetDictCache(dictType, dictDatas)
+	dict.SetDictCache(s.ctx, dictType, dictDatas)
 
 	return dictDatas, nil
 }
@@ -76,6 +86,9 @@ func (s *DictDataService) DeleteDictDataByIds(dictCodes []int64) error {
 		if err != nil {
 			return err
 		}
+		if dictData != nil && isOtherTenantRow(s.ctx, dictData.TenantID) {
+			return fmt.Errorf("平台字典数据【%s】不能删除", dictData.DictLabel)
+		}
 
 		// 删除字典数据
 		err = s.dictDataDao.DeleteDictDataById(dictCode)
@@ -83,12 +96,8 @@ func (s *DictDataService) DeleteDictDataByIds(dictCodes []int64) error {
 			return err
 		}
 
-		// 更新缓存
-		dictDatas, err := s.dictDataDao.SelectDictDataByType(dictData.DictType)
-		if err != nil {
-			return err
-		}
-		dict.SetDictCache(dictData.DictType, dictDatas)
+		// 删除缓存，平台租户的字典数据变化时同时删除各租户的缓存
+		dict.RemoveDictCache(s.ctx, dictData.DictType)
 	}
 
 	return nil
@@ -109,7 +118,7 @@ func (s *DictDataService) InsertDictData(dictData *model.SysDictData) error {
 	}
 
 	// 删除缓存
-	dict.RemoveDictCache(dictData.DictType)
+	dict.RemoveDictCache(s.ctx, dictData.DictType)
 
 	return nil
 }
@@ -118,18 +127,26 @@ func (s *DictDataService) InsertDictData(dictData *model.SysDictData) error {
 func (s *DictDataService) UpdateDictData(dictData *model.SysDictData) error {
 	fmt.Printf("DictDataService.UpdateDictData: 修改字典数据, DictCode=%d\n", dictData.DictCode)
 
+	existing, err := s.dictDataDao.SelectDictDataById(dictData.DictCode)
+	if err != nil {
+		return err
+	}
+	if existing != nil && isOtherTenantRow(s.ctx, existing.TenantID) {
+		return fmt.Errorf("平台字典数据不能修改，请新增该类型的字典数据覆盖")
+	}
+
 	// 设置更新时间
 	now := time.Now()
 	dictData.UpdateTime = &now
 
 	// 修改字典数据
-	err := s.dictDataDao.UpdateDictData(dictData)
+	err = s.dictDataDao.UpdateDictData(dictData)
 	if err != nil {
 		return err
 	}
 
 	// 删除缓存
-	dict.RemoveDictCache(dictData.DictType)
+	dict.RemoveDictCache(s.ctx, dictData.DictType)
 
 	return nil
 }
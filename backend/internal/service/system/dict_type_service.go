@@ -1,6 +1,7 @@
 package system
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"time"
@@ -11,6 +12,7 @@ import (
 
 // DictTypeService 字典类型服务 对应Java后端的ISysDictTypeService
 type DictTypeService struct {
+	ctx         context.Context // 数据访问使用的context，方法中创建的其他服务使用该context
 	dictTypeDao *dao.DictTypeDao
 	dictDataDao *dao.DictDataDao
 }
@@ -23,6 +25,15 @@ func NewDictTypeService() *DictTypeService {
 	}
 }
 
+// WithContext 返回使用指定context的服务实例，数据访问携带请求信息和租户
+func (s *DictTypeService) WithContext(ctx context.Context) *DictTypeService {
+	return &DictTypeService{
+		ctx:         ctx,
+		dictTypeDao: s.dictTypeDao.WithContext(ctx),
+		dictDataDao: s.dictDataDao.WithContext(ctx),
+	}
+}
+
 // SelectDictTypeList 根据条件分页查询字典类型 对应Java后端的selectDictTypeList
 func (s *DictTypeService) SelectDictTypeList(dictType *model.SysDictType) ([]model.SysDictType, error) {
 	fmt.Printf("DictTypeService.SelectDictTypeList: 查询字典类型列表\n")
@@ -84,6 +95,10 @@ func (s *DictTypeService) CheckDictTypeUnique(dictType *model.SysDictType) bool
 func (s *DictTypeService) InsertDictType(dictType *model.SysDictType) error {
 	fmt.Printf("DictTypeService.InsertDictType: 新增字典类型, DictType=%s\n", dictType.DictType)
 
+	if err := checkPlatformTenant(s.ctx); err != nil {
+		return err
+	}
+
 	// 验证字典类型格式 对应Java后端的@Pattern验证
 	if err := s.ValidateDictType(dictType.DictType); err != nil {
 		return err
@@ -100,6 +115,10 @@ func (s *DictTypeService) InsertDictType(dictType *model.SysDictType) error {
 func (s *DictTypeService) UpdateDictType(dictType *model.SysDictType) error {
 	fmt.Printf("DictTypeService.UpdateDictType: 修改字典类型, DictID=%d\n", dictType.DictID)
 
+	if err := checkPlatformTenant(s.ctx); err != nil {
+		return err
+	}
+
 	// 验证字典类型格式 对应Java后端的@Pattern验证
 	if err := s.ValidateDictType(dictType.DictType); err != nil {
 		return err
@@ -129,11 +148,11 @@ func (s *DictTypeService) UpdateDictType(dictType *model.SysDictType) error {
 		}
 
 		// 删除旧的缓存
-		dict.RemoveDictCache(oldDictType.DictType)
+		dict.RemoveDictCache(s.ctx, oldDictType.DictType)
 	}
 
 	// 删除缓存
-	dict.RemoveDictCache(dictType.DictType)
+	dict.RemoveDictCache(s.ctx, dictType.DictType)
 
 	return nil
 }
@@ -142,6 +161,10 @@ func (s *DictTypeService) UpdateDictType(dictType *model.SysDictType) error {
 func (s *DictTypeService) DeleteDictTypeByIds(dictIds []int64) error {
 	fmt.Printf("DictTypeService.DeleteDictTypeByIds: 批量删除字典类型, DictIDs=%v\n", dictIds)
 
+	if err := checkPlatformTenant(s.ctx); err != nil {
+		return err
+	}
+
 	for _, dictId := range dictIds {
 		// 查询字典类型信息
 		dictType, err := s.dictTypeDao.SelectDictTypeById(dictId)
@@ -170,7 +193,7 @@ func (s *DictTypeService) DeleteDictTypeByIds(dictIds []int64) error {
 		}
 
 		// 删除缓存
-		dict.RemoveDictCache(dictType.DictType)
+		dict.RemoveDictCache(s.ctx, dictType.DictType)
 	}
 
 	return nil
@@ -193,7 +216,7 @@ func (s *DictTypeService) ResetDictCache() error {
 	for _, dictType := range dictTypes {
 		dictDatas, err := s.dictDataDao.SelectDictDataByType(dictType.DictType)
 		if err == nil {
-			dict.SetDictCache(dictType.DictType, dictDatas)
+			dict.SetDictCache(s.ctx, dictType.DictType, dictDatas)
 		}
 	}
 
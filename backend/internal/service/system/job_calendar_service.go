@@ -0,0 +1,314 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+	"wosm/internal/repository/dao"
+	"wosm/internal/repository/model"
+	cronUtils "wosm/pkg/cron"
+)
+
+// jobCalendarCache 已编译的排除日历缓存，日历修改或删除后失效
+// 值为nil表示日历不存在或已停用，不排除任何时间
+var jobCalendarCache = struct {
+	sync.RWMutex
+	items map[int64]*cronUtils.ExclusionCalendar
+}{items: make(map[int64]*cronUtils.ExclusionCalendar)}
+
+// invalidateJobCalendar 使日历缓存失效
+func invalidateJobCalendar(calendarIds ...int64) {
+	jobCalendarCache.Lock()
+	defer jobCalendarCache.Unlock()
+	for _, id := range calendarIds {
+		delete(jobCalendarCache.items, id)
+	}
+}
+
+// loadJobCalendar 从缓存或数据库加载已编译的日历
+func loadJobCalendar(calendarDao *dao.JobCalendarDao, calendarId int64) (*cronUtils.ExclusionCalendar, error) {
+	jobCalendarCache.RLock()
+	cached, ok := jobCalendarCache.items[calendarId]
+	jobCalendarCache.RUnlock()
+	if ok {
+		return cached, nil
+	}
+
+	calendar, err := calendarDao.SelectCalendarById(calendarId)
+	if err != nil {
+		return nil, err
+	}
+	var compiled *cronUtils.ExclusionCalendar
+	if calendar != nil && calendar.IsNormal() {
+		rules, err := calendarDao.SelectRulesByCalendarId(calendarId)
+		if err != nil {
+			return nil, err
+		}
+		if compiled, err = BuildExclusionCalendar(calendar, rules); err != nil {
+			return nil, err
+		}
+	}
+
+	jobCalendarCache.Lock()
+	jobCalendarCache.items[calendarId] = compiled
+	jobCalendarCache.Unlock()
+	return compiled, nil
+}
+
+// BuildExclusionCalendar 将日历及其规则编译为调度器使用的排除日历
+func BuildExclusionCalendar(calendar *model.SysJobCalendar, rules []model.SysJobCalendarRule) (*cronUtils.ExclusionCalendar, error) {
+	loc, err := calendar.Location()
+	if err != nil {
+		return nil, err
+	}
+
+	compiled := cronUtils.NewExclusionCalendar(loc)
+	for _, rule := range rules {
+		switch rule.RuleType {
+		case model.JobCalendarRuleDate:
+			if rule.StartTime != nil {
+				// 日期按日历时区的年月日解释，与保存时数据库所在时区无关
+				start := *rule.StartTime
+				compiled.ExcludeDate(time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc))
+			}
+		case model.JobCalendarRuleWeek:
+			weekdays, err := rule.WeekdayList()
+			if err != nil {
+				return nil, err
+			}
+			for _, weekday := range weekdays {
+				compiled.ExcludeWeekday(weekday)
+			}
+		case model.JobCalendarRuleRange:
+			if rule.StartTime != nil && rule.EndTime != nil {
+				compiled.ExcludeRange(inLocation(*rule.StartTime, loc), inLocation(*rule.EndTime, loc))
+			}
+		}
+	}
+	return compiled, nil
+}
+
+// inLocation 将不带时区语义的数据库时间按日历时区解释
+func inLocation(t time.Time, loc *time.Location) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, loc)
+}
+
+// jobCalendarRef 按ID引用的排除日历，每次判断时从缓存读取，日历修改后无需重新注册任务
+type jobCalendarRef struct {
+	calendarId  int64
+	calendarDao *dao.JobCalendarDao
+}
+
+// IsTimeIncluded 判断时间点是否可以触发任务，日历加载失败时不排除
+func (r jobCalendarRef) IsTimeIncluded(t time.Time) bool {
+	calendar, err := loadJobCalendar(r.calendarDao, r.calendarId)
+	if err != nil || calendar == nil {
+		return true
+	}
+	return calendar.IsTimeIncluded(t)
+}
+
+// NextIncludedTime 返回不早于t的第一个可以触发任务的时间点
+func (r jobCalendarRef) NextIncludedTime(t time.Time) time.Time {
+	calendar, err := loadJobCalendar(r.calendarDao, r.calendarId)
+	if err != nil || calendar == nil {
+		return t
+	}
+	return calendar.NextIncludedTime(t)
+}
+
+// JobCalendarService 定时任务排除日历服务
+type JobCalendarService struct {
+	calendarDao *dao.JobCalendarDao
+}
+
+// NewJobCalendarService 创建定时任务排除日历服务实例
+func NewJobCalendarService() *JobCalendarService {
+	return &JobCalendarService{
+		calendarDao: dao.NewJobCalendarDao(),
+	}
+}
+
+// WithContext 返回使用指定context的服务实例，数据访问携带请求信息和租户
+func (s *JobCalendarService) WithContext(ctx context.Context) *JobCalendarService {
+	return &JobCalendarService{
+		calendarDao: s.calendarDao.WithContext(ctx),
+	}
+}
+
+// SelectCalendarList 查询日历列表
+func (s *JobCalendarService) SelectCalendarList(calendar *model.SysJobCalendar) ([]model.SysJobCalendar, error) {
+	return s.calendarDao.SelectCalendarList(calendar)
+}
+
+// SelectCalendarById 查询日历详情（包含排除规则）
+func (s *JobCalendarService) SelectCalendarById(calendarId int64) (*model.SysJobCalendar, error) {
+	calendar, err := s.calendarDao.SelectCalendarById(calendarId)
+	if err != nil || calendar == nil {
+		return calendar, err
+	}
+
+	rules, err := s.calendarDao.SelectRulesByCalendarId(calendarId)
+	if err != nil {
+		return nil, err
+	}
+	calendar.Rules = rules
+	return calendar, nil
+}
+
+// InsertCalendar 新增日历
+func (s *JobCalendarService) InsertCalendar(calendar *model.SysJobCalendar) error {
+	if err := model.ValidateJobCalendar(calendar); err != nil {
+		return err
+	}
+	isUnique, err := s.calendarDao.CheckCalendarNameUnique(calendar.CalendarName, 0)
+	if err != nil {
+		return err
+	}
+	if !isUnique {
+		return fmt.Errorf("日历名称已存在: %s", calendar.CalendarName)
+	}
+
+	if calendar.Status == "" {
+		calendar.Status = model.JobCalendarStatusNormal
+	}
+	now := time.Now()
+	calendar.CreateTime = &now
+
+	if err := s.calendarDao.InsertCalendar(calendar); err != nil {
+		return err
+	}
+	if calendar.Rules != nil {
+		if err := s.saveRules(calendar.CalendarID, calendar.Rules); err != nil {
+			return err
+		}
+	}
+
+	invalidateJobCalendar(calendar.CalendarID)
+	return nil
+}
+
+// UpdateCalendar 修改日历，规则为nil时保留原有规则
+func (s *JobCalendarService) UpdateCalendar(calendar *model.SysJobCalendar) error {
+	if err := model.ValidateJobCalendar(calendar); err != nil {
+		return err
+	}
+	old, err := s.calendarDao.SelectCalendarById(calendar.CalendarID)
+	if err != nil {
+		return err
+	}
+	if old == nil {
+		return fmt.Errorf("日历不存在: %d", calendar.CalendarID)
+	}
+	isUnique, err := s.calendarDao.CheckCalendarNameUnique(calendar.CalendarName, calendar.CalendarID)
+	if err != nil {
+		return err
+	}
+	if !isUnique {
+		return fmt.Errorf("日历名称已存在: %s", calendar.CalendarName)
+	}
+
+	if calendar.Status == "" {
+		calendar.Status = old.Status
+	}
+	now := time.Now()
+	calendar.UpdateTime = &now
+
+	if err := s.calendarDao.UpdateCalendar(calendar); err != nil {
+		return err
+	}
+	if calendar.Rules != nil {
+		if err := s.saveRules(calendar.CalendarID, calendar.Rules); err != nil {
+			return err
+		}
+	}
+
+	invalidateJobCalendar(calendar.CalendarID)
+	return nil
+}
+
+// DeleteCalendarByIds 批量删除日历，被任务引用的日历不允许删除
+func (s *JobCalendarService) DeleteCalendarByIds(calendarIds []int64) error {
+	count, err := s.calendarDao.CountJobsByCalendarIds(calendarIds)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("日历已被%d个定时任务引用，不能删除", count)
+	}
+
+	if err := s.calendarDao.DeleteCalendarByIds(calendarIds); err != nil {
+		return err
+	}
+	invalidateJobCalendar(calendarIds...)
+	return nil
+}
+
+// ImportICal 从iCal文件导入排除规则，全天事件导入为排除日期，其它事件导入为排除时间段
+// 返回导入的规则数量
+func (s *JobCalendarService) ImportICal(calendarId int64, r io.Reader) (int, error) {
+	calendar, err := s.calendarDao.SelectCalendarById(calendarId)
+	if err != nil {
+		return 0, err
+	}
+	if calendar == nil {
+		return 0, fmt.Errorf("日历不存在: %d", calendarId)
+	}
+	loc, err := calendar.Location()
+	if err != nil {
+		return 0, err
+	}
+
+	events, err := cronUtils.ParseICal(r, loc)
+	if err != nil {
+		return 0, err
+	}
+
+	var rules []model.SysJobCalendarRule
+	for _, event := range events {
+		summary := truncateJobText(event.Summary, 200)
+		if event.AllDay {
+			// 多天的全天事件按天展开
+			for day := event.Start; day.Before(event.End); day = day.AddDate(0, 0, 1) {
+				date := day
+				rules = append(rules, model.SysJobCalendarRule{
+					CalendarID: calendarId,
+					RuleType:   model.JobCalendarRuleDate,
+					StartTime:  &date,
+					Summary:    summary,
+				})
+			}
+			continue
+		}
+		if !event.End.After(event.Start) {
+			continue
+		}
+		start, end := event.Start.In(loc), event.End.In(loc)
+		rules = append(rules, model.SysJobCalendarRule{
+			CalendarID: calendarId,
+			RuleType:   model.JobCalendarRuleRange,
+			StartTime:  &start,
+			EndTime:    &end,
+			Summary:    summary,
+		})
+	}
+
+	if err := s.calendarDao.InsertRules(rules); err != nil {
+		return 0, err
+	}
+	invalidateJobCalendar(calendarId)
+	return len(rules), nil
+}
+
+// saveRules 校验并保存日历规则
+func (s *JobCalendarService) saveRules(calendarId int64, rules []model.SysJobCalendarRule) error {
+	saved := make([]model.SysJobCalendarRule, 0, len(rules))
+	for _, rule := range rules {
+		rule.RuleID = 0
+		rule.CalendarID = calendarId
+		saved = append(saved, rule)
+	}
+	return s.calendarDao.ReplaceRules(calendarId, saved)
+}
@@ -0,0 +1,270 @@
+package system
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+	"wosm/internal/config"
+	"wosm/internal/repository/dao"
+	"wosm/internal/repository/model"
+
+	"gorm.io/gorm"
+)
+
+// 响应内容记录到执行日志时的最大长度
+const jobResponseLogLimit = 64 * 1024
+
+// JobExecutor 任务执行器，返回的结果摘要会记录到 sys_job_log.job_message
+type JobExecutor interface {
+	Execute(ctx context.Context, job *model.SysJob, params map[string]string, jobLogger *JobLogger) (string, error)
+}
+
+// jobTemplateData HTTP任务模板可引用的数据
+type jobTemplateData struct {
+	Job    *model.SysJob
+	Params map[string]string
+	Now    time.Time
+}
+
+// HttpJobExecutor HTTP请求任务执行器
+type HttpJobExecutor struct {
+	client       *http.Client
+	allowedHosts []string
+}
+
+// jobMaxRedirects HTTP任务最多跟随的重定向次数，与http.Client的默认值一致
+const jobMaxRedirects = 10
+
+// NewHttpJobExecutor 创建HTTP请求任务执行器，allowedHosts 为空时不限制访问的主机
+func NewHttpJobExecutor(allowedHosts []string) *HttpJobExecutor {
+	executor := &HttpJobExecutor{allowedHosts: allowedHosts}
+	executor.client = &http.Client{CheckRedirect: executor.checkRedirect}
+	return executor
+}
+
+// checkRedirect 每次重定向都检查目标主机，允许的主机不能通过重定向访问列表外的地址
+func (e *HttpJobExecutor) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= jobMaxRedirects {
+		return fmt.Errorf("重定向次数超过%d次", jobMaxRedirects)
+	}
+	return e.checkHost(req.URL.String())
+}
+
+// Validate 验证HTTP任务配置
+func (e *HttpJobExecutor) Validate(jobConfig string) (*model.HttpJobConfig, error) {
+	cfg, err := model.ParseHttpJobConfig(jobConfig)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if _, err := template.New("body").Parse(cfg.BodyTemplate); err != nil {
+		return nil, fmt.Errorf("请求体模板语法错误: %v", err)
+	}
+	if _, err := template.New("url").Parse(cfg.URL); err != nil {
+		return nil, fmt.Errorf("请求地址模板语法错误: %v", err)
+	}
+	return cfg, nil
+}
+
+// Execute 发送HTTP请求，状态码不符合预期时返回错误
+func (e *HttpJobExecutor) Execute(ctx context.Context, job *model.SysJob, params map[string]string, jobLogger *JobLogger) (string, error) {
+	cfg, err := e.Validate(job.JobConfig)
+	if err != nil {
+		return "", err
+	}
+
+	data := jobTemplateData{Job: job, Params: params, Now: time.Now()}
+	reqURL, err := renderJobTemplate("url", cfg.URL, data)
+	if err != nil {
+		return "", err
+	}
+	if err := e.checkHost(reqURL); err != nil {
+		return "", err
+	}
+	body, err := renderJobTemplate("body", cfg.BodyTemplate, data)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(cfg.Timeout)*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, cfg.Method, reqURL, strings.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("创建HTTP请求失败: %v", err)
+	}
+	for key, value := range cfg.Headers {
+		req.Header.Set(key, value)
+	}
+	if body != "" && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	jobLogger.Info("发送HTTP请求", "method", cfg.Method, "url", reqURL)
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("HTTP请求失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, jobResponseLogLimit))
+	if err != nil {
+		return "", fmt.Errorf("读取HTTP响应失败: %v", err)
+	}
+	jobLogger.Info("收到HTTP响应", "status", resp.StatusCode, "body", string(respBody))
+
+	result := fmt.Sprintf("HTTP %d %s", resp.StatusCode, truncateJobText(strings.TrimSpace(string(respBody)), 200))
+	if cfg.ExpectedStatus != 0 && resp.StatusCode != cfg.ExpectedStatus {
+		return result, fmt.Errorf("HTTP响应状态码为%d，期望%d", resp.StatusCode, cfg.ExpectedStatus)
+	}
+	if cfg.ExpectedStatus == 0 && (resp.StatusCode < 200 || resp.StatusCode > 299) {
+		return result, fmt.Errorf("HTTP响应状态码为%d", resp.StatusCode)
+	}
+	return result, nil
+}
+
+// checkHost 检查请求地址的主机是否在允许列表内
+func (e *HttpJobExecutor) checkHost(reqURL string) error {
+	u, err := url.Parse(reqURL)
+	if err != nil {
+		return fmt.Errorf("请求地址格式错误: %v", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("请求地址只支持http(s)协议")
+	}
+	if len(e.allowedHosts) == 0 {
+		return nil
+	}
+	for _, host := range e.allowedHosts {
+		if strings.EqualFold(host, u.Host) || strings.EqualFold(host, u.Hostname()) {
+			return nil
+		}
+	}
+	return fmt.Errorf("请求地址的主机不在允许列表内: %s", u.Host)
+}
+
+// renderJobTemplate 渲染任务模板
+func renderJobTemplate(name, text string, data jobTemplateData) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+	tmpl, err := template.New(name).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("模板语法错误: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("模板渲染失败: %v", err)
+	}
+	return buf.String(), nil
+}
+
+// SqlJobExecutor SQL语句或存储过程任务执行器
+type SqlJobExecutor struct {
+	db        *gorm.DB
+	whitelist []string
+}
+
+// NewSqlJobExecutor 创建SQL任务执行器
+func NewSqlJobExecutor(db *gorm.DB, whitelist []string) *SqlJobExecutor {
+	return &SqlJobExecutor{
+		db:        db,
+		whitelist: whitelist,
+	}
+}
+
+// Validate 验证SQL任务配置
+func (e *SqlJobExecutor) Validate(jobConfig string) (*model.SqlJobConfig, error) {
+	cfg, err := model.ParseSqlJobConfig(jobConfig)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(e.whitelist); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Execute 执行白名单内的SQL语句或存储过程，返回影响或查询到的行数
+func (e *SqlJobExecutor) Execute(ctx context.Context, job *model.SysJob, params map[string]string, jobLogger *JobLogger) (string, error) {
+	cfg, err := e.Validate(job.JobConfig)
+	if err != nil {
+		return "", err
+	}
+	// 执行白名单中的原文，提交的文本只用于匹配
+	approved, _ := cfg.ApprovedText(e.whitelist)
+	if cfg.Procedure != "" {
+		cfg.Procedure = approved
+	} else {
+		cfg.Statement = approved
+	}
+	if e.db == nil {
+		return "", fmt.Errorf("数据库未初始化")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(cfg.Timeout)*time.Second)
+	defer cancel()
+	// 定时任务是平台级的处理，白名单内的SQL不按租户限定
+	db := dao.WithoutTenant(e.db.WithContext(ctx))
+
+	// 查询语句统计返回的行数
+	if cfg.Statement != "" && cfg.IsQuery() {
+		jobLogger.Info("执行SQL查询", "statement", cfg.Statement, "params", cfg.Params)
+		rows, err := db.Raw(cfg.Statement, cfg.Params...).Rows()
+		if err != nil {
+			return "", fmt.Errorf("SQL执行失败: %v", err)
+		}
+		defer rows.Close()
+
+		var count int64
+		for rows.Next() {
+			count++
+		}
+		if err := rows.Err(); err != nil {
+			return "", fmt.Errorf("SQL执行失败: %v", err)
+		}
+		jobLogger.Info("SQL查询完成", "rows", count)
+		return fmt.Sprintf("查询行数：%d", count), nil
+	}
+
+	statement := cfg.Statement
+	if cfg.Procedure != "" {
+		statement = e.procedureCall(cfg.Procedure, len(cfg.Params))
+	}
+
+	jobLogger.Info("执行SQL", "statement", statement, "params", cfg.Params)
+	result := db.Exec(statement, cfg.Params...)
+	if result.Error != nil {
+		return "", fmt.Errorf("SQL执行失败: %v", result.Error)
+	}
+	jobLogger.Info("SQL执行完成", "rowsAffected", result.RowsAffected)
+	return fmt.Sprintf("影响行数：%d", result.RowsAffected), nil
+}
+
+// procedureCall 根据数据库方言生成存储过程调用语句
+func (e *SqlJobExecutor) procedureCall(procedure string, paramCount int) string {
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", paramCount), ", ")
+	if e.db.Dialector != nil && e.db.Dialector.Name() == "sqlserver" {
+		if placeholders == "" {
+			return "EXEC " + procedure
+		}
+		return "EXEC " + procedure + " " + placeholders
+	}
+	return "CALL " + procedure + "(" + placeholders + ")"
+}
+
+// jobTypeSettings 读取定时任务配置，配置未加载时返回空配置
+func jobTypeSettings() config.JobConfig {
+	if config.AppConfig == nil {
+		return config.JobConfig{}
+	}
+	return config.AppConfig.Job
+}
@@ -0,0 +1,187 @@
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"wosm/internal/repository/model"
+	"wosm/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newHttpJob 创建HTTP任务
+func newHttpJob(t *testing.T, cfg model.HttpJobConfig) *model.SysJob {
+	data, err := json.Marshal(cfg)
+	require.NoError(t, err)
+	return &model.SysJob{JobID: 1, JobName: "webhook", JobType: model.JobTypeHttp, JobConfig: string(data)}
+}
+
+func TestHttpJobExecutorRendersBodyAndCapturesResponse(t *testing.T) {
+	var gotMethod, gotBody, gotHeader string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		gotHeader = r.Header.Get("X-Token")
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	job := newHttpJob(t, model.HttpJobConfig{
+		Method:         "post",
+		URL:            server.URL + "/hook",
+		Headers:        map[string]string{"X-Token": "abc"},
+		BodyTemplate:   `{"job":"{{.Job.JobName}}","upstream":"{{.Params.upstreamStatus}}"}`,
+		ExpectedStatus: http.StatusAccepted,
+	})
+	jobLogger := NewJobLogger(0)
+
+	result, err := NewHttpJobExecutor(nil).Execute(context.Background(), job, map[string]string{"upstreamStatus": "0"}, jobLogger)
+	require.NoError(t, err)
+	assert.Equal(t, http.MethodPost, gotMethod)
+	assert.Equal(t, "abc", gotHeader)
+	assert.Equal(t, `{"job":"webhook","upstream":"0"}`, gotBody)
+	assert.Equal(t, `HTTP 202 {"ok":true}`, result)
+	assert.Len(t, jobLogger.Lines(), 2)
+}
+
+func TestHttpJobExecutorStatusMismatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		expected int
+	}{
+		{"默认要求2xx", 0},
+		{"指定状态码", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := newHttpJob(t, model.HttpJobConfig{URL: server.URL, ExpectedStatus: tt.expected})
+			result, err := NewHttpJobExecutor(nil).Execute(context.Background(), job, nil, NewJobLogger(0))
+			assert.Error(t, err)
+			assert.True(t, strings.HasPrefix(result, "HTTP 500"))
+		})
+	}
+}
+
+func TestHttpJobExecutorAllowedHosts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	job := newHttpJob(t, model.HttpJobConfig{URL: server.URL})
+
+	_, err := NewHttpJobExecutor([]string{"internal.example.com"}).Execute(context.Background(), job, nil, NewJobLogger(0))
+	assert.Error(t, err)
+
+	_, err = NewHttpJobExecutor([]string{u.Host}).Execute(context.Background(), job, nil, NewJobLogger(0))
+	assert.NoError(t, err)
+}
+
+func TestHttpJobExecutorRedirectToDisallowedHost(t *testing.T) {
+	internalHit := false
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		internalHit = true
+	}))
+	defer internal.Close()
+	internalURL, _ := url.Parse(internal.URL)
+
+	allowed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://localhost:"+internalURL.Port()+"/admin", http.StatusFound)
+	}))
+	defer allowed.Close()
+	allowedURL, _ := url.Parse(allowed.URL)
+
+	job := newHttpJob(t, model.HttpJobConfig{URL: allowed.URL})
+	_, err := NewHttpJobExecutor([]string{allowedURL.Host}).Execute(context.Background(), job, nil, NewJobLogger(0))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "不在允许列表内")
+	assert.False(t, internalHit)
+
+	// 重定向到允许的主机可以继续访问
+	_, err = NewHttpJobExecutor([]string{allowedURL.Host, "localhost"}).Execute(context.Background(), job, nil, NewJobLogger(0))
+	assert.NoError(t, err)
+	assert.True(t, internalHit)
+}
+
+func TestHttpJobExecutorValidate(t *testing.T) {
+	executor := NewHttpJobExecutor(nil)
+	tests := []struct {
+		name    string
+		config  string
+		wantErr bool
+	}{
+		{"合法配置", `{"url":"http://localhost:8080/ping"}`, false},
+		{"非法JSON", `{`, true},
+		{"缺少地址", `{"method":"GET"}`, true},
+		{"非HTTP协议", `{"url":"ftp://localhost/a"}`, true},
+		{"不支持的方法", `{"method":"TRACE","url":"http://localhost"}`, true},
+		{"模板语法错误", `{"url":"http://localhost","bodyTemplate":"{{.Job"}`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executor.Validate(tt.config)
+			assert.Equal(t, tt.wantErr, err != nil, "err=%v", err)
+		})
+	}
+}
+
+func TestSqlJobExecutorValidate(t *testing.T) {
+	executor := NewSqlJobExecutor(nil, []string{
+		"DELETE FROM sys_oper_log WHERE oper_time < DATEADD(day, -30, GETDATE())",
+		"UPDATE sys_config SET config_value = 'skin-blue' WHERE config_key = 'sys.index.skinName'",
+		"dbo.sp_refresh_report",
+	})
+	tests := []struct {
+		name    string
+		config  string
+		wantErr bool
+	}{
+		{"白名单语句忽略多余空白和末尾分号", `{"statement":"DELETE FROM  sys_oper_log\nWHERE oper_time < DATEADD(day, -30, GETDATE()) ;"}`, false},
+		{"关键字大小写不同", `{"statement":"delete from sys_oper_log where oper_time < dateadd(day, -30, getdate())"}`, true},
+		{"字符串常量大小写不同", `{"statement":"UPDATE sys_config SET config_value = 'SKIN-BLUE' WHERE config_key = 'sys.index.skinName'"}`, true},
+		{"字符串常量中的方括号", `{"statement":"UPDATE sys_config SET config_value = '[skin-blue]' WHERE config_key = 'sys.index.skinName'"}`, true},
+		{"字符串常量中的空白", `{"statement":"UPDATE sys_config SET config_value = 'skin-blue ' WHERE config_key = 'sys.index.skinName'"}`, true},
+		{"白名单存储过程", `{"procedure":"dbo.sp_refresh_report"}`, false},
+		{"存储过程名称加方括号", `{"procedure":"[dbo].[sp_refresh_report]"}`, true},
+		{"不在白名单的语句", `{"statement":"DROP TABLE sys_user"}`, true},
+		{"不在白名单的存储过程", `{"procedure":"dbo.sp_other"}`, true},
+		{"同时填写语句和存储过程", `{"statement":"SELECT 1","procedure":"dbo.sp_refresh_report"}`, true},
+		{"存储过程名称非法", `{"procedure":"dbo.sp_refresh_report; DROP TABLE x"}`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executor.Validate(tt.config)
+			assert.Equal(t, tt.wantErr, err != nil, "err=%v", err)
+		})
+	}
+}
+
+func TestSqlJobExecutorRunsApprovedText(t *testing.T) {
+	db := testutil.OpenMigratedDB(t)
+	approved := "UPDATE sys_config SET config_value = 'skin-red' WHERE config_key = 'sys.index.skinName'"
+	executor := NewSqlJobExecutor(db, []string{approved})
+	job := &model.SysJob{JobType: model.JobTypeSql,
+		JobConfig: `{"statement":"UPDATE  sys_config SET config_value = 'skin-red'\n WHERE config_key = 'sys.index.skinName';"}`}
+
+	jobLogger := NewJobLogger(0)
+	result, err := executor.Execute(context.Background(), job, nil, jobLogger)
+	require.NoError(t, err)
+	assert.Equal(t, "影响行数：1", result)
+	assert.Contains(t, strings.Join(jobLogger.Lines(), "\n"), approved)
+	var values []string
+	require.NoError(t, db.Table("sys_config").Where("config_key = ?", "sys.index.skinName").Pluck("config_value", &values).Error)
+	assert.Equal(t, []string{"skin-red"}, values)
+}
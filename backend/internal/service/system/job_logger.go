@@ -0,0 +1,110 @@
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+	"wosm/internal/constants"
+	"wosm/internal/repository/model"
+	"wosm/pkg/redis"
+)
+
+// jobLogStreamExpire 实时日志在Redis中的保留时间，任务结束后日志已持久化到数据库
+const jobLogStreamExpire = model.JobLogRunningTimeout
+
+// JobLogger 任务执行日志记录器，日志行会缓冲在内存中并实时写入Redis供前端流式读取
+type JobLogger struct {
+	jobLogId int64
+	mu       sync.Mutex
+	lines    []string
+}
+
+// NewJobLogger 创建任务执行日志记录器，jobLogId为0时只在内存中缓冲
+func NewJobLogger(jobLogId int64) *JobLogger {
+	return &JobLogger{jobLogId: jobLogId}
+}
+
+// Info 记录信息日志，fields 为键值对，例如 Info("同步完成", "count", 10)
+func (l *JobLogger) Info(msg string, fields ...interface{}) {
+	l.log(model.JobLogLevelInfo, msg, fields)
+}
+
+// Warn 记录警告日志
+func (l *JobLogger) Warn(msg string, fields ...interface{}) {
+	l.log(model.JobLogLevelWarn, msg, fields)
+}
+
+// Error 记录错误日志
+func (l *JobLogger) Error(msg string, fields ...interface{}) {
+	l.log(model.JobLogLevelError, msg, fields)
+}
+
+// Lines 获取已记录的全部日志行（JSON格式）
+func (l *JobLogger) Lines() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.lines...)
+}
+
+// log 生成结构化日志行并写入缓冲区和Redis
+func (l *JobLogger) log(level, msg string, fields []interface{}) {
+	line := model.JobLogLine{
+		Time:    time.Now().Format("2006-01-02 15:04:05.000"),
+		Level:   level,
+		Message: msg,
+	}
+	if len(fields) > 0 {
+		line.Fields = make(map[string]interface{}, (len(fields)+1)/2)
+		for i := 0; i < len(fields); i += 2 {
+			key := fmt.Sprint(fields[i])
+			if i+1 < len(fields) {
+				line.Fields[key] = fields[i+1]
+			} else {
+				line.Fields[key] = nil
+			}
+		}
+	}
+
+	data, err := json.Marshal(line)
+	if err != nil {
+		data, _ = json.Marshal(model.JobLogLine{Time: line.Time, Level: level, Message: msg})
+	}
+
+	l.mu.Lock()
+	l.lines = append(l.lines, string(data))
+	l.mu.Unlock()
+
+	l.push(string(data))
+}
+
+// push 将日志行追加到Redis列表
+func (l *JobLogger) push(line string) {
+	rdb := redis.GetRedis()
+	if rdb == nil || l.jobLogId == 0 {
+		return
+	}
+
+	key := constants.JOB_LOG_STREAM_KEY + strconv.FormatInt(l.jobLogId, 10)
+	ctx := context.Background()
+	if err := rdb.RPush(ctx, key, line).Err(); err != nil {
+		fmt.Printf("JobLogger.push: 写入实时日志失败, JobLogID=%d, 错误=%v\n", l.jobLogId, err)
+		return
+	}
+	rdb.Expire(ctx, key, jobLogStreamExpire)
+}
+
+// finish 标记实时日志结束，status 为任务最终执行状态
+func (l *JobLogger) finish(status string) {
+	rdb := redis.GetRedis()
+	if rdb == nil || l.jobLogId == 0 {
+		return
+	}
+
+	key := constants.JOB_LOG_STREAM_KEY + strconv.FormatInt(l.jobLogId, 10) + constants.JOB_LOG_STREAM_END_SUFFIX
+	if err := rdb.Set(context.Background(), key, status, jobLogStreamExpire).Err(); err != nil {
+		fmt.Printf("JobLogger.finish: 标记实时日志结束失败, JobLogID=%d, 错误=%v\n", l.jobLogId, err)
+	}
+}
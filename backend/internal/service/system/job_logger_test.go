@@ -0,0 +1,90 @@
+package system
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+	"wosm/internal/repository/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobLoggerLines(t *testing.T) {
+	// jobLogId为0时只在内存中缓冲，不写入Redis
+	logger := NewJobLogger(0)
+	logger.Info("开始同步")
+	logger.Warn("跳过记录", "id", 3)
+	logger.Error("同步失败", "reason")
+
+	lines := logger.Lines()
+	require.Len(t, lines, 3)
+
+	expected := []model.JobLogLine{
+		{Level: model.JobLogLevelInfo, Message: "开始同步"},
+		{Level: model.JobLogLevelWarn, Message: "跳过记录", Fields: map[string]interface{}{"id": float64(3)}},
+		{Level: model.JobLogLevelError, Message: "同步失败", Fields: map[string]interface{}{"reason": nil}},
+	}
+	for i, line := range lines {
+		var actual model.JobLogLine
+		require.NoError(t, json.Unmarshal([]byte(line), &actual))
+		assert.NotEmpty(t, actual.Time)
+		actual.Time = ""
+		assert.Equal(t, expected[i], actual)
+	}
+
+	// 返回的是副本，修改不影响记录器
+	lines[0] = ""
+	assert.NotEmpty(t, logger.Lines()[0])
+}
+
+func TestJobLogLinesRoundTrip(t *testing.T) {
+	logger := NewJobLogger(0)
+	logger.Info("第一行")
+	logger.Info("包含\n换行的字段", "sql", "SELECT 1;\nSELECT 2;")
+
+	jobLog := &model.SysJobLog{}
+	require.NoError(t, jobLog.SetLogLines(logger.Lines()))
+	require.NotEmpty(t, jobLog.LogContent)
+
+	// 日志行为JSON，字段中的换行已转义，解压后按行拆分不变
+	lines, err := jobLog.GetLogLines()
+	require.NoError(t, err)
+	assert.Equal(t, logger.Lines(), lines)
+
+	// 没有日志时不保存内容
+	require.NoError(t, jobLog.SetLogLines(nil))
+	assert.Nil(t, jobLog.LogContent)
+	lines, err = jobLog.GetLogLines()
+	require.NoError(t, err)
+	assert.Empty(t, lines)
+
+	// 内容损坏时返回错误
+	jobLog.LogContent = []byte("not gzip")
+	_, err = jobLog.GetLogLines()
+	assert.Error(t, err)
+}
+
+func TestJobLogIsRunning(t *testing.T) {
+	now := time.Now()
+	recent := now.Add(-time.Minute)
+	stale := now.Add(-model.JobLogRunningTimeout - time.Minute)
+
+	tests := []struct {
+		name     string
+		jobLog   model.SysJobLog
+		expected bool
+	}{
+		{"执行中", model.SysJobLog{Status: model.JobLogStatusRunning, CreateTime: &recent}, true},
+		{"执行中状态已超时", model.SysJobLog{Status: model.JobLogStatusRunning, CreateTime: &stale}, false},
+		{"没有开始时间", model.SysJobLog{Status: model.JobLogStatusRunning}, false},
+		{"执行成功", model.SysJobLog{Status: model.JobLogStatusNormal, CreateTime: &recent}, false},
+		{"执行失败", model.SysJobLog{Status: model.JobLogStatusFail, CreateTime: &recent}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.jobLog.IsRunning(now))
+		})
+	}
+}
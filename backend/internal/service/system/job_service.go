@@ -1,30 +1,52 @@
 package system
 
 import (
+	"context"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+	"wosm/internal/config"
 	"wosm/internal/repository/dao"
 	"wosm/internal/repository/model"
 	cronUtils "wosm/pkg/cron"
+	"wosm/pkg/database"
 
 	"github.com/robfig/cron/v3"
+	"gorm.io/gorm"
 )
 
 // JobService 定时任务服务 对应Java后端的ISysJobService
 type JobService struct {
-	jobDao *dao.JobDao
-	cron   *cron.Cron
+	jobDao        *dao.JobDao
+	jobLogDao     *dao.JobLogDao
+	dependencyDao *dao.JobDependencyDao
+	calendarDao   *dao.JobCalendarDao
+	httpExecutor  *HttpJobExecutor
+	sqlExecutor   *SqlJobExecutor
+	cron          *cron.Cron
+	entryMu       *sync.Mutex            // WithContext 返回的服务实例与调度器共用
+	entries       map[int64]cron.EntryID // 任务ID到调度器条目ID的映射
 }
 
 // NewJobService 创建定时任务服务实例
 func NewJobService() *JobService {
 	// 创建cron调度器，支持秒级精度
 	c := cron.New(cron.WithSeconds())
+	settings := jobTypeSettings()
 
 	service := &JobService{
-		jobDao: dao.NewJobDao(),
-		cron:   c,
+		jobDao:        dao.NewJobDao(),
+		jobLogDao:     dao.NewJobLogDao(),
+		dependencyDao: dao.NewJobDependencyDao(),
+		calendarDao:   dao.NewJobCalendarDao(),
+		httpExecutor:  NewHttpJobExecutor(settings.HttpAllowedHosts),
+		sqlExecutor:   NewSqlJobExecutor(database.GetDB(), settings.SqlWhitelist),
+		cron:          c,
+		entryMu:       &sync.Mutex{},
+		entries:       make(map[int64]cron.EntryID),
 	}
 
 	// 启动调度器
@@ -36,6 +58,26 @@ func NewJobService() *JobService {
 	return service
 }
 
+// WithContext 返回使用指定context的服务实例，数据访问携带请求信息和租户
+func (s *JobService) WithContext(ctx context.Context) *JobService {
+	return &JobService{
+		jobDao:        s.jobDao.WithContext(ctx),
+		jobLogDao:     s.jobLogDao.WithContext(ctx),
+		dependencyDao: s.dependencyDao.WithContext(ctx),
+		calendarDao:   s.calendarDao.WithContext(ctx),
+		httpExecutor:  s.httpExecutor,
+		sqlExecutor:   s.sqlExecutor,
+		cron:          s.cron,
+		entryMu:       s.entryMu,
+		entries:       s.entries,
+	}
+}
+
+// background 调度器和立即执行的任务使用的服务实例，任务在请求结束后仍在执行，不使用请求的context
+func (s *JobService) background() *JobService {
+	return s.WithContext(context.Background())
+}
+
 // SelectJobList 获取quartz调度器的计划任务 对应Java后端的selectJobList
 func (s *JobService) SelectJobList(job *model.SysJob) ([]model.SysJob, error) {
 	fmt.Printf("JobService.SelectJobList: 查询定时任务列表\n")
@@ -48,7 +90,7 @@ func (s *JobService) SelectJobList(job *model.SysJob) ([]model.SysJob, error) {
 	// 为每个任务设置下次执行时间
 	for i := range jobList {
 		if jobList[i].Status == model.JobStatusNormal && jobList[i].CronExpression != "" {
-			nextTime, err := cronUtils.GetNextExecution(jobList[i].CronExpression)
+			nextTime, err := cronUtils.GetNextExecution(jobList[i].CronExpression, s.jobCalendars(&jobList[i])...)
 			if err == nil {
 				jobList[i].NextValidTime = nextTime
 			}
@@ -68,12 +110,21 @@ func (s *JobService) SelectJobById(jobId int64) (*model.SysJob, error) {
 	}
 
 	if job != nil && job.Status == model.JobStatusNormal && job.CronExpression != "" {
-		nextTime, err := cronUtils.GetNextExecution(job.CronExpression)
+		nextTime, err := cronUtils.GetNextExecution(job.CronExpression, s.jobCalendars(job)...)
 		if err == nil {
 			job.NextValidTime = nextTime
 		}
 	}
 
+	// 加载上游依赖
+	if job != nil {
+		deps, err := s.dependencyDao.SelectParentsByJobId(job.JobID)
+		if err != nil {
+			return nil, err
+		}
+		job.Dependencies = deps
+	}
+
 	return job, nil
 }
 
@@ -86,6 +137,16 @@ func (s *JobService) InsertJob(job *model.SysJob) error {
 		return fmt.Errorf("cron表达式无效: %s", job.CronExpression)
 	}
 
+	// 验证HTTP/SQL任务配置
+	if err := s.ValidateTypedJob(job); err != nil {
+		return err
+	}
+
+	// 验证排除日历
+	if err := s.checkCalendar(job.CalendarID); err != nil {
+		return err
+	}
+
 	// 检查任务名称唯一性
 	isUnique, err := s.jobDao.CheckJobNameUnique(job.JobName, job.JobGroup, 0)
 	if err != nil {
@@ -108,13 +169,21 @@ func (s *JobService) InsertJob(job *model.SysJob) error {
 	if job.MisfirePolicy == "" {
 		job.MisfirePolicy = model.MisfirePolicyDefault // 默认策略为"3"（不触发立即执行）
 	}
+	if job.JobType == "" {
+		job.JobType = model.JobTypeInvoke
+	}
 
 	// 设置创建时间
 	now := time.Now()
 	job.CreateTime = &now
 
-	// 保存到数据库
-	err = s.jobDao.InsertJob(job)
+	// 在同一事务中保存任务和上游依赖，依赖校验或保存失败时任务一起回滚
+	err = s.jobDao.Transaction(func(tx *gorm.DB) error {
+		if err := s.jobDao.WithTx(tx).InsertJob(job); err != nil {
+			return err
+		}
+		return s.saveDependencies(tx, job)
+	})
 	if err != nil {
 		return err
 	}
@@ -136,6 +205,16 @@ func (s *JobService) UpdateJob(job *model.SysJob) error {
 		return fmt.Errorf("cron表达式无效: %s", job.CronExpression)
 	}
 
+	// 验证HTTP/SQL任务配置
+	if err := s.ValidateTypedJob(job); err != nil {
+		return err
+	}
+
+	// 验证排除日历
+	if err := s.checkCalendar(job.CalendarID); err != nil {
+		return err
+	}
+
 	// 检查任务名称唯一性
 	isUnique, err := s.jobDao.CheckJobNameUnique(job.JobName, job.JobGroup, job.JobID)
 	if err != nil {
@@ -158,8 +237,19 @@ func (s *JobService) UpdateJob(job *model.SysJob) error {
 	now := time.Now()
 	job.UpdateTime = &now
 
-	// 更新数据库
-	err = s.jobDao.UpdateJob(job)
+	// 在同一事务中更新任务和上游依赖，依赖形成环或保存失败时任务的修改一起回滚
+	err = s.jobDao.Transaction(func(tx *gorm.DB) error {
+		jobDao := s.jobDao.WithTx(tx)
+		if err := jobDao.UpdateJob(job); err != nil {
+			return err
+		}
+		if job.CalendarID == 0 && oldJob.CalendarID != 0 {
+			if err := jobDao.UpdateJobCalendar(job.JobID, 0); err != nil {
+				return err
+			}
+		}
+		return s.saveDependencies(tx, job)
+	})
 	if err != nil {
 		return err
 	}
@@ -180,6 +270,11 @@ func (s *JobService) DeleteJob(job *model.SysJob) error {
 	// 从调度器中移除
 	s.removeJobFromScheduler(job)
 
+	// 删除相关依赖
+	if err := s.dependencyDao.DeleteByJobIds([]int64{job.JobID}); err != nil {
+		return err
+	}
+
 	// 从数据库删除
 	return s.jobDao.DeleteJobById(job.JobID)
 }
@@ -199,6 +294,11 @@ func (s *JobService) DeleteJobByIds(jobIds []int64) error {
 		}
 	}
 
+	// 删除相关依赖
+	if err := s.dependencyDao.DeleteByJobIds(jobIds); err != nil {
+		return err
+	}
+
 	// 批量删除数据库记录
 	return s.jobDao.DeleteJobByIds(jobIds)
 }
@@ -290,7 +390,7 @@ func (s *JobService) Run(job *model.SysJob) error {
 	}
 
 	// 立即执行任务
-	go s.executeJob(fullJob)
+	go s.background().executeJob(fullJob, nil)
 
 	return nil
 }
@@ -311,16 +411,80 @@ func (s *JobService) RunJob(job *model.SysJob) bool {
 	}
 
 	// 立即执行任务
-	go s.executeJob(fullJob)
+	go s.background().executeJob(fullJob, nil)
 
 	return true
 }
 
+// ValidateTypedJob 验证HTTP/SQL任务的配置，并根据配置生成调用目标字符串用于日志展示
+func (s *JobService) ValidateTypedJob(job *model.SysJob) error {
+	switch job.JobType {
+	case "", model.JobTypeInvoke:
+		return nil
+	case model.JobTypeHttp:
+		cfg, err := s.httpExecutor.Validate(job.JobConfig)
+		if err != nil {
+			return err
+		}
+		if !strings.Contains(cfg.URL, "{{") {
+			if err := s.httpExecutor.checkHost(cfg.URL); err != nil {
+				return err
+			}
+		}
+		job.InvokeTarget = truncateJobText(fmt.Sprintf("http:%s %s", cfg.Method, cfg.URL), 500)
+		return nil
+	case model.JobTypeSql:
+		cfg, err := s.sqlExecutor.Validate(job.JobConfig)
+		if err != nil {
+			return err
+		}
+		if cfg.Procedure != "" {
+			job.InvokeTarget = truncateJobText("sql:"+cfg.Procedure, 500)
+		} else {
+			job.InvokeTarget = truncateJobText("sql:"+cfg.Statement, 500)
+		}
+		return nil
+	default:
+		return fmt.Errorf("任务类型无效: %s", job.JobType)
+	}
+}
+
 // CheckCronExpressionIsValid 校验cron表达式是否有效 对应Java后端的checkCronExpressionIsValid
 func (s *JobService) CheckCronExpressionIsValid(cronExpression string) bool {
 	return cronUtils.IsValid(cronExpression)
 }
 
+// GetNextExecutions 获取cron表达式未来N次执行时间，calendarId 大于0时跳过日历排除的时间
+func (s *JobService) GetNextExecutions(cronExpression string, count int, calendarId int64) ([]time.Time, error) {
+	if err := s.checkCalendar(calendarId); err != nil {
+		return nil, err
+	}
+	return cronUtils.GetNextExecutions(cronExpression, count, s.jobCalendars(&model.SysJob{CalendarID: calendarId})...)
+}
+
+// checkCalendar 检查任务引用的排除日历是否存在
+func (s *JobService) checkCalendar(calendarId int64) error {
+	if calendarId <= 0 {
+		return nil
+	}
+	calendar, err := s.calendarDao.SelectCalendarById(calendarId)
+	if err != nil {
+		return err
+	}
+	if calendar == nil {
+		return fmt.Errorf("排除日历不存在: %d", calendarId)
+	}
+	return nil
+}
+
+// jobCalendars 返回任务引用的排除日历
+func (s *JobService) jobCalendars(job *model.SysJob) []cronUtils.Calendar {
+	if job.CalendarID <= 0 {
+		return nil
+	}
+	return []cronUtils.Calendar{jobCalendarRef{calendarId: job.CalendarID, calendarDao: s.calendarDao}}
+}
+
 // initJobs 初始化定时器，主要是防止手动修改数据库导致未同步到定时任务处理
 func (s *JobService) initJobs() {
 	fmt.Printf("JobService.initJobs: 初始化定时任务\n")
@@ -356,34 +520,55 @@ func (s *JobService) updateSchedulerJob(job *model.SysJob, jobGroup string) erro
 }
 
 // addJobToScheduler 添加任务到调度器
+// 使用Quartz兼容的表达式解析，支持 L、W、#、年字段和 TZ= 时区前缀，并跳过排除日历中的时间
 func (s *JobService) addJobToScheduler(job *model.SysJob) {
 	if job.CronExpression == "" {
 		fmt.Printf("addJobToScheduler: 任务cron表达式为空, JobID=%d\n", job.JobID)
 		return
 	}
 
-	jobFunc := func() {
-		s.executeJob(job)
-	}
-
-	entryID, err := s.cron.AddFunc(job.CronExpression, jobFunc)
+	schedule, err := cronUtils.Parse(job.CronExpression)
 	if err != nil {
 		fmt.Printf("addJobToScheduler: 添加任务到调度器失败, JobID=%d, 错误=%v\n", job.JobID, err)
 		return
 	}
 
+	calendarSchedule := cronUtils.WithCalendars(schedule, s.jobCalendars(job)...)
+	runner := s.background()
+	jobFunc := func() {
+		// 调度器在日历修改前已计算好的执行时间可能已被排除，执行前再次检查
+		if !calendarSchedule.IsTimeIncluded(time.Now()) {
+			fmt.Printf("addJobToScheduler: 当前时间被排除日历排除, 跳过执行, JobID=%d\n", job.JobID)
+			return
+		}
+		runner.executeJob(job, nil)
+	}
+
+	s.entryMu.Lock()
+	defer s.entryMu.Unlock()
+	if entryID, ok := s.entries[job.JobID]; ok {
+		s.cron.Remove(entryID)
+	}
+	entryID := s.cron.Schedule(calendarSchedule, cron.FuncJob(jobFunc))
+	s.entries[job.JobID] = entryID
+
 	fmt.Printf("addJobToScheduler: 添加任务到调度器成功, JobID=%d, EntryID=%d\n", job.JobID, entryID)
 }
 
 // removeJobFromScheduler 从调度器移除任务
 func (s *JobService) removeJobFromScheduler(job *model.SysJob) {
-	// 由于cron库的限制，这里简化处理
-	// 实际项目中可以维护一个JobID到EntryID的映射
+	s.entryMu.Lock()
+	defer s.entryMu.Unlock()
+	if entryID, ok := s.entries[job.JobID]; ok {
+		s.cron.Remove(entryID)
+		delete(s.entries, job.JobID)
+	}
 	fmt.Printf("removeJobFromScheduler: 从调度器移除任务, JobID=%d\n", job.JobID)
 }
 
 // executeJob 执行任务 对应Java后端的任务执行逻辑
-func (s *JobService) executeJob(job *model.SysJob) {
+// params 为上游任务传递的执行结果参数，由定时调度或手动执行时为nil
+func (s *JobService) executeJob(job *model.SysJob, params map[string]string) {
 	fmt.Printf("executeJob: 开始执行任务, JobID=%d, JobName=%s, InvokeTarget=%s\n",
 		job.JobID, job.JobName, job.InvokeTarget)
 
@@ -396,6 +581,12 @@ func (s *JobService) executeJob(job *model.SysJob) {
 	startTime := time.Now()
 	var err error
 	var jobMessage string
+	var result string
+
+	// 任务开始时先创建执行中的调度日志，以便前端实时查看执行日志
+	jobLog := s.startJobLog(job, startTime)
+	jobLogger := NewJobLogger(jobLog.JobLogID)
+	jobLogger.Info("任务开始执行", "jobId", job.JobID, "invokeTarget", job.InvokeTarget)
 
 	// 执行任务的核心逻辑
 	defer func() {
@@ -409,28 +600,49 @@ func (s *JobService) executeJob(job *model.SysJob) {
 		}
 
 		// 记录执行结果
-		status := "0" // 成功
+		status := model.JobLogStatusNormal
 		if err != nil {
-			status = "1" // 失败
+			status = model.JobLogStatusFail
 			jobMessage = err.Error()
+			if result != "" {
+				jobMessage = fmt.Sprintf("%s，%s", jobMessage, result)
+			}
 			fmt.Printf("executeJob: 任务执行失败, JobID=%d, 耗时=%v, 错误=%v\n",
 				job.JobID, duration, err)
 		} else {
-			jobMessage = "任务执行成功"
+			jobMessage = fmt.Sprintf("%s 总共耗时：%d毫秒", job.JobName, duration.Milliseconds())
+			if result != "" {
+				jobMessage = fmt.Sprintf("%s，%s", jobMessage, result)
+			}
 			fmt.Printf("executeJob: 任务执行成功, JobID=%d, 耗时=%v\n",
 				job.JobID, duration)
 		}
 
-		// TODO: 记录任务执行日志到数据库
-		s.recordJobLog(job, startTime, endTime, status, jobMessage, err)
+		if err != nil {
+			jobLogger.Error("任务执行失败", "error", err.Error(), "costMs", duration.Milliseconds())
+		} else {
+			jobLogger.Info("任务执行结束", "costMs", duration.Milliseconds())
+		}
+
+		s.recordJobLog(jobLog, jobLogger, status, jobMessage, err)
+
+		// 触发下游任务
+		s.triggerDownstreamJobs(job, status, jobMessage)
 	}()
 
-	// 根据InvokeTarget执行相应的任务
-	err = s.invokeMethod(job.InvokeTarget)
+	// 根据任务类型执行相应的任务
+	switch job.JobType {
+	case model.JobTypeHttp:
+		result, err = s.httpExecutor.Execute(context.Background(), job, params, jobLogger)
+	case model.JobTypeSql:
+		result, err = s.sqlExecutor.Execute(context.Background(), job, params, jobLogger)
+	default:
+		err = s.invokeMethod(job.InvokeTarget, params, jobLogger)
+	}
 }
 
-// invokeMethod 调用目标方法
-func (s *JobService) invokeMethod(invokeTarget string) error {
+// invokeMethod 调用目标方法，处理函数通过 jobLogger 输出结构化的执行日志
+func (s *JobService) invokeMethod(invokeTarget string, params map[string]string, jobLogger *JobLogger) error {
 	// 简化的方法调用实现
 	// 实际项目中需要根据invokeTarget解析并调用相应的方法
 
@@ -450,45 +662,304 @@ func (s *JobService) invokeMethod(invokeTarget string) error {
 	switch invokeTarget {
 	case "testTask":
 		fmt.Printf("invokeMethod: 执行测试任务\n")
+		jobLogger.Info("执行测试任务")
 		time.Sleep(1 * time.Second) // 模拟任务执行
 		return nil
+	case "recycleTask.purgeExpired":
+		// 彻底删除超过保留天数的回收站数据 保留天数对应配置 recycle.retention_days，清理全部租户的回收站
+		retentionDays := config.GetRecycleRetentionDays()
+		purged, err := NewRecycleService().WithContext(database.WithAllTenants(context.Background())).PurgeExpired(retentionDays)
+		jobLogger.Info("清理回收站", "retentionDays", retentionDays, "purged", purged)
+		return err
 	case "cleanTempFiles":
 		fmt.Printf("invokeMethod: 执行清理临时文件任务\n")
+		jobLogger.Info("执行清理临时文件任务")
 		// 实际的清理逻辑
 		return nil
 	default:
-		fmt.Printf("invokeMethod: 执行自定义任务: %s\n", invokeTarget)
+		fmt.Printf("invokeMethod: 执行自定义任务: %s, 参数=%v\n", invokeTarget, params)
+		jobLogger.Info("执行自定义任务", "invokeTarget", invokeTarget, "params", params)
 		return nil
 	}
 }
 
+// startJobLog 创建执行中的调度日志，保存失败时返回ID为0的日志并在任务结束后补录
+func (s *JobService) startJobLog(job *model.SysJob, startTime time.Time) *model.SysJobLog {
+	jobLog := &model.SysJobLog{
+		JobName:      job.JobName,
+		JobGroup:     job.JobGroup,
+		InvokeTarget: job.InvokeTarget,
+		JobMessage:   "任务执行中",
+		Status:       model.JobLogStatusRunning,
+		CreateTime:   &startTime,
+	}
+	if err := s.jobLogDao.InsertJobLog(jobLog); err != nil {
+		fmt.Printf("startJobLog: 创建调度日志失败, JobID=%d, 错误=%v\n", job.JobID, err)
+		jobLog.JobLogID = 0
+	}
+	return jobLog
+}
+
 // recordJobLog 记录任务执行日志 对应Java后端的任务日志记录
-func (s *JobService) recordJobLog(job *model.SysJob, startTime, endTime time.Time, status, jobMessage string, execErr error) {
-	fmt.Printf("recordJobLog: 记录任务执行日志, JobID=%d, Status=%s, Message=%s\n",
-		job.JobID, status, jobMessage)
-
-	// 计算执行时长
-	duration := endTime.Sub(startTime)
-	fmt.Printf("recordJobLog: 任务执行时长=%v\n", duration)
-
-	// TODO: 实现任务执行日志记录到数据库
-	// 这里需要创建SysJobLog实体并保存到数据库
-	// jobLog := &model.SysJobLog{
-	//     JobName:       job.JobName,
-	//     JobGroup:      job.JobGroup,
-	//     InvokeTarget:  job.InvokeTarget,
-	//     JobMessage:    jobMessage,
-	//     Status:        status,
-	//     ExceptionInfo: "",
-	//     CreateTime:    &startTime,
-	// }
-	// if execErr != nil {
-	//     jobLog.ExceptionInfo = execErr.Error()
-	// }
-	// s.jobLogDao.InsertJobLog(jobLog)
-
-	// 记录执行时间信息
-	_ = startTime // 使用startTime参数
-	_ = endTime   // 使用endTime参数
-	_ = execErr   // 使用execErr参数
+func (s *JobService) recordJobLog(jobLog *model.SysJobLog, jobLogger *JobLogger, status, jobMessage string, execErr error) {
+	fmt.Printf("recordJobLog: 记录任务执行日志, JobLogID=%d, Status=%s, Message=%s\n",
+		jobLog.JobLogID, status, jobMessage)
+
+	jobLog.JobMessage = truncateJobText(jobMessage, 500)
+	jobLog.Status = status
+	jobLog.ExceptionInfo = ""
+	if execErr != nil {
+		jobLog.ExceptionInfo = truncateJobText(execErr.Error(), 2000)
+	}
+	if err := jobLog.SetLogLines(jobLogger.Lines()); err != nil {
+		fmt.Printf("recordJobLog: 压缩执行日志失败, JobLogID=%d, 错误=%v\n", jobLog.JobLogID, err)
+	}
+
+	var err error
+	if jobLog.JobLogID > 0 {
+		err = s.jobLogDao.UpdateJobLogResult(jobLog)
+	} else {
+		err = s.jobLogDao.InsertJobLog(jobLog)
+	}
+	if err != nil {
+		fmt.Printf("recordJobLog: 保存任务执行日志失败, JobLogID=%d, 错误=%v\n", jobLog.JobLogID, err)
+	}
+
+	// 通知实时日志订阅方任务已结束
+	jobLogger.finish(status)
+}
+
+// truncateJobText 按字符截断日志文本，避免超出字段长度
+func truncateJobText(text string, maxLen int) string {
+	runes := []rune(text)
+	if len(runes) <= maxLen {
+		return text
+	}
+	return string(runes[:maxLen])
+}
+
+// triggerDownstreamJobs 根据依赖关系触发下游任务，并将上游执行结果作为参数传递
+func (s *JobService) triggerDownstreamJobs(job *model.SysJob, status, jobMessage string) {
+	children, err := s.dependencyDao.SelectChildrenByJobId(job.JobID)
+	if err != nil {
+		fmt.Printf("triggerDownstreamJobs: 查询下游任务失败, JobID=%d, 错误=%v\n", job.JobID, err)
+		return
+	}
+
+	for _, dep := range children {
+		if !dep.Matches(status) {
+			continue
+		}
+
+		child, err := s.jobDao.SelectJobById(dep.JobID)
+		if err != nil || child == nil {
+			fmt.Printf("triggerDownstreamJobs: 下游任务不存在, JobID=%d\n", dep.JobID)
+			continue
+		}
+		if child.Status != model.JobStatusNormal {
+			fmt.Printf("triggerDownstreamJobs: 下游任务已暂停, 跳过执行, JobID=%d\n", child.JobID)
+			continue
+		}
+
+		params := map[string]string{
+			"upstreamJobId":    strconv.FormatInt(job.JobID, 10),
+			"upstreamJobName":  job.JobName,
+			"upstreamJobGroup": job.JobGroup,
+			"upstreamStatus":   status,
+			"upstreamMessage":  jobMessage,
+		}
+
+		fmt.Printf("triggerDownstreamJobs: 触发下游任务, ParentJobID=%d, JobID=%d, TriggerType=%s\n",
+			job.JobID, child.JobID, dep.TriggerType)
+		go s.executeJob(child, params)
+	}
+}
+
+// checkDependencies 校验任务的上游依赖：上游任务必须存在且依赖关系图不能形成环
+// 在保存依赖的事务中调用，jobDao、dependencyDao 使用该事务
+func checkDependencies(jobDao *dao.JobDao, dependencyDao *dao.JobDependencyDao, jobId int64, deps []model.SysJobDependency) error {
+	seen := make(map[int64]bool)
+	for i := range deps {
+		dep := &deps[i]
+		dep.JobID = jobId
+		if err := model.ValidateJobDependency(dep); err != nil {
+			return err
+		}
+		if seen[dep.ParentJobID] {
+			return fmt.Errorf("上游任务重复: %d", dep.ParentJobID)
+		}
+		seen[dep.ParentJobID] = true
+
+		parent, err := jobDao.SelectJobById(dep.ParentJobID)
+		if err != nil {
+			return err
+		}
+		if parent == nil {
+			return fmt.Errorf("上游任务不存在: %d", dep.ParentJobID)
+		}
+	}
+
+	// 以数据库中的依赖关系为基础，替换当前任务的上游依赖后检查是否有环
+	allDeps, err := dependencyDao.SelectAllDependencies()
+	if err != nil {
+		return err
+	}
+	edges := make(map[int64][]int64)
+	for _, dep := range allDeps {
+		if dep.JobID == jobId {
+			continue
+		}
+		edges[dep.ParentJobID] = append(edges[dep.ParentJobID], dep.JobID)
+	}
+	for _, dep := range deps {
+		edges[dep.ParentJobID] = append(edges[dep.ParentJobID], jobId)
+	}
+
+	if cycle := findJobCycle(edges); len(cycle) > 0 {
+		path := make([]string, len(cycle))
+		for i, id := range cycle {
+			path[i] = strconv.FormatInt(id, 10)
+		}
+		return fmt.Errorf("任务依赖存在循环: %s", strings.Join(path, " -> "))
+	}
+
+	return nil
+}
+
+// saveDependencies 在事务中校验并保存任务的上游依赖，未提交依赖（Dependencies为nil）时保留原有依赖
+func (s *JobService) saveDependencies(tx *gorm.DB, job *model.SysJob) error {
+	if job.Dependencies == nil {
+		return nil
+	}
+	dependencyDao := s.dependencyDao.WithTx(tx)
+	if err := checkDependencies(s.jobDao.WithTx(tx), dependencyDao, job.JobID, job.Dependencies); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	createBy := job.UpdateBy
+	if createBy == "" {
+		createBy = job.CreateBy
+	}
+	deps := make([]model.SysJobDependency, 0, len(job.Dependencies))
+	for _, dep := range job.Dependencies {
+		triggerType := dep.TriggerType
+		if triggerType == "" {
+			triggerType = model.JobTriggerOnSuccess
+		}
+		deps = append(deps, model.SysJobDependency{
+			JobID:       job.JobID,
+			ParentJobID: dep.ParentJobID,
+			TriggerType: triggerType,
+			CreateBy:    createBy,
+			CreateTime:  &now,
+		})
+	}
+
+	return dependencyDao.ReplaceParents(job.JobID, deps)
+}
+
+// findJobCycle 在依赖图中查找环，返回环上的任务ID路径（首尾相同），无环时返回nil
+func findJobCycle(edges map[int64][]int64) []int64 {
+	const (
+		white = iota // 未访问
+		gray         // 访问中
+		black        // 已完成
+	)
+
+	// 按ID排序保证结果稳定
+	nodes := make([]int64, 0, len(edges))
+	for id := range edges {
+		nodes = append(nodes, id)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i] < nodes[j] })
+
+	color := make(map[int64]int)
+	var stack []int64
+	var cycle []int64
+
+	var visit func(id int64) bool
+	visit = func(id int64) bool {
+		color[id] = gray
+		stack = append(stack, id)
+		for _, next := range edges[id] {
+			switch color[next] {
+			case gray:
+				for i, v := range stack {
+					if v == next {
+						cycle = append(append([]int64{}, stack[i:]...), next)
+						break
+					}
+				}
+				return true
+			case white:
+				if visit(next) {
+					return true
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		color[id] = black
+		return false
+	}
+
+	for _, id := range nodes {
+		if color[id] == white && visit(id) {
+			return cycle
+		}
+	}
+	return nil
+}
+
+// SelectJobGraph 查询任务依赖关系图及每个任务最近一次的执行状态
+func (s *JobService) SelectJobGraph() (*model.JobGraph, error) {
+	fmt.Printf("JobService.SelectJobGraph: 查询任务依赖关系图\n")
+
+	jobList, err := s.jobDao.SelectJobAll()
+	if err != nil {
+		return nil, err
+	}
+	deps, err := s.dependencyDao.SelectAllDependencies()
+	if err != nil {
+		return nil, err
+	}
+
+	// 一次查询所有任务最近的调度日志，按任务名称和组名匹配
+	latestLogs, err := s.jobLogDao.SelectLatestJobLogs()
+	if err != nil {
+		return nil, err
+	}
+	lastLogs := make(map[[2]string]*model.SysJobLog, len(latestLogs))
+	for i := range latestLogs {
+		lastLogs[[2]string{latestLogs[i].JobName, latestLogs[i].JobGroup}] = &latestLogs[i]
+	}
+
+	graph := &model.JobGraph{
+		Nodes: make([]model.JobGraphNode, 0, len(jobList)),
+		Edges: make([]model.JobGraphEdge, 0, len(deps)),
+	}
+	for _, job := range jobList {
+		node := model.JobGraphNode{
+			JobID:          job.JobID,
+			JobName:        job.JobName,
+			JobGroup:       job.JobGroup,
+			CronExpression: job.CronExpression,
+			Status:         job.Status,
+		}
+		if lastLog, ok := lastLogs[[2]string{job.JobName, job.JobGroup}]; ok {
+			node.LastRunStatus = lastLog.Status
+			node.LastRunTime = lastLog.CreateTime
+			node.LastRunMessage = lastLog.JobMessage
+		}
+		graph.Nodes = append(graph.Nodes, node)
+	}
+	for _, dep := range deps {
+		graph.Edges = append(graph.Edges, model.JobGraphEdge{
+			Source:      dep.ParentJobID,
+			Target:      dep.JobID,
+			TriggerType: dep.TriggerType,
+		})
+	}
+
+	return graph, nil
 }
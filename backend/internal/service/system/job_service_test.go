@@ -0,0 +1,110 @@
+package system
+
+import (
+	"testing"
+	"wosm/internal/repository/dao"
+	"wosm/internal/repository/model"
+	"wosm/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindJobCycle(t *testing.T) {
+	tests := []struct {
+		name     string
+		edges    map[int64][]int64
+		expected []int64
+	}{
+		{"没有依赖", map[int64][]int64{}, nil},
+		{"有向无环图", map[int64][]int64{1: {2, 3}, 2: {4}, 3: {4}}, nil},
+		{"依赖自身", map[int64][]int64{1: {2}, 2: {2}}, []int64{2, 2}},
+		{"间接循环", map[int64][]int64{1: {2}, 2: {3}, 3: {1}}, []int64{1, 2, 3, 1}},
+		{"环不经过起点", map[int64][]int64{1: {2}, 2: {3}, 3: {2}}, []int64{2, 3, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, findJobCycle(tt.edges))
+		})
+	}
+}
+
+func TestCheckDependencies(t *testing.T) {
+	db := testutil.OpenMigratedDB(t)
+	// 初始任务1、2、3：任务2依赖任务1，任务3依赖任务2
+	require.NoError(t, db.Create([]model.SysJobDependency{
+		{JobID: 2, ParentJobID: 1, TriggerType: model.JobTriggerOnSuccess},
+		{JobID: 3, ParentJobID: 2, TriggerType: model.JobTriggerOnSuccess},
+	}).Error)
+
+	tests := []struct {
+		name    string
+		jobId   int64
+		parents []int64
+		err     string
+	}{
+		{"有向无环图", 3, []int64{1, 2}, ""},
+		{"新增任务", 0, []int64{3}, ""},
+		{"依赖自身", 2, []int64{2}, "任务不能依赖自身"},
+		{"间接循环", 1, []int64{3}, "任务依赖存在循环: 1 -> 2 -> 3 -> 1"},
+		{"上游任务不存在", 3, []int64{999}, "上游任务不存在: 999"},
+		{"上游任务重复", 3, []int64{1, 1}, "上游任务重复: 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deps := make([]model.SysJobDependency, len(tt.parents))
+			for i, parentId := range tt.parents {
+				deps[i] = model.SysJobDependency{ParentJobID: parentId}
+			}
+			err := checkDependencies(dao.NewJobDao(), dao.NewJobDependencyDao(), tt.jobId, deps)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestInsertJobRollbackOnInvalidDependency(t *testing.T) {
+	testutil.OpenMigratedDB(t)
+	// 不使用 NewJobService，避免启动调度器
+	service := &JobService{jobDao: dao.NewJobDao(), dependencyDao: dao.NewJobDependencyDao(), calendarDao: dao.NewJobCalendarDao()}
+
+	job := &model.SysJob{
+		JobName:        "依赖校验失败",
+		JobGroup:       model.JobGroupDefault,
+		InvokeTarget:   "ryTask.ryNoParams",
+		CronExpression: "0/10 * * * * ?",
+		Dependencies:   []model.SysJobDependency{{ParentJobID: 999}},
+	}
+	assert.EqualError(t, service.InsertJob(job), "上游任务不存在: 999")
+
+	// 依赖校验失败时任务不应保存
+	isUnique, err := service.jobDao.CheckJobNameUnique(job.JobName, job.JobGroup, 0)
+	require.NoError(t, err)
+	assert.True(t, isUnique)
+}
+
+func TestSelectJobGraphLatestLog(t *testing.T) {
+	db := testutil.OpenMigratedDB(t)
+	service := &JobService{jobDao: dao.NewJobDao(), jobLogDao: dao.NewJobLogDao(), dependencyDao: dao.NewJobDependencyDao()}
+	require.NoError(t, db.Create([]model.SysJobLog{
+		{JobName: "系统默认（无参）", JobGroup: model.JobGroupDefault, InvokeTarget: "ryTask.ryNoParams", Status: "1", JobMessage: "第一次"},
+		{JobName: "系统默认（无参）", JobGroup: model.JobGroupDefault, InvokeTarget: "ryTask.ryNoParams", Status: "0", JobMessage: "第二次"},
+		{JobName: "系统默认（有参）", JobGroup: model.JobGroupDefault, InvokeTarget: "ryTask.ryParams('ry')", Status: "1", JobMessage: "失败"},
+	}).Error)
+
+	graph, err := service.SelectJobGraph()
+	require.NoError(t, err)
+	messages := make(map[int64]string)
+	for _, node := range graph.Nodes {
+		messages[node.JobID] = node.LastRunMessage
+	}
+	// 每个任务取最近一次调度日志，没有日志的任务为空
+	assert.Equal(t, "第二次", messages[1])
+	assert.Equal(t, "失败", messages[2])
+	assert.Equal(t, "", messages[3])
+}
@@ -1,6 +1,7 @@
 package system
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
@@ -21,6 +22,13 @@ func NewLoginLogService() *LoginLogService {
 	}
 }
 
+// WithContext 返回使用指定context的服务实例，数据访问携带请求信息和租户
+func (s *LoginLogService) WithContext(ctx context.Context) *LoginLogService {
+	return &LoginLogService{
+		loginLogDao: s.loginLogDao.WithContext(ctx),
+	}
+}
+
 // SelectLogininforList 查询系统登录日志集合 对应Java后端的selectLogininforList
 func (s *LoginLogService) SelectLogininforList(logininfor *model.SysLogininfor) ([]model.SysLogininfor, error) {
 	fmt.Printf("LoginLogService.SelectLogininforList: 查询登录日志列表\n")
@@ -54,7 +62,7 @@ func (s *LoginLogService) CleanLogininfor() error {
 func (s *LoginLogService) RecordLoginInfo(userName, status, message, ipAddr, userAgent string) {
 	fmt.Printf("LoginLogService.RecordLoginInfo: 记录登录信息, UserName=%s, Status=%s\n", userName, status)
 
-	// 异步记录登录日志，避免影响登录性能
+	// 异步记录登录日志，避免影响登录性能；租户插件按服务context中的租户写入
 	go func() {
 		logininfor := &model.SysLogininfor{
 			UserName:      userName,
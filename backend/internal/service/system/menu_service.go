@@ -1,15 +1,19 @@
 package system
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
 	"wosm/internal/repository/dao"
 	"wosm/internal/repository/model"
+
+	"gorm.io/gorm"
 )
 
 // MenuService 菜单服务 对应Java后端的ISysMenuService
 type MenuService struct {
+	ctx     context.Context // 数据访问使用的context，方法中创建的其他服务使用该context
 	menuDao *dao.MenuDao
 }
 
@@ -20,12 +24,20 @@ func NewMenuService() *MenuService {
 	}
 }
 
+// WithContext 返回使用指定context的服务实例，数据访问携带请求信息和租户
+func (s *MenuService) WithContext(ctx context.Context) *MenuService {
+	return &MenuService{
+		ctx:     ctx,
+		menuDao: s.menuDao.WithContext(ctx),
+	}
+}
+
 // SelectMenuList 查询菜单列表 对应Java后端的selectMenuList(SysMenu menu, Long userId)
 func (s *MenuService) SelectMenuList(menu *model.SysMenu, userId int64) ([]model.SysMenu, error) {
 	fmt.Printf("MenuService.SelectMenuList: 查询菜单列表, UserID=%d\n", userId)
 
 	// 管理员显示所有菜单信息 - 对应Java后端的SysUser.isAdmin(userId)
-	userService := NewUserService()
+	userService := NewUserService().WithContext(s.ctx)
 	user, err := userService.SelectUserById(userId)
 	if err != nil {
 		fmt.Printf("MenuService.SelectMenuList: 查询用户失败: %v\n", err)
@@ -56,6 +68,11 @@ func (s *MenuService) SelectMenuById(menuId int64) (*model.SysMenu, error) {
 	return s.menuDao.SelectMenuById(menuId)
 }
 
+// SelectMenuByPath 根据上级菜单和路由地址查询菜单
+func (s *MenuService) SelectMenuByPath(parentId int64, path, menuType string) (*model.SysMenu, error) {
+	return s.menuDao.SelectMenuByPath(parentId, path, menuType)
+}
+
 // BuildMenuTree 构建菜单树结构 对应Java后端的buildMenuTree
 func (s *MenuService) BuildMenuTree(menus []model.SysMenu) []model.SysMenu {
 	fmt.Printf("MenuService.BuildMenuTree: 构建菜单树, 菜单数量=%d\n", len(menus))
@@ -104,7 +121,7 @@ func (s *MenuService) SelectMenuListByRoleId(roleId int64) ([]int64, error) {
 	fmt.Printf("MenuService.SelectMenuListByRoleId: 查询角色菜单, RoleID=%d\n", roleId)
 
 	// 需要先查询角色信息，获取MenuCheckStrictly字段
-	roleService := NewRoleService()
+	roleService := NewRoleService().WithContext(s.ctx)
 	role, err := roleService.SelectRoleById(roleId)
 	if err != nil {
 		fmt.Printf("MenuService.SelectMenuListByRoleId: 查询角色信息失败: %v\n", err)
@@ -140,6 +157,10 @@ func (s *MenuService) CheckMenuNameUnique(menu *model.SysMenu) (bool, error) {
 func (s *MenuService) InsertMenu(menu *model.SysMenu) error {
 	fmt.Printf("MenuService.InsertMenu: 新增菜单, MenuName=%s\n", menu.MenuName)
 
+	if err := checkPlatformTenant(s.ctx); err != nil {
+		return err
+	}
+
 	// 设置创建时间
 	now := time.Now()
 	menu.CreateTime = &now
@@ -151,6 +172,10 @@ func (s *MenuService) InsertMenu(menu *model.SysMenu) error {
 func (s *MenuService) UpdateMenu(menu *model.SysMenu) error {
 	fmt.Printf("MenuService.UpdateMenu: 修改菜单, MenuID=%d\n", menu.MenuID)
 
+	if err := checkPlatformTenant(s.ctx); err != nil {
+		return err
+	}
+
 	// 设置更新时间
 	now := time.Now()
 	menu.UpdateTime = &now
@@ -160,10 +185,138 @@ func (s *MenuService) UpdateMenu(menu *model.SysMenu) error {
 
 // DeleteMenuById 删除菜单 对应Java后端的deleteMenuById
 func (s *MenuService) DeleteMenuById(menuId int64) error {
+	if err := checkPlatformTenant(s.ctx); err != nil {
+		return err
+	}
+
 	fmt.Printf("MenuService.DeleteMenuById: 删除菜单, MenuID=%d\n", menuId)
 	return s.menuDao.DeleteMenuById(menuId)
 }
 
+// InsertMenuTree 在一个事务中新增菜单及其全部子菜单，并授权给指定角色 用于代码生成安装模块菜单
+// menu.MenuID 大于0表示使用已存在的菜单，只新增其子菜单；角色同时获得新增菜单的全部上级菜单
+func (s *MenuService) InsertMenuTree(menu *model.SysMenu, roleIds []int64) error {
+	fmt.Printf("MenuService.InsertMenuTree: 新增菜单树, MenuName=%s, RoleIDs=%v\n", menu.MenuName, roleIds)
+
+	if err := checkPlatformTenant(s.ctx); err != nil {
+		return err
+	}
+
+	return s.menuDao.Transaction(func(tx *gorm.DB) error {
+		menuDao := s.menuDao.WithTx(tx)
+		roleMenuDao := dao.NewRoleMenuDao().WithTx(tx)
+
+		// 已存在的菜单及其上级菜单，角色缺少时补充授权
+		var existingIds []int64
+		parentId := menu.ParentID
+		if menu.MenuID > 0 {
+			parentId = menu.MenuID
+		}
+		for parentId > 0 {
+			parent, err := menuDao.SelectMenuById(parentId)
+			if err != nil {
+				return err
+			}
+			if parent == nil {
+				return fmt.Errorf("上级菜单不存在, MenuID=%d", parentId)
+			}
+			existingIds = append(existingIds, parent.MenuID)
+			parentId = parent.ParentID
+		}
+
+		var insertedIds []int64
+		if err := insertMenuTree(menuDao, menu, &insertedIds); err != nil {
+			return err
+		}
+
+		var roleMenus []model.SysRoleMenu
+		for _, roleId := range roleIds {
+			for _, menuId := range insertedIds {
+				roleMenus = append(roleMenus, model.SysRoleMenu{RoleID: roleId, MenuID: menuId})
+			}
+		}
+		for _, menuId := range existingIds {
+			granted, err := roleMenuDao.SelectRoleIdsByMenuId(menuId)
+			if err != nil {
+				return err
+			}
+			for _, roleId := range roleIds {
+				if !contains(granted, roleId) {
+					roleMenus = append(roleMenus, model.SysRoleMenu{RoleID: roleId, MenuID: menuId})
+				}
+			}
+		}
+		return roleMenuDao.BatchInsertRoleMenu(roleMenus)
+	})
+}
+
+// insertMenuTree 递归新增菜单，子菜单的上级菜单ID使用新增后的菜单ID
+func insertMenuTree(menuDao *dao.MenuDao, menu *model.SysMenu, insertedIds *[]int64) error {
+	if menu.MenuID == 0 {
+		unique, err := menuDao.CheckMenuNameUnique(menu.MenuName, menu.ParentID, 0)
+		if err != nil {
+			return err
+		}
+		if !unique {
+			return fmt.Errorf("菜单'%s'已存在", menu.MenuName)
+		}
+		now := time.Now()
+		menu.CreateTime = &now
+		if err := menuDao.InsertMenu(menu); err != nil {
+			return err
+		}
+		*insertedIds = append(*insertedIds, menu.MenuID)
+	}
+
+	for i := range menu.Children {
+		menu.Children[i].ParentID = menu.MenuID
+		if err := insertMenuTree(menuDao, &menu.Children[i], insertedIds); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteMenuTree 在一个事务中删除菜单及其全部子菜单，同时删除角色菜单关联 用于代码生成卸载模块菜单
+// emptyParentId 不为0时，删除后该菜单没有子菜单则一并删除
+func (s *MenuService) DeleteMenuTree(menuId, emptyParentId int64) error {
+	fmt.Printf("MenuService.DeleteMenuTree: 删除菜单树, MenuID=%d\n", menuId)
+
+	if err := checkPlatformTenant(s.ctx); err != nil {
+		return err
+	}
+
+	return s.menuDao.Transaction(func(tx *gorm.DB) error {
+		menuDao := s.menuDao.WithTx(tx)
+		roleMenuDao := dao.NewRoleMenuDao().WithTx(tx)
+
+		menuIds := []int64{menuId}
+		for parentIds := menuIds; len(parentIds) > 0; {
+			childIds, err := menuDao.SelectMenuIdsByParentIds(parentIds)
+			if err != nil {
+				return err
+			}
+			menuIds = append(menuIds, childIds...)
+			parentIds = childIds
+		}
+
+		if emptyParentId > 0 {
+			childIds, err := menuDao.SelectMenuIdsByParentIds([]int64{emptyParentId})
+			if err != nil {
+				return err
+			}
+			if len(childIds) == 1 && childIds[0] == menuId {
+				menuIds = append(menuIds, emptyParentId)
+			}
+		}
+
+		if err := roleMenuDao.DeleteRoleMenuByMenuIds(menuIds); err != nil {
+			return err
+		}
+		return menuDao.DeleteMenuByIds(menuIds)
+	})
+}
+
 // SelectMenuPermsByUserId 根据用户ID查询权限 对应Java后端的selectMenuPermsByUserId
 func (s *MenuService) SelectMenuPermsByUserId(userId int64) ([]string, error) {
 	fmt.Printf("MenuService.SelectMenuPermsByUserId: 查询用户权限, UserID=%d\n", userId)
@@ -184,7 +337,7 @@ func (s *MenuService) SelectMenuTreeByUserId(userId int64) ([]model.SysMenu, err
 	var err error
 
 	// 管理员显示所有菜单信息 - 对应Java后端的SecurityUtils.isAdmin(userId)
-	userService := NewUserService()
+	userService := NewUserService().WithContext(s.ctx)
 	user, err := userService.SelectUserById(userId)
 	if err != nil {
 		fmt.Printf("MenuService.SelectMenuTreeByUserId: 查询用户失败: %v\n", err)
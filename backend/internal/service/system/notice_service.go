@@ -1,6 +1,7 @@
 package system
 
 import (
+	"context"
 	"fmt"
 	"time"
 	"wosm/internal/repository/dao"
@@ -21,6 +22,13 @@ func NewNoticeService() *NoticeService {
 	}
 }
 
+// WithContext 返回使用指定context的服务实例，数据访问携带请求信息和租户
+func (s *NoticeService) WithContext(ctx context.Context) *NoticeService {
+	return &NoticeService{
+		noticeDao: s.noticeDao.WithContext(ctx),
+	}
+}
+
 // SelectNoticeById 根据公告ID查询公告信息 对应Java后端的selectNoticeById
 func (s *NoticeService) SelectNoticeById(noticeId int64) (*model.SysNotice, error) {
 	fmt.Printf("NoticeService.SelectNoticeById: 查询公告信息, NoticeId=%d\n", noticeId)
@@ -40,7 +48,7 @@ func (s *NoticeService) SelectNoticeList(params *model.NoticeQueryParams) ([]mod
 		params = &model.NoticeQueryParams{}
 	}
 
-	return s.noticeDao.SelectNoticeList(params)
+	return s.noticeDao.SelectNoticeList(params, nil)
 }
 
 // SelectNoticeListWithDataScope 查询公告列表（支持数据权限） 对应Java后端的@DataScope注解
@@ -51,23 +59,9 @@ func (s *NoticeService) SelectNoticeListWithDataScope(currentUser *model.SysUser
 		params = &model.NoticeQueryParams{}
 	}
 
-	// 创建查询参数
-	dataScopeParams := make(map[string]any)
-
-	// 应用数据权限 - 通知公告主要基于创建者权限控制
+	// 应用数据权限 - 通知公告基于创建者权限控制
 	// 对应Java后端的@DataScope(deptAlias = "d", creatorAlias = "c")
-	err := datascope.ApplyDataScopeWithCreator(currentUser, "d", "", "c", "system:notice:list", dataScopeParams)
-	if err != nil {
-		return nil, fmt.Errorf("应用数据权限失败: %v", err)
-	}
-
-	// 将数据权限SQL设置到查询参数中
-	if dataScope, exists := dataScopeParams["dataScope"]; exists && dataScope != "" {
-		params.DataScope = fmt.Sprintf("%v", dataScope)
-		fmt.Printf("NoticeService.SelectNoticeListWithDataScope: 设置数据权限SQL: %s\n", params.DataScope)
-	}
-
-	return s.noticeDao.SelectNoticeList(params)
+	return s.noticeDao.SelectNoticeList(params, datascope.Build(currentUser, "system:notice:list"))
 }
 
 // CountNoticeList 统计公告总数 用于分页
@@ -78,7 +72,7 @@ func (s *NoticeService) CountNoticeList(params *model.NoticeQueryParams) (int64,
 		params = &model.NoticeQueryParams{}
 	}
 
-	return s.noticeDao.CountNoticeList(params)
+	return s.noticeDao.CountNoticeList(params, nil)
 }
 
 // InsertNotice 新增公告 对应Java后端的insertNotice
@@ -1,6 +1,7 @@
 package system
 
 import (
+	"context"
 	"fmt"
 	"time"
 	"wosm/internal/repository/dao"
@@ -9,13 +10,23 @@ import (
 
 // OperLogService 操作日志服务 对应Java后端的ISysOperLogService
 type OperLogService struct {
-	operLogDao *dao.OperLogDao
+	operLogDao    *dao.OperLogDao
+	dataChangeDao *dao.DataChangeDao
 }
 
 // NewOperLogService 创建操作日志服务实例
 func NewOperLogService() *OperLogService {
 	return &OperLogService{
-		operLogDao: dao.NewOperLogDao(),
+		operLogDao:    dao.NewOperLogDao(),
+		dataChangeDao: dao.NewDataChangeDao(),
+	}
+}
+
+// WithContext 返回使用指定context的服务实例，数据访问携带请求信息和租户
+func (s *OperLogService) WithContext(ctx context.Context) *OperLogService {
+	return &OperLogService{
+		operLogDao:    s.operLogDao.WithContext(ctx),
+		dataChangeDao: s.dataChangeDao.WithContext(ctx),
 	}
 }
 
@@ -39,7 +50,17 @@ func (s *OperLogService) InsertOperLog(operLog *model.SysOperLog) error {
 	now := time.Now()
 	operLog.OperTime = &now
 
-	return s.operLogDao.InsertOperLog(operLog)
+	if err := s.operLogDao.InsertOperLog(operLog); err != nil {
+		return err
+	}
+
+	// 同一请求中的数据变更记录关联到操作日志
+	if operLog.RequestID != "" {
+		if err := s.dataChangeDao.UpdateOperIdByRequestId(operLog.RequestID, operLog.OperID); err != nil {
+			fmt.Printf("OperLogService.InsertOperLog: 关联数据变更记录失败: %v\n", err)
+		}
+	}
+	return nil
 }
 
 // DeleteOperLogByIds 批量删除系统操作日志 对应Java后端的deleteOperLogByIds
@@ -1,6 +1,7 @@
 package system
 
 import (
+	"context"
 	"fmt"
 	"wosm/internal/repository/model"
 )
@@ -19,6 +20,14 @@ func NewPermissionService() *PermissionService {
 	}
 }
 
+// WithContext 返回使用指定context的服务实例，数据访问携带请求信息和租户
+func (s *PermissionService) WithContext(ctx context.Context) *PermissionService {
+	return &PermissionService{
+		roleService: s.roleService.WithContext(ctx),
+		menuService: s.menuService.WithContext(ctx),
+	}
+}
+
 // GetRolePermission 获取角色数据权限 对应Java后端的getRolePermission
 func (s *PermissionService) GetRolePermission(user *model.SysUser) ([]string, error) {
 	fmt.Printf("PermissionService.GetRolePermission: 获取用户角色权限, UserID=%d\n", user.UserID)
@@ -1,6 +1,7 @@
 package system
 
 import (
+	"context"
 	"fmt"
 	"time"
 	"wosm/internal/repository/dao"
@@ -19,6 +20,13 @@ func NewPostService() *PostService {
 	}
 }
 
+// WithContext 返回使用指定context的服务实例，数据访问携带请求信息和租户
+func (s *PostService) WithContext(ctx context.Context) *PostService {
+	return &PostService{
+		postDao: s.postDao.WithContext(ctx),
+	}
+}
+
 // SelectPostList 查询岗位信息集合 对应Java后端的selectPostList
 func (s *PostService) SelectPostList(post *model.SysPost) ([]model.SysPost, error) {
 	fmt.Printf("PostService.SelectPostList: 查询岗位列表\n")
@@ -0,0 +1,369 @@
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+	"wosm/internal/repository/dao"
+	"wosm/internal/repository/model"
+
+	"gorm.io/gorm"
+)
+
+// RecycleService 回收站服务 查看、恢复和彻底删除已删除的用户、部门和角色
+type RecycleService struct {
+	recycleDao *dao.RecycleDao
+	userDao    *dao.UserDao
+	deptDao    *dao.DeptDao
+	roleDao    *dao.RoleDao
+}
+
+// NewRecycleService 创建回收站服务实例
+func NewRecycleService() *RecycleService {
+	return &RecycleService{
+		recycleDao: dao.NewRecycleDao(),
+		userDao:    dao.NewUserDao(),
+		deptDao:    dao.NewDeptDao(),
+		roleDao:    dao.NewRoleDao(),
+	}
+}
+
+// WithContext 返回使用指定context的服务实例，数据访问携带请求信息和租户
+func (s *RecycleService) WithContext(ctx context.Context) *RecycleService {
+	return &RecycleService{
+		recycleDao: s.recycleDao.WithContext(ctx),
+		userDao:    s.userDao.WithContext(ctx),
+		deptDao:    s.deptDao.WithContext(ctx),
+		roleDao:    s.roleDao.WithContext(ctx),
+	}
+}
+
+// recordRecycle 删除用户、部门或角色后写入回收站，snapshot 为删除时解除的关联数据
+func recordRecycle(recycleDao *dao.RecycleDao, objectType string, objectId int64, objectName string, snapshot *model.RecycleSnapshot, deleteBy string) error {
+	recycle := &model.SysRecycle{
+		ObjectType: objectType,
+		ObjectID:   objectId,
+		ObjectName: objectName,
+		DeleteBy:   deleteBy,
+	}
+	if snapshot != nil {
+		data, err := json.Marshal(snapshot)
+		if err != nil {
+			return err
+		}
+		recycle.Snapshot = string(data)
+	}
+	now := time.Now()
+	recycle.DeleteTime = &now
+
+	return recycleDao.InsertRecycle(recycle)
+}
+
+// SelectRecycleList 分页查询回收站列表
+func (s *RecycleService) SelectRecycleList(recycle *model.SysRecycle, pageNum, pageSize int) ([]model.SysRecycle, int64, error) {
+	fmt.Printf("RecycleService.SelectRecycleList: 查询回收站列表, ObjectType=%s\n", recycle.ObjectType)
+	return s.recycleDao.SelectRecycleList(recycle, pageNum, pageSize)
+}
+
+// SelectRecycleById 查询回收站记录
+func (s *RecycleService) SelectRecycleById(recycleId int64) (*model.SysRecycle, error) {
+	return s.recycleDao.SelectRecycleById(recycleId)
+}
+
+// Restore 恢复回收站中的数据，与现有数据冲突时返回错误
+// 用户：登录账号、手机号码、邮箱不能与现有用户重复，所属部门必须存在
+// 部门：上级部门必须存在且正常，同一上级部门下名称不能重复
+// 角色：角色名称、权限字符不能与现有角色重复
+// 删除时解除的关联（用户的角色和岗位、角色的菜单和数据权限）一并恢复，已删除的关联对象会被跳过
+func (s *RecycleService) Restore(recycleId int64, operName string) (*model.SysRecycle, error) {
+	fmt.Printf("RecycleService.Restore: 恢复回收站数据, RecycleID=%d\n", recycleId)
+
+	recycle, err := s.recycleDao.SelectRecycleById(recycleId)
+	if err != nil {
+		return nil, err
+	}
+	if recycle == nil {
+		return nil, fmt.Errorf("回收站记录不存在")
+	}
+
+	var snapshot model.RecycleSnapshot
+	if recycle.Snapshot != "" {
+		if err := json.Unmarshal([]byte(recycle.Snapshot), &snapshot); err != nil {
+			return nil, fmt.Errorf("回收站关联数据格式错误: %v", err)
+		}
+	}
+
+	switch recycle.ObjectType {
+	case model.RecycleTypeUser:
+		err = s.restoreUser(recycle, &snapshot, operName)
+	case model.RecycleTypeDept:
+		err = s.restoreDept(recycle, operName)
+	case model.RecycleTypeRole:
+		err = s.restoreRole(recycle, &snapshot, operName)
+	default:
+		err = fmt.Errorf("不支持的对象类型: %s", recycle.ObjectType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("RecycleService.Restore: 恢复成功, ObjectType=%s, ObjectID=%d\n", recycle.ObjectType, recycle.ObjectID)
+	return recycle, nil
+}
+
+// restoreUser 恢复用户及其角色、岗位
+func (s *RecycleService) restoreUser(recycle *model.SysRecycle, snapshot *model.RecycleSnapshot, operName string) error {
+	user, err := s.recycleDao.SelectDeletedUser(recycle.ObjectID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return fmt.Errorf("用户'%s'已被恢复或彻底删除", recycle.ObjectName)
+	}
+
+	if unique, err := s.userDao.CheckLoginNameUnique(user.UserName, user.UserID); err != nil {
+		return err
+	} else if !unique {
+		return fmt.Errorf("恢复用户'%s'失败，登录账号已存在", user.UserName)
+	}
+	if user.Phonenumber != "" {
+		if unique, err := s.userDao.CheckPhoneUnique(user.Phonenumber, user.UserID); err != nil {
+			return err
+		} else if !unique {
+			return fmt.Errorf("恢复用户'%s'失败，手机号码已存在", user.UserName)
+		}
+	}
+	if user.Email != "" {
+		if unique, err := s.userDao.CheckEmailUnique(user.Email, user.UserID); err != nil {
+			return err
+		} else if !unique {
+			return fmt.Errorf("恢复用户'%s'失败，邮箱账号已存在", user.UserName)
+		}
+	}
+	if user.DeptID != nil && *user.DeptID > 0 {
+		deptIds, err := s.recycleDao.SelectNormalDeptIds([]int64{*user.DeptID})
+		if err != nil {
+			return err
+		}
+		if len(deptIds) == 0 {
+			return fmt.Errorf("恢复用户'%s'失败，所属部门不存在，请先恢复部门", user.UserName)
+		}
+	}
+
+	roleIds, err := s.recycleDao.SelectNormalRoleIds(snapshot.RoleIds)
+	if err != nil {
+		return err
+	}
+	postIds, err := s.recycleDao.SelectExistingPostIds(snapshot.PostIds)
+	if err != nil {
+		return err
+	}
+
+	return s.recycleDao.Transaction(func(tx *gorm.DB) error {
+		recycleDao := s.recycleDao.WithTx(tx)
+		if err := recycleDao.RestoreObject(model.RecycleTypeUser, user.UserID, operName); err != nil {
+			return err
+		}
+
+		userRoles := make([]model.SysUserRole, 0, len(roleIds))
+		for _, roleId := range roleIds {
+			userRoles = append(userRoles, model.SysUserRole{UserID: user.UserID, RoleID: roleId})
+		}
+		if err := dao.NewUserRoleDao().WithTx(tx).BatchInsertUserRole(userRoles); err != nil {
+			return err
+		}
+
+		userPosts := make([]model.SysUserPost, 0, len(postIds))
+		for _, postId := range postIds {
+			userPosts = append(userPosts, model.SysUserPost{UserID: user.UserID, PostID: postId})
+		}
+		if err := dao.NewUserPostDao().WithTx(tx).BatchInsertUserPost(userPosts); err != nil {
+			return err
+		}
+
+		return recycleDao.DeleteRecycleById(recycle.RecycleID)
+	})
+}
+
+// restoreDept 恢复部门，祖级列表按上级部门当前的位置重新计算
+func (s *RecycleService) restoreDept(recycle *model.SysRecycle, operName string) error {
+	dept, err := s.recycleDao.SelectDeletedDept(recycle.ObjectID)
+	if err != nil {
+		return err
+	}
+	if dept == nil {
+		return fmt.Errorf("部门'%s'已被恢复或彻底删除", recycle.ObjectName)
+	}
+
+	ancestors := dept.Ancestors
+	if dept.ParentID > 0 {
+		parentIds, err := s.recycleDao.SelectNormalDeptIds([]int64{dept.ParentID})
+		if err != nil {
+			return err
+		}
+		if len(parentIds) == 0 {
+			return fmt.Errorf("恢复部门'%s'失败，上级部门不存在，请先恢复上级部门", dept.DeptName)
+		}
+		parent, err := s.deptDao.SelectDeptById(dept.ParentID)
+		if err != nil {
+			return err
+		}
+		if parent.Status != "0" {
+			return fmt.Errorf("恢复部门'%s'失败，上级部门已停用", dept.DeptName)
+		}
+		ancestors = fmt.Sprintf("%s,%d", parent.Ancestors, dept.ParentID)
+	}
+
+	existDept, err := s.deptDao.CheckDeptNameUnique(dept.DeptName, dept.ParentID)
+	if err != nil {
+		return err
+	}
+	if existDept != nil {
+		return fmt.Errorf("恢复部门'%s'失败，部门名称已存在", dept.DeptName)
+	}
+
+	return s.recycleDao.Transaction(func(tx *gorm.DB) error {
+		recycleDao := s.recycleDao.WithTx(tx)
+		if err := recycleDao.RestoreObject(model.RecycleTypeDept, dept.DeptID, operName); err != nil {
+			return err
+		}
+		if ancestors != dept.Ancestors {
+			if err := dao.NewDeptDao().WithTx(tx).UpdateDept(&model.SysDept{DeptID: dept.DeptID, Ancestors: ancestors}); err != nil {
+				return err
+			}
+		}
+		return recycleDao.DeleteRecycleById(recycle.RecycleID)
+	})
+}
+
+// restoreRole 恢复角色及其菜单权限、数据权限
+func (s *RecycleService) restoreRole(recycle *model.SysRecycle, snapshot *model.RecycleSnapshot, operName string) error {
+	role, err := s.recycleDao.SelectDeletedRole(recycle.ObjectID)
+	if err != nil {
+		return err
+	}
+	if role == nil {
+		return fmt.Errorf("角色'%s'已被恢复或彻底删除", recycle.ObjectName)
+	}
+
+	if unique, err := s.roleDao.CheckRoleNameUnique(role.RoleName, role.RoleID); err != nil {
+		return err
+	} else if !unique {
+		return fmt.Errorf("恢复角色'%s'失败，角色名称已存在", role.RoleName)
+	}
+	if unique, err := s.roleDao.CheckRoleKeyUnique(role.RoleKey, role.RoleID); err != nil {
+		return err
+	} else if !unique {
+		return fmt.Errorf("恢复角色'%s'失败，角色权限已存在", role.RoleName)
+	}
+
+	menuIds, err := s.recycleDao.SelectExistingMenuIds(snapshot.MenuIds)
+	if err != nil {
+		return err
+	}
+	deptIds, err := s.recycleDao.SelectNormalDeptIds(snapshot.DeptIds)
+	if err != nil {
+		return err
+	}
+
+	return s.recycleDao.Transaction(func(tx *gorm.DB) error {
+		recycleDao := s.recycleDao.WithTx(tx)
+		if err := recycleDao.RestoreObject(model.RecycleTypeRole, role.RoleID, operName); err != nil {
+			return err
+		}
+
+		roleMenus := make([]model.SysRoleMenu, 0, len(menuIds))
+		for _, menuId := range menuIds {
+			roleMenus = append(roleMenus, model.SysRoleMenu{RoleID: role.RoleID, MenuID: menuId})
+		}
+		if err := dao.NewRoleMenuDao().WithTx(tx).BatchInsertRoleMenu(roleMenus); err != nil {
+			return err
+		}
+
+		roleDepts := make([]model.SysRoleDept, 0, len(deptIds))
+		for _, deptId := range deptIds {
+			roleDepts = append(roleDepts, model.SysRoleDept{RoleID: role.RoleID, DeptID: deptId})
+		}
+		if err := dao.NewRoleDeptDao().WithTx(tx).BatchInsertRoleDept(roleDepts); err != nil {
+			return err
+		}
+
+		return recycleDao.DeleteRecycleById(recycle.RecycleID)
+	})
+}
+
+// Purge 彻底删除回收站中的数据，数据已不是删除状态时（例如已在别处恢复）只移除回收站记录
+func (s *RecycleService) Purge(recycleId int64) (*model.SysRecycle, error) {
+	fmt.Printf("RecycleService.Purge: 彻底删除回收站数据, RecycleID=%d\n", recycleId)
+
+	recycle, err := s.recycleDao.SelectRecycleById(recycleId)
+	if err != nil {
+		return nil, err
+	}
+	if recycle == nil {
+		return nil, fmt.Errorf("回收站记录不存在")
+	}
+	if err := s.purge(recycle); err != nil {
+		return nil, err
+	}
+	return recycle, nil
+}
+
+// purge 彻底删除回收站记录对应的数据
+func (s *RecycleService) purge(recycle *model.SysRecycle) error {
+	deleted, err := s.isDeleted(recycle)
+	if err != nil {
+		return err
+	}
+
+	return s.recycleDao.Transaction(func(tx *gorm.DB) error {
+		recycleDao := s.recycleDao.WithTx(tx)
+		if deleted {
+			if err := recycleDao.PurgeObject(recycle.ObjectType, recycle.ObjectID); err != nil {
+				return err
+			}
+		}
+		return recycleDao.DeleteRecycleById(recycle.RecycleID)
+	})
+}
+
+// isDeleted 回收站记录对应的数据是否仍是删除状态
+func (s *RecycleService) isDeleted(recycle *model.SysRecycle) (bool, error) {
+	switch recycle.ObjectType {
+	case model.RecycleTypeUser:
+		user, err := s.recycleDao.SelectDeletedUser(recycle.ObjectID)
+		return user != nil, err
+	case model.RecycleTypeDept:
+		dept, err := s.recycleDao.SelectDeletedDept(recycle.ObjectID)
+		return dept != nil, err
+	case model.RecycleTypeRole:
+		role, err := s.recycleDao.SelectDeletedRole(recycle.ObjectID)
+		return role != nil, err
+	}
+	return false, fmt.Errorf("不支持的对象类型: %s", recycle.ObjectType)
+}
+
+// PurgeExpired 彻底删除超过保留天数的回收站数据，返回删除的数量 retentionDays 小于等于0时不清理
+func (s *RecycleService) PurgeExpired(retentionDays int) (int, error) {
+	if retentionDays <= 0 {
+		return 0, nil
+	}
+
+	before := time.Now().AddDate(0, 0, -retentionDays)
+	recycles, err := s.recycleDao.SelectExpiredRecycles(before)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for i := range recycles {
+		if err := s.purge(&recycles[i]); err != nil {
+			return purged, fmt.Errorf("彻底删除%s'%s'失败: %v", recycles[i].ObjectType, recycles[i].ObjectName, err)
+		}
+		purged++
+	}
+
+	fmt.Printf("RecycleService.PurgeExpired: 清理回收站完成, 保留天数=%d, 删除数量=%d\n", retentionDays, purged)
+	return purged, nil
+}
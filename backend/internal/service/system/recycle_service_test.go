@@ -0,0 +1,159 @@
+package system
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+	"wosm/internal/repository/dao"
+	"wosm/internal/repository/model"
+	"wosm/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// findRecycle 查询对象的回收站记录
+func findRecycle(t *testing.T, service *RecycleService, objectType string, objectId int64) *model.SysRecycle {
+	recycles, _, err := service.SelectRecycleList(&model.SysRecycle{ObjectType: objectType}, 1, 100)
+	require.NoError(t, err)
+	for i := range recycles {
+		if recycles[i].ObjectID == objectId {
+			return &recycles[i]
+		}
+	}
+	t.Fatalf("回收站中没有%s %d", objectType, objectId)
+	return nil
+}
+
+func TestRecycleRestoreUserAndRole(t *testing.T) {
+	db := testutil.OpenMigratedDB(t)
+	admin := &model.SysUser{UserID: 1, UserName: "admin"}
+	// 不使用 NewUserService，避免参数缓存访问Redis
+	userService := &UserService{
+		userDao:     dao.NewUserDao(),
+		userRoleDao: dao.NewUserRoleDao(),
+		userPostDao: dao.NewUserPostDao(),
+		recycleDao:  dao.NewRecycleDao(),
+	}
+	roleService := NewRoleService()
+	recycleService := NewRecycleService()
+
+	require.NoError(t, userService.DeleteUserByIds(admin, []int64{2}))
+	require.NoError(t, roleService.DeleteRoleById(admin, 2))
+
+	userRecycle := findRecycle(t, recycleService, model.RecycleTypeUser, 2)
+	assert.Equal(t, "ry", userRecycle.ObjectName)
+	assert.Equal(t, "admin", userRecycle.DeleteBy)
+	assert.NotNil(t, userRecycle.DeleteTime)
+	var snapshot model.RecycleSnapshot
+	require.NoError(t, json.Unmarshal([]byte(userRecycle.Snapshot), &snapshot))
+	assert.Equal(t, []int64{2}, snapshot.RoleIds)
+	assert.Equal(t, []int64{2}, snapshot.PostIds)
+	roleRecycle := findRecycle(t, recycleService, model.RecycleTypeRole, 2)
+
+	// 登录账号已被新用户使用
+	require.NoError(t, db.Exec("INSERT INTO sys_user (user_id, user_name, nick_name, del_flag) VALUES (3, 'ry', 'ry', '0')").Error)
+	_, err := recycleService.Restore(userRecycle.RecycleID, "admin")
+	assert.ErrorContains(t, err, "登录账号已存在")
+	require.NoError(t, db.Exec("DELETE FROM sys_user WHERE user_id = 3").Error)
+
+	// 先恢复角色，再恢复用户时重新关联角色
+	_, err = recycleService.Restore(roleRecycle.RecycleID, "admin")
+	require.NoError(t, err)
+	_, err = recycleService.Restore(userRecycle.RecycleID, "admin")
+	require.NoError(t, err)
+
+	user, err := dao.NewUserDao().SelectUserById(2)
+	require.NoError(t, err)
+	require.NotNil(t, user)
+	assert.Equal(t, "admin", user.UpdateBy)
+	roleIds, _ := dao.NewUserRoleDao().SelectRoleListByUserId(2)
+	assert.Equal(t, []int64{2}, roleIds)
+	postIds, _ := dao.NewUserPostDao().SelectPostListByUserId(2)
+	assert.Equal(t, []int64{2}, postIds)
+	menuIds, _ := dao.NewRoleMenuDao().SelectMenuListByRoleId(2, false)
+	assert.NotEmpty(t, menuIds)
+	deptIds, _ := dao.NewRoleDeptDao().SelectDeptListByRoleId(2)
+	assert.ElementsMatch(t, []int64{100, 101, 105}, deptIds)
+
+	_, total, _ := recycleService.SelectRecycleList(&model.SysRecycle{}, 1, 10)
+	assert.Zero(t, total)
+	_, err = recycleService.Restore(userRecycle.RecycleID, "admin")
+	assert.ErrorContains(t, err, "回收站记录不存在")
+}
+
+func TestRecycleRestoreDeptAndPurge(t *testing.T) {
+	db := testutil.OpenMigratedDB(t)
+	admin := &model.SysUser{UserID: 1, UserName: "admin"}
+	deptService := NewDeptService()
+	recycleService := NewRecycleService()
+
+	require.NoError(t, deptService.DeleteDeptById(admin, 109))
+	recycle := findRecycle(t, recycleService, model.RecycleTypeDept, 109)
+
+	// 上级部门已删除
+	require.NoError(t, db.Exec("UPDATE sys_dept SET del_flag = '2' WHERE dept_id = 102").Error)
+	_, err := recycleService.Restore(recycle.RecycleID, "admin")
+	assert.ErrorContains(t, err, "上级部门不存在")
+	require.NoError(t, db.Exec("UPDATE sys_dept SET del_flag = '0' WHERE dept_id = 102").Error)
+
+	// 同一上级部门下已有同名部门
+	require.NoError(t, db.Exec("INSERT INTO sys_dept (dept_id, parent_id, ancestors, dept_name, del_flag) VALUES (200, 102, '0,100,102', '财务部门', '0')").Error)
+	_, err = recycleService.Restore(recycle.RecycleID, "admin")
+	assert.ErrorContains(t, err, "部门名称已存在")
+	require.NoError(t, db.Exec("DELETE FROM sys_dept WHERE dept_id = 200").Error)
+
+	_, err = recycleService.Restore(recycle.RecycleID, "admin")
+	require.NoError(t, err)
+	dept, err := dao.NewDeptDao().SelectDeptById(109)
+	require.NoError(t, err)
+	assert.Equal(t, "0,100,102", dept.Ancestors)
+
+	// 彻底删除
+	require.NoError(t, deptService.DeleteDeptById(admin, 109))
+	recycle = findRecycle(t, recycleService, model.RecycleTypeDept, 109)
+	_, err = recycleService.Purge(recycle.RecycleID)
+	require.NoError(t, err)
+	var count int64
+	db.Table("sys_dept").Where("dept_id = 109").Count(&count)
+	assert.Zero(t, count)
+
+	// 超过保留天数的自动清理
+	require.NoError(t, deptService.DeleteDeptById(admin, 108))
+	purged, err := recycleService.PurgeExpired(30)
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+	require.NoError(t, db.Exec("UPDATE sys_recycle SET delete_time = ? WHERE object_id = 108", time.Now().AddDate(0, 0, -31)).Error)
+	purged, err = recycleService.PurgeExpired(-1)
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+	purged, err = recycleService.PurgeExpired(30)
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+	db.Table("sys_dept").Where("dept_id = 108").Count(&count)
+	assert.Zero(t, count)
+}
+
+func TestRecycleMigrationSeed(t *testing.T) {
+	db := testutil.OpenMigratedDB(t)
+
+	// 菜单ID由数据库生成，按钮挂在回收站菜单下
+	var menus []model.SysMenu
+	require.NoError(t, db.Where("perms LIKE ?", "system:recycle:%").Order("order_num").Find(&menus).Error)
+	require.Len(t, menus, 3)
+	parent := map[string]int64{}
+	var listId int64
+	for _, menu := range menus {
+		parent[menu.Perms] = menu.ParentID
+		if menu.Perms == "system:recycle:list" {
+			listId = menu.MenuID
+		}
+	}
+	assert.Equal(t, int64(1), parent["system:recycle:list"])
+	assert.Equal(t, listId, parent["system:recycle:restore"])
+	assert.Equal(t, listId, parent["system:recycle:remove"])
+
+	var jobs int64
+	require.NoError(t, db.Model(&model.SysJob{}).Where("invoke_target = ?", "recycleTask.purgeExpired").Count(&jobs).Error)
+	assert.Equal(t, int64(1), jobs)
+}
@@ -1,6 +1,7 @@
 package system
 
 import (
+	"context"
 	"fmt"
 	"time"
 	"wosm/internal/repository/model"
@@ -22,6 +23,14 @@ func NewRegisterService() *RegisterService {
 	}
 }
 
+// WithContext 返回使用指定context的服务实例，数据访问携带请求信息和租户
+func (s *RegisterService) WithContext(ctx context.Context) *RegisterService {
+	return &RegisterService{
+		userService:   s.userService.WithContext(ctx),
+		configService: s.configService.WithContext(ctx),
+	}
+}
+
 // Register 用户注册 对应Java后端的register方法
 func (s *RegisterService) Register(registerBody *model.RegisterBody) error {
 	fmt.Printf("RegisterService.Register: 用户注册, Username=%s\n", registerBody.Username)
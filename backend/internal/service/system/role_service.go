@@ -1,6 +1,7 @@
 package system
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -11,10 +12,12 @@ import (
 
 // RoleService 角色服务 对应Java后端的ISysRoleService
 type RoleService struct {
+	ctx         context.Context // 数据访问使用的context，方法中创建的其他服务使用该context
 	roleDao     *dao.RoleDao
 	roleMenuDao *dao.RoleMenuDao
 	roleDeptDao *dao.RoleDeptDao
 	userRoleDao *dao.UserRoleDao
+	recycleDao  *dao.RecycleDao
 }
 
 // NewRoleService 创建角色服务实例
@@ -24,19 +27,32 @@ func NewRoleService() *RoleService {
 		roleMenuDao: dao.NewRoleMenuDao(),
 		roleDeptDao: dao.NewRoleDeptDao(),
 		userRoleDao: dao.NewUserRoleDao(),
+		recycleDao:  dao.NewRecycleDao(),
+	}
+}
+
+// WithContext 返回使用指定context的服务实例，数据访问携带请求信息和租户
+func (s *RoleService) WithContext(ctx context.Context) *RoleService {
+	return &RoleService{
+		ctx:         ctx,
+		roleDao:     s.roleDao.WithContext(ctx),
+		roleMenuDao: s.roleMenuDao.WithContext(ctx),
+		roleDeptDao: s.roleDeptDao.WithContext(ctx),
+		userRoleDao: s.userRoleDao.WithContext(ctx),
+		recycleDao:  s.recycleDao.WithContext(ctx),
 	}
 }
 
 // SelectRoleList 查询角色列表 对应Java后端的selectRoleList
 func (s *RoleService) SelectRoleList(role *model.SysRole, pageNum, pageSize int) ([]model.SysRole, int64, error) {
 	fmt.Printf("RoleService.SelectRoleList: 查询角色列表, PageNum=%d, PageSize=%d\n", pageNum, pageSize)
-	return s.roleDao.SelectRoleListWithPage(role, pageNum, pageSize)
+	return s.roleDao.SelectRoleListWithPage(role, pageNum, pageSize, nil)
 }
 
 // SelectRoleListAll 查询所有角色列表（不分页） 对应Java后端的selectRoleList
 func (s *RoleService) SelectRoleListAll(role *model.SysRole) ([]model.SysRole, error) {
 	fmt.Printf("RoleService.SelectRoleListAll: 查询所有角色列表\n")
-	return s.roleDao.SelectRoleList(role)
+	return s.roleDao.SelectRoleList(role, nil)
 }
 
 // SelectRoleById 根据角色ID查询角色信息 对应Java后端的selectRoleById
@@ -102,7 +118,7 @@ func (s *RoleService) CheckRoleDataScope(currentUser *model.SysUser, roleIds ...
 	// 如果用户没有角色信息，需要重新加载
 	if len(currentUser.Roles) == 0 {
 		fmt.Printf("RoleService.CheckRoleDataScope: 用户角色信息为空，重新加载用户信息\n")
-		userService := NewUserService()
+		userService := NewUserService().WithContext(s.ctx)
 		fullUser, err := userService.SelectUserById(currentUser.UserID)
 		if err != nil {
 			return fmt.Errorf("重新加载用户信息失败: %v", err)
@@ -140,30 +156,12 @@ func (s *RoleService) CheckRoleDataScope(currentUser *model.SysUser, roleIds ...
 func (s *RoleService) SelectRoleListWithDataScope(currentUser *model.SysUser, queryRole *model.SysRole) ([]model.SysRole, error) {
 	fmt.Printf("RoleService.SelectRoleListWithDataScope: 查询角色列表（数据权限）\n")
 
-	// 创建查询参数
-	params := make(map[string]interface{})
-
-	// 应用数据权限 对应Java后端的@DataScope(deptAlias = "d")
-	err := datascope.ApplyDataScope(currentUser, "d", "", "system:role:list", params)
-	if err != nil {
-		return nil, fmt.Errorf("应用数据权限失败: %v", err)
-	}
-
-	// 将数据权限SQL设置到查询角色对象中
 	if queryRole == nil {
 		queryRole = &model.SysRole{}
 	}
-	if queryRole.Params == nil {
-		queryRole.Params = make(map[string]interface{})
-	}
 
-	// 复制数据权限参数
-	for key, value := range params {
-		queryRole.Params[key] = value
-	}
-
-	// 调用DAO层查询
-	return s.roleDao.SelectRoleList(queryRole)
+	// 应用数据权限 对应Java后端的@DataScope(deptAlias = "d")
+	return s.roleDao.SelectRoleList(queryRole, datascope.Build(currentUser, "system:role:list"))
 }
 
 // InsertRole 新增角色 对应Java后端的insertRole
@@ -262,6 +260,12 @@ func (s *RoleService) DeleteRoleById(currentUser *model.SysUser, roleId int64) e
 		return fmt.Errorf("%s已分配,不能删除", roleInfo.RoleName)
 	}
 
+	// 记录删除前的菜单和数据权限，从回收站恢复时重新关联
+	snapshot, err := s.roleSnapshot(roleId)
+	if err != nil {
+		return err
+	}
+
 	// 删除角色与菜单关联
 	err = s.roleMenuDao.DeleteRoleMenuByRoleId(roleId)
 	if err != nil {
@@ -275,7 +279,12 @@ func (s *RoleService) DeleteRoleById(currentUser *model.SysUser, roleId int64) e
 	}
 
 	// 删除角色信息
-	return s.roleDao.DeleteRoleById(roleId)
+	if err := s.roleDao.DeleteRoleById(roleId); err != nil {
+		return err
+	}
+
+	// 写入回收站
+	return recordRecycle(s.recycleDao, model.RecycleTypeRole, roleId, roleInfo.RoleName, snapshot, currentUser.UserName)
 }
 
 // DeleteRoleByIds 批量删除角色 对应Java后端的deleteRoleByIds
@@ -283,6 +292,8 @@ func (s *RoleService) DeleteRoleByIds(currentUser *model.SysUser, roleIds []int6
 	fmt.Printf("RoleService.DeleteRoleByIds: 批量删除角色, CurrentUserID=%d, RoleIDs=%v\n", currentUser.UserID, roleIds)
 
 	// 校验每个角色 对应Java后端的for (Long roleId : roleIds)
+	roleNames := make(map[int64]string, len(roleIds))
+	snapshots := make(map[int64]*model.RecycleSnapshot, len(roleIds))
 	for _, roleId := range roleIds {
 		// 校验角色是否允许操作 对应Java后端的checkRoleAllowed(new SysRole(roleId))
 		role := &model.SysRole{RoleID: roleId}
@@ -312,6 +323,14 @@ func (s *RoleService) DeleteRoleByIds(currentUser *model.SysUser, roleIds []int6
 		if count > 0 {
 			return fmt.Errorf("%s已分配,不能删除", roleInfo.RoleName)
 		}
+
+		// 记录删除前的菜单和数据权限，从回收站恢复时重新关联
+		snapshot, err := s.roleSnapshot(roleId)
+		if err != nil {
+			return err
+		}
+		roleNames[roleId] = roleInfo.RoleName
+		snapshots[roleId] = snapshot
 	}
 
 	// 删除角色与菜单关联
@@ -329,7 +348,30 @@ func (s *RoleService) DeleteRoleByIds(currentUser *model.SysUser, roleIds []int6
 	}
 
 	// 删除角色信息
-	return s.roleDao.DeleteRoleByIds(roleIds)
+	if err := s.roleDao.DeleteRoleByIds(roleIds); err != nil {
+		return err
+	}
+
+	// 写入回收站
+	for _, roleId := range roleIds {
+		if err := recordRecycle(s.recycleDao, model.RecycleTypeRole, roleId, roleNames[roleId], snapshots[roleId], currentUser.UserName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// roleSnapshot 查询角色的菜单权限和数据权限部门，用于写入回收站
+func (s *RoleService) roleSnapshot(roleId int64) (*model.RecycleSnapshot, error) {
+	menuIds, err := s.roleMenuDao.SelectMenuListByRoleId(roleId, false)
+	if err != nil {
+		return nil, err
+	}
+	deptIds, err := s.roleDeptDao.SelectDeptListByRoleId(roleId)
+	if err != nil {
+		return nil, err
+	}
+	return &model.RecycleSnapshot{MenuIds: menuIds, DeptIds: deptIds}, nil
 }
 
 // AuthDataScope 修改角色数据权限 对应Java后端的authDataScope
@@ -0,0 +1,344 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+	"wosm/internal/repository/dao"
+	"wosm/internal/repository/model"
+	"wosm/internal/utils"
+	"wosm/pkg/database"
+
+	"gorm.io/gorm"
+)
+
+// ErrPlatformOnly 租户修改平台统一维护的数据（菜单、字典类型）
+var ErrPlatformOnly = errors.New("该数据由平台统一维护，租户不能修改")
+
+// checkPlatformTenant 只有平台租户可以修改所有租户共用的数据，未开启租户模式时context没有租户不做限制
+func checkPlatformTenant(ctx context.Context) error {
+	if current := database.RequestTenant(ctx); current != 0 && current != model.DefaultTenantID {
+		return ErrPlatformOnly
+	}
+	return nil
+}
+
+// isOtherTenantRow 共享平台租户数据的表（参数、字典数据）中的行是否不属于context中的租户
+func isOtherTenantRow(ctx context.Context, tenantID int64) bool {
+	current := database.RequestTenant(ctx)
+	return current != 0 && tenantID != current
+}
+
+// TenantService 租户服务 管理租户和租户套餐，新增租户时初始化租户的部门、管理员角色和管理员账号
+type TenantService struct {
+	ctx       context.Context // 数据访问使用的context，方法中创建的其他服务使用该context
+	tenantDao *dao.TenantDao
+}
+
+// NewTenantService 创建租户服务实例
+func NewTenantService() *TenantService {
+	return &TenantService{
+		tenantDao: dao.NewTenantDao(),
+	}
+}
+
+// WithContext 返回使用指定context的服务实例，数据访问携带请求信息和租户
+func (s *TenantService) WithContext(ctx context.Context) *TenantService {
+	return &TenantService{
+		ctx:       ctx,
+		tenantDao: s.tenantDao.WithContext(ctx),
+	}
+}
+
+// SelectTenantList 分页查询租户列表
+func (s *TenantService) SelectTenantList(params *model.TenantQueryParams) ([]model.SysTenant, int64, error) {
+	fmt.Printf("TenantService.SelectTenantList: 查询租户列表\n")
+	return s.tenantDao.SelectTenantList(params)
+}
+
+// SelectTenantAll 查询所有正常状态的租户
+func (s *TenantService) SelectTenantAll() ([]model.SysTenant, error) {
+	return s.tenantDao.SelectTenantAll()
+}
+
+// SelectTenantById 根据租户ID查询租户
+func (s *TenantService) SelectTenantById(tenantId int64) (*model.SysTenant, error) {
+	fmt.Printf("TenantService.SelectTenantById: 查询租户详情, TenantID=%d\n", tenantId)
+	return s.tenantDao.SelectTenantById(tenantId)
+}
+
+// checkTenantUnique 校验租户名称和绑定域名
+func (s *TenantService) checkTenantUnique(tenant *model.SysTenant, action string) error {
+	unique, err := s.tenantDao.CheckTenantNameUnique(tenant.TenantName, tenant.TenantID)
+	if err != nil {
+		return fmt.Errorf("校验租户名称失败: %v", err)
+	}
+	if !unique {
+		return fmt.Errorf("%s租户'%s'失败，租户名称已存在", action, tenant.TenantName)
+	}
+	if tenant.Domain != "" {
+		unique, err = s.tenantDao.CheckTenantDomainUnique(tenant.Domain, tenant.TenantID)
+		if err != nil {
+			return fmt.Errorf("校验绑定域名失败: %v", err)
+		}
+		if !unique {
+			return fmt.Errorf("%s租户'%s'失败，绑定域名已存在", action, tenant.TenantName)
+		}
+	}
+	if tenant.PackageID > 0 {
+		pkg, err := s.tenantDao.SelectPackageById(tenant.PackageID)
+		if err != nil {
+			return err
+		}
+		if pkg == nil {
+			return fmt.Errorf("%s租户'%s'失败，租户套餐不存在", action, tenant.TenantName)
+		}
+	}
+	return nil
+}
+
+// InsertTenant 新增租户，在一个事务中创建租户的根部门、管理员角色和管理员账号，管理员角色拥有套餐的全部菜单
+func (s *TenantService) InsertTenant(tenant *model.SysTenant, createBy string) error {
+	fmt.Printf("TenantService.InsertTenant: 新增租户, TenantName=%s\n", tenant.TenantName)
+
+	tenant.TenantID = 0
+	if err := s.checkTenantUnique(tenant, "新增"); err != nil {
+		return err
+	}
+	if tenant.AdminUserName == "" || tenant.AdminPassword == "" {
+		return fmt.Errorf("新增租户'%s'失败，管理员账号和密码不能为空", tenant.TenantName)
+	}
+	if len(tenant.AdminPassword) < 5 || len(tenant.AdminPassword) > 20 {
+		return fmt.Errorf("新增租户'%s'失败，管理员密码长度必须在5到20个字符之间", tenant.TenantName)
+	}
+	hashedPassword, err := utils.BcryptPassword(tenant.AdminPassword)
+	if err != nil {
+		return fmt.Errorf("密码加密失败: %v", err)
+	}
+
+	now := time.Now()
+	tenant.Status = "0"
+	tenant.DelFlag = "0"
+	tenant.CreateBy = createBy
+	tenant.CreateTime = &now
+
+	return s.tenantDao.Transaction(func(tx *gorm.DB) error {
+		tenantDao := s.tenantDao.WithTx(tx)
+		if err := tenantDao.InsertTenant(tenant); err != nil {
+			return err
+		}
+		menuIds, err := tenantDao.SelectTenantMenuIds(tenant.PackageID)
+		if err != nil {
+			return err
+		}
+		admin := &model.SysUser{
+			UserName: tenant.AdminUserName,
+			NickName: tenant.AdminUserName,
+			Password: hashedPassword,
+			Status:   "0",
+			DelFlag:  "0",
+		}
+		return tenantDao.InitTenantData(tenant, admin, menuIds)
+	})
+}
+
+// UpdateTenant 修改租户，平台租户不能停用；套餐变化时同步租户角色的菜单
+func (s *TenantService) UpdateTenant(tenant *model.SysTenant, updateBy string) error {
+	fmt.Printf("TenantService.UpdateTenant: 修改租户, TenantID=%d\n", tenant.TenantID)
+
+	existing, err := s.tenantDao.SelectTenantById(tenant.TenantID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return fmt.Errorf("租户不存在")
+	}
+	if existing.IsPlatform() {
+		if tenant.Status != "0" {
+			return fmt.Errorf("不允许停用平台租户")
+		}
+		tenant.PackageID = 0
+		tenant.ExpireTime = nil
+	}
+	if err := s.checkTenantUnique(tenant, "修改"); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	tenant.UpdateBy = updateBy
+	tenant.UpdateTime = &now
+
+	return s.tenantDao.Transaction(func(tx *gorm.DB) error {
+		tenantDao := s.tenantDao.WithTx(tx)
+		if err := tenantDao.UpdateTenant(tenant); err != nil {
+			return err
+		}
+		if existing.PackageID == tenant.PackageID || tenant.PackageID == 0 {
+			return nil
+		}
+		menuIds, err := tenantDao.SelectPackageMenuIds(tenant.PackageID)
+		if err != nil {
+			return err
+		}
+		return tenantDao.SyncPackageRoleMenus(tenant.PackageID, menuIds)
+	})
+}
+
+// DeleteTenantByIds 批量删除租户，平台租户不能删除
+func (s *TenantService) DeleteTenantByIds(tenantIds []int64) error {
+	fmt.Printf("TenantService.DeleteTenantByIds: 删除租户, TenantIDs=%v\n", tenantIds)
+
+	for _, tenantId := range tenantIds {
+		if tenantId == model.DefaultTenantID {
+			return fmt.Errorf("不允许删除平台租户")
+		}
+	}
+	return s.tenantDao.DeleteTenantByIds(tenantIds)
+}
+
+// ResolveLoginTenant 确定登录的租户：优先使用登录时选择的租户，其次按访问域名匹配绑定域名，都没有时为平台租户
+func (s *TenantService) ResolveLoginTenant(tenantId int64, host string) (int64, error) {
+	var tenant *model.SysTenant
+	var err error
+	if tenantId > 0 {
+		tenant, err = s.tenantDao.SelectTenantById(tenantId)
+	} else {
+		if h, _, splitErr := net.SplitHostPort(host); splitErr == nil {
+			host = h
+		}
+		host = strings.ToLower(strings.TrimSpace(host))
+		if host != "" {
+			tenant, err = s.tenantDao.SelectTenantByDomain(host)
+		}
+		if err == nil && tenant == nil {
+			tenant, err = s.tenantDao.SelectTenantById(model.DefaultTenantID)
+		}
+	}
+	if err != nil {
+		return 0, err
+	}
+	if tenant == nil {
+		return 0, fmt.Errorf("租户不存在")
+	}
+	if tenant.Status != "0" {
+		return 0, fmt.Errorf("租户已停用")
+	}
+	if tenant.IsExpired() {
+		return 0, fmt.Errorf("租户已过期")
+	}
+	return tenant.TenantID, nil
+}
+
+// SelectPackageList 分页查询租户套餐列表
+func (s *TenantService) SelectPackageList(params *model.TenantPackageQueryParams) ([]model.SysTenantPackage, int64, error) {
+	fmt.Printf("TenantService.SelectPackageList: 查询套餐列表\n")
+	return s.tenantDao.SelectPackageList(params)
+}
+
+// SelectPackageAll 查询所有正常状态的套餐
+func (s *TenantService) SelectPackageAll() ([]model.SysTenantPackage, error) {
+	return s.tenantDao.SelectPackageAll()
+}
+
+// SelectPackageById 根据套餐ID查询套餐及其菜单
+func (s *TenantService) SelectPackageById(packageId int64) (*model.SysTenantPackage, error) {
+	fmt.Printf("TenantService.SelectPackageById: 查询套餐详情, PackageID=%d\n", packageId)
+
+	pkg, err := s.tenantDao.SelectPackageById(packageId)
+	if err != nil || pkg == nil {
+		return pkg, err
+	}
+	pkg.MenuIds, err = s.tenantDao.SelectPackageMenuIds(packageId)
+	if err != nil {
+		return nil, err
+	}
+	return pkg, nil
+}
+
+// InsertPackage 新增套餐
+func (s *TenantService) InsertPackage(pkg *model.SysTenantPackage, createBy string) error {
+	fmt.Printf("TenantService.InsertPackage: 新增套餐, PackageName=%s\n", pkg.PackageName)
+
+	unique, err := s.tenantDao.CheckPackageNameUnique(pkg.PackageName, 0)
+	if err != nil {
+		return fmt.Errorf("校验套餐名称失败: %v", err)
+	}
+	if !unique {
+		return fmt.Errorf("新增套餐'%s'失败，套餐名称已存在", pkg.PackageName)
+	}
+
+	now := time.Now()
+	pkg.PackageID = 0
+	pkg.DelFlag = "0"
+	pkg.CreateBy = createBy
+	pkg.CreateTime = &now
+	if pkg.MenuIds, err = s.tenantDao.ExcludePlatformMenus(pkg.MenuIds); err != nil {
+		return err
+	}
+	if pkg.Status == "" {
+		pkg.Status = "0"
+	}
+
+	return s.tenantDao.Transaction(func(tx *gorm.DB) error {
+		return s.tenantDao.WithTx(tx).InsertPackage(pkg)
+	})
+}
+
+// UpdatePackage 修改套餐，同时同步使用该套餐的租户的角色菜单
+func (s *TenantService) UpdatePackage(pkg *model.SysTenantPackage, updateBy string) error {
+	fmt.Printf("TenantService.UpdatePackage: 修改套餐, PackageID=%d\n", pkg.PackageID)
+
+	existing, err := s.tenantDao.SelectPackageById(pkg.PackageID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return fmt.Errorf("套餐不存在")
+	}
+	unique, err := s.tenantDao.CheckPackageNameUnique(pkg.PackageName, pkg.PackageID)
+	if err != nil {
+		return fmt.Errorf("校验套餐名称失败: %v", err)
+	}
+	if !unique {
+		return fmt.Errorf("修改套餐'%s'失败，套餐名称已存在", pkg.PackageName)
+	}
+
+	now := time.Now()
+	pkg.UpdateBy = updateBy
+	pkg.UpdateTime = &now
+	if pkg.MenuIds, err = s.tenantDao.ExcludePlatformMenus(pkg.MenuIds); err != nil {
+		return err
+	}
+
+	return s.tenantDao.Transaction(func(tx *gorm.DB) error {
+		tenantDao := s.tenantDao.WithTx(tx)
+		if err := tenantDao.UpdatePackage(pkg); err != nil {
+			return err
+		}
+		return tenantDao.SyncPackageRoleMenus(pkg.PackageID, pkg.MenuIds)
+	})
+}
+
+// DeletePackageByIds 批量删除套餐，有租户使用的套餐不能删除
+func (s *TenantService) DeletePackageByIds(packageIds []int64) error {
+	fmt.Printf("TenantService.DeletePackageByIds: 删除套餐, PackageIDs=%v\n", packageIds)
+
+	for _, packageId := range packageIds {
+		count, err := s.tenantDao.CountTenantByPackageId(packageId)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			pkg, _ := s.tenantDao.SelectPackageById(packageId)
+			name := fmt.Sprintf("%d", packageId)
+			if pkg != nil {
+				name = pkg.PackageName
+			}
+			return fmt.Errorf("%s已分配给租户,不能删除", name)
+		}
+	}
+	return s.tenantDao.DeletePackageByIds(packageIds)
+}
@@ -0,0 +1,207 @@
+package system
+
+import (
+	"context"
+	"testing"
+	"time"
+	"wosm/internal/repository/dao"
+	"wosm/internal/repository/model"
+	"wosm/internal/testutil"
+	"wosm/pkg/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// openTenantDB 创建执行过全部迁移并注册了多租户插件的SQLite内存库
+func openTenantDB(t *testing.T) *gorm.DB {
+	db := testutil.OpenMigratedDB(t)
+	require.NoError(t, db.Use(dao.NewTenantPlugin()))
+	return db
+}
+
+func TestTenantIsolation(t *testing.T) {
+	db := openTenantDB(t)
+	platformCtx := database.WithRequestTenant(context.Background(), model.DefaultTenantID)
+	service := NewTenantService().WithContext(platformCtx)
+
+	// 套餐不包含租户管理菜单及其按钮
+	var tenantMenus []int64
+	require.NoError(t, db.Model(&model.SysMenu{}).Where("perms IN ?", []string{"system:tenant:list", "system:tenant:query"}).
+		Pluck("menu_id", &tenantMenus).Error)
+	require.Len(t, tenantMenus, 2)
+	pkg := &model.SysTenantPackage{PackageName: "基础套餐", MenuIds: append([]int64{1, 100, 1000}, tenantMenus...)}
+	require.NoError(t, service.InsertPackage(pkg, "admin"))
+	pkg, err := service.SelectPackageById(pkg.PackageID)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 100, 1000}, pkg.MenuIds)
+
+	tenant := &model.SysTenant{TenantName: "测试租户", Domain: "demo.example.com", PackageID: pkg.PackageID,
+		AdminUserName: "admin", AdminPassword: "admin123"}
+	require.NoError(t, service.InsertTenant(tenant, "admin"))
+	require.Greater(t, tenant.TenantID, model.DefaultTenantID)
+
+	// 租户的管理员与平台的admin同名，按租户区分
+	tenantCtx := database.WithRequestTenant(context.Background(), tenant.TenantID)
+	userDao := dao.NewUserDao().WithContext(tenantCtx)
+	tenantAdmin, err := userDao.SelectUserByLoginName("admin")
+	require.NoError(t, err)
+	require.NotNil(t, tenantAdmin)
+	assert.Equal(t, tenant.TenantID, tenantAdmin.TenantID)
+	assert.NotEqual(t, int64(1), tenantAdmin.UserID)
+	ry, err := userDao.SelectUserByLoginName("ry")
+	require.NoError(t, err)
+	assert.Nil(t, ry)
+
+	var roleId int64
+	require.NoError(t, db.Model(&model.SysUserRole{}).Where("user_id = ?", tenantAdmin.UserID).Pluck("role_id", &roleId).Error)
+	menuIds, err := dao.NewRoleMenuDao().SelectMenuListByRoleId(roleId, false)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []int64{1, 100, 1000}, menuIds)
+
+	// 新增的数据属于当前租户，忽略提交的租户ID
+	postDao := dao.NewPostDao().WithContext(tenantCtx)
+	posts, err := postDao.SelectPostAll()
+	require.NoError(t, err)
+	assert.Empty(t, posts)
+	post := &model.SysPost{TenantID: model.DefaultTenantID, PostCode: "dev", PostName: "开发", PostSort: 1, Status: "0"}
+	require.NoError(t, postDao.InsertPost(post))
+	assert.Equal(t, tenant.TenantID, post.TenantID)
+
+	// 租户不能修改和删除其他租户的数据
+	require.NoError(t, db.WithContext(tenantCtx).Model(&model.SysPost{}).Where("post_id = ?", 1).Update("post_name", "被修改").Error)
+	require.NoError(t, db.WithContext(tenantCtx).Where("post_id = ?", 2).Delete(&model.SysPost{}).Error)
+	assert.Equal(t, ErrPlatformOnly, NewMenuService().WithContext(tenantCtx).InsertMenu(&model.SysMenu{MenuName: "租户菜单"}))
+
+	postDao = dao.NewPostDao().WithContext(platformCtx)
+	posts, err = postDao.SelectPostAll()
+	require.NoError(t, err)
+	assert.Len(t, posts, 4)
+	platformPost, err := postDao.SelectPostById(1)
+	require.NoError(t, err)
+	assert.Equal(t, "董事长", platformPost.PostName)
+	platformAdmin, err := dao.NewUserDao().WithContext(platformCtx).SelectUserByLoginName("admin")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), platformAdmin.UserID)
+}
+
+func TestTenantConfigOverride(t *testing.T) {
+	openTenantDB(t)
+	platformCtx := database.WithRequestTenant(context.Background(), model.DefaultTenantID)
+	service := NewTenantService().WithContext(platformCtx)
+	tenant := &model.SysTenant{TenantName: "测试租户", AdminUserName: "admin", AdminPassword: "admin123"}
+	require.NoError(t, service.InsertTenant(tenant, "admin"))
+
+	// 租户未覆盖时使用平台的参数
+	tenantCtx := database.WithRequestTenant(context.Background(), tenant.TenantID)
+	configDao := dao.NewConfigDao().WithContext(tenantCtx)
+	config, err := configDao.SelectConfigByKey("sys.index.skinName")
+	require.NoError(t, err)
+	assert.Equal(t, "skin-blue", config.ConfigValue)
+	assert.True(t, isOtherTenantRow(tenantCtx, config.TenantID))
+
+	// 同键名的参数在租户内唯一，租户新增后覆盖平台的值
+	unique, err := configDao.CheckConfigKeyUnique("sys.index.skinName", 0)
+	require.NoError(t, err)
+	assert.True(t, unique)
+	require.NoError(t, configDao.InsertConfig(&model.SysConfig{ConfigName: "皮肤", ConfigKey: "sys.index.skinName", ConfigValue: "skin-red", ConfigType: "Y"}))
+	config, err = configDao.SelectConfigByKey("sys.index.skinName")
+	require.NoError(t, err)
+	assert.Equal(t, "skin-red", config.ConfigValue)
+	assert.False(t, isOtherTenantRow(tenantCtx, config.TenantID))
+
+	config, err = dao.NewConfigDao().WithContext(platformCtx).SelectConfigByKey("sys.index.skinName")
+	require.NoError(t, err)
+	assert.Equal(t, "skin-blue", config.ConfigValue)
+}
+
+func TestResolveLoginTenant(t *testing.T) {
+	openTenantDB(t)
+	service := NewTenantService().WithContext(database.WithRequestTenant(context.Background(), model.DefaultTenantID))
+	expired := time.Now().Add(-time.Hour)
+	active := &model.SysTenant{TenantName: "正常租户", Domain: "a.example.com", AdminUserName: "admin", AdminPassword: "admin123"}
+	require.NoError(t, service.InsertTenant(active, "admin"))
+	outdated := &model.SysTenant{TenantName: "过期租户", AdminUserName: "admin", AdminPassword: "admin123", ExpireTime: &expired}
+	require.NoError(t, service.InsertTenant(outdated, "admin"))
+
+	tenantId, err := service.ResolveLoginTenant(0, "a.example.com:8080")
+	require.NoError(t, err)
+	assert.Equal(t, active.TenantID, tenantId)
+	tenantId, err = service.ResolveLoginTenant(0, "localhost:8080")
+	require.NoError(t, err)
+	assert.Equal(t, model.DefaultTenantID, tenantId)
+	_, err = service.ResolveLoginTenant(outdated.TenantID, "")
+	assert.EqualError(t, err, "租户已过期")
+	_, err = service.ResolveLoginTenant(99, "")
+	assert.EqualError(t, err, "租户不存在")
+
+	assert.EqualError(t, service.DeleteTenantByIds([]int64{model.DefaultTenantID}), "不允许删除平台租户")
+}
+
+func TestTenantFailClosed(t *testing.T) {
+	db := openTenantDB(t)
+	platformCtx := database.WithRequestTenant(context.Background(), model.DefaultTenantID)
+
+	// 未确定租户时不能访问按租户隔离的表
+	_, err := dao.NewPostDao().WithContext(context.Background()).SelectPostAll()
+	assert.ErrorContains(t, err, dao.ErrTenantRequired.Error())
+	err = db.WithContext(context.Background()).Model(&model.SysPost{}).Where("post_id = ?", 1).Update("post_name", "被修改").Error
+	assert.ErrorIs(t, err, dao.ErrTenantRequired)
+	assert.ErrorIs(t, db.Create(&model.SysPost{PostCode: "dev", PostName: "开发", PostSort: 1, Status: "0"}).Error, dao.ErrTenantRequired)
+
+	// 共享平台租户数据的表只返回平台租户的行
+	config, err := dao.NewConfigDao().WithContext(context.Background()).SelectConfigByKey("sys.index.skinName")
+	require.NoError(t, err)
+	assert.Equal(t, model.DefaultTenantID, config.TenantID)
+
+	// 原生SQL不能加入租户条件，访问按租户隔离的表时必须使用IgnoreTenant
+	var count int64
+	err = db.WithContext(platformCtx).Raw("SELECT COUNT(*) FROM sys_user").Scan(&count).Error
+	assert.ErrorIs(t, err, dao.ErrTenantRawSQL)
+	_, err = db.WithContext(platformCtx).Raw("SELECT u.user_id FROM sys_user_role ur LEFT JOIN `sys_user` u ON u.user_id = ur.user_id").Rows()
+	assert.ErrorIs(t, err, dao.ErrTenantRawSQL)
+	assert.ErrorIs(t, db.WithContext(platformCtx).Exec("DELETE FROM sys_post WHERE post_id = ?", 1).Error, dao.ErrTenantRawSQL)
+	require.NoError(t, dao.IgnoreTenant(db.WithContext(platformCtx)).Raw("SELECT COUNT(*) FROM sys_user").Scan(&count).Error)
+	assert.Positive(t, count)
+	require.NoError(t, db.WithContext(platformCtx).Raw("SELECT COUNT(*) FROM sys_menu").Scan(&count).Error)
+	_, err = dao.NewMenuDao().WithContext(platformCtx).SelectMenuTreeByUserId(2)
+	require.NoError(t, err)
+
+	// 平台级的跨租户处理访问全部租户的数据
+	allCtx := database.WithAllTenants(context.Background())
+	posts, err := dao.NewPostDao().WithContext(allCtx).SelectPostAll()
+	require.NoError(t, err)
+	assert.Len(t, posts, 4)
+	_, err = NewRecycleService().WithContext(allCtx).PurgeExpired(30)
+	require.NoError(t, err)
+}
+
+func TestTenantCleanLogs(t *testing.T) {
+	openTenantDB(t)
+	platformCtx := database.WithRequestTenant(context.Background(), model.DefaultTenantID)
+	tenant := &model.SysTenant{TenantName: "测试租户", AdminUserName: "admin", AdminPassword: "admin123"}
+	require.NoError(t, NewTenantService().WithContext(platformCtx).InsertTenant(tenant, "admin"))
+	tenantCtx := database.WithRequestTenant(context.Background(), tenant.TenantID)
+
+	for _, ctx := range []context.Context{platformCtx, tenantCtx} {
+		require.NoError(t, dao.NewOperLogDao().WithContext(ctx).InsertOperLog(&model.SysOperLog{Title: "岗位管理", OperName: "admin"}))
+		require.NoError(t, dao.NewLoginLogDao().WithContext(ctx).InsertLogininfor(&model.SysLogininfor{UserName: "admin", Status: "0"}))
+	}
+
+	// 租户清空日志时只删除本租户的日志
+	require.NoError(t, dao.NewOperLogDao().WithContext(tenantCtx).CleanOperLog())
+	require.NoError(t, dao.NewLoginLogDao().WithContext(tenantCtx).CleanLogininfor())
+	operLogs, err := dao.NewOperLogDao().WithContext(tenantCtx).SelectOperLogList(&model.SysOperLog{})
+	require.NoError(t, err)
+	assert.Empty(t, operLogs)
+	operLogs, err = dao.NewOperLogDao().WithContext(platformCtx).SelectOperLogList(&model.SysOperLog{})
+	require.NoError(t, err)
+	assert.Len(t, operLogs, 1)
+	loginLogs, err := dao.NewLoginLogDao().WithContext(tenantCtx).SelectLogininforList(&model.SysLogininfor{})
+	require.NoError(t, err)
+	assert.Empty(t, loginLogs)
+	loginLogs, err = dao.NewLoginLogDao().WithContext(platformCtx).SelectLogininforList(&model.SysLogininfor{})
+	require.NoError(t, err)
+	assert.Len(t, loginLogs, 1)
+}
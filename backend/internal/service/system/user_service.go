@@ -1,6 +1,7 @@
 package system
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
@@ -13,12 +14,14 @@ import (
 
 // UserService 用户服务 对应Java后端的SysUserServiceImpl
 type UserService struct {
+	ctx           context.Context // 数据访问使用的context，方法中创建的其他服务使用该context
 	userDao       *dao.UserDao
 	userRoleDao   *dao.UserRoleDao
 	userPostDao   *dao.UserPostDao
 	roleDao       *dao.RoleDao
 	postDao       *dao.PostDao
 	deptDao       *dao.DeptDao
+	recycleDao    *dao.RecycleDao
 	configService *ConfigService
 }
 
@@ -31,14 +34,30 @@ func NewUserService() *UserService {
 		roleDao:       dao.NewRoleDao(),
 		postDao:       dao.NewPostDao(),
 		deptDao:       dao.NewDeptDao(),
+		recycleDao:    dao.NewRecycleDao(),
 		configService: NewConfigService(),
 	}
 }
 
+// WithContext 返回使用指定context的服务实例，数据访问携带请求信息和租户
+func (s *UserService) WithContext(ctx context.Context) *UserService {
+	return &UserService{
+		ctx:           ctx,
+		userDao:       s.userDao.WithContext(ctx),
+		userRoleDao:   s.userRoleDao.WithContext(ctx),
+		userPostDao:   s.userPostDao.WithContext(ctx),
+		roleDao:       s.roleDao.WithContext(ctx),
+		postDao:       s.postDao.WithContext(ctx),
+		deptDao:       s.deptDao.WithContext(ctx),
+		recycleDao:    s.recycleDao.WithContext(ctx),
+		configService: s.configService.WithContext(ctx),
+	}
+}
+
 // SelectUserList 查询用户列表 对应Java后端的selectUserList
 func (s *UserService) SelectUserList(user *model.SysUser, pageNum, pageSize int) ([]model.SysUser, int64, error) {
 	// 直接使用数据库分页查询
-	return s.userDao.SelectUserList(user, pageNum, pageSize)
+	return s.userDao.SelectUserList(user, pageNum, pageSize, nil)
 }
 
 // SelectAllocatedList 查询已分配用户角色列表 对应Java后端的selectAllocatedList
@@ -49,8 +68,8 @@ func (s *UserService) SelectAllocatedList(currentUser *model.SysUser, user *mode
 		return nil, 0, fmt.Errorf("角色ID不能为空")
 	}
 
-	// 使用数据权限处理器查询
-	return s.userDao.SelectAllocatedList(currentUser, user, pageNum, pageSize)
+	// 应用数据权限 对应Java后端的@DataScope(deptAlias = "d", userAlias = "u")
+	return s.userDao.SelectAllocatedList(user, pageNum, pageSize, datascope.Build(currentUser, "system:role:list"))
 }
 
 // SelectUnallocatedList 查询未分配用户角色列表 对应Java后端的selectUnallocatedList
@@ -61,8 +80,8 @@ func (s *UserService) SelectUnallocatedList(currentUser *model.SysUser, user *mo
 		return nil, 0, fmt.Errorf("角色ID不能为空")
 	}
 
-	// 使用数据权限处理器查询
-	return s.userDao.SelectUnallocatedList(currentUser, user, pageNum, pageSize)
+	// 应用数据权限 对应Java后端的@DataScope(deptAlias = "d", userAlias = "u")
+	return s.userDao.SelectUnallocatedList(user, pageNum, pageSize, datascope.Build(currentUser, "system:role:list"))
 }
 
 // SelectUserListWithDataScope 查询用户列表（支持数据权限） 对应Java后端的@DataScope注解
@@ -77,35 +96,15 @@ func (s *UserService) SelectUserListWithDataScope(currentUser *model.SysUser, qu
 			i+1, role.RoleID, role.RoleName, role.DataScope, role.Status, len(role.Permissions))
 	}
 
-	// 创建查询参数
-	params := make(map[string]interface{})
-
 	// 应用数据权限 对应Java后端的@DataScope(deptAlias = "d", userAlias = "u")
-	err := datascope.ApplyDataScope(currentUser, "d", "u", "system:user:list", params)
-	if err != nil {
-		fmt.Printf("UserService.SelectUserListWithDataScope: 应用数据权限失败: %v\n", err)
-		return nil, 0, fmt.Errorf("应用数据权限失败: %v", err)
-	}
+	scope := datascope.Build(currentUser, "system:user:list")
 
-	// 打印数据权限参数
-	fmt.Printf("UserService.SelectUserListWithDataScope: 数据权限参数: %+v\n", params)
-
-	// 将数据权限SQL设置到查询用户对象中
 	if queryUser == nil {
 		queryUser = &model.SysUser{}
 	}
-	if queryUser.Params == nil {
-		queryUser.Params = make(map[string]interface{})
-	}
-
-	// 合并数据权限参数
-	for key, value := range params {
-		queryUser.Params[key] = value
-		fmt.Printf("UserService.SelectUserListWithDataScope: 设置查询参数: %s = %v\n", key, value)
-	}
 
 	// 执行查询
-	users, total, err := s.userDao.SelectUserList(queryUser, pageNum, pageSize)
+	users, total, err := s.userDao.SelectUserList(queryUser, pageNum, pageSize, scope)
 	fmt.Printf("UserService.SelectUserListWithDataScope: 查询结果: 总数=%d, 当前页数量=%d, 错误=%v\n", total, len(users), err)
 	return users, total, err
 }
@@ -247,6 +246,13 @@ func (s *UserService) UpdateUser(user *model.SysUser, updateBy string) error {
 	user.UpdateBy = updateBy
 	user.UpdateTime = &now
 
+	// 先更新用户基本信息 对应Java后端的userMapper.updateUser(user)
+	// 版本号不一致（已被其他用户修改）时不修改角色和岗位关联
+	err = s.userDao.UpdateUser(user)
+	if err != nil {
+		return fmt.Errorf("更新用户信息失败: %w", err)
+	}
+
 	// 1. 删除用户与角色关联 对应Java后端的userRoleMapper.deleteUserRoleByUserId(userId)
 	err = s.userRoleDao.DeleteUserRoleByUserId(user.UserID)
 	if err != nil {
@@ -275,12 +281,6 @@ func (s *UserService) UpdateUser(user *model.SysUser, updateBy string) error {
 		}
 	}
 
-	// 5. 更新用户基本信息 对应Java后端的userMapper.updateUser(user)
-	err = s.userDao.UpdateUser(user)
-	if err != nil {
-		return fmt.Errorf("更新用户信息失败: %v", err)
-	}
-
 	fmt.Printf("UserService.UpdateUser: 修改用户成功, UserID=%d\n", user.UserID)
 	return nil
 }
@@ -303,6 +303,29 @@ func (s *UserService) DeleteUserByIds(currentUser *model.SysUser, userIds []int6
 		}
 	}
 
+	// 记录删除前的角色和岗位，从回收站恢复时重新关联
+	users := make(map[int64]*model.SysUser, len(userIds))
+	snapshots := make(map[int64]*model.RecycleSnapshot, len(userIds))
+	for _, userId := range userIds {
+		user, err := s.userDao.SelectUserById(userId)
+		if err != nil {
+			return fmt.Errorf("查询用户失败: %v", err)
+		}
+		if user == nil {
+			continue
+		}
+		roleIds, err := s.userRoleDao.SelectRoleListByUserId(userId)
+		if err != nil {
+			return fmt.Errorf("查询用户角色关联失败: %v", err)
+		}
+		postIds, err := s.userPostDao.SelectPostListByUserId(userId)
+		if err != nil {
+			return fmt.Errorf("查询用户岗位关联失败: %v", err)
+		}
+		users[userId] = user
+		snapshots[userId] = &model.RecycleSnapshot{RoleIds: roleIds, PostIds: postIds}
+	}
+
 	// 删除用户与角色关联 对应Java后端的userRoleMapper.deleteUserRole(userIds)
 	for _, userId := range userIds {
 		err := s.userRoleDao.DeleteUserRoleByUserId(userId)
@@ -325,6 +348,14 @@ func (s *UserService) DeleteUserByIds(currentUser *model.SysUser, userIds []int6
 		if err != nil {
 			return fmt.Errorf("删除用户失败: %v", err)
 		}
+
+		// 写入回收站
+		if user, ok := users[userId]; ok {
+			err = recordRecycle(s.recycleDao, model.RecycleTypeUser, userId, user.UserName, snapshots[userId], currentUser.UserName)
+			if err != nil {
+				return fmt.Errorf("写入回收站失败: %v", err)
+			}
+		}
 	}
 
 	fmt.Printf("UserService.DeleteUserByIds: 批量删除用户成功, 数量=%d\n", len(userIds))
@@ -393,7 +424,7 @@ func (s *UserService) GetUserAuthRole(userId int64) (map[string]interface{}, err
 	}
 
 	// 获取所有角色列表
-	roleService := NewRoleService()
+	roleService := NewRoleService().WithContext(s.ctx)
 	allRoles, err := roleService.SelectRoleAll()
 	if err != nil {
 		return nil, fmt.Errorf("查询角色列表失败: %v", err)
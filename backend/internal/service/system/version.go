@@ -0,0 +1,6 @@
+package system
+
+import "wosm/internal/repository/dao"
+
+// ErrVersionConflict 乐观锁冲突：数据在客户端读取之后已被其他用户修改，控制器返回response.CONFLICT提示刷新后重新修改
+var ErrVersionConflict = dao.ErrVersionConflict
@@ -0,0 +1,63 @@
+package system
+
+import (
+	"testing"
+	"wosm/internal/repository/model"
+	"wosm/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateRoleVersionConflict(t *testing.T) {
+	testutil.OpenMigratedDB(t)
+	roleService := NewRoleService()
+
+	role, err := roleService.SelectRoleById(2)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, role.Version)
+
+	// 两个管理员读取了同一版本，先保存的成功，后保存的冲突
+	first := *role
+	first.RoleName = "普通角色A"
+	require.NoError(t, roleService.UpdateRole(&first))
+	second := *role
+	second.RoleName = "普通角色B"
+	assert.ErrorIs(t, roleService.UpdateRole(&second), ErrVersionConflict)
+
+	role, err = roleService.SelectRoleById(2)
+	require.NoError(t, err)
+	assert.Equal(t, "普通角色A", role.RoleName)
+	assert.EqualValues(t, 2, role.Version)
+
+	// 未传版本号时不校验，版本号仍然加1
+	require.NoError(t, roleService.UpdateRoleStatus(&model.SysRole{RoleID: 2, Status: "1"}))
+	role, err = roleService.SelectRoleById(2)
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, role.Version)
+}
+
+func TestUpdateDeptVersionConflict(t *testing.T) {
+	db := testutil.OpenMigratedDB(t)
+	deptService := NewDeptService()
+
+	dept, err := deptService.SelectDeptById(105)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, dept.Version)
+	require.NoError(t, db.Exec("UPDATE sys_dept SET version = version + 1 WHERE dept_id = 105").Error)
+
+	// 版本号不一致时不修改子部门的祖级列表
+	dept.ParentID = 101
+	assert.ErrorIs(t, deptService.UpdateDept(dept), ErrVersionConflict)
+	var ancestors string
+	require.NoError(t, db.Table("sys_dept").Where("dept_id = 105").Pluck("ancestors", &ancestors).Error)
+	assert.Equal(t, "0,100,101", ancestors)
+
+	dept.Version = 2
+	dept.DeptName = "测试部门2"
+	require.NoError(t, deptService.UpdateDept(dept))
+	dept, err = deptService.SelectDeptById(105)
+	require.NoError(t, err)
+	assert.Equal(t, "测试部门2", dept.DeptName)
+	assert.EqualValues(t, 3, dept.Version)
+}
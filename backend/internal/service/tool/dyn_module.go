@@ -0,0 +1,463 @@
+package tool
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"wosm/internal/repository/model"
+	"wosm/pkg/database"
+	"wosm/pkg/datascope"
+)
+
+// dynAlias 运行时模块查询中的表别名，数据权限字段使用该别名
+const dynAlias = "t"
+
+// dynTenantColumn 租户模式下按租户隔离的运行时模块表中的租户列
+const dynTenantColumn = "tenant_id"
+
+// dynIdentPattern 允许出现在SQL中的表名和列名，其余名称一律拒绝
+var dynIdentPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// dynDateLayouts 日期字段可接受的格式
+var dynDateLayouts = []string{"2006-01-02 15:04:05", "2006-01-02", time.RFC3339}
+
+// DynStatement 参数化SQL语句，值只通过参数传递
+type DynStatement struct {
+	SQL  string
+	Args []interface{}
+}
+
+// DynQuery 运行时模块的列表查询条件
+type DynQuery struct {
+	Values   url.Values // 查询参数，按字段属性名取值，范围查询使用 params[beginXxx]、params[endXxx]
+	OrderBy  string     // 排序字段属性名，必须是列表字段
+	IsAsc    bool       // 是否升序
+	PageNum  int        // 页码（0表示不分页）
+	PageSize int        // 每页数量
+}
+
+// DynModule 已发布为运行时模块的业务表，根据字段元数据构建参数化SQL
+type DynModule struct {
+	Table    *model.GenTable
+	PkColumn *model.GenTableColumn
+	TenantID int64 // 按租户隔离时为当前租户，查询、修改和删除只处理该租户的行，新增时写入该租户；0表示不隔离
+	dialect  database.Dialect
+}
+
+// NewDynModule 校验业务表能否作为运行时模块：必须有主键，表名和列名只能是普通标识符
+func NewDynModule(table *model.GenTable) (*DynModule, error) {
+	if table.IsSub() {
+		return nil, fmt.Errorf("主子表不支持发布为运行时模块")
+	}
+	if !dynIdentPattern.MatchString(table.Name) {
+		return nil, fmt.Errorf("表名'%s'包含非法字符", table.Name)
+	}
+	for _, column := range table.Columns {
+		if !dynIdentPattern.MatchString(column.ColumnName) {
+			return nil, fmt.Errorf("列名'%s'包含非法字符", column.ColumnName)
+		}
+	}
+	pk := table.GetPkColumn()
+	if pk == nil {
+		return nil, fmt.Errorf("表%s缺少主键字段", table.Name)
+	}
+	return &DynModule{Table: table, PkColumn: pk, dialect: database.CurrentDialect()}, nil
+}
+
+// Permission 模块的权限字符，与生成代码和安装菜单使用的权限一致
+func (m *DynModule) Permission(action string) string {
+	return fmt.Sprintf("%s:%s:%s", m.Table.ModuleName, m.Table.BusinessName, action)
+}
+
+// DataScopeColumns 数据权限使用的字段：表中存在dept_id时才启用数据权限，user_id、create_by用于仅本人数据权限
+func (m *DynModule) DataScopeColumns() (datascope.Columns, bool) {
+	if m.Table.GetColumnByName("dept_id") == nil {
+		return datascope.Columns{}, false
+	}
+	columns := datascope.Columns{Dept: m.column("dept_id")}
+	if m.Table.GetColumnByName("user_id") != nil {
+		columns.User = m.column("user_id")
+	}
+	if m.Table.GetColumnByName("create_by") != nil {
+		columns.Creator = m.column("create_by")
+	}
+	return columns, true
+}
+
+// ListColumns 列表和导出使用的字段（始终包含主键）
+func (m *DynModule) ListColumns() []model.GenTableColumn {
+	var columns []model.GenTableColumn
+	for _, column := range m.Table.Columns {
+		if column.IsPrimaryKey() || column.IsListField() {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+// SelectList 构建列表查询和总数查询
+func (m *DynModule) SelectList(query *DynQuery, dataScope datascope.Condition) (*DynStatement, *DynStatement, error) {
+	where, args, err := m.buildWhere(query.Values)
+	if err != nil {
+		return nil, nil, err
+	}
+	where, args = m.withTenant(where, args)
+	where, args = withDataScope(where, args, dataScope)
+
+	orderBy := m.PkColumn.ColumnName
+	if query.OrderBy != "" {
+		column := m.Table.GetColumnByField(query.OrderBy)
+		if column == nil || !(column.IsListField() || column.IsPrimaryKey()) {
+			return nil, nil, fmt.Errorf("不支持按%s排序", query.OrderBy)
+		}
+		orderBy = column.ColumnName
+	}
+	direction := "DESC"
+	if query.IsAsc {
+		direction = "ASC"
+	}
+
+	list := &DynStatement{
+		SQL:  fmt.Sprintf("SELECT %s FROM %s %s WHERE 1 = 1%s ORDER BY %s %s", m.selectColumns(m.ListColumns()), m.tableName(), dynAlias, where, m.column(orderBy), direction),
+		Args: args,
+	}
+	if query.PageNum > 0 && query.PageSize > 0 {
+		paginate, paginateArgs := m.dialect.Paginate((query.PageNum-1)*query.PageSize, query.PageSize)
+		list.SQL += paginate
+		list.Args = append(append([]interface{}{}, args...), paginateArgs...)
+	}
+	count := &DynStatement{
+		SQL:  fmt.Sprintf("SELECT COUNT(*) FROM %s %s WHERE 1 = 1%s", m.tableName(), dynAlias, where),
+		Args: args,
+	}
+	return list, count, nil
+}
+
+// SelectById 构建按主键查询全部字段的语句
+func (m *DynModule) SelectById(id interface{}, dataScope datascope.Condition) *DynStatement {
+	where, args := m.withTenant("", []interface{}{id})
+	where, args = withDataScope(where, args, dataScope)
+	return &DynStatement{
+		SQL:  fmt.Sprintf("SELECT %s FROM %s %s WHERE %s = ?%s", m.selectColumns(m.Table.Columns), m.tableName(), dynAlias, m.column(m.PkColumn.ColumnName), where),
+		Args: args,
+	}
+}
+
+// CountByIds 构建统计有权访问的主键数量的语句，用于删除前校验数据权限
+func (m *DynModule) CountByIds(ids []interface{}, dataScope datascope.Condition) *DynStatement {
+	where, args := m.withTenant("", append([]interface{}{}, ids...))
+	where, args = withDataScope(where, args, dataScope)
+	return &DynStatement{
+		SQL:  fmt.Sprintf("SELECT COUNT(*) FROM %s %s WHERE %s IN (%s)%s", m.tableName(), dynAlias, m.column(m.PkColumn.ColumnName), placeholders(len(ids)), where),
+		Args: args,
+	}
+}
+
+// Insert 构建新增语句：只写入可插入字段，自增主键由数据库生成，create_by、create_time自动填充，按租户隔离时写入当前租户
+func (m *DynModule) Insert(values map[string]interface{}, operName string, now time.Time) (*DynStatement, error) {
+	var names []string
+	var args []interface{}
+	for i := range m.Table.Columns {
+		column := &m.Table.Columns[i]
+		var value interface{}
+		switch {
+		case column.IsPrimaryKey() && column.IsAutoIncrement():
+			continue
+		case column.ColumnName == "create_by":
+			value = operName
+		case column.ColumnName == "create_time":
+			value = now
+		case column.ColumnName == dynTenantColumn && m.TenantID != 0:
+			value = m.TenantID
+		case column.IsPrimaryKey() || column.IsInsertField():
+			raw, ok := values[column.JavaField]
+			converted, err := ConvertDynValue(column, raw)
+			if err != nil {
+				return nil, err
+			}
+			if isEmptyDynValue(converted) && (column.IsPrimaryKey() || column.IsRequiredField()) {
+				return nil, fmt.Errorf("%s不能为空", columnLabel(column.ColumnComment))
+			}
+			if !ok {
+				continue
+			}
+			value = converted
+		default:
+			continue
+		}
+		names = append(names, m.dialect.Quote(column.ColumnName))
+		args = append(args, value)
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("没有要新增的字段")
+	}
+
+	return &DynStatement{
+		SQL:  fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", m.tableName(), strings.Join(names, ", "), placeholders(len(names))),
+		Args: args,
+	}, nil
+}
+
+// Update 构建修改语句：只修改请求中出现的可编辑字段，update_by、update_time自动填充，按租户隔离时不修改租户
+func (m *DynModule) Update(values map[string]interface{}, operName string, now time.Time, dataScope datascope.Condition) (*DynStatement, error) {
+	id, err := ConvertDynValue(m.PkColumn, values[m.PkColumn.JavaField])
+	if err != nil {
+		return nil, err
+	}
+	if isEmptyDynValue(id) {
+		return nil, fmt.Errorf("%s不能为空", columnLabel(m.PkColumn.ColumnComment))
+	}
+
+	var sets []string
+	var args []interface{}
+	for i := range m.Table.Columns {
+		column := &m.Table.Columns[i]
+		var value interface{}
+		switch {
+		case column.IsPrimaryKey(), column.ColumnName == dynTenantColumn && m.TenantID != 0:
+			continue
+		case column.ColumnName == "update_by":
+			value = operName
+		case column.ColumnName == "update_time":
+			value = now
+		case column.IsEditField():
+			raw, ok := values[column.JavaField]
+			if !ok {
+				continue
+			}
+			converted, err := ConvertDynValue(column, raw)
+			if err != nil {
+				return nil, err
+			}
+			if isEmptyDynValue(converted) && column.IsRequiredField() {
+				return nil, fmt.Errorf("%s不能为空", columnLabel(column.ColumnComment))
+			}
+			value = converted
+		default:
+			continue
+		}
+		sets = append(sets, column.ColumnName)
+		args = append(args, value)
+	}
+	if len(sets) == 0 {
+		return nil, fmt.Errorf("没有要修改的字段")
+	}
+
+	where, args := m.withTenant("", append(args, id))
+	where, args = withDataScope(where, args, dataScope)
+	return &DynStatement{
+		SQL:  fmt.Sprintf("%s WHERE %s = ?%s", m.dialect.Update(m.Table.Name, dynAlias, sets), m.column(m.PkColumn.ColumnName), where),
+		Args: args,
+	}, nil
+}
+
+// DeleteByIds 构建批量删除语句
+func (m *DynModule) DeleteByIds(ids []interface{}, dataScope datascope.Condition) *DynStatement {
+	where, args := m.withTenant("", append([]interface{}{}, ids...))
+	where, args = withDataScope(where, args, dataScope)
+	return &DynStatement{
+		SQL:  fmt.Sprintf("%s WHERE %s IN (%s)%s", m.dialect.Delete(m.Table.Name, dynAlias), m.column(m.PkColumn.ColumnName), placeholders(len(ids)), where),
+		Args: args,
+	}
+}
+
+// withTenant 按租户隔离时在条件和参数之后追加租户条件
+func (m *DynModule) withTenant(where string, args []interface{}) (string, []interface{}) {
+	if m.TenantID == 0 {
+		return where, args
+	}
+	return where + " AND " + m.column(dynTenantColumn) + " = ?", append(args, m.TenantID)
+}
+
+// withDataScope 在条件和参数之后追加数据权限条件
+func withDataScope(where string, args []interface{}, dataScope datascope.Condition) (string, []interface{}) {
+	if dataScope.SQL == "" {
+		return where, args
+	}
+	return where + " AND " + dataScope.SQL, append(args, dataScope.Args...)
+}
+
+// ParseIds 解析逗号分隔的主键
+func (m *DynModule) ParseIds(value string) ([]interface{}, error) {
+	var ids []interface{}
+	for _, item := range strings.Split(value, ",") {
+		id, err := ConvertDynValue(m.PkColumn, strings.TrimSpace(item))
+		if err != nil {
+			return nil, err
+		}
+		if isEmptyDynValue(id) {
+			return nil, fmt.Errorf("%s不能为空", columnLabel(m.PkColumn.ColumnComment))
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// ToRecord 将查询结果的列名转换为字段属性名
+func (m *DynModule) ToRecord(row map[string]interface{}) map[string]interface{} {
+	record := make(map[string]interface{}, len(row))
+	for _, column := range m.Table.Columns {
+		for name, value := range row {
+			if !strings.EqualFold(name, column.ColumnName) {
+				continue
+			}
+			if data, ok := value.([]byte); ok {
+				value = string(data)
+			}
+			record[column.JavaField] = value
+		}
+	}
+	return record
+}
+
+// buildWhere 根据查询字段的查询方式构建条件，空值不参与查询
+func (m *DynModule) buildWhere(values url.Values) (string, []interface{}, error) {
+	var where strings.Builder
+	var args []interface{}
+	for i := range m.Table.Columns {
+		column := &m.Table.Columns[i]
+		if !column.IsQueryField() {
+			continue
+		}
+		name := m.column(column.ColumnName)
+
+		if column.QueryType == model.QueryTypeBetween {
+			field := capitalize(column.JavaField)
+			for _, bound := range []struct{ key, op string }{{"params[begin" + field + "]", ">="}, {"params[end" + field + "]", "<="}} {
+				value, err := ConvertDynValue(column, values.Get(bound.key))
+				if err != nil {
+					return "", nil, err
+				}
+				if !isEmptyDynValue(value) {
+					where.WriteString(fmt.Sprintf(" AND %s %s ?", name, bound.op))
+					args = append(args, value)
+				}
+			}
+			continue
+		}
+
+		raw := values.Get(column.JavaField)
+		if raw == "" {
+			continue
+		}
+		if column.QueryType == model.QueryTypeLike {
+			where.WriteString(fmt.Sprintf(" AND %s LIKE ?", name))
+			args = append(args, "%"+raw+"%")
+			continue
+		}
+		value, err := ConvertDynValue(column, raw)
+		if err != nil {
+			return "", nil, err
+		}
+		op, ok := dynOperators[column.QueryType]
+		if !ok {
+			op = "="
+		}
+		where.WriteString(fmt.Sprintf(" AND %s %s ?", name, op))
+		args = append(args, value)
+	}
+	return where.String(), args, nil
+}
+
+// dynOperators 查询方式对应的比较运算符
+var dynOperators = map[string]string{
+	model.QueryTypeEQ:  "=",
+	model.QueryTypeNE:  "<>",
+	model.QueryTypeGT:  ">",
+	model.QueryTypeGTE: ">=",
+	model.QueryTypeLT:  "<",
+	model.QueryTypeLTE: "<=",
+}
+
+// selectColumns 查询的列
+func (m *DynModule) selectColumns(columns []model.GenTableColumn) string {
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = m.column(column.ColumnName)
+	}
+	return strings.Join(names, ", ")
+}
+
+// tableName 带架构的表名
+func (m *DynModule) tableName() string {
+	return m.dialect.TableName(m.Table.Name)
+}
+
+// column 带表别名的列名
+func (m *DynModule) column(name string) string {
+	return dynAlias + "." + m.dialect.Quote(name)
+}
+
+// placeholders 生成n个参数占位符
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// isEmptyDynValue 判断转换后的值是否为空
+func isEmptyDynValue(value interface{}) bool {
+	return value == nil || value == ""
+}
+
+// ConvertDynValue 按字段类型转换请求中的值，空值返回nil（字符串字段保留空字符串）
+func ConvertDynValue(column *model.GenTableColumn, raw interface{}) (interface{}, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	var text string
+	switch value := raw.(type) {
+	case string:
+		text = value
+	case []interface{}:
+		// 复选框提交的多个值以逗号保存
+		items := make([]string, len(value))
+		for i, item := range value {
+			items[i] = fmt.Sprint(item)
+		}
+		text = strings.Join(items, ",")
+	case map[string]interface{}:
+		return nil, fmt.Errorf("%s格式错误", columnLabel(column.ColumnComment))
+	default:
+		text = fmt.Sprint(value)
+	}
+
+	if column.JavaType == model.JavaTypeString || column.JavaType == "" {
+		return text, nil
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, nil
+	}
+
+	var value interface{}
+	var err error
+	switch column.JavaType {
+	case model.JavaTypeLong, model.JavaTypeInteger:
+		value, err = strconv.ParseInt(text, 10, 64)
+	case model.JavaTypeDouble:
+		value, err = strconv.ParseFloat(text, 64)
+	case model.JavaTypeBigDecimal:
+		// 小数保留原始文本，避免浮点精度损失
+		_, err = strconv.ParseFloat(text, 64)
+		value = text
+	case model.JavaTypeBoolean:
+		value, err = strconv.ParseBool(text)
+	case model.JavaTypeDate:
+		err = fmt.Errorf("日期格式错误")
+		for _, layout := range dynDateLayouts {
+			var date time.Time
+			if date, err = time.ParseInLocation(layout, text, time.Local); err == nil {
+				value = date
+				break
+			}
+		}
+	default:
+		value = text
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s格式错误: %s", columnLabel(column.ColumnComment), text)
+	}
+	return value, nil
+}
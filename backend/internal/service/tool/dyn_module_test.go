@@ -0,0 +1,201 @@
+package tool
+
+import (
+	"net/url"
+	"testing"
+	"time"
+	"wosm/internal/repository/model"
+	"wosm/pkg/datascope"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDynModule(t *testing.T) *DynModule {
+	table := &model.GenTable{
+		Name:         "biz_order",
+		ModuleName:   "biz",
+		BusinessName: "order",
+		FunctionName: "订单",
+		TplCategory:  model.TplCategoryCrud,
+		Columns: []model.GenTableColumn{
+			{ColumnName: "order_id", ColumnComment: "订单ID", JavaType: model.JavaTypeLong, JavaField: "orderId", IsPk: "1", IsIncrement: "1", IsList: "1"},
+			{ColumnName: "order_name", ColumnComment: "订单名称", JavaType: model.JavaTypeString, JavaField: "orderName", IsRequired: "1", IsInsert: "1", IsEdit: "1", IsList: "1", IsQuery: "1", QueryType: model.QueryTypeLike},
+			{ColumnName: "amount", ColumnComment: "金额", JavaType: model.JavaTypeBigDecimal, JavaField: "amount", IsInsert: "1", IsEdit: "1", IsList: "1", IsQuery: "1", QueryType: model.QueryTypeGTE},
+			{ColumnName: "order_time", ColumnComment: "下单时间", JavaType: model.JavaTypeDate, JavaField: "orderTime", IsInsert: "1", IsEdit: "1", IsList: "1", IsQuery: "1", QueryType: model.QueryTypeBetween},
+			{ColumnName: "dept_id", ColumnComment: "部门ID", JavaType: model.JavaTypeLong, JavaField: "deptId", IsInsert: "1"},
+			{ColumnName: "create_by", ColumnComment: "创建者", JavaType: model.JavaTypeString, JavaField: "createBy"},
+			{ColumnName: "create_time", ColumnComment: "创建时间", JavaType: model.JavaTypeDate, JavaField: "createTime"},
+			{ColumnName: "update_by", ColumnComment: "更新者", JavaType: model.JavaTypeString, JavaField: "updateBy"},
+		},
+	}
+	module, err := NewDynModule(table)
+	require.NoError(t, err)
+	return module
+}
+
+func TestNewDynModule(t *testing.T) {
+	tests := []struct {
+		name    string
+		table   *model.GenTable
+		wantErr bool
+	}{
+		{"有主键", &model.GenTable{Name: "biz_order", Columns: []model.GenTableColumn{{ColumnName: "order_id", IsPk: "1"}}}, false},
+		{"缺少主键", &model.GenTable{Name: "biz_order", Columns: []model.GenTableColumn{{ColumnName: "order_id"}}}, true},
+		{"非法表名", &model.GenTable{Name: "biz_order]; DROP TABLE x--", Columns: []model.GenTableColumn{{ColumnName: "order_id", IsPk: "1"}}}, true},
+		{"非法列名", &model.GenTable{Name: "biz_order", Columns: []model.GenTableColumn{{ColumnName: "order_id", IsPk: "1"}, {ColumnName: "a]b"}}}, true},
+		{"主子表", &model.GenTable{Name: "biz_order", TplCategory: model.TplCategorySub, Columns: []model.GenTableColumn{{ColumnName: "order_id", IsPk: "1"}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewDynModule(tt.table)
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
+
+func TestDynModuleSelectList(t *testing.T) {
+	module := newTestDynModule(t)
+	values := url.Values{
+		"orderName":              {"' OR 1=1 --"},
+		"amount":                 {"10.5"},
+		"params[beginOrderTime]": {"2025-01-01"},
+		"params[endOrderTime]":   {""},
+	}
+
+	list, count, err := module.SelectList(&DynQuery{Values: values, OrderBy: "orderName", IsAsc: true, PageNum: 2, PageSize: 10},
+		datascope.Condition{SQL: "(t.[dept_id] IN ?)", Args: []interface{}{[]int64{100}}})
+	require.NoError(t, err)
+
+	where := " WHERE 1 = 1 AND t.[order_name] LIKE ? AND t.[amount] >= ? AND t.[order_time] >= ? AND (t.[dept_id] IN ?)"
+	assert.Equal(t, "SELECT t.[order_id], t.[order_name], t.[amount], t.[order_time] FROM [dbo].[biz_order] t"+where+" ORDER BY t.[order_name] ASC OFFSET ? ROWS FETCH NEXT ? ROWS ONLY", list.SQL)
+	assert.Equal(t, "SELECT COUNT(*) FROM [dbo].[biz_order] t"+where, count.SQL)
+
+	begin := time.Date(2025, 1, 1, 0, 0, 0, 0, time.Local)
+	assert.Equal(t, []interface{}{"%' OR 1=1 --%", "10.5", begin, []int64{100}, 10, 10}, list.Args)
+	assert.Equal(t, []interface{}{"%' OR 1=1 --%", "10.5", begin, []int64{100}}, count.Args)
+
+	tests := []struct {
+		name  string
+		query *DynQuery
+	}{
+		{"非列表字段排序", &DynQuery{OrderBy: "deptId"}},
+		{"不存在的排序字段", &DynQuery{OrderBy: "order_name; DROP TABLE x"}},
+		{"查询值格式错误", &DynQuery{Values: url.Values{"amount": {"abc"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := module.SelectList(tt.query, datascope.Condition{})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestDynModuleInsert(t *testing.T) {
+	module := newTestDynModule(t)
+	now := time.Date(2025, 5, 1, 8, 0, 0, 0, time.Local)
+
+	statement, err := module.Insert(map[string]interface{}{"orderId": "99", "orderName": "订单1", "deptId": "100", "createBy": "hacker"}, "admin", now)
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO [dbo].[biz_order] ([order_name], [dept_id], [create_by], [create_time]) VALUES (?, ?, ?, ?)", statement.SQL)
+	assert.Equal(t, []interface{}{"订单1", int64(100), "admin", now}, statement.Args)
+
+	tests := []struct {
+		name   string
+		values map[string]interface{}
+	}{
+		{"必填字段为空", map[string]interface{}{"orderName": ""}},
+		{"缺少必填字段", map[string]interface{}{"amount": "1"}},
+		{"数字格式错误", map[string]interface{}{"orderName": "订单1", "amount": "1a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := module.Insert(tt.values, "admin", now)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestDynModuleUpdateAndDelete(t *testing.T) {
+	module := newTestDynModule(t)
+	now := time.Date(2025, 5, 1, 8, 0, 0, 0, time.Local)
+
+	statement, err := module.Update(map[string]interface{}{"orderId": "1", "amount": "2.50", "deptId": "200"}, "admin", now,
+		datascope.Condition{SQL: "(t.[create_by] = ?)", Args: []interface{}{"ry"}})
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET t.[amount] = ?, t.[update_by] = ? FROM [dbo].[biz_order] t WHERE t.[order_id] = ? AND (t.[create_by] = ?)", statement.SQL)
+	assert.Equal(t, []interface{}{"2.50", "admin", int64(1), "ry"}, statement.Args)
+
+	_, err = module.Update(map[string]interface{}{"orderName": "订单1"}, "admin", now, datascope.Condition{})
+	assert.Error(t, err, "缺少主键")
+
+	ids, err := module.ParseIds("1, 2")
+	require.NoError(t, err)
+	statement = module.DeleteByIds(ids, datascope.Condition{})
+	assert.Equal(t, "DELETE t FROM [dbo].[biz_order] t WHERE t.[order_id] IN (?, ?)", statement.SQL)
+	assert.Equal(t, []interface{}{int64(1), int64(2)}, statement.Args)
+
+	_, err = module.ParseIds("1 OR 1=1")
+	assert.Error(t, err)
+}
+
+func TestDynModuleTenant(t *testing.T) {
+	module := newTestDynModule(t)
+	module.Table.Columns = append(module.Table.Columns,
+		model.GenTableColumn{ColumnName: "tenant_id", ColumnComment: "租户ID", JavaType: model.JavaTypeLong, JavaField: "tenantId", IsInsert: "1", IsEdit: "1", IsList: "1"})
+	module.TenantID = 5
+	now := time.Date(2025, 5, 1, 8, 0, 0, 0, time.Local)
+
+	// 查询、修改和删除只处理当前租户的行
+	statement := module.SelectById(int64(1), datascope.Condition{SQL: "(t.[create_by] = ?)", Args: []interface{}{"ry"}})
+	assert.Contains(t, statement.SQL, "WHERE t.[order_id] = ? AND t.[tenant_id] = ? AND (t.[create_by] = ?)")
+	assert.Equal(t, []interface{}{int64(1), int64(5), "ry"}, statement.Args)
+	statement = module.DeleteByIds([]interface{}{int64(1)}, datascope.Condition{})
+	assert.Equal(t, "DELETE t FROM [dbo].[biz_order] t WHERE t.[order_id] IN (?) AND t.[tenant_id] = ?", statement.SQL)
+
+	// 新增写入当前租户，修改不能变更租户
+	statement, err := module.Insert(map[string]interface{}{"orderName": "订单1", "tenantId": "1"}, "admin", now)
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO [dbo].[biz_order] ([order_name], [create_by], [create_time], [tenant_id]) VALUES (?, ?, ?, ?)", statement.SQL)
+	assert.Equal(t, []interface{}{"订单1", "admin", now, int64(5)}, statement.Args)
+	statement, err = module.Update(map[string]interface{}{"orderId": "1", "amount": "2.50", "tenantId": "1"}, "admin", now, datascope.Condition{})
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET t.[amount] = ?, t.[update_by] = ? FROM [dbo].[biz_order] t WHERE t.[order_id] = ? AND t.[tenant_id] = ?", statement.SQL)
+	assert.Equal(t, []interface{}{"2.50", "admin", int64(1), int64(5)}, statement.Args)
+
+	list, count, err := module.SelectList(&DynQuery{}, datascope.Condition{})
+	require.NoError(t, err)
+	assert.Contains(t, list.SQL, "WHERE 1 = 1 AND t.[tenant_id] = ?")
+	assert.Equal(t, []interface{}{int64(5)}, count.Args)
+}
+
+func TestConvertDynValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		javaType string
+		raw      interface{}
+		want     interface{}
+		wantErr  bool
+	}{
+		{"字符串", model.JavaTypeString, "abc", "abc", false},
+		{"复选框", model.JavaTypeString, []interface{}{"1", "2"}, "1,2", false},
+		{"长整型", model.JavaTypeLong, "9007199254740993", int64(9007199254740993), false},
+		{"整型格式错误", model.JavaTypeInteger, "1.5", nil, true},
+		{"小数保留原文", model.JavaTypeBigDecimal, "0.10", "0.10", false},
+		{"布尔", model.JavaTypeBoolean, "true", true, false},
+		{"日期", model.JavaTypeDate, "2025-01-02 03:04:05", time.Date(2025, 1, 2, 3, 4, 5, 0, time.Local), false},
+		{"空数字", model.JavaTypeLong, " ", nil, false},
+		{"对象", model.JavaTypeString, map[string]interface{}{}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ConvertDynValue(&model.GenTableColumn{JavaType: tt.javaType, ColumnComment: "字段"}, tt.raw)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
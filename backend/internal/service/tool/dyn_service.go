@@ -0,0 +1,243 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"wosm/internal/config"
+	"wosm/internal/repository/dao"
+	"wosm/internal/repository/model"
+	systemService "wosm/internal/service/system"
+	"wosm/pkg/database"
+	"wosm/pkg/datascope"
+	"wosm/pkg/excel"
+)
+
+// DynService 运行时模块服务：已发布的业务表不生成代码，按字段元数据直接增删改查
+type DynService struct {
+	genDao          *dao.GenDao
+	dynDao          *dao.DynDao
+	dictDataService *systemService.DictDataService
+	ctx             context.Context // 数据访问使用的context，按租户隔离的模块使用其中的租户
+}
+
+// NewDynService 创建运行时模块服务实例
+func NewDynService() *DynService {
+	return &DynService{
+		genDao:          dao.NewGenDao(),
+		dynDao:          dao.NewDynDao(),
+		dictDataService: systemService.NewDictDataService(),
+	}
+}
+
+// WithContext 返回使用指定context的服务实例，数据访问携带请求信息和租户
+func (s *DynService) WithContext(ctx context.Context) *DynService {
+	return &DynService{
+		genDao:          s.genDao.WithContext(ctx),
+		dynDao:          s.dynDao.WithContext(ctx),
+		dictDataService: s.dictDataService.WithContext(ctx),
+		ctx:             ctx,
+	}
+}
+
+// LoadModule 根据业务名加载已发布的运行时模块及其字段
+// 开启租户模式时，表中存在tenant_id的模块按context中的租户隔离，未确定租户时拒绝访问
+func (s *DynService) LoadModule(name string) (*DynModule, error) {
+	tables, err := s.genDao.SelectGenTableListByBusinessName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var table *model.GenTable
+	for i := range tables {
+		tables[i].SetTableFromOptions()
+		if tables[i].Published {
+			table = &tables[i]
+			break
+		}
+	}
+	if table == nil {
+		return nil, fmt.Errorf("模块'%s'不存在或未发布", name)
+	}
+
+	table.Columns, err = s.genDao.SelectGenTableColumnListByTableId(table.TableID)
+	if err != nil {
+		return nil, err
+	}
+	module, err := NewDynModule(table)
+	if err != nil {
+		return nil, err
+	}
+	if config.IsTenantEnabled() && table.GetColumnByName(dynTenantColumn) != nil {
+		if module.TenantID = database.RequestTenant(s.ctx); module.TenantID == 0 {
+			return nil, dao.ErrTenantRequired
+		}
+	}
+	return module, nil
+}
+
+// SelectList 查询列表，返回当前页数据和总数
+func (s *DynService) SelectList(module *DynModule, query *DynQuery, dataScope datascope.Condition) ([]map[string]interface{}, int64, error) {
+	list, count, err := module.SelectList(query, dataScope)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.dynDao.SelectCount(count.SQL, count.Args)
+	if err != nil {
+		return nil, 0, err
+	}
+	rows, err := s.dynDao.SelectRows(list.SQL, list.Args)
+	if err != nil {
+		return nil, 0, err
+	}
+	records := make([]map[string]interface{}, len(rows))
+	for i, row := range rows {
+		records[i] = module.ToRecord(row)
+	}
+	return records, total, nil
+}
+
+// SelectById 根据主键查询，数据不存在或没有数据权限时返回nil
+func (s *DynService) SelectById(module *DynModule, id interface{}, dataScope datascope.Condition) (map[string]interface{}, error) {
+	statement := module.SelectById(id, dataScope)
+	rows, err := s.dynDao.SelectRows(statement.SQL, statement.Args)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	return module.ToRecord(rows[0]), nil
+}
+
+// Insert 新增数据，必填和字典字段校验通过后写入
+func (s *DynService) Insert(module *DynModule, values map[string]interface{}, operName string) error {
+	if err := s.checkDictValues(module, values); err != nil {
+		return err
+	}
+	statement, err := module.Insert(values, operName, time.Now())
+	if err != nil {
+		return err
+	}
+	_, err = s.dynDao.Exec(statement.SQL, statement.Args)
+	return err
+}
+
+// Update 修改数据，数据不存在或没有数据权限时返回错误
+func (s *DynService) Update(module *DynModule, values map[string]interface{}, operName string, dataScope datascope.Condition) error {
+	if err := s.checkDictValues(module, values); err != nil {
+		return err
+	}
+	statement, err := module.Update(values, operName, time.Now(), dataScope)
+	if err != nil {
+		return err
+	}
+	affected, err := s.dynDao.Exec(statement.SQL, statement.Args)
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("数据不存在或没有权限访问")
+	}
+	return nil
+}
+
+// DeleteByIds 批量删除，任一数据不存在或没有数据权限时不删除
+func (s *DynService) DeleteByIds(module *DynModule, ids []interface{}, dataScope datascope.Condition) error {
+	count := module.CountByIds(ids, dataScope)
+	total, err := s.dynDao.SelectCount(count.SQL, count.Args)
+	if err != nil {
+		return err
+	}
+	if total != int64(len(ids)) {
+		return fmt.Errorf("数据不存在或没有权限访问")
+	}
+
+	statement := module.DeleteByIds(ids, dataScope)
+	_, err = s.dynDao.Exec(statement.SQL, statement.Args)
+	return err
+}
+
+// Export 导出列表字段为Excel，字典字段导出字典标签
+func (s *DynService) Export(module *DynModule, query *DynQuery, dataScope datascope.Condition) ([]byte, int, error) {
+	query.PageNum, query.PageSize = 0, 0
+	records, _, err := s.SelectList(module, query, dataScope)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	columns := module.ListColumns()
+	headers := make([]string, len(columns))
+	labels := make([]map[string]string, len(columns))
+	for i, column := range columns {
+		headers[i] = columnLabel(column.ColumnComment)
+		if column.DictType != "" && isDictHtml(column.HtmlType) {
+			if labels[i], err = s.dictLabels(column.DictType); err != nil {
+				return nil, 0, err
+			}
+		}
+	}
+
+	rows := make([][]interface{}, len(records))
+	for i, record := range records {
+		rows[i] = make([]interface{}, len(columns))
+		for j, column := range columns {
+			value := record[column.JavaField]
+			if labels[j] != nil && value != nil {
+				items := strings.Split(fmt.Sprint(value), ",")
+				for k, item := range items {
+					if label, ok := labels[j][item]; ok {
+						items[k] = label
+					}
+				}
+				value = strings.Join(items, ",")
+			}
+			rows[i][j] = value
+		}
+	}
+
+	data, err := excel.NewExcelUtil().ExportRows(headers, rows, module.Table.FunctionName, module.Table.FunctionName+"列表")
+	return data, len(rows), err
+}
+
+// checkDictValues 校验字典字段的值（复选框为逗号分隔的多个值）必须是字典中的键值
+func (s *DynService) checkDictValues(module *DynModule, values map[string]interface{}) error {
+	for i := range module.Table.Columns {
+		column := &module.Table.Columns[i]
+		if column.DictType == "" || !isDictHtml(column.HtmlType) {
+			continue
+		}
+		value, err := ConvertDynValue(column, values[column.JavaField])
+		if err != nil {
+			return err
+		}
+		if isEmptyDynValue(value) {
+			continue
+		}
+		labels, err := s.dictLabels(column.DictType)
+		if err != nil {
+			return err
+		}
+		for _, item := range strings.Split(fmt.Sprint(value), ",") {
+			if _, ok := labels[item]; !ok {
+				return fmt.Errorf("%s的值'%s'不在字典%s中", columnLabel(column.ColumnComment), item, column.DictType)
+			}
+		}
+	}
+	return nil
+}
+
+// dictLabels 字典键值到字典标签的映射
+func (s *DynService) dictLabels(dictType string) (map[string]string, error) {
+	list, err := s.dictDataService.SelectDictDataByType(dictType)
+	if err != nil {
+		return nil, err
+	}
+	labels := make(map[string]string, len(list))
+	for _, data := range list {
+		labels[data.DictValue] = data.DictLabel
+	}
+	return labels, nil
+}
@@ -0,0 +1,726 @@
+package tool
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"wosm/internal/repository/model"
+	"wosm/pkg/ddl"
+	"wosm/pkg/excel"
+)
+
+// GenDefinitionError 表定义的校验错误，Row为Excel行号（JSON Schema和表级错误为0）
+type GenDefinitionError struct {
+	Row     int    `json:"row"`     // 行号
+	Column  string `json:"column"`  // 列名
+	Message string `json:"message"` // 错误信息
+}
+
+// GenDefinition 从Excel或JSON Schema解析出的表定义，存在错误时不能导入
+type GenDefinition struct {
+	Table  *ddl.Table           `json:"table"`
+	Errors []GenDefinitionError `json:"errors"`
+}
+
+// addError 记录校验错误
+func (d *GenDefinition) addError(row int, column, format string, args ...interface{}) {
+	d.Errors = append(d.Errors, GenDefinitionError{Row: row, Column: column, Message: fmt.Sprintf(format, args...)})
+}
+
+// definitionField 表定义模板中的字段，表头可以使用中文或英文名称
+type definitionField struct {
+	key     string   // 字段标识（与表设计器的JSON字段一致）
+	zh      string   // 中文名称
+	en      string   // 英文名称
+	aliases []string // 其它可识别的名称
+}
+
+// definitionTableFields 表头上方的表信息
+var definitionTableFields = []definitionField{
+	{key: "tableName", zh: "表名", en: "Table Name", aliases: []string{"表名称"}},
+	{key: "tableComment", zh: "表注释", en: "Table Comment", aliases: []string{"表描述", "Table Description"}},
+}
+
+// definitionColumnFields 列定义的表头
+var definitionColumnFields = []definitionField{
+	{key: "columnName", zh: "列名", en: "Column Name", aliases: []string{"字段名", "Column"}},
+	{key: "columnType", zh: "类型", en: "Type", aliases: []string{"数据类型", "Data Type"}},
+	{key: "length", zh: "长度", en: "Length", aliases: []string{"精度", "Precision"}},
+	{key: "scale", zh: "小数位", en: "Scale", aliases: []string{"小数位数"}},
+	{key: "primaryKey", zh: "主键", en: "Primary Key", aliases: []string{"PK"}},
+	{key: "autoIncrement", zh: "自增", en: "Auto Increment", aliases: []string{"Identity"}},
+	{key: "nullable", zh: "可空", en: "Nullable", aliases: []string{"允许为空", "Null"}},
+	{key: "defaultValue", zh: "默认值", en: "Default", aliases: []string{"Default Value"}},
+	{key: "columnComment", zh: "注释", en: "Comment", aliases: []string{"列注释", "说明", "描述", "Description"}},
+	{key: "index", zh: "索引", en: "Index"},
+}
+
+// matchDefinitionField 根据表头名称匹配字段，表头可以同时写中英文（如 列名/Column Name），不区分大小写
+func matchDefinitionField(header string, fields []definitionField) string {
+	for _, part := range strings.Split(header, "/") {
+		name := normalizeHeader(part)
+		if name == "" {
+			continue
+		}
+		for _, field := range fields {
+			names := append([]string{field.key, field.zh, field.en}, field.aliases...)
+			for _, candidate := range names {
+				if name == normalizeHeader(candidate) {
+					return field.key
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// normalizeHeader 去除表头中的空白、下划线和必填标记
+func normalizeHeader(header string) string {
+	header = strings.ToLower(strings.TrimSpace(header))
+	return strings.NewReplacer(" ", "", "_", "", "-", "", "*", "").Replace(header)
+}
+
+// definitionTypeAliases 类型的中英文名称，与表设计器中的类型名称一致
+var definitionTypeAliases = map[string]string{
+	"字符串": ddl.TypeVarchar, "string": ddl.TypeVarchar, "nvarchar": ddl.TypeVarchar,
+	"定长字符": ddl.TypeChar, "nchar": ddl.TypeChar,
+	"长文本": ddl.TypeText, "文本": ddl.TypeText, "ntext": ddl.TypeText,
+	"短整数": ddl.TypeSmallint,
+	"整数":  ddl.TypeInt, "integer": ddl.TypeInt,
+	"长整数": ddl.TypeBigint, "long": ddl.TypeBigint,
+	"小数": ddl.TypeDecimal, "numeric": ddl.TypeDecimal, "number": ddl.TypeDecimal,
+	"布尔": ddl.TypeBoolean, "bool": ddl.TypeBoolean, "bit": ddl.TypeBoolean,
+	"日期":   ddl.TypeDate,
+	"日期时间": ddl.TypeDatetime, "datetime2": ddl.TypeDatetime, "timestamp": ddl.TypeDatetime,
+}
+
+// definitionTypePattern 类型可以带长度，如 varchar(64)、decimal(10,2)
+var definitionTypePattern = regexp.MustCompile(`^([^()]+?)\s*(?:\(\s*(\d+|max)\s*(?:,\s*(\d+)\s*)?\))?$`)
+
+// parseDefinitionType 解析类型名称，返回逻辑类型及类型中携带的长度和小数位（-1表示未指定）
+func parseDefinitionType(value string) (columnType string, length, scale int, err error) {
+	match := definitionTypePattern.FindStringSubmatch(strings.ToLower(strings.TrimSpace(value)))
+	if match == nil {
+		return "", 0, 0, fmt.Errorf("类型'%s'格式错误", value)
+	}
+	columnType = strings.TrimSpace(match[1])
+	if alias, ok := definitionTypeAliases[columnType]; ok {
+		columnType = alias
+	}
+	switch columnType {
+	case ddl.TypeVarchar, ddl.TypeChar, ddl.TypeText, ddl.TypeSmallint, ddl.TypeInt, ddl.TypeBigint,
+		ddl.TypeDecimal, ddl.TypeBoolean, ddl.TypeDate, ddl.TypeDatetime:
+	default:
+		return "", 0, 0, fmt.Errorf("类型'%s'不支持", value)
+	}
+
+	length, scale = -1, -1
+	switch match[2] {
+	case "":
+	case "max":
+		length = 0
+	default:
+		length, _ = strconv.Atoi(match[2])
+	}
+	if match[3] != "" {
+		scale, _ = strconv.Atoi(match[3])
+	}
+	return columnType, length, scale, nil
+}
+
+// parseDefinitionBool 解析是/否，空值返回默认值
+func parseDefinitionBool(value string, defaultValue bool) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "":
+		return defaultValue, nil
+	case "是", "y", "yes", "true", "1", "√", "✓":
+		return true, nil
+	case "否", "n", "no", "false", "0", "×":
+		return false, nil
+	}
+	return false, fmt.Errorf("'%s'应为是或否", value)
+}
+
+// parseDefinitionIndex 解析单列索引：普通索引或唯一索引，空值表示不建索引
+func parseDefinitionIndex(value string) (index, unique bool, err error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "":
+		return false, false, nil
+	case "普通", "索引", "index", "idx", "normal":
+		return true, false, nil
+	case "唯一", "unique", "uk":
+		return true, true, nil
+	}
+	return false, false, fmt.Errorf("索引'%s'应为普通或唯一", value)
+}
+
+// definitionBuilder 逐列构建表定义，记录每列所在的行用于报告重复列名
+type definitionBuilder struct {
+	definition *GenDefinition
+	rows       map[string]int
+}
+
+// newDefinitionBuilder 创建表定义构建器
+func newDefinitionBuilder() *definitionBuilder {
+	return &definitionBuilder{
+		definition: &GenDefinition{Table: &ddl.Table{}},
+		rows:       make(map[string]int),
+	}
+}
+
+// addColumn 校验列定义后加入表定义，列名重复时报告首次出现的行
+func (b *definitionBuilder) addColumn(row int, column ddl.Column, index, unique bool) {
+	if err := column.Validate(); err != nil {
+		b.definition.addError(row, column.Name, "%s", err.Error())
+		return
+	}
+	key := strings.ToLower(column.Name)
+	if previous, ok := b.rows[key]; ok {
+		if previous > 0 {
+			b.definition.addError(row, column.Name, "列名'%s'与第%d行重复", column.Name, previous)
+		} else {
+			b.definition.addError(row, column.Name, "列名'%s'重复", column.Name)
+		}
+		return
+	}
+	b.rows[key] = row
+
+	table := b.definition.Table
+	table.Columns = append(table.Columns, column)
+	if index {
+		table.Indexes = append(table.Indexes, ddl.Index{Columns: []string{column.Name}, Unique: unique})
+	}
+}
+
+// finish 校验表级规则（表名、索引等），只有各列都正确时才校验
+func (b *definitionBuilder) finish() *GenDefinition {
+	definition := b.definition
+	table := definition.Table
+	if table.Name == "" {
+		definition.addError(0, "", "表名不能为空")
+	}
+	if len(table.Columns) == 0 && len(definition.Errors) == 0 {
+		definition.addError(0, "", "没有列定义")
+	}
+	if len(definition.Errors) == 0 {
+		if err := table.Validate(); err != nil {
+			definition.addError(0, "", "%s", err.Error())
+		}
+	}
+	return definition
+}
+
+// ParseDefinitionExcel 解析Excel表定义：表头上方为表名、表注释，表头下方每行一列
+func ParseDefinitionExcel(data []byte) (*GenDefinition, error) {
+	rows, err := excel.NewExcelUtil().ReadRows(data)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := newDefinitionBuilder()
+	table := builder.definition.Table
+	var keys []string
+	for i, row := range rows {
+		if keys == nil {
+			if len(row) > 1 {
+				switch matchDefinitionField(row[0], definitionTableFields) {
+				case "tableName":
+					table.Name = strings.TrimSpace(row[1])
+					continue
+				case "tableComment":
+					table.Comment = strings.TrimSpace(row[1])
+					continue
+				}
+			}
+			keys = definitionHeader(row)
+			continue
+		}
+
+		values := make(map[string]string, len(keys))
+		blank := true
+		for j, key := range keys {
+			if key != "" && j < len(row) {
+				values[key] = strings.TrimSpace(row[j])
+				blank = blank && values[key] == ""
+			}
+		}
+		if !blank {
+			builder.parseExcelRow(i+1, values)
+		}
+	}
+	if keys == nil {
+		return nil, fmt.Errorf("未找到列名和类型表头，请使用导入模板")
+	}
+	return builder.finish(), nil
+}
+
+// definitionHeader 识别表头行，必须包含列名和类型，否则返回nil
+func definitionHeader(row []string) []string {
+	keys := make([]string, len(row))
+	found := make(map[string]bool, len(row))
+	for i, header := range row {
+		key := matchDefinitionField(header, definitionColumnFields)
+		if key != "" && !found[key] {
+			keys[i] = key
+			found[key] = true
+		}
+	}
+	if !found["columnName"] || !found["columnType"] {
+		return nil
+	}
+	return keys
+}
+
+// parseExcelRow 解析Excel中的一行列定义，同一行的错误全部报告
+func (b *definitionBuilder) parseExcelRow(row int, values map[string]string) {
+	column := ddl.Column{Name: values["columnName"], Comment: values["columnComment"]}
+	errors := len(b.definition.Errors)
+	fail := func(format string, args ...interface{}) {
+		b.definition.addError(row, column.Name, format, args...)
+	}
+
+	columnType, length, scale, err := parseDefinitionType(values["columnType"])
+	if err != nil {
+		fail("%s", err.Error())
+	}
+	column.Type = columnType
+	if length >= 0 {
+		column.Length = length
+	}
+	if scale >= 0 {
+		column.Scale = scale
+	}
+	if value := values["length"]; value != "" {
+		if column.Length, err = strconv.Atoi(value); err != nil {
+			fail("长度'%s'不是整数", value)
+		}
+	}
+	if value := values["scale"]; value != "" {
+		if column.Scale, err = strconv.Atoi(value); err != nil {
+			fail("小数位'%s'不是整数", value)
+		}
+	}
+
+	if column.PrimaryKey, err = parseDefinitionBool(values["primaryKey"], false); err != nil {
+		fail("主键%s", err.Error())
+	}
+	if column.AutoIncrement, err = parseDefinitionBool(values["autoIncrement"], false); err != nil {
+		fail("自增%s", err.Error())
+	}
+	if column.Nullable, err = parseDefinitionBool(values["nullable"], !column.PrimaryKey); err != nil {
+		fail("可空%s", err.Error())
+	}
+	if value, ok := values["defaultValue"]; ok && value != "" {
+		column.Default = &value
+	}
+	index, unique, err := parseDefinitionIndex(values["index"])
+	if err != nil {
+		fail("%s", err.Error())
+	}
+
+	if len(b.definition.Errors) == errors {
+		b.addColumn(row, column, index, unique)
+	}
+}
+
+// definitionSchema JSON Schema表定义，列的顺序与properties中的顺序一致
+type definitionSchema struct {
+	TableName   string           `json:"x-table-name"` // 表名，未设置时使用title
+	Title       string           `json:"title"`
+	Description string           `json:"description"`
+	Properties  schemaProperties `json:"properties"`
+	Required    []string         `json:"required"`
+}
+
+// schemaProperty JSON Schema中的一列，x- 开头的扩展属性用于描述主键、自增、索引和精确类型
+type schemaProperty struct {
+	Type          interface{} `json:"type"` // 类型，可以是数组（如 ["string", "null"] 表示可空）
+	Format        string      `json:"format"`
+	Title         string      `json:"title"`
+	Description   string      `json:"description"`
+	MaxLength     *int        `json:"maxLength"`
+	Default       interface{} `json:"default"`
+	ColumnType    string      `json:"x-column-type"` // 直接指定类型，如 char(1)、text
+	Precision     int         `json:"x-precision"`   // number的精度，默认18
+	Scale         *int        `json:"x-scale"`       // number的小数位数，默认2
+	PrimaryKey    bool        `json:"x-primary-key"`
+	AutoIncrement bool        `json:"x-auto-increment"`
+	Index         string      `json:"x-index"` // index 或 unique
+}
+
+// schemaProperties 保持属性顺序的properties
+type schemaProperties []struct {
+	Name     string
+	Property schemaProperty
+}
+
+// UnmarshalJSON 按JSON中的顺序读取属性
+func (p *schemaProperties) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return fmt.Errorf("properties必须是对象")
+	}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		name, _ := token.(string)
+		var property schemaProperty
+		if err := decoder.Decode(&property); err != nil {
+			return fmt.Errorf("属性%s格式错误: %v", name, err)
+		}
+		*p = append(*p, struct {
+			Name     string
+			Property schemaProperty
+		}{name, property})
+	}
+	return nil
+}
+
+// ParseDefinitionSchema 解析JSON Schema表定义：properties中每个属性为一列，required中的列不允许为空
+func ParseDefinitionSchema(data []byte) (*GenDefinition, error) {
+	var schema definitionSchema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("JSON Schema格式错误: %v", err)
+	}
+
+	builder := newDefinitionBuilder()
+	table := builder.definition.Table
+	table.Name, table.Comment = strings.TrimSpace(schema.TableName), schema.Description
+	if table.Name == "" {
+		table.Name = strings.TrimSpace(schema.Title)
+	} else if table.Comment == "" {
+		table.Comment = schema.Title
+	}
+
+	required := make(map[string]bool, len(schema.Required))
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+	for _, item := range schema.Properties {
+		builder.parseSchemaProperty(item.Name, &item.Property, required[item.Name])
+	}
+	return builder.finish(), nil
+}
+
+// parseSchemaProperty 解析JSON Schema中的一列
+func (b *definitionBuilder) parseSchemaProperty(name string, property *schemaProperty, required bool) {
+	column := ddl.Column{
+		Name:          name,
+		Comment:       property.Title,
+		PrimaryKey:    property.PrimaryKey,
+		AutoIncrement: property.AutoIncrement,
+	}
+	if column.Comment == "" {
+		column.Comment = property.Description
+	}
+	fail := func(format string, args ...interface{}) {
+		b.definition.addError(0, name, format, args...)
+	}
+
+	var types []string
+	switch value := property.Type.(type) {
+	case string:
+		types = []string{value}
+	case []interface{}:
+		for _, item := range value {
+			types = append(types, fmt.Sprint(item))
+		}
+	}
+	schemaType, nullable := "", false
+	for _, item := range types {
+		if item == "null" {
+			nullable = true
+		} else if schemaType == "" {
+			schemaType = item
+		}
+	}
+	column.Nullable = (nullable || !required) && !column.PrimaryKey
+
+	switch {
+	case property.ColumnType != "":
+		columnType, length, scale, err := parseDefinitionType(property.ColumnType)
+		if err != nil {
+			fail("%s", err.Error())
+			return
+		}
+		column.Type, column.Length, column.Scale = columnType, length, scale
+		if length < 0 {
+			column.Length = 0
+			if property.MaxLength != nil {
+				column.Length = *property.MaxLength
+			}
+		}
+		if scale < 0 {
+			column.Scale = 0
+		}
+	case schemaType == "string" && property.Format == "date":
+		column.Type = ddl.TypeDate
+	case schemaType == "string" && property.Format == "date-time":
+		column.Type = ddl.TypeDatetime
+	case schemaType == "string":
+		column.Type = ddl.TypeVarchar
+		if property.MaxLength != nil {
+			column.Length = *property.MaxLength
+			if column.Length > 4000 {
+				column.Type, column.Length = ddl.TypeText, 0
+			}
+		}
+	case schemaType == "integer" && property.Format == "int64":
+		column.Type = ddl.TypeBigint
+	case schemaType == "integer" && property.Format == "int16":
+		column.Type = ddl.TypeSmallint
+	case schemaType == "integer":
+		column.Type = ddl.TypeInt
+	case schemaType == "number":
+		column.Type, column.Length, column.Scale = ddl.TypeDecimal, 18, 2
+		if property.Precision > 0 {
+			column.Length = property.Precision
+		}
+		if property.Scale != nil {
+			column.Scale = *property.Scale
+		}
+	case schemaType == "boolean":
+		column.Type = ddl.TypeBoolean
+	default:
+		fail("类型'%s'不支持", schemaType)
+		return
+	}
+
+	switch value := property.Default.(type) {
+	case nil:
+	case string:
+		column.Default = &value
+	case float64:
+		text := strconv.FormatFloat(value, 'f', -1, 64)
+		column.Default = &text
+	case bool:
+		text := strconv.FormatBool(value)
+		column.Default = &text
+	default:
+		fail("默认值必须是字符串、数字或布尔值")
+		return
+	}
+
+	index, unique, err := parseDefinitionIndex(property.Index)
+	if err != nil {
+		fail("%s", err.Error())
+		return
+	}
+	b.addColumn(0, column, index, unique)
+}
+
+// definitionSample 模板中的示例列
+type definitionSample struct {
+	name, columnType  string
+	primaryKey        bool
+	autoIncrement     bool
+	nullable          bool
+	defaultValue      string
+	zh, en            string
+	index             string
+	schemaType        string
+	schemaFormat      string
+	schemaColumnType  string
+	schemaMaxLength   int
+	schemaDefaultJSON string
+}
+
+// definitionSamples 模板示例：订单表
+var definitionSamples = []definitionSample{
+	{name: "order_id", columnType: "bigint", primaryKey: true, autoIncrement: true, zh: "订单ID", en: "Order ID",
+		schemaType: "integer", schemaFormat: "int64"},
+	{name: "order_no", columnType: "varchar(32)", zh: "订单编号", en: "Order No.", index: "unique",
+		schemaType: "string", schemaMaxLength: 32},
+	{name: "amount", columnType: "decimal(10,2)", defaultValue: "0", zh: "订单金额", en: "Amount",
+		schemaType: "number", schemaDefaultJSON: "0"},
+	{name: "status", columnType: "char(1)", defaultValue: "0", zh: "状态（0正常 1停用）", en: "Status (0 normal, 1 disabled)",
+		schemaType: "string", schemaColumnType: "char(1)", schemaDefaultJSON: `"0"`},
+	{name: "create_time", columnType: "datetime", nullable: true, zh: "创建时间", en: "Create Time",
+		schemaType: "string", schemaFormat: "date-time"},
+	{name: "remark", columnType: "varchar(500)", nullable: true, zh: "备注", en: "Remark",
+		schemaType: "string", schemaMaxLength: 500},
+}
+
+// DefinitionExcelTemplate 生成Excel表定义模板，lang为en时使用英文表头
+func DefinitionExcelTemplate(lang string) ([]byte, error) {
+	english := lang == "en"
+	yes, no := "是", "否"
+	indexes := map[string]string{"index": "普通", "unique": "唯一"}
+	if english {
+		yes, no = "Y", "N"
+		indexes = map[string]string{"index": "index", "unique": "unique"}
+	}
+	flag := func(value bool) string {
+		if value {
+			return yes
+		}
+		return no
+	}
+
+	fields := make([]*excel.ExcelField, len(definitionColumnFields))
+	for i, field := range definitionColumnFields {
+		fields[i] = &excel.ExcelField{Name: field.zh, Width: 14}
+		if english {
+			fields[i].Name = field.en
+		}
+		switch field.key {
+		case "columnName", "columnComment":
+			fields[i].Width = 24
+		case "columnType":
+			fields[i].Combo = []string{ddl.TypeVarchar, ddl.TypeChar, ddl.TypeText, ddl.TypeSmallint, ddl.TypeInt,
+				ddl.TypeBigint, ddl.TypeDecimal, ddl.TypeBoolean, ddl.TypeDate, ddl.TypeDatetime}
+			fields[i].Prompt = "可直接填写长度，如 varchar(64)、decimal(10,2)"
+			if english {
+				fields[i].Prompt = "Length may be inline, e.g. varchar(64), decimal(10,2)"
+			}
+		case "primaryKey", "autoIncrement", "nullable":
+			fields[i].Combo = []string{yes, no}
+		case "index":
+			fields[i].Combo = []string{indexes["index"], indexes["unique"]}
+		}
+	}
+
+	rows := make([][]interface{}, len(definitionSamples))
+	for i, sample := range definitionSamples {
+		comment := sample.zh
+		if english {
+			comment = sample.en
+		}
+		rows[i] = []interface{}{sample.name, sample.columnType, "", "", flag(sample.primaryKey), flag(sample.autoIncrement),
+			flag(sample.nullable), sample.defaultValue, comment, indexes[sample.index]}
+	}
+
+	preface := [][]string{{definitionTableFields[0].zh, "biz_order"}, {definitionTableFields[1].zh, "订单"}}
+	sheetName := "表定义"
+	if english {
+		preface = [][]string{{definitionTableFields[0].en, "biz_order"}, {definitionTableFields[1].en, "Order"}}
+		sheetName = "Table"
+	}
+	return excel.NewExcelUtil().ImportTemplate(fields, preface, rows, sheetName)
+}
+
+// DefinitionSchemaTemplate 生成JSON Schema表定义模板，lang为en时使用英文注释
+func DefinitionSchemaTemplate(lang string) []byte {
+	english := lang == "en"
+	var buf bytes.Buffer
+	title := "订单"
+	if english {
+		title = "Order"
+	}
+	buf.WriteString("{\n")
+	buf.WriteString(`  "$schema": "http://json-schema.org/draft-07/schema#",` + "\n")
+	buf.WriteString(`  "x-table-name": "biz_order",` + "\n")
+	fmt.Fprintf(&buf, "  \"title\": %s,\n", jsonString(title))
+	buf.WriteString(`  "type": "object",` + "\n")
+	buf.WriteString(`  "properties": {` + "\n")
+
+	var required []string
+	for i, sample := range definitionSamples {
+		comment := sample.zh
+		if english {
+			comment = sample.en
+		}
+		attrs := []string{fmt.Sprintf(`"type": %s`, jsonString(sample.schemaType))}
+		if sample.schemaFormat != "" {
+			attrs = append(attrs, fmt.Sprintf(`"format": %s`, jsonString(sample.schemaFormat)))
+		}
+		if sample.schemaMaxLength > 0 {
+			attrs = append(attrs, fmt.Sprintf(`"maxLength": %d`, sample.schemaMaxLength))
+		}
+		if sample.schemaColumnType != "" {
+			attrs = append(attrs, fmt.Sprintf(`"x-column-type": %s`, jsonString(sample.schemaColumnType)))
+		}
+		if sample.schemaType == "number" {
+			attrs = append(attrs, `"x-precision": 10`, `"x-scale": 2`)
+		}
+		if sample.schemaDefaultJSON != "" {
+			attrs = append(attrs, `"default": `+sample.schemaDefaultJSON)
+		}
+		if sample.primaryKey {
+			attrs = append(attrs, `"x-primary-key": true`)
+		}
+		if sample.autoIncrement {
+			attrs = append(attrs, `"x-auto-increment": true`)
+		}
+		if sample.index != "" {
+			attrs = append(attrs, fmt.Sprintf(`"x-index": %s`, jsonString(sample.index)))
+		}
+		attrs = append(attrs, fmt.Sprintf(`"title": %s`, jsonString(comment)))
+
+		separator := ","
+		if i == len(definitionSamples)-1 {
+			separator = ""
+		}
+		fmt.Fprintf(&buf, "    %s: {%s}%s\n", jsonString(sample.name), strings.Join(attrs, ", "), separator)
+		if !sample.nullable && !sample.primaryKey {
+			required = append(required, jsonString(sample.name))
+		}
+	}
+	buf.WriteString("  },\n")
+	fmt.Fprintf(&buf, "  \"required\": [%s]\n", strings.Join(required, ", "))
+	buf.WriteString("}\n")
+	return buf.Bytes()
+}
+
+// ImportDefinition 将表定义导入代码生成（不创建数据库表），列类型按当前数据库方言转换
+func (s *GenService) ImportDefinition(table *ddl.Table, operName string) (*model.GenTable, error) {
+	fmt.Printf("GenService.ImportDefinition: 导入表定义, TableName=%s\n", table.Name)
+
+	dialect, err := currentDialect()
+	if err != nil {
+		return nil, err
+	}
+	if err := table.Validate(); err != nil {
+		return nil, err
+	}
+	unique, err := s.genDao.CheckTableNameUnique(table.Name)
+	if err != nil {
+		return nil, err
+	}
+	if !unique {
+		return nil, fmt.Errorf("表'%s'已导入代码生成", table.Name)
+	}
+
+	genTable := s.initTable(table.Name, operName)
+	if table.Comment != "" {
+		genTable.TableComment = table.Comment
+		genTable.FunctionName = table.Comment
+	}
+
+	dbColumns := make([]model.DbTableColumn, len(table.Columns))
+	for i, column := range table.Columns {
+		columnType := dialect.ColumnType(&column)
+		dbColumns[i] = model.DbTableColumn{
+			ColumnName:    column.Name,
+			ColumnComment: column.Comment,
+			ColumnType:    columnType,
+			DataType:      strings.SplitN(columnType, "(", 2)[0],
+		}
+		if column.PrimaryKey {
+			dbColumns[i].ColumnKey = "PRI"
+		}
+		if column.AutoIncrement {
+			dbColumns[i].Extra = "auto_increment"
+		}
+	}
+	s.initColumnField(genTable, dbColumns)
+
+	// 表定义中不允许为空且没有默认值的列在表单中必填
+	for i := range genTable.Columns {
+		column := &genTable.Columns[i]
+		definition := table.Columns[i]
+		if column.IsInsertField() && !definition.Nullable && definition.Default == nil {
+			column.IsRequired = "1"
+		}
+	}
+
+	if err := s.insertGenTable(genTable); err != nil {
+		return nil, err
+	}
+	return genTable, nil
+}
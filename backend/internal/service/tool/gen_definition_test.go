@@ -0,0 +1,173 @@
+package tool
+
+import (
+	"testing"
+	"wosm/pkg/ddl"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+// newDefinitionExcel 生成测试用的表定义Excel
+func newDefinitionExcel(t *testing.T, rows [][]interface{}) []byte {
+	file := excelize.NewFile()
+	for i, row := range rows {
+		cell, err := excelize.CoordinatesToCellName(1, i+1)
+		require.NoError(t, err)
+		require.NoError(t, file.SetSheetRow("Sheet1", cell, &row))
+	}
+	buffer, err := file.WriteToBuffer()
+	require.NoError(t, err)
+	return buffer.Bytes()
+}
+
+func TestDefinitionTemplates(t *testing.T) {
+	tests := []struct {
+		name  string
+		parse func(lang string) (*GenDefinition, error)
+	}{
+		{"excel", func(lang string) (*GenDefinition, error) {
+			data, err := DefinitionExcelTemplate(lang)
+			if err != nil {
+				return nil, err
+			}
+			return ParseDefinitionExcel(data)
+		}},
+		{"json", func(lang string) (*GenDefinition, error) {
+			return ParseDefinitionSchema(DefinitionSchemaTemplate(lang))
+		}},
+	}
+	for _, tt := range tests {
+		for _, lang := range []string{"zh", "en"} {
+			t.Run(tt.name+"_"+lang, func(t *testing.T) {
+				definition, err := tt.parse(lang)
+				require.NoError(t, err)
+				require.Empty(t, definition.Errors)
+
+				table := definition.Table
+				assert.Equal(t, "biz_order", table.Name)
+				require.Len(t, table.Columns, len(definitionSamples))
+				assert.Equal(t, []string{"order_id"}, table.PrimaryKeys())
+				assert.True(t, table.Columns[0].AutoIncrement)
+				assert.Equal(t, ddl.Column{Name: "order_no", Type: ddl.TypeVarchar, Length: 32, Comment: table.Columns[1].Comment}, table.Columns[1])
+				assert.Equal(t, 10, table.Columns[2].Length)
+				assert.Equal(t, 2, table.Columns[2].Scale)
+				assert.Equal(t, "0", *table.Columns[3].Default)
+				assert.True(t, table.Columns[4].Nullable)
+				assert.Equal(t, []ddl.Index{{Name: "uk_biz_order_order_no", Columns: []string{"order_no"}, Unique: true}}, table.Indexes)
+				if lang == "en" {
+					assert.Equal(t, "Order No.", table.Columns[1].Comment)
+				} else {
+					assert.Equal(t, "订单编号", table.Columns[1].Comment)
+				}
+			})
+		}
+	}
+}
+
+func TestParseDefinitionExcel(t *testing.T) {
+	data := newDefinitionExcel(t, [][]interface{}{
+		{"Table Name", "biz_item"},
+		{},
+		{"Column Name", "类型", "Length", "主键", "Nullable", "Comment"},
+		{"item_id", "长整数", "", "是", "", "Item ID"},
+		{"item_name", "varchar", "abc", "", "N", "名称"},
+		{"price", "money", "", "", "", "价格"},
+		{"item_id", "int", "", "", "", "重复"},
+		{},
+		{"flag", "bit", "", "maybe", "", ""},
+	})
+
+	definition, err := ParseDefinitionExcel(data)
+	require.NoError(t, err)
+	assert.Equal(t, "biz_item", definition.Table.Name)
+	assert.Equal(t, []GenDefinitionError{
+		{Row: 5, Column: "item_name", Message: "长度'abc'不是整数"},
+		{Row: 6, Column: "price", Message: "类型'money'不支持"},
+		{Row: 7, Column: "item_id", Message: "列名'item_id'与第4行重复"},
+		{Row: 9, Column: "flag", Message: "主键'maybe'应为是或否"},
+	}, definition.Errors)
+	require.Len(t, definition.Table.Columns, 1)
+	assert.False(t, definition.Table.Columns[0].Nullable)
+
+	_, err = ParseDefinitionExcel(newDefinitionExcel(t, [][]interface{}{{"a", "b"}}))
+	assert.Error(t, err, "缺少表头")
+}
+
+func TestParseDefinitionSchema(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		columns []ddl.Column
+		errors  []GenDefinitionError
+	}{
+		{
+			name:   "类型映射",
+			schema: `{"title": "biz_user", "properties": {"user_id": {"type": "integer", "format": "int64", "x-primary-key": true}, "nick": {"type": ["string", "null"], "maxLength": 30, "description": "Nick name"}, "bio": {"type": "string", "maxLength": 8000}, "age": {"type": "integer", "format": "int16", "default": 18}, "birthday": {"type": "string", "format": "date"}}, "required": ["nick", "age"]}`,
+			columns: []ddl.Column{
+				{Name: "user_id", Type: ddl.TypeBigint, PrimaryKey: true},
+				{Name: "nick", Type: ddl.TypeVarchar, Length: 30, Nullable: true, Comment: "Nick name"},
+				{Name: "bio", Type: ddl.TypeText, Nullable: true},
+				{Name: "age", Type: ddl.TypeSmallint, Default: func() *string { s := "18"; return &s }()},
+				{Name: "birthday", Type: ddl.TypeDate, Nullable: true},
+			},
+		},
+		{
+			name:   "列错误",
+			schema: `{"x-table-name": "biz_user", "properties": {"id": {"type": "object"}, "code": {"type": "string", "x-auto-increment": true}, "tag": {"type": "string", "x-index": "fulltext"}}}`,
+			errors: []GenDefinitionError{
+				{Column: "id", Message: "类型'object'不支持"},
+				{Column: "code", Message: "自增列'code'必须是int或bigint类型"},
+				{Column: "tag", Message: "索引'fulltext'应为普通或唯一"},
+			},
+		},
+		{
+			name:   "缺少表名",
+			schema: `{"properties": {"id": {"type": "integer", "x-primary-key": true}}}`,
+			columns: []ddl.Column{
+				{Name: "id", Type: ddl.TypeInt, PrimaryKey: true},
+			},
+			errors: []GenDefinitionError{{Message: "表名不能为空"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			definition, err := ParseDefinitionSchema([]byte(tt.schema))
+			require.NoError(t, err)
+			assert.Equal(t, tt.columns, definition.Table.Columns)
+			assert.Equal(t, tt.errors, definition.Errors)
+		})
+	}
+
+	_, err := ParseDefinitionSchema([]byte(`{"properties": [1]}`))
+	assert.Error(t, err)
+}
+
+func TestParseDefinitionType(t *testing.T) {
+	tests := []struct {
+		value      string
+		columnType string
+		length     int
+		scale      int
+		wantErr    bool
+	}{
+		{"varchar(64)", ddl.TypeVarchar, 64, -1, false},
+		{"NVARCHAR(MAX)", ddl.TypeVarchar, 0, -1, false},
+		{"decimal(10, 2)", ddl.TypeDecimal, 10, 2, false},
+		{"日期时间", ddl.TypeDatetime, -1, -1, false},
+		{"varchar(64", "", 0, 0, true},
+		{"geometry", "", 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			columnType, length, scale, err := parseDefinitionType(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []interface{}{tt.columnType, tt.length, tt.scale}, []interface{}{columnType, length, scale})
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package tool
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"wosm/internal/repository/model"
+)
+
+// DetectDrift 对比业务表与数据库表结构，返回差异报告（不修改业务表）
+func (s *GenService) DetectDrift(tableName string) (*model.GenTableDrift, error) {
+	table, err := s.genDao.SelectGenTableByName(tableName)
+	if err != nil {
+		return nil, err
+	}
+	if table == nil {
+		return nil, fmt.Errorf("业务表不存在")
+	}
+	drift, _, _, err := s.detectDrift(table)
+	return drift, err
+}
+
+// ScanDrift 扫描所有已导入的业务表，返回与数据库不一致的表
+func (s *GenService) ScanDrift() ([]model.GenTableDrift, error) {
+	fmt.Printf("GenService.ScanDrift: 扫描业务表结构差异\n")
+
+	tables, err := s.genDao.SelectGenTableAll()
+	if err != nil {
+		return nil, err
+	}
+
+	drifts := make([]model.GenTableDrift, 0)
+	for i := range tables {
+		drift, _, _, err := s.detectDrift(&tables[i])
+		if err != nil {
+			return nil, err
+		}
+		if drift.HasDrift() {
+			drifts = append(drifts, *drift)
+		}
+	}
+	return drifts, nil
+}
+
+// SynchDb 同步数据库 对应Java后端的synchDb
+// 只应用选中列的差异：新增列按默认规则初始化，删除列移除字段，类型和注释变化只更新对应属性，
+// 未选中或没有变化的字段保留用户调整过的显示类型、查询方式和字典类型；返回同步后剩余的差异
+func (s *GenService) SynchDb(tableName string, req *model.GenSynchRequest, operName string) (*model.GenTableDrift, error) {
+	fmt.Printf("GenService.SynchDb: 同步数据库, TableName=%s, Columns=%v\n", tableName, req.Columns)
+
+	table, err := s.genDao.SelectGenTableByName(tableName)
+	if err != nil {
+		return nil, err
+	}
+	if table == nil {
+		return nil, fmt.Errorf("业务表不存在")
+	}
+	drift, dbColumns, columns, err := s.detectDrift(table)
+	if err != nil {
+		return nil, err
+	}
+	if !drift.TableExists {
+		return nil, fmt.Errorf("数据库表'%s'不存在", tableName)
+	}
+
+	selected := make(map[string]bool, len(req.Columns))
+	for _, name := range req.Columns {
+		selected[strings.ToLower(name)] = true
+	}
+	current := make(map[string]*model.GenTableColumn, len(columns))
+	for i := range columns {
+		current[strings.ToLower(columns[i].ColumnName)] = &columns[i]
+	}
+
+	now := time.Now()
+	var inserts, updates []model.GenTableColumn
+	var deleteIds []int64
+	for i, dbColumn := range dbColumns {
+		key := strings.ToLower(dbColumn.ColumnName)
+		if !selected[key] {
+			continue
+		}
+		column, ok := current[key]
+		if !ok {
+			inserted := s.newColumnField(table.TableID, dbColumn, i+1)
+			inserted.CreateBy = operName
+			inserted.CreateTime = &now
+			inserts = append(inserts, inserted)
+			continue
+		}
+		if !columnChanged(column, &dbColumn) {
+			continue
+		}
+		column.ColumnType = dbColumn.ColumnType
+		column.JavaType = model.GetJavaType(dbColumn.DataType)
+		column.IsPk = boolFlag(dbColumn.ColumnKey == "PRI")
+		column.IsIncrement = boolFlag(dbColumn.Extra == "auto_increment")
+		column.ColumnComment = dbColumn.ColumnComment
+		column.UpdateBy = operName
+		column.UpdateTime = &now
+		updates = append(updates, *column)
+	}
+	for _, change := range drift.Columns {
+		if change.ChangeType == model.GenDriftRemoved && selected[strings.ToLower(change.ColumnName)] {
+			deleteIds = append(deleteIds, current[strings.ToLower(change.ColumnName)].ColumnID)
+		}
+	}
+
+	table.TableComment = drift.NewTableComment
+	table.UpdateBy = operName
+	table.UpdateTime = &now
+	if err := s.genDao.SynchGenTableColumns(table, req.TableComment, inserts, updates, deleteIds); err != nil {
+		return nil, err
+	}
+
+	return s.DetectDrift(tableName)
+}
+
+// detectDrift 对比业务表与数据库表结构，同时返回查询到的数据库列和业务字段
+func (s *GenService) detectDrift(table *model.GenTable) (*model.GenTableDrift, []model.DbTableColumn, []model.GenTableColumn, error) {
+	drift := &model.GenTableDrift{
+		TableID:         table.TableID,
+		TableName:       table.Name,
+		OldTableComment: table.TableComment,
+		NewTableComment: table.TableComment,
+		Columns:         []model.GenColumnDrift{},
+	}
+
+	dbTables, err := s.genDao.SelectDbTableListByNames([]string{table.Name})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if len(dbTables) == 0 {
+		return drift, nil, nil, nil
+	}
+	drift.TableExists = true
+	drift.NewTableComment = dbTables[0].TableComment
+
+	dbColumns, err := s.genDao.SelectDbTableColumnsByName(table.Name)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	columns, err := s.genDao.SelectGenTableColumnListByTableId(table.TableID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	drift.Columns = diffColumns(columns, dbColumns)
+	return drift, dbColumns, columns, nil
+}
+
+// diffColumns 按列名（不区分大小写）对比业务字段与数据库列，差异按数据库列顺序排列，删除的列排在最后
+func diffColumns(columns []model.GenTableColumn, dbColumns []model.DbTableColumn) []model.GenColumnDrift {
+	current := make(map[string]*model.GenTableColumn, len(columns))
+	for i := range columns {
+		current[strings.ToLower(columns[i].ColumnName)] = &columns[i]
+	}
+
+	drifts := make([]model.GenColumnDrift, 0)
+	seen := make(map[string]bool, len(dbColumns))
+	for i := range dbColumns {
+		dbColumn := &dbColumns[i]
+		key := strings.ToLower(dbColumn.ColumnName)
+		seen[key] = true
+		column, ok := current[key]
+		if !ok {
+			drifts = append(drifts, model.GenColumnDrift{
+				ColumnName: dbColumn.ColumnName,
+				ChangeType: model.GenDriftAdded,
+				NewType:    columnTypeLabel(dbColumn.ColumnType, dbColumn.ColumnKey == "PRI", dbColumn.Extra == "auto_increment"),
+				NewComment: dbColumn.ColumnComment,
+			})
+			continue
+		}
+		oldType := columnTypeLabel(column.ColumnType, column.IsPk == "1", column.IsIncrement == "1")
+		newType := columnTypeLabel(dbColumn.ColumnType, dbColumn.ColumnKey == "PRI", dbColumn.Extra == "auto_increment")
+		if !strings.EqualFold(oldType, newType) {
+			drifts = append(drifts, model.GenColumnDrift{
+				ColumnName: column.ColumnName,
+				ChangeType: model.GenDriftTypeChanged,
+				OldType:    oldType,
+				NewType:    newType,
+			})
+		}
+		if column.ColumnComment != dbColumn.ColumnComment {
+			drifts = append(drifts, model.GenColumnDrift{
+				ColumnName: column.ColumnName,
+				ChangeType: model.GenDriftCommentChanged,
+				OldComment: column.ColumnComment,
+				NewComment: dbColumn.ColumnComment,
+			})
+		}
+	}
+	for _, column := range columns {
+		if !seen[strings.ToLower(column.ColumnName)] {
+			drifts = append(drifts, model.GenColumnDrift{
+				ColumnName: column.ColumnName,
+				ChangeType: model.GenDriftRemoved,
+				OldType:    columnTypeLabel(column.ColumnType, column.IsPk == "1", column.IsIncrement == "1"),
+				OldComment: column.ColumnComment,
+			})
+		}
+	}
+	return drifts
+}
+
+// columnChanged 业务字段与数据库列是否不一致
+func columnChanged(column *model.GenTableColumn, dbColumn *model.DbTableColumn) bool {
+	return len(diffColumns([]model.GenTableColumn{*column}, []model.DbTableColumn{*dbColumn})) > 0
+}
+
+// columnTypeLabel 列类型及主键、自增属性的显示文本
+func columnTypeLabel(columnType string, pk, increment bool) string {
+	label := columnType
+	if pk {
+		label += " 主键"
+	}
+	if increment {
+		label += " 自增"
+	}
+	return label
+}
+
+// boolFlag 布尔值转换为"1"/"0"
+func boolFlag(value bool) string {
+	if value {
+		return "1"
+	}
+	return "0"
+}
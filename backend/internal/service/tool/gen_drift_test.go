@@ -0,0 +1,33 @@
+package tool
+
+import (
+	"testing"
+	"wosm/internal/repository/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffColumns(t *testing.T) {
+	columns := []model.GenTableColumn{
+		{ColumnName: "order_id", ColumnType: "bigint", IsPk: "1", IsIncrement: "1", ColumnComment: "订单ID"},
+		{ColumnName: "status", ColumnType: "char(1)", ColumnComment: "状态", HtmlType: model.HtmlTypeRadio, DictType: "order_status"},
+		{ColumnName: "amount", ColumnType: "int", ColumnComment: "金额"},
+		{ColumnName: "legacy", ColumnType: "nvarchar(10)", ColumnComment: "旧字段"},
+	}
+	dbColumns := []model.DbTableColumn{
+		{ColumnName: "ORDER_ID", ColumnType: "BIGINT", ColumnKey: "PRI", Extra: "auto_increment", ColumnComment: "订单ID"},
+		{ColumnName: "status", ColumnType: "char(1)", ColumnComment: "订单状态"},
+		{ColumnName: "amount", ColumnType: "decimal(10,2)", DataType: "decimal", ColumnComment: "金额"},
+		{ColumnName: "paid_time", ColumnType: "datetime", ColumnComment: "支付时间"},
+	}
+
+	assert.Equal(t, []model.GenColumnDrift{
+		{ColumnName: "status", ChangeType: model.GenDriftCommentChanged, OldComment: "状态", NewComment: "订单状态"},
+		{ColumnName: "amount", ChangeType: model.GenDriftTypeChanged, OldType: "int", NewType: "decimal(10,2)"},
+		{ColumnName: "paid_time", ChangeType: model.GenDriftAdded, NewType: "datetime", NewComment: "支付时间"},
+		{ColumnName: "legacy", ChangeType: model.GenDriftRemoved, OldType: "nvarchar(10)", OldComment: "旧字段"},
+	}, diffColumns(columns, dbColumns))
+
+	assert.True(t, columnChanged(&columns[0], &model.DbTableColumn{ColumnName: "order_id", ColumnType: "bigint", ColumnComment: "订单ID"}))
+	assert.False(t, columnChanged(&columns[0], &dbColumns[0]))
+}
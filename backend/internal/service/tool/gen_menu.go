@@ -0,0 +1,171 @@
+package tool
+
+import (
+	"fmt"
+	"time"
+	"wosm/internal/repository/model"
+)
+
+// genMenuButtons 安装模块菜单时创建的按钮权限，列表权限设置在页面菜单上
+var genMenuButtons = []struct {
+	name   string
+	action string
+}{
+	{"查询", "query"},
+	{"新增", "add"},
+	{"修改", "edit"},
+	{"删除", "remove"},
+	{"导出", "export"},
+}
+
+// BuildModuleMenu 构建业务表的模块菜单树：模块目录 -> 页面菜单 -> 按钮权限
+// 配置了上级菜单时挂在上级菜单下；否则使用（或新建）与模块名同名的顶级目录
+func (s *GenService) BuildModuleMenu(table *model.GenTable, operName string) (*model.SysMenu, error) {
+	prefix := fmt.Sprintf("%s:%s", table.ModuleName, table.BusinessName)
+	page := model.SysMenu{
+		MenuName:  table.FunctionName,
+		OrderNum:  1,
+		Path:      table.BusinessName,
+		Component: fmt.Sprintf("%s/%s/index", table.ModuleName, table.BusinessName),
+		IsFrame:   "1",
+		IsCache:   "0",
+		MenuType:  "C",
+		Visible:   "0",
+		Status:    "0",
+		Perms:     prefix + ":list",
+		Icon:      "#",
+		CreateBy:  operName,
+		Remark:    table.FunctionName + "菜单",
+	}
+	for i, button := range genMenuButtons {
+		page.Children = append(page.Children, model.SysMenu{
+			MenuName: table.FunctionName + button.name,
+			OrderNum: i + 1,
+			Path:     "#",
+			IsFrame:  "1",
+			IsCache:  "0",
+			MenuType: "F",
+			Visible:  "0",
+			Status:   "0",
+			Perms:    prefix + ":" + button.action,
+			Icon:     "#",
+			CreateBy: operName,
+		})
+	}
+
+	if table.ParentMenuId > 0 {
+		parent, err := s.menuService.SelectMenuById(table.ParentMenuId)
+		if err != nil {
+			return nil, err
+		}
+		if parent == nil {
+			return nil, fmt.Errorf("上级菜单不存在")
+		}
+		if parent.MenuType == "F" {
+			return nil, fmt.Errorf("上级菜单不能是按钮")
+		}
+		parent.Children = []model.SysMenu{page}
+		return parent, nil
+	}
+
+	directory, err := s.menuService.SelectMenuByPath(0, table.ModuleName, "M")
+	if err != nil {
+		return nil, err
+	}
+	if directory == nil {
+		directory = &model.SysMenu{
+			MenuName: table.ModuleName,
+			OrderNum: 10,
+			Path:     table.ModuleName,
+			IsFrame:  "1",
+			IsCache:  "0",
+			MenuType: "M",
+			Visible:  "0",
+			Status:   "0",
+			Icon:     "component",
+			CreateBy: operName,
+			Remark:   "代码生成模块目录",
+		}
+	}
+	directory.Children = []model.SysMenu{page}
+	return directory, nil
+}
+
+// InstallMenu 安装模块菜单：在一个事务中创建模块目录、页面菜单和按钮权限，并授权给指定角色
+func (s *GenService) InstallMenu(tableId int64, roleIds []int64, operName string) (*model.GenTable, error) {
+	fmt.Printf("GenService.InstallMenu: 安装模块菜单, TableID=%d, RoleIDs=%v\n", tableId, roleIds)
+
+	table, err := s.SelectGenTableById(tableId)
+	if err != nil {
+		return nil, err
+	}
+	if table == nil {
+		return nil, fmt.Errorf("表不存在")
+	}
+	if table.MenuId > 0 {
+		menu, err := s.menuService.SelectMenuById(table.MenuId)
+		if err != nil {
+			return nil, err
+		}
+		if menu != nil {
+			return nil, fmt.Errorf("模块菜单已安装，请先卸载")
+		}
+	}
+
+	root, err := s.BuildModuleMenu(table, operName)
+	if err != nil {
+		return nil, err
+	}
+	createdRoot := root.MenuID == 0
+	if err := s.menuService.InsertMenuTree(root, roleIds); err != nil {
+		return nil, err
+	}
+
+	// 记录安装的菜单，卸载时使用
+	table.MenuId = root.Children[0].MenuID
+	table.InstallParentMenuId = 0
+	if createdRoot {
+		table.InstallParentMenuId = root.MenuID
+	}
+	if err := s.saveOptions(table, operName); err != nil {
+		return nil, err
+	}
+
+	return table, nil
+}
+
+// UninstallMenu 卸载模块菜单：删除页面菜单、按钮权限及其角色授权，安装时新建的模块目录为空时一并删除
+func (s *GenService) UninstallMenu(tableId int64, operName string) error {
+	fmt.Printf("GenService.UninstallMenu: 卸载模块菜单, TableID=%d\n", tableId)
+
+	table, err := s.SelectGenTableById(tableId)
+	if err != nil {
+		return err
+	}
+	if table == nil {
+		return fmt.Errorf("表不存在")
+	}
+	if table.MenuId == 0 {
+		return fmt.Errorf("模块菜单未安装")
+	}
+
+	if err := s.menuService.DeleteMenuTree(table.MenuId, table.InstallParentMenuId); err != nil {
+		return err
+	}
+
+	table.MenuId = 0
+	table.InstallParentMenuId = 0
+	return s.saveOptions(table, operName)
+}
+
+// saveOptions 只保存生成选项（已安装菜单、发布状态），不影响业务表的其它配置
+func (s *GenService) saveOptions(table *model.GenTable, operName string) error {
+	table.BuildOptions()
+	now := time.Now()
+	return s.genDao.UpdateGenTable(&model.GenTable{
+		TableID:    table.TableID,
+		Options:    table.Options,
+		UpdateBy:   operName,
+		UpdateTime: &now,
+	})
+}
@@ -0,0 +1,45 @@
+package tool
+
+import (
+	"fmt"
+	"wosm/internal/repository/model"
+)
+
+// PublishTable 发布或取消发布运行时模块，发布后通过 /dyn/{业务名} 按字段元数据直接增删改查
+// 发布前校验表结构可用于运行时模块，且已发布的模块中业务名唯一
+func (s *GenService) PublishTable(tableId int64, published bool, operName string) (*model.GenTable, error) {
+	fmt.Printf("GenService.PublishTable: 发布运行时模块, TableID=%d, Published=%v\n", tableId, published)
+
+	table, err := s.SelectGenTableById(tableId)
+	if err != nil {
+		return nil, err
+	}
+	if table == nil {
+		return nil, fmt.Errorf("表不存在")
+	}
+
+	if published {
+		if table.Columns, err = s.genDao.SelectGenTableColumnListByTableId(tableId); err != nil {
+			return nil, err
+		}
+		if _, err := NewDynModule(table); err != nil {
+			return nil, err
+		}
+		tables, err := s.genDao.SelectGenTableListByBusinessName(table.BusinessName)
+		if err != nil {
+			return nil, err
+		}
+		for i := range tables {
+			tables[i].SetTableFromOptions()
+			if tables[i].TableID != tableId && tables[i].Published {
+				return nil, fmt.Errorf("业务名'%s'已被表%s发布", table.BusinessName, tables[i].Name)
+			}
+		}
+	}
+
+	table.Published = published
+	if err := s.saveOptions(table, operName); err != nil {
+		return nil, err
+	}
+	return table, nil
+}
@@ -1,16 +1,25 @@
 package tool
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"strings"
 	"time"
+	"wosm/internal/config"
 	"wosm/internal/repository/dao"
 	"wosm/internal/repository/model"
+	systemService "wosm/internal/service/system"
+	"wosm/pkg/database"
+	"wosm/pkg/ddl"
 )
 
 // GenService 代码生成服务 对应Java后端的IGenTableService
 type GenService struct {
 	genDao         *dao.GenDao
+	templateDao    *dao.GenTemplateDao
+	menuService    *systemService.MenuService
 	templateEngine *TemplateEngine
 }
 
@@ -18,14 +27,34 @@ type GenService struct {
 func NewGenService() *GenService {
 	return &GenService{
 		genDao:         dao.NewGenDao(),
+		templateDao:    dao.NewGenTemplateDao(),
+		menuService:    systemService.NewMenuService(),
 		templateEngine: NewTemplateEngine(),
 	}
 }
 
+// WithContext 返回使用指定context的服务实例，数据访问携带请求信息和租户
+func (s *GenService) WithContext(ctx context.Context) *GenService {
+	return &GenService{
+		genDao:         s.genDao.WithContext(ctx),
+		templateDao:    s.templateDao.WithContext(ctx),
+		menuService:    s.menuService.WithContext(ctx),
+		templateEngine: s.templateEngine,
+	}
+}
+
 // SelectGenTableList 查询业务表集合 对应Java后端的selectGenTableList
 func (s *GenService) SelectGenTableList(genTable *model.GenTable) ([]model.GenTable, error) {
 	fmt.Printf("GenService.SelectGenTableList: 查询业务表列表\n")
-	return s.genDao.SelectGenTableList(genTable)
+	tables, err := s.genDao.SelectGenTableList(genTable)
+	if err != nil {
+		return nil, err
+	}
+	// 列表需要显示模块菜单的安装状态
+	for i := range tables {
+		tables[i].SetTableFromOptions()
+	}
+	return tables, nil
 }
 
 // SelectDbTableList 查询数据库表集合 对应Java后端的selectDbTableList
@@ -37,7 +66,13 @@ func (s *GenService) SelectDbTableList(tableName, tableComment string) ([]model.
 // SelectGenTableById 查询业务表信息 对应Java后端的selectGenTableById
 func (s *GenService) SelectGenTableById(id int64) (*model.GenTable, error) {
 	fmt.Printf("GenService.SelectGenTableById: 查询业务表信息, ID=%d\n", id)
-	return s.genDao.SelectGenTableById(id)
+	table, err := s.genDao.SelectGenTableById(id)
+	if err != nil || table == nil {
+		return table, err
+	}
+	// 编辑页面需要回显树表、上级菜单和模板组配置
+	table.SetTableFromOptions()
+	return table, nil
 }
 
 // SelectGenTableByName 根据表名称查询业务表信息 对应Java后端的selectGenTableByName
@@ -54,6 +89,9 @@ func (s *GenService) UpdateGenTable(genTable *model.GenTable) error {
 	now := time.Now()
 	genTable.UpdateTime = &now
 
+	// 树表和上级菜单配置保存到生成选项中
+	genTable.BuildOptions()
+
 	return s.genDao.UpdateGenTable(genTable)
 }
 
@@ -63,18 +101,79 @@ func (s *GenService) DeleteGenTableByIds(ids []int) error {
 	return s.genDao.DeleteGenTableByIds(ids)
 }
 
-// CreateTable 创建表结构 对应Java后端的createTable
-func (s *GenService) CreateTable(sql, operName string) error {
-	fmt.Printf("GenService.CreateTable: 创建表结构, SQL=%s\n", sql)
+// CreateTablePlan 建表预览：待执行的DDL及确认令牌
+type CreateTablePlan struct {
+	TableNames []string `json:"tableNames"` // 要创建的表
+	Statements []string `json:"statements"` // 按顺序执行的DDL
+	Token      string   `json:"token"`      // 确认令牌，DDL变化后失效
+}
 
-	// 执行SQL创建表
-	err := s.genDao.CreateTable(sql)
+// PlanCreateTable 预览建表语句：表定义按当前数据库方言渲染；手写SQL只允许CREATE TABLE语句
+func (s *GenService) PlanCreateTable(table *ddl.Table, sql string) (*CreateTablePlan, error) {
+	dialect, err := currentDialect()
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	plan := &CreateTablePlan{}
+	if table != nil {
+		if plan.Statements, err = dialect.CreateTable(table); err != nil {
+			return nil, err
+		}
+		plan.TableNames = []string{table.Name}
+	} else {
+		if strings.TrimSpace(sql) == "" {
+			return nil, fmt.Errorf("表定义和建表语句不能同时为空")
+		}
+		statements, err := dialect.ParseCreateTable(sql)
+		if err != nil {
+			return nil, err
+		}
+		seen := make(map[string]bool, len(statements))
+		for _, statement := range statements {
+			if seen[strings.ToLower(statement.TableName)] {
+				return nil, fmt.Errorf("表'%s'重复创建", statement.TableName)
+			}
+			seen[strings.ToLower(statement.TableName)] = true
+			plan.TableNames = append(plan.TableNames, statement.TableName)
+			plan.Statements = append(plan.Statements, statement.SQL)
+		}
+	}
+
+	existing, err := s.genDao.SelectDbTableListByNames(plan.TableNames)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		return nil, fmt.Errorf("表'%s'已存在", existing[0].TableName)
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(plan.Statements, "\n;\n")))
+	plan.Token = hex.EncodeToString(sum[:])
+	return plan, nil
+}
+
+// CreateTable 创建表结构 对应Java后端的createTable
+// 重新生成DDL并与预览时的令牌比对，一致时才执行，创建成功后导入代码生成
+func (s *GenService) CreateTable(table *ddl.Table, sql, token, operName string) (*CreateTablePlan, error) {
+	plan, err := s.PlanCreateTable(table, sql)
+	if err != nil {
+		return nil, err
+	}
+	if token == "" || token != plan.Token {
+		return nil, fmt.Errorf("表结构已变化，请重新预览")
+	}
+	fmt.Printf("GenService.CreateTable: 创建表结构, TableNames=%v\n", plan.TableNames)
+
+	if err := s.genDao.CreateTable(plan.Statements); err != nil {
+		return nil, err
+	}
+	if err := s.ImportTable(plan.TableNames, operName); err != nil {
+		return nil, err
 	}
 
 	fmt.Printf("CreateTable: 表结构创建成功\n")
-	return nil
+	return plan, nil
 }
 
 // ImportTable 导入表结构 对应Java后端的importGenTable
@@ -104,9 +203,8 @@ func (s *GenService) ImportTable(tableNames []string, operName string) error {
 		// 初始化表字段信息
 		s.initColumnField(genTable, columns)
 
-		// 保存业务表
-		err = s.genDao.InsertGenTable(genTable)
-		if err != nil {
+		// 保存业务表和表字段
+		if err := s.insertGenTable(genTable); err != nil {
 			return err
 		}
 	}
@@ -114,31 +212,56 @@ func (s *GenService) ImportTable(tableNames []string, operName string) error {
 	return nil
 }
 
+// insertGenTable 保存业务表及其字段
+func (s *GenService) insertGenTable(genTable *model.GenTable) error {
+	if err := s.genDao.InsertGenTable(genTable); err != nil {
+		return err
+	}
+	for i := range genTable.Columns {
+		genTable.Columns[i].TableID = genTable.TableID
+		if err := s.genDao.InsertGenTableColumn(&genTable.Columns[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // PreviewCode 预览代码 对应Java后端的previewCode
 func (s *GenService) PreviewCode(tableId int64) (map[string]string, error) {
 	fmt.Printf("GenService.PreviewCode: 预览代码, TableID=%d\n", tableId)
 
-	// 查询表信息
-	table, err := s.genDao.SelectGenTableById(tableId)
+	// 查询表信息（包含字段和子表）
+	table, err := s.loadGenTable(tableId)
 	if err != nil {
 		return nil, err
 	}
-	if table == nil {
-		return nil, fmt.Errorf("表不存在")
-	}
 
 	// 准备模板上下文
 	ctx := s.templateEngine.PrepareContext(table)
+	if err := s.templateEngine.ValidateContext(ctx); err != nil {
+		return nil, err
+	}
 
 	// 获取模板列表
 	templates := s.templateEngine.GetTemplateList(table.TplCategory, table.TplWebType)
 
+	// 模板组中的自定义模板，未定义的模板使用内置模板
+	customTemplates, err := s.loadTemplateSet(table.TemplateSetId)
+	if err != nil {
+		return nil, err
+	}
+
 	// 生成代码预览
 	codeMap := make(map[string]string)
 
 	for _, templateName := range templates {
-		code, err := s.templateEngine.RenderTemplate(templateName, ctx)
-		if err != nil {
+		var code string
+		if content, ok := customTemplates[templateName]; ok {
+			// 自定义模板渲染失败时直接返回，避免生成结果中静默缺少文件
+			if code, err = s.templateEngine.RenderContent(templateName, content, ctx); err != nil {
+				return nil, fmt.Errorf("自定义模板%s: %v", templateName, err)
+			}
+		} else if code, err = s.templateEngine.RenderTemplate(templateName, ctx); err != nil {
 			fmt.Printf("PreviewCode: 渲染模板失败, Template=%s, Error=%v\n", templateName, err)
 			continue
 		}
@@ -152,49 +275,199 @@ func (s *GenService) PreviewCode(tableId int64) (map[string]string, error) {
 	return codeMap, nil
 }
 
-// GenerateCode 生成代码（自定义路径） 对应Java后端的generatorCode
-func (s *GenService) GenerateCode(tableName string) error {
-	fmt.Printf("GenService.GenerateCode: 生成代码, TableName=%s\n", tableName)
+// PreviewTemplate 使用指定的模板内容预览业务表的生成结果，用于编辑自定义模板时预览
+func (s *GenService) PreviewTemplate(tableId int64, templateName, content string) (map[string]string, error) {
+	fmt.Printf("GenService.PreviewTemplate: 预览模板, TableID=%d, Template=%s\n", tableId, templateName)
 
-	// 查询表信息
-	table, err := s.genDao.SelectGenTableByName(tableName)
+	if err := s.templateEngine.ParseTemplate(templateName, content); err != nil {
+		return nil, err
+	}
+
+	table, err := s.loadGenTable(tableId)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	ctx := s.templateEngine.PrepareContext(table)
+	if err := s.templateEngine.ValidateContext(ctx); err != nil {
+		return nil, err
+	}
+
+	code, err := s.templateEngine.RenderContent(templateName, content, ctx)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{s.getFileName(templateName, table): code}, nil
+}
+
+// loadTemplateSet 加载业务表选择的模板组，返回模板名到模板内容的映射，未选择模板组时返回nil
+func (s *GenService) loadTemplateSet(setId int64) (map[string]string, error) {
+	if setId == 0 {
+		return nil, nil
+	}
+
+	set, err := s.templateDao.SelectTemplateSetById(setId)
+	if err != nil {
+		return nil, err
+	}
+	if set == nil {
+		return nil, fmt.Errorf("模板组不存在")
+	}
+	if !set.IsNormal() {
+		return nil, fmt.Errorf("模板组'%s'已停用", set.SetName)
+	}
+
+	templates, err := s.templateDao.SelectTemplatesBySetId(setId)
+	if err != nil {
+		return nil, err
+	}
+	customTemplates := make(map[string]string, len(templates))
+	for _, tpl := range templates {
+		customTemplates[tpl.TemplateName] = tpl.Content
+	}
+	return customTemplates, nil
+}
+
+// loadGenTable 查询业务表及其字段，主子表同时加载子表信息 对应Java后端的setSubTable
+func (s *GenService) loadGenTable(tableId int64) (*model.GenTable, error) {
+	table, err := s.genDao.SelectGenTableById(tableId)
+	if err != nil {
+		return nil, err
 	}
 	if table == nil {
-		return fmt.Errorf("表不存在")
+		return nil, fmt.Errorf("表不存在")
 	}
+	if table.Columns, err = s.genDao.SelectGenTableColumnListByTableId(table.TableID); err != nil {
+		return nil, err
+	}
+	table.SetTableFromOptions()
 
-	// TODO: 实现代码生成到文件系统
-	fmt.Printf("GenerateCode: 代码生成功能开发中\n")
+	if table.IsSub() && table.SubTableName != "" {
+		subTable, err := s.genDao.SelectGenTableByName(table.SubTableName)
+		if err != nil {
+			return nil, err
+		}
+		if subTable != nil {
+			if subTable.Columns, err = s.genDao.SelectGenTableColumnListByTableId(subTable.TableID); err != nil {
+				return nil, err
+			}
+			table.SubTable = subTable
+		}
+	}
 
-	return nil
+	return table, nil
 }
 
-// SynchDb 同步数据库 对应Java后端的synchDb
-func (s *GenService) SynchDb(tableName string) error {
-	fmt.Printf("GenService.SynchDb: 同步数据库, TableName=%s\n", tableName)
+// GenerateDiff 预览生成到自定义路径的差异，不写入任何文件
+func (s *GenService) GenerateDiff(tableName string, registerRoutes bool) ([]GenFileDiff, error) {
+	fmt.Printf("GenService.GenerateDiff: 预览生成差异, TableName=%s\n", tableName)
 
-	// 查询数据库表字段信息
-	columns, err := s.genDao.SelectDbTableColumnsByName(tableName)
+	_, diffs, _, err := s.prepareGenerate(tableName, registerRoutes)
+	return diffs, err
+}
+
+// GenerateCode 生成代码（自定义路径） 对应Java后端的generatorCode
+// 在上次生成后被修改过的文件不会被覆盖，存在冲突时不写入任何文件
+func (s *GenService) GenerateCode(tableName string, registerRoutes bool) ([]GenFileDiff, error) {
+	fmt.Printf("GenService.GenerateCode: 生成代码, TableName=%s\n", tableName)
+
+	writer, diffs, contents, err := s.prepareGenerate(tableName, registerRoutes)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	var conflicts []string
+	for _, diff := range diffs {
+		if diff.Status == GenFileConflict {
+			conflicts = append(conflicts, diff.Path)
+		}
+	}
+	if len(conflicts) > 0 {
+		return diffs, fmt.Errorf("以下文件在上次生成后被修改过，请先处理后再生成: %s", strings.Join(conflicts, ", "))
+	}
+
+	manifest, err := writer.loadManifest()
+	if err != nil {
+		return nil, err
+	}
+	for _, diff := range diffs {
+		content := contents[diff.Path]
+		if diff.Status == GenFileCreate || diff.Status == GenFileUpdate {
+			if err := writer.writeFile(diff.Path, content); err != nil {
+				return nil, fmt.Errorf("写入文件%s失败: %v", diff.Path, err)
+			}
+		}
+		// 路由文件由生成器追加内容，不记录到清单
+		if diff.Path != s.routerFile() {
+			manifest.Files[diff.Path] = newManifestEntry(content, tableName)
+		}
+	}
+	if err := writer.saveManifest(manifest); err != nil {
+		return nil, err
 	}
 
-	// 查询业务表信息
+	fmt.Printf("GenerateCode: 代码生成成功, 文件数量=%d\n", len(diffs))
+	return diffs, nil
+}
+
+// prepareGenerate 渲染代码并与生成路径下的现有文件对比
+func (s *GenService) prepareGenerate(tableName string, registerRoutes bool) (*genWriter, []GenFileDiff, map[string]string, error) {
 	table, err := s.genDao.SelectGenTableByName(tableName)
 	if err != nil {
-		return err
+		return nil, nil, nil, err
 	}
 	if table == nil {
-		return fmt.Errorf("业务表不存在")
+		return nil, nil, nil, fmt.Errorf("表不存在")
+	}
+
+	writer, err := newGenWriter(genSettings().ProjectRoot, table.GenPath)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	contents, err := s.PreviewCode(table.TableID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	manifest, err := writer.loadManifest()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	diffs, err := writer.plan(contents, manifest)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if registerRoutes {
+		diff, content, err := writer.planRouter(s.routerFile(), table.ModuleName, table.ClassName)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		diffs = append(diffs, diff)
+		contents[diff.Path] = content
 	}
 
-	// 重新初始化表字段信息
-	s.initColumnField(table, columns)
+	return writer, diffs, contents, nil
+}
 
-	// 更新业务表
-	return s.genDao.UpdateGenTable(table)
+// routerFile 自动注册路由的路由文件（相对生成路径）
+func (s *GenService) routerFile() string {
+	if routerFile := genSettings().RouterFile; routerFile != "" {
+		return routerFile
+	}
+	return "internal/router/gen_routes.go"
+}
+
+// currentDialect 当前数据库的表结构方言
+func currentDialect() (ddl.Dialect, error) {
+	return ddl.GetDialect(database.CurrentDialect().Name())
+}
+
+// genSettings 读取代码生成配置，配置未加载时返回空配置
+func genSettings() config.GenConfig {
+	if config.AppConfig == nil {
+		return config.GenConfig{}
+	}
+	return config.AppConfig.Gen
 }
 
 // initTable 初始化表信息
@@ -225,38 +498,46 @@ func (s *GenService) initColumnField(genTable *model.GenTable, dbColumns []model
 	var columns []model.GenTableColumn
 
 	for i, dbColumn := range dbColumns {
-		column := model.GenTableColumn{
-			TableID:       genTable.TableID,
-			ColumnName:    dbColumn.ColumnName,
-			ColumnComment: dbColumn.ColumnComment,
-			ColumnType:    dbColumn.ColumnType,
-			JavaType:      model.GetJavaType(dbColumn.DataType),
-			JavaField:     dao.ConvertColumnName(dbColumn.ColumnName),
-			Sort:          i + 1,
-		}
-
-		// 设置主键
-		if dbColumn.ColumnKey == "PRI" {
-			column.IsPk = "1"
+		column := s.newColumnField(genTable.TableID, dbColumn, i+1)
+		if column.IsPk == "1" {
 			genTable.PkColumn = &column
-		} else {
-			column.IsPk = "0"
 		}
+		columns = append(columns, column)
+	}
 
-		// 设置自增
-		if dbColumn.Extra == "auto_increment" {
-			column.IsIncrement = "1"
-		} else {
-			column.IsIncrement = "0"
-		}
+	genTable.Columns = columns
+}
 
-		// 设置字段操作属性
-		s.setColumnField(&column)
+// newColumnField 根据数据库列创建业务字段
+func (s *GenService) newColumnField(tableId int64, dbColumn model.DbTableColumn, sort int) model.GenTableColumn {
+	column := model.GenTableColumn{
+		TableID:       tableId,
+		ColumnName:    dbColumn.ColumnName,
+		ColumnComment: dbColumn.ColumnComment,
+		ColumnType:    dbColumn.ColumnType,
+		JavaType:      model.GetJavaType(dbColumn.DataType),
+		JavaField:     dao.ConvertColumnName(dbColumn.ColumnName),
+		Sort:          sort,
+	}
 
-		columns = append(columns, column)
+	// 设置主键
+	if dbColumn.ColumnKey == "PRI" {
+		column.IsPk = "1"
+	} else {
+		column.IsPk = "0"
 	}
 
-	genTable.Columns = columns
+	// 设置自增
+	if dbColumn.Extra == "auto_increment" {
+		column.IsIncrement = "1"
+	} else {
+		column.IsIncrement = "0"
+	}
+
+	// 设置字段操作属性
+	s.setColumnField(&column)
+
+	return column
 }
 
 // setColumnField 设置字段操作属性
@@ -461,6 +742,43 @@ func (s *GenService) ValidateEdit(genTable *model.GenTable) error {
 		}
 	}
 
+	// 校验树表配置
+	if genTable.IsTree() {
+		if genTable.TreeCode == "" {
+			return fmt.Errorf("树编码字段不能为空")
+		}
+		if genTable.TreeParentCode == "" {
+			return fmt.Errorf("树父编码字段不能为空")
+		}
+		if genTable.TreeName == "" {
+			return fmt.Errorf("树名称字段不能为空")
+		}
+	}
+
+	// 校验主子表配置
+	if genTable.IsSub() {
+		if genTable.SubTableName == "" {
+			return fmt.Errorf("关联子表的表名不能为空")
+		}
+		if genTable.SubTableFkName == "" {
+			return fmt.Errorf("子表关联的外键名不能为空")
+		}
+		if genTable.SubTableName == genTable.Name {
+			return fmt.Errorf("关联子表不能是当前表")
+		}
+	}
+
+	// 校验模板组
+	if genTable.TemplateSetId != 0 {
+		set, err := s.templateDao.SelectTemplateSetById(genTable.TemplateSetId)
+		if err != nil {
+			return err
+		}
+		if set == nil {
+			return fmt.Errorf("模板组不存在")
+		}
+	}
+
 	fmt.Printf("ValidateEdit: 参数校验通过\n")
 	return nil
 }
@@ -477,31 +795,40 @@ func (s *GenService) createZipFile(codeMap map[string]string) ([]byte, error) {
 	return []byte(zipContent), nil
 }
 
-// getFileName 根据模板名生成文件名 对应Java后端的文件名生成逻辑
+// getFileName 根据模板名生成文件路径（相对项目根目录） 对应Java后端的VelocityUtils.getFileName
 func (s *GenService) getFileName(templateName string, table *model.GenTable) string {
+	name := strings.ToLower(table.ClassName)
 	switch templateName {
 	case "model.go.tmpl":
-		return fmt.Sprintf("%s.go", strings.ToLower(table.ClassName))
+		return fmt.Sprintf("internal/repository/model/%s.go", name)
 	case "controller.go.tmpl":
-		return fmt.Sprintf("%s_controller.go", strings.ToLower(table.ClassName))
+		return fmt.Sprintf("internal/api/v1/%s/%s_controller.go", table.ModuleName, name)
 	case "service.go.tmpl":
-		return fmt.Sprintf("%s_service.go", strings.ToLower(table.ClassName))
+		return fmt.Sprintf("internal/service/%s/%s_service.go", table.ModuleName, name)
 	case "dao.go.tmpl":
-		return fmt.Sprintf("%s_dao.go", strings.ToLower(table.ClassName))
+		return fmt.Sprintf("internal/repository/dao/%s_dao.go", name)
 	case "sql.tmpl":
-		return fmt.Sprintf("%s_menu.sql", table.Name)
+		return fmt.Sprintf("sql/%s_menu.sql", table.Name)
 	case "tree.go.tmpl":
-		return fmt.Sprintf("%s_tree.go", strings.ToLower(table.ClassName))
+		return fmt.Sprintf("internal/repository/model/%s_tree.go", name)
 	case "sub.go.tmpl":
-		return fmt.Sprintf("%s_sub.go", strings.ToLower(table.ClassName))
-	case "index.vue.tmpl":
-		return "index.vue"
-	case "index-plus.vue.tmpl":
-		return "index.vue"
-	case "api.js.tmpl":
-		return fmt.Sprintf("%s.js", table.BusinessName)
-	case "api-plus.js.tmpl":
-		return fmt.Sprintf("%s.js", table.BusinessName)
+		return fmt.Sprintf("internal/repository/model/%s_sub.go", name)
+	case "dao_memory.go.tmpl":
+		return fmt.Sprintf("internal/repository/dao/%s_dao_memory.go", name)
+	case "service_test.go.tmpl":
+		return fmt.Sprintf("internal/service/%s/%s_service_test.go", table.ModuleName, name)
+	case "controller_test.go.tmpl":
+		return fmt.Sprintf("internal/api/v1/%s/%s_controller_test.go", table.ModuleName, name)
+	case "openapi.json.tmpl":
+		return fmt.Sprintf("%s/%s_%s.json", config.GetOpenAPIDir(), table.ModuleName, table.BusinessName)
+	case "index.vue.tmpl", "index-plus.vue.tmpl":
+		return fmt.Sprintf("vue/views/%s/%s/index.vue", table.ModuleName, table.BusinessName)
+	case "api.js.tmpl", "api-plus.js.tmpl":
+		return fmt.Sprintf("vue/api/%s/%s.js", table.ModuleName, table.BusinessName)
+	case "api.ts.tmpl":
+		return fmt.Sprintf("vue/api/%s/%s.ts", table.ModuleName, table.BusinessName)
+	case "types.ts.tmpl":
+		return fmt.Sprintf("vue/types/%s/%s.ts", table.ModuleName, table.BusinessName)
 	default:
 		return templateName
 	}
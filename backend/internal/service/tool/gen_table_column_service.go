@@ -1,6 +1,7 @@
 package tool
 
 import (
+	"context"
 	"fmt"
 	"time"
 	"wosm/internal/repository/dao"
@@ -19,6 +20,13 @@ func NewGenTableColumnService() *GenTableColumnService {
 	}
 }
 
+// WithContext 返回使用指定context的服务实例，数据访问携带请求信息和租户
+func (s *GenTableColumnService) WithContext(ctx context.Context) *GenTableColumnService {
+	return &GenTableColumnService{
+		genDao: s.genDao.WithContext(ctx),
+	}
+}
+
 // SelectGenTableColumnListByTableId 查询业务字段列表 对应Java后端的selectGenTableColumnListByTableId
 func (s *GenTableColumnService) SelectGenTableColumnListByTableId(tableId int64) ([]model.GenTableColumn, error) {
 	fmt.Printf("GenTableColumnService.SelectGenTableColumnListByTableId: 查询业务字段列表, TableID=%d\n", tableId)
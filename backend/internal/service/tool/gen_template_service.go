@@ -0,0 +1,195 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"wosm/internal/repository/dao"
+	"wosm/internal/repository/model"
+)
+
+// GenTemplateService 代码生成模板组服务
+// 模板组保存在数据库中，修改生成代码的风格不需要重新部署后端
+type GenTemplateService struct {
+	templateDao    *dao.GenTemplateDao
+	templateEngine *TemplateEngine
+}
+
+// NewGenTemplateService 创建代码生成模板组服务实例
+func NewGenTemplateService() *GenTemplateService {
+	return &GenTemplateService{
+		templateDao:    dao.NewGenTemplateDao(),
+		templateEngine: NewTemplateEngine(),
+	}
+}
+
+// WithContext 返回使用指定context的服务实例，数据访问携带请求信息和租户
+func (s *GenTemplateService) WithContext(ctx context.Context) *GenTemplateService {
+	return &GenTemplateService{
+		templateDao:    s.templateDao.WithContext(ctx),
+		templateEngine: s.templateEngine,
+	}
+}
+
+// BuiltinTemplate 内置模板
+type BuiltinTemplate struct {
+	TemplateName string `json:"templateName"` // 模板名称
+	Content      string `json:"content"`      // 模板内容
+}
+
+// SelectTemplateSetList 查询模板组列表
+func (s *GenTemplateService) SelectTemplateSetList(set *model.GenTemplateSet) ([]model.GenTemplateSet, error) {
+	return s.templateDao.SelectTemplateSetList(set)
+}
+
+// SelectTemplateSetById 查询模板组（包含模板）
+func (s *GenTemplateService) SelectTemplateSetById(setId int64) (*model.GenTemplateSet, error) {
+	set, err := s.templateDao.SelectTemplateSetById(setId)
+	if err != nil || set == nil {
+		return set, err
+	}
+	if set.Templates, err = s.templateDao.SelectTemplatesBySetId(setId); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+// InsertTemplateSet 新增模板组，同时保存请求中携带的模板
+func (s *GenTemplateService) InsertTemplateSet(set *model.GenTemplateSet) error {
+	if err := s.checkTemplateSet(set); err != nil {
+		return err
+	}
+	for _, tpl := range set.Templates {
+		if err := s.templateEngine.ParseTemplate(tpl.TemplateName, tpl.Content); err != nil {
+			return fmt.Errorf("%s: %v", tpl.TemplateName, err)
+		}
+	}
+
+	now := time.Now()
+	set.CreateTime = &now
+	if set.Status == "" {
+		set.Status = model.GenTemplateSetStatusNormal
+	}
+	if err := s.templateDao.InsertTemplateSet(set); err != nil {
+		return err
+	}
+
+	for _, tpl := range set.Templates {
+		if _, err := s.templateDao.SaveTemplate(set.SetID, tpl.TemplateName, tpl.Content, set.CreateBy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateTemplateSet 修改模板组基本信息，模板通过 SaveTemplate 单独保存
+func (s *GenTemplateService) UpdateTemplateSet(set *model.GenTemplateSet) error {
+	existing, err := s.templateDao.SelectTemplateSetById(set.SetID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return fmt.Errorf("模板组不存在")
+	}
+	if err := s.checkTemplateSet(set); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	set.UpdateTime = &now
+	return s.templateDao.UpdateTemplateSet(set)
+}
+
+// DeleteTemplateSetByIds 删除模板组
+func (s *GenTemplateService) DeleteTemplateSetByIds(setIds []int64) error {
+	return s.templateDao.DeleteTemplateSetByIds(setIds)
+}
+
+// checkTemplateSet 校验模板组名称和状态
+func (s *GenTemplateService) checkTemplateSet(set *model.GenTemplateSet) error {
+	set.SetName = strings.TrimSpace(set.SetName)
+	if set.SetName == "" {
+		return fmt.Errorf("模板组名称不能为空")
+	}
+	if set.Status != "" && set.Status != model.GenTemplateSetStatusNormal && set.Status != model.GenTemplateSetStatusDisable {
+		return fmt.Errorf("模板组状态错误")
+	}
+	unique, err := s.templateDao.CheckSetNameUnique(set.SetName, set.SetID)
+	if err != nil {
+		return err
+	}
+	if !unique {
+		return fmt.Errorf("模板组名称'%s'已存在", set.SetName)
+	}
+	return nil
+}
+
+// SaveTemplate 保存模板组中的模板，保存前校验模板语法，每次保存生成一个新版本
+func (s *GenTemplateService) SaveTemplate(setId int64, templateName, content, operName string) (*model.GenTemplate, error) {
+	set, err := s.templateDao.SelectTemplateSetById(setId)
+	if err != nil {
+		return nil, err
+	}
+	if set == nil {
+		return nil, fmt.Errorf("模板组不存在")
+	}
+	if err := s.templateEngine.ParseTemplate(templateName, content); err != nil {
+		return nil, err
+	}
+
+	return s.templateDao.SaveTemplate(setId, templateName, content, operName)
+}
+
+// DeleteTemplate 删除模板组中的模板，删除后恢复使用内置模板
+func (s *GenTemplateService) DeleteTemplate(templateId int64) error {
+	return s.templateDao.DeleteTemplateById(templateId)
+}
+
+// SelectTemplateHistory 查询模板的历史版本
+func (s *GenTemplateService) SelectTemplateHistory(templateId int64) ([]model.GenTemplateHistory, error) {
+	return s.templateDao.SelectTemplateHistory(templateId)
+}
+
+// SelectTemplateHistoryByVersion 查询模板指定版本的内容
+func (s *GenTemplateService) SelectTemplateHistoryByVersion(templateId int64, version int) (*model.GenTemplateHistory, error) {
+	return s.templateDao.SelectTemplateHistoryByVersion(templateId, version)
+}
+
+// RollbackTemplate 将模板回滚到指定版本，回滚本身也会生成一个新版本，可以再次回滚
+func (s *GenTemplateService) RollbackTemplate(templateId int64, version int, operName string) (*model.GenTemplate, error) {
+	tpl, err := s.templateDao.SelectTemplateById(templateId)
+	if err != nil {
+		return nil, err
+	}
+	if tpl == nil {
+		return nil, fmt.Errorf("模板不存在")
+	}
+	if tpl.Version == version {
+		return nil, fmt.Errorf("版本%d已经是当前版本", version)
+	}
+
+	history, err := s.templateDao.SelectTemplateHistoryByVersion(templateId, version)
+	if err != nil {
+		return nil, err
+	}
+	if history == nil {
+		return nil, fmt.Errorf("版本%d不存在", version)
+	}
+
+	return s.templateDao.SaveTemplate(tpl.SetID, tpl.TemplateName, history.Content, operName)
+}
+
+// SelectBuiltinTemplates 查询所有内置模板
+func (s *GenTemplateService) SelectBuiltinTemplates() ([]BuiltinTemplate, error) {
+	names := s.templateEngine.BuiltinTemplateNames()
+	templates := make([]BuiltinTemplate, 0, len(names))
+	for _, name := range names {
+		content, err := s.templateEngine.GetBuiltinTemplate(name)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, BuiltinTemplate{TemplateName: name, Content: content})
+	}
+	return templates, nil
+}
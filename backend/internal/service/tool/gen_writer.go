@@ -0,0 +1,344 @@
+package tool
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"wosm/pkg/utils"
+)
+
+// 生成文件状态
+const (
+	GenFileCreate    = "create"    // 新建文件
+	GenFileUpdate    = "update"    // 覆盖上次生成且未被修改过的文件
+	GenFileUnchanged = "unchanged" // 内容没有变化
+	GenFileConflict  = "conflict"  // 文件在上次生成后被修改过（或不是生成器创建的），不会覆盖
+)
+
+// genManifestFile 生成清单文件名，位于生成根目录下，记录每个生成文件的内容哈希
+const genManifestFile = ".gen-manifest.json"
+
+// 路由文件中的插入标记
+const (
+	genRouterImportMarker = "// gen:imports"
+	genRouterRouteMarker  = "// gen:routes"
+)
+
+// genRouterSkeleton 路由文件不存在时使用的初始内容，与 internal/router/gen_routes.go 保持一致
+const genRouterSkeleton = `// Package router 注册代码生成器生成的业务模块路由
+// 代码生成时勾选"注册路由"会在标记行后追加导入和注册语句，请保留标记行
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	// gen:imports
+)
+
+// RegisterGenRoutes 注册代码生成器生成的业务路由，router 为需要登录的路由组
+func RegisterGenRoutes(router *gin.RouterGroup) {
+	// gen:routes
+}
+`
+
+// GenFileDiff 生成文件与磁盘上现有文件的差异
+type GenFileDiff struct {
+	Path   string `json:"path"`           // 相对生成根目录的路径
+	Status string `json:"status"`         // 文件状态
+	Diff   string `json:"diff,omitempty"` // unified diff，内容没有变化时为空
+}
+
+// genManifestEntry 生成清单中的文件记录
+type genManifestEntry struct {
+	Hash      string `json:"hash"`      // 最近一次生成写入内容的sha256
+	TableName string `json:"tableName"` // 生成该文件的业务表
+	GenTime   string `json:"genTime"`   // 生成时间
+}
+
+// genManifest 生成清单
+type genManifest struct {
+	Files map[string]genManifestEntry `json:"files"`
+}
+
+// genWriter 将生成的代码写入项目根目录下的生成路径，所有写入都限制在该目录内
+type genWriter struct {
+	root string // 生成根目录（绝对路径，已解析符号链接）
+}
+
+// newGenWriter 创建写入器，genPath 为空或"/"时使用项目根目录
+func newGenWriter(projectRoot, genPath string) (*genWriter, error) {
+	if strings.TrimSpace(projectRoot) == "" {
+		return nil, fmt.Errorf("未配置代码生成项目根目录(gen.project_root)，不能生成到自定义路径")
+	}
+	root, err := filepath.Abs(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+	if root, err = filepath.EvalSymlinks(root); err != nil {
+		return nil, fmt.Errorf("代码生成项目根目录不存在: %s", projectRoot)
+	}
+
+	target := strings.TrimSpace(genPath)
+	if target == "" || target == "/" {
+		target = "."
+	}
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(root, target)
+	}
+	if !isWithin(root, filepath.Clean(target)) {
+		return nil, fmt.Errorf("生成路径必须位于项目根目录下: %s", genPath)
+	}
+
+	w := &genWriter{root: root}
+	if target, err = w.checkPath(target); err != nil {
+		return nil, err
+	}
+	w.root = target
+	return w, nil
+}
+
+// isWithin 判断path是否为root或root下的路径
+func isWithin(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
+
+// checkPath 解析路径中已存在部分的符号链接，防止通过符号链接写到生成根目录之外
+func (w *genWriter) checkPath(path string) (string, error) {
+	existing, rest := path, ""
+	for {
+		if _, err := os.Lstat(existing); err == nil {
+			break
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			break
+		}
+		rest = filepath.Join(filepath.Base(existing), rest)
+		existing = parent
+	}
+
+	resolved, err := filepath.EvalSymlinks(existing)
+	if err != nil {
+		return "", err
+	}
+	resolved = filepath.Join(resolved, rest)
+	if !isWithin(w.root, resolved) {
+		return "", fmt.Errorf("路径超出生成目录: %s", path)
+	}
+	return resolved, nil
+}
+
+// resolve 将相对生成根目录的文件路径转换为绝对路径
+func (w *genWriter) resolve(rel string) (string, error) {
+	if filepath.IsAbs(rel) || strings.Contains(rel, "\\") {
+		return "", fmt.Errorf("非法的文件路径: %s", rel)
+	}
+	path := filepath.Join(w.root, filepath.FromSlash(rel))
+	if !isWithin(w.root, path) {
+		return "", fmt.Errorf("路径超出生成目录: %s", rel)
+	}
+	return w.checkPath(path)
+}
+
+// readFile 读取生成根目录下的文件，文件不存在时返回 exists=false
+func (w *genWriter) readFile(rel string) (content string, exists bool, err error) {
+	path, err := w.resolve(rel)
+	if err != nil {
+		return "", false, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return string(data), true, nil
+}
+
+// writeFile 写入文件，先写临时文件再重命名，避免写入中断留下不完整的文件
+func (w *genWriter) writeFile(rel, content string) error {
+	path, err := w.resolve(rel)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".gen-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// loadManifest 读取生成清单，清单不存在时返回空清单
+func (w *genWriter) loadManifest() (*genManifest, error) {
+	manifest := &genManifest{Files: make(map[string]genManifestEntry)}
+	content, exists, err := w.readFile(genManifestFile)
+	if err != nil || !exists {
+		return manifest, err
+	}
+	if err := json.Unmarshal([]byte(content), manifest); err != nil {
+		return nil, fmt.Errorf("生成清单格式错误: %v", err)
+	}
+	if manifest.Files == nil {
+		manifest.Files = make(map[string]genManifestEntry)
+	}
+	return manifest, nil
+}
+
+// saveManifest 保存生成清单
+func (w *genWriter) saveManifest(manifest *genManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return w.writeFile(genManifestFile, string(data)+"\n")
+}
+
+// plan 对比生成内容与磁盘上的文件，计算每个文件的状态和差异
+// 磁盘上的文件与清单中记录的哈希不一致时，说明开发者修改过，标记为冲突
+func (w *genWriter) plan(files map[string]string, manifest *genManifest) ([]GenFileDiff, error) {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	diffs := make([]GenFileDiff, 0, len(paths))
+	for _, path := range paths {
+		content := files[path]
+		existing, exists, err := w.readFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		diff := GenFileDiff{Path: path}
+		switch {
+		case !exists:
+			diff.Status = GenFileCreate
+			diff.Diff = utils.UnifiedDiff("/dev/null", "b/"+path, "", content, 3)
+		case existing == content:
+			diff.Status = GenFileUnchanged
+		default:
+			diff.Diff = utils.UnifiedDiff("a/"+path, "b/"+path, existing, content, 3)
+			if entry, ok := manifest.Files[path]; ok && entry.Hash == contentHash(existing) {
+				diff.Status = GenFileUpdate
+			} else {
+				diff.Status = GenFileConflict
+			}
+		}
+		diffs = append(diffs, diff)
+	}
+	return diffs, nil
+}
+
+// planRouter 计算注册路由后的路由文件内容，路由文件只追加不覆盖，因此不会产生冲突
+func (w *genWriter) planRouter(routerFile, moduleName, className string) (GenFileDiff, string, error) {
+	existing, exists, err := w.readFile(routerFile)
+	if err != nil {
+		return GenFileDiff{}, "", err
+	}
+	base := existing
+	if !exists {
+		base = genRouterSkeleton
+	}
+
+	content, err := registerGenRoute(base, moduleName, className)
+	if err != nil {
+		return GenFileDiff{}, "", fmt.Errorf("%s: %v", routerFile, err)
+	}
+
+	diff := GenFileDiff{Path: routerFile, Status: GenFileUpdate}
+	switch {
+	case !exists:
+		diff.Status = GenFileCreate
+		diff.Diff = utils.UnifiedDiff("/dev/null", "b/"+routerFile, "", content, 3)
+	case existing == content:
+		diff.Status = GenFileUnchanged
+	default:
+		diff.Diff = utils.UnifiedDiff("a/"+routerFile, "b/"+routerFile, existing, content, 3)
+	}
+	return diff, content, nil
+}
+
+// registerGenRoute 在路由文件的标记行后追加模块导入和路由注册语句，已注册时不重复追加
+func registerGenRoute(content, moduleName, className string) (string, error) {
+	alias := moduleName + "Api"
+	importLine := fmt.Sprintf("%s \"wosm/internal/api/v1/%s\"", alias, moduleName)
+	routeLine := fmt.Sprintf("%s.New%sController().Register%sRoutes(router)", alias, className, className)
+
+	content, err := insertAfterMarker(content, genRouterImportMarker, importLine)
+	if err != nil {
+		return "", err
+	}
+	if content, err = insertAfterMarker(content, genRouterRouteMarker, routeLine); err != nil {
+		return "", err
+	}
+
+	formatted, err := format.Source([]byte(content))
+	if err != nil {
+		return "", fmt.Errorf("路由文件格式错误: %v", err)
+	}
+	return string(formatted), nil
+}
+
+// insertAfterMarker 在标记行后插入一行，保持标记行的缩进
+func insertAfterMarker(content, marker, line string) (string, error) {
+	lines := strings.Split(content, "\n")
+	for i, l := range lines {
+		if strings.TrimSpace(l) == line {
+			return content, nil
+		}
+		if strings.TrimSpace(l) != marker {
+			continue
+		}
+		indent := l[:len(l)-len(strings.TrimLeft(l, " \t"))]
+		for _, next := range lines[i+1:] {
+			if strings.TrimSpace(next) == line {
+				return content, nil
+			}
+		}
+		lines = append(lines[:i+1], append([]string{indent + line}, lines[i+1:]...)...)
+		return strings.Join(lines, "\n"), nil
+	}
+	return "", fmt.Errorf("缺少标记行 %s", marker)
+}
+
+// newManifestEntry 创建清单记录
+func newManifestEntry(content, tableName string) genManifestEntry {
+	return genManifestEntry{
+		Hash:      contentHash(content),
+		TableName: tableName,
+		GenTime:   time.Now().Format("2006-01-02 15:04:05"),
+	}
+}
+
+// contentHash 计算内容的sha256
+func contentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,83 @@
+package tool
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenWriterPlan(t *testing.T) {
+	root := t.TempDir()
+	w, err := newGenWriter(root, "")
+	require.NoError(t, err)
+
+	manifest, err := w.loadManifest()
+	require.NoError(t, err)
+
+	require.NoError(t, w.writeFile("a.go", "package a\n"))
+	require.NoError(t, w.writeFile("b.go", "package b\n"))
+	require.NoError(t, w.writeFile("c.go", "package c\n"))
+	manifest.Files["a.go"] = newManifestEntry("package a\n", "t")
+	manifest.Files["b.go"] = newManifestEntry("package b // old\n", "t")
+
+	diffs, err := w.plan(map[string]string{
+		"a.go":     "package a // new\n",
+		"b.go":     "package b // new\n",
+		"c.go":     "package c\n",
+		"dir/d.go": "package d\n",
+	}, manifest)
+	require.NoError(t, err)
+
+	status := make(map[string]string)
+	for _, diff := range diffs {
+		status[diff.Path] = diff.Status
+	}
+	assert.Equal(t, map[string]string{
+		"a.go":     GenFileUpdate,    // 与清单一致，可以覆盖
+		"b.go":     GenFileConflict,  // 生成后被修改过
+		"c.go":     GenFileUnchanged, // 内容相同
+		"dir/d.go": GenFileCreate,
+	}, status)
+}
+
+func TestGenWriterSandbox(t *testing.T) {
+	root := t.TempDir()
+
+	_, err := newGenWriter(root, "../outside")
+	assert.Error(t, err)
+
+	w, err := newGenWriter(root, "gen")
+	require.NoError(t, err)
+	assert.Error(t, w.writeFile("../escape.go", "package x\n"))
+	assert.Error(t, w.writeFile("/etc/escape.go", "package x\n"))
+
+	// 通过符号链接指向生成目录之外
+	outside := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "gen"), 0o755))
+	if err := os.Symlink(outside, filepath.Join(root, "gen", "link")); err != nil {
+		t.Skipf("不支持符号链接: %v", err)
+	}
+	assert.Error(t, w.writeFile("link/escape.go", "package x\n"))
+	_, err = os.Stat(filepath.Join(outside, "escape.go"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRegisterGenRoute(t *testing.T) {
+	content, err := registerGenRoute(genRouterSkeleton, "biz", "BizOrder")
+	require.NoError(t, err)
+	assert.Contains(t, content, `bizApi "wosm/internal/api/v1/biz"`)
+	assert.Contains(t, content, "bizApi.NewBizOrderController().RegisterBizOrderRoutes(router)")
+
+	// 重复注册不会追加
+	again, err := registerGenRoute(content, "biz", "BizOrder")
+	require.NoError(t, err)
+	assert.Equal(t, content, again)
+	assert.Equal(t, 1, strings.Count(again, "RegisterBizOrderRoutes"))
+
+	_, err = registerGenRoute("package router\n", "biz", "BizOrder")
+	assert.Error(t, err)
+}
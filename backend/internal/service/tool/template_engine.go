@@ -2,11 +2,15 @@ package tool
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
+	"go/format"
+	"sort"
 	"strings"
 	"text/template"
 	"time"
 	"wosm/internal/repository/model"
+	"wosm/pkg/database"
 )
 
 // TemplateEngine 代码生成模板引擎 对应Java后端的VelocityUtils
@@ -36,10 +40,34 @@ type TemplateContext struct {
 	Columns          []model.GenTableColumn // 表字段
 	PkColumn         *model.GenTableColumn  // 主键字段
 	ImportList       []string               // 导入列表
+	RangeQuery       bool                   // 是否有范围查询字段，模型中生成Params接收params[beginXxx]、params[endXxx]
 	PermissionPrefix string                 // 权限前缀
 	TplCategory      string                 // 模板类型
 	TplWebType       string                 // 前端类型
 	ParentMenuId     int64                  // 上级菜单ID
+	Dialect          database.Dialect       // 数据库方言，SQL脚本中的时间、自增ID和字符串常量按方言生成
+
+	// 树表（tree）
+	TreeCode        *model.GenTableColumn // 树编码字段
+	TreeParentCode  *model.GenTableColumn // 树父编码字段
+	TreeName        *model.GenTableColumn // 树名称字段
+	AncestorsColumn *model.GenTableColumn // 祖级列表字段（表中存在ancestors列时维护）
+
+	// 主子表（sub）
+	SubTable         *model.GenTable       // 子表信息
+	SubClassName     string                // 子表类名
+	SubPkColumn      *model.GenTableColumn // 子表主键字段
+	SubTableFkColumn *model.GenTableColumn // 子表关联主表的外键字段
+	SubImportList    []string              // 子表模型的导入列表
+
+	// 前端（vue）
+	TypeScript     bool                   // 是否生成TypeScript代码
+	BusinessClass  string                 // 业务名首字母大写，用于接口方法名
+	QueryColumns   []model.GenTableColumn // 查询表单字段
+	ListColumns    []model.GenTableColumn // 列表字段
+	FormColumns    []model.GenTableColumn // 新增/修改表单字段
+	SubFormColumns []model.GenTableColumn // 子表表单字段（不含主键和外键）
+	DictTypes      []string               // 用到的字典类型
 }
 
 // PrepareContext 准备模板上下文 对应Java后端的VelocityUtils.prepareContext
@@ -61,22 +89,120 @@ func (e *TemplateEngine) PrepareContext(table *model.GenTable) *TemplateContext
 		TplWebType:       table.TplWebType,
 		PermissionPrefix: fmt.Sprintf("%s:%s", table.ModuleName, table.BusinessName),
 		ParentMenuId:     table.ParentMenuId,
+		Dialect:          database.CurrentDialect(),
 	}
 
 	// 构建导入列表
 	ctx.ImportList = e.buildImportList(table)
 
+	// 树表字段 对应Java后端的setTreeVelocityContext
+	if table.IsTree() {
+		ctx.TreeCode = table.GetColumnByName(table.TreeCode)
+		ctx.TreeParentCode = table.GetColumnByName(table.TreeParentCode)
+		ctx.TreeName = table.GetColumnByName(table.TreeName)
+		ctx.AncestorsColumn = table.GetColumnByField("ancestors")
+	}
+
+	// 子表字段 对应Java后端的setSubVelocityContext
+	if table.IsSub() && table.SubTable != nil {
+		ctx.SubTable = table.SubTable
+		ctx.SubClassName = table.SubTable.ClassName
+		ctx.SubPkColumn = table.SubTable.GetPkColumn()
+		ctx.SubTableFkColumn = table.SubTable.GetColumnByName(table.SubTableFkName)
+		ctx.SubImportList = e.buildImportList(table.SubTable)
+	}
+
+	e.prepareWebContext(ctx)
+
 	return ctx
 }
 
+// prepareWebContext 准备前端模板使用的字段分组 对应Java后端的getDicts等方法
+func (e *TemplateEngine) prepareWebContext(ctx *TemplateContext) {
+	ctx.TypeScript = ctx.TplWebType == model.TplWebTypeElementPlusTs
+	ctx.BusinessClass = capitalize(ctx.BusinessName)
+
+	dicts := make(map[string]bool)
+	for _, column := range ctx.Columns {
+		if column.IsQueryField() {
+			ctx.QueryColumns = append(ctx.QueryColumns, column)
+			if column.QueryType == model.QueryTypeBetween {
+				ctx.RangeQuery = true
+			}
+		}
+		if column.IsListField() {
+			ctx.ListColumns = append(ctx.ListColumns, column)
+		}
+		// 祖级列表由后端维护，不在表单中编辑
+		isAncestors := ctx.AncestorsColumn != nil && column.ColumnName == ctx.AncestorsColumn.ColumnName
+		if !column.IsPrimaryKey() && !isAncestors && (column.IsInsertField() || column.IsEditField()) {
+			ctx.FormColumns = append(ctx.FormColumns, column)
+		}
+		if column.DictType != "" && isDictHtml(column.HtmlType) {
+			dicts[column.DictType] = true
+		}
+	}
+
+	if ctx.SubTable != nil {
+		for _, column := range ctx.SubTable.Columns {
+			if column.IsPrimaryKey() || (ctx.SubTableFkColumn != nil && column.ColumnName == ctx.SubTableFkColumn.ColumnName) {
+				continue
+			}
+			ctx.SubFormColumns = append(ctx.SubFormColumns, column)
+			if column.DictType != "" && isDictHtml(column.HtmlType) {
+				dicts[column.DictType] = true
+			}
+		}
+	}
+
+	ctx.DictTypes = nil
+	for dict := range dicts {
+		ctx.DictTypes = append(ctx.DictTypes, dict)
+	}
+	sort.Strings(ctx.DictTypes)
+}
+
+// ValidateContext 检查树表和主子表模板所需的字段是否齐全，避免渲染出不完整的代码
+func (e *TemplateEngine) ValidateContext(ctx *TemplateContext) error {
+	if ctx.PkColumn == nil {
+		return fmt.Errorf("表%s缺少主键字段", ctx.TableName)
+	}
+
+	switch ctx.TplCategory {
+	case model.TplCategoryTree:
+		if ctx.TreeCode == nil {
+			return fmt.Errorf("树编码字段不存在: %s", ctx.Table.TreeCode)
+		}
+		if ctx.TreeParentCode == nil {
+			return fmt.Errorf("树父编码字段不存在: %s", ctx.Table.TreeParentCode)
+		}
+		if ctx.TreeName == nil {
+			return fmt.Errorf("树名称字段不存在: %s", ctx.Table.TreeName)
+		}
+		if getGoType(ctx.TreeCode.JavaType) != getGoType(ctx.TreeParentCode.JavaType) {
+			return fmt.Errorf("树编码字段和树父编码字段的类型必须一致")
+		}
+	case model.TplCategorySub:
+		if ctx.SubTable == nil {
+			return fmt.Errorf("关联子表不存在: %s", ctx.Table.SubTableName)
+		}
+		if ctx.SubPkColumn == nil {
+			return fmt.Errorf("子表%s缺少主键字段", ctx.SubTable.Name)
+		}
+		if ctx.SubTableFkColumn == nil {
+			return fmt.Errorf("子表外键字段不存在: %s", ctx.Table.SubTableFkName)
+		}
+		if getGoType(ctx.SubTableFkColumn.JavaType) != getGoType(ctx.PkColumn.JavaType) {
+			return fmt.Errorf("子表外键字段的类型必须与主表主键一致")
+		}
+	}
+	return nil
+}
+
 // buildImportList 构建导入列表 对应Java后端的导入包逻辑
 func (e *TemplateEngine) buildImportList(table *model.GenTable) []string {
 	imports := make(map[string]bool)
 
-	// 基础导入
-	imports["time"] = true
-	imports["gorm.io/gorm"] = true
-
 	// 根据字段类型添加导入
 	for _, column := range table.Columns {
 		switch column.JavaType {
@@ -92,6 +218,7 @@ func (e *TemplateEngine) buildImportList(table *model.GenTable) []string {
 	for imp := range imports {
 		importList = append(importList, imp)
 	}
+	sort.Strings(importList)
 
 	return importList
 }
@@ -104,10 +231,14 @@ func (e *TemplateEngine) RenderTemplate(templateName string, ctx *TemplateContex
 		return "", err
 	}
 
-	// 创建模板
-	tmpl, err := template.New(templateName).Funcs(e.getTemplateFuncs()).Parse(templateContent)
+	return e.RenderContent(templateName, templateContent, ctx)
+}
+
+// RenderContent 使用指定的模板内容渲染，templateName 决定分隔符和是否格式化Go代码
+func (e *TemplateEngine) RenderContent(templateName, templateContent string, ctx *TemplateContext) (string, error) {
+	tmpl, err := e.parse(templateName, templateContent)
 	if err != nil {
-		return "", fmt.Errorf("解析模板失败: %v", err)
+		return "", err
 	}
 
 	// 渲染模板
@@ -116,37 +247,118 @@ func (e *TemplateEngine) RenderTemplate(templateName string, ctx *TemplateContex
 		return "", fmt.Errorf("渲染模板失败: %v", err)
 	}
 
+	// Go代码统一格式化，格式化失败说明生成的代码无法编译
+	if strings.HasSuffix(templateName, ".go.tmpl") {
+		formatted, err := format.Source(buf.Bytes())
+		if err != nil {
+			return "", fmt.Errorf("生成的代码格式错误: %v", err)
+		}
+		return string(formatted), nil
+	}
+
+	// JSON片段校验后统一缩进
+	if strings.HasSuffix(templateName, ".json.tmpl") {
+		var compact, indented bytes.Buffer
+		if err := json.Compact(&compact, buf.Bytes()); err != nil {
+			return "", fmt.Errorf("生成的JSON格式错误: %v", err)
+		}
+		if err := json.Indent(&indented, compact.Bytes(), "", "  "); err != nil {
+			return "", fmt.Errorf("生成的JSON格式错误: %v", err)
+		}
+		return indented.String() + "\n", nil
+	}
+
 	return buf.String(), nil
 }
 
+// ParseTemplate 校验模板名称和模板语法
+func (e *TemplateEngine) ParseTemplate(templateName, templateContent string) error {
+	if _, err := e.getTemplateContent(templateName); err != nil {
+		return err
+	}
+	_, err := e.parse(templateName, templateContent)
+	return err
+}
+
+// parse 解析模板，前端模板中的 {{ }} 属于vue插值语法，改用 [[ ]] 作为模板分隔符
+func (e *TemplateEngine) parse(templateName, templateContent string) (*template.Template, error) {
+	tmpl := template.New(templateName).Funcs(e.getTemplateFuncs())
+	if isWebTemplate(templateName) {
+		tmpl = tmpl.Delims("[[", "]]")
+	}
+	tmpl, err := tmpl.Parse(templateContent)
+	if err != nil {
+		return nil, fmt.Errorf("解析模板失败: %v", err)
+	}
+	return tmpl, nil
+}
+
 // getTemplateFuncs 获取模板函数 对应Java后端的模板工具方法
 func (e *TemplateEngine) getTemplateFuncs() template.FuncMap {
 	return template.FuncMap{
-		"toLower":      strings.ToLower,
-		"toUpper":      strings.ToUpper,
-		"capitalize":   capitalize,
-		"uncapitalize": uncapitalize,
-		"contains":     strings.Contains,
-		"hasPrefix":    strings.HasPrefix,
-		"hasSuffix":    strings.HasSuffix,
-		"replace":      strings.ReplaceAll,
-		"split":        strings.Split,
-		"join":         strings.Join,
-		"trim":         strings.TrimSpace,
-		"isNotEmpty":   isNotEmpty,
-		"isEmpty":      isEmpty,
-		"eq":           eq,
-		"ne":           ne,
-		"gt":           gt,
-		"lt":           lt,
-		"add":          add,
-		"sub":          sub,
-		"formatDate":   formatDate,
-		"now":          time.Now,
-		"getGoType":    getGoType,
+		"toLower":       strings.ToLower,
+		"toUpper":       strings.ToUpper,
+		"capitalize":    capitalize,
+		"uncapitalize":  uncapitalize,
+		"contains":      strings.Contains,
+		"hasPrefix":     strings.HasPrefix,
+		"hasSuffix":     strings.HasSuffix,
+		"replace":       strings.ReplaceAll,
+		"split":         strings.Split,
+		"join":          strings.Join,
+		"trim":          strings.TrimSpace,
+		"isNotEmpty":    isNotEmpty,
+		"isEmpty":       isEmpty,
+		"eq":            eq,
+		"ne":            ne,
+		"gt":            gt,
+		"lt":            lt,
+		"add":           add,
+		"sub":           sub,
+		"formatDate":    formatDate,
+		"now":           time.Now,
+		"getGoType":     getGoType,
+		"zeroValue":     zeroValue,
+		"tsType":        tsType,
+		"columnLabel":   columnLabel,
+		"isDictHtml":    isDictHtml,
+		"sampleValue":   sampleValue,
+		"sampleId":      sampleId,
+		"isSampleField": isSampleField,
+		"swaggerType":   swaggerType,
+		"jsonString":    jsonString,
+		"queryOperator": queryOperator,
+	}
+}
+
+// BuiltinTemplateNames 所有内置模板名称，模板组只能覆盖这些模板
+func (e *TemplateEngine) BuiltinTemplateNames() []string {
+	return []string{
+		"model.go.tmpl",
+		"dao.go.tmpl",
+		"service.go.tmpl",
+		"controller.go.tmpl",
+		"sql.tmpl",
+		"tree.go.tmpl",
+		"sub.go.tmpl",
+		"dao_memory.go.tmpl",
+		"service_test.go.tmpl",
+		"controller_test.go.tmpl",
+		"openapi.json.tmpl",
+		"index.vue.tmpl",
+		"index-plus.vue.tmpl",
+		"api.js.tmpl",
+		"api-plus.js.tmpl",
+		"api.ts.tmpl",
+		"types.ts.tmpl",
 	}
 }
 
+// GetBuiltinTemplate 获取内置模板内容，作为编辑自定义模板的起点
+func (e *TemplateEngine) GetBuiltinTemplate(templateName string) (string, error) {
+	return e.getTemplateContent(templateName)
+}
+
 // getTemplateContent 获取模板内容 对应Java后端的模板文件读取
 func (e *TemplateEngine) getTemplateContent(templateName string) (string, error) {
 	switch templateName {
@@ -160,6 +372,28 @@ func (e *TemplateEngine) getTemplateContent(templateName string) (string, error)
 		return e.getDaoTemplate(), nil
 	case "sql.tmpl":
 		return e.getSqlTemplate(), nil
+	case "tree.go.tmpl":
+		return e.getTreeTemplate(), nil
+	case "sub.go.tmpl":
+		return e.getSubTemplate(), nil
+	case "dao_memory.go.tmpl":
+		return e.getDaoMemoryTemplate(), nil
+	case "service_test.go.tmpl":
+		return e.getServiceTestTemplate(), nil
+	case "controller_test.go.tmpl":
+		return e.getControllerTestTemplate(), nil
+	case "openapi.json.tmpl":
+		return e.getOpenAPITemplate(), nil
+	case "index.vue.tmpl":
+		return e.getIndexVueTemplate(), nil
+	case "index-plus.vue.tmpl":
+		return e.getIndexPlusVueTemplate(), nil
+	case "api.js.tmpl", "api-plus.js.tmpl":
+		return e.getApiJsTemplate(), nil
+	case "api.ts.tmpl":
+		return e.getApiTsTemplate(), nil
+	case "types.ts.tmpl":
+		return e.getTypesTsTemplate(), nil
 	default:
 		return "", fmt.Errorf("未知的模板: %s", templateName)
 	}
@@ -184,12 +418,17 @@ func (e *TemplateEngine) GetTemplateList(tplCategory, tplWebType string) []strin
 		templates = append(templates, "sql.tmpl", "sub.go.tmpl")
 	}
 
+	// 单元测试和接口文档片段
+	templates = append(templates, "dao_memory.go.tmpl", "service_test.go.tmpl", "controller_test.go.tmpl", "openapi.json.tmpl")
+
 	// 根据前端类型添加前端模板
 	switch tplWebType {
 	case model.TplWebTypeElementUI:
 		templates = append(templates, "index.vue.tmpl", "api.js.tmpl")
 	case model.TplWebTypeElementPlus:
 		templates = append(templates, "index-plus.vue.tmpl", "api-plus.js.tmpl")
+	case model.TplWebTypeElementPlusTs:
+		templates = append(templates, "index-plus.vue.tmpl", "api.ts.tmpl", "types.ts.tmpl")
 	}
 
 	return templates
@@ -269,20 +508,94 @@ func getGoType(javaType string) string {
 	}
 }
 
+// queryOperator 获取查询方式对应的比较运算符，未知的查询方式按等于处理
+func queryOperator(queryType string) string {
+	switch queryType {
+	case model.QueryTypeNE:
+		return "<>"
+	case model.QueryTypeGT:
+		return ">"
+	case model.QueryTypeGTE:
+		return ">="
+	case model.QueryTypeLT:
+		return "<"
+	case model.QueryTypeLTE:
+		return "<="
+	default:
+		return "="
+	}
+}
+
+// zeroValue 获取Go类型的零值字面量
+func zeroValue(javaType string) string {
+	switch getGoType(javaType) {
+	case "string":
+		return `""`
+	case "int", "int64", "float64":
+		return "0"
+	case "bool":
+		return "false"
+	default:
+		return "nil"
+	}
+}
+
+// tsType 获取TypeScript类型
+func tsType(javaType string) string {
+	switch javaType {
+	case model.JavaTypeInteger, model.JavaTypeLong, model.JavaTypeDouble, model.JavaTypeBigDecimal:
+		return "number"
+	case model.JavaTypeBoolean:
+		return "boolean"
+	default:
+		return "string"
+	}
+}
+
+// columnLabel 获取字段显示名称，去掉注释中括号内的说明 对应Java后端的getColumnComment
+func columnLabel(comment string) string {
+	if i := strings.IndexAny(comment, "（("); i > 0 {
+		return comment[:i]
+	}
+	return comment
+}
+
+// isDictHtml 判断显示类型是否使用字典数据
+func isDictHtml(htmlType string) bool {
+	return htmlType == model.HtmlTypeSelect || htmlType == model.HtmlTypeRadio || htmlType == model.HtmlTypeCheckbox
+}
+
+// isWebTemplate 判断是否为前端模板
+func isWebTemplate(templateName string) bool {
+	return strings.HasSuffix(templateName, ".vue.tmpl") || strings.HasSuffix(templateName, ".js.tmpl") || strings.HasSuffix(templateName, ".ts.tmpl")
+}
+
 // getModelTemplate 获取模型模板 对应Java后端的domain.java.vm
 func (e *TemplateEngine) getModelTemplate() string {
 	return `package model
+{{- if .ImportList}}
 
 import (
 {{- range .ImportList}}
 	"{{.}}"
 {{- end}}
 )
+{{- end}}
 
 // {{.ClassName}} {{.FunctionName}} 对应Java后端的{{.ClassName}}实体
 type {{.ClassName}} struct {
 {{- range .Columns}}
-	{{capitalize .JavaField}} {{getGoType .JavaType}} ` + "`" + `gorm:"column:{{.ColumnName}}" json:"{{.JavaField}}"` + "`" + ` // {{.ColumnComment}}
+	{{capitalize .JavaField}} {{getGoType .JavaType}} ` + "`" + `gorm:"column:{{.ColumnName}}{{if .IsPrimaryKey}};primaryKey{{if .IsAutoIncrement}};autoIncrement{{end}}{{end}}" json:"{{.JavaField}}" form:"{{if .IsQueryField}}{{.JavaField}}{{else}}-{{end}}"` + "`" + ` // {{.ColumnComment}}
+{{- end}}
+{{- if .RangeQuery}}
+
+	// 范围查询参数 params[beginXxx]、params[endXxx]（不映射到数据库）
+	Params map[string]string ` + "`" + `gorm:"-" json:"params,omitempty" form:"-"` + "`" + `
+{{- end}}
+{{- if .SubClassName}}
+
+	// 子表信息（不映射到数据库）
+	{{.SubClassName}}List []{{.SubClassName}} ` + "`" + `gorm:"-" json:"{{uncapitalize .SubClassName}}List" form:"-"` + "`" + ` // {{.SubTable.FunctionName}}
 {{- end}}
 }
 
@@ -295,111 +608,373 @@ func ({{.ClassName}}) TableName() string {
 // getControllerTemplate 获取控制器模板 对应Java后端的controller.java.vm
 func (e *TemplateEngine) getControllerTemplate() string {
 	return `package {{.ModuleName}}
+{{- $pkType := getGoType .PkColumn.JavaType}}
 
 import (
-	"github.com/gin-gonic/gin"
+	"fmt"
+	"strings"
+	"wosm/internal/repository/model"
+	{{.ModuleName}}Service "wosm/internal/service/{{.ModuleName}}"
+	"wosm/pkg/middleware"
 	"wosm/pkg/response"
-	"wosm/internal/service/{{.ModuleName}}"
+
+	"github.com/gin-gonic/gin"
 )
 
 // {{.ClassName}}Controller {{.FunctionName}}控制器
 type {{.ClassName}}Controller struct {
-	{{uncapitalize .ClassName}}Service *{{.ModuleName}}.{{.ClassName}}Service
+	{{uncapitalize .ClassName}}Service *{{.ModuleName}}Service.{{.ClassName}}Service
 }
 
 // New{{.ClassName}}Controller 创建{{.FunctionName}}控制器实例
 func New{{.ClassName}}Controller() *{{.ClassName}}Controller {
+	return New{{.ClassName}}ControllerWithService({{.ModuleName}}Service.New{{.ClassName}}Service())
+}
+
+// New{{.ClassName}}ControllerWithService 使用指定的服务创建{{.FunctionName}}控制器实例
+func New{{.ClassName}}ControllerWithService({{uncapitalize .ClassName}}Service *{{.ModuleName}}Service.{{.ClassName}}Service) *{{.ClassName}}Controller {
 	return &{{.ClassName}}Controller{
-		{{uncapitalize .ClassName}}Service: {{.ModuleName}}.New{{.ClassName}}Service(),
+		{{uncapitalize .ClassName}}Service: {{uncapitalize .ClassName}}Service,
+	}
+}
+
+// Register{{.ClassName}}Routes 注册{{.FunctionName}}路由
+func (c *{{.ClassName}}Controller) Register{{.ClassName}}Routes(router *gin.RouterGroup) {
+	group := router.Group("/{{.ModuleName}}/{{.BusinessName}}")
+	{
+		group.GET("/list", middleware.RequirePermission("{{.PermissionPrefix}}:list"), c.List)
+{{- if .Table.IsTree}}
+		group.GET("/treeList", middleware.RequirePermission("{{.PermissionPrefix}}:list"), c.TreeList)
+{{- end}}
+		group.GET("/:{{.PkColumn.JavaField}}", middleware.RequirePermission("{{.PermissionPrefix}}:query"), c.GetInfo)
+		group.POST("", middleware.RequirePermission("{{.PermissionPrefix}}:add"), c.Add)
+		group.PUT("", middleware.RequirePermission("{{.PermissionPrefix}}:edit"), c.Edit)
+		group.DELETE("/:{{.PkColumn.JavaField}}s", middleware.RequirePermission("{{.PermissionPrefix}}:remove"), c.Remove)
 	}
 }
 
 // List 查询{{.FunctionName}}列表
 func (c *{{.ClassName}}Controller) List(ctx *gin.Context) {
-	// TODO: 实现查询列表逻辑
-	response.Success(ctx)
+	var query model.{{.ClassName}}
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		response.ErrorWithMessage(ctx, "参数错误")
+		return
+	}
+{{- if .RangeQuery}}
+	query.Params = ctx.QueryMap("params")
+{{- end}}
+
+	list, err := c.{{uncapitalize .ClassName}}Service.WithContext(ctx.Request.Context()).Select{{.ClassName}}List(&query)
+	if err != nil {
+		response.ErrorWithMessage(ctx, "查询{{.FunctionName}}列表失败")
+		return
+	}
+
+	response.Page(ctx, int64(len(list)), list)
 }
+{{- if .Table.IsTree}}
+
+// TreeList 查询{{.FunctionName}}树结构
+func (c *{{.ClassName}}Controller) TreeList(ctx *gin.Context) {
+	var query model.{{.ClassName}}
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		response.ErrorWithMessage(ctx, "参数错误")
+		return
+	}
+{{- if .RangeQuery}}
+	query.Params = ctx.QueryMap("params")
+{{- end}}
+
+	tree, err := c.{{uncapitalize .ClassName}}Service.WithContext(ctx.Request.Context()).Select{{.ClassName}}TreeList(&query)
+	if err != nil {
+		response.ErrorWithMessage(ctx, "查询{{.FunctionName}}树结构失败")
+		return
+	}
+
+	response.SuccessWithData(ctx, tree)
+}
+{{- end}}
 
 // GetInfo 获取{{.FunctionName}}详细信息
 func (c *{{.ClassName}}Controller) GetInfo(ctx *gin.Context) {
-	// TODO: 实现获取详情逻辑
-	response.Success(ctx)
+	{{.PkColumn.JavaField}}, err := parse{{.ClassName}}Id(ctx.Param("{{.PkColumn.JavaField}}"))
+	if err != nil {
+		response.ErrorWithMessage(ctx, "{{.FunctionName}}ID格式错误")
+		return
+	}
+
+	{{uncapitalize .ClassName}}, err := c.{{uncapitalize .ClassName}}Service.WithContext(ctx.Request.Context()).Select{{.ClassName}}ById({{.PkColumn.JavaField}})
+	if err != nil {
+		response.ErrorWithMessage(ctx, "查询{{.FunctionName}}失败")
+		return
+	}
+	if {{uncapitalize .ClassName}} == nil {
+		response.ErrorWithMessage(ctx, "{{.FunctionName}}不存在")
+		return
+	}
+
+	response.SuccessWithData(ctx, {{uncapitalize .ClassName}})
 }
 
 // Add 新增{{.FunctionName}}
 func (c *{{.ClassName}}Controller) Add(ctx *gin.Context) {
-	// TODO: 实现新增逻辑
-	response.Success(ctx)
+	var {{uncapitalize .ClassName}} model.{{.ClassName}}
+	if err := ctx.ShouldBindJSON(&{{uncapitalize .ClassName}}); err != nil {
+		response.ErrorWithMessage(ctx, "参数错误")
+		return
+	}
+
+	if err := c.{{uncapitalize .ClassName}}Service.WithContext(ctx.Request.Context()).Insert{{.ClassName}}(&{{uncapitalize .ClassName}}); err != nil {
+		response.ErrorWithMessage(ctx, fmt.Sprintf("新增{{.FunctionName}}失败，%v", err))
+		return
+	}
+
+	response.SuccessWithMessage(ctx, "新增成功")
 }
 
 // Edit 修改{{.FunctionName}}
 func (c *{{.ClassName}}Controller) Edit(ctx *gin.Context) {
-	// TODO: 实现修改逻辑
-	response.Success(ctx)
+	var {{uncapitalize .ClassName}} model.{{.ClassName}}
+	if err := ctx.ShouldBindJSON(&{{uncapitalize .ClassName}}); err != nil {
+		response.ErrorWithMessage(ctx, "参数错误")
+		return
+	}
+
+	if err := c.{{uncapitalize .ClassName}}Service.WithContext(ctx.Request.Context()).Update{{.ClassName}}(&{{uncapitalize .ClassName}}); err != nil {
+		response.ErrorWithMessage(ctx, fmt.Sprintf("修改{{.FunctionName}}失败，%v", err))
+		return
+	}
+
+	response.SuccessWithMessage(ctx, "修改成功")
 }
 
 // Remove 删除{{.FunctionName}}
 func (c *{{.ClassName}}Controller) Remove(ctx *gin.Context) {
-	// TODO: 实现删除逻辑
-	response.Success(ctx)
+	var ids []{{$pkType}}
+	for _, value := range strings.Split(ctx.Param("{{.PkColumn.JavaField}}s"), ",") {
+		id, err := parse{{.ClassName}}Id(strings.TrimSpace(value))
+		if err != nil {
+			response.ErrorWithMessage(ctx, "{{.FunctionName}}ID格式错误")
+			return
+		}
+		ids = append(ids, id)
+	}
+
+	if err := c.{{uncapitalize .ClassName}}Service.WithContext(ctx.Request.Context()).Delete{{.ClassName}}ByIds(ids); err != nil {
+		response.ErrorWithMessage(ctx, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(ctx, "删除成功")
+}
+
+// parse{{.ClassName}}Id 解析路径中的{{.FunctionName}}ID
+func parse{{.ClassName}}Id(value string) ({{$pkType}}, error) {
+	var id {{$pkType}}
+	if _, err := fmt.Sscan(value, &id); err != nil {
+		return id, err
+	}
+	return id, nil
 }`
 }
 
 // getServiceTemplate 获取服务模板 对应Java后端的service.java.vm
 func (e *TemplateEngine) getServiceTemplate() string {
 	return `package {{.ModuleName}}
+{{- $pkType := getGoType .PkColumn.JavaType}}
 
 import (
+	"context"
+{{- if .Table.IsTree}}
+	"fmt"
+{{- end}}
+{{- if .AncestorsColumn}}
+	"strings"
+{{- end}}
 	"wosm/internal/repository/dao"
 	"wosm/internal/repository/model"
 )
 
+// {{.ClassName}}Repository {{.FunctionName}}服务依赖的数据访问接口，测试时可替换为 dao.Memory{{.ClassName}}Dao
+type {{.ClassName}}Repository interface {
+	Select{{.ClassName}}List({{uncapitalize .ClassName}} *model.{{.ClassName}}) ([]model.{{.ClassName}}, error)
+	Select{{.ClassName}}ById({{.PkColumn.JavaField}} {{$pkType}}) (*model.{{.ClassName}}, error)
+	Insert{{.ClassName}}({{uncapitalize .ClassName}} *model.{{.ClassName}}) error
+	Update{{.ClassName}}({{uncapitalize .ClassName}} *model.{{.ClassName}}) error
+	Delete{{.ClassName}}ByIds(ids []{{$pkType}}) error
+{{- if .Table.IsTree}}
+{{- $codeType := getGoType .TreeCode.JavaType}}
+	Select{{.ClassName}}ChildrenCount(code {{$codeType}}) (int64, error)
+{{- if .AncestorsColumn}}
+	Select{{.ClassName}}ByTreeCode(code {{$codeType}}) (*model.{{.ClassName}}, error)
+	Select{{.ClassName}}Descendants(code {{$codeType}}) ([]model.{{.ClassName}}, error)
+	Update{{.ClassName}}Ancestors(list []model.{{.ClassName}}) error
+{{- end}}
+{{- end}}
+}
+
 // {{.ClassName}}Service {{.FunctionName}}服务
 type {{.ClassName}}Service struct {
-	{{uncapitalize .ClassName}}Dao *dao.{{.ClassName}}Dao
+	{{uncapitalize .ClassName}}Dao {{.ClassName}}Repository
 }
 
 // New{{.ClassName}}Service 创建{{.FunctionName}}服务实例
 func New{{.ClassName}}Service() *{{.ClassName}}Service {
+	return New{{.ClassName}}ServiceWithDao(dao.New{{.ClassName}}Dao())
+}
+
+// New{{.ClassName}}ServiceWithDao 使用指定的数据访问层创建{{.FunctionName}}服务实例
+func New{{.ClassName}}ServiceWithDao({{uncapitalize .ClassName}}Dao {{.ClassName}}Repository) *{{.ClassName}}Service {
 	return &{{.ClassName}}Service{
-		{{uncapitalize .ClassName}}Dao: dao.New{{.ClassName}}Dao(),
+		{{uncapitalize .ClassName}}Dao: {{uncapitalize .ClassName}}Dao,
 	}
 }
 
+// WithContext 返回使用指定context的服务实例，数据访问携带请求信息；内存数据访问层不区分context
+func (s *{{.ClassName}}Service) WithContext(ctx context.Context) *{{.ClassName}}Service {
+	if {{uncapitalize .ClassName}}Dao, ok := s.{{uncapitalize .ClassName}}Dao.(*dao.{{.ClassName}}Dao); ok {
+		return New{{.ClassName}}ServiceWithDao({{uncapitalize .ClassName}}Dao.WithContext(ctx))
+	}
+	return s
+}
+
 // Select{{.ClassName}}List 查询{{.FunctionName}}列表
 func (s *{{.ClassName}}Service) Select{{.ClassName}}List({{uncapitalize .ClassName}} *model.{{.ClassName}}) ([]model.{{.ClassName}}, error) {
 	return s.{{uncapitalize .ClassName}}Dao.Select{{.ClassName}}List({{uncapitalize .ClassName}})
 }
+{{- if .Table.IsTree}}
+
+// Select{{.ClassName}}TreeList 查询{{.FunctionName}}树结构
+func (s *{{.ClassName}}Service) Select{{.ClassName}}TreeList({{uncapitalize .ClassName}} *model.{{.ClassName}}) ([]model.{{.ClassName}}TreeSelect, error) {
+	list, err := s.{{uncapitalize .ClassName}}Dao.Select{{.ClassName}}List({{uncapitalize .ClassName}})
+	if err != nil {
+		return nil, err
+	}
+	return model.Build{{.ClassName}}Tree(list), nil
+}
+{{- end}}
 
 // Select{{.ClassName}}ById 根据ID查询{{.FunctionName}}
-func (s *{{.ClassName}}Service) Select{{.ClassName}}ById({{.PkColumn.JavaField}} {{getGoType .PkColumn.JavaType}}) (*model.{{.ClassName}}, error) {
+func (s *{{.ClassName}}Service) Select{{.ClassName}}ById({{.PkColumn.JavaField}} {{$pkType}}) (*model.{{.ClassName}}, error) {
 	return s.{{uncapitalize .ClassName}}Dao.Select{{.ClassName}}ById({{.PkColumn.JavaField}})
 }
 
 // Insert{{.ClassName}} 新增{{.FunctionName}}
 func (s *{{.ClassName}}Service) Insert{{.ClassName}}({{uncapitalize .ClassName}} *model.{{.ClassName}}) error {
+{{- if .AncestorsColumn}}
+	ancestors, err := s.build{{.ClassName}}Ancestors({{uncapitalize .ClassName}}.{{capitalize .TreeParentCode.JavaField}})
+	if err != nil {
+		return err
+	}
+	{{uncapitalize .ClassName}}.{{capitalize .AncestorsColumn.JavaField}} = ancestors
+
+{{- end}}
 	return s.{{uncapitalize .ClassName}}Dao.Insert{{.ClassName}}({{uncapitalize .ClassName}})
 }
 
 // Update{{.ClassName}} 修改{{.FunctionName}}
 func (s *{{.ClassName}}Service) Update{{.ClassName}}({{uncapitalize .ClassName}} *model.{{.ClassName}}) error {
+{{- if .Table.IsTree}}
+	if {{uncapitalize .ClassName}}.{{capitalize .TreeParentCode.JavaField}} == {{uncapitalize .ClassName}}.{{capitalize .TreeCode.JavaField}} {
+		return fmt.Errorf("上级{{.FunctionName}}不能是自己")
+	}
+{{- end}}
+{{- if .AncestorsColumn}}
+
+	old, err := s.{{uncapitalize .ClassName}}Dao.Select{{.ClassName}}ById({{uncapitalize .ClassName}}.{{capitalize .PkColumn.JavaField}})
+	if err != nil {
+		return err
+	}
+	if old == nil {
+		return fmt.Errorf("{{.FunctionName}}不存在")
+	}
+
+	// 重新计算祖级列表，上级不能是自己的下级
+	ancestors, err := s.build{{.ClassName}}Ancestors({{uncapitalize .ClassName}}.{{capitalize .TreeParentCode.JavaField}})
+	if err != nil {
+		return err
+	}
+	if strings.Contains(","+ancestors+",", fmt.Sprintf(",%v,", {{uncapitalize .ClassName}}.{{capitalize .TreeCode.JavaField}})) {
+		return fmt.Errorf("上级{{.FunctionName}}不能是自己的下级")
+	}
+	{{uncapitalize .ClassName}}.{{capitalize .AncestorsColumn.JavaField}} = ancestors
+
+	if err := s.{{uncapitalize .ClassName}}Dao.Update{{.ClassName}}({{uncapitalize .ClassName}}); err != nil {
+		return err
+	}
+
+	// 上级变化时同步修改所有下级的祖级列表
+	if old.{{capitalize .AncestorsColumn.JavaField}} == ancestors {
+		return nil
+	}
+	children, err := s.{{uncapitalize .ClassName}}Dao.Select{{.ClassName}}Descendants({{uncapitalize .ClassName}}.{{capitalize .TreeCode.JavaField}})
+	if err != nil {
+		return err
+	}
+	for i := range children {
+		children[i].{{capitalize .AncestorsColumn.JavaField}} = ancestors + strings.TrimPrefix(children[i].{{capitalize .AncestorsColumn.JavaField}}, old.{{capitalize .AncestorsColumn.JavaField}})
+	}
+	return s.{{uncapitalize .ClassName}}Dao.Update{{.ClassName}}Ancestors(children)
+{{- else}}
 	return s.{{uncapitalize .ClassName}}Dao.Update{{.ClassName}}({{uncapitalize .ClassName}})
+{{- end}}
 }
 
 // Delete{{.ClassName}}ByIds 批量删除{{.FunctionName}}
-func (s *{{.ClassName}}Service) Delete{{.ClassName}}ByIds(ids []{{getGoType .PkColumn.JavaType}}) error {
+func (s *{{.ClassName}}Service) Delete{{.ClassName}}ByIds(ids []{{$pkType}}) error {
+{{- if .Table.IsTree}}
+	// 存在下级时不允许删除
+	for _, id := range ids {
+{{- if eq .TreeCode.ColumnName .PkColumn.ColumnName}}
+		count, err := s.{{uncapitalize .ClassName}}Dao.Select{{.ClassName}}ChildrenCount(id)
+{{- else}}
+		item, err := s.{{uncapitalize .ClassName}}Dao.Select{{.ClassName}}ById(id)
+		if err != nil {
+			return err
+		}
+		if item == nil {
+			continue
+		}
+		count, err := s.{{uncapitalize .ClassName}}Dao.Select{{.ClassName}}ChildrenCount(item.{{capitalize .TreeCode.JavaField}})
+{{- end}}
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return fmt.Errorf("存在下级{{.FunctionName}}，不允许删除")
+		}
+	}
+
+{{- end}}
 	return s.{{uncapitalize .ClassName}}Dao.Delete{{.ClassName}}ByIds(ids)
-}`
+}
+{{- if .AncestorsColumn}}
+
+// build{{.ClassName}}Ancestors 根据上级计算祖级列表，上级不存在时作为根节点
+func (s *{{.ClassName}}Service) build{{.ClassName}}Ancestors(parent {{getGoType .TreeParentCode.JavaType}}) (string, error) {
+	item, err := s.{{uncapitalize .ClassName}}Dao.Select{{.ClassName}}ByTreeCode(parent)
+	if err != nil {
+		return "", err
+	}
+	if item == nil {
+		return fmt.Sprint(parent), nil
+	}
+	return item.{{capitalize .AncestorsColumn.JavaField}} + "," + fmt.Sprint(parent), nil
+}
+{{- end}}`
 }
 
 // getDaoTemplate 获取DAO模板 对应Java后端的mapper.java.vm
 func (e *TemplateEngine) getDaoTemplate() string {
 	return `package dao
+{{- $pkType := getGoType .PkColumn.JavaType}}
 
 import (
+	"context"
 	"fmt"
 	"wosm/internal/repository/model"
 	"wosm/pkg/database"
+
 	"gorm.io/gorm"
 )
 
@@ -415,12 +990,45 @@ func New{{.ClassName}}Dao() *{{.ClassName}}Dao {
 	}
 }
 
+// WithContext 返回使用指定context的数据访问层实例，语句的context携带请求信息
+func (d *{{.ClassName}}Dao) WithContext(ctx context.Context) *{{.ClassName}}Dao {
+	return &{{.ClassName}}Dao{db: d.db.WithContext(ctx)}
+}
+
 // Select{{.ClassName}}List 查询{{.FunctionName}}列表
 func (d *{{.ClassName}}Dao) Select{{.ClassName}}List({{uncapitalize .ClassName}} *model.{{.ClassName}}) ([]model.{{.ClassName}}, error) {
 	var list []model.{{.ClassName}}
 	query := d.db.Model(&model.{{.ClassName}}{})
-
-	// TODO: 添加查询条件
+{{- $var := uncapitalize .ClassName}}
+{{- range .QueryColumns}}
+{{- $field := capitalize .JavaField}}
+{{- $goType := getGoType .JavaType}}
+{{- if eq .QueryType "BETWEEN"}}
+	if begin := {{$var}}.Params["begin{{$field}}"]; begin != "" {
+		query = query.Where("{{.ColumnName}} >= ?", begin)
+	}
+	if end := {{$var}}.Params["end{{$field}}"]; end != "" {
+{{- if eq $goType "*time.Time"}}
+		// 结束日期包含当天
+		query = query.Where("{{.ColumnName}} <= ?", end+" 23:59:59")
+{{- else}}
+		query = query.Where("{{.ColumnName}} <= ?", end)
+{{- end}}
+	}
+{{- else if and (eq .QueryType "LIKE") (eq $goType "string")}}
+	if {{$var}}.{{$field}} != "" {
+		query = query.Where("{{.ColumnName}} LIKE ?", "%"+{{$var}}.{{$field}}+"%")
+	}
+{{- else if eq $goType "bool"}}
+	if {{$var}}.{{$field}} {
+		query = query.Where("{{.ColumnName}} {{queryOperator .QueryType}} ?", true)
+	}
+{{- else}}
+	if {{$var}}.{{$field}} != {{zeroValue .JavaType}} {
+		query = query.Where("{{.ColumnName}} {{queryOperator .QueryType}} ?", {{$var}}.{{$field}})
+	}
+{{- end}}
+{{- end}}
 
 	err := query.Find(&list).Error
 	if err != nil {
@@ -433,7 +1041,7 @@ func (d *{{.ClassName}}Dao) Select{{.ClassName}}List({{uncapitalize .ClassName}}
 }
 
 // Select{{.ClassName}}ById 根据ID查询{{.FunctionName}}
-func (d *{{.ClassName}}Dao) Select{{.ClassName}}ById({{.PkColumn.JavaField}} {{getGoType .PkColumn.JavaType}}) (*model.{{.ClassName}}, error) {
+func (d *{{.ClassName}}Dao) Select{{.ClassName}}ById({{.PkColumn.JavaField}} {{$pkType}}) (*model.{{.ClassName}}, error) {
 	var {{uncapitalize .ClassName}} model.{{.ClassName}}
 	err := d.db.Where("{{.PkColumn.ColumnName}} = ?", {{.PkColumn.JavaField}}).First(&{{uncapitalize .ClassName}}).Error
 	if err != nil {
@@ -444,6 +1052,15 @@ func (d *{{.ClassName}}Dao) Select{{.ClassName}}ById({{.PkColumn.JavaField}} {{g
 		fmt.Printf("Select{{.ClassName}}ById: 查询{{.FunctionName}}失败: %v\n", err)
 		return nil, err
 	}
+{{- if .SubClassName}}
+
+	// 查询子表数据
+	err = d.db.Where("{{.SubTableFkColumn.ColumnName}} = ?", {{.PkColumn.JavaField}}).Find(&{{uncapitalize .ClassName}}.{{.SubClassName}}List).Error
+	if err != nil {
+		fmt.Printf("Select{{.ClassName}}ById: 查询{{.SubTable.FunctionName}}失败: %v\n", err)
+		return nil, err
+	}
+{{- end}}
 
 	fmt.Printf("Select{{.ClassName}}ById: 查询{{.FunctionName}}成功, ID=%v\n", {{.PkColumn.JavaField}})
 	return &{{uncapitalize .ClassName}}, nil
@@ -451,7 +1068,17 @@ func (d *{{.ClassName}}Dao) Select{{.ClassName}}ById({{.PkColumn.JavaField}} {{g
 
 // Insert{{.ClassName}} 新增{{.FunctionName}}
 func (d *{{.ClassName}}Dao) Insert{{.ClassName}}({{uncapitalize .ClassName}} *model.{{.ClassName}}) error {
+{{- if .SubClassName}}
+	// 主表和子表在同一事务中保存
+	err := d.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create({{uncapitalize .ClassName}}).Error; err != nil {
+			return err
+		}
+		return insert{{.ClassName}}{{.SubClassName}}List(tx, {{uncapitalize .ClassName}})
+	})
+{{- else}}
 	err := d.db.Create({{uncapitalize .ClassName}}).Error
+{{- end}}
 	if err != nil {
 		fmt.Printf("Insert{{.ClassName}}: 新增{{.FunctionName}}失败: %v\n", err)
 		return err
@@ -463,7 +1090,20 @@ func (d *{{.ClassName}}Dao) Insert{{.ClassName}}({{uncapitalize .ClassName}} *mo
 
 // Update{{.ClassName}} 修改{{.FunctionName}}
 func (d *{{.ClassName}}Dao) Update{{.ClassName}}({{uncapitalize .ClassName}} *model.{{.ClassName}}) error {
+{{- if .SubClassName}}
+	// 修改主表后删除原有子表数据再重新插入
+	err := d.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("{{.PkColumn.ColumnName}} = ?", {{uncapitalize .ClassName}}.{{capitalize .PkColumn.JavaField}}).Updates({{uncapitalize .ClassName}}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("{{.SubTableFkColumn.ColumnName}} = ?", {{uncapitalize .ClassName}}.{{capitalize .PkColumn.JavaField}}).Delete(&model.{{.SubClassName}}{}).Error; err != nil {
+			return err
+		}
+		return insert{{.ClassName}}{{.SubClassName}}List(tx, {{uncapitalize .ClassName}})
+	})
+{{- else}}
 	err := d.db.Where("{{.PkColumn.ColumnName}} = ?", {{uncapitalize .ClassName}}.{{capitalize .PkColumn.JavaField}}).Updates({{uncapitalize .ClassName}}).Error
+{{- end}}
 	if err != nil {
 		fmt.Printf("Update{{.ClassName}}: 修改{{.FunctionName}}失败: %v\n", err)
 		return err
@@ -474,8 +1114,18 @@ func (d *{{.ClassName}}Dao) Update{{.ClassName}}({{uncapitalize .ClassName}} *mo
 }
 
 // Delete{{.ClassName}}ByIds 批量删除{{.FunctionName}}
-func (d *{{.ClassName}}Dao) Delete{{.ClassName}}ByIds(ids []{{getGoType .PkColumn.JavaType}}) error {
+func (d *{{.ClassName}}Dao) Delete{{.ClassName}}ByIds(ids []{{$pkType}}) error {
+{{- if .SubClassName}}
+	// 先删除子表数据再删除主表
+	err := d.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("{{.SubTableFkColumn.ColumnName}} IN ?", ids).Delete(&model.{{.SubClassName}}{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("{{.PkColumn.ColumnName}} IN ?", ids).Delete(&model.{{.ClassName}}{}).Error
+	})
+{{- else}}
 	err := d.db.Where("{{.PkColumn.ColumnName}} IN ?", ids).Delete(&model.{{.ClassName}}{}).Error
+{{- end}}
 	if err != nil {
 		fmt.Printf("Delete{{.ClassName}}ByIds: 批量删除{{.FunctionName}}失败: %v\n", err)
 		return err
@@ -483,49 +1133,231 @@ func (d *{{.ClassName}}Dao) Delete{{.ClassName}}ByIds(ids []{{getGoType .PkColum
 
 	fmt.Printf("Delete{{.ClassName}}ByIds: 批量删除{{.FunctionName}}成功, 数量=%d\n", len(ids))
 	return nil
+}
+{{- if .Table.IsTree}}
+{{- $codeType := getGoType .TreeCode.JavaType}}
+
+// Select{{.ClassName}}ByTreeCode 根据树编码查询{{.FunctionName}}
+func (d *{{.ClassName}}Dao) Select{{.ClassName}}ByTreeCode(code {{$codeType}}) (*model.{{.ClassName}}, error) {
+	var {{uncapitalize .ClassName}} model.{{.ClassName}}
+	err := d.db.Where("{{.TreeCode.ColumnName}} = ?", code).First(&{{uncapitalize .ClassName}}).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		fmt.Printf("Select{{.ClassName}}ByTreeCode: 查询{{.FunctionName}}失败: %v\n", err)
+		return nil, err
+	}
+
+	return &{{uncapitalize .ClassName}}, nil
+}
+
+// Select{{.ClassName}}ChildrenCount 查询直接下级的数量
+func (d *{{.ClassName}}Dao) Select{{.ClassName}}ChildrenCount(code {{$codeType}}) (int64, error) {
+	var count int64
+	err := d.db.Model(&model.{{.ClassName}}{}).Where("{{.TreeParentCode.ColumnName}} = ?", code).Count(&count).Error
+	if err != nil {
+		fmt.Printf("Select{{.ClassName}}ChildrenCount: 查询下级{{.FunctionName}}数量失败: %v\n", err)
+		return 0, err
+	}
+
+	return count, nil
+}
+{{- if .AncestorsColumn}}
+
+// Select{{.ClassName}}Descendants 根据祖级列表查询所有下级
+func (d *{{.ClassName}}Dao) Select{{.ClassName}}Descendants(code {{$codeType}}) ([]model.{{.ClassName}}, error) {
+	var list []model.{{.ClassName}}
+	id := fmt.Sprint(code)
+	err := d.db.Where("{{.AncestorsColumn.ColumnName}} LIKE ? OR {{.AncestorsColumn.ColumnName}} LIKE ?", "%,"+id+",%", "%,"+id).Find(&list).Error
+	if err != nil {
+		fmt.Printf("Select{{.ClassName}}Descendants: 查询下级{{.FunctionName}}失败: %v\n", err)
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// Update{{.ClassName}}Ancestors 批量修改祖级列表
+func (d *{{.ClassName}}Dao) Update{{.ClassName}}Ancestors(list []model.{{.ClassName}}) error {
+	err := d.db.Transaction(func(tx *gorm.DB) error {
+		for _, item := range list {
+			err := tx.Model(&model.{{.ClassName}}{}).Where("{{.PkColumn.ColumnName}} = ?", item.{{capitalize .PkColumn.JavaField}}).
+				Update("{{.AncestorsColumn.ColumnName}}", item.{{capitalize .AncestorsColumn.JavaField}}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Printf("Update{{.ClassName}}Ancestors: 修改祖级列表失败: %v\n", err)
+		return err
+	}
+
+	return nil
+}
+{{- end}}
+{{- end}}
+{{- if .SubClassName}}
+
+// insert{{.ClassName}}{{.SubClassName}}List 批量新增{{.SubTable.FunctionName}}，外键取主表主键
+func insert{{.ClassName}}{{.SubClassName}}List(tx *gorm.DB, {{uncapitalize .ClassName}} *model.{{.ClassName}}) error {
+	if len({{uncapitalize .ClassName}}.{{.SubClassName}}List) == 0 {
+		return nil
+	}
+	for i := range {{uncapitalize .ClassName}}.{{.SubClassName}}List {
+		{{uncapitalize .ClassName}}.{{.SubClassName}}List[i].{{capitalize .SubTableFkColumn.JavaField}} = {{uncapitalize .ClassName}}.{{capitalize .PkColumn.JavaField}}
+{{- if .SubPkColumn.IsAutoIncrement}}
+		{{uncapitalize .ClassName}}.{{.SubClassName}}List[i].{{capitalize .SubPkColumn.JavaField}} = {{zeroValue .SubPkColumn.JavaType}}
+{{- end}}
+	}
+	return tx.Create(&{{uncapitalize .ClassName}}.{{.SubClassName}}List).Error
+}
+{{- end}}`
+}
+
+// getTreeTemplate 获取树表模板，生成树结构和构建方法 对应Java后端的树表模板
+func (e *TemplateEngine) getTreeTemplate() string {
+	return `package model
+
+import (
+	"fmt"
+)
+
+// {{.ClassName}}TreeSelect {{.FunctionName}}树结构
+type {{.ClassName}}TreeSelect struct {
+	ID       {{getGoType .TreeCode.JavaType}} ` + "`" + `json:"id"` + "`" + `
+	Label    string ` + "`" + `json:"label"` + "`" + `
+	Children []{{.ClassName}}TreeSelect ` + "`" + `json:"children,omitempty"` + "`" + `
+}
+
+// Build{{.ClassName}}Tree 构建{{.FunctionName}}树，上级不在列表中的节点作为根节点
+func Build{{.ClassName}}Tree(list []{{.ClassName}}) []{{.ClassName}}TreeSelect {
+	codes := make(map[{{getGoType .TreeCode.JavaType}}]bool, len(list))
+	children := make(map[{{getGoType .TreeCode.JavaType}}][]{{.ClassName}}, len(list))
+	for _, item := range list {
+		codes[item.{{capitalize .TreeCode.JavaField}}] = true
+		children[item.{{capitalize .TreeParentCode.JavaField}}] = append(children[item.{{capitalize .TreeParentCode.JavaField}}], item)
+	}
+
+	var tree []{{.ClassName}}TreeSelect
+	for _, item := range list {
+		if !codes[item.{{capitalize .TreeParentCode.JavaField}}] {
+			tree = append(tree, build{{.ClassName}}TreeNode(item, children, 0))
+		}
+	}
+	return tree
+}
+
+// build{{.ClassName}}TreeNode 递归构建树节点，depth 用于防止数据存在环时无限递归
+func build{{.ClassName}}TreeNode(item {{.ClassName}}, children map[{{getGoType .TreeCode.JavaType}}][]{{.ClassName}}, depth int) {{.ClassName}}TreeSelect {
+	node := {{.ClassName}}TreeSelect{
+		ID:    item.{{capitalize .TreeCode.JavaField}},
+		Label: fmt.Sprint(item.{{capitalize .TreeName.JavaField}}),
+	}
+	if depth >= 100 {
+		return node
+	}
+	for _, child := range children[item.{{capitalize .TreeCode.JavaField}}] {
+		node.Children = append(node.Children, build{{.ClassName}}TreeNode(child, children, depth+1))
+	}
+	return node
+}`
+}
+
+// getSubTemplate 获取主子表模板，生成子表模型 对应Java后端的主子表模板
+func (e *TemplateEngine) getSubTemplate() string {
+	return `package model
+{{- if .SubImportList}}
+
+import (
+{{- range .SubImportList}}
+	"{{.}}"
+{{- end}}
+)
+{{- end}}
+
+// {{.SubClassName}} {{.SubTable.FunctionName}}（{{.FunctionName}}子表，通过{{.SubTableFkColumn.ColumnName}}关联）
+type {{.SubClassName}} struct {
+{{- range .SubTable.Columns}}
+	{{capitalize .JavaField}} {{getGoType .JavaType}} ` + "`" + `gorm:"column:{{.ColumnName}}{{if .IsPrimaryKey}};primaryKey{{if .IsAutoIncrement}};autoIncrement{{end}}{{end}}" json:"{{.JavaField}}"` + "`" + ` // {{.ColumnComment}}
+{{- end}}
+}
+
+// TableName 指定表名
+func ({{.SubClassName}}) TableName() string {
+	return "{{.SubTable.Name}}"
 }`
 }
 
-// getSqlTemplate 获取SQL模板 对应Java后端的sql.vm
+// getSqlTemplate 获取SQL模板 对应Java后端的sql.vm，按当前数据库方言生成建表和菜单语句
 func (e *TemplateEngine) getSqlTemplate() string {
-	return `-- {{.FunctionName}}表 {{.TableComment}}
+	return `{{- $d := .Dialect}}{{$db := $d.Name -}}
+-- {{.FunctionName}}表 {{.TableComment}}
 -- 作者: {{.FunctionAuthor}}
 -- 日期: {{.DateTime}}
+-- 数据库: {{$db}}
 
 -- 表结构
 CREATE TABLE {{.TableName}} (
 {{- range $index, $column := .Columns}}
-    {{$column.ColumnName}} {{$column.ColumnType}}{{if $column.IsRequired}} NOT NULL{{end}}{{if $column.ColumnComment}} COMMENT '{{$column.ColumnComment}}'{{end}}{{if ne $index (sub (len $.Columns) 1)}},{{end}}
+    {{$column.ColumnName}} {{$column.ColumnType}}{{if $column.IsRequiredField}} NOT NULL{{end}}{{if and (eq $db "mysql") $column.ColumnComment}} COMMENT {{$d.StringLiteral $column.ColumnComment}}{{end}}{{if or $.PkColumn (ne $index (sub (len $.Columns) 1))}},{{end}}{{if and (eq $db "sqlite") $column.ColumnComment}} -- {{$column.ColumnComment}}{{end}}
 {{- end}}
 {{- if .PkColumn}}
     PRIMARY KEY ({{.PkColumn.ColumnName}})
 {{- end}}
-) COMMENT = '{{.TableComment}}';
+){{if eq $db "mysql"}} COMMENT = {{$d.StringLiteral .TableComment}}{{end}};
+{{- if eq $db "postgres"}}
+COMMENT ON TABLE {{.TableName}} IS {{$d.StringLiteral .TableComment}};
+{{- range .Columns}}{{if .ColumnComment}}
+COMMENT ON COLUMN {{$.TableName}}.{{.ColumnName}} IS {{$d.StringLiteral .ColumnComment}};
+{{- end}}{{end}}
+{{- else if eq $db "sqlserver"}}
+EXEC sp_addextendedproperty 'MS_Description', {{$d.StringLiteral .TableComment}}, 'SCHEMA', 'dbo', 'TABLE', '{{.TableName}}';
+{{- range .Columns}}{{if .ColumnComment}}
+EXEC sp_addextendedproperty 'MS_Description', {{$d.StringLiteral .ColumnComment}}, 'SCHEMA', 'dbo', 'TABLE', '{{$.TableName}}', 'COLUMN', '{{.ColumnName}}';
+{{- end}}{{end}}
+{{- end}}
 
 -- 菜单SQL
 INSERT INTO sys_menu (menu_name, parent_id, order_num, path, component, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark)
-VALUES ('{{.FunctionName}}', '{{.ParentMenuId}}', '1', '{{.ModuleName}}/{{.BusinessName}}', '{{.ModuleName}}/{{.BusinessName}}/index', 1, 0, 'C', '0', '0', '{{.PermissionPrefix}}:list', '#', 'admin', GETDATE(), '', NULL, '{{.FunctionName}}菜单');
+VALUES ({{$d.StringLiteral .FunctionName}}, {{.ParentMenuId}}, 1, '{{.ModuleName}}/{{.BusinessName}}', '{{.ModuleName}}/{{.BusinessName}}/index', 1, 0, 'C', '0', '0', '{{.PermissionPrefix}}:list', '#', 'admin', {{$d.Now}}, '', NULL, {{$d.StringLiteral (printf "%s菜单" .FunctionName)}});
 
 -- 按钮父菜单ID
-DECLARE @MenuId INT = SCOPE_IDENTITY();
+{{- $menuId := $d.InsertIDVar "parent_menu_id"}}
+{{$menuId.Declare}}
 
 -- 查询按钮
 INSERT INTO sys_menu (menu_name, parent_id, order_num, path, component, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark)
-VALUES ('{{.FunctionName}}查询', @MenuId, '1', '#', '', 1, 0, 'F', '0', '0', '{{.PermissionPrefix}}:query', '#', 'admin', GETDATE(), '', NULL, '');
+VALUES ({{$d.StringLiteral (printf "%s查询" .FunctionName)}}, {{$menuId.Ref}}, 1, '#', '', 1, 0, 'F', '0', '0', '{{.PermissionPrefix}}:query', '#', 'admin', {{$d.Now}}, '', NULL, '');
 
 -- 新增按钮
 INSERT INTO sys_menu (menu_name, parent_id, order_num, path, component, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark)
-VALUES ('{{.FunctionName}}新增', @MenuId, '2', '#', '', 1, 0, 'F', '0', '0', '{{.PermissionPrefix}}:add', '#', 'admin', GETDATE(), '', NULL, '');
+VALUES ({{$d.StringLiteral (printf "%s新增" .FunctionName)}}, {{$menuId.Ref}}, 2, '#', '', 1, 0, 'F', '0', '0', '{{.PermissionPrefix}}:add', '#', 'admin', {{$d.Now}}, '', NULL, '');
 
 -- 修改按钮
 INSERT INTO sys_menu (menu_name, parent_id, order_num, path, component, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark)
-VALUES ('{{.FunctionName}}修改', @MenuId, '3', '#', '', 1, 0, 'F', '0', '0', '{{.PermissionPrefix}}:edit', '#', 'admin', GETDATE(), '', NULL, '');
+VALUES ({{$d.StringLiteral (printf "%s修改" .FunctionName)}}, {{$menuId.Ref}}, 3, '#', '', 1, 0, 'F', '0', '0', '{{.PermissionPrefix}}:edit', '#', 'admin', {{$d.Now}}, '', NULL, '');
 
 -- 删除按钮
 INSERT INTO sys_menu (menu_name, parent_id, order_num, path, component, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark)
-VALUES ('{{.FunctionName}}删除', @MenuId, '4', '#', '', 1, 0, 'F', '0', '0', '{{.PermissionPrefix}}:remove', '#', 'admin', GETDATE(), '', NULL, '');
+VALUES ({{$d.StringLiteral (printf "%s删除" .FunctionName)}}, {{$menuId.Ref}}, 4, '#', '', 1, 0, 'F', '0', '0', '{{.PermissionPrefix}}:remove', '#', 'admin', {{$d.Now}}, '', NULL, '');
 
 -- 导出按钮
 INSERT INTO sys_menu (menu_name, parent_id, order_num, path, component, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark)
-VALUES ('{{.FunctionName}}导出', @MenuId, '5', '#', '', 1, 0, 'F', '0', '0', '{{.PermissionPrefix}}:export', '#', 'admin', GETDATE(), '', NULL, '');`
+VALUES ({{$d.StringLiteral (printf "%s导出" .FunctionName)}}, {{$menuId.Ref}}, 5, '#', '', 1, 0, 'F', '0', '0', '{{.PermissionPrefix}}:export', '#', 'admin', {{$d.Now}}, '', NULL, '');
+{{- if $menuId.Drop}}
+{{$menuId.Drop}}
+{{- end}}
+{{- if .TreeParentCode}}
+
+-- 树表上级字段索引
+CREATE INDEX idx_{{.TableName}}_{{.TreeParentCode.ColumnName}} ON {{.TableName}} ({{.TreeParentCode.ColumnName}});
+{{- end}}
+{{- if .SubTableFkColumn}}
+
+-- 子表外键索引（{{.SubTable.FunctionName}}）
+CREATE INDEX idx_{{.SubTable.Name}}_{{.SubTableFkColumn.ColumnName}} ON {{.SubTable.Name}} ({{.SubTableFkColumn.ColumnName}});
+{{- end}}`
 }
@@ -0,0 +1,272 @@
+package tool
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"wosm/internal/repository/model"
+	"wosm/internal/testutil"
+	"wosm/pkg/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "更新golden文件")
+
+// genColumn 构造测试用的表字段
+func genColumn(columnName, javaField, javaType, comment string, pk bool) model.GenTableColumn {
+	column := model.GenTableColumn{
+		ColumnName:    columnName,
+		JavaField:     javaField,
+		JavaType:      javaType,
+		ColumnComment: comment,
+		HtmlType:      model.HtmlTypeInput,
+		QueryType:     model.QueryTypeEQ,
+	}
+	if pk {
+		column.IsPk = "1"
+		column.IsIncrement = "1"
+	} else {
+		column.IsInsert = "1"
+		column.IsEdit = "1"
+		column.IsList = "1"
+	}
+	return column
+}
+
+// withQuery 设置字段的查询方式和显示类型
+func withQuery(column model.GenTableColumn, queryType, htmlType, dictType string) model.GenTableColumn {
+	column.IsQuery = "1"
+	column.QueryType = queryType
+	column.HtmlType = htmlType
+	column.DictType = dictType
+	return column
+}
+
+func crudTable() *model.GenTable {
+	remark := genColumn("remark", "remark", model.JavaTypeString, "备注", false)
+	remark.HtmlType = model.HtmlTypeTextarea
+	remark.IsList = "0"
+	tags := genColumn("tags", "tags", model.JavaTypeString, "标签", false)
+	tags.HtmlType = model.HtmlTypeCheckbox
+	tags.DictType = "biz_notice_tag"
+	title := withQuery(genColumn("notice_title", "noticeTitle", model.JavaTypeString, "公告标题", false), model.QueryTypeLike, model.HtmlTypeInput, "")
+	title.IsRequired = "1"
+
+	return &model.GenTable{
+		Name:           "biz_notice",
+		TableComment:   "公告表",
+		ClassName:      "BizNotice",
+		TplCategory:    model.TplCategoryCrud,
+		TplWebType:     model.TplWebTypeElementUI,
+		ModuleName:     "biz",
+		BusinessName:   "notice",
+		FunctionName:   "公告",
+		FunctionAuthor: "ruoyi",
+		Columns: []model.GenTableColumn{
+			genColumn("notice_id", "noticeId", model.JavaTypeLong, "公告ID", true),
+			title,
+			withQuery(genColumn("notice_type", "noticeType", model.JavaTypeString, "公告类型（1通知 2公告）", false), model.QueryTypeEQ, model.HtmlTypeSelect, "sys_notice_type"),
+			withQuery(genColumn("status", "status", model.JavaTypeString, "状态", false), model.QueryTypeEQ, model.HtmlTypeRadio, "sys_normal_disable"),
+			withQuery(genColumn("publish_time", "publishTime", model.JavaTypeDate, "发布时间", false), model.QueryTypeBetween, model.HtmlTypeDatetime, ""),
+			tags,
+			remark,
+		},
+	}
+}
+
+func treeTable() *model.GenTable {
+	return &model.GenTable{
+		Name:           "biz_category",
+		TableComment:   "商品分类表",
+		ClassName:      "BizCategory",
+		TplCategory:    model.TplCategoryTree,
+		TplWebType:     model.TplWebTypeElementPlus,
+		ModuleName:     "biz",
+		BusinessName:   "category",
+		FunctionName:   "商品分类",
+		FunctionAuthor: "ruoyi",
+		TreeCode:       "category_id",
+		TreeParentCode: "parent_id",
+		TreeName:       "category_name",
+		Columns: []model.GenTableColumn{
+			genColumn("category_id", "categoryId", model.JavaTypeLong, "分类ID", true),
+			genColumn("parent_id", "parentId", model.JavaTypeLong, "父分类ID", false),
+			genColumn("ancestors", "ancestors", model.JavaTypeString, "祖级列表", false),
+			withQuery(genColumn("category_name", "categoryName", model.JavaTypeString, "分类名称", false), model.QueryTypeLike, model.HtmlTypeInput, ""),
+			withQuery(genColumn("order_num", "orderNum", model.JavaTypeInteger, "显示顺序", false), model.QueryTypeGTE, model.HtmlTypeInput, ""),
+			withQuery(genColumn("status", "status", model.JavaTypeString, "状态", false), model.QueryTypeEQ, model.HtmlTypeRadio, "sys_normal_disable"),
+		},
+	}
+}
+
+func subTable() *model.GenTable {
+	return &model.GenTable{
+		Name:           "biz_order",
+		TableComment:   "订单表",
+		ClassName:      "BizOrder",
+		TplCategory:    model.TplCategorySub,
+		TplWebType:     model.TplWebTypeElementPlusTs,
+		ModuleName:     "biz",
+		BusinessName:   "order",
+		FunctionName:   "订单",
+		FunctionAuthor: "ruoyi",
+		SubTableName:   "biz_order_item",
+		SubTableFkName: "order_id",
+		Columns: []model.GenTableColumn{
+			genColumn("order_id", "orderId", model.JavaTypeLong, "订单ID", true),
+			withQuery(genColumn("order_no", "orderNo", model.JavaTypeString, "订单编号", false), model.QueryTypeLike, model.HtmlTypeInput, ""),
+			withQuery(genColumn("create_time", "createTime", model.JavaTypeDate, "下单时间", false), model.QueryTypeBetween, model.HtmlTypeDatetime, ""),
+		},
+		SubTable: &model.GenTable{
+			Name:         "biz_order_item",
+			ClassName:    "BizOrderItem",
+			FunctionName: "订单明细",
+			Columns: []model.GenTableColumn{
+				genColumn("item_id", "itemId", model.JavaTypeLong, "明细ID", true),
+				genColumn("order_id", "orderId", model.JavaTypeLong, "订单ID", false),
+				genColumn("goods_name", "goodsName", model.JavaTypeString, "商品名称", false),
+				genColumn("quantity", "quantity", model.JavaTypeInteger, "数量", false),
+			},
+		},
+	}
+}
+
+func TestRenderTemplateGolden(t *testing.T) {
+	tests := []struct {
+		name  string
+		table *model.GenTable
+	}{
+		{"crud", crudTable()},
+		{"tree", treeTable()},
+		{"sub", subTable()},
+	}
+
+	engine := NewTemplateEngine()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := engine.PrepareContext(tt.table)
+			ctx.DateTime = "2026-01-01 00:00:00"
+			ctx.Dialect, _ = database.GetDialect(database.DriverSqlServer)
+			require.NoError(t, engine.ValidateContext(ctx))
+
+			for _, templateName := range engine.GetTemplateList(tt.table.TplCategory, tt.table.TplWebType) {
+				code, err := engine.RenderTemplate(templateName, ctx)
+				require.NoError(t, err, templateName)
+
+				golden := filepath.Join("testdata", "golden", tt.name, strings.TrimSuffix(templateName, ".tmpl")+".golden")
+				if *update {
+					require.NoError(t, os.MkdirAll(filepath.Dir(golden), 0o755))
+					require.NoError(t, os.WriteFile(golden, []byte(code), 0o644))
+					continue
+				}
+				expected, err := os.ReadFile(golden)
+				require.NoError(t, err)
+				assert.Equal(t, string(expected), code, templateName)
+			}
+		})
+	}
+}
+
+// sqlTemplateContext 树表在指定方言下的模板上下文，字段带数据库类型
+func sqlTemplateContext(t *testing.T, engine *TemplateEngine, driver string) *TemplateContext {
+	columnTypes := map[string]string{
+		"category_id": "bigint", "parent_id": "bigint", "ancestors": "varchar(50)",
+		"category_name": "varchar(30)", "order_num": "int", "status": "char(1)",
+	}
+	table := treeTable()
+	for i := range table.Columns {
+		table.Columns[i].ColumnType = columnTypes[table.Columns[i].ColumnName]
+	}
+	ctx := engine.PrepareContext(table)
+	ctx.DateTime = "2026-01-01 00:00:00"
+	dialect, err := database.GetDialect(driver)
+	require.NoError(t, err)
+	ctx.Dialect = dialect
+	return ctx
+}
+
+// TestRenderSqlTemplateDialects 各数据库方言的建表和菜单脚本
+func TestRenderSqlTemplateDialects(t *testing.T) {
+	engine := NewTemplateEngine()
+	for _, driver := range []string{database.DriverSqlServer, database.DriverMySQL, database.DriverPostgres, database.DriverSQLite} {
+		t.Run(driver, func(t *testing.T) {
+			ctx := sqlTemplateContext(t, engine, driver)
+
+			code, err := engine.RenderTemplate("sql.tmpl", ctx)
+			require.NoError(t, err)
+			golden := filepath.Join("testdata", "golden", "sql", driver+".golden")
+			if *update {
+				require.NoError(t, os.MkdirAll(filepath.Dir(golden), 0o755))
+				require.NoError(t, os.WriteFile(golden, []byte(code), 0o644))
+				return
+			}
+			expected, err := os.ReadFile(golden)
+			require.NoError(t, err)
+			assert.Equal(t, string(expected), code)
+		})
+	}
+}
+
+func TestExecuteSqlTemplateOnSQLite(t *testing.T) {
+	db := testutil.OpenMigratedDB(t)
+	engine := NewTemplateEngine()
+	code, err := engine.RenderTemplate("sql.tmpl", sqlTemplateContext(t, engine, database.DriverSQLite))
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(code).Error)
+
+	var menus []model.SysMenu
+	require.NoError(t, db.Where("perms LIKE ?", "biz:category:%").Order("menu_id").Find(&menus).Error)
+	require.Len(t, menus, 6)
+	assert.Equal(t, "biz:category:list", menus[0].Perms)
+	for _, button := range menus[1:] {
+		assert.Equal(t, menus[0].MenuID, button.ParentID, button.Perms)
+	}
+	assert.True(t, db.Migrator().HasTable("biz_category"))
+	assert.False(t, db.Migrator().HasTable("parent_menu_id"))
+}
+
+func TestValidateContext(t *testing.T) {
+	engine := NewTemplateEngine()
+
+	table := treeTable()
+	table.TreeName = "missing"
+	assert.Error(t, engine.ValidateContext(engine.PrepareContext(table)))
+
+	table = subTable()
+	table.SubTableFkName = "missing"
+	assert.Error(t, engine.ValidateContext(engine.PrepareContext(table)))
+
+	table = subTable()
+	table.SubTable = nil
+	assert.Error(t, engine.ValidateContext(engine.PrepareContext(table)))
+}
+
+func TestRenderCustomTemplate(t *testing.T) {
+	engine := NewTemplateEngine()
+	ctx := engine.PrepareContext(crudTable())
+
+	// 内置模板都可以作为自定义模板的起点
+	for _, name := range engine.BuiltinTemplateNames() {
+		content, err := engine.GetBuiltinTemplate(name)
+		require.NoError(t, err, name)
+		assert.NoError(t, engine.ParseTemplate(name, content), name)
+	}
+
+	code, err := engine.RenderContent("model.go.tmpl", "package model\n\ntype {{.ClassName}} struct{}\n", ctx)
+	require.NoError(t, err)
+	assert.Contains(t, code, "type "+ctx.ClassName+" struct{}")
+
+	// 前端模板使用 [[ ]] 分隔符，{{ }} 原样输出
+	code, err = engine.RenderContent("index.vue.tmpl", "<span>{{ row.name }}</span>[[.ClassName]]", ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "<span>{{ row.name }}</span>"+ctx.ClassName, code)
+
+	assert.Error(t, engine.ParseTemplate("unknown.tmpl", ""))
+	assert.Error(t, engine.ParseTemplate("model.go.tmpl", "{{if .ClassName}}"))
+	_, err = engine.RenderContent("model.go.tmpl", "package model\n\nfunc {", ctx)
+	assert.Error(t, err)
+}
@@ -0,0 +1,148 @@
+package tool
+
+import (
+	"encoding/json"
+	"wosm/internal/repository/model"
+)
+
+// swaggerType 获取字段在Swagger文档中的类型定义（JSON片段）
+func swaggerType(javaType string) string {
+	switch javaType {
+	case model.JavaTypeInteger:
+		return `"type": "integer", "format": "int32"`
+	case model.JavaTypeLong:
+		return `"type": "integer", "format": "int64"`
+	case model.JavaTypeDouble, model.JavaTypeBigDecimal:
+		return `"type": "number"`
+	case model.JavaTypeBoolean:
+		return `"type": "boolean"`
+	case model.JavaTypeDate:
+		return `"type": "string", "format": "date-time"`
+	default:
+		return `"type": "string"`
+	}
+}
+
+// jsonString 将字符串转换为JSON字符串字面量（含引号）
+func jsonString(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
+
+// getOpenAPITemplate 获取OpenAPI片段模板，生成的片段由Swagger控制器合并到 /swagger-ui/api-docs
+func (e *TemplateEngine) getOpenAPITemplate() string {
+	return `{{- $tag := jsonString .FunctionName -}}
+{{- $base := printf "/%s/%s" .ModuleName .BusinessName -}}
+{{- $pk := .PkColumn -}}
+{
+  "paths": {
+    "{{$base}}/list": {
+      "get": {
+        "tags": [{{$tag}}],
+        "summary": {{jsonString (printf "查询%s列表" .FunctionName)}},
+        "operationId": "list{{.ClassName}}",
+        "produces": ["application/json"],
+        "parameters": [
+          {"name": "pageNum", "in": "query", "required": false, "type": "integer"},
+          {"name": "pageSize", "in": "query", "required": false, "type": "integer"}
+{{- range .QueryColumns}},
+          {"name": "{{.JavaField}}", "in": "query", "description": {{jsonString (columnLabel .ColumnComment)}}, "required": false, {{swaggerType .JavaType}}}
+{{- end}}
+        ],
+        "security": [{"Authorization": []}],
+        "responses": {"200": {"description": "查询成功", "schema": {"$ref": "#/definitions/TableDataInfo"}}}
+      }
+    },
+{{- if .Table.IsTree}}
+    "{{$base}}/treeList": {
+      "get": {
+        "tags": [{{$tag}}],
+        "summary": {{jsonString (printf "查询%s树结构" .FunctionName)}},
+        "operationId": "treeList{{.ClassName}}",
+        "produces": ["application/json"],
+        "security": [{"Authorization": []}],
+        "responses": {"200": {"description": "查询成功", "schema": {"$ref": "#/definitions/AjaxResult"}}}
+      }
+    },
+{{- end}}
+    "{{$base}}/{{"{"}}{{$pk.JavaField}}{{"}"}}": {
+      "get": {
+        "tags": [{{$tag}}],
+        "summary": {{jsonString (printf "获取%s详细信息" .FunctionName)}},
+        "operationId": "get{{.ClassName}}",
+        "produces": ["application/json"],
+        "parameters": [
+          {"name": "{{$pk.JavaField}}", "in": "path", "description": {{jsonString (columnLabel $pk.ColumnComment)}}, "required": true, {{swaggerType $pk.JavaType}}}
+        ],
+        "security": [{"Authorization": []}],
+        "responses": {"200": {"description": "查询成功", "schema": {"$ref": "#/definitions/AjaxResult"}}}
+      }
+    },
+    "{{$base}}": {
+      "post": {
+        "tags": [{{$tag}}],
+        "summary": {{jsonString (printf "新增%s" .FunctionName)}},
+        "operationId": "add{{.ClassName}}",
+        "consumes": ["application/json"],
+        "produces": ["application/json"],
+        "parameters": [
+          {"name": "body", "in": "body", "required": true, "schema": {"$ref": "#/definitions/{{.ClassName}}"}}
+        ],
+        "security": [{"Authorization": []}],
+        "responses": {"200": {"description": "新增成功", "schema": {"$ref": "#/definitions/AjaxResult"}}}
+      },
+      "put": {
+        "tags": [{{$tag}}],
+        "summary": {{jsonString (printf "修改%s" .FunctionName)}},
+        "operationId": "edit{{.ClassName}}",
+        "consumes": ["application/json"],
+        "produces": ["application/json"],
+        "parameters": [
+          {"name": "body", "in": "body", "required": true, "schema": {"$ref": "#/definitions/{{.ClassName}}"}}
+        ],
+        "security": [{"Authorization": []}],
+        "responses": {"200": {"description": "修改成功", "schema": {"$ref": "#/definitions/AjaxResult"}}}
+      }
+    },
+    "{{$base}}/{{"{"}}{{$pk.JavaField}}s{{"}"}}": {
+      "delete": {
+        "tags": [{{$tag}}],
+        "summary": {{jsonString (printf "删除%s" .FunctionName)}},
+        "operationId": "remove{{.ClassName}}",
+        "produces": ["application/json"],
+        "parameters": [
+          {"name": "{{$pk.JavaField}}s", "in": "path", "description": "多个ID用逗号分隔", "required": true, "type": "string"}
+        ],
+        "security": [{"Authorization": []}],
+        "responses": {"200": {"description": "删除成功", "schema": {"$ref": "#/definitions/AjaxResult"}}}
+      }
+    }
+  },
+  "definitions": {
+    "{{.ClassName}}": {
+      "type": "object",
+      "description": {{jsonString .FunctionName}},
+      "properties": {
+{{- range $i, $column := .Columns}}{{if $i}},{{end}}
+        "{{$column.JavaField}}": { {{- swaggerType $column.JavaType}}, "description": {{jsonString (columnLabel $column.ColumnComment)}}}
+{{- end}}
+{{- if .SubClassName}},
+        "{{uncapitalize .SubClassName}}List": {"type": "array", "items": {"$ref": "#/definitions/{{.SubClassName}}"}}
+{{- end}}
+      }
+    }
+{{- if .SubClassName}},
+    "{{.SubClassName}}": {
+      "type": "object",
+      "description": {{jsonString .SubTable.FunctionName}},
+      "properties": {
+{{- range $i, $column := .SubTable.Columns}}{{if $i}},{{end}}
+        "{{$column.JavaField}}": { {{- swaggerType $column.JavaType}}, "description": {{jsonString (columnLabel $column.ColumnComment)}}}
+{{- end}}
+      }
+    }
+{{- end}}
+  }
+}
+`
+}
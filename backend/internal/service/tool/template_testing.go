@@ -0,0 +1,431 @@
+package tool
+
+import "wosm/internal/repository/model"
+
+// sampleValue 获取测试数据使用的Go字面量，指针类型（日期、小数）返回空字符串表示不赋值
+func sampleValue(javaType string) string {
+	switch getGoType(javaType) {
+	case "string":
+		return `"test"`
+	case "int", "int64":
+		return "1"
+	case "float64":
+		return "1.5"
+	case "bool":
+		return "true"
+	default:
+		return ""
+	}
+}
+
+// sampleId 根据序号构造测试数据主键的Go表达式，变量名为 seq（int）
+func sampleId(javaType string) string {
+	switch getGoType(javaType) {
+	case "string":
+		return "fmt.Sprint(seq)"
+	case "int64":
+		return "int64(seq)"
+	case "float64":
+		return "float64(seq)"
+	default:
+		return "seq"
+	}
+}
+
+// isSampleField 判断字段是否需要填充测试数据：主键和树编码由序号或自增生成，树表的上级和祖级列表由测试构造
+func isSampleField(column model.GenTableColumn, ctx *TemplateContext) bool {
+	if column.IsPrimaryKey() || sampleValue(column.JavaType) == "" {
+		return false
+	}
+	for _, tree := range []*model.GenTableColumn{ctx.TreeCode, ctx.TreeParentCode, ctx.AncestorsColumn} {
+		if tree != nil && column.ColumnName == tree.ColumnName {
+			return false
+		}
+	}
+	return true
+}
+
+// getDaoMemoryTemplate 获取内存数据访问层模板，实现与数据库版本相同的方法，供生成的单元测试使用
+func (e *TemplateEngine) getDaoMemoryTemplate() string {
+	return `package dao
+{{- $pkType := getGoType .PkColumn.JavaType}}
+{{- $pk := capitalize .PkColumn.JavaField}}
+
+import (
+{{- if or (not .PkColumn.IsAutoIncrement) .AncestorsColumn}}
+	"fmt"
+{{- end}}
+{{- if .AncestorsColumn}}
+	"strings"
+{{- end}}
+	"sync"
+	"wosm/internal/repository/model"
+)
+
+// Memory{{.ClassName}}Dao {{.FunctionName}}的内存数据访问层，用于单元测试替换数据库
+type Memory{{.ClassName}}Dao struct {
+	mu    sync.Mutex
+	items []model.{{.ClassName}}
+{{- if .PkColumn.IsAutoIncrement}}
+	lastId {{$pkType}}
+{{- end}}
+}
+
+// NewMemory{{.ClassName}}Dao 创建{{.FunctionName}}内存数据访问层实例
+func NewMemory{{.ClassName}}Dao() *Memory{{.ClassName}}Dao {
+	return &Memory{{.ClassName}}Dao{}
+}
+
+// Select{{.ClassName}}List 查询{{.FunctionName}}列表（返回全部数据）
+func (d *Memory{{.ClassName}}Dao) Select{{.ClassName}}List({{uncapitalize .ClassName}} *model.{{.ClassName}}) ([]model.{{.ClassName}}, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	list := make([]model.{{.ClassName}}, len(d.items))
+	copy(list, d.items)
+	return list, nil
+}
+
+// Select{{.ClassName}}ById 根据ID查询{{.FunctionName}}
+func (d *Memory{{.ClassName}}Dao) Select{{.ClassName}}ById({{.PkColumn.JavaField}} {{$pkType}}) (*model.{{.ClassName}}, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, item := range d.items {
+		if item.{{$pk}} == {{.PkColumn.JavaField}} {
+			return &item, nil
+		}
+	}
+	return nil, nil
+}
+
+// Insert{{.ClassName}} 新增{{.FunctionName}}
+func (d *Memory{{.ClassName}}Dao) Insert{{.ClassName}}({{uncapitalize .ClassName}} *model.{{.ClassName}}) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+{{- if .PkColumn.IsAutoIncrement}}
+	d.lastId++
+	{{uncapitalize .ClassName}}.{{$pk}} = d.lastId
+{{- else}}
+	for _, item := range d.items {
+		if item.{{$pk}} == {{uncapitalize .ClassName}}.{{$pk}} {
+			return fmt.Errorf("{{.FunctionName}}ID重复: %v", item.{{$pk}})
+		}
+	}
+{{- end}}
+{{- if .SubClassName}}
+	for i := range {{uncapitalize .ClassName}}.{{.SubClassName}}List {
+		{{uncapitalize .ClassName}}.{{.SubClassName}}List[i].{{capitalize .SubTableFkColumn.JavaField}} = {{uncapitalize .ClassName}}.{{$pk}}
+	}
+{{- end}}
+	d.items = append(d.items, *{{uncapitalize .ClassName}})
+	return nil
+}
+
+// Update{{.ClassName}} 修改{{.FunctionName}}（整条替换）
+func (d *Memory{{.ClassName}}Dao) Update{{.ClassName}}({{uncapitalize .ClassName}} *model.{{.ClassName}}) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for i := range d.items {
+		if d.items[i].{{$pk}} == {{uncapitalize .ClassName}}.{{$pk}} {
+{{- if .SubClassName}}
+			for j := range {{uncapitalize .ClassName}}.{{.SubClassName}}List {
+				{{uncapitalize .ClassName}}.{{.SubClassName}}List[j].{{capitalize .SubTableFkColumn.JavaField}} = {{uncapitalize .ClassName}}.{{$pk}}
+			}
+{{- end}}
+			d.items[i] = *{{uncapitalize .ClassName}}
+		}
+	}
+	return nil
+}
+
+// Delete{{.ClassName}}ByIds 批量删除{{.FunctionName}}
+func (d *Memory{{.ClassName}}Dao) Delete{{.ClassName}}ByIds(ids []{{$pkType}}) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	removed := make(map[{{$pkType}}]bool, len(ids))
+	for _, id := range ids {
+		removed[id] = true
+	}
+	items := d.items[:0]
+	for _, item := range d.items {
+		if !removed[item.{{$pk}}] {
+			items = append(items, item)
+		}
+	}
+	d.items = items
+	return nil
+}
+{{- if .Table.IsTree}}
+{{- $codeType := getGoType .TreeCode.JavaType}}
+{{- $code := capitalize .TreeCode.JavaField}}
+{{- $parent := capitalize .TreeParentCode.JavaField}}
+
+// Select{{.ClassName}}ChildrenCount 查询直接下级的数量
+func (d *Memory{{.ClassName}}Dao) Select{{.ClassName}}ChildrenCount(code {{$codeType}}) (int64, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var count int64
+	for _, item := range d.items {
+		if item.{{$parent}} == code {
+			count++
+		}
+	}
+	return count, nil
+}
+{{- if .AncestorsColumn}}
+{{- $ancestors := capitalize .AncestorsColumn.JavaField}}
+
+// Select{{.ClassName}}ByTreeCode 根据树编码查询{{.FunctionName}}
+func (d *Memory{{.ClassName}}Dao) Select{{.ClassName}}ByTreeCode(code {{$codeType}}) (*model.{{.ClassName}}, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, item := range d.items {
+		if item.{{$code}} == code {
+			return &item, nil
+		}
+	}
+	return nil, nil
+}
+
+// Select{{.ClassName}}Descendants 根据祖级列表查询所有下级
+func (d *Memory{{.ClassName}}Dao) Select{{.ClassName}}Descendants(code {{$codeType}}) ([]model.{{.ClassName}}, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var list []model.{{.ClassName}}
+	id := "," + fmt.Sprint(code) + ","
+	for _, item := range d.items {
+		if strings.Contains(item.{{$ancestors}}+",", id) {
+			list = append(list, item)
+		}
+	}
+	return list, nil
+}
+
+// Update{{.ClassName}}Ancestors 批量修改祖级列表
+func (d *Memory{{.ClassName}}Dao) Update{{.ClassName}}Ancestors(list []model.{{.ClassName}}) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, changed := range list {
+		for i := range d.items {
+			if d.items[i].{{$pk}} == changed.{{$pk}} {
+				d.items[i].{{$ancestors}} = changed.{{$ancestors}}
+			}
+		}
+	}
+	return nil
+}
+{{- end}}
+{{- end}}`
+}
+
+// getServiceTestTemplate 获取服务单元测试模板，使用内存数据访问层按步骤验证增删改查
+func (e *TemplateEngine) getServiceTestTemplate() string {
+	return `package {{.ModuleName}}
+{{- $pkType := getGoType .PkColumn.JavaType}}
+{{- $pk := capitalize .PkColumn.JavaField}}
+{{- $ctx := .}}
+
+import (
+{{- if or .AncestorsColumn (and (not .PkColumn.IsAutoIncrement) (eq $pkType "string")) (and .Table.IsTree (eq (getGoType .TreeCode.JavaType) "string"))}}
+	"fmt"
+{{- end}}
+	"testing"
+	"wosm/internal/repository/dao"
+	"wosm/internal/repository/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTest{{.ClassName}} 构造测试用的{{.FunctionName}}
+func newTest{{.ClassName}}(seq int) *model.{{.ClassName}} {
+	return &model.{{.ClassName}}{
+{{- if not .PkColumn.IsAutoIncrement}}
+		{{$pk}}: {{sampleId .PkColumn.JavaType}},
+{{- end}}
+{{- if and .Table.IsTree (ne .TreeCode.ColumnName .PkColumn.ColumnName)}}
+		{{capitalize .TreeCode.JavaField}}: {{sampleId .TreeCode.JavaType}},
+{{- end}}
+{{- range .Columns}}
+{{- if isSampleField . $ctx}}
+		{{capitalize .JavaField}}: {{sampleValue .JavaType}},
+{{- end}}
+{{- end}}
+	}
+}
+
+func Test{{.ClassName}}Service(t *testing.T) {
+	service := New{{.ClassName}}ServiceWithDao(dao.NewMemory{{.ClassName}}Dao())
+	first := newTest{{.ClassName}}(1)
+	second := newTest{{.ClassName}}(2)
+{{- if .Table.IsTree}}
+	child := newTest{{.ClassName}}(3)
+{{- end}}
+{{- if .SubClassName}}
+	first.{{.SubClassName}}List = []model.{{.SubClassName}}{ {} }
+{{- end}}
+
+	tests := []struct {
+		name      string
+		action    func() error
+		wantErr   bool
+		wantTotal int
+	}{
+		{"新增", func() error { return service.Insert{{.ClassName}}(first) }, false, 1},
+		{"再次新增", func() error { return service.Insert{{.ClassName}}(second) }, false, 2},
+		{"修改", func() error { return service.Update{{.ClassName}}(first) }, false, 2},
+{{- if .Table.IsTree}}
+		{"上级不能是自己", func() error {
+			invalid := *first
+			invalid.{{capitalize .TreeParentCode.JavaField}} = invalid.{{capitalize .TreeCode.JavaField}}
+			return service.Update{{.ClassName}}(&invalid)
+		}, true, 2},
+		{"新增下级", func() error {
+			child.{{capitalize .TreeParentCode.JavaField}} = first.{{capitalize .TreeCode.JavaField}}
+			return service.Insert{{.ClassName}}(child)
+		}, false, 3},
+		{"存在下级时不允许删除", func() error { return service.Delete{{.ClassName}}ByIds([]{{$pkType}}{first.{{$pk}}}) }, true, 3},
+		{"删除", func() error { return service.Delete{{.ClassName}}ByIds([]{{$pkType}}{second.{{$pk}}}) }, false, 2},
+{{- else}}
+		{"删除", func() error { return service.Delete{{.ClassName}}ByIds([]{{$pkType}}{second.{{$pk}}}) }, false, 1},
+{{- end}}
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.action()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			list, err := service.Select{{.ClassName}}List(&model.{{.ClassName}}{})
+			require.NoError(t, err)
+			assert.Len(t, list, tt.wantTotal)
+		})
+	}
+
+	got, err := service.Select{{.ClassName}}ById(first.{{$pk}})
+	require.NoError(t, err)
+	require.NotNil(t, got)
+{{- if .SubClassName}}
+	assert.Len(t, got.{{.SubClassName}}List, 1)
+{{- end}}
+{{- if .AncestorsColumn}}
+
+	got, err = service.Select{{.ClassName}}ById(child.{{$pk}})
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, first.{{capitalize .AncestorsColumn.JavaField}}+","+fmt.Sprint(first.{{capitalize .TreeCode.JavaField}}), got.{{capitalize .AncestorsColumn.JavaField}})
+{{- end}}
+
+	got, err = service.Select{{.ClassName}}ById(second.{{$pk}})
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}`
+}
+
+// getControllerTestTemplate 获取控制器测试模板，通过HTTP请求验证接口（不经过权限校验）
+func (e *TemplateEngine) getControllerTestTemplate() string {
+	return `package {{.ModuleName}}
+{{- $pk := capitalize .PkColumn.JavaField}}
+{{- $ctx := .}}
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"wosm/internal/repository/dao"
+	"wosm/internal/repository/model"
+	{{.ModuleName}}Service "wosm/internal/service/{{.ModuleName}}"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// new{{.ClassName}}TestRouter 使用内存数据访问层创建{{.FunctionName}}测试路由
+func new{{.ClassName}}TestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	c := New{{.ClassName}}ControllerWithService({{.ModuleName}}Service.New{{.ClassName}}ServiceWithDao(dao.NewMemory{{.ClassName}}Dao()))
+
+	router := gin.New()
+	group := router.Group("/{{.ModuleName}}/{{.BusinessName}}")
+	group.GET("/list", c.List)
+{{- if .Table.IsTree}}
+	group.GET("/treeList", c.TreeList)
+{{- end}}
+	group.GET("/:{{.PkColumn.JavaField}}", c.GetInfo)
+	group.POST("", c.Add)
+	group.PUT("", c.Edit)
+	group.DELETE("/:{{.PkColumn.JavaField}}s", c.Remove)
+	return router
+}
+
+func Test{{.ClassName}}Controller(t *testing.T) {
+	router := new{{.ClassName}}TestRouter()
+	item := model.{{.ClassName}}{
+		{{$pk}}: {{if eq (getGoType .PkColumn.JavaType) "string"}}"1"{{else}}1{{end}},
+{{- if and .Table.IsTree (ne .TreeCode.ColumnName .PkColumn.ColumnName)}}
+		{{capitalize .TreeCode.JavaField}}: {{if eq (getGoType .TreeCode.JavaType) "string"}}"1"{{else}}1{{end}},
+{{- end}}
+{{- range .Columns}}
+{{- if isSampleField . $ctx}}
+		{{capitalize .JavaField}}: {{sampleValue .JavaType}},
+{{- end}}
+{{- end}}
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     any
+		wantCode int
+	}{
+		{"新增", http.MethodPost, "/{{.ModuleName}}/{{.BusinessName}}", item, 200},
+		{"参数错误", http.MethodPost, "/{{.ModuleName}}/{{.BusinessName}}", "invalid", 500},
+		{"查询列表", http.MethodGet, "/{{.ModuleName}}/{{.BusinessName}}/list", nil, 200},
+{{- if .Table.IsTree}}
+		{"查询树结构", http.MethodGet, "/{{.ModuleName}}/{{.BusinessName}}/treeList", nil, 200},
+{{- end}}
+		{"查询详细", http.MethodGet, "/{{.ModuleName}}/{{.BusinessName}}/1", nil, 200},
+		{"查询不存在", http.MethodGet, "/{{.ModuleName}}/{{.BusinessName}}/999", nil, 500},
+		{"修改", http.MethodPut, "/{{.ModuleName}}/{{.BusinessName}}", item, 200},
+		{"删除", http.MethodDelete, "/{{.ModuleName}}/{{.BusinessName}}/1", nil, 200},
+		{"删除后查询", http.MethodGet, "/{{.ModuleName}}/{{.BusinessName}}/1", nil, 500},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body io.Reader
+			if tt.body != nil {
+				data, err := json.Marshal(tt.body)
+				require.NoError(t, err)
+				body = bytes.NewReader(data)
+			}
+			req := httptest.NewRequest(tt.method, tt.path, body)
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusOK, w.Code)
+			var result struct {
+				Code int ` + "`" + `json:"code"` + "`" + `
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+			assert.Equal(t, tt.wantCode, result.Code, w.Body.String())
+		})
+	}
+}`
+}
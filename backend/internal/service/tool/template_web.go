@@ -0,0 +1,1371 @@
+package tool
+
+// 前端模板使用 [[ ]] 作为分隔符，避免与vue的 {{ }} 插值语法冲突
+
+// getIndexPlusVueTemplate 获取Vue3 Element Plus页面模板（TypeScript共用） 对应Java后端的vm/vue/v3/index.vue.vm
+func (e *TemplateEngine) getIndexPlusVueTemplate() string {
+	return `[[- $ts := .TypeScript]]
+[[- $pk := .PkColumn.JavaField]]
+[[- $api := .BusinessClass]]
+[[- $list := printf "%sList" .BusinessName]]
+[[- $ref := printf "%sRef" .BusinessName]]
+[[- $isTree := .Table.IsTree]]
+[[- $treeCode := ""]][[$treeParent := ""]][[$treeName := ""]][[$treeParentColumn := ""]][[$rootValue := "0"]]
+[[- if $isTree]]
+[[- $treeCode = .TreeCode.JavaField]][[$treeParent = .TreeParentCode.JavaField]][[$treeName = .TreeName.JavaField]][[$treeParentColumn = .TreeParentCode.ColumnName]]
+[[- if ne (tsType .TreeParentCode.JavaType) "number"]][[$rootValue = "\"0\""]][[end]]
+[[- end]]
+[[- $subList := ""]][[$sub := ""]]
+[[- if .SubClassName]][[$sub = .SubClassName]][[$subList = printf "%sList" (uncapitalize .SubClassName)]][[end]]
+<template>
+  <div class="app-container">
+    <el-form :model="queryParams" ref="queryRef" :inline="true" v-show="showSearch" label-width="68px">
+[[- range .QueryColumns]]
+[[- $label := columnLabel .ColumnComment]]
+[[- if and (eq .HtmlType "datetime") (eq .QueryType "BETWEEN")]]
+      <el-form-item label="[[$label]]" style="width: 308px">
+        <el-date-picker
+          v-model="daterange[[capitalize .JavaField]]"
+          value-format="YYYY-MM-DD"
+          type="daterange"
+          range-separator="-"
+          start-placeholder="开始日期"
+          end-placeholder="结束日期"
+        ></el-date-picker>
+      </el-form-item>
+[[- else if eq .HtmlType "datetime"]]
+      <el-form-item label="[[$label]]" prop="[[.JavaField]]">
+        <el-date-picker
+          clearable
+          v-model="queryParams.[[.JavaField]]"
+          type="date"
+          value-format="YYYY-MM-DD"
+          placeholder="请选择[[$label]]"
+        ></el-date-picker>
+      </el-form-item>
+[[- else if isDictHtml .HtmlType]]
+      <el-form-item label="[[$label]]" prop="[[.JavaField]]">
+        <el-select v-model="queryParams.[[.JavaField]]" placeholder="请选择[[$label]]" clearable style="width: 200px">
+[[- if .DictType]]
+          <el-option
+            v-for="dict in [[.DictType]]"
+            :key="dict.value"
+            :label="dict.label"
+            :value="dict.value"
+          />
+[[- else]]
+          <el-option label="请选择字典生成" value="" />
+[[- end]]
+        </el-select>
+      </el-form-item>
+[[- else]]
+      <el-form-item label="[[$label]]" prop="[[.JavaField]]">
+        <el-input
+          v-model="queryParams.[[.JavaField]]"
+          placeholder="请输入[[$label]]"
+          clearable
+          style="width: 200px"
+          @keyup.enter="handleQuery"
+        />
+      </el-form-item>
+[[- end]]
+[[- end]]
+      <el-form-item>
+        <el-button type="primary" icon="Search" @click="handleQuery">搜索</el-button>
+        <el-button icon="Refresh" @click="resetQuery">重置</el-button>
+      </el-form-item>
+    </el-form>
+
+    <el-row :gutter="10" class="mb8">
+      <el-col :span="1.5">
+        <el-button
+          type="primary"
+          plain
+          icon="Plus"
+          @click="handleAdd"
+          v-hasPermi="['[[.PermissionPrefix]]:add']"
+        >新增</el-button>
+      </el-col>
+[[- if $isTree]]
+      <el-col :span="1.5">
+        <el-button
+          type="info"
+          plain
+          icon="Sort"
+          @click="toggleExpandAll"
+        >展开/折叠</el-button>
+      </el-col>
+[[- else]]
+      <el-col :span="1.5">
+        <el-button
+          type="success"
+          plain
+          icon="Edit"
+          :disabled="single"
+          @click="handleUpdate"
+          v-hasPermi="['[[.PermissionPrefix]]:edit']"
+        >修改</el-button>
+      </el-col>
+      <el-col :span="1.5">
+        <el-button
+          type="danger"
+          plain
+          icon="Delete"
+          :disabled="multiple"
+          @click="handleDelete"
+          v-hasPermi="['[[.PermissionPrefix]]:remove']"
+        >删除</el-button>
+      </el-col>
+[[- end]]
+      <right-toolbar v-model:showSearch="showSearch" @queryTable="getList"></right-toolbar>
+    </el-row>
+
+[[- if $isTree]]
+
+    <el-table
+      v-if="refreshTable"
+      v-loading="loading"
+      :data="[[$list]]"
+      row-key="[[$treeCode]]"
+      :default-expand-all="isExpandAll"
+      :tree-props="{ children: 'children', hasChildren: 'hasChildren' }"
+    >
+[[- else]]
+
+    <el-table v-loading="loading" :data="[[$list]]" @selection-change="handleSelectionChange">
+      <el-table-column type="selection" width="55" align="center" />
+[[- end]]
+[[- range .ListColumns]]
+[[- $label := columnLabel .ColumnComment]]
+[[- if eq .HtmlType "datetime"]]
+      <el-table-column label="[[$label]]" align="center" prop="[[.JavaField]]" width="180">
+        <template #default="scope">
+          <span>{{ parseTime(scope.row.[[.JavaField]], '{y}-{m}-{d}') }}</span>
+        </template>
+      </el-table-column>
+[[- else if eq .HtmlType "imageUpload"]]
+      <el-table-column label="[[$label]]" align="center" prop="[[.JavaField]]" width="100">
+        <template #default="scope">
+          <image-preview :src="scope.row.[[.JavaField]]" :width="50" :height="50" />
+        </template>
+      </el-table-column>
+[[- else if and (isDictHtml .HtmlType) .DictType]]
+      <el-table-column label="[[$label]]" align="center" prop="[[.JavaField]]">
+        <template #default="scope">
+          <dict-tag :options="[[.DictType]]" :value="scope.row.[[.JavaField]]" />
+        </template>
+      </el-table-column>
+[[- else]]
+      <el-table-column label="[[$label]]" align="center" prop="[[.JavaField]]" />
+[[- end]]
+[[- end]]
+      <el-table-column label="操作" align="center" class-name="small-padding fixed-width">
+        <template #default="scope">
+          <el-button link type="primary" icon="Edit" @click="handleUpdate(scope.row)" v-hasPermi="['[[.PermissionPrefix]]:edit']">修改</el-button>
+[[- if $isTree]]
+          <el-button link type="primary" icon="Plus" @click="handleAdd(scope.row)" v-hasPermi="['[[.PermissionPrefix]]:add']">新增</el-button>
+[[- end]]
+          <el-button link type="primary" icon="Delete" @click="handleDelete(scope.row)" v-hasPermi="['[[.PermissionPrefix]]:remove']">删除</el-button>
+        </template>
+      </el-table-column>
+    </el-table>
+[[- if not $isTree]]
+
+    <pagination
+      v-show="total > 0"
+      :total="total"
+      v-model:page="queryParams.pageNum"
+      v-model:limit="queryParams.pageSize"
+      @pagination="getList"
+    />
+[[- end]]
+
+    <!-- 添加或修改[[.FunctionName]]对话框 -->
+    <el-dialog :title="title" v-model="open" width="[[if $sub]]800px[[else]]500px[[end]]" append-to-body>
+      <el-form ref="[[$ref]]" :model="form" :rules="rules" label-width="80px">
+[[- range .FormColumns]]
+[[- $label := columnLabel .ColumnComment]]
+[[- $number := eq (tsType .JavaType) "number"]]
+[[- if and $isTree (eq .ColumnName $treeParentColumn)]]
+        <el-form-item label="[[$label]]" prop="[[.JavaField]]">
+          <el-tree-select
+            v-model="form.[[.JavaField]]"
+            :data="[[$.BusinessName]]Options"
+            :props="{ value: '[[$treeCode]]', label: '[[$treeName]]', children: 'children' }"
+            value-key="[[$treeCode]]"
+            placeholder="请选择[[$label]]"
+            check-strictly
+          />
+        </el-form-item>
+[[- else if eq .HtmlType "textarea"]]
+        <el-form-item label="[[$label]]" prop="[[.JavaField]]">
+          <el-input v-model="form.[[.JavaField]]" type="textarea" placeholder="请输入内容" />
+        </el-form-item>
+[[- else if eq .HtmlType "select"]]
+        <el-form-item label="[[$label]]" prop="[[.JavaField]]">
+          <el-select v-model="form.[[.JavaField]]" placeholder="请选择[[$label]]">
+[[- if .DictType]]
+            <el-option
+              v-for="dict in [[.DictType]]"
+              :key="dict.value"
+              :label="dict.label"
+              :value="[[if $number]]parseInt(dict.value)[[else]]dict.value[[end]]"
+            ></el-option>
+[[- else]]
+            <el-option label="请选择字典生成" value="" />
+[[- end]]
+          </el-select>
+        </el-form-item>
+[[- else if eq .HtmlType "radio"]]
+        <el-form-item label="[[$label]]" prop="[[.JavaField]]">
+          <el-radio-group v-model="form.[[.JavaField]]">
+[[- if .DictType]]
+            <el-radio
+              v-for="dict in [[.DictType]]"
+              :key="dict.value"
+              :value="[[if $number]]parseInt(dict.value)[[else]]dict.value[[end]]"
+            >{{ dict.label }}</el-radio>
+[[- else]]
+            <el-radio>请选择字典生成</el-radio>
+[[- end]]
+          </el-radio-group>
+        </el-form-item>
+[[- else if eq .HtmlType "checkbox"]]
+        <el-form-item label="[[$label]]" prop="[[.JavaField]]">
+          <el-checkbox-group v-model="form.[[.JavaField]]">
+[[- if .DictType]]
+            <el-checkbox
+              v-for="dict in [[.DictType]]"
+              :key="dict.value"
+              :value="dict.value"
+            >{{ dict.label }}</el-checkbox>
+[[- else]]
+            <el-checkbox>请选择字典生成</el-checkbox>
+[[- end]]
+          </el-checkbox-group>
+        </el-form-item>
+[[- else if eq .HtmlType "datetime"]]
+        <el-form-item label="[[$label]]" prop="[[.JavaField]]">
+          <el-date-picker
+            clearable
+            v-model="form.[[.JavaField]]"
+            type="date"
+            value-format="YYYY-MM-DD"
+            placeholder="请选择[[$label]]"
+          ></el-date-picker>
+        </el-form-item>
+[[- else if eq .HtmlType "imageUpload"]]
+        <el-form-item label="[[$label]]" prop="[[.JavaField]]">
+          <image-upload v-model="form.[[.JavaField]]" />
+        </el-form-item>
+[[- else if eq .HtmlType "fileUpload"]]
+        <el-form-item label="[[$label]]" prop="[[.JavaField]]">
+          <file-upload v-model="form.[[.JavaField]]" />
+        </el-form-item>
+[[- else if eq .HtmlType "editor"]]
+        <el-form-item label="[[$label]]">
+          <editor v-model="form.[[.JavaField]]" :min-height="192" />
+        </el-form-item>
+[[- else]]
+        <el-form-item label="[[$label]]" prop="[[.JavaField]]">
+          <el-input v-model="form.[[.JavaField]]" placeholder="请输入[[$label]]" />
+        </el-form-item>
+[[- end]]
+[[- end]]
+[[- if $sub]]
+        <el-divider content-position="center">[[.SubTable.FunctionName]]信息</el-divider>
+        <el-row :gutter="10" class="mb8">
+          <el-col :span="1.5">
+            <el-button type="primary" icon="Plus" @click="handleAdd[[$sub]]">添加</el-button>
+          </el-col>
+          <el-col :span="1.5">
+            <el-button type="danger" icon="Delete" @click="handleDelete[[$sub]]">删除</el-button>
+          </el-col>
+        </el-row>
+        <el-table :data="[[$subList]]" @selection-change="handle[[$sub]]SelectionChange">
+          <el-table-column type="selection" width="50" align="center" />
+          <el-table-column label="序号" align="center" type="index" width="50" />
+[[- range .SubFormColumns]]
+[[- $label := columnLabel .ColumnComment]]
+          <el-table-column label="[[$label]]" prop="[[.JavaField]]" width="150">
+            <template #default="scope">
+[[- if eq .HtmlType "datetime"]]
+              <el-date-picker
+                clearable
+                v-model="scope.row.[[.JavaField]]"
+                type="date"
+                value-format="YYYY-MM-DD"
+                placeholder="请选择[[$label]]"
+              ></el-date-picker>
+[[- else if and (isDictHtml .HtmlType) .DictType]]
+              <el-select v-model="scope.row.[[.JavaField]]" placeholder="请选择[[$label]]">
+                <el-option
+                  v-for="dict in [[.DictType]]"
+                  :key="dict.value"
+                  :label="dict.label"
+                  :value="dict.value"
+                ></el-option>
+              </el-select>
+[[- else]]
+              <el-input v-model="scope.row.[[.JavaField]]" placeholder="请输入[[$label]]" />
+[[- end]]
+            </template>
+          </el-table-column>
+[[- end]]
+        </el-table>
+[[- end]]
+      </el-form>
+      <template #footer>
+        <div class="dialog-footer">
+          <el-button type="primary" @click="submitForm">确 定</el-button>
+          <el-button @click="cancel">取 消</el-button>
+        </div>
+      </template>
+    </el-dialog>
+  </div>
+</template>
+
+<script setup[[if $ts]] lang="ts"[[end]] name="[[.ClassName]]">
+import { list[[$api]], get[[$api]], del[[$api]], add[[$api]], update[[$api]] } from "@/api/[[.ModuleName]]/[[.BusinessName]]"
+[[- if $ts]]
+import type { [[.ClassName]], [[.ClassName]]Query[[if $sub]], [[$sub]][[end]] } from "@/types/[[.ModuleName]]/[[.BusinessName]]"
+[[- end]]
+
+const { proxy } = getCurrentInstance()[[if $ts]] as any[[end]]
+[[- if .DictTypes]]
+const { [[join .DictTypes ", "]] } = proxy.useDict([[range $i, $dict := .DictTypes]][[if $i]], [[end]]"[[$dict]]"[[end]])
+[[- end]]
+
+const [[$list]] = ref[[if $ts]]<[[.ClassName]][]>[[end]]([])
+[[- if $sub]]
+const [[$subList]] = ref[[if $ts]]<[[$sub]][]>[[end]]([])
+const checked[[$sub]] = ref[[if $ts]]<[[$sub]][]>[[end]]([])
+[[- end]]
+[[- if $isTree]]
+const [[.BusinessName]]Options = ref[[if $ts]]<any[]>[[end]]([])
+[[- end]]
+const open = ref(false)
+const loading = ref(true)
+const showSearch = ref(true)
+[[- if $isTree]]
+const isExpandAll = ref(true)
+const refreshTable = ref(true)
+[[- else]]
+const ids = ref[[if $ts]]<[[tsType .PkColumn.JavaType]][]>[[end]]([])
+const single = ref(true)
+const multiple = ref(true)
+const total = ref(0)
+[[- end]]
+const title = ref("")
+[[- range .QueryColumns]]
+[[- if and (eq .HtmlType "datetime") (eq .QueryType "BETWEEN")]]
+const daterange[[capitalize .JavaField]] = ref[[if $ts]]<string[]>[[end]]([])
+[[- end]]
+[[- end]]
+
+const data = reactive({
+  form: {}[[if $ts]] as [[.ClassName]][[end]],
+  queryParams: {
+[[- if not $isTree]]
+    pageNum: 1,
+    pageSize: 10,
+[[- end]]
+[[- range .QueryColumns]]
+[[- if not (and (eq .HtmlType "datetime") (eq .QueryType "BETWEEN"))]]
+    [[.JavaField]]: undefined,
+[[- end]]
+[[- end]]
+  }[[if $ts]] as [[.ClassName]]Query[[end]],
+  rules: {
+[[- range .FormColumns]]
+[[- if .IsRequiredField]]
+    [[.JavaField]]: [
+      { required: true, message: "[[columnLabel .ColumnComment]]不能为空", trigger: "[[if or (isDictHtml .HtmlType) (eq .HtmlType "datetime")]]change[[else]]blur[[end]]" }
+    ],
+[[- end]]
+[[- end]]
+  }
+})
+
+const { queryParams, form, rules } = toRefs(data)
+
+/** 查询[[.FunctionName]]列表 */
+function getList() {
+  loading.value = true
+[[- $hasRange := false]]
+[[- range .QueryColumns]][[if and (eq .HtmlType "datetime") (eq .QueryType "BETWEEN")]][[$hasRange = true]][[end]][[end]]
+[[- if $hasRange]]
+  queryParams.value.params = {}
+[[- range .QueryColumns]]
+[[- if and (eq .HtmlType "datetime") (eq .QueryType "BETWEEN")]]
+  proxy.addDateRange(queryParams.value, daterange[[capitalize .JavaField]].value, "[[capitalize .JavaField]]")
+[[- end]]
+[[- end]]
+[[- end]]
+  list[[$api]](queryParams.value).then(response => {
+[[- if $isTree]]
+    [[$list]].value = proxy.handleTree(response.rows, "[[$treeCode]]", "[[$treeParent]]")
+[[- else]]
+    [[$list]].value = response.rows
+    total.value = response.total
+[[- end]]
+    loading.value = false
+  })
+}
+[[- if $isTree]]
+
+/** 查询[[.FunctionName]]下拉树结构 */
+function getTreeselect() {
+  list[[$api]]().then(response => {
+    const root[[if $ts]]: any[[end]] = { [[$treeCode]]: [[$rootValue]], [[$treeName]]: "顶级节点", children: [] }
+    root.children = proxy.handleTree(response.rows, "[[$treeCode]]", "[[$treeParent]]")
+    [[.BusinessName]]Options.value = [root]
+  })
+}
+[[- end]]
+
+/** 取消按钮 */
+function cancel() {
+  open.value = false
+  reset()
+}
+
+/** 表单重置 */
+function reset() {
+  form.value = {
+[[- range .Columns]]
+    [[.JavaField]]: [[if eq .HtmlType "checkbox"]][][[else]]undefined[[end]],
+[[- end]]
+  }[[if $ts]] as [[.ClassName]][[end]]
+[[- if $sub]]
+  [[$subList]].value = []
+[[- end]]
+  proxy.resetForm("[[$ref]]")
+}
+
+/** 搜索按钮操作 */
+function handleQuery() {
+[[- if not $isTree]]
+  queryParams.value.pageNum = 1
+[[- end]]
+  getList()
+}
+
+/** 重置按钮操作 */
+function resetQuery() {
+[[- range .QueryColumns]]
+[[- if and (eq .HtmlType "datetime") (eq .QueryType "BETWEEN")]]
+  daterange[[capitalize .JavaField]].value = []
+[[- end]]
+[[- end]]
+  proxy.resetForm("queryRef")
+  handleQuery()
+}
+[[- if not $isTree]]
+
+/** 多选框选中数据 */
+function handleSelectionChange(selection[[if $ts]]: [[.ClassName]][][[end]]) {
+  ids.value = selection.map(item => item.[[$pk]][[if $ts]]![[end]])
+  single.value = selection.length != 1
+  multiple.value = !selection.length
+}
+[[- end]]
+
+/** 新增按钮操作 */
+function handleAdd([[if $isTree]]row[[if $ts]]?: [[.ClassName]][[end]][[end]]) {
+  reset()
+[[- if $isTree]]
+  getTreeselect()
+  if (row != null && row.[[$treeCode]]) {
+    form.value.[[$treeParent]] = row.[[$treeCode]]
+  } else {
+    form.value.[[$treeParent]] = [[$rootValue]]
+  }
+[[- end]]
+  open.value = true
+  title.value = "添加[[.FunctionName]]"
+}
+[[- if $isTree]]
+
+/** 展开/折叠操作 */
+function toggleExpandAll() {
+  refreshTable.value = false
+  isExpandAll.value = !isExpandAll.value
+  nextTick(() => {
+    refreshTable.value = true
+  })
+}
+[[- end]]
+
+/** 修改按钮操作 */
+function handleUpdate(row[[if $ts]]?: [[.ClassName]][[end]]) {
+  reset()
+[[- if $isTree]]
+  getTreeselect()
+[[- end]]
+[[- if $isTree]]
+  const _[[$pk]] = row[[if $ts]]![[end]].[[$pk]]
+[[- else if $ts]]
+  const _[[$pk]] = row?.[[$pk]] || ids.value[0]
+[[- else]]
+  const _[[$pk]] = row.[[$pk]] || ids.value
+[[- end]]
+  get[[$api]](_[[$pk]][[if $ts]]![[end]]).then(response => {
+    form.value = response.data
+[[- range .FormColumns]]
+[[- if eq .HtmlType "checkbox"]]
+    form.value.[[.JavaField]] = response.data.[[.JavaField]] ? response.data.[[.JavaField]].split(",") : []
+[[- end]]
+[[- end]]
+[[- if $sub]]
+    [[$subList]].value = response.data.[[$subList]] || []
+[[- end]]
+    open.value = true
+    title.value = "修改[[.FunctionName]]"
+  })
+}
+
+/** 提交按钮 */
+function submitForm() {
+  proxy.$refs["[[$ref]]"].validate((valid[[if $ts]]: boolean[[end]]) => {
+    if (valid) {
+[[- range .FormColumns]]
+[[- if eq .HtmlType "checkbox"]]
+      form.value.[[.JavaField]] = Array.isArray(form.value.[[.JavaField]]) ? form.value.[[.JavaField]].join(",") : form.value.[[.JavaField]]
+[[- end]]
+[[- end]]
+[[- if $sub]]
+      form.value.[[$subList]] = [[$subList]].value
+[[- end]]
+      if (form.value.[[$pk]] != null) {
+        update[[$api]](form.value).then(() => {
+          proxy.$modal.msgSuccess("修改成功")
+          open.value = false
+          getList()
+        })
+      } else {
+        add[[$api]](form.value).then(() => {
+          proxy.$modal.msgSuccess("新增成功")
+          open.value = false
+          getList()
+        })
+      }
+    }
+  })
+}
+
+/** 删除按钮操作 */
+function handleDelete(row[[if $ts]]?: [[.ClassName]][[end]]) {
+[[- if $isTree]]
+  const _[[$pk]]s = row[[if $ts]]![[end]].[[$pk]]
+[[- else if $ts]]
+  const _[[$pk]]s = row?.[[$pk]] || ids.value
+[[- else]]
+  const _[[$pk]]s = row.[[$pk]] || ids.value
+[[- end]]
+  proxy.$modal.confirm('是否确认删除[[.FunctionName]]编号为"' + _[[$pk]]s + '"的数据项？').then(function() {
+    return del[[$api]](_[[$pk]]s[[if $ts]]![[end]])
+  }).then(() => {
+    getList()
+    proxy.$modal.msgSuccess("删除成功")
+  }).catch(() => {})
+}
+[[- if $sub]]
+
+/** [[.SubTable.FunctionName]]添加按钮操作 */
+function handleAdd[[$sub]]() {
+  [[$subList]].value.push({})
+}
+
+/** [[.SubTable.FunctionName]]删除按钮操作 */
+function handleDelete[[$sub]]() {
+  if (checked[[$sub]].value.length == 0) {
+    proxy.$modal.msgError("请先选择要删除的[[.SubTable.FunctionName]]数据")
+    return
+  }
+  [[$subList]].value = [[$subList]].value.filter(item => !checked[[$sub]].value.includes(item))
+}
+
+/** [[.SubTable.FunctionName]]复选框选中数据 */
+function handle[[$sub]]SelectionChange(selection[[if $ts]]: [[$sub]][][[end]]) {
+  checked[[$sub]].value = selection
+}
+[[- end]]
+
+getList()
+</script>
+`
+}
+
+// getIndexVueTemplate 获取Vue2 Element UI页面模板 对应Java后端的vm/vue/index.vue.vm
+func (e *TemplateEngine) getIndexVueTemplate() string {
+	return `[[- $pk := .PkColumn.JavaField]]
+[[- $api := .BusinessClass]]
+[[- $list := printf "%sList" .BusinessName]]
+[[- $isTree := .Table.IsTree]]
+[[- $treeCode := ""]][[$treeParent := ""]][[$treeName := ""]][[$treeParentColumn := ""]][[$rootValue := "0"]]
+[[- if $isTree]]
+[[- $treeCode = .TreeCode.JavaField]][[$treeParent = .TreeParentCode.JavaField]][[$treeName = .TreeName.JavaField]][[$treeParentColumn = .TreeParentCode.ColumnName]]
+[[- if ne (tsType .TreeParentCode.JavaType) "number"]][[$rootValue = "\"0\""]][[end]]
+[[- end]]
+[[- $subList := ""]][[$sub := ""]]
+[[- if .SubClassName]][[$sub = .SubClassName]][[$subList = printf "%sList" (uncapitalize .SubClassName)]][[end]]
+<template>
+  <div class="app-container">
+    <el-form :model="queryParams" ref="queryForm" size="small" :inline="true" v-show="showSearch" label-width="68px">
+[[- range .QueryColumns]]
+[[- $label := columnLabel .ColumnComment]]
+[[- if and (eq .HtmlType "datetime") (eq .QueryType "BETWEEN")]]
+      <el-form-item label="[[$label]]">
+        <el-date-picker
+          v-model="daterange[[capitalize .JavaField]]"
+          style="width: 240px"
+          value-format="yyyy-MM-dd"
+          type="daterange"
+          range-separator="-"
+          start-placeholder="开始日期"
+          end-placeholder="结束日期"
+        ></el-date-picker>
+      </el-form-item>
+[[- else if eq .HtmlType "datetime"]]
+      <el-form-item label="[[$label]]" prop="[[.JavaField]]">
+        <el-date-picker
+          clearable
+          v-model="queryParams.[[.JavaField]]"
+          type="date"
+          value-format="yyyy-MM-dd"
+          placeholder="请选择[[$label]]"
+        ></el-date-picker>
+      </el-form-item>
+[[- else if isDictHtml .HtmlType]]
+      <el-form-item label="[[$label]]" prop="[[.JavaField]]">
+        <el-select v-model="queryParams.[[.JavaField]]" placeholder="请选择[[$label]]" clearable>
+[[- if .DictType]]
+          <el-option
+            v-for="dict in dict.type.[[.DictType]]"
+            :key="dict.value"
+            :label="dict.label"
+            :value="dict.value"
+          />
+[[- else]]
+          <el-option label="请选择字典生成" value="" />
+[[- end]]
+        </el-select>
+      </el-form-item>
+[[- else]]
+      <el-form-item label="[[$label]]" prop="[[.JavaField]]">
+        <el-input
+          v-model="queryParams.[[.JavaField]]"
+          placeholder="请输入[[$label]]"
+          clearable
+          @keyup.enter.native="handleQuery"
+        />
+      </el-form-item>
+[[- end]]
+[[- end]]
+      <el-form-item>
+        <el-button type="primary" icon="el-icon-search" size="mini" @click="handleQuery">搜索</el-button>
+        <el-button icon="el-icon-refresh" size="mini" @click="resetQuery">重置</el-button>
+      </el-form-item>
+    </el-form>
+
+    <el-row :gutter="10" class="mb8">
+      <el-col :span="1.5">
+        <el-button
+          type="primary"
+          plain
+          icon="el-icon-plus"
+          size="mini"
+          @click="handleAdd"
+          v-hasPermi="['[[.PermissionPrefix]]:add']"
+        >新增</el-button>
+      </el-col>
+[[- if $isTree]]
+      <el-col :span="1.5">
+        <el-button
+          type="info"
+          plain
+          icon="el-icon-sort"
+          size="mini"
+          @click="toggleExpandAll"
+        >展开/折叠</el-button>
+      </el-col>
+[[- else]]
+      <el-col :span="1.5">
+        <el-button
+          type="success"
+          plain
+          icon="el-icon-edit"
+          size="mini"
+          :disabled="single"
+          @click="handleUpdate"
+          v-hasPermi="['[[.PermissionPrefix]]:edit']"
+        >修改</el-button>
+      </el-col>
+      <el-col :span="1.5">
+        <el-button
+          type="danger"
+          plain
+          icon="el-icon-delete"
+          size="mini"
+          :disabled="multiple"
+          @click="handleDelete"
+          v-hasPermi="['[[.PermissionPrefix]]:remove']"
+        >删除</el-button>
+      </el-col>
+[[- end]]
+      <right-toolbar :showSearch.sync="showSearch" @queryTable="getList"></right-toolbar>
+    </el-row>
+
+[[- if $isTree]]
+
+    <el-table
+      v-if="refreshTable"
+      v-loading="loading"
+      :data="[[$list]]"
+      row-key="[[$treeCode]]"
+      :default-expand-all="isExpandAll"
+      :tree-props="{children: 'children', hasChildren: 'hasChildren'}"
+    >
+[[- else]]
+
+    <el-table v-loading="loading" :data="[[$list]]" @selection-change="handleSelectionChange">
+      <el-table-column type="selection" width="55" align="center" />
+[[- end]]
+[[- range .ListColumns]]
+[[- $label := columnLabel .ColumnComment]]
+[[- if eq .HtmlType "datetime"]]
+      <el-table-column label="[[$label]]" align="center" prop="[[.JavaField]]" width="180">
+        <template slot-scope="scope">
+          <span>{{ parseTime(scope.row.[[.JavaField]], '{y}-{m}-{d}') }}</span>
+        </template>
+      </el-table-column>
+[[- else if eq .HtmlType "imageUpload"]]
+      <el-table-column label="[[$label]]" align="center" prop="[[.JavaField]]" width="100">
+        <template slot-scope="scope">
+          <image-preview :src="scope.row.[[.JavaField]]" :width="50" :height="50" />
+        </template>
+      </el-table-column>
+[[- else if and (isDictHtml .HtmlType) .DictType]]
+      <el-table-column label="[[$label]]" align="center" prop="[[.JavaField]]">
+        <template slot-scope="scope">
+          <dict-tag :options="dict.type.[[.DictType]]" :value="scope.row.[[.JavaField]]" />
+        </template>
+      </el-table-column>
+[[- else]]
+      <el-table-column label="[[$label]]" align="center" prop="[[.JavaField]]" />
+[[- end]]
+[[- end]]
+      <el-table-column label="操作" align="center" class-name="small-padding fixed-width">
+        <template slot-scope="scope">
+          <el-button
+            size="mini"
+            type="text"
+            icon="el-icon-edit"
+            @click="handleUpdate(scope.row)"
+            v-hasPermi="['[[.PermissionPrefix]]:edit']"
+          >修改</el-button>
+[[- if $isTree]]
+          <el-button
+            size="mini"
+            type="text"
+            icon="el-icon-plus"
+            @click="handleAdd(scope.row)"
+            v-hasPermi="['[[.PermissionPrefix]]:add']"
+          >新增</el-button>
+[[- end]]
+          <el-button
+            size="mini"
+            type="text"
+            icon="el-icon-delete"
+            @click="handleDelete(scope.row)"
+            v-hasPermi="['[[.PermissionPrefix]]:remove']"
+          >删除</el-button>
+        </template>
+      </el-table-column>
+    </el-table>
+[[- if not $isTree]]
+
+    <pagination
+      v-show="total > 0"
+      :total="total"
+      :page.sync="queryParams.pageNum"
+      :limit.sync="queryParams.pageSize"
+      @pagination="getList"
+    />
+[[- end]]
+
+    <!-- 添加或修改[[.FunctionName]]对话框 -->
+    <el-dialog :title="title" :visible.sync="open" width="[[if $sub]]800px[[else]]500px[[end]]" append-to-body>
+      <el-form ref="form" :model="form" :rules="rules" label-width="80px">
+[[- range .FormColumns]]
+[[- $label := columnLabel .ColumnComment]]
+[[- $number := eq (tsType .JavaType) "number"]]
+[[- if and $isTree (eq .ColumnName $treeParentColumn)]]
+        <el-form-item label="[[$label]]" prop="[[.JavaField]]">
+          <treeselect v-model="form.[[.JavaField]]" :options="[[$.BusinessName]]Options" :normalizer="normalizer" placeholder="请选择[[$label]]" />
+        </el-form-item>
+[[- else if eq .HtmlType "textarea"]]
+        <el-form-item label="[[$label]]" prop="[[.JavaField]]">
+          <el-input v-model="form.[[.JavaField]]" type="textarea" placeholder="请输入内容" />
+        </el-form-item>
+[[- else if eq .HtmlType "select"]]
+        <el-form-item label="[[$label]]" prop="[[.JavaField]]">
+          <el-select v-model="form.[[.JavaField]]" placeholder="请选择[[$label]]">
+[[- if .DictType]]
+            <el-option
+              v-for="dict in dict.type.[[.DictType]]"
+              :key="dict.value"
+              :label="dict.label"
+              :value="[[if $number]]parseInt(dict.value)[[else]]dict.value[[end]]"
+            ></el-option>
+[[- else]]
+            <el-option label="请选择字典生成" value="" />
+[[- end]]
+          </el-select>
+        </el-form-item>
+[[- else if eq .HtmlType "radio"]]
+        <el-form-item label="[[$label]]" prop="[[.JavaField]]">
+          <el-radio-group v-model="form.[[.JavaField]]">
+[[- if .DictType]]
+            <el-radio
+              v-for="dict in dict.type.[[.DictType]]"
+              :key="dict.value"
+              :label="[[if $number]]parseInt(dict.value)[[else]]dict.value[[end]]"
+            >{{ dict.label }}</el-radio>
+[[- else]]
+            <el-radio label="1">请选择字典生成</el-radio>
+[[- end]]
+          </el-radio-group>
+        </el-form-item>
+[[- else if eq .HtmlType "checkbox"]]
+        <el-form-item label="[[$label]]" prop="[[.JavaField]]">
+          <el-checkbox-group v-model="form.[[.JavaField]]">
+[[- if .DictType]]
+            <el-checkbox
+              v-for="dict in dict.type.[[.DictType]]"
+              :key="dict.value"
+              :label="dict.value"
+            >{{ dict.label }}</el-checkbox>
+[[- else]]
+            <el-checkbox>请选择字典生成</el-checkbox>
+[[- end]]
+          </el-checkbox-group>
+        </el-form-item>
+[[- else if eq .HtmlType "datetime"]]
+        <el-form-item label="[[$label]]" prop="[[.JavaField]]">
+          <el-date-picker
+            clearable
+            v-model="form.[[.JavaField]]"
+            type="date"
+            value-format="yyyy-MM-dd"
+            placeholder="请选择[[$label]]"
+          ></el-date-picker>
+        </el-form-item>
+[[- else if eq .HtmlType "imageUpload"]]
+        <el-form-item label="[[$label]]" prop="[[.JavaField]]">
+          <image-upload v-model="form.[[.JavaField]]" />
+        </el-form-item>
+[[- else if eq .HtmlType "fileUpload"]]
+        <el-form-item label="[[$label]]" prop="[[.JavaField]]">
+          <file-upload v-model="form.[[.JavaField]]" />
+        </el-form-item>
+[[- else if eq .HtmlType "editor"]]
+        <el-form-item label="[[$label]]">
+          <editor v-model="form.[[.JavaField]]" :min-height="192" />
+        </el-form-item>
+[[- else]]
+        <el-form-item label="[[$label]]" prop="[[.JavaField]]">
+          <el-input v-model="form.[[.JavaField]]" placeholder="请输入[[$label]]" />
+        </el-form-item>
+[[- end]]
+[[- end]]
+[[- if $sub]]
+        <el-divider content-position="center">[[.SubTable.FunctionName]]信息</el-divider>
+        <el-row :gutter="10" class="mb8">
+          <el-col :span="1.5">
+            <el-button type="primary" icon="el-icon-plus" size="mini" @click="handleAdd[[$sub]]">添加</el-button>
+          </el-col>
+          <el-col :span="1.5">
+            <el-button type="danger" icon="el-icon-delete" size="mini" @click="handleDelete[[$sub]]">删除</el-button>
+          </el-col>
+        </el-row>
+        <el-table :data="[[$subList]]" @selection-change="handle[[$sub]]SelectionChange">
+          <el-table-column type="selection" width="50" align="center" />
+          <el-table-column label="序号" align="center" type="index" width="50" />
+[[- range .SubFormColumns]]
+[[- $label := columnLabel .ColumnComment]]
+          <el-table-column label="[[$label]]" prop="[[.JavaField]]" width="150">
+            <template slot-scope="scope">
+[[- if eq .HtmlType "datetime"]]
+              <el-date-picker
+                clearable
+                v-model="scope.row.[[.JavaField]]"
+                type="date"
+                value-format="yyyy-MM-dd"
+                placeholder="请选择[[$label]]"
+              ></el-date-picker>
+[[- else if and (isDictHtml .HtmlType) .DictType]]
+              <el-select v-model="scope.row.[[.JavaField]]" placeholder="请选择[[$label]]">
+                <el-option
+                  v-for="dict in dict.type.[[.DictType]]"
+                  :key="dict.value"
+                  :label="dict.label"
+                  :value="dict.value"
+                ></el-option>
+              </el-select>
+[[- else]]
+              <el-input v-model="scope.row.[[.JavaField]]" placeholder="请输入[[$label]]" />
+[[- end]]
+            </template>
+          </el-table-column>
+[[- end]]
+        </el-table>
+[[- end]]
+      </el-form>
+      <div slot="footer" class="dialog-footer">
+        <el-button type="primary" @click="submitForm">确 定</el-button>
+        <el-button @click="cancel">取 消</el-button>
+      </div>
+    </el-dialog>
+  </div>
+</template>
+
+<script>
+import { list[[$api]], get[[$api]], del[[$api]], add[[$api]], update[[$api]] } from "@/api/[[.ModuleName]]/[[.BusinessName]]"
+[[- if $isTree]]
+import Treeselect from "@riophae/vue-treeselect"
+import "@riophae/vue-treeselect/dist/vue-treeselect.css"
+[[- end]]
+
+export default {
+  name: "[[.ClassName]]",
+[[- if $isTree]]
+  components: {
+    Treeselect
+  },
+[[- end]]
+[[- if .DictTypes]]
+  dicts: [[print "["]][[range $i, $dict := .DictTypes]][[if $i]], [[end]]'[[$dict]]'[[end]]],
+[[- end]]
+  data() {
+    return {
+      // 遮罩层
+      loading: true,
+[[- if $isTree]]
+      // 是否展开，默认全部展开
+      isExpandAll: true,
+      // 重新渲染表格状态
+      refreshTable: true,
+      // [[.FunctionName]]树选项
+      [[.BusinessName]]Options: [],
+[[- else]]
+      // 选中数组
+      ids: [],
+      // 非单个禁用
+      single: true,
+      // 非多个禁用
+      multiple: true,
+      // 总条数
+      total: 0,
+[[- end]]
+[[- if $sub]]
+      // 子表选中数据
+      checked[[$sub]]: [],
+      // [[.SubTable.FunctionName]]表格数据
+      [[$subList]]: [],
+[[- end]]
+      // 显示搜索条件
+      showSearch: true,
+      // [[.FunctionName]]表格数据
+      [[$list]]: [],
+      // 弹出层标题
+      title: "",
+      // 是否显示弹出层
+      open: false,
+[[- range .QueryColumns]]
+[[- if and (eq .HtmlType "datetime") (eq .QueryType "BETWEEN")]]
+      // [[columnLabel .ColumnComment]]时间范围
+      daterange[[capitalize .JavaField]]: [],
+[[- end]]
+[[- end]]
+      // 查询参数
+      queryParams: {
+[[- if not $isTree]]
+        pageNum: 1,
+        pageSize: 10,
+[[- end]]
+[[- range .QueryColumns]]
+[[- if not (and (eq .HtmlType "datetime") (eq .QueryType "BETWEEN"))]]
+        [[.JavaField]]: null,
+[[- end]]
+[[- end]]
+      },
+      // 表单参数
+      form: {},
+      // 表单校验
+      rules: {
+[[- range .FormColumns]]
+[[- if .IsRequiredField]]
+        [[.JavaField]]: [
+          { required: true, message: "[[columnLabel .ColumnComment]]不能为空", trigger: "[[if or (isDictHtml .HtmlType) (eq .HtmlType "datetime")]]change[[else]]blur[[end]]" }
+        ],
+[[- end]]
+[[- end]]
+      }
+    }
+  },
+  created() {
+    this.getList()
+  },
+  methods: {
+    /** 查询[[.FunctionName]]列表 */
+    getList() {
+      this.loading = true
+[[- $hasRange := false]]
+[[- range .QueryColumns]][[if and (eq .HtmlType "datetime") (eq .QueryType "BETWEEN")]][[$hasRange = true]][[end]][[end]]
+[[- if $hasRange]]
+      this.queryParams.params = {}
+[[- range .QueryColumns]]
+[[- if and (eq .HtmlType "datetime") (eq .QueryType "BETWEEN")]]
+      this.addDateRange(this.queryParams, this.daterange[[capitalize .JavaField]], "[[capitalize .JavaField]]")
+[[- end]]
+[[- end]]
+[[- end]]
+      list[[$api]](this.queryParams).then(response => {
+[[- if $isTree]]
+        this.[[$list]] = this.handleTree(response.rows, "[[$treeCode]]", "[[$treeParent]]")
+[[- else]]
+        this.[[$list]] = response.rows
+        this.total = response.total
+[[- end]]
+        this.loading = false
+      })
+    },
+[[- if $isTree]]
+    /** 转换[[.FunctionName]]数据结构 */
+    normalizer(node) {
+      if (node.children && !node.children.length) {
+        delete node.children
+      }
+      return {
+        id: node.[[$treeCode]],
+        label: node.[[$treeName]],
+        children: node.children
+      }
+    },
+    /** 查询[[.FunctionName]]下拉树结构 */
+    getTreeselect() {
+      list[[$api]]().then(response => {
+        const root = { [[$treeCode]]: [[$rootValue]], [[$treeName]]: "顶级节点", children: [] }
+        root.children = this.handleTree(response.rows, "[[$treeCode]]", "[[$treeParent]]")
+        this.[[.BusinessName]]Options = [root]
+      })
+    },
+[[- end]]
+    // 取消按钮
+    cancel() {
+      this.open = false
+      this.reset()
+    },
+    // 表单重置
+    reset() {
+      this.form = {
+[[- range .Columns]]
+        [[.JavaField]]: [[if eq .HtmlType "checkbox"]][][[else]]null[[end]],
+[[- end]]
+      }
+[[- if $sub]]
+      this.[[$subList]] = []
+[[- end]]
+      this.resetForm("form")
+    },
+    /** 搜索按钮操作 */
+    handleQuery() {
+[[- if not $isTree]]
+      this.queryParams.pageNum = 1
+[[- end]]
+      this.getList()
+    },
+    /** 重置按钮操作 */
+    resetQuery() {
+[[- range .QueryColumns]]
+[[- if and (eq .HtmlType "datetime") (eq .QueryType "BETWEEN")]]
+      this.daterange[[capitalize .JavaField]] = []
+[[- end]]
+[[- end]]
+      this.resetForm("queryForm")
+      this.handleQuery()
+    },
+[[- if not $isTree]]
+    // 多选框选中数据
+    handleSelectionChange(selection) {
+      this.ids = selection.map(item => item.[[$pk]])
+      this.single = selection.length !== 1
+      this.multiple = !selection.length
+    },
+[[- end]]
+    /** 新增按钮操作 */
+    handleAdd([[if $isTree]]row[[end]]) {
+      this.reset()
+[[- if $isTree]]
+      this.getTreeselect()
+      if (row != null && row.[[$treeCode]]) {
+        this.form.[[$treeParent]] = row.[[$treeCode]]
+      } else {
+        this.form.[[$treeParent]] = [[$rootValue]]
+      }
+[[- end]]
+      this.open = true
+      this.title = "添加[[.FunctionName]]"
+    },
+[[- if $isTree]]
+    /** 展开/折叠操作 */
+    toggleExpandAll() {
+      this.refreshTable = false
+      this.isExpandAll = !this.isExpandAll
+      this.$nextTick(() => {
+        this.refreshTable = true
+      })
+    },
+[[- end]]
+    /** 修改按钮操作 */
+    handleUpdate(row) {
+      this.reset()
+[[- if $isTree]]
+      this.getTreeselect()
+      const [[$pk]] = row.[[$pk]]
+[[- else]]
+      const [[$pk]] = row.[[$pk]] || this.ids
+[[- end]]
+      get[[$api]]([[$pk]]).then(response => {
+        this.form = response.data
+[[- range .FormColumns]]
+[[- if eq .HtmlType "checkbox"]]
+        this.form.[[.JavaField]] = response.data.[[.JavaField]] ? response.data.[[.JavaField]].split(",") : []
+[[- end]]
+[[- end]]
+[[- if $sub]]
+        this.[[$subList]] = response.data.[[$subList]] || []
+[[- end]]
+        this.open = true
+        this.title = "修改[[.FunctionName]]"
+      })
+    },
+    /** 提交按钮 */
+    submitForm() {
+      this.$refs["form"].validate(valid => {
+        if (valid) {
+[[- range .FormColumns]]
+[[- if eq .HtmlType "checkbox"]]
+          this.form.[[.JavaField]] = Array.isArray(this.form.[[.JavaField]]) ? this.form.[[.JavaField]].join(",") : this.form.[[.JavaField]]
+[[- end]]
+[[- end]]
+[[- if $sub]]
+          this.form.[[$subList]] = this.[[$subList]]
+[[- end]]
+          if (this.form.[[$pk]] != null) {
+            update[[$api]](this.form).then(() => {
+              this.$modal.msgSuccess("修改成功")
+              this.open = false
+              this.getList()
+            })
+          } else {
+            add[[$api]](this.form).then(() => {
+              this.$modal.msgSuccess("新增成功")
+              this.open = false
+              this.getList()
+            })
+          }
+        }
+      })
+    },
+    /** 删除按钮操作 */
+    handleDelete(row) {
+[[- if $isTree]]
+      const [[$pk]]s = row.[[$pk]]
+[[- else]]
+      const [[$pk]]s = row.[[$pk]] || this.ids
+[[- end]]
+      this.$modal.confirm('是否确认删除[[.FunctionName]]编号为"' + [[$pk]]s + '"的数据项？').then(function() {
+        return del[[$api]]([[$pk]]s)
+      }).then(() => {
+        this.getList()
+        this.$modal.msgSuccess("删除成功")
+      }).catch(() => {})
+    },
+[[- if $sub]]
+    /** [[.SubTable.FunctionName]]添加按钮操作 */
+    handleAdd[[$sub]]() {
+      this.[[$subList]].push({})
+    },
+    /** [[.SubTable.FunctionName]]删除按钮操作 */
+    handleDelete[[$sub]]() {
+      if (this.checked[[$sub]].length === 0) {
+        this.$modal.msgError("请先选择要删除的[[.SubTable.FunctionName]]数据")
+        return
+      }
+      this.[[$subList]] = this.[[$subList]].filter(item => this.checked[[$sub]].indexOf(item) === -1)
+    },
+    /** [[.SubTable.FunctionName]]复选框选中数据 */
+    handle[[$sub]]SelectionChange(selection) {
+      this.checked[[$sub]] = selection
+    },
+[[- end]]
+  }
+}
+</script>
+`
+}
+
+// getApiJsTemplate 获取前端接口模板 对应Java后端的vm/js/api.js.vm
+func (e *TemplateEngine) getApiJsTemplate() string {
+	return `[[- $api := .BusinessClass]]
+[[- $pk := .PkColumn.JavaField]]
+[[- $url := printf "/%s/%s" .ModuleName .BusinessName]]
+import request from '@/utils/request'
+
+// 查询[[.FunctionName]]列表
+export function list[[$api]](query) {
+  return request({
+    url: '[[$url]]/list',
+    method: 'get',
+    params: query
+  })
+}
+
+// 查询[[.FunctionName]]详细
+export function get[[$api]]([[$pk]]) {
+  return request({
+    url: '[[$url]]/' + [[$pk]],
+    method: 'get'
+  })
+}
+
+// 新增[[.FunctionName]]
+export function add[[$api]](data) {
+  return request({
+    url: '[[$url]]',
+    method: 'post',
+    data: data
+  })
+}
+
+// 修改[[.FunctionName]]
+export function update[[$api]](data) {
+  return request({
+    url: '[[$url]]',
+    method: 'put',
+    data: data
+  })
+}
+
+// 删除[[.FunctionName]]
+export function del[[$api]]([[$pk]]) {
+  return request({
+    url: '[[$url]]/' + [[$pk]],
+    method: 'delete'
+  })
+}
+`
+}
+
+// getApiTsTemplate 获取TypeScript前端接口模板
+func (e *TemplateEngine) getApiTsTemplate() string {
+	return `[[- $api := .BusinessClass]]
+[[- $pk := .PkColumn.JavaField]]
+[[- $pkType := tsType .PkColumn.JavaType]]
+[[- $url := printf "/%s/%s" .ModuleName .BusinessName]]
+import request from '@/utils/request'
+import type { [[.ClassName]], [[.ClassName]]Query } from '@/types/[[.ModuleName]]/[[.BusinessName]]'
+
+// 查询[[.FunctionName]]列表
+export function list[[$api]](query?: [[.ClassName]]Query) {
+  return request({
+    url: '[[$url]]/list',
+    method: 'get',
+    params: query
+  })
+}
+
+// 查询[[.FunctionName]]详细
+export function get[[$api]]([[$pk]]: [[$pkType]]) {
+  return request({
+    url: '[[$url]]/' + [[$pk]],
+    method: 'get'
+  })
+}
+
+// 新增[[.FunctionName]]
+export function add[[$api]](data: [[.ClassName]]) {
+  return request({
+    url: '[[$url]]',
+    method: 'post',
+    data: data
+  })
+}
+
+// 修改[[.FunctionName]]
+export function update[[$api]](data: [[.ClassName]]) {
+  return request({
+    url: '[[$url]]',
+    method: 'put',
+    data: data
+  })
+}
+
+// 删除[[.FunctionName]]
+export function del[[$api]]([[$pk]]: [[$pkType]] | [[$pkType]][]) {
+  return request({
+    url: '[[$url]]/' + [[$pk]],
+    method: 'delete'
+  })
+}
+`
+}
+
+// getTypesTsTemplate 获取TypeScript类型定义模板，字段均为可选以便复用于表单
+func (e *TemplateEngine) getTypesTsTemplate() string {
+	return `/** [[.FunctionName]]（[[.TableName]]） */
+export interface [[.ClassName]] {
+[[- range .Columns]]
+  /** [[.ColumnComment]] */
+  [[.JavaField]]?: [[tsType .JavaType]][[if eq .HtmlType "checkbox"]] | string[][[end]]
+[[- end]]
+[[- if .SubClassName]]
+  /** [[.SubTable.FunctionName]]信息 */
+  [[uncapitalize .SubClassName]]List?: [[.SubClassName]][]
+[[- end]]
+[[- if .Table.IsTree]]
+  /** 子节点 */
+  children?: [[.ClassName]][]
+[[- end]]
+}
+[[- if .SubClassName]]
+
+/** [[.SubTable.FunctionName]]（[[.SubTable.Name]]） */
+export interface [[.SubClassName]] {
+[[- range .SubTable.Columns]]
+  /** [[.ColumnComment]] */
+  [[.JavaField]]?: [[tsType .JavaType]]
+[[- end]]
+}
+[[- end]]
+
+/** [[.FunctionName]]查询参数 */
+export interface [[.ClassName]]Query {
+[[- if not .Table.IsTree]]
+  pageNum?: number
+  pageSize?: number
+[[- end]]
+[[- range .QueryColumns]]
+[[- if not (and (eq .HtmlType "datetime") (eq .QueryType "BETWEEN"))]]
+  /** [[.ColumnComment]] */
+  [[.JavaField]]?: [[tsType .JavaType]]
+[[- end]]
+[[- end]]
+  /** 范围查询等扩展参数 */
+  params?: Record<string, any>
+}
+`
+}
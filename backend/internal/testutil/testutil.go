@@ -0,0 +1,31 @@
+// Package testutil 服务和数据权限测试共用的数据库
+package testutil
+
+import (
+	"context"
+	"testing"
+	"wosm/pkg/database"
+	"wosm/pkg/migrate"
+	"wosm/pkg/sqlitetest"
+
+	"gorm.io/gorm"
+)
+
+// OpenMigratedDB 创建执行过全部迁移的SQLite内存库，并作为全局数据库连接，测试结束时恢复
+// 初始数据：用户1(admin)属于部门103，用户2(ry)属于部门105；角色2自定的部门为100、101、105；公告均由admin创建
+func OpenMigratedDB(t testing.TB) *gorm.DB {
+	t.Helper()
+	db := sqlitetest.Open(t, "")
+	migrator, err := migrate.New(db)
+	if err != nil {
+		t.Fatalf("创建迁移失败: %v", err)
+	}
+	if _, err := migrator.Up(context.Background(), 0); err != nil {
+		t.Fatalf("执行迁移失败: %v", err)
+	}
+
+	previous := database.DB
+	database.DB = db
+	t.Cleanup(func() { database.DB = previous })
+	return db
+}
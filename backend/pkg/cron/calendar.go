@@ -0,0 +1,154 @@
+package cron
+
+import (
+	"time"
+)
+
+// 计算下一个可用时间时的最大跳过次数，防止日历排除了所有时间时死循环
+const maxCalendarSkips = 10000
+
+// Calendar 排除日历 对应Quartz的org.quartz.Calendar
+// 被日历排除的时间点不会触发任务
+type Calendar interface {
+	// IsTimeIncluded 判断时间点是否可以触发任务
+	IsTimeIncluded(t time.Time) bool
+	// NextIncludedTime 返回不早于t的第一个可以触发任务的时间点，没有时返回零值
+	NextIncludedTime(t time.Time) time.Time
+}
+
+// TimeRange 时间段，左闭右开
+type TimeRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Contains 判断时间点是否在时间段内
+func (r TimeRange) Contains(t time.Time) bool {
+	return !t.Before(r.Start) && t.Before(r.End)
+}
+
+// ExclusionCalendar 节假日/封账日历，支持排除指定日期、每周固定的星期几以及时间段
+type ExclusionCalendar struct {
+	location *time.Location
+	dates    map[string]bool
+	weekdays [7]bool
+	ranges   []TimeRange
+}
+
+// NewExclusionCalendar 创建排除日历，日期和星期按loc时区判断
+func NewExclusionCalendar(loc *time.Location) *ExclusionCalendar {
+	if loc == nil {
+		loc = time.Local
+	}
+	return &ExclusionCalendar{
+		location: loc,
+		dates:    make(map[string]bool),
+	}
+}
+
+// ExcludeDate 排除某一天
+func (c *ExclusionCalendar) ExcludeDate(date time.Time) {
+	c.dates[date.In(c.location).Format("2006-01-02")] = true
+}
+
+// ExcludeWeekday 排除每周的某一天
+func (c *ExclusionCalendar) ExcludeWeekday(weekday time.Weekday) {
+	c.weekdays[weekday] = true
+}
+
+// ExcludeRange 排除时间段[start, end)
+func (c *ExclusionCalendar) ExcludeRange(start, end time.Time) {
+	if !end.After(start) {
+		return
+	}
+	c.ranges = append(c.ranges, TimeRange{Start: start, End: end})
+}
+
+// IsTimeIncluded 判断时间点是否可以触发任务
+func (c *ExclusionCalendar) IsTimeIncluded(t time.Time) bool {
+	local := t.In(c.location)
+	if c.weekdays[local.Weekday()] || c.dates[local.Format("2006-01-02")] {
+		return false
+	}
+	for _, r := range c.ranges {
+		if r.Contains(t) {
+			return false
+		}
+	}
+	return true
+}
+
+// NextIncludedTime 返回不早于t的第一个可以触发任务的时间点
+func (c *ExclusionCalendar) NextIncludedTime(t time.Time) time.Time {
+	for i := 0; i < maxCalendarSkips; i++ {
+		local := t.In(c.location)
+		if c.weekdays[local.Weekday()] || c.dates[local.Format("2006-01-02")] {
+			t = time.Date(local.Year(), local.Month(), local.Day()+1, 0, 0, 0, 0, c.location)
+			continue
+		}
+
+		excluded := false
+		for _, r := range c.ranges {
+			if r.Contains(t) {
+				t = r.End
+				excluded = true
+			}
+		}
+		if !excluded {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// CalendarSchedule 带排除日历的调度计划，被任一日历排除的执行时间会被跳过
+type CalendarSchedule struct {
+	schedule  *Schedule
+	calendars []Calendar
+}
+
+// WithCalendars 为调度计划附加排除日历
+func WithCalendars(schedule *Schedule, calendars ...Calendar) *CalendarSchedule {
+	return &CalendarSchedule{schedule: schedule, calendars: calendars}
+}
+
+// Next 返回严格晚于t且未被日历排除的下一次执行时间，没有时返回零值
+func (s *CalendarSchedule) Next(t time.Time) time.Time {
+	next := s.schedule.Next(t)
+	for i := 0; i < maxCalendarSkips && !next.IsZero(); i++ {
+		resume, included := s.resumeTime(next)
+		if included {
+			return next
+		}
+		if resume.IsZero() {
+			return time.Time{}
+		}
+		// 从日历重新允许触发的时间点开始查找
+		next = s.schedule.Next(resume.Add(-time.Second))
+	}
+	return time.Time{}
+}
+
+// IsTimeIncluded 判断时间点是否未被任何日历排除
+func (s *CalendarSchedule) IsTimeIncluded(t time.Time) bool {
+	_, included := s.resumeTime(t)
+	return included
+}
+
+// resumeTime 检查时间点是否被排除，被排除时返回所有日历都允许触发的最早时间
+func (s *CalendarSchedule) resumeTime(t time.Time) (time.Time, bool) {
+	resume := t
+	for _, calendar := range s.calendars {
+		if calendar == nil || calendar.IsTimeIncluded(t) {
+			continue
+		}
+		next := calendar.NextIncludedTime(t)
+		if next.IsZero() {
+			return time.Time{}, false
+		}
+		if next.After(resume) {
+			resume = next
+		}
+	}
+	return resume, !resume.After(t)
+}
@@ -0,0 +1,100 @@
+package cron
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetNextExecutionsWithCalendar(t *testing.T) {
+	calendar := NewExclusionCalendar(time.UTC)
+	calendar.ExcludeWeekday(time.Saturday)
+	calendar.ExcludeWeekday(time.Sunday)
+	calendar.ExcludeDate(utc("2026-01-01 00:00:00"))
+	// 月末封账窗口
+	calendar.ExcludeRange(utc("2026-01-28 00:00:00"), utc("2026-02-03 00:00:00"))
+
+	tests := []struct {
+		name       string
+		expression string
+		from       string
+		expected   []string
+	}{
+		{"跳过节假日和周末", "0 0 9 * * ?", "2025-12-31 12:00:00",
+			[]string{"2026-01-02 09:00:00", "2026-01-05 09:00:00", "2026-01-06 09:00:00"}},
+		{"跳过封账窗口", "0 0 9 * * ?", "2026-01-27 12:00:00",
+			[]string{"2026-02-03 09:00:00", "2026-02-04 09:00:00"}},
+		{"时间段内的高频任务", "0 0 * * * ?", "2026-01-27 22:30:00",
+			[]string{"2026-01-27 23:00:00", "2026-02-03 00:00:00"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executions, err := GetNextExecutionsFrom("TZ=UTC "+tt.expression, utc(tt.from), len(tt.expected), calendar)
+			require.NoError(t, err)
+
+			var actual []string
+			for _, e := range executions {
+				actual = append(actual, e.UTC().Format("2006-01-02 15:04:05"))
+			}
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestCalendarExcludesEverything(t *testing.T) {
+	calendar := NewExclusionCalendar(time.UTC)
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		calendar.ExcludeWeekday(d)
+	}
+
+	executions, err := GetNextExecutionsFrom("TZ=UTC 0 0 9 * * ?", utc("2026-01-01 00:00:00"), 3, calendar)
+	require.NoError(t, err)
+	assert.Empty(t, executions)
+}
+
+func TestParseICal(t *testing.T) {
+	ics := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20261001",
+		"DTEND;VALUE=DATE:20261008",
+		"SUMMARY:国庆节",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART:20260130T180000Z",
+		"DTEND;TZID=Asia/Shanghai:20260202T090000",
+		"SUMMARY:Month-end\\, freeze",
+		" window",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART:20260501",
+		"SUMMARY:劳动节",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	events, err := ParseICal(strings.NewReader(ics), time.UTC)
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+
+	assert.Equal(t, "国庆节", events[0].Summary)
+	assert.True(t, events[0].AllDay)
+	assert.Equal(t, utc("2026-10-01 00:00:00"), events[0].Start)
+	assert.Equal(t, utc("2026-10-08 00:00:00"), events[0].End)
+
+	assert.Equal(t, "Month-end, freezewindow", events[1].Summary)
+	assert.False(t, events[1].AllDay)
+	assert.Equal(t, utc("2026-01-30 18:00:00"), events[1].Start.UTC())
+	assert.Equal(t, utc("2026-02-02 01:00:00"), events[1].End.UTC())
+
+	assert.True(t, events[2].AllDay)
+	assert.Equal(t, utc("2026-05-02 00:00:00"), events[2].End)
+
+	_, err = ParseICal(strings.NewReader("BEGIN:VEVENT\nSUMMARY:x\n"), time.UTC)
+	assert.Error(t, err)
+}
@@ -3,8 +3,6 @@ package cron
 import (
 	"fmt"
 	"time"
-
-	"github.com/robfig/cron/v3"
 )
 
 // CronUtils Cron表达式工具类 对应Java后端的CronUtils
@@ -16,47 +14,50 @@ func NewCronUtils() *CronUtils {
 }
 
 // IsValid 校验cron表达式是否有效 对应Java后端的isValid方法
+// 支持Quartz语法（L、W、#、?、年字段）以及 TZ= 时区前缀
 func IsValid(cronExpression string) bool {
 	if cronExpression == "" {
 		return false
 	}
 
-	// 创建cron解析器
-	parser := cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
-	
 	// 尝试解析cron表达式
-	_, err := parser.Parse(cronExpression)
+	_, err := Parse(cronExpression)
 	if err != nil {
 		fmt.Printf("IsValid: Cron表达式无效: %s, 错误: %v\n", cronExpression, err)
 		return false
 	}
 
-	fmt.Printf("IsValid: Cron表达式有效: %s\n", cronExpression)
 	return true
 }
 
-// GetNextExecution 获取下次执行时间 对应Java后端的getNextExecution方法
-func GetNextExecution(cronExpression string) (*time.Time, error) {
-	if cronExpression == "" {
-		return nil, fmt.Errorf("cron表达式不能为空")
+// GetInvalidMessage 获取cron表达式无效的原因，表达式有效时返回空字符串
+func GetInvalidMessage(cronExpression string) string {
+	if _, err := Parse(cronExpression); err != nil {
+		return err.Error()
 	}
+	return ""
+}
 
-	// 创建cron解析器
-	parser := cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
-	
-	// 解析cron表达式
-	schedule, err := parser.Parse(cronExpression)
+// GetNextExecution 获取下次执行时间 对应Java后端的getNextExecution方法
+// calendars 为排除日历，被排除的时间会被跳过
+func GetNextExecution(cronExpression string, calendars ...Calendar) (*time.Time, error) {
+	executions, err := GetNextExecutionsFrom(cronExpression, time.Now(), 1, calendars...)
 	if err != nil {
-		return nil, fmt.Errorf("解析cron表达式失败: %v", err)
+		return nil, err
 	}
+	if len(executions) == 0 {
+		return nil, nil
+	}
+	return &executions[0], nil
+}
 
-	// 获取下次执行时间
-	nextTime := schedule.Next(time.Now())
-	return &nextTime, nil
+// GetNextExecutions 获取未来N次执行时间，calendars 为排除日历
+func GetNextExecutions(cronExpression string, count int, calendars ...Calendar) ([]time.Time, error) {
+	return GetNextExecutionsFrom(cronExpression, time.Now(), count, calendars...)
 }
 
-// GetNextExecutions 获取未来N次执行时间
-func GetNextExecutions(cronExpression string, count int) ([]time.Time, error) {
+// GetNextExecutionsFrom 获取from之后的N次执行时间，表达式不再触发时返回的数量可能少于N
+func GetNextExecutionsFrom(cronExpression string, from time.Time, count int, calendars ...Calendar) ([]time.Time, error) {
 	if cronExpression == "" {
 		return nil, fmt.Errorf("cron表达式不能为空")
 	}
@@ -65,21 +66,19 @@ func GetNextExecutions(cronExpression string, count int) ([]time.Time, error) {
 		return nil, fmt.Errorf("执行次数必须大于0")
 	}
 
-	// 创建cron解析器
-	parser := cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
-	
-	// 解析cron表达式
-	schedule, err := parser.Parse(cronExpression)
+	schedule, err := Parse(cronExpression)
 	if err != nil {
 		return nil, fmt.Errorf("解析cron表达式失败: %v", err)
 	}
+	calendarSchedule := WithCalendars(schedule, calendars...)
 
-	// 获取未来N次执行时间
 	var executions []time.Time
-	currentTime := time.Now()
-	
+	currentTime := from
 	for i := 0; i < count; i++ {
-		nextTime := schedule.Next(currentTime)
+		nextTime := calendarSchedule.Next(currentTime)
+		if nextTime.IsZero() {
+			break
+		}
 		executions = append(executions, nextTime)
 		currentTime = nextTime
 	}
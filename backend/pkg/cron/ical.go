@@ -0,0 +1,149 @@
+package cron
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ICalEvent iCal文件中的事件（VEVENT）
+type ICalEvent struct {
+	Summary string
+	Start   time.Time
+	End     time.Time // 不包含，未指定DTEND时全天事件为次日0点，其它事件等于Start
+	AllDay  bool      // DTSTART为VALUE=DATE格式
+}
+
+// ParseICal 解析iCal（RFC 5545）文件中的VEVENT事件，用于导入节假日
+// 只读取 SUMMARY、DTSTART、DTEND，不展开 RRULE 重复规则；未指定时区的时间按loc解析
+func ParseICal(r io.Reader, loc *time.Location) ([]ICalEvent, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+
+	lines, err := unfoldICalLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []ICalEvent
+	var current *ICalEvent
+	for i, line := range lines {
+		name, params, value := splitICalLine(line)
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			current = &ICalEvent{}
+		case name == "END" && strings.EqualFold(value, "VEVENT"):
+			if current == nil {
+				return nil, fmt.Errorf("第%d行: END:VEVENT 缺少对应的 BEGIN", i+1)
+			}
+			if current.Start.IsZero() {
+				return nil, fmt.Errorf("第%d行: 事件缺少DTSTART", i+1)
+			}
+			if current.End.IsZero() {
+				current.End = current.Start
+				if current.AllDay {
+					current.End = current.Start.AddDate(0, 0, 1)
+				}
+			}
+			events = append(events, *current)
+			current = nil
+		case current == nil:
+			continue
+		case name == "SUMMARY":
+			current.Summary = unescapeICalText(value)
+		case name == "DTSTART" || name == "DTEND":
+			t, allDay, err := parseICalTime(value, params, loc)
+			if err != nil {
+				return nil, fmt.Errorf("第%d行: %v", i+1, err)
+			}
+			if name == "DTSTART" {
+				current.Start, current.AllDay = t, allDay
+			} else {
+				current.End = t
+			}
+		}
+	}
+	if current != nil {
+		return nil, fmt.Errorf("iCal文件不完整，缺少 END:VEVENT")
+	}
+	return events, nil
+}
+
+// unfoldICalLines 读取并展开折行（以空格或制表符开头的行是上一行的延续）
+func unfoldICalLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取iCal文件失败: %v", err)
+	}
+	return lines, nil
+}
+
+// splitICalLine 拆分属性名、参数和值，例如 DTSTART;VALUE=DATE:20260101
+func splitICalLine(line string) (string, map[string]string, string) {
+	i := strings.Index(line, ":")
+	if i == -1 {
+		return strings.ToUpper(line), nil, ""
+	}
+	head, value := line[:i], line[i+1:]
+
+	parts := strings.Split(head, ";")
+	params := make(map[string]string, len(parts)-1)
+	for _, p := range parts[1:] {
+		if kv := strings.SplitN(p, "=", 2); len(kv) == 2 {
+			params[strings.ToUpper(kv[0])] = strings.Trim(kv[1], `"`)
+		}
+	}
+	return strings.ToUpper(parts[0]), params, strings.TrimSpace(value)
+}
+
+// parseICalTime 解析DATE或DATE-TIME格式的时间
+func parseICalTime(value string, params map[string]string, loc *time.Location) (time.Time, bool, error) {
+	if tzid := params["TZID"]; tzid != "" {
+		tz, err := time.LoadLocation(tzid)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("时区无效: %s", tzid)
+		}
+		loc = tz
+	}
+
+	if strings.EqualFold(params["VALUE"], "DATE") || len(value) == 8 {
+		t, err := time.ParseInLocation("20060102", value, loc)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("日期格式错误: %s", value)
+		}
+		return t, true, nil
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("时间格式错误: %s", value)
+		}
+		return t, false, nil
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("时间格式错误: %s", value)
+	}
+	return t, false, nil
+}
+
+// unescapeICalText 还原文本中的转义字符
+func unescapeICalText(text string) string {
+	return strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(text)
+}
@@ -0,0 +1,530 @@
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Quartz表达式支持的年份范围 对应Quartz的CronExpression
+const (
+	MinYear = 1970
+	MaxYear = 2099
+)
+
+// Schedule Quartz兼容的cron调度计划 对应Java后端的org.quartz.CronExpression
+// 字段顺序：秒 分 时 日 月 周 [年]
+// 支持 * ? - , / 以及日字段的 L、L-n、nW、LW 和周字段的 nL、n#m
+// 实现了 robfig/cron 的 Schedule 接口，可直接交给调度器使用
+type Schedule struct {
+	expression string
+	location   *time.Location
+
+	second, minute, hour uint64 // 位集合
+	month                uint64 // 位集合（1-12）
+	years                []bool // 下标为 year-MinYear，为nil时表示任意年份
+
+	// 日字段
+	domAny         bool   // * 或 ?
+	dom            uint64 // 位集合（1-31）
+	domLast        bool   // L 或 L-n
+	domLastOffset  int    // L-n 中的 n
+	domWeekday     int    // nW 中的 n（最近的工作日）
+	domLastWeekday bool   // LW（本月最后一个工作日）
+
+	// 周字段（1=周日 ... 7=周六）
+	dowAny  bool
+	dow     uint64 // 位集合（1-7）
+	dowLast int    // nL 中的 n（本月最后一个周n）
+	dowNth  int    // n#m 中的 n
+	dowNthM int    // n#m 中的 m（本月第m个周n）
+
+	every time.Duration // @every 描述符的固定间隔
+}
+
+// 字段范围定义
+type fieldBounds struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	secondBounds = fieldBounds{"秒", 0, 59, nil}
+	minuteBounds = fieldBounds{"分", 0, 59, nil}
+	hourBounds   = fieldBounds{"时", 0, 23, nil}
+	domBounds    = fieldBounds{"日", 1, 31, nil}
+	monthBounds  = fieldBounds{"月", 1, 12, map[string]int{
+		"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+		"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+	}}
+	dowBounds = fieldBounds{"周", 1, 7, map[string]int{
+		"SUN": 1, "MON": 2, "TUE": 3, "WED": 4, "THU": 5, "FRI": 6, "SAT": 7,
+	}}
+	yearBounds = fieldBounds{"年", MinYear, MaxYear, nil}
+)
+
+// 预定义描述符，转换为等价的Quartz表达式
+var descriptors = map[string]string{
+	"@yearly":   "0 0 0 1 1 ?",
+	"@annually": "0 0 0 1 1 ?",
+	"@monthly":  "0 0 0 1 * ?",
+	"@weekly":   "0 0 0 ? * SUN",
+	"@daily":    "0 0 0 * * ?",
+	"@midnight": "0 0 0 * * ?",
+	"@hourly":   "0 0 * * * ?",
+}
+
+// Parse 解析Quartz cron表达式，使用本地时区计算执行时间
+// 表达式可以使用 TZ=Asia/Shanghai 或 CRON_TZ=Asia/Shanghai 前缀指定时区
+func Parse(expression string) (*Schedule, error) {
+	return ParseInLocation(expression, time.Local)
+}
+
+// ParseInLocation 解析Quartz cron表达式，并在指定时区计算执行时间
+func ParseInLocation(expression string, loc *time.Location) (*Schedule, error) {
+	spec := strings.TrimSpace(expression)
+	if spec == "" {
+		return nil, fmt.Errorf("cron表达式不能为空")
+	}
+	if loc == nil {
+		loc = time.Local
+	}
+
+	// 时区前缀
+	if strings.HasPrefix(spec, "TZ=") || strings.HasPrefix(spec, "CRON_TZ=") {
+		i := strings.IndexAny(spec, " \t")
+		if i == -1 {
+			return nil, fmt.Errorf("cron表达式缺少时间字段: %s", expression)
+		}
+		name := spec[strings.Index(spec, "=")+1 : i]
+		tz, err := time.LoadLocation(name)
+		if err != nil {
+			return nil, fmt.Errorf("时区无效: %s", name)
+		}
+		loc = tz
+		spec = strings.TrimSpace(spec[i:])
+	}
+
+	// 描述符
+	if strings.HasPrefix(spec, "@") {
+		if strings.HasPrefix(spec, "@every ") {
+			d, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(spec, "@every ")))
+			if err != nil || d < time.Second {
+				return nil, fmt.Errorf("@every 间隔无效: %s", spec)
+			}
+			return &Schedule{expression: expression, location: loc, every: d.Truncate(time.Second)}, nil
+		}
+		quartz, ok := descriptors[strings.ToLower(spec)]
+		if !ok {
+			return nil, fmt.Errorf("不支持的描述符: %s", spec)
+		}
+		spec = quartz
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 6 && len(fields) != 7 {
+		return nil, fmt.Errorf("cron表达式应包含6或7个字段（秒 分 时 日 月 周 [年]），实际为%d个", len(fields))
+	}
+
+	s := &Schedule{expression: expression, location: loc}
+	var err error
+	if s.second, err = parseBitField(fields[0], secondBounds); err != nil {
+		return nil, err
+	}
+	if s.minute, err = parseBitField(fields[1], minuteBounds); err != nil {
+		return nil, err
+	}
+	if s.hour, err = parseBitField(fields[2], hourBounds); err != nil {
+		return nil, err
+	}
+	if s.month, err = parseBitField(fields[4], monthBounds); err != nil {
+		return nil, err
+	}
+
+	domField, dowField := strings.ToUpper(fields[3]), strings.ToUpper(fields[5])
+	// Quartz要求日和周字段必须有一个为?，这里兼容其中一个写成*的情况
+	switch {
+	case domField == "?" && dowField == "?":
+		return nil, fmt.Errorf("日和周字段不能同时为'?'")
+	case domField != "?" && dowField != "?":
+		if dowField == "*" {
+			dowField = "?"
+		} else if domField == "*" {
+			domField = "?"
+		} else {
+			return nil, fmt.Errorf("不支持同时指定日和周字段，其中一个必须为'?'")
+		}
+	}
+	if err := s.parseDayOfMonth(domField); err != nil {
+		return nil, err
+	}
+	if err := s.parseDayOfWeek(dowField); err != nil {
+		return nil, err
+	}
+
+	if len(fields) == 7 && fields[6] != "*" && fields[6] != "?" {
+		if s.years, err = parseYearField(fields[6]); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// parseDayOfMonth 解析日字段
+func (s *Schedule) parseDayOfMonth(field string) error {
+	switch {
+	case field == "?" || field == "*":
+		s.domAny = true
+		return nil
+	case field == "L":
+		s.domLast = true
+		return nil
+	case strings.HasPrefix(field, "L-"):
+		n, err := strconv.Atoi(field[2:])
+		if err != nil || n < 0 || n > 30 {
+			return fmt.Errorf("日字段的L偏移量无效: %s", field)
+		}
+		s.domLast, s.domLastOffset = true, n
+		return nil
+	case field == "LW" || field == "WL":
+		s.domLastWeekday = true
+		return nil
+	case strings.HasSuffix(field, "W"):
+		n, err := strconv.Atoi(strings.TrimSuffix(field, "W"))
+		if err != nil || n < 1 || n > 31 {
+			return fmt.Errorf("日字段的W只能跟在单个日期后面: %s", field)
+		}
+		s.domWeekday = n
+		return nil
+	}
+
+	bits, err := parseBitField(field, domBounds)
+	if err != nil {
+		return err
+	}
+	s.dom = bits
+	return nil
+}
+
+// parseDayOfWeek 解析周字段
+func (s *Schedule) parseDayOfWeek(field string) error {
+	switch {
+	case field == "?" || field == "*":
+		s.dowAny = true
+		return nil
+	case field == "L":
+		// 单独的L表示周六
+		s.dow = 1 << 7
+		return nil
+	case strings.HasSuffix(field, "L"):
+		n, err := parseValue(strings.TrimSuffix(field, "L"), dowBounds)
+		if err != nil {
+			return err
+		}
+		s.dowLast = n
+		return nil
+	case strings.Contains(field, "#"):
+		parts := strings.SplitN(field, "#", 2)
+		n, err := parseValue(parts[0], dowBounds)
+		if err != nil {
+			return err
+		}
+		m, err := strconv.Atoi(parts[1])
+		if err != nil || m < 1 || m > 5 {
+			return fmt.Errorf("周字段#后面的序号必须在1-5之间: %s", field)
+		}
+		s.dowNth, s.dowNthM = n, m
+		return nil
+	}
+
+	bits, err := parseBitField(field, dowBounds)
+	if err != nil {
+		return err
+	}
+	s.dow = bits
+	return nil
+}
+
+// parseBitField 解析由逗号分隔的列表、范围和步长组成的字段
+func parseBitField(field string, bounds fieldBounds) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(strings.ToUpper(field), ",") {
+		start, end, step, err := parseRange(part, bounds)
+		if err != nil {
+			return 0, err
+		}
+		// 起始值大于结束值时为跨越边界的范围，例如 FRI-MON 或 22-2
+		size := bounds.max - bounds.min + 1
+		count := end - start + 1
+		if start > end {
+			count += size
+		}
+		for i := 0; i < count; i += step {
+			v := start + i
+			if v > bounds.max {
+				v -= size
+			}
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// parseYearField 解析年字段
+func parseYearField(field string) ([]bool, error) {
+	years := make([]bool, MaxYear-MinYear+1)
+	for _, part := range strings.Split(field, ",") {
+		start, end, step, err := parseRange(part, yearBounds)
+		if err != nil {
+			return nil, err
+		}
+		if start > end {
+			return nil, fmt.Errorf("年字段范围无效: %s", part)
+		}
+		for v := start; v <= end; v += step {
+			years[v-MinYear] = true
+		}
+	}
+	return years, nil
+}
+
+// parseRange 解析 *、n、n-m、*/s、n/s、n-m/s 形式的表达式
+func parseRange(part string, bounds fieldBounds) (start, end, step int, err error) {
+	if part == "" {
+		return 0, 0, 0, fmt.Errorf("%s字段存在空值", bounds.name)
+	}
+
+	step = 1
+	rangePart := part
+	if i := strings.Index(part, "/"); i != -1 {
+		rangePart = part[:i]
+		step, err = strconv.Atoi(part[i+1:])
+		if err != nil || step <= 0 || step > bounds.max-bounds.min+1 {
+			return 0, 0, 0, fmt.Errorf("%s字段步长无效: %s", bounds.name, part)
+		}
+	}
+
+	switch {
+	case rangePart == "*" || rangePart == "?":
+		if rangePart == "?" && part != "?" {
+			return 0, 0, 0, fmt.Errorf("%s字段的'?'不能带步长: %s", bounds.name, part)
+		}
+		return bounds.min, bounds.max, step, nil
+	case strings.Contains(rangePart, "-"):
+		ends := strings.SplitN(rangePart, "-", 2)
+		if start, err = parseValue(ends[0], bounds); err != nil {
+			return 0, 0, 0, err
+		}
+		if end, err = parseValue(ends[1], bounds); err != nil {
+			return 0, 0, 0, err
+		}
+		return start, end, step, nil
+	default:
+		if start, err = parseValue(rangePart, bounds); err != nil {
+			return 0, 0, 0, err
+		}
+		end = start
+		// n/s 表示从n开始到最大值每隔s
+		if strings.Contains(part, "/") {
+			end = bounds.max
+		}
+		return start, end, step, nil
+	}
+}
+
+// parseValue 解析单个数值或名称
+func parseValue(value string, bounds fieldBounds) (int, error) {
+	if bounds.names != nil {
+		if v, ok := bounds.names[strings.ToUpper(value)]; ok {
+			return v, nil
+		}
+	}
+	v, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s字段的值无效: %s", bounds.name, value)
+	}
+	if v < bounds.min || v > bounds.max {
+		return 0, fmt.Errorf("%s字段的值%d超出范围[%d,%d]", bounds.name, v, bounds.min, bounds.max)
+	}
+	return v, nil
+}
+
+// String 返回原始表达式
+func (s *Schedule) String() string {
+	return s.expression
+}
+
+// Location 返回计算执行时间使用的时区
+func (s *Schedule) Location() *time.Location {
+	return s.location
+}
+
+// Next 返回严格晚于t的下一次执行时间（以t的时区返回），没有下一次执行时返回零值
+// 按计划时区的墙上时间查找，与Quartz一致：夏令时开始时跳过的时刻在跳过后的第一个时刻执行，夏令时结束时重复的时间段只执行一次
+func (s *Schedule) Next(t time.Time) time.Time {
+	if s.every > 0 {
+		return t.Add(s.every - time.Duration(t.Nanosecond())*time.Nanosecond)
+	}
+
+	origLoc := t.Location()
+	t = t.In(s.location)
+	w := wallClock(t).Add(time.Second)
+
+	for w.Year() <= MaxYear {
+		if !s.matchYear(w.Year()) {
+			next := s.nextYear(w.Year())
+			if next == 0 {
+				return time.Time{}
+			}
+			w = time.Date(next, time.January, 1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if s.month&(1<<uint(w.Month())) == 0 {
+			w = time.Date(w.Year(), w.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !s.matchDay(w) {
+			w = time.Date(w.Year(), w.Month(), w.Day()+1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if s.hour&(1<<uint(w.Hour())) == 0 {
+			w = w.Truncate(time.Hour).Add(time.Hour)
+			continue
+		}
+		if s.minute&(1<<uint(w.Minute())) == 0 {
+			w = w.Truncate(time.Minute).Add(time.Minute)
+			continue
+		}
+		if s.second&(1<<uint(w.Second())) == 0 {
+			w = w.Add(time.Second)
+			continue
+		}
+		return s.instantOf(w, t).In(origLoc)
+	}
+	return time.Time{}
+}
+
+// wallClock 时间在其时区中的墙上时间，以UTC表示，按墙上时间推进不受夏令时切换影响
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+}
+
+// instantOf 墙上时间wall在计划时区中晚于after的最早时刻
+// 墙上时间重复时（夏令时结束）取晚于after的第一次；不存在时（夏令时开始）返回跳过后的第一个时刻
+func (s *Schedule) instantOf(wall, after time.Time) time.Time {
+	// time.Date遇到不存在的墙上时间会换算到跳过之后，遇到重复的墙上时间不保证取哪一次，切换前后的偏移都试一遍
+	guess := time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), 0, s.location)
+	start, end := guess.ZoneBounds()
+	probes := []time.Time{guess}
+	if !start.IsZero() {
+		probes = append(probes, start.Add(-time.Nanosecond))
+	}
+	if !end.IsZero() {
+		probes = append(probes, end)
+	}
+
+	var earliest time.Time
+	for _, probe := range probes {
+		_, offset := probe.Zone()
+		candidate := time.Unix(wall.Unix()-int64(offset), 0).In(s.location)
+		if wallClock(candidate).Equal(wall) && candidate.After(after) && (earliest.IsZero() || candidate.Before(earliest)) {
+			earliest = candidate
+		}
+	}
+	if !earliest.IsZero() {
+		return earliest
+	}
+	// 墙上时间落在夏令时开始跳过的时间段中，取切换的时刻
+	if wallClock(guess).After(wall) {
+		return start
+	}
+	return end
+}
+
+// matchYear 判断年份是否匹配
+func (s *Schedule) matchYear(year int) bool {
+	if year < MinYear || year > MaxYear {
+		return false
+	}
+	return s.years == nil || s.years[year-MinYear]
+}
+
+// nextYear 返回晚于year的下一个匹配年份，没有时返回0
+func (s *Schedule) nextYear(year int) int {
+	for y := year + 1; y <= MaxYear; y++ {
+		if s.matchYear(y) {
+			return y
+		}
+	}
+	return 0
+}
+
+// matchDay 判断日期是否满足日和周字段
+func (s *Schedule) matchDay(t time.Time) bool {
+	return s.matchDayOfMonth(t) && s.matchDayOfWeek(t)
+}
+
+// matchDayOfMonth 判断日期是否满足日字段
+func (s *Schedule) matchDayOfMonth(t time.Time) bool {
+	day := t.Day()
+	lastDay := daysIn(t.Year(), t.Month())
+
+	switch {
+	case s.domAny:
+		return true
+	case s.domLast:
+		return day == lastDay-s.domLastOffset
+	case s.domLastWeekday:
+		return day == nearestWeekday(t.Year(), t.Month(), lastDay, s.location)
+	case s.domWeekday > 0:
+		if s.domWeekday > lastDay {
+			return false
+		}
+		return day == nearestWeekday(t.Year(), t.Month(), s.domWeekday, s.location)
+	default:
+		return s.dom&(1<<uint(day)) != 0
+	}
+}
+
+// matchDayOfWeek 判断日期是否满足周字段
+func (s *Schedule) matchDayOfWeek(t time.Time) bool {
+	weekday := int(t.Weekday()) + 1 // Quartz中1表示周日
+
+	switch {
+	case s.dowAny:
+		return true
+	case s.dowLast > 0:
+		return weekday == s.dowLast && t.Day()+7 > daysIn(t.Year(), t.Month())
+	case s.dowNth > 0:
+		return weekday == s.dowNth && (t.Day()-1)/7+1 == s.dowNthM
+	default:
+		return s.dow&(1<<uint(weekday)) != 0
+	}
+}
+
+// daysIn 返回某月的天数
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// nearestWeekday 返回本月内距离day最近的工作日（周一至周五），不会跨月
+func nearestWeekday(year int, month time.Month, day int, loc *time.Location) int {
+	lastDay := daysIn(year, month)
+	switch time.Date(year, month, day, 0, 0, 0, 0, loc).Weekday() {
+	case time.Saturday:
+		if day == 1 {
+			return 3 // 1号是周六时取周一3号
+		}
+		return day - 1
+	case time.Sunday:
+		if day == lastDay {
+			return day - 2 // 月末是周日时取周五
+		}
+		return day + 1
+	default:
+		return day
+	}
+}
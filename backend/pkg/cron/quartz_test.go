@@ -0,0 +1,131 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// utc 构造UTC时间
+func utc(value string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02 15:04:05", value, time.UTC)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestScheduleNext(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		from       string
+		expected   []string
+	}{
+		{"每月最后一天", "0 0 12 L * ?", "2026-01-01 00:00:00",
+			[]string{"2026-01-31 12:00:00", "2026-02-28 12:00:00", "2026-03-31 12:00:00"}},
+		{"每月倒数第三天", "0 0 0 L-2 * ?", "2026-01-01 00:00:00",
+			[]string{"2026-01-29 00:00:00", "2026-02-26 00:00:00"}},
+		{"每月第三个周五", "0 15 10 ? * 6#3", "2026-01-01 00:00:00",
+			[]string{"2026-01-16 10:15:00", "2026-02-20 10:15:00", "2026-03-20 10:15:00"}},
+		{"每月最后一个周五", "0 0 10 ? * 6L", "2026-01-01 00:00:00",
+			[]string{"2026-01-30 10:00:00", "2026-02-27 10:00:00", "2026-03-27 10:00:00"}},
+		{"指定年份的工作日", "0 0 9 ? * MON-FRI 2027", "2026-01-01 00:00:00",
+			[]string{"2027-01-01 09:00:00", "2027-01-04 09:00:00"}},
+		{"最近的工作日", "0 0 9 15W * ?", "2026-01-01 00:00:00",
+			[]string{"2026-01-15 09:00:00", "2026-02-16 09:00:00", "2026-03-16 09:00:00"}},
+		{"1号为周六时不跨月", "0 0 9 1W * ?", "2026-07-15 00:00:00",
+			[]string{"2026-08-03 09:00:00"}},
+		{"每月最后一个工作日", "0 0 18 LW * ?", "2026-01-01 00:00:00",
+			[]string{"2026-01-30 18:00:00", "2026-02-27 18:00:00", "2026-03-31 18:00:00"}},
+		{"步长", "0 0/20 * * * ?", "2026-01-01 00:00:00",
+			[]string{"2026-01-01 00:20:00", "2026-01-01 00:40:00", "2026-01-01 01:00:00"}},
+		{"跨边界的周范围", "0 0 8 ? * FRI-MON", "2026-01-01 00:00:00",
+			[]string{"2026-01-02 08:00:00", "2026-01-03 08:00:00", "2026-01-04 08:00:00", "2026-01-05 08:00:00", "2026-01-09 08:00:00"}},
+		{"闰年2月29日", "0 0 0 29 2 ?", "2026-01-01 00:00:00",
+			[]string{"2028-02-29 00:00:00", "2032-02-29 00:00:00"}},
+		{"兼容日和周都为*", "0 30 * * * *", "2026-01-01 00:00:00",
+			[]string{"2026-01-01 00:30:00", "2026-01-01 01:30:00"}},
+		{"描述符", "@monthly", "2026-01-15 00:00:00",
+			[]string{"2026-02-01 00:00:00", "2026-03-01 00:00:00"}},
+		{"固定间隔", "@every 90m", "2026-01-01 00:00:00",
+			[]string{"2026-01-01 01:30:00", "2026-01-01 03:00:00"}},
+		{"年份已过期", "0 0 0 1 1 ? 2025", "2026-01-01 00:00:00", nil},
+		{"上海时区", "TZ=Asia/Shanghai 0 0 9 * * ?", "2026-01-01 00:00:00",
+			[]string{"2026-01-01 01:00:00", "2026-01-02 01:00:00"}},
+		{"夏令时开始时不存在的时刻在跳过后执行", "CRON_TZ=America/New_York 0 30 2 * * ?", "2026-03-07 12:00:00",
+			[]string{"2026-03-08 07:00:00", "2026-03-09 06:30:00", "2026-03-10 06:30:00"}},
+		{"夏令时开始时跳过的多个时刻只执行一次", "CRON_TZ=America/New_York 0 0/20 * * * ?", "2026-03-08 06:30:00",
+			[]string{"2026-03-08 06:40:00", "2026-03-08 07:00:00", "2026-03-08 07:20:00"}},
+		{"夏令时结束时重复的时刻只执行一次", "CRON_TZ=America/New_York 0 30 1 * * ?", "2026-10-31 12:00:00",
+			[]string{"2026-11-01 05:30:00", "2026-11-02 06:30:00"}},
+		{"夏令时结束时重复的时间段不再执行", "CRON_TZ=America/New_York 0 0/30 * * * ?", "2026-11-01 05:00:00",
+			[]string{"2026-11-01 05:30:00", "2026-11-01 07:00:00", "2026-11-01 07:30:00"}},
+		{"从重复时间段的第二次开始", "CRON_TZ=America/New_York 0 30 1 * * ?", "2026-11-01 06:10:00",
+			[]string{"2026-11-01 06:30:00", "2026-11-02 06:30:00"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseInLocation(tt.expression, time.UTC)
+			require.NoError(t, err)
+
+			var actual []string
+			next := utc(tt.from)
+			for range tt.expected {
+				next = schedule.Next(next)
+				if next.IsZero() {
+					break
+				}
+				actual = append(actual, next.UTC().Format("2006-01-02 15:04:05"))
+			}
+			if tt.expected == nil {
+				assert.True(t, schedule.Next(utc(tt.from)).IsZero())
+				return
+			}
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+	}{
+		{"空表达式", ""},
+		{"字段数量不足", "* * * *"},
+		{"日和周同时为?", "0 0 12 ? * ?"},
+		{"同时指定日和周", "0 0 12 1 * MON"},
+		{"小时超出范围", "0 0 25 * * ?"},
+		{"L偏移量超出范围", "0 0 12 L-31 * ?"},
+		{"第几个周几超出范围", "0 0 12 ? * 2#6"},
+		{"W用于范围", "0 0 12 1-5W * ?"},
+		{"未知的周名称", "0 0 12 ? * FOO"},
+		{"年份超出范围", "0 0 12 * * ? 1969"},
+		{"步长为0", "0/0 * * * * ?"},
+		{"未知时区", "TZ=Mars/Base 0 0 * * * ?"},
+		{"未知描述符", "@fortnightly"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.expression)
+			assert.Error(t, err)
+			assert.False(t, IsValid(tt.expression))
+		})
+	}
+}
+
+func TestGetNextExecutionsFrom(t *testing.T) {
+	executions, err := GetNextExecutionsFrom("TZ=UTC 0 0 0 1 1 ? 2026-2027", utc("2025-06-01 00:00:00"), 5)
+	require.NoError(t, err)
+	require.Len(t, executions, 2)
+	assert.Equal(t, utc("2026-01-01 00:00:00"), executions[0].UTC())
+	assert.Equal(t, utc("2027-01-01 00:00:00"), executions[1].UTC())
+
+	_, err = GetNextExecutionsFrom("0 0 0 * * ?", time.Now(), 0)
+	assert.Error(t, err)
+}
@@ -4,9 +4,13 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 	"wosm/internal/config"
 
+	"github.com/glebarez/sqlite"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/driver/sqlserver"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -18,14 +22,11 @@ var DB *gorm.DB
 func InitDatabase() error {
 	cfg := config.AppConfig.Database
 
-	// 构建SQL Server连接字符串
-	dsn := fmt.Sprintf("sqlserver://%s:%s@%s:%d?database=%s&encrypt=disable&trustServerCertificate=true",
-		cfg.Username,
-		cfg.Password,
-		cfg.Host,
-		cfg.Port,
-		cfg.Database,
-	)
+	// 根据配置的驱动构建连接
+	dialector, err := openDialector(&cfg)
+	if err != nil {
+		return err
+	}
 
 	// 配置GORM日志级别
 	var logLevel logger.LogLevel
@@ -39,7 +40,7 @@ func InitDatabase() error {
 	}
 
 	// 连接数据库
-	db, err := gorm.Open(sqlserver.Open(dsn), &gorm.Config{
+	db, err := gorm.Open(dialector, &gorm.Config{
 		Logger: logger.Default.LogMode(logLevel),
 		NowFunc: func() time.Time {
 			return time.Now().Local()
@@ -49,6 +50,13 @@ func InitDatabase() error {
 		return fmt.Errorf("连接数据库失败: %v", err)
 	}
 
+	// 注册SQL统计插件 对应Java后端Druid的StatFilter
+	Stats.Reset()
+	Stats.Configure(cfg.SlowSQLMillis, cfg.RepeatSQLThreshold)
+	if err := db.Use(Stats); err != nil {
+		return fmt.Errorf("注册SQL统计插件失败: %v", err)
+	}
+
 	// 获取底层sql.DB对象配置连接池
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -60,6 +68,13 @@ func InitDatabase() error {
 	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)                                    // 最大打开连接数
 	sqlDB.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetime) * time.Second) // 连接最大生存时间
 	sqlDB.SetConnMaxIdleTime(time.Duration(cfg.ConnMaxIdleTime) * time.Second) // 连接最大空闲时间
+	if dialector.Name() == DriverSQLite {
+		// SQLite同一时间只允许一个写入，内存数据库的每个连接还是各自独立的库，只保留一个连接
+		sqlDB.SetMaxOpenConns(1)
+		sqlDB.SetMaxIdleConns(1)
+		sqlDB.SetConnMaxLifetime(0)
+		sqlDB.SetConnMaxIdleTime(0)
+	}
 
 	// 测试数据库连接 对应Java后端的validationQuery
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.PingTimeout)*time.Second)
@@ -69,27 +84,99 @@ func InitDatabase() error {
 		return fmt.Errorf("数据库连接测试失败: %v", err)
 	}
 
-	DB = db
-	log.Printf("数据库连接成功: %s:%d/%s", cfg.Host, cfg.Port, cfg.Database)
+	// 注册读写分离插件，配置了只读副本时查询读副本
+	if len(cfg.Replicas) > 0 {
+		resolver, err := NewReplicaResolver(dialector.Name(), &cfg)
+		if err != nil {
+			return err
+		}
+		if err := db.Use(resolver); err != nil {
+			return fmt.Errorf("注册读写分离插件失败: %v", err)
+		}
+		Resolver = resolver
+	}
 
-	// 自动迁移表结构（开发环境）
-	// 注释掉自动迁移，因为数据库表已存在且结构正确
-	// if err := autoMigrate(); err != nil {
-	//	log.Printf("数据库表结构迁移警告: %v", err)
-	// }
+	DB = db
+	if dialector.Name() == DriverSQLite {
+		log.Printf("数据库连接成功: sqlite %s", cfg.Database)
+	} else {
+		log.Printf("数据库连接成功: %s %s:%d/%s", dialector.Name(), cfg.Host, cfg.Port, cfg.Database)
+	}
 
 	return nil
 }
 
-// autoMigrate 自动迁移表结构（已禁用，数据库表已存在）
-// func autoMigrate() error {
-//	return DB.AutoMigrate(
-//		&model.SysUser{},
-//		&model.SysRole{},
-//		&model.SysDept{},
-//		&model.SysMenu{},
-//	)
-// }
+// openDialector 根据 database.driver 构建GORM驱动，driver 为空时使用SQL Server
+// SQLite的 database 配置为数据库文件路径（:memory: 表示内存数据库），不需要主机和账号
+func openDialector(cfg *config.DatabaseConfig) (gorm.Dialector, error) {
+	charset := cfg.Charset
+	if charset == "" || charset == "utf8" {
+		charset = "utf8mb4"
+	}
+
+	var dsn string
+	switch cfg.Driver {
+	case "", DriverSqlServer:
+		dsn = fmt.Sprintf("sqlserver://%s:%s@%s:%d?database=%s&encrypt=disable&trustServerCertificate=true",
+			cfg.Username,
+			cfg.Password,
+			cfg.Host,
+			cfg.Port,
+			cfg.Database,
+		)
+	case DriverMySQL:
+		dsn = fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=%s&parseTime=True&loc=Local",
+			cfg.Username,
+			cfg.Password,
+			cfg.Host,
+			cfg.Port,
+			cfg.Database,
+			charset,
+		)
+	case DriverPostgres:
+		dsn = fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
+			cfg.Host,
+			cfg.Port,
+			postgresValue(cfg.Username),
+			postgresValue(cfg.Password),
+			postgresValue(cfg.Database),
+		)
+	case DriverSQLite:
+		if cfg.Database == "" {
+			return nil, fmt.Errorf("SQLite数据库文件路径不能为空")
+		}
+		dsn = cfg.Database
+	}
+	return dialectorFromDSN(cfg.Driver, dsn)
+}
+
+// dialectorFromDSN 根据驱动和连接串构建GORM驱动，只读副本直接配置连接串
+func dialectorFromDSN(driver, dsn string) (gorm.Dialector, error) {
+	switch driver {
+	case "", DriverSqlServer:
+		return sqlserver.Open(dsn), nil
+	case DriverMySQL:
+		return mysql.Open(dsn), nil
+	case DriverPostgres:
+		return postgres.Open(dsn), nil
+	case DriverSQLite:
+		return sqlite.Open(sqliteDSN(dsn)), nil
+	}
+	return nil, fmt.Errorf("不支持的数据库类型: %s，可选值: sqlserver、mysql、postgres、sqlite", driver)
+}
+
+// sqliteDSN SQLite数据库文件路径加上连接参数：开启外键约束，写入时等待锁而不是立即失败
+func sqliteDSN(path string) string {
+	return path + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+}
+
+// postgresValue 转义PostgreSQL连接串中的值
+func postgresValue(value string) string {
+	if value == "" {
+		return "''"
+	}
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
 
 // GetDB 获取数据库连接
 func GetDB() *gorm.DB {
@@ -98,6 +185,9 @@ func GetDB() *gorm.DB {
 
 // Close 关闭数据库连接
 func Close() error {
+	if Resolver != nil {
+		Resolver.Close()
+	}
 	if DB != nil {
 		sqlDB, err := DB.DB()
 		if err != nil {
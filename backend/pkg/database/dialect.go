@@ -0,0 +1,442 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+	"wosm/internal/config"
+)
+
+// 支持的数据库驱动，与 database.driver 配置一致
+const (
+	DriverSqlServer = "sqlserver"
+	DriverMySQL     = "mysql"
+	DriverPostgres  = "postgres"
+	DriverSQLite    = "sqlite"
+)
+
+// Dialect 数据库方言，封装DAO中无法写成通用SQL的片段
+type Dialect interface {
+	// Name 方言名称，与 database.driver 配置一致
+	Name() string
+	// Quote 引用标识符（表名、列名）
+	Quote(name string) string
+	// TableName 业务表的完整表名（SQL Server带dbo架构）
+	TableName(name string) string
+	// FindInSet 逗号分隔的列表字段column中包含value的条件，对应MySQL的find_in_set；value为SQL表达式（参数占位符或字面量）
+	FindInSet(value, column string) string
+	// Paginate 分页子句及其参数，追加在 ORDER BY 之后
+	Paginate(offset, limit int) (string, []interface{})
+	// Update 带别名的单表修改语句（不含WHERE），columns为要修改的列，值按顺序通过参数传递
+	Update(table, alias string, columns []string) string
+	// Delete 带别名的单表删除语句（不含WHERE）
+	Delete(table, alias string) string
+	// Truncate 清空表的语句
+	Truncate(table string) string
+	// TablesQuery 代码生成读取当前库业务表的查询，返回 table_name、table_comment、create_time、update_time 列
+	TablesQuery() string
+	// ColumnsQuery 代码生成读取表字段的查询，返回 column_name、column_comment、data_type、column_type、column_key、extra 列，按字段顺序排序
+	ColumnsQuery(tableName string) (string, []interface{})
+	// Now 当前时间的SQL表达式，用于代码生成的初始化脚本
+	Now() string
+	// StringLiteral 字符串常量（转义引号，SQL Server带N前缀保留中文）
+	StringLiteral(value string) string
+	// InsertIDVar 在脚本中保存上一条插入语句生成的自增ID，name为变量名
+	InsertIDVar(name string) InsertIDVar
+}
+
+// InsertIDVar 脚本中保存自增ID的变量，之后的插入语句生成新的ID也不影响已保存的值
+type InsertIDVar struct {
+	Declare string // 保存上一条插入语句生成的自增ID的语句
+	Ref     string // 引用保存的ID的表达式
+	Drop    string // 用完后的清理语句，不需要清理时为空
+}
+
+var dialects = map[string]Dialect{
+	DriverSqlServer: sqlServerDialect{},
+	DriverMySQL:     mysqlDialect{},
+	DriverPostgres:  postgresDialect{},
+	DriverSQLite:    sqliteDialect{},
+}
+
+// GetDialect 根据数据库驱动获取方言，driver 为空时使用SQL Server
+func GetDialect(driver string) (Dialect, error) {
+	if driver == "" {
+		driver = DriverSqlServer
+	}
+	dialect, ok := dialects[strings.ToLower(driver)]
+	if !ok {
+		return nil, fmt.Errorf("不支持的数据库类型: %s", driver)
+	}
+	return dialect, nil
+}
+
+// CurrentDialect 当前数据库连接的方言；未连接数据库时按配置的驱动，配置无效时使用SQL Server
+func CurrentDialect() Dialect {
+	if DB != nil {
+		if dialect, err := GetDialect(DB.Dialector.Name()); err == nil {
+			return dialect
+		}
+	}
+	if config.AppConfig != nil {
+		if dialect, err := GetDialect(config.AppConfig.Database.Driver); err == nil {
+			return dialect
+		}
+	}
+	return sqlServerDialect{}
+}
+
+// quoteString 单引号字符串常量，引号写两次
+func quoteString(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// tempTableInsertID 没有脚本变量的数据库把自增ID保存到临时表
+func tempTableInsertID(name, lastID string) InsertIDVar {
+	return InsertIDVar{
+		Declare: fmt.Sprintf("CREATE TEMPORARY TABLE %s AS SELECT %s AS id;", name, lastID),
+		Ref:     fmt.Sprintf("(SELECT id FROM %s)", name),
+		Drop:    fmt.Sprintf("DROP TABLE %s;", name),
+	}
+}
+
+// quoteColumns 引用并拼接 SET 子句的列，prefix为列名前缀（SQL Server、MySQL使用表别名）
+func quoteColumns(dialect Dialect, prefix string, columns []string) string {
+	sets := make([]string, len(columns))
+	for i, column := range columns {
+		sets[i] = prefix + dialect.Quote(column) + " = ?"
+	}
+	return strings.Join(sets, ", ")
+}
+
+// sqlServerDialect SQL Server方言，业务表建在dbo架构下，注释保存为 MS_Description 扩展属性
+type sqlServerDialect struct{}
+
+func (sqlServerDialect) Name() string {
+	return DriverSqlServer
+}
+
+func (sqlServerDialect) Quote(name string) string {
+	return "[" + strings.ReplaceAll(name, "]", "]]") + "]"
+}
+
+func (d sqlServerDialect) TableName(name string) string {
+	return "[dbo]." + d.Quote(name)
+}
+
+func (sqlServerDialect) Now() string {
+	return "GETDATE()"
+}
+
+func (sqlServerDialect) StringLiteral(value string) string {
+	return "N" + quoteString(value)
+}
+
+func (sqlServerDialect) InsertIDVar(name string) InsertIDVar {
+	return InsertIDVar{Declare: fmt.Sprintf("DECLARE @%s BIGINT = SCOPE_IDENTITY();", name), Ref: "@" + name}
+}
+
+func (sqlServerDialect) FindInSet(value, column string) string {
+	return fmt.Sprintf("CHARINDEX(',' + %s + ',', ',' + ISNULL(%s, '') + ',') > 0", value, column)
+}
+
+func (sqlServerDialect) Paginate(offset, limit int) (string, []interface{}) {
+	return " OFFSET ? ROWS FETCH NEXT ? ROWS ONLY", []interface{}{offset, limit}
+}
+
+func (d sqlServerDialect) Update(table, alias string, columns []string) string {
+	return fmt.Sprintf("UPDATE %s SET %s FROM %s %s", alias, quoteColumns(d, alias+".", columns), d.TableName(table), alias)
+}
+
+func (d sqlServerDialect) Delete(table, alias string) string {
+	return fmt.Sprintf("DELETE %s FROM %s %s", alias, d.TableName(table), alias)
+}
+
+func (d sqlServerDialect) Truncate(table string) string {
+	return "TRUNCATE TABLE " + d.Quote(table)
+}
+
+func (sqlServerDialect) TablesQuery() string {
+	return `
+		SELECT
+			t.TABLE_NAME AS table_name,
+			CAST(ISNULL(ep.value, '') AS NVARCHAR(500)) AS table_comment,
+			GETDATE() AS create_time,
+			GETDATE() AS update_time
+		FROM INFORMATION_SCHEMA.TABLES t
+		LEFT JOIN sys.extended_properties ep ON ep.major_id = OBJECT_ID(t.TABLE_SCHEMA + '.' + t.TABLE_NAME)
+			AND ep.minor_id = 0 AND ep.name = 'MS_Description'
+		WHERE t.TABLE_TYPE = 'BASE TABLE'
+			AND t.TABLE_SCHEMA = 'dbo'
+			AND t.TABLE_NAME NOT IN ('sysdiagrams')
+	`
+}
+
+func (sqlServerDialect) ColumnsQuery(tableName string) (string, []interface{}) {
+	return `
+		SELECT
+			c.COLUMN_NAME AS column_name,
+			CAST(ISNULL(ep.value, '') AS NVARCHAR(500)) AS column_comment,
+			c.DATA_TYPE AS data_type,
+			c.DATA_TYPE +
+				CASE
+					WHEN c.CHARACTER_MAXIMUM_LENGTH IS NOT NULL
+					THEN '(' + CAST(c.CHARACTER_MAXIMUM_LENGTH AS VARCHAR) + ')'
+					WHEN c.NUMERIC_PRECISION IS NOT NULL AND c.NUMERIC_SCALE IS NOT NULL
+					THEN '(' + CAST(c.NUMERIC_PRECISION AS VARCHAR) + ',' + CAST(c.NUMERIC_SCALE AS VARCHAR) + ')'
+					ELSE ''
+				END AS column_type,
+			CASE WHEN pk.COLUMN_NAME IS NOT NULL THEN 'PRI' ELSE '' END AS column_key,
+			CASE WHEN COLUMNPROPERTY(OBJECT_ID(c.TABLE_SCHEMA + '.' + c.TABLE_NAME), c.COLUMN_NAME, 'IsIdentity') = 1 THEN 'auto_increment' ELSE '' END AS extra
+		FROM INFORMATION_SCHEMA.COLUMNS c
+		LEFT JOIN INFORMATION_SCHEMA.KEY_COLUMN_USAGE pk ON pk.TABLE_NAME = c.TABLE_NAME
+			AND pk.COLUMN_NAME = c.COLUMN_NAME
+			AND pk.CONSTRAINT_NAME LIKE 'PK_%'
+		LEFT JOIN sys.extended_properties ep ON ep.major_id = OBJECT_ID(c.TABLE_SCHEMA + '.' + c.TABLE_NAME)
+			AND ep.minor_id = c.ORDINAL_POSITION AND ep.name = 'MS_Description'
+		WHERE c.TABLE_NAME = ? AND c.TABLE_SCHEMA = 'dbo'
+		ORDER BY c.ORDINAL_POSITION
+	`, []interface{}{tableName}
+}
+
+// mysqlDialect MySQL方言，对应若依原版的Mapper SQL
+type mysqlDialect struct{}
+
+func (mysqlDialect) Name() string {
+	return DriverMySQL
+}
+
+func (mysqlDialect) Quote(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+func (d mysqlDialect) TableName(name string) string {
+	return d.Quote(name)
+}
+
+func (mysqlDialect) Now() string {
+	return "sysdate()"
+}
+
+func (mysqlDialect) StringLiteral(value string) string {
+	return quoteString(strings.ReplaceAll(value, `\`, `\\`))
+}
+
+func (mysqlDialect) InsertIDVar(name string) InsertIDVar {
+	return InsertIDVar{Declare: fmt.Sprintf("SET @%s = LAST_INSERT_ID();", name), Ref: "@" + name}
+}
+
+func (mysqlDialect) FindInSet(value, column string) string {
+	return fmt.Sprintf("FIND_IN_SET(%s, %s) > 0", value, column)
+}
+
+func (mysqlDialect) Paginate(offset, limit int) (string, []interface{}) {
+	return " LIMIT ? OFFSET ?", []interface{}{limit, offset}
+}
+
+func (d mysqlDialect) Update(table, alias string, columns []string) string {
+	return fmt.Sprintf("UPDATE %s %s SET %s", d.TableName(table), alias, quoteColumns(d, alias+".", columns))
+}
+
+func (d mysqlDialect) Delete(table, alias string) string {
+	return fmt.Sprintf("DELETE %s FROM %s %s", alias, d.TableName(table), alias)
+}
+
+func (d mysqlDialect) Truncate(table string) string {
+	return "TRUNCATE TABLE " + d.Quote(table)
+}
+
+func (mysqlDialect) TablesQuery() string {
+	return `
+		SELECT
+			table_name AS table_name,
+			table_comment AS table_comment,
+			create_time AS create_time,
+			COALESCE(update_time, create_time) AS update_time
+		FROM information_schema.tables
+		WHERE table_schema = (SELECT DATABASE())
+			AND table_type = 'BASE TABLE'
+	`
+}
+
+func (mysqlDialect) ColumnsQuery(tableName string) (string, []interface{}) {
+	return `
+		SELECT
+			column_name AS column_name,
+			column_comment AS column_comment,
+			data_type AS data_type,
+			column_type AS column_type,
+			column_key AS column_key,
+			extra AS extra
+		FROM information_schema.columns
+		WHERE table_schema = (SELECT DATABASE()) AND table_name = ?
+		ORDER BY ordinal_position
+	`, []interface{}{tableName}
+}
+
+// postgresDialect PostgreSQL方言，业务表建在当前schema下，注释通过 COMMENT ON 保存
+type postgresDialect struct{}
+
+func (postgresDialect) Name() string {
+	return DriverPostgres
+}
+
+func (postgresDialect) Quote(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func (d postgresDialect) TableName(name string) string {
+	return d.Quote(name)
+}
+
+func (postgresDialect) Now() string {
+	return "now()"
+}
+
+func (postgresDialect) StringLiteral(value string) string {
+	return quoteString(value)
+}
+
+func (postgresDialect) InsertIDVar(name string) InsertIDVar {
+	return tempTableInsertID(name, "lastval()")
+}
+
+func (postgresDialect) FindInSet(value, column string) string {
+	return fmt.Sprintf("CAST(%s AS VARCHAR) = ANY(STRING_TO_ARRAY(%s, ','))", value, column)
+}
+
+func (postgresDialect) Paginate(offset, limit int) (string, []interface{}) {
+	return " LIMIT ? OFFSET ?", []interface{}{limit, offset}
+}
+
+// Update PostgreSQL的 SET 子句不能带表别名
+func (d postgresDialect) Update(table, alias string, columns []string) string {
+	return fmt.Sprintf("UPDATE %s AS %s SET %s", d.TableName(table), alias, quoteColumns(d, "", columns))
+}
+
+func (d postgresDialect) Delete(table, alias string) string {
+	return fmt.Sprintf("DELETE FROM %s AS %s", d.TableName(table), alias)
+}
+
+func (d postgresDialect) Truncate(table string) string {
+	return "TRUNCATE TABLE " + d.Quote(table) + " RESTART IDENTITY"
+}
+
+func (postgresDialect) TablesQuery() string {
+	return `
+		SELECT
+			t.table_name AS table_name,
+			COALESCE(obj_description(CAST(quote_ident(t.table_schema) || '.' || quote_ident(t.table_name) AS regclass), 'pg_class'), '') AS table_comment,
+			CURRENT_TIMESTAMP AS create_time,
+			CURRENT_TIMESTAMP AS update_time
+		FROM information_schema.tables t
+		WHERE t.table_schema = current_schema()
+			AND t.table_type = 'BASE TABLE'
+	`
+}
+
+func (postgresDialect) ColumnsQuery(tableName string) (string, []interface{}) {
+	return `
+		SELECT
+			c.column_name AS column_name,
+			COALESCE(col_description(CAST(quote_ident(c.table_schema) || '.' || quote_ident(c.table_name) AS regclass), CAST(c.ordinal_position AS INTEGER)), '') AS column_comment,
+			c.data_type AS data_type,
+			CASE
+				WHEN c.character_maximum_length IS NOT NULL
+				THEN c.data_type || '(' || c.character_maximum_length || ')'
+				WHEN c.data_type = 'numeric' AND c.numeric_precision IS NOT NULL
+				THEN c.data_type || '(' || c.numeric_precision || ',' || c.numeric_scale || ')'
+				ELSE c.data_type
+			END AS column_type,
+			CASE WHEN pk.column_name IS NOT NULL THEN 'PRI' ELSE '' END AS column_key,
+			CASE WHEN c.is_identity = 'YES' OR c.column_default LIKE 'nextval(%' THEN 'auto_increment' ELSE '' END AS extra
+		FROM information_schema.columns c
+		LEFT JOIN (
+			SELECT k.table_schema, k.table_name, k.column_name
+			FROM information_schema.table_constraints tc
+			JOIN information_schema.key_column_usage k ON k.constraint_schema = tc.constraint_schema
+				AND k.constraint_name = tc.constraint_name
+			WHERE tc.constraint_type = 'PRIMARY KEY'
+		) pk ON pk.table_schema = c.table_schema AND pk.table_name = c.table_name AND pk.column_name = c.column_name
+		WHERE c.table_schema = current_schema() AND c.table_name = ?
+		ORDER BY c.ordinal_position
+	`, []interface{}{tableName}
+}
+
+// sqliteDialect SQLite方言，用于本地开发和测试；SQLite没有表和列注释
+type sqliteDialect struct{}
+
+func (sqliteDialect) Name() string {
+	return DriverSQLite
+}
+
+func (sqliteDialect) Quote(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func (d sqliteDialect) TableName(name string) string {
+	return d.Quote(name)
+}
+
+func (sqliteDialect) Now() string {
+	return "datetime('now', 'localtime')"
+}
+
+func (sqliteDialect) StringLiteral(value string) string {
+	return quoteString(value)
+}
+
+func (sqliteDialect) InsertIDVar(name string) InsertIDVar {
+	return tempTableInsertID(name, "last_insert_rowid()")
+}
+
+func (sqliteDialect) FindInSet(value, column string) string {
+	return fmt.Sprintf("INSTR(',' || IFNULL(%s, '') || ',', ',' || %s || ',') > 0", column, value)
+}
+
+func (sqliteDialect) Paginate(offset, limit int) (string, []interface{}) {
+	return " LIMIT ? OFFSET ?", []interface{}{limit, offset}
+}
+
+// Update SQLite的 SET 子句不能带表别名
+func (d sqliteDialect) Update(table, alias string, columns []string) string {
+	return fmt.Sprintf("UPDATE %s AS %s SET %s", d.TableName(table), alias, quoteColumns(d, "", columns))
+}
+
+func (d sqliteDialect) Delete(table, alias string) string {
+	return fmt.Sprintf("DELETE FROM %s AS %s", d.TableName(table), alias)
+}
+
+// Truncate SQLite没有TRUNCATE，DELETE不带条件时会走清空表的优化
+func (d sqliteDialect) Truncate(table string) string {
+	return "DELETE FROM " + d.Quote(table)
+}
+
+func (sqliteDialect) TablesQuery() string {
+	return `
+		SELECT
+			name AS table_name,
+			'' AS table_comment,
+			datetime('now', 'localtime') AS create_time,
+			datetime('now', 'localtime') AS update_time
+		FROM sqlite_master
+		WHERE type = 'table'
+			AND name NOT LIKE 'sqlite_%'
+	`
+}
+
+// ColumnsQuery 整数单主键是rowid的别名，插入时自动生成
+func (sqliteDialect) ColumnsQuery(tableName string) (string, []interface{}) {
+	return `
+		SELECT
+			p.name AS column_name,
+			'' AS column_comment,
+			LOWER(CASE WHEN INSTR(p.type, '(') > 0 THEN SUBSTR(p.type, 1, INSTR(p.type, '(') - 1) ELSE p.type END) AS data_type,
+			LOWER(p.type) AS column_type,
+			CASE WHEN p.pk > 0 THEN 'PRI' ELSE '' END AS column_key,
+			CASE WHEN p.pk = 1 AND LOWER(p.type) = 'integer'
+				AND (SELECT COUNT(*) FROM pragma_table_info(?) WHERE pk > 0) = 1
+				THEN 'auto_increment' ELSE '' END AS extra
+		FROM pragma_table_info(?) p
+		ORDER BY p.cid
+	`, []interface{}{tableName, tableName}
+}
@@ -0,0 +1,150 @@
+package database
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"wosm/pkg/migrate"
+	"wosm/pkg/sqlitetest"
+)
+
+func TestGetDialect(t *testing.T) {
+	for driver, expected := range map[string]string{"": DriverSqlServer, "MySQL": DriverMySQL, "postgres": DriverPostgres, "sqlite": DriverSQLite} {
+		dialect, err := GetDialect(driver)
+		if err != nil {
+			t.Fatalf("GetDialect(%q) 返回错误: %v", driver, err)
+		}
+		if dialect.Name() != expected {
+			t.Errorf("GetDialect(%q) = %s, 期望 %s", driver, dialect.Name(), expected)
+		}
+	}
+	if _, err := GetDialect("oracle"); err == nil {
+		t.Error("不支持的数据库类型应返回错误")
+	}
+}
+
+func TestDialectStatements(t *testing.T) {
+	tests := []struct {
+		driver string
+		update string
+		delete string
+		page   string
+		args   []interface{}
+	}{
+		{DriverSqlServer, "UPDATE t SET t.[name] = ?, t.[status] = ? FROM [dbo].[demo] t", "DELETE t FROM [dbo].[demo] t", " OFFSET ? ROWS FETCH NEXT ? ROWS ONLY", []interface{}{20, 10}},
+		{DriverMySQL, "UPDATE `demo` t SET t.`name` = ?, t.`status` = ?", "DELETE t FROM `demo` t", " LIMIT ? OFFSET ?", []interface{}{10, 20}},
+		{DriverPostgres, `UPDATE "demo" AS t SET "name" = ?, "status" = ?`, `DELETE FROM "demo" AS t`, " LIMIT ? OFFSET ?", []interface{}{10, 20}},
+		{DriverSQLite, `UPDATE "demo" AS t SET "name" = ?, "status" = ?`, `DELETE FROM "demo" AS t`, " LIMIT ? OFFSET ?", []interface{}{10, 20}},
+	}
+	for _, tt := range tests {
+		dialect, _ := GetDialect(tt.driver)
+		if got := dialect.Update("demo", "t", []string{"name", "status"}); got != tt.update {
+			t.Errorf("%s Update = %s, 期望 %s", tt.driver, got, tt.update)
+		}
+		if got := dialect.Delete("demo", "t"); got != tt.delete {
+			t.Errorf("%s Delete = %s, 期望 %s", tt.driver, got, tt.delete)
+		}
+		page, args := dialect.Paginate(20, 10)
+		if page != tt.page || len(args) != 2 || args[0] != tt.args[0] || args[1] != tt.args[1] {
+			t.Errorf("%s Paginate = %s %v, 期望 %s %v", tt.driver, page, args, tt.page, tt.args)
+		}
+	}
+}
+
+// TestSQLiteDialect 在SQLite上执行方言生成的SQL
+func TestSQLiteDialect(t *testing.T) {
+	db := sqlitetest.Open(t, "")
+	migrator, err := migrate.New(db)
+	if err != nil {
+		t.Fatalf("创建迁移失败: %v", err)
+	}
+	if _, err := migrator.Up(context.Background(), 0); err != nil {
+		t.Fatalf("执行迁移失败: %v", err)
+	}
+	dialect := sqliteDialect{}
+
+	// 祖级列表匹配：101的子孙部门
+	var deptIds []int64
+	if err := db.Table("sys_dept").Where(dialect.FindInSet("?", "ancestors"), 101).Order("dept_id").Pluck("dept_id", &deptIds).Error; err != nil {
+		t.Fatalf("FindInSet查询失败: %v", err)
+	}
+	if len(deptIds) != 5 || deptIds[0] != 103 || deptIds[4] != 107 {
+		t.Errorf("101的子部门 = %v, 期望 103~107", deptIds)
+	}
+
+	// 分页
+	page, args := dialect.Paginate(2, 3)
+	var pagedIds []int64
+	if err := db.Raw("SELECT dept_id FROM sys_dept ORDER BY dept_id"+page, args...).Scan(&pagedIds).Error; err != nil {
+		t.Fatalf("分页查询失败: %v", err)
+	}
+	if len(pagedIds) != 3 || pagedIds[0] != 102 {
+		t.Errorf("分页结果 = %v, 期望 [102 103 104]", pagedIds)
+	}
+
+	// 带别名的修改和删除
+	if err := db.Exec(dialect.Update("sys_notice", "t", []string{"status"})+" WHERE t.notice_id = ?", "1", 1).Error; err != nil {
+		t.Fatalf("修改失败: %v", err)
+	}
+	if err := db.Exec(dialect.Delete("sys_notice", "t")+" WHERE t.status = ?", "1").Error; err != nil {
+		t.Fatalf("删除失败: %v", err)
+	}
+	var notices int64
+	db.Table("sys_notice").Count(&notices)
+	if notices != 1 {
+		t.Errorf("删除后公告数 = %d, 期望 1", notices)
+	}
+
+	// 清空表后自增从初始值继续
+	if err := db.Exec(dialect.Truncate("sys_oper_log")).Error; err != nil {
+		t.Fatalf("清空表失败: %v", err)
+	}
+	if err := db.Exec("INSERT INTO sys_oper_log (title) VALUES ('test')").Error; err != nil {
+		t.Fatalf("插入失败: %v", err)
+	}
+	var operId int64
+	db.Raw("SELECT MAX(oper_id) FROM sys_oper_log").Scan(&operId)
+	if operId != 100 {
+		t.Errorf("操作日志自增ID = %d, 期望 100", operId)
+	}
+
+	// 代码生成读取表和字段
+	var tables []string
+	if err := db.Raw("SELECT table_name FROM (" + dialect.TablesQuery() + ") t ORDER BY table_name").Scan(&tables).Error; err != nil {
+		t.Fatalf("读取表失败: %v", err)
+	}
+	if !strings.Contains(strings.Join(tables, ","), "sys_user") || strings.Contains(strings.Join(tables, ","), "sqlite_sequence") {
+		t.Errorf("表列表 = %v", tables)
+	}
+
+	type column struct {
+		ColumnName string
+		DataType   string
+		ColumnType string
+		ColumnKey  string
+		Extra      string
+	}
+	var columns []column
+	query, queryArgs := dialect.ColumnsQuery("sys_post")
+	if err := db.Raw(query, queryArgs...).Scan(&columns).Error; err != nil {
+		t.Fatalf("读取字段失败: %v", err)
+	}
+	// 初始的10个字段加上0005迁移增加的tenant_id
+	if len(columns) != 11 {
+		t.Fatalf("sys_post 字段数 = %d, 期望 11", len(columns))
+	}
+	if columns[0].ColumnName != "post_id" || columns[0].ColumnKey != "PRI" || columns[0].Extra != "auto_increment" {
+		t.Errorf("主键字段 = %+v", columns[0])
+	}
+	if columns[1].DataType != "varchar" || columns[1].ColumnType != "varchar(64)" {
+		t.Errorf("post_code 字段 = %+v", columns[1])
+	}
+
+	// 联合主键不是自增列
+	query, queryArgs = dialect.ColumnsQuery("sys_user_role")
+	columns = nil
+	db.Raw(query, queryArgs...).Scan(&columns)
+	if len(columns) != 2 || columns[0].ColumnKey != "PRI" || columns[0].Extra != "" {
+		t.Errorf("sys_user_role 字段 = %+v", columns)
+	}
+}
@@ -0,0 +1,395 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"wosm/internal/config"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// 副本负载均衡策略，与 database.replica_policy 配置一致
+const (
+	PolicyRandom     = "random"      // 按权重随机
+	PolicyRoundRobin = "round_robin" // 轮询
+	PolicyLeastConn  = "least_conn"  // 最少活跃连接
+)
+
+// DefaultReplicaCheckSeconds 副本健康检查的默认间隔（秒）
+const DefaultReplicaCheckSeconds = 10
+
+// DefaultReplicaStickySeconds 客户端写请求之后读主库的默认时间（秒）
+const DefaultReplicaStickySeconds = 5
+
+// usePrimaryKey 语句强制读主库的设置
+const usePrimaryKey = "wosm:use_primary"
+
+// Resolver 读写分离插件，没有配置只读副本时为nil
+var Resolver *ReplicaResolver
+
+// ReplicaStat 只读副本状态
+type ReplicaStat struct {
+	Name          string    `json:"Name"`          // 副本名称
+	Weight        int       `json:"Weight"`        // 权重
+	Healthy       bool      `json:"Healthy"`       // 是否可用
+	ReadCount     int64     `json:"ReadCount"`     // 路由到该副本的查询次数
+	LastCheckTime time.Time `json:"LastCheckTime"` // 最后健康检查时间
+	LastError     string    `json:"LastError"`     // 最后一次检查失败的错误
+	ActiveCount   int       `json:"ActiveCount"`   // 当前活跃连接数
+	PoolingCount  int       `json:"PoolingCount"`  // 当前空闲连接数
+}
+
+// replica 只读副本
+type replica struct {
+	name    string
+	weight  int
+	db      *sql.DB
+	healthy int32 // 1可用 0不可用
+	reads   int64
+
+	mu        sync.Mutex
+	lastCheck time.Time
+	lastError string
+}
+
+// ReplicaResolver 读写分离插件，对应Java后端的动态数据源（主从）
+// 不在事务中的查询读只读副本，写入、事务中的语句和加锁查询（FOR UPDATE）使用主库；
+// 请求中写入数据后，该请求之后的查询也读主库，避免读不到自己刚写入的数据
+type ReplicaResolver struct {
+	replicas []*replica
+	policy   string
+	interval time.Duration
+	sticky   time.Duration
+	primary  gorm.ConnPool // 主库连接池，只有使用主库连接池的语句才路由
+	next     uint64        // 轮询计数
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewReplicaResolver 按配置连接只读副本，连接池参数与主库一致
+func NewReplicaResolver(driver string, cfg *config.DatabaseConfig) (*ReplicaResolver, error) {
+	policy := cfg.ReplicaPolicy
+	if policy == "" {
+		policy = PolicyRandom
+	}
+	if policy != PolicyRandom && policy != PolicyRoundRobin && policy != PolicyLeastConn {
+		return nil, fmt.Errorf("不支持的副本负载均衡策略: %s，可选值: random、round_robin、least_conn", policy)
+	}
+	interval := cfg.ReplicaCheckInterval
+	if interval <= 0 {
+		interval = DefaultReplicaCheckSeconds
+	}
+	sticky := cfg.ReplicaStickySeconds
+	if sticky == 0 {
+		sticky = DefaultReplicaStickySeconds
+	}
+
+	r := &ReplicaResolver{
+		policy:   policy,
+		interval: time.Duration(interval) * time.Second,
+		sticky:   time.Duration(sticky) * time.Second,
+		stop:     make(chan struct{}),
+	}
+	for i, replicaConfig := range cfg.Replicas {
+		name := replicaConfig.Name
+		if name == "" {
+			name = fmt.Sprintf("replica-%d", i+1)
+		}
+		if replicaConfig.DSN == "" {
+			r.closeReplicas()
+			return nil, fmt.Errorf("只读副本 %s 的连接串不能为空", name)
+		}
+		sqlDB, err := openReplica(driver, replicaConfig.DSN, cfg)
+		if err != nil {
+			r.closeReplicas()
+			return nil, fmt.Errorf("连接只读副本 %s 失败: %v", name, err)
+		}
+		weight := replicaConfig.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+		r.replicas = append(r.replicas, &replica{name: name, weight: weight, db: sqlDB})
+	}
+	return r, nil
+}
+
+// sqlDriverNames GORM驱动使用的database/sql驱动名称
+var sqlDriverNames = map[string]string{
+	DriverSqlServer: "sqlserver",
+	DriverMySQL:     "mysql",
+	DriverPostgres:  "pgx",
+	DriverSQLite:    "sqlite",
+}
+
+// openReplica 打开只读副本的连接池，不立即连接（副本不可用时不影响启动），由健康检查确认是否可用
+func openReplica(driver, dsn string, cfg *config.DatabaseConfig) (*sql.DB, error) {
+	if driver == "" {
+		driver = DriverSqlServer
+	}
+	driverName, ok := sqlDriverNames[driver]
+	if !ok {
+		return nil, fmt.Errorf("不支持的数据库类型: %s", driver)
+	}
+	if driver == DriverSQLite {
+		dsn = sqliteDSN(dsn)
+	}
+	sqlDB, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetime) * time.Second)
+	sqlDB.SetConnMaxIdleTime(time.Duration(cfg.ConnMaxIdleTime) * time.Second)
+	return sqlDB, nil
+}
+
+// Name 插件名称
+func (r *ReplicaResolver) Name() string {
+	return "wosm:replica_resolver"
+}
+
+// Initialize 注册路由回调，检查一次副本状态后定时检查
+// 需要在SQL统计插件之后注册，主库连接池为统计插件包装后的连接池
+func (r *ReplicaResolver) Initialize(db *gorm.DB) error {
+	r.primary = db.ConnPool
+
+	callback := db.Callback()
+	if err := callback.Query().Before("gorm:query").Register("wosm:resolver_query", r.routeQuery); err != nil {
+		return err
+	}
+	if err := callback.Row().Before("gorm:row").Register("wosm:resolver_row", r.routeRaw); err != nil {
+		return err
+	}
+	if err := callback.Raw().Before("gorm:raw").Register("wosm:resolver_raw", r.routeRaw); err != nil {
+		return err
+	}
+	if err := callback.Create().Before("gorm:create").Register("wosm:resolver_create", r.markWrite); err != nil {
+		return err
+	}
+	if err := callback.Update().Before("gorm:update").Register("wosm:resolver_update", r.markWrite); err != nil {
+		return err
+	}
+	if err := callback.Delete().Before("gorm:delete").Register("wosm:resolver_delete", r.markWrite); err != nil {
+		return err
+	}
+
+	r.check()
+	go r.healthCheck()
+	return nil
+}
+
+// routeQuery 查询路由到副本
+func (r *ReplicaResolver) routeQuery(db *gorm.DB) {
+	if _, locking := db.Statement.Clauses["FOR"]; locking {
+		return
+	}
+	r.route(db)
+}
+
+// routeRaw 原生SQL中的查询路由到副本，其他语句使用主库并标记请求已写入
+func (r *ReplicaResolver) routeRaw(db *gorm.DB) {
+	sqlText := db.Statement.SQL.String()
+	// Rows() 的链式查询在 gorm:row 中才构建SQL
+	if sqlText != "" && !isQueryStatement(sqlText) {
+		r.markWrite(db)
+		return
+	}
+	if strings.Contains(strings.ToUpper(sqlText), " FOR UPDATE") {
+		return
+	}
+	r.route(db)
+}
+
+// route 不在事务中、没有要求读主库时，选择一个可用的副本
+func (r *ReplicaResolver) route(db *gorm.DB) {
+	if db.Error != nil || db.Statement.ConnPool != r.primary {
+		return
+	}
+	if usePrimary, ok := db.Get(usePrimaryKey); ok && usePrimary.(bool) {
+		return
+	}
+	if scope := requestFrom(db.Statement.Context); scope != nil {
+		scope.mu.Lock()
+		primary := scope.primary
+		scope.mu.Unlock()
+		if primary {
+			return
+		}
+	}
+	if target := r.pick(); target != nil {
+		db.Statement.ConnPool = target.db
+		atomic.AddInt64(&target.reads, 1)
+	}
+}
+
+// markWrite 请求中写入数据后，该请求之后的查询读主库
+func (r *ReplicaResolver) markWrite(db *gorm.DB) {
+	if scope := requestFrom(db.Statement.Context); scope != nil {
+		scope.mu.Lock()
+		scope.primary = true
+		scope.wrote = true
+		scope.mu.Unlock()
+	}
+}
+
+// pick 按负载均衡策略选择一个可用的副本，没有可用副本时返回nil（读主库）
+func (r *ReplicaResolver) pick() *replica {
+	healthy := make([]*replica, 0, len(r.replicas))
+	totalWeight := 0
+	for _, target := range r.replicas {
+		if atomic.LoadInt32(&target.healthy) == 1 {
+			healthy = append(healthy, target)
+			totalWeight += target.weight
+		}
+	}
+	if len(healthy) == 0 {
+		return nil
+	}
+
+	switch r.policy {
+	case PolicyRoundRobin:
+		return healthy[(atomic.AddUint64(&r.next, 1)-1)%uint64(len(healthy))]
+	case PolicyLeastConn:
+		least := healthy[0]
+		leastInUse := least.db.Stats().InUse
+		for _, target := range healthy[1:] {
+			if inUse := target.db.Stats().InUse; inUse < leastInUse {
+				least, leastInUse = target, inUse
+			}
+		}
+		return least
+	}
+	n := rand.Intn(totalWeight)
+	for _, target := range healthy {
+		if n < target.weight {
+			return target
+		}
+		n -= target.weight
+	}
+	return healthy[len(healthy)-1]
+}
+
+// healthCheck 定时检查副本是否可用
+func (r *ReplicaResolver) healthCheck() {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			r.check()
+		}
+	}
+}
+
+// check 检查所有副本，状态变化时记录日志
+func (r *ReplicaResolver) check() {
+	var wg sync.WaitGroup
+	for _, target := range r.replicas {
+		wg.Add(1)
+		go func(target *replica) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), r.interval)
+			defer cancel()
+			err := target.db.PingContext(ctx)
+
+			target.mu.Lock()
+			first := target.lastCheck.IsZero()
+			target.lastCheck = time.Now()
+			target.lastError = ""
+			if err != nil {
+				target.lastError = err.Error()
+			}
+			target.mu.Unlock()
+
+			if err != nil {
+				if atomic.SwapInt32(&target.healthy, 0) == 1 || first {
+					warn("只读副本不可用，查询改为读主库", zap.String("replica", target.name), zap.Error(err))
+				}
+				return
+			}
+			if atomic.SwapInt32(&target.healthy, 1) == 0 {
+				info("只读副本可用", zap.String("replica", target.name))
+			}
+		}(target)
+	}
+	wg.Wait()
+}
+
+// ReplicaStats 只读副本状态，按配置顺序
+func (r *ReplicaResolver) ReplicaStats() []ReplicaStat {
+	stats := make([]ReplicaStat, 0, len(r.replicas))
+	for _, target := range r.replicas {
+		pool := target.db.Stats()
+		target.mu.Lock()
+		stats = append(stats, ReplicaStat{
+			Name:          target.name,
+			Weight:        target.weight,
+			Healthy:       atomic.LoadInt32(&target.healthy) == 1,
+			ReadCount:     atomic.LoadInt64(&target.reads),
+			LastCheckTime: target.lastCheck,
+			LastError:     target.lastError,
+			ActiveCount:   pool.InUse,
+			PoolingCount:  pool.Idle,
+		})
+		target.mu.Unlock()
+	}
+	return stats
+}
+
+// StickyDuration 客户端写请求之后读主库的时间，0表示关闭
+func (r *ReplicaResolver) StickyDuration() time.Duration {
+	if r == nil || r.sticky < 0 {
+		return 0
+	}
+	return r.sticky
+}
+
+// Close 停止健康检查并关闭副本连接
+func (r *ReplicaResolver) Close() {
+	r.stopOnce.Do(func() {
+		close(r.stop)
+		r.closeReplicas()
+	})
+}
+
+// closeReplicas 关闭副本连接
+func (r *ReplicaResolver) closeReplicas() {
+	for _, target := range r.replicas {
+		target.db.Close()
+	}
+}
+
+// UsePrimary 查询读主库，用于刚写入后需要立即读取的场景：db.Scopes(database.UsePrimary)
+func UsePrimary(db *gorm.DB) *gorm.DB {
+	return db.Set(usePrimaryKey, true)
+}
+
+// ReadFromPrimary context所属请求之后的查询都读主库；不在请求中时（如定时任务）不起作用
+func ReadFromPrimary(ctx context.Context) {
+	if scope := requestFrom(ctx); scope != nil {
+		scope.mu.Lock()
+		scope.primary = true
+		scope.mu.Unlock()
+	}
+}
+
+// RequestWrote context所属请求中是否已写入数据；不在请求中时返回false
+func RequestWrote(ctx context.Context) bool {
+	scope := requestFrom(ctx)
+	if scope == nil {
+		return false
+	}
+	scope.mu.Lock()
+	defer scope.mu.Unlock()
+	return scope.wrote
+}
@@ -0,0 +1,128 @@
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"wosm/internal/config"
+	"wosm/pkg/sqlitetest"
+
+	"gorm.io/gorm"
+)
+
+// openFileDB 打开SQLite文件数据库并写入一条标识数据库的记录
+func openFileDB(t *testing.T, path, name string) *gorm.DB {
+	db := sqlitetest.Open(t, path)
+	db.AutoMigrate(&statItem{})
+	db.Create(&statItem{ID: 1, Name: name})
+	return db
+}
+
+// servedBy 查询标识记录，返回执行查询的数据库
+func servedBy(db *gorm.DB) string {
+	var item statItem
+	db.Take(&item, 1)
+	return item.Name
+}
+
+func TestReplicaResolver(t *testing.T) {
+	dir := t.TempDir()
+	primaryPath := filepath.Join(dir, "primary.db")
+	db := openFileDB(t, primaryPath, "primary")
+	openFileDB(t, filepath.Join(dir, "r1.db"), "r1")
+	openFileDB(t, filepath.Join(dir, "r2.db"), "r2")
+
+	stats := NewSQLStatPlugin()
+	db.Use(stats)
+	resolver, err := NewReplicaResolver(DriverSQLite, &config.DatabaseConfig{
+		ReplicaPolicy: PolicyRoundRobin,
+		Replicas: []config.ReplicaConfig{
+			{Name: "r1", DSN: filepath.Join(dir, "r1.db")},
+			{Name: "r2", DSN: filepath.Join(dir, "r2.db")},
+			{Name: "down", DSN: filepath.Join(dir, "not_exists", "r3.db")},
+		},
+	})
+	if err != nil {
+		t.Fatalf("创建读写分离插件失败: %v", err)
+	}
+	defer resolver.Close()
+	if err := db.Use(resolver); err != nil {
+		t.Fatalf("注册读写分离插件失败: %v", err)
+	}
+
+	// 轮询可用的副本，不可用的副本不参与
+	if first, second := servedBy(db), servedBy(db); first == second || first == "primary" || second == "primary" {
+		t.Errorf("轮询读副本 = %s, %s", first, second)
+	}
+	replicas := resolver.ReplicaStats()
+	if !replicas[0].Healthy || !replicas[1].Healthy || replicas[2].Healthy || replicas[2].LastError == "" {
+		t.Errorf("副本状态 = %+v", replicas)
+	}
+	if replicas[0].ReadCount != 1 || replicas[1].ReadCount != 1 {
+		t.Errorf("副本查询次数 = %d, %d", replicas[0].ReadCount, replicas[1].ReadCount)
+	}
+
+	// 写入、事务和指定读主库
+	db.Create(&statItem{ID: 2, Name: "written"})
+	var count int64
+	db.Model(&statItem{}).Where("id = ?", 2).Count(&count)
+	if count != 0 {
+		t.Error("写入应在主库执行，副本中不应有数据")
+	}
+	db.Transaction(func(tx *gorm.DB) error {
+		if name := servedBy(tx); name != "primary" {
+			t.Errorf("事务中的查询读 %s, 期望主库", name)
+		}
+		return nil
+	})
+	if name := servedBy(db.Scopes(UsePrimary)); name != "primary" {
+		t.Errorf("UsePrimary 查询读 %s, 期望主库", name)
+	}
+	var name string
+	db.Raw("SELECT name FROM stat_items WHERE id = ?", 1).Scan(&name)
+	if name == "primary" {
+		t.Error("原生查询应读副本")
+	}
+
+	// 请求中写入之后读主库
+	ctx, done := Stats.TrackRequest(context.Background(), "req-1", "POST", "/system/user")
+	requestDB := db.WithContext(ctx)
+	if name := servedBy(requestDB); name == "primary" {
+		t.Error("写入之前应读副本")
+	}
+	requestDB.Exec("UPDATE stat_items SET name = ? WHERE id = ?", "primary", 2)
+	if name := servedBy(requestDB); name != "primary" {
+		t.Errorf("请求中写入之后读 %s, 期望主库", name)
+	}
+	if name := servedBy(db); name == "primary" {
+		t.Error("其他请求不受影响，应读副本")
+	}
+	done()
+	if name := servedBy(requestDB); name == "primary" {
+		t.Error("请求结束后应恢复读副本")
+	}
+
+	// 副本都不可用时读主库
+	resolver.closeReplicas()
+	resolver.check()
+	if name := servedBy(db); name != "primary" {
+		t.Errorf("副本不可用时读 %s, 期望主库", name)
+	}
+}
+
+func TestReplicaResolverConfig(t *testing.T) {
+	if _, err := NewReplicaResolver(DriverSQLite, &config.DatabaseConfig{ReplicaPolicy: "fastest"}); err == nil {
+		t.Error("不支持的负载均衡策略应返回错误")
+	}
+	if _, err := NewReplicaResolver(DriverSQLite, &config.DatabaseConfig{Replicas: []config.ReplicaConfig{{Name: "r1"}}}); err == nil {
+		t.Error("副本连接串为空应返回错误")
+	}
+	resolver, _ := NewReplicaResolver(DriverSQLite, &config.DatabaseConfig{ReplicaStickySeconds: -1})
+	if resolver.StickyDuration() != 0 {
+		t.Error("负数应关闭写请求之后读主库")
+	}
+	var none *ReplicaResolver
+	if none.StickyDuration() != 0 {
+		t.Error("没有配置副本时不读主库")
+	}
+}
@@ -0,0 +1,431 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+	"wosm/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// 慢SQL和重复SQL监控的默认值，对应Java后端Druid的slowSqlMillis配置
+const (
+	DefaultSlowSQLMillis      = 1000
+	DefaultRepeatSQLThreshold = 10
+)
+
+// maxMonitorRecords 保留的慢SQL和重复SQL记录数
+const maxMonitorRecords = 100
+
+// maxVarLength 记录参数时字符串的最大长度
+const maxVarLength = 200
+
+// maskedValue 敏感参数的显示值
+const maskedValue = "******"
+
+// SlowSQL 一次慢SQL执行
+type SlowSQL struct {
+	Time      time.Time `json:"time"`      // 执行时间
+	SQL       string    `json:"sql"`       // SQL语句（参数为占位符）
+	Vars      []string  `json:"vars"`      // 参数值，敏感字段已脱敏
+	Millis    int64     `json:"millis"`    // 耗时（毫秒）
+	Rows      int64     `json:"rows"`      // 返回或影响的行数
+	Error     string    `json:"error"`     // 错误消息
+	Caller    string    `json:"caller"`    // 调用位置（文件:行号）
+	RequestID string    `json:"requestId"` // 请求ID，不在HTTP请求中执行时为空
+	Method    string    `json:"method"`    // 请求方法
+	Path      string    `json:"path"`      // 请求路径
+}
+
+// RepeatedSQL 一次HTTP请求中同一SQL指纹执行次数超过阈值（通常是循环中逐行查询的N+1问题）
+type RepeatedSQL struct {
+	Time      time.Time `json:"time"`      // 超过阈值的时间
+	SQL       string    `json:"sql"`       // SQL指纹
+	Count     int       `json:"count"`     // 请求中的执行次数，请求结束后为最终次数
+	Caller    string    `json:"caller"`    // 超过阈值时的调用位置（文件:行号）
+	RequestID string    `json:"requestId"` // 请求ID
+	Method    string    `json:"method"`    // 请求方法
+	Path      string    `json:"path"`      // 请求路径
+}
+
+// requestScope 一次HTTP请求中执行的SQL
+type requestScope struct {
+	id       string
+	method   string
+	path     string
+	mu       sync.Mutex
+	counts   map[string]int
+	repeated map[string]*RepeatedSQL
+	done     bool // 请求已结束
+	primary  bool // 之后的查询读主库（请求中已写入数据或客户端要求）
+	wrote    bool // 请求中已写入数据
+}
+
+// context中保存请求信息的键
+type (
+	requestScopeKey struct{}
+	operatorKey     struct{}
+)
+
+// sqlMonitor 慢SQL和重复SQL监控
+// 按 db.Statement.Context 中的请求信息关联SQL和请求，DAO需通过 WithContext 传入请求的context
+type sqlMonitor struct {
+	slowThreshold   time.Duration
+	repeatThreshold int
+	mu              sync.Mutex
+	slow            []SlowSQL
+	repeated        []*RepeatedSQL
+}
+
+// Configure 设置慢SQL阈值（毫秒）和单次请求同一SQL的重复次数阈值，0使用默认值，负数关闭
+func (p *SQLStatPlugin) Configure(slowSQLMillis, repeatSQLThreshold int) {
+	if slowSQLMillis == 0 {
+		slowSQLMillis = DefaultSlowSQLMillis
+	}
+	if repeatSQLThreshold == 0 {
+		repeatSQLThreshold = DefaultRepeatSQLThreshold
+	}
+	p.monitor.mu.Lock()
+	defer p.monitor.mu.Unlock()
+	p.monitor.slowThreshold = time.Duration(slowSQLMillis) * time.Millisecond
+	p.monitor.repeatThreshold = repeatSQLThreshold
+}
+
+// TrackRequest 开始统计HTTP请求执行的SQL，返回携带请求信息的context和请求结束时调用的函数
+// 使用返回的context执行的SQL（db.WithContext）计入请求，请求结束后不再统计
+func (p *SQLStatPlugin) TrackRequest(ctx context.Context, requestID, method, path string) (context.Context, func()) {
+	scope := &requestScope{id: requestID, method: method, path: path, counts: make(map[string]int)}
+
+	return context.WithValue(ctx, requestScopeKey{}, scope), func() {
+		// 重复SQL记录的次数更新为请求的最终次数
+		scope.mu.Lock()
+		defer scope.mu.Unlock()
+		scope.done = true
+		if len(scope.repeated) == 0 {
+			return
+		}
+		p.monitor.mu.Lock()
+		defer p.monitor.mu.Unlock()
+		for fingerprint, record := range scope.repeated {
+			record.Count = scope.counts[fingerprint]
+		}
+	}
+}
+
+// SlowSQLs 最近的慢SQL，最新的在前
+func (p *SQLStatPlugin) SlowSQLs() []SlowSQL {
+	p.monitor.mu.Lock()
+	defer p.monitor.mu.Unlock()
+	records := make([]SlowSQL, len(p.monitor.slow))
+	for i, record := range p.monitor.slow {
+		records[len(records)-1-i] = record
+	}
+	return records
+}
+
+// RepeatedSQLs 最近的重复SQL，最新的在前
+func (p *SQLStatPlugin) RepeatedSQLs() []RepeatedSQL {
+	p.monitor.mu.Lock()
+	defer p.monitor.mu.Unlock()
+	records := make([]RepeatedSQL, len(p.monitor.repeated))
+	for i, record := range p.monitor.repeated {
+		records[len(records)-1-i] = *record
+	}
+	return records
+}
+
+// MonitorThresholds 当前的慢SQL阈值（毫秒）和重复次数阈值，负数表示关闭
+func (p *SQLStatPlugin) MonitorThresholds() (slowSQLMillis int64, repeatSQLThreshold int) {
+	p.monitor.mu.Lock()
+	defer p.monitor.mu.Unlock()
+	return p.monitor.slowThreshold.Milliseconds(), p.monitor.repeatThreshold
+}
+
+// clearMonitor 清空慢SQL和重复SQL记录
+func (p *SQLStatPlugin) clearMonitor() {
+	p.monitor.mu.Lock()
+	defer p.monitor.mu.Unlock()
+	p.monitor.slow = nil
+	p.monitor.repeated = nil
+}
+
+// requestFrom context中正在处理的请求，不在请求中或请求已结束时返回nil
+func requestFrom(ctx context.Context) *requestScope {
+	if ctx == nil {
+		return nil
+	}
+	scope, ok := ctx.Value(requestScopeKey{}).(*requestScope)
+	if !ok {
+		return nil
+	}
+	scope.mu.Lock()
+	defer scope.mu.Unlock()
+	if scope.done {
+		return nil
+	}
+	return scope
+}
+
+// WithRequestOperator 返回记录了登录用户的context，供数据变更记录使用
+func WithRequestOperator(ctx context.Context, operator string) context.Context {
+	return context.WithValue(ctx, operatorKey{}, operator)
+}
+
+// RequestInfo context中的请求ID和登录用户；不在请求中时（如定时任务）返回空字符串
+func RequestInfo(ctx context.Context) (requestID, operator string) {
+	if ctx == nil {
+		return "", ""
+	}
+	if scope, ok := ctx.Value(requestScopeKey{}).(*requestScope); ok {
+		requestID = scope.id
+	}
+	operator, _ = ctx.Value(operatorKey{}).(string)
+	return requestID, operator
+}
+
+// check 检查一次执行是否为慢SQL、是否在请求中重复执行
+func (p *SQLStatPlugin) check(ctx context.Context, sqlText, fingerprint string, vars []interface{}, start time.Time, elapsed time.Duration, rows int64, err error) {
+	p.monitor.mu.Lock()
+	slowThreshold, repeatThreshold := p.monitor.slowThreshold, p.monitor.repeatThreshold
+	p.monitor.mu.Unlock()
+
+	scope := requestFrom(ctx)
+
+	if slowThreshold > 0 && elapsed >= slowThreshold {
+		record := SlowSQL{
+			Time:   start,
+			SQL:    sqlText,
+			Vars:   maskVars(sqlText, vars),
+			Millis: elapsed.Milliseconds(),
+			Rows:   rows,
+			Caller: callerLine(),
+		}
+		if err != nil {
+			record.Error = err.Error()
+		}
+		if scope != nil {
+			record.RequestID, record.Method, record.Path = scope.id, scope.method, scope.path
+		}
+		p.monitor.mu.Lock()
+		p.monitor.slow = appendLimited(p.monitor.slow, record)
+		p.monitor.mu.Unlock()
+
+		warn("慢SQL",
+			zap.Int64("millis", record.Millis),
+			zap.String("sql", record.SQL),
+			zap.Strings("vars", record.Vars),
+			zap.String("caller", record.Caller),
+			zap.String("requestId", record.RequestID))
+	}
+
+	if scope == nil || repeatThreshold <= 0 {
+		return
+	}
+	scope.mu.Lock()
+	scope.counts[fingerprint]++
+	count := scope.counts[fingerprint]
+	if count != repeatThreshold+1 {
+		scope.mu.Unlock()
+		return
+	}
+	record := &RepeatedSQL{
+		Time:      start,
+		SQL:       fingerprint,
+		Count:     count,
+		Caller:    callerLine(),
+		RequestID: scope.id,
+		Method:    scope.method,
+		Path:      scope.path,
+	}
+	if scope.repeated == nil {
+		scope.repeated = make(map[string]*RepeatedSQL)
+	}
+	scope.repeated[fingerprint] = record
+	scope.mu.Unlock()
+
+	p.monitor.mu.Lock()
+	p.monitor.repeated = appendLimited(p.monitor.repeated, record)
+	p.monitor.mu.Unlock()
+
+	warn("同一请求中重复执行SQL，可能存在N+1查询",
+		zap.Int("threshold", repeatThreshold),
+		zap.String("sql", fingerprint),
+		zap.String("caller", record.Caller),
+		zap.String("requestId", scope.id),
+		zap.String("path", scope.method+" "+scope.path))
+}
+
+// appendLimited 追加记录，超过 maxMonitorRecords 时丢弃最早的
+func appendLimited[T any](records []T, record T) []T {
+	records = append(records, record)
+	if len(records) > maxMonitorRecords {
+		records = append(records[:0:0], records[len(records)-maxMonitorRecords:]...)
+	}
+	return records
+}
+
+// warn 通过 pkg/logger 输出警告，日志未初始化时（如单元测试）不输出
+func warn(msg string, fields ...zap.Field) {
+	if logger.Logger != nil {
+		logger.Logger.WithOptions(zap.WithCaller(false)).Warn(msg, fields...)
+	}
+}
+
+// info 通过 pkg/logger 输出信息，日志未初始化时不输出
+func info(msg string, fields ...zap.Field) {
+	if logger.Logger != nil {
+		logger.Logger.WithOptions(zap.WithCaller(false)).Info(msg, fields...)
+	}
+}
+
+// packageDir 本包源码目录，查找调用位置时跳过
+var packageDir = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(file)
+}()
+
+// callerLine 执行SQL的业务代码位置，跳过GORM、数据库驱动和本包的调用帧
+func callerLine() string {
+	pcs := make([]uintptr, 48)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		skip := strings.HasPrefix(frame.Function, "runtime.") ||
+			strings.HasPrefix(frame.Function, "database/sql.") ||
+			strings.HasPrefix(frame.Function, "gorm.io/") ||
+			strings.Contains(frame.File, "/gorm.io/") ||
+			(filepath.Dir(frame.File) == packageDir && !strings.HasSuffix(frame.File, "_test.go"))
+		if !skip && frame.File != "" {
+			return filepath.Join(filepath.Base(filepath.Dir(frame.File)), filepath.Base(frame.File)) + ":" + strconv.Itoa(frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+var (
+	// 占位符前的比较条件，如 password = ?、t.[password]=@p1
+	comparedColumn = regexp.MustCompile(`(?i)([a-z_][a-z0-9_]*)[\]` + "`" + `"]?\s*(?:=|<>|!=|<=|>=|<|>|\sLIKE)\s*\(?\s*$`)
+	// INSERT 语句的列清单
+	insertColumns = regexp.MustCompile(`(?is)^\s*INSERT\s+INTO\s+\S+\s*\(([^)]*)\)\s*(?:OUTPUT\s+[^)]*?\s+)?VALUES`)
+	// 敏感字段名
+	sensitiveColumn = regexp.MustCompile(`(?i)password|passwd|pwd|secret|token|credential|private_?key|api_?key|access_?key`)
+)
+
+// maskVars 格式化参数值，敏感字段（密码、密钥、令牌等）的值脱敏
+// 按占位符前的比较条件或 INSERT 的列清单判断参数对应的字段
+func maskVars(sqlText string, vars []interface{}) []string {
+	if len(vars) == 0 {
+		return nil
+	}
+	sensitive := make([]bool, len(vars))
+
+	if m := insertColumns.FindStringSubmatch(sqlText); m != nil {
+		columns := strings.Split(m[1], ",")
+		for i := range vars {
+			if sensitiveColumn.MatchString(columns[i%len(columns)]) {
+				sensitive[i] = true
+			}
+		}
+	} else {
+		for _, placeholder := range placeholders(sqlText) {
+			if placeholder.index >= len(vars) {
+				continue
+			}
+			prefix := sqlText[max(0, placeholder.pos-80):placeholder.pos]
+			if m := comparedColumn.FindStringSubmatch(prefix); m != nil && sensitiveColumn.MatchString(m[1]) {
+				sensitive[placeholder.index] = true
+			}
+		}
+	}
+
+	values := make([]string, len(vars))
+	for i, v := range vars {
+		if sensitive[i] {
+			values[i] = maskedValue
+		} else {
+			values[i] = formatVar(v)
+		}
+	}
+	return values
+}
+
+// placeholder 参数占位符在SQL中的位置和对应的参数序号
+type placeholder struct {
+	pos   int
+	index int
+}
+
+// placeholders 查找SQL中的参数占位符（? @p1 $1），跳过字符串和引用的标识符
+func placeholders(sqlText string) []placeholder {
+	var result []placeholder
+	next := 0
+	for i := 0; i < len(sqlText); i++ {
+		switch c := sqlText[i]; c {
+		case '\'', '"', '`', '[':
+			closing := c
+			if c == '[' {
+				closing = ']'
+			}
+			for i++; i < len(sqlText) && sqlText[i] != closing; i++ {
+			}
+		case '?':
+			result = append(result, placeholder{pos: i, index: next})
+			next++
+		case '@', '$':
+			j := i + 1
+			if c == '@' && j < len(sqlText) && (sqlText[j] == 'p' || sqlText[j] == 'P') {
+				j++
+			}
+			k := j
+			for k < len(sqlText) && sqlText[k] >= '0' && sqlText[k] <= '9' {
+				k++
+			}
+			if k > j {
+				n, _ := strconv.Atoi(sqlText[j:k])
+				result = append(result, placeholder{pos: i, index: n - 1})
+				i = k - 1
+			}
+		}
+	}
+	return result
+}
+
+// formatVar 参数值的显示文本，二进制只显示长度，长字符串截断
+func formatVar(v interface{}) string {
+	if valuer, ok := v.(driver.Valuer); ok {
+		if value, err := valuer.Value(); err == nil {
+			v = value
+		}
+	}
+	switch value := v.(type) {
+	case nil:
+		return "NULL"
+	case []byte:
+		return fmt.Sprintf("<%d bytes>", len(value))
+	case time.Time:
+		return value.Format("2006-01-02 15:04:05")
+	case *time.Time:
+		if value == nil {
+			return "NULL"
+		}
+		return value.Format("2006-01-02 15:04:05")
+	case string:
+		if utf8.RuneCountInString(value) > maxVarLength {
+			return string([]rune(value)[:maxVarLength]) + "..."
+		}
+		return value
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,141 @@
+package database
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestMaskVars(t *testing.T) {
+	tests := []struct {
+		name     string
+		sql      string
+		vars     []interface{}
+		expected []string
+	}{
+		{
+			name:     "比较条件",
+			sql:      "SELECT * FROM sys_user WHERE user_name = ? AND password = ? AND status IN (?,?)",
+			vars:     []interface{}{"admin", "123456", "0", "1"},
+			expected: []string{"admin", "******", "0", "1"},
+		},
+		{
+			name:     "SQL Server占位符",
+			sql:      "UPDATE [sys_user] SET [password]=@p1,[update_time]=@p2 WHERE user_id = @p3",
+			vars:     []interface{}{"$2a$10$hash", time.Date(2025, 5, 22, 8, 0, 0, 0, time.Local), 1},
+			expected: []string{"******", "2025-05-22 08:00:00", "1"},
+		},
+		{
+			name:     "批量插入",
+			sql:      `INSERT INTO "sys_user" ("user_name","password","avatar") VALUES ($1,$2,$3),($4,$5,$6)`,
+			vars:     []interface{}{"a", "x", []byte{1, 2}, "b", "y", nil},
+			expected: []string{"a", "******", "<2 bytes>", "b", "******", "NULL"},
+		},
+		{
+			name:     "字符串中的问号不是占位符",
+			sql:      "SELECT * FROM t WHERE remark = '?' AND api_key = ?",
+			vars:     []interface{}{"secret-value"},
+			expected: []string{"******"},
+		},
+	}
+	for _, tt := range tests {
+		if got := maskVars(tt.sql, tt.vars); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("%s: maskVars = %v, 期望 %v", tt.name, got, tt.expected)
+		}
+	}
+
+	long := formatVar(strings.Repeat("长", maxVarLength+10))
+	if !strings.HasSuffix(long, "...") || len([]rune(long)) != maxVarLength+3 {
+		t.Errorf("长字符串未截断: %d", len([]rune(long)))
+	}
+}
+
+// TestSQLMonitor 慢SQL和请求中重复执行的SQL
+func TestSQLMonitor(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("打开SQLite失败: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+
+	stats := NewSQLStatPlugin()
+	stats.Configure(1, 3)
+	if err := db.Use(stats); err != nil {
+		t.Fatalf("注册插件失败: %v", err)
+	}
+	db.AutoMigrate(&statItem{})
+	for i := 1; i <= 5; i++ {
+		db.Create(&statItem{ID: int64(i), Name: "item"})
+	}
+
+	// 请求中逐行查询（N+1），以及一条慢SQL
+	ctx, done := stats.TrackRequest(context.Background(), "req-1", "GET", "/system/user/list")
+	requestDB := db.WithContext(ctx)
+	for i := 1; i <= 5; i++ {
+		var item statItem
+		requestDB.Where("id = ?", i).Take(&item)
+	}
+	var count int64
+	requestDB.Raw("WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM c WHERE x < ?) SELECT COUNT(*) FROM c", 500000).Find(&count)
+
+	// 没有使用请求context执行的SQL不计入请求
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 5; i++ {
+			var items []statItem
+			db.Find(&items)
+		}
+	}()
+	wg.Wait()
+	done()
+
+	repeated := stats.RepeatedSQLs()
+	if len(repeated) != 1 {
+		t.Fatalf("重复SQL记录 = %+v, 期望1条", repeated)
+	}
+	record := repeated[0]
+	if record.SQL != "SELECT * FROM `stat_items` WHERE id = ? LIMIT ?" || record.Count != 5 || record.RequestID != "req-1" || record.Path != "/system/user/list" {
+		t.Errorf("重复SQL记录 = %+v", record)
+	}
+	if !strings.HasPrefix(record.Caller, "database/sqlmonitor_test.go:") {
+		t.Errorf("调用位置 = %s", record.Caller)
+	}
+
+	var slow *SlowSQL
+	for _, s := range stats.SlowSQLs() {
+		if strings.HasPrefix(s.SQL, "WITH RECURSIVE") {
+			s := s
+			slow = &s
+		}
+	}
+	if slow == nil {
+		t.Fatal("没有记录慢SQL")
+	}
+	if slow.RequestID != "req-1" || !reflect.DeepEqual(slow.Vars, []string{"500000"}) || slow.Millis < 1 {
+		t.Errorf("慢SQL记录 = %+v", slow)
+	}
+
+	// 请求结束后不再统计
+	for i := 0; i < 5; i++ {
+		var item statItem
+		requestDB.Where("id = ?", 1).Take(&item)
+	}
+	if len(stats.RepeatedSQLs()) != 1 {
+		t.Error("请求结束后不应再记录重复SQL")
+	}
+
+	stats.Reset()
+	if len(stats.SlowSQLs()) != 0 || len(stats.RepeatedSQLs()) != 0 {
+		t.Error("重置后慢SQL记录应为空")
+	}
+}
@@ -0,0 +1,467 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+// HistogramSize 耗时分布的格数，区间上限依次为1ms、10ms、100ms、1s、10s、100s、1000s，最后一格为超过1000s（与Druid一致）
+const HistogramSize = 8
+
+// maxSQLStatSize 最多统计的SQL指纹数，超出后新的SQL只计入汇总 对应Druid的maxSqlSize
+const maxSQLStatSize = 1000
+
+// startTimeKey 语句开始时间在Statement中的键
+const startTimeKey = "sqlstat:start_time"
+
+// Stats 数据源的SQL统计，InitDatabase时注册到GORM
+var Stats = NewSQLStatPlugin()
+
+// SQLStat 一类SQL（参数归一化后的指纹）的执行统计 对应Druid的JdbcSqlStat
+type SQLStat struct {
+	SQL                  string               // SQL指纹
+	ExecuteCount         int64                // 执行次数
+	ErrorCount           int64                // 错误次数
+	TotalTime            time.Duration        // 总耗时
+	MaxTimespan          time.Duration        // 最大耗时
+	MaxTimespanOccurTime time.Time            // 最大耗时发生时间
+	LastTime             time.Time            // 最后执行时间
+	Histogram            [HistogramSize]int64 // 耗时分布
+	FetchRowCount        int64                // 查询返回的行数
+	UpdateCount          int64                // 增删改影响的行数
+	InTransactionCount   int64                // 在事务中执行的次数
+	LastError            string               // 最后错误消息
+	LastErrorTime        time.Time            // 最后错误时间
+}
+
+// DataSourceStat 数据源汇总统计 对应Druid的DruidDataSourceStatValue
+type DataSourceStat struct {
+	ExecuteCount    int64                // 执行次数
+	ErrorCount      int64                // 错误次数
+	CommitCount     int64                // 提交次数
+	RollbackCount   int64                // 回滚次数
+	TotalTime       time.Duration        // 总耗时
+	MaxTimespan     time.Duration        // 最大耗时
+	Histogram       [HistogramSize]int64 // 耗时分布
+	ActivePeak      int                  // 使用中连接数峰值
+	ActivePeakTime  time.Time            // 使用中连接数峰值时间
+	PoolingPeak     int                  // 打开连接数峰值
+	PoolingPeakTime time.Time            // 打开连接数峰值时间
+	ResetTime       time.Time            // 统计开始时间（启动或上次重置的时间）
+	Pool            sql.DBStats          // 连接池状态，等待和关闭次数从上次重置开始累计
+}
+
+// SQLStatPlugin 记录SQL执行统计的GORM插件 对应Java后端Druid的StatFilter
+// 语句的耗时、影响行数和错误通过回调记录，事务的提交和回滚通过包装连接池记录
+type SQLStatPlugin struct {
+	mu       sync.Mutex
+	sqls     map[string]*SQLStat
+	total    DataSourceStat
+	sqlDB    *sql.DB
+	poolBase sql.DBStats // 上次重置时的连接池累计值
+	monitor  sqlMonitor  // 慢SQL和重复SQL监控
+}
+
+// NewSQLStatPlugin 创建SQL统计插件
+func NewSQLStatPlugin() *SQLStatPlugin {
+	p := &SQLStatPlugin{
+		sqls:  make(map[string]*SQLStat),
+		total: DataSourceStat{ResetTime: time.Now()},
+	}
+	p.Configure(0, 0)
+	return p
+}
+
+// Name 插件名称
+func (p *SQLStatPlugin) Name() string {
+	return "wosm:sqlstat"
+}
+
+// Initialize 注册回调并包装连接池
+// 回调紧挨着执行语句的默认回调，耗时不包含预加载和关联保存，且在默认事务提交之前（仍能判断是否在事务中）
+func (p *SQLStatPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	if err := errors.Join(
+		cb.Create().Before("gorm:create").Register("sqlstat:before_create", p.before),
+		cb.Create().After("gorm:create").Before("gorm:save_after_associations").Register("sqlstat:after_create", p.after),
+		cb.Query().Before("gorm:query").Register("sqlstat:before_query", p.before),
+		cb.Query().After("gorm:query").Before("gorm:preload").Register("sqlstat:after_query", p.after),
+		cb.Update().Before("gorm:update").Register("sqlstat:before_update", p.before),
+		cb.Update().After("gorm:update").Before("gorm:save_after_associations").Register("sqlstat:after_update", p.after),
+		cb.Delete().Before("gorm:delete").Register("sqlstat:before_delete", p.before),
+		cb.Delete().After("gorm:delete").Before("gorm:after_delete").Register("sqlstat:after_delete", p.after),
+		cb.Row().Before("gorm:row").Register("sqlstat:before_row", p.before),
+		cb.Row().After("gorm:row").Register("sqlstat:after_row", p.after),
+		cb.Raw().Before("gorm:raw").Register("sqlstat:before_raw", p.before),
+		cb.Raw().After("gorm:raw").Register("sqlstat:after_raw", p.after),
+	); err != nil {
+		return err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	p.sqlDB = sqlDB
+	p.poolBase = sqlDB.Stats()
+	p.mu.Unlock()
+
+	pool := &statConnPool{ConnPool: db.ConnPool, stats: p, sqlDB: sqlDB}
+	db.ConnPool = pool
+	db.Statement.ConnPool = pool
+	return nil
+}
+
+// before 记录语句开始时间
+func (p *SQLStatPlugin) before(db *gorm.DB) {
+	db.InstanceSet(startTimeKey, time.Now())
+}
+
+// after 记录语句耗时、影响行数和错误
+func (p *SQLStatPlugin) after(db *gorm.DB) {
+	if db.DryRun {
+		return
+	}
+	value, ok := db.InstanceGet(startTimeKey)
+	if !ok {
+		return
+	}
+	start := value.(time.Time)
+	sqlText := db.Statement.SQL.String()
+	if sqlText == "" {
+		// 构建SQL之前就失败了（如没有条件的全表删除），不是一次数据库执行
+		return
+	}
+
+	elapsed := time.Since(start)
+	err := db.Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = nil
+	}
+	_, inTransaction := db.Statement.ConnPool.(gorm.TxCommitter)
+	isQuery := isQueryStatement(sqlText)
+	fingerprint := Fingerprint(sqlText)
+	p.record(fingerprint, start, elapsed, db.RowsAffected, isQuery, inTransaction, err)
+	p.check(db.Statement.Context, sqlText, fingerprint, db.Statement.Vars, start, elapsed, db.RowsAffected, err)
+}
+
+// record 累加一次执行
+func (p *SQLStatPlugin) record(fingerprint string, start time.Time, elapsed time.Duration, rows int64, isQuery, inTransaction bool, err error) {
+	var pool sql.DBStats
+	if p.sqlDB != nil {
+		pool = p.sqlDB.Stats()
+	}
+	bucket := histogramBucket(elapsed)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	total := &p.total
+	total.ExecuteCount++
+	total.TotalTime += elapsed
+	total.Histogram[bucket]++
+	if elapsed > total.MaxTimespan {
+		total.MaxTimespan = elapsed
+	}
+	if err != nil {
+		total.ErrorCount++
+	}
+	if pool.InUse > total.ActivePeak {
+		total.ActivePeak = pool.InUse
+		total.ActivePeakTime = start
+	}
+	if pool.OpenConnections > total.PoolingPeak {
+		total.PoolingPeak = pool.OpenConnections
+		total.PoolingPeakTime = start
+	}
+
+	stat, ok := p.sqls[fingerprint]
+	if !ok {
+		if len(p.sqls) >= maxSQLStatSize {
+			return
+		}
+		stat = &SQLStat{SQL: fingerprint}
+		p.sqls[fingerprint] = stat
+	}
+	stat.ExecuteCount++
+	stat.TotalTime += elapsed
+	stat.LastTime = start
+	stat.Histogram[bucket]++
+	if elapsed > stat.MaxTimespan {
+		stat.MaxTimespan = elapsed
+		stat.MaxTimespanOccurTime = start
+	}
+	if inTransaction {
+		stat.InTransactionCount++
+	}
+	if err != nil {
+		stat.ErrorCount++
+		stat.LastError = err.Error()
+		stat.LastErrorTime = start
+	} else if rows > 0 {
+		if isQuery {
+			stat.FetchRowCount += rows
+		} else {
+			stat.UpdateCount += rows
+		}
+	}
+}
+
+// recordTransaction 记录提交或回滚
+func (p *SQLStatPlugin) recordTransaction(commit bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if commit {
+		p.total.CommitCount++
+	} else {
+		p.total.RollbackCount++
+	}
+}
+
+// SQLStats 所有SQL的统计，按总耗时从大到小排序
+func (p *SQLStatPlugin) SQLStats() []SQLStat {
+	p.mu.Lock()
+	stats := make([]SQLStat, 0, len(p.sqls))
+	for _, stat := range p.sqls {
+		stats = append(stats, *stat)
+	}
+	p.mu.Unlock()
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].TotalTime != stats[j].TotalTime {
+			return stats[i].TotalTime > stats[j].TotalTime
+		}
+		return stats[i].SQL < stats[j].SQL
+	})
+	return stats
+}
+
+// DataSourceStat 数据源汇总统计和当前连接池状态
+func (p *SQLStatPlugin) DataSourceStat() DataSourceStat {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stat := p.total
+	if p.sqlDB != nil {
+		pool := p.sqlDB.Stats()
+		pool.WaitCount -= p.poolBase.WaitCount
+		pool.WaitDuration -= p.poolBase.WaitDuration
+		pool.MaxIdleClosed -= p.poolBase.MaxIdleClosed
+		pool.MaxIdleTimeClosed -= p.poolBase.MaxIdleTimeClosed
+		pool.MaxLifetimeClosed -= p.poolBase.MaxLifetimeClosed
+		stat.Pool = pool
+	}
+	return stat
+}
+
+// Reset 清空所有统计和慢SQL记录 对应Druid的resetAll
+func (p *SQLStatPlugin) Reset() {
+	p.clearMonitor()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.sqls = make(map[string]*SQLStat)
+	p.total = DataSourceStat{ResetTime: time.Now()}
+	if p.sqlDB != nil {
+		p.poolBase = p.sqlDB.Stats()
+	}
+}
+
+// histogramBucket 耗时所在的分布格
+func histogramBucket(elapsed time.Duration) int {
+	bound := time.Millisecond
+	for i := 0; i < HistogramSize-1; i++ {
+		if elapsed < bound {
+			return i
+		}
+		bound *= 10
+	}
+	return HistogramSize - 1
+}
+
+// isQueryStatement 是否为返回结果集的查询
+func isQueryStatement(sqlText string) bool {
+	word := strings.TrimLeft(sqlText, " \t\r\n(")
+	if i := strings.IndexFunc(word, unicode.IsSpace); i > 0 {
+		word = word[:i]
+	}
+	return strings.EqualFold(word, "SELECT") || strings.EqualFold(word, "WITH") || strings.EqualFold(word, "PRAGMA")
+}
+
+var (
+	// 逗号分隔的多个参数，如 IN (?, ?, ?)
+	repeatedParams = regexp.MustCompile(`\?(?:\s*,\s*\?)+`)
+	// 批量插入的多组值，如 VALUES (?), (?)
+	repeatedTuples = regexp.MustCompile(`\(\?\)(?:\s*,\s*\(\?\))+`)
+)
+
+// Fingerprint SQL指纹：字符串和数字字面量、各驱动的参数占位符（? @p1 $1）都替换为?，
+// 参数列表和批量插入的多组值合并为一个，空白压缩为一个空格，使同一类SQL归为一条统计
+func Fingerprint(sqlText string) string {
+	runes := []rune(sqlText)
+	var b strings.Builder
+	b.Grow(len(sqlText))
+	space := false
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			space = true
+			continue
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+			space = true
+			continue
+		}
+
+		if space && b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		space = false
+
+		switch {
+		case r == '\'' || ((r == 'N' || r == 'n') && i+1 < len(runes) && runes[i+1] == '\'' && !isIdentRune(runes, i-1)):
+			if r != '\'' {
+				i++
+			}
+			for i++; i < len(runes); i++ {
+				if runes[i] == '\'' {
+					if i+1 < len(runes) && runes[i+1] == '\'' {
+						i++
+						continue
+					}
+					break
+				}
+			}
+			b.WriteByte('?')
+		case r == '"' || r == '`' || r == '[':
+			closing := r
+			if r == '[' {
+				closing = ']'
+			}
+			b.WriteRune(r)
+			for i++; i < len(runes); i++ {
+				b.WriteRune(runes[i])
+				if runes[i] == closing {
+					break
+				}
+			}
+		case (r == '@' || r == '$') && i+1 < len(runes) && (runes[i+1] == 'p' || runes[i+1] == 'P' || unicode.IsDigit(runes[i+1])):
+			j := i + 1
+			if !unicode.IsDigit(runes[j]) {
+				j++
+			}
+			if j < len(runes) && unicode.IsDigit(runes[j]) {
+				for j < len(runes) && unicode.IsDigit(runes[j]) {
+					j++
+				}
+				b.WriteByte('?')
+				i = j - 1
+			} else {
+				b.WriteRune(r)
+			}
+		case unicode.IsDigit(r) && !isIdentRune(runes, i-1):
+			for i+1 < len(runes) && (unicode.IsDigit(runes[i+1]) || runes[i+1] == '.') {
+				i++
+			}
+			b.WriteByte('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	fingerprint := repeatedParams.ReplaceAllString(b.String(), "?")
+	return repeatedTuples.ReplaceAllString(fingerprint, "(?)")
+}
+
+// isIdentRune 位置i的字符是否为标识符的一部分（用于区分 t1 中的数字和数字字面量）
+func isIdentRune(runes []rune, i int) bool {
+	if i < 0 {
+		return false
+	}
+	r := runes[i]
+	return r == '_' || r == '.' || r == '@' || r == '$' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// statConnPool 包装GORM连接池，统计事务的提交和回滚
+type statConnPool struct {
+	gorm.ConnPool
+	stats *SQLStatPlugin
+	sqlDB *sql.DB
+}
+
+// BeginTx 开启事务，返回包装后的事务
+func (p *statConnPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	var (
+		tx  gorm.ConnPool
+		err error
+	)
+	switch beginner := p.ConnPool.(type) {
+	case gorm.TxBeginner:
+		tx, err = beginner.BeginTx(ctx, opts)
+	case gorm.ConnPoolBeginner:
+		tx, err = beginner.BeginTx(ctx, opts)
+	default:
+		err = gorm.ErrInvalidTransaction
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &statTx{ConnPool: tx, stats: p.stats, sqlDB: p.sqlDB}, nil
+}
+
+// GetDBConn 底层sql.DB，供 gorm.DB.DB() 使用
+func (p *statConnPool) GetDBConn() (*sql.DB, error) {
+	return p.sqlDB, nil
+}
+
+// statTx 包装的事务
+type statTx struct {
+	gorm.ConnPool
+	stats *SQLStatPlugin
+	sqlDB *sql.DB
+}
+
+// Commit 提交事务
+func (tx *statTx) Commit() error {
+	committer, ok := tx.ConnPool.(gorm.TxCommitter)
+	if !ok {
+		return gorm.ErrInvalidTransaction
+	}
+	err := committer.Commit()
+	if err == nil {
+		tx.stats.recordTransaction(true)
+	}
+	return err
+}
+
+// Rollback 回滚事务；事务已结束时的回滚不计数
+func (tx *statTx) Rollback() error {
+	committer, ok := tx.ConnPool.(gorm.TxCommitter)
+	if !ok {
+		return gorm.ErrInvalidTransaction
+	}
+	err := committer.Rollback()
+	if !errors.Is(err, sql.ErrTxDone) {
+		tx.stats.recordTransaction(false)
+	}
+	return err
+}
+
+// GetDBConn 底层sql.DB，供事务中的 gorm.DB.DB() 使用
+func (tx *statTx) GetDBConn() (*sql.DB, error) {
+	return tx.sqlDB, nil
+}
@@ -0,0 +1,140 @@
+package database
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestFingerprint(t *testing.T) {
+	tests := []struct {
+		sql      string
+		expected string
+	}{
+		{"SELECT * FROM sys_user WHERE user_id = 1 AND user_name = N'admin'", "SELECT * FROM sys_user WHERE user_id = ? AND user_name = ?"},
+		{"SELECT * FROM [sys_user] WHERE [user_id] = @p1 AND status = @p2", "SELECT * FROM [sys_user] WHERE [user_id] = ? AND status = ?"},
+		{`SELECT * FROM "sys_user" WHERE "user_id" = $1 LIMIT $2`, `SELECT * FROM "sys_user" WHERE "user_id" = ? LIMIT ?`},
+		{"SELECT * FROM sys_dept WHERE dept_id IN (?,?,?)", "SELECT * FROM sys_dept WHERE dept_id IN (?)"},
+		{"INSERT INTO t1 (a,b) VALUES (?,?),(?,?),(?,?)", "INSERT INTO t1 (a,b) VALUES (?)"},
+		{"SELECT  t.col2\n\tFROM tab3 t -- 注释\n WHERE x = 'it''s'", "SELECT t.col2 FROM tab3 t WHERE x = ?"},
+		{"SELECT * FROM t OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY", "SELECT * FROM t OFFSET ? ROWS FETCH NEXT ? ROWS ONLY"},
+	}
+	for _, tt := range tests {
+		if got := Fingerprint(tt.sql); got != tt.expected {
+			t.Errorf("Fingerprint(%q)\n得到 %q\n期望 %q", tt.sql, got, tt.expected)
+		}
+	}
+}
+
+func TestHistogramBucket(t *testing.T) {
+	tests := map[time.Duration]int{
+		500 * time.Microsecond: 0,
+		5 * time.Millisecond:   1,
+		50 * time.Millisecond:  2,
+		2 * time.Second:        4,
+		2 * time.Hour:          7,
+	}
+	for elapsed, expected := range tests {
+		if got := histogramBucket(elapsed); got != expected {
+			t.Errorf("histogramBucket(%v) = %d, 期望 %d", elapsed, got, expected)
+		}
+	}
+}
+
+type statItem struct {
+	ID   int64 `gorm:"primaryKey"`
+	Name string
+}
+
+// TestSQLStatPlugin 在SQLite上执行语句并检查统计
+func TestSQLStatPlugin(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("打开SQLite失败: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+
+	stats := NewSQLStatPlugin()
+	if err := db.Use(stats); err != nil {
+		t.Fatalf("注册插件失败: %v", err)
+	}
+	if _, err := db.DB(); err != nil {
+		t.Fatalf("包装连接池后获取sql.DB失败: %v", err)
+	}
+	if err := db.AutoMigrate(&statItem{}); err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+	stats.Reset()
+
+	// 同一指纹的查询合并统计
+	for i := 1; i <= 3; i++ {
+		db.Create(&statItem{ID: int64(i), Name: "item"})
+	}
+	var items []statItem
+	db.Where("id IN ?", []int64{1, 2}).Find(&items)
+	db.Where("id IN ?", []int64{1, 2, 3}).Find(&items)
+
+	// 没有记录不算错误，语法错误计入错误
+	var item statItem
+	db.First(&item, 100)
+	db.Exec("SELECT * FROM not_exists")
+
+	// 提交和回滚
+	db.Transaction(func(tx *gorm.DB) error {
+		return tx.Model(&statItem{}).Where("id = ?", 1).Update("name", "changed").Error
+	})
+	db.Transaction(func(tx *gorm.DB) error {
+		tx.Delete(&statItem{}, 2)
+		return errors.New("回滚")
+	})
+
+	total := stats.DataSourceStat()
+	// Create默认在事务中执行，3次插入也各提交一次
+	if total.CommitCount != 4 || total.RollbackCount != 1 {
+		t.Errorf("提交/回滚 = %d/%d, 期望 4/1", total.CommitCount, total.RollbackCount)
+	}
+	if total.ExecuteCount != 9 || total.ErrorCount != 1 {
+		t.Errorf("执行/错误次数 = %d/%d, 期望 9/1", total.ExecuteCount, total.ErrorCount)
+	}
+	if total.Pool.MaxOpenConnections != 1 {
+		t.Errorf("连接池最大连接数 = %d, 期望 1", total.Pool.MaxOpenConnections)
+	}
+
+	bySQL := make(map[string]SQLStat)
+	for _, stat := range stats.SQLStats() {
+		bySQL[stat.SQL] = stat
+	}
+	insert := bySQL["INSERT INTO `stat_items` (`name`,`id`) VALUES (?) RETURNING `id`"]
+	if insert.ExecuteCount != 3 || insert.UpdateCount != 3 || insert.InTransactionCount != 3 {
+		t.Errorf("插入统计 = %+v", insert)
+	}
+	query := bySQL["SELECT * FROM `stat_items` WHERE id IN (?)"]
+	if query.ExecuteCount != 2 || query.FetchRowCount != 5 {
+		t.Errorf("IN查询统计 = %+v", query)
+	}
+	var histogram int64
+	for _, count := range query.Histogram {
+		histogram += count
+	}
+	if histogram != 2 {
+		t.Errorf("耗时分布合计 = %d, 期望 2", histogram)
+	}
+	failed := bySQL["SELECT * FROM not_exists"]
+	if failed.ErrorCount != 1 || failed.LastError == "" {
+		t.Errorf("错误语句统计 = %+v", failed)
+	}
+	update := bySQL["UPDATE `stat_items` SET `name`=? WHERE id = ?"]
+	if update.InTransactionCount != 1 || update.UpdateCount != 1 {
+		t.Errorf("事务内修改统计 = %+v", update)
+	}
+
+	stats.Reset()
+	if len(stats.SQLStats()) != 0 || stats.DataSourceStat().ExecuteCount != 0 {
+		t.Error("重置后统计应为空")
+	}
+}
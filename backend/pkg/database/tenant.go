@@ -0,0 +1,37 @@
+package database
+
+import "context"
+
+// context中保存租户信息的键
+type (
+	tenantKey     struct{}
+	allTenantsKey struct{}
+)
+
+// WithRequestTenant 返回记录了租户的context，租户插件按该租户过滤数据
+func WithRequestTenant(ctx context.Context, tenantID int64) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenantID)
+}
+
+// RequestTenant context中的租户；未设置时返回0
+func RequestTenant(ctx context.Context) int64 {
+	if ctx == nil {
+		return 0
+	}
+	tenantID, _ := ctx.Value(tenantKey{}).(int64)
+	return tenantID
+}
+
+// WithAllTenants 返回访问全部租户数据的context，用于定时任务等平台级的跨租户处理
+func WithAllTenants(ctx context.Context) context.Context {
+	return context.WithValue(ctx, allTenantsKey{}, true)
+}
+
+// AllTenants context是否访问全部租户的数据
+func AllTenants(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	all, _ := ctx.Value(allTenantsKey{}).(bool)
+	return all
+}
@@ -10,7 +10,7 @@ const (
 	DataScopeDeptAndChild = "4" // 部门及以下数据权限
 	DataScopeSelf         = "5" // 仅本人数据权限
 
-	// 数据权限过滤关键字 对应Java后端的DATA_SCOPE
+	// 数据权限上下文关键字，值为 *Predicate 对应Java后端的DATA_SCOPE
 	DataScopeKey = "dataScope"
 
 	// 权限上下文关键字
@@ -24,14 +24,6 @@ const (
 	RoleStatusDisable = "1" // 停用
 )
 
-// DataScopeConfig 数据权限配置 对应Java后端的@DataScope注解
-type DataScopeConfig struct {
-	DeptAlias    string // 部门表别名
-	UserAlias    string // 用户表别名
-	CreatorAlias string // 创建者表别名（用于create_by字段）
-	Permission   string // 权限字符
-}
-
 // GetDataScopeText 获取数据权限范围文本描述
 func GetDataScopeText(dataScope string) string {
 	switch dataScope {
@@ -1,201 +1,87 @@
 package datascope
 
 import (
+	"reflect"
 	"testing"
 	"wosm/internal/repository/model"
 )
 
-// TestDataScopeSelfWithCreator 测试基于创建者的"仅本人数据"权限
-func TestDataScopeSelfWithCreator(t *testing.T) {
-	// 创建测试用户
-	user := &model.SysUser{
-		UserID:   123,
-		UserName: "testuser",
-		DeptID:   func() *int64 { id := int64(100); return &id }(),
-		Roles: []model.SysRole{
-			{
-				RoleID:    2,
-				DataScope: DataScopeSelf, // 仅本人数据权限
-				Status:    RoleStatusNormal,
-				Permissions: []string{"system:notice:list"},
-			},
-		},
-	}
+// newScopeUser 创建部门为100、拥有指定角色的测试用户
+func newScopeUser(roles ...model.SysRole) *model.SysUser {
+	deptId := int64(100)
+	return &model.SysUser{UserID: 123, UserName: "testuser", DeptID: &deptId, Roles: roles}
+}
+
+// newScopeRole 创建拥有system:notice:list权限的正常角色
+func newScopeRole(roleId int64, dataScope string) model.SysRole {
+	return model.SysRole{RoleID: roleId, DataScope: dataScope, Status: RoleStatusNormal, Permissions: []string{"system:notice:list"}}
+}
+
+// TestBuild 测试按角色数据范围生成数据权限条件
+func TestBuild(t *testing.T) {
+	disabled := newScopeRole(5, DataScopeAll)
+	disabled.Status = RoleStatusDisable
+	unpermitted := newScopeRole(6, DataScopeAll)
+	unpermitted.Permissions = []string{"system:user:list"}
 
 	tests := []struct {
-		name         string
-		config       *DataScopeConfig
-		expectedSQL  string
-		description  string
+		name     string
+		user     *model.SysUser
+		expected *Predicate
 	}{
-		{
-			name: "仅用户ID权限",
-			config: &DataScopeConfig{
-				DeptAlias:  "d",
-				UserAlias:  "u",
-				Permission: "system:notice:list",
-			},
-			expectedSQL: "u.user_id = 123",
-			description: "只配置UserAlias时，使用user_id字段",
-		},
-		{
-			name: "仅创建者权限",
-			config: &DataScopeConfig{
-				DeptAlias:    "d",
-				CreatorAlias: "c",
-				Permission:   "system:notice:list",
-			},
-			expectedSQL: "c.create_by = 'testuser'",
-			description: "只配置CreatorAlias时，使用create_by字段",
-		},
-		{
-			name: "混合权限",
-			config: &DataScopeConfig{
-				DeptAlias:    "d",
-				UserAlias:    "u",
-				CreatorAlias: "c",
-				Permission:   "system:notice:list",
-			},
-			expectedSQL: "u.user_id = 123 OR c.create_by = 'testuser'",
-			description: "同时配置UserAlias和CreatorAlias时，使用OR连接",
-		},
-		{
-			name: "无权限别名",
-			config: &DataScopeConfig{
-				DeptAlias:  "d",
-				Permission: "system:notice:list",
-			},
-			expectedSQL: "d.dept_id = 0",
-			description: "没有配置任何权限别名时，返回限制性条件",
-		},
+		{"未登录用户", nil, &Predicate{}},
+		{"超级管理员", &model.SysUser{UserID: 1}, &Predicate{All: true}},
+		{"全部数据权限", newScopeUser(newScopeRole(2, DataScopeDept), newScopeRole(3, DataScopeAll)), &Predicate{All: true}},
+		{"本部门数据权限", newScopeUser(newScopeRole(2, DataScopeDept)),
+			&Predicate{DeptIDs: []int64{100}, UserID: 123, UserName: "testuser"}},
+		{"本部门及以下数据权限", newScopeUser(newScopeRole(2, DataScopeDeptAndChild)),
+			&Predicate{DeptTrees: []int64{100}, UserID: 123, UserName: "testuser"}},
+		{"多个自定数据权限角色", newScopeUser(newScopeRole(2, DataScopeCustom), newScopeRole(3, DataScopeCustom)),
+			&Predicate{RoleIDs: []int64{2, 3}, UserID: 123, UserName: "testuser"}},
+		{"多个角色取并集", newScopeUser(newScopeRole(2, DataScopeSelf), newScopeRole(3, DataScopeDept)),
+			&Predicate{DeptIDs: []int64{100}, Self: true, UserID: 123, UserName: "testuser"}},
+		{"停用和没有权限的角色不生效", newScopeUser(disabled, unpermitted, newScopeRole(2, DataScopeSelf)),
+			&Predicate{Self: true, UserID: 123, UserName: "testuser"}},
+		{"没有有效角色", newScopeUser(disabled, unpermitted), &Predicate{UserID: 123, UserName: "testuser"}},
 	}
 
-	processor := NewDataScopeProcessor()
-
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			actualSQL := processor.generateDataScopeSQL(user, tt.config)
-			
-			if actualSQL != tt.expectedSQL {
-				t.Errorf("测试 %s 失败:\n期望SQL: %s\n实际SQL: %s\n描述: %s", 
-					tt.name, tt.expectedSQL, actualSQL, tt.description)
-			} else {
-				t.Logf("测试 %s 成功: %s", tt.name, tt.description)
+			actual := Build(tt.user, "system:notice:list")
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("Build() = %+v, 期望 %+v", actual, tt.expected)
 			}
 		})
 	}
 }
 
-// TestDataScopeWithDifferentRoles 测试不同角色的数据权限
-func TestDataScopeWithDifferentRoles(t *testing.T) {
-	user := &model.SysUser{
-		UserID:   123,
-		UserName: "testuser",
-		DeptID:   func() *int64 { id := int64(100); return &id }(),
-	}
-
-	config := &DataScopeConfig{
-		DeptAlias:    "d",
-		CreatorAlias: "c",
-		Permission:   "system:notice:list",
-	}
-
+// TestPredicateCondition 测试数据权限条件按声明的字段编译为参数化SQL
+func TestPredicateCondition(t *testing.T) {
+	columns := Columns{Dept: "d.dept_id", User: "u.user_id", Creator: "n.create_by"}
 	tests := []struct {
-		name        string
-		roles       []model.SysRole
-		expectedSQL string
-		description string
+		name      string
+		predicate *Predicate
+		columns   Columns
+		sql       string
+		args      []interface{}
 	}{
-		{
-			name: "全部数据权限",
-			roles: []model.SysRole{
-				{
-					RoleID:      1,
-					DataScope:   DataScopeAll,
-					Status:      RoleStatusNormal,
-					Permissions: []string{"system:notice:list"},
-				},
-			},
-			expectedSQL: "",
-			description: "全部数据权限不添加任何条件",
-		},
-		{
-			name: "本部门数据权限",
-			roles: []model.SysRole{
-				{
-					RoleID:      2,
-					DataScope:   DataScopeDept,
-					Status:      RoleStatusNormal,
-					Permissions: []string{"system:notice:list"},
-				},
-			},
-			expectedSQL: "d.dept_id = 100",
-			description: "本部门数据权限基于用户部门ID",
-		},
-		{
-			name: "仅本人数据权限（创建者）",
-			roles: []model.SysRole{
-				{
-					RoleID:      3,
-					DataScope:   DataScopeSelf,
-					Status:      RoleStatusNormal,
-					Permissions: []string{"system:notice:list"},
-				},
-			},
-			expectedSQL: "c.create_by = 'testuser'",
-			description: "仅本人数据权限基于创建者字段",
-		},
+		{"未设置数据权限", nil, columns, "", nil},
+		{"全部数据权限", &Predicate{All: true}, columns, "", nil},
+		{"没有可访问的范围", &Predicate{}, columns, "1 = 0", nil},
+		{"部门和自定数据权限", &Predicate{RoleIDs: []int64{2}, DeptIDs: []int64{100}}, columns,
+			"(d.dept_id IN (SELECT dept_id FROM sys_role_dept WHERE role_id IN ?) OR d.dept_id IN ?)",
+			[]interface{}{[]int64{2}, []int64{100}}},
+		{"仅本人数据权限", &Predicate{Self: true, UserID: 123, UserName: "' OR '1'='1"}, columns,
+			"(u.user_id = ? OR n.create_by = ?)", []interface{}{int64(123), "' OR '1'='1"}},
+		{"查询中没有对应字段", &Predicate{Self: true, DeptIDs: []int64{100}}, Columns{}, "1 = 0", nil},
 	}
 
-	processor := NewDataScopeProcessor()
-
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			user.Roles = tt.roles
-			actualSQL := processor.generateDataScopeSQL(user, config)
-			
-			if actualSQL != tt.expectedSQL {
-				t.Errorf("测试 %s 失败:\n期望SQL: %s\n实际SQL: %s\n描述: %s", 
-					tt.name, tt.expectedSQL, actualSQL, tt.description)
-			} else {
-				t.Logf("测试 %s 成功: %s", tt.name, tt.description)
+			condition := tt.predicate.Condition(tt.columns)
+			if condition.SQL != tt.sql || !reflect.DeepEqual(condition.Args, tt.args) {
+				t.Errorf("Condition() = %s %v, 期望 %s %v", condition.SQL, condition.Args, tt.sql, tt.args)
 			}
 		})
 	}
 }
-
-// TestApplyDataScopeWithCreator 测试ApplyDataScopeWithCreator函数
-func TestApplyDataScopeWithCreator(t *testing.T) {
-	user := &model.SysUser{
-		UserID:   123,
-		UserName: "testuser",
-		DeptID:   func() *int64 { id := int64(100); return &id }(),
-		Roles: []model.SysRole{
-			{
-				RoleID:      2,
-				DataScope:   DataScopeSelf,
-				Status:      RoleStatusNormal,
-				Permissions: []string{"system:notice:list"},
-			},
-		},
-	}
-
-	params := make(map[string]interface{})
-
-	err := ApplyDataScopeWithCreator(user, "d", "", "c", "system:notice:list", params)
-	if err != nil {
-		t.Fatalf("ApplyDataScopeWithCreator 失败: %v", err)
-	}
-
-	dataScope, exists := params["dataScope"]
-	if !exists {
-		t.Fatal("数据权限参数未设置")
-	}
-
-	expectedSQL := " AND (c.create_by = 'testuser')"
-	if dataScope != expectedSQL {
-		t.Errorf("数据权限SQL不正确:\n期望: %s\n实际: %s", expectedSQL, dataScope)
-	} else {
-		t.Logf("ApplyDataScopeWithCreator 测试成功: %s", dataScope)
-	}
-}
@@ -0,0 +1,113 @@
+package datascope
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"wosm/pkg/database"
+
+	"gorm.io/gorm"
+)
+
+// Predicate 数据权限条件 由用户角色的数据范围生成，各条件之间为或的关系；不包含任何条件时不能查询任何数据
+// 条件只记录可以访问的范围，由各DAO按Columns声明的字段编译为参数化的查询条件
+type Predicate struct {
+	All       bool    // 全部数据权限，不过滤
+	DeptIDs   []int64 // 部门数据权限：只能访问这些部门的数据
+	DeptTrees []int64 // 部门及以下数据权限：可以访问这些部门及其全部下级部门的数据
+	RoleIDs   []int64 // 自定数据权限：可以访问这些角色在sys_role_dept中分配的部门的数据
+	Self      bool    // 仅本人数据权限：按用户字段或创建者字段匹配当前用户
+	UserID    int64   // 当前用户ID，用于仅本人数据权限
+	UserName  string  // 当前用户账号，用于按创建者匹配的仅本人数据权限
+}
+
+// Columns 查询中数据权限字段的完整名称（带表别名），由各DAO按自己的查询声明 对应Java后端@DataScope的deptAlias、userAlias
+// 字段为空表示查询中没有该字段，对应的数据权限不匹配任何数据
+type Columns struct {
+	Dept    string // 部门字段，如 d.dept_id
+	User    string // 用户字段，如 u.user_id
+	Creator string // 创建者字段，如 create_by
+}
+
+// Condition 编译后的参数化条件，SQL为空表示不过滤；用于拼接原生SQL的场景（如运行时模块）
+type Condition struct {
+	SQL  string
+	Args []interface{}
+}
+
+// Condition 按字段编译数据权限条件
+func (p *Predicate) Condition(columns Columns) Condition {
+	if p == nil || p.All {
+		return Condition{}
+	}
+
+	var parts []string
+	var args []interface{}
+	if columns.Dept != "" {
+		if len(p.RoleIDs) > 0 {
+			parts = append(parts, columns.Dept+" IN (SELECT dept_id FROM sys_role_dept WHERE role_id IN ?)")
+			args = append(args, p.RoleIDs)
+		}
+		if len(p.DeptIDs) > 0 {
+			parts = append(parts, columns.Dept+" IN ?")
+			args = append(args, p.DeptIDs)
+		}
+		for _, deptId := range p.DeptTrees {
+			// find_in_set(deptId, ancestors) 由方言翻译为各数据库的写法
+			parts = append(parts, fmt.Sprintf("%s IN (SELECT dept_id FROM sys_dept WHERE dept_id = ? OR %s)",
+				columns.Dept, database.CurrentDialect().FindInSet("?", "ancestors")))
+			args = append(args, deptId, strconv.FormatInt(deptId, 10))
+		}
+	}
+	if p.Self {
+		if columns.User != "" {
+			parts = append(parts, columns.User+" = ?")
+			args = append(args, p.UserID)
+		}
+		if columns.Creator != "" {
+			parts = append(parts, columns.Creator+" = ?")
+			args = append(args, p.UserName)
+		}
+	}
+
+	// 没有可以访问的范围时不查询任何数据
+	if len(parts) == 0 {
+		return Condition{SQL: "1 = 0"}
+	}
+	return Condition{SQL: "(" + strings.Join(parts, " OR ") + ")", Args: args}
+}
+
+// Scope 按字段编译为GORM查询范围，用法：query.Scopes(predicate.Scope(columns))；predicate为nil时不过滤
+func (p *Predicate) Scope(columns Columns) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		condition := p.Condition(columns)
+		if condition.SQL == "" {
+			return db
+		}
+		return db.Where(condition.SQL, condition.Args...)
+	}
+}
+
+// String 数据权限条件的描述，用于日志
+func (p *Predicate) String() string {
+	if p == nil || p.All {
+		return "全部数据权限"
+	}
+	var parts []string
+	if len(p.RoleIDs) > 0 {
+		parts = append(parts, fmt.Sprintf("自定数据权限%v", p.RoleIDs))
+	}
+	if len(p.DeptIDs) > 0 {
+		parts = append(parts, fmt.Sprintf("部门数据权限%v", p.DeptIDs))
+	}
+	if len(p.DeptTrees) > 0 {
+		parts = append(parts, fmt.Sprintf("部门及以下数据权限%v", p.DeptTrees))
+	}
+	if p.Self {
+		parts = append(parts, "仅本人数据权限")
+	}
+	if len(parts) == 0 {
+		return "无数据权限"
+	}
+	return strings.Join(parts, "、")
+}
@@ -0,0 +1,54 @@
+package datascope
+
+import (
+	"reflect"
+	"testing"
+	"wosm/internal/testutil"
+)
+
+// TestPredicateScope 在SQLite上执行数据权限查询范围
+func TestPredicateScope(t *testing.T) {
+	db := testutil.OpenMigratedDB(t)
+	userColumns := Columns{Dept: "u.dept_id", User: "u.user_id"}
+
+	tests := []struct {
+		name      string
+		predicate *Predicate
+		table     string
+		column    string
+		columns   Columns
+		expected  []int64
+	}{
+		{"未设置数据权限", nil, "sys_user u", "u.user_id", userColumns, []int64{1, 2}},
+		{"全部数据权限", &Predicate{All: true}, "sys_user u", "u.user_id", userColumns, []int64{1, 2}},
+		{"本部门数据权限", &Predicate{DeptIDs: []int64{105}}, "sys_user u", "u.user_id", userColumns, []int64{2}},
+		{"本部门及以下数据权限", &Predicate{DeptTrees: []int64{101}}, "sys_dept d", "d.dept_id", Columns{Dept: "d.dept_id"},
+			[]int64{101, 103, 104, 105, 106, 107}},
+		{"下级部门没有用户", &Predicate{DeptTrees: []int64{102}}, "sys_user u", "u.user_id", userColumns, []int64{}},
+		{"自定数据权限", &Predicate{RoleIDs: []int64{2}}, "sys_user u", "u.user_id", userColumns, []int64{2}},
+		{"仅本人数据权限", &Predicate{Self: true, UserID: 2, UserName: "ry"}, "sys_user u", "u.user_id", userColumns, []int64{2}},
+		{"按创建者匹配本人", &Predicate{Self: true, UserID: 1, UserName: "admin"}, "sys_notice n", "n.notice_id",
+			Columns{Creator: "n.create_by"}, []int64{1, 2}},
+		{"创建者参数不拼接到SQL", &Predicate{Self: true, UserName: "' OR '1'='1"}, "sys_notice n", "n.notice_id",
+			Columns{Creator: "n.create_by"}, []int64{}},
+		{"没有可访问的范围", &Predicate{UserID: 2}, "sys_user u", "u.user_id", userColumns, []int64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 同一查询先统计总数再查询列表，两次都应用数据权限
+			query := db.Table(tt.table).Scopes(tt.predicate.Scope(tt.columns))
+			var total int64
+			if err := query.Count(&total).Error; err != nil {
+				t.Fatalf("统计失败: %v", err)
+			}
+			ids := []int64{}
+			if err := query.Order(tt.column).Pluck(tt.column, &ids).Error; err != nil {
+				t.Fatalf("查询失败: %v", err)
+			}
+			if !reflect.DeepEqual(ids, tt.expected) || total != int64(len(tt.expected)) {
+				t.Errorf("查询结果 = %v（总数%d），期望 %v", ids, total, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,264 @@
+// Package ddl 表结构设计器：将结构化的表定义渲染为各数据库方言的建表语句，
+// 并校验手写的建表SQL只包含 CREATE TABLE 语句
+package ddl
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 列类型（与数据库无关的逻辑类型，由方言映射为具体类型）
+const (
+	TypeVarchar  = "varchar"  // 变长字符串，length为0时不限长度
+	TypeChar     = "char"     // 定长字符串
+	TypeText     = "text"     // 长文本
+	TypeSmallint = "smallint" // 短整数
+	TypeInt      = "int"      // 整数
+	TypeBigint   = "bigint"   // 长整数
+	TypeDecimal  = "decimal"  // 定点小数，length为精度，scale为小数位数
+	TypeBoolean  = "boolean"  // 布尔
+	TypeDate     = "date"     // 日期
+	TypeDatetime = "datetime" // 日期时间
+)
+
+// DefaultCurrentTimestamp 日期时间列的默认值为当前时间
+const DefaultCurrentTimestamp = "CURRENT_TIMESTAMP"
+
+// Table 表定义
+type Table struct {
+	Name    string   `json:"tableName"`    // 表名
+	Comment string   `json:"tableComment"` // 表注释
+	Columns []Column `json:"columns"`      // 列
+	Indexes []Index  `json:"indexes"`      // 索引
+}
+
+// Column 列定义
+type Column struct {
+	Name          string  `json:"columnName"`    // 列名
+	Type          string  `json:"columnType"`    // 逻辑类型
+	Length        int     `json:"length"`        // 长度（decimal为精度）
+	Scale         int     `json:"scale"`         // 小数位数
+	Nullable      bool    `json:"nullable"`      // 是否允许为空
+	Default       *string `json:"defaultValue"`  // 默认值，nil表示没有默认值
+	Comment       string  `json:"columnComment"` // 列注释
+	PrimaryKey    bool    `json:"primaryKey"`    // 是否主键
+	AutoIncrement bool    `json:"autoIncrement"` // 是否自增
+}
+
+// Index 索引定义
+type Index struct {
+	Name    string   `json:"indexName"` // 索引名，为空时自动生成
+	Columns []string `json:"columns"`   // 索引列
+	Unique  bool     `json:"unique"`    // 是否唯一索引
+}
+
+// Statement 建表SQL中解析出的一条 CREATE TABLE 语句
+type Statement struct {
+	TableName string `json:"tableName"` // 表名（不含架构）
+	SQL       string `json:"sql"`       // 语句
+}
+
+// Dialect 数据库方言
+type Dialect interface {
+	// Name 方言名称，与 database.driver 配置一致
+	Name() string
+	// CreateTable 渲染建表语句（包含主键、索引和注释），按顺序执行
+	CreateTable(table *Table) ([]string, error)
+	// ColumnType 列的数据库类型（小写，与代码生成导入表时读取的列类型格式一致）
+	ColumnType(column *Column) string
+	// ParseCreateTable 拆分并校验手写的建表SQL，只允许 CREATE TABLE 语句
+	ParseCreateTable(sql string) ([]Statement, error)
+}
+
+var dialects = map[string]Dialect{}
+
+// register 注册方言
+func register(dialect Dialect) {
+	dialects[dialect.Name()] = dialect
+}
+
+// GetDialect 根据数据库驱动获取方言，driver 为空时使用SQL Server
+func GetDialect(driver string) (Dialect, error) {
+	if driver == "" {
+		driver = "sqlserver"
+	}
+	dialect, ok := dialects[driver]
+	if !ok {
+		return nil, fmt.Errorf("表结构设计器不支持数据库类型: %s", driver)
+	}
+	return dialect, nil
+}
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]{0,63}$`)
+
+// Validate 校验表定义，方言渲染前调用；同时补全自动生成的索引名
+func (t *Table) Validate() error {
+	if !identifierPattern.MatchString(t.Name) {
+		return fmt.Errorf("表名'%s'不合法，只能包含字母、数字和下划线，且不能以数字开头", t.Name)
+	}
+	if len(t.Columns) == 0 {
+		return fmt.Errorf("表'%s'至少需要一列", t.Name)
+	}
+
+	columns := make(map[string]*Column, len(t.Columns))
+	autoIncrement := 0
+	for i := range t.Columns {
+		column := &t.Columns[i]
+		if err := column.Validate(); err != nil {
+			return err
+		}
+		key := strings.ToLower(column.Name)
+		if _, ok := columns[key]; ok {
+			return fmt.Errorf("列名'%s'重复", column.Name)
+		}
+		columns[key] = column
+		if column.AutoIncrement {
+			autoIncrement++
+		}
+	}
+	if autoIncrement > 1 {
+		return fmt.Errorf("一张表只能有一个自增列")
+	}
+
+	names := make(map[string]bool, len(t.Indexes))
+	for i := range t.Indexes {
+		index := &t.Indexes[i]
+		if len(index.Columns) == 0 {
+			return fmt.Errorf("索引至少需要一列")
+		}
+		seen := make(map[string]bool, len(index.Columns))
+		for _, name := range index.Columns {
+			if _, ok := columns[strings.ToLower(name)]; !ok {
+				return fmt.Errorf("索引列'%s'不存在", name)
+			}
+			if seen[strings.ToLower(name)] {
+				return fmt.Errorf("索引列'%s'重复", name)
+			}
+			seen[strings.ToLower(name)] = true
+		}
+		if index.Name == "" {
+			prefix := "idx"
+			if index.Unique {
+				prefix = "uk"
+			}
+			index.Name = fmt.Sprintf("%s_%s_%s", prefix, t.Name, strings.Join(index.Columns, "_"))
+			if len(index.Name) > 64 {
+				index.Name = fmt.Sprintf("%s_%s_%d", prefix, t.Name, i+1)
+			}
+		}
+		if !identifierPattern.MatchString(index.Name) {
+			return fmt.Errorf("索引名'%s'不合法", index.Name)
+		}
+		if names[strings.ToLower(index.Name)] {
+			return fmt.Errorf("索引名'%s'重复", index.Name)
+		}
+		names[strings.ToLower(index.Name)] = true
+	}
+	return nil
+}
+
+// PrimaryKeys 主键列名
+func (t *Table) PrimaryKeys() []string {
+	var keys []string
+	for _, column := range t.Columns {
+		if column.PrimaryKey {
+			keys = append(keys, column.Name)
+		}
+	}
+	return keys
+}
+
+// Validate 校验列定义（不含表级规则，如列名重复）
+func (c *Column) Validate() error {
+	if !identifierPattern.MatchString(c.Name) {
+		return fmt.Errorf("列名'%s'不合法，只能包含字母、数字和下划线，且不能以数字开头", c.Name)
+	}
+
+	switch c.Type {
+	case TypeVarchar:
+		if c.Length < 0 || c.Length > 4000 {
+			return fmt.Errorf("列'%s'的长度必须在1到4000之间（0表示不限长度）", c.Name)
+		}
+	case TypeChar:
+		if c.Length < 1 || c.Length > 4000 {
+			return fmt.Errorf("列'%s'的长度必须在1到4000之间", c.Name)
+		}
+	case TypeDecimal:
+		if c.Length < 1 || c.Length > 38 {
+			return fmt.Errorf("列'%s'的精度必须在1到38之间", c.Name)
+		}
+		if c.Scale < 0 || c.Scale > c.Length {
+			return fmt.Errorf("列'%s'的小数位数必须在0到精度之间", c.Name)
+		}
+	case TypeText, TypeSmallint, TypeInt, TypeBigint, TypeBoolean, TypeDate, TypeDatetime:
+	default:
+		return fmt.Errorf("列'%s'的类型'%s'不支持", c.Name, c.Type)
+	}
+
+	if c.AutoIncrement {
+		if c.Type != TypeInt && c.Type != TypeBigint {
+			return fmt.Errorf("自增列'%s'必须是int或bigint类型", c.Name)
+		}
+		if !c.PrimaryKey {
+			return fmt.Errorf("自增列'%s'必须是主键", c.Name)
+		}
+		if c.Default != nil {
+			return fmt.Errorf("自增列'%s'不能设置默认值", c.Name)
+		}
+	}
+	if c.PrimaryKey && c.Nullable {
+		return fmt.Errorf("主键列'%s'不能为空", c.Name)
+	}
+	if c.Default != nil {
+		if _, err := c.defaultValue(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// defaultValue 校验并规范化默认值，返回类型化的值（字符串类型原样返回，由方言负责转义）
+func (c *Column) defaultValue() (string, error) {
+	value := *c.Default
+	switch c.Type {
+	case TypeSmallint, TypeInt, TypeBigint:
+		if _, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64); err != nil {
+			return "", fmt.Errorf("列'%s'的默认值'%s'不是整数", c.Name, value)
+		}
+		return strings.TrimSpace(value), nil
+	case TypeDecimal:
+		if _, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err != nil || strings.ContainsAny(value, "eEnN") {
+			return "", fmt.Errorf("列'%s'的默认值'%s'不是数字", c.Name, value)
+		}
+		return strings.TrimSpace(value), nil
+	case TypeBoolean:
+		switch strings.ToLower(strings.TrimSpace(value)) {
+		case "1", "true":
+			return "1", nil
+		case "0", "false":
+			return "0", nil
+		}
+		return "", fmt.Errorf("列'%s'的默认值'%s'不是布尔值", c.Name, value)
+	case TypeDate, TypeDatetime:
+		value = strings.TrimSpace(value)
+		if strings.EqualFold(value, DefaultCurrentTimestamp) {
+			return DefaultCurrentTimestamp, nil
+		}
+		layouts := []string{"2006-01-02", "2006-01-02 15:04:05"}
+		for _, layout := range layouts {
+			if _, err := time.Parse(layout, value); err == nil {
+				return value, nil
+			}
+		}
+		return "", fmt.Errorf("列'%s'的默认值'%s'不是日期", c.Name, value)
+	case TypeText:
+		return "", fmt.Errorf("长文本列'%s'不能设置默认值", c.Name)
+	}
+	if c.Length > 0 && len([]rune(value)) > c.Length {
+		return "", fmt.Errorf("列'%s'的默认值超过列长度", c.Name)
+	}
+	return value, nil
+}
@@ -0,0 +1,266 @@
+package ddl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestSqlServerCreateTable(t *testing.T) {
+	dialect, err := GetDialect("sqlserver")
+	require.NoError(t, err)
+
+	table := &Table{
+		Name:    "biz_order",
+		Comment: "订单",
+		Columns: []Column{
+			{Name: "order_id", Type: TypeBigint, PrimaryKey: true, AutoIncrement: true, Comment: "订单ID"},
+			{Name: "order_no", Type: TypeVarchar, Length: 32, Comment: "订单号"},
+			{Name: "amount", Type: TypeDecimal, Length: 10, Scale: 2, Default: strPtr("0")},
+			{Name: "remark", Type: TypeVarchar, Length: 200, Nullable: true, Default: strPtr("it's")},
+			{Name: "paid", Type: TypeBoolean, Default: strPtr("false")},
+			{Name: "create_time", Type: TypeDatetime, Nullable: true, Default: strPtr("current_timestamp")},
+		},
+		Indexes: []Index{{Columns: []string{"order_no"}, Unique: true}},
+	}
+
+	statements, err := dialect.CreateTable(table)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"CREATE TABLE [dbo].[biz_order] (\n" +
+			"  [order_id] BIGINT IDENTITY(1,1) NOT NULL,\n" +
+			"  [order_no] NVARCHAR(32) NOT NULL,\n" +
+			"  [amount] DECIMAL(10,2) NOT NULL DEFAULT 0,\n" +
+			"  [remark] NVARCHAR(200) NULL DEFAULT N'it''s',\n" +
+			"  [paid] BIT NOT NULL DEFAULT 0,\n" +
+			"  [create_time] DATETIME NULL DEFAULT GETDATE(),\n" +
+			"  CONSTRAINT [PK_biz_order] PRIMARY KEY ([order_id])\n" +
+			")",
+		"CREATE UNIQUE INDEX [uk_biz_order_order_no] ON [dbo].[biz_order] ([order_no])",
+		"EXEC sp_addextendedproperty N'MS_Description', N'订单', N'SCHEMA', N'dbo', N'TABLE', N'biz_order'",
+		"EXEC sp_addextendedproperty N'MS_Description', N'订单ID', N'SCHEMA', N'dbo', N'TABLE', N'biz_order', N'COLUMN', N'order_id'",
+		"EXEC sp_addextendedproperty N'MS_Description', N'订单号', N'SCHEMA', N'dbo', N'TABLE', N'biz_order', N'COLUMN', N'order_no'",
+	}, statements)
+}
+
+func TestTableValidate(t *testing.T) {
+	id := Column{Name: "id", Type: TypeBigint, PrimaryKey: true}
+	tests := []struct {
+		name  string
+		table Table
+	}{
+		{"表名注入", Table{Name: "t; DROP TABLE sys_user", Columns: []Column{id}}},
+		{"没有列", Table{Name: "t"}},
+		{"列名注入", Table{Name: "t", Columns: []Column{{Name: "a]b", Type: TypeInt}}}},
+		{"列名重复", Table{Name: "t", Columns: []Column{id, {Name: "ID", Type: TypeInt}}}},
+		{"类型不支持", Table{Name: "t", Columns: []Column{{Name: "a", Type: "xml"}}}},
+		{"字符串长度越界", Table{Name: "t", Columns: []Column{{Name: "a", Type: TypeVarchar, Length: 5000}}}},
+		{"小数位数大于精度", Table{Name: "t", Columns: []Column{{Name: "a", Type: TypeDecimal, Length: 5, Scale: 6}}}},
+		{"自增列不是主键", Table{Name: "t", Columns: []Column{{Name: "a", Type: TypeInt, AutoIncrement: true}}}},
+		{"主键可为空", Table{Name: "t", Columns: []Column{{Name: "a", Type: TypeInt, PrimaryKey: true, Nullable: true}}}},
+		{"整数默认值注入", Table{Name: "t", Columns: []Column{{Name: "a", Type: TypeInt, Default: strPtr("1); DROP TABLE x --")}}}},
+		{"日期默认值不合法", Table{Name: "t", Columns: []Column{{Name: "a", Type: TypeDate, Default: strPtr("tomorrow")}}}},
+		{"默认值超长", Table{Name: "t", Columns: []Column{{Name: "a", Type: TypeVarchar, Length: 2, Default: strPtr("abc")}}}},
+		{"索引列不存在", Table{Name: "t", Columns: []Column{id}, Indexes: []Index{{Columns: []string{"x"}}}}},
+		{"索引名注入", Table{Name: "t", Columns: []Column{id}, Indexes: []Index{{Name: "i]; --", Columns: []string{"id"}}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Error(t, tt.table.Validate())
+		})
+	}
+}
+
+func TestSqlServerParseCreateTable(t *testing.T) {
+	dialect, err := GetDialect("")
+	require.NoError(t, err)
+
+	sql := `-- 订单表
+CREATE TABLE [dbo].[biz_order] (
+  [order_id] BIGINT IDENTITY(1,1) NOT NULL PRIMARY KEY,
+  [remark] NVARCHAR(200) DEFAULT N'; DROP TABLE sys_user; --',
+  [user_id] BIGINT REFERENCES sys_user(user_id) ON DELETE SET NULL
+) ON [PRIMARY]
+GO
+/* 明细 /* 嵌套 */ */
+create table biz_order_item (item_id bigint not null);`
+
+	statements, err := dialect.ParseCreateTable(sql)
+	require.NoError(t, err)
+	require.Len(t, statements, 2)
+	assert.Equal(t, "biz_order", statements[0].TableName)
+	assert.Contains(t, statements[0].SQL, "ON [PRIMARY]")
+	assert.NotContains(t, statements[0].SQL, "订单表")
+	assert.Equal(t, "biz_order_item", statements[1].TableName)
+	assert.Equal(t, "create table biz_order_item (item_id bigint not null)", statements[1].SQL)
+}
+
+func TestSqlServerParseCreateTableRejects(t *testing.T) {
+	dialect, err := GetDialect("sqlserver")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		sql  string
+	}{
+		{"空语句", " -- 注释\n;"},
+		{"不是建表语句", "DROP TABLE sys_user"},
+		{"追加其它语句", "CREATE TABLE t (id INT); DELETE FROM sys_user"},
+		{"GO之后的其它语句", "CREATE TABLE t (id INT)\nGO\nEXEC xp_cmdshell 'dir'"},
+		{"括号之后追加语句", "CREATE TABLE t (id INT) DROP TABLE sys_user"},
+		{"列定义中的子查询", "CREATE TABLE t (id INT DEFAULT (SELECT MAX(user_id) FROM sys_user))"},
+		{"其它架构", "CREATE TABLE sys.t (id INT)"},
+		{"跨库建表", "CREATE TABLE master.dbo.t (id INT)"},
+		{"临时表", "CREATE TABLE #t (id INT)"},
+		{"括号不匹配", "CREATE TABLE t (id INT"},
+		{"字符串未结束", "CREATE TABLE t (a NVARCHAR(10) DEFAULT 'x)"},
+		{"注释未结束", "CREATE TABLE t (id INT) /* ..."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := dialect.ParseCreateTable(tt.sql)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestGetDialectUnsupported(t *testing.T) {
+	_, err := GetDialect("oracle")
+	assert.Error(t, err)
+}
+
+// newTestTable 各方言共用的测试表定义
+func newTestTable() *Table {
+	return &Table{
+		Name:    "biz_order",
+		Comment: "订单",
+		Columns: []Column{
+			{Name: "order_id", Type: TypeBigint, PrimaryKey: true, AutoIncrement: true, Comment: "订单ID"},
+			{Name: "order_no", Type: TypeVarchar, Length: 32, Comment: "订单号"},
+			{Name: "paid", Type: TypeBoolean, Default: strPtr("true")},
+			{Name: "remark", Type: TypeVarchar, Length: 200, Nullable: true, Default: strPtr(`it's\`)},
+			{Name: "order_date", Type: TypeDate, Nullable: true, Default: strPtr("CURRENT_TIMESTAMP")},
+		},
+		Indexes: []Index{{Columns: []string{"order_no"}, Unique: true}},
+	}
+}
+
+func TestDialectsCreateTable(t *testing.T) {
+	tests := []struct {
+		driver     string
+		statements []string
+	}{
+		{"mysql", []string{
+			"CREATE TABLE `biz_order` (\n" +
+				"  `order_id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '订单ID',\n" +
+				"  `order_no` VARCHAR(32) NOT NULL COMMENT '订单号',\n" +
+				"  `paid` TINYINT(1) NOT NULL DEFAULT 1,\n" +
+				"  `remark` VARCHAR(200) NULL DEFAULT 'it''s\\\\',\n" +
+				"  `order_date` DATE NULL DEFAULT (CURRENT_DATE),\n" +
+				"  PRIMARY KEY (`order_id`)\n" +
+				") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='订单'",
+			"CREATE UNIQUE INDEX `uk_biz_order_order_no` ON `biz_order` (`order_no`)",
+		}},
+		{"postgres", []string{
+			"CREATE TABLE \"biz_order\" (\n" +
+				"  \"order_id\" BIGINT GENERATED BY DEFAULT AS IDENTITY NOT NULL,\n" +
+				"  \"order_no\" CHARACTER VARYING(32) NOT NULL,\n" +
+				"  \"paid\" BOOLEAN NOT NULL DEFAULT TRUE,\n" +
+				"  \"remark\" CHARACTER VARYING(200) NULL DEFAULT 'it''s\\',\n" +
+				"  \"order_date\" DATE NULL DEFAULT CURRENT_DATE,\n" +
+				"  CONSTRAINT \"pk_biz_order\" PRIMARY KEY (\"order_id\")\n" +
+				")",
+			"CREATE UNIQUE INDEX \"uk_biz_order_order_no\" ON \"biz_order\" (\"order_no\")",
+			"COMMENT ON TABLE \"biz_order\" IS '订单'",
+			"COMMENT ON COLUMN \"biz_order\".\"order_id\" IS '订单ID'",
+			"COMMENT ON COLUMN \"biz_order\".\"order_no\" IS '订单号'",
+		}},
+		{"sqlite", []string{
+			"CREATE TABLE \"biz_order\" (\n" +
+				"  \"order_id\" INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,\n" +
+				"  \"order_no\" VARCHAR(32) NOT NULL,\n" +
+				"  \"paid\" BOOLEAN NOT NULL DEFAULT 1,\n" +
+				"  \"remark\" VARCHAR(200) NULL DEFAULT 'it''s\\',\n" +
+				"  \"order_date\" DATE NULL DEFAULT (DATE('now', 'localtime'))\n" +
+				")",
+			"CREATE UNIQUE INDEX \"uk_biz_order_order_no\" ON \"biz_order\" (\"order_no\")",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.driver, func(t *testing.T) {
+			dialect, err := GetDialect(tt.driver)
+			require.NoError(t, err)
+			statements, err := dialect.CreateTable(newTestTable())
+			require.NoError(t, err)
+			assert.Equal(t, tt.statements, statements)
+		})
+	}
+
+	// SQLite的自增列只能是单独的主键
+	table := newTestTable()
+	table.Columns[1].PrimaryKey = true
+	dialect, _ := GetDialect("sqlite")
+	_, err := dialect.CreateTable(table)
+	assert.Error(t, err)
+}
+
+func TestDialectsParseCreateTable(t *testing.T) {
+	tests := []struct {
+		name    string
+		driver  string
+		sql     string
+		tables  []string
+		wantErr bool
+	}{
+		{"MySQL表选项", "mysql", "CREATE TABLE `biz_a` (id BIGINT NOT NULL AUTO_INCREMENT, name VARCHAR(10) CHARACTER SET utf8mb4) ENGINE=InnoDB AUTO_INCREMENT=200 DEFAULT CHARSET=utf8mb4 COMMENT='A表';\n# 注释 DROP\ncreate table biz_b (id int)", []string{"biz_a", "biz_b"}, false},
+		{"MySQL反斜杠转义", "mysql", "CREATE TABLE t (a VARCHAR(10) DEFAULT '\\''); DROP TABLE sys_user; -- ')", nil, true},
+		{"MySQL跨库建表", "mysql", "CREATE TABLE ry.t (id INT)", nil, true},
+		{"MySQL表选项注入", "mysql", "CREATE TABLE t (id INT) ENGINE=InnoDB SELECT * FROM sys_user", nil, true},
+		{"PostgreSQL表空间", "postgres", "CREATE TABLE public.biz_a (id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY) TABLESPACE pg_default", []string{"biz_a"}, false},
+		{"PostgreSQL美元引号", "postgres", "CREATE TABLE t (a TEXT DEFAULT $x$'$x$); DROP TABLE sys_user; --')", nil, true},
+		{"PostgreSQL转义字符串", "postgres", "CREATE TABLE t (a TEXT DEFAULT E'\\''); DROP TABLE sys_user; --')", nil, true},
+		{"PostgreSQL其它架构", "postgres", "CREATE TABLE pg_catalog.t (id INT)", nil, true},
+		{"SQLite表选项", "sqlite", "CREATE TABLE main.biz_a (id INTEGER PRIMARY KEY) WITHOUT ROWID, STRICT", []string{"biz_a"}, false},
+		{"SQLite附加数据库", "sqlite", "CREATE TABLE t (id INT); ATTACH DATABASE 'x.db' AS x", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dialect, err := GetDialect(tt.driver)
+			require.NoError(t, err)
+			statements, err := dialect.ParseCreateTable(tt.sql)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			var tables []string
+			for _, statement := range statements {
+				tables = append(tables, statement.TableName)
+			}
+			assert.Equal(t, tt.tables, tables)
+		})
+	}
+}
+
+func TestSplitScript(t *testing.T) {
+	statements, err := SplitScript("mysql", "-- 部门表\ncreate table t (a varchar(10) default 'x;y');\n\ninsert into t values ('it\\'s;'); # 注释\n;\n")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"create table t (a varchar(10) default 'x;y')", "insert into t values ('it\\'s;')"}, statements)
+
+	statements, err = SplitScript("postgres", "create function f() returns int as $$ select 1; $$ language sql;\nselect f();")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"create function f() returns int as $$ select 1; $$ language sql", "select f()"}, statements)
+
+	// SQL Server按GO拆分，批处理内的分号保留
+	statements, err = SplitScript("", "SET IDENTITY_INSERT [t] ON;\nINSERT INTO [t] VALUES (N'GO');\nGO\n/* 注释 */\nSELECT 1\ngo\n")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"SET IDENTITY_INSERT [t] ON;\nINSERT INTO [t] VALUES (N'GO');", "SELECT 1"}, statements)
+
+	_, err = SplitScript("oracle", "select 1")
+	assert.Error(t, err)
+}
@@ -0,0 +1,152 @@
+package ddl
+
+import (
+	"fmt"
+	"strings"
+)
+
+func init() {
+	register(mysql{})
+}
+
+// mysql MySQL方言，使用InnoDB引擎和utf8mb4字符集，注释写在列定义和表选项中
+type mysql struct{}
+
+// Name 方言名称
+func (mysql) Name() string {
+	return "mysql"
+}
+
+// CreateTable 渲染建表语句
+func (d mysql) CreateTable(table *Table) ([]string, error) {
+	if err := table.Validate(); err != nil {
+		return nil, err
+	}
+
+	tableName := d.quote(table.Name)
+	lines := make([]string, 0, len(table.Columns)+1)
+	for _, column := range table.Columns {
+		line, err := d.columnDefinition(&column)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, "  "+line)
+	}
+	if keys := table.PrimaryKeys(); len(keys) > 0 {
+		lines = append(lines, fmt.Sprintf("  PRIMARY KEY (%s)", d.quoteList(keys)))
+	}
+
+	options := "ENGINE=InnoDB DEFAULT CHARSET=utf8mb4"
+	if table.Comment != "" {
+		options += " COMMENT=" + d.literal(table.Comment)
+	}
+	statements := []string{fmt.Sprintf("CREATE TABLE %s (\n%s\n) %s", tableName, strings.Join(lines, ",\n"), options)}
+	for _, index := range table.Indexes {
+		unique := ""
+		if index.Unique {
+			unique = "UNIQUE "
+		}
+		statements = append(statements, fmt.Sprintf("CREATE %sINDEX %s ON %s (%s)", unique, d.quote(index.Name), tableName, d.quoteList(index.Columns)))
+	}
+	return statements, nil
+}
+
+// ColumnType 列的数据库类型
+func (mysql) ColumnType(column *Column) string {
+	switch column.Type {
+	case TypeVarchar:
+		if column.Length > 0 {
+			return fmt.Sprintf("varchar(%d)", column.Length)
+		}
+		return "text"
+	case TypeChar:
+		return fmt.Sprintf("char(%d)", column.Length)
+	case TypeDecimal:
+		return fmt.Sprintf("decimal(%d,%d)", column.Length, column.Scale)
+	case TypeBoolean:
+		return "tinyint(1)"
+	default:
+		// text、smallint、int、bigint、date、datetime 与逻辑类型同名
+		return column.Type
+	}
+}
+
+// columnDefinition 渲染列定义
+func (d mysql) columnDefinition(column *Column) (string, error) {
+	definition := d.quote(column.Name) + " " + strings.ToUpper(d.ColumnType(column))
+	if column.Nullable {
+		definition += " NULL"
+	} else {
+		definition += " NOT NULL"
+	}
+	if column.AutoIncrement {
+		definition += " AUTO_INCREMENT"
+	}
+
+	if column.Default != nil {
+		if column.Type == TypeVarchar && column.Length == 0 {
+			return "", fmt.Errorf("列'%s'不限长度时不能设置默认值", column.Name)
+		}
+		value, err := column.defaultValue()
+		if err != nil {
+			return "", err
+		}
+		switch column.Type {
+		case TypeVarchar, TypeChar:
+			value = d.literal(value)
+		case TypeDate:
+			if value == DefaultCurrentTimestamp {
+				value = "(CURRENT_DATE)"
+			} else {
+				value = "'" + value + "'"
+			}
+		case TypeDatetime:
+			if value != DefaultCurrentTimestamp {
+				value = "'" + value + "'"
+			}
+		}
+		definition += " DEFAULT " + value
+	}
+	if column.Comment != "" {
+		definition += " COMMENT " + d.literal(column.Comment)
+	}
+	return definition, nil
+}
+
+// quote 引用标识符
+func (mysql) quote(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// quoteList 引用多个标识符
+func (d mysql) quoteList(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = d.quote(name)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// literal 字符串字面量，MySQL的反斜杠也需要转义
+func (mysql) literal(value string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", "''").Replace(value) + "'"
+}
+
+// mysqlRules MySQL建表语句的校验规则
+var mysqlRules = &createRules{
+	syntax: mysqlSyntax,
+	forbidden: forbiddenWords("EXECUTE", "PREPARE", "DO", "HANDLER", "LOAD", "USE", "KILL", "SHUTDOWN",
+		"LOCK", "UNLOCK", "REPLACE", "IGNORE", "OUTFILE", "DUMPFILE", "SLEEP", "BENCHMARK"),
+	options: keyValueOptions(
+		map[string]bool{"ENGINE": true, "AUTO_INCREMENT": true, "CHARSET": true, "SET": true, "COLLATE": true,
+			"COMMENT": true, "ROW_FORMAT": true, "AVG_ROW_LENGTH": true, "KEY_BLOCK_SIZE": true},
+		// DEFAULT CHARSET、CHARACTER SET
+		map[string]bool{"DEFAULT": true, "CHARACTER": true},
+	),
+}
+
+// ParseCreateTable 拆分并校验手写的建表SQL
+// 语句之间用分号分隔；每条语句必须是 CREATE TABLE 名称 (列定义) [ENGINE、CHARSET、COMMENT等表选项]
+func (mysql) ParseCreateTable(sql string) ([]Statement, error) {
+	return parseCreateTable(sql, mysqlRules)
+}
@@ -0,0 +1,162 @@
+package ddl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// createRules 方言的建表语句校验规则
+type createRules struct {
+	syntax    syntax                    // 词法规则
+	schemas   []string                  // 允许的架构名，为空时不允许指定架构
+	forbidden map[string]bool           // 列定义和表选项中不允许出现的关键字（引号内的标识符不受限制）
+	options   func(p *tokenReader) bool // 读取右括号之后的表选项，选项不合法时返回false
+}
+
+// commonForbiddenWords 各方言的建表语句中都不允许出现的关键字
+var commonForbiddenWords = []string{
+	"SELECT", "INSERT", "UPDATE", "DELETE", "MERGE", "DROP", "ALTER", "TRUNCATE",
+	"CREATE", "GRANT", "REVOKE", "SET", "INTO", "CALL",
+}
+
+// forbiddenWords 通用关键字加上方言特有的关键字
+func forbiddenWords(words ...string) map[string]bool {
+	forbidden := make(map[string]bool, len(commonForbiddenWords)+len(words))
+	for _, word := range append(append([]string{}, commonForbiddenWords...), words...) {
+		forbidden[word] = true
+	}
+	return forbidden
+}
+
+// parseCreateTable 拆分并校验手写的建表SQL，每条语句必须是 CREATE TABLE 名称 (列定义) [表选项]
+func parseCreateTable(sql string, rules *createRules) ([]Statement, error) {
+	tokens, err := tokenize(sql, rules.syntax)
+	if err != nil {
+		return nil, err
+	}
+
+	var statements []Statement
+	for _, stmt := range splitStatements(tokens) {
+		statement, err := parseStatement(sql, stmt, rules)
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, statement)
+	}
+	if len(statements) == 0 {
+		return nil, fmt.Errorf("没有找到建表语句")
+	}
+	return statements, nil
+}
+
+// parseStatement 校验一条语句
+func parseStatement(sql string, tokens []token, rules *createRules) (Statement, error) {
+	p := &tokenReader{tokens: tokens}
+	if !p.keyword("CREATE") || !p.keyword("TABLE") {
+		p.pos = 0
+		return Statement{}, fmt.Errorf("只允许CREATE TABLE语句: %s", p.snippet(sql))
+	}
+
+	// 表名：[架构.]表名
+	var parts []string
+	for {
+		tok, ok := p.next()
+		if !ok || (tok.kind != tokenWord && tok.kind != tokenQuoted) {
+			return Statement{}, fmt.Errorf("建表语句缺少表名")
+		}
+		parts = append(parts, tok.value)
+		if !p.punct('.') {
+			break
+		}
+	}
+	if len(parts) > 2 || (len(parts) == 2 && !containsFold(rules.schemas, parts[0])) {
+		if len(rules.schemas) == 0 {
+			return Statement{}, fmt.Errorf("表'%s'不能指定数据库或架构", strings.Join(parts, "."))
+		}
+		return Statement{}, fmt.Errorf("表'%s'只能创建在%s架构下", strings.Join(parts, "."), rules.schemas[0])
+	}
+	tableName := parts[len(parts)-1]
+	if !identifierPattern.MatchString(tableName) {
+		return Statement{}, fmt.Errorf("表名'%s'不合法，只能包含字母、数字和下划线，且不能以数字开头", tableName)
+	}
+
+	// 列定义
+	if !p.punct('(') {
+		return Statement{}, fmt.Errorf("表'%s'缺少列定义", tableName)
+	}
+	depth, prev := 1, ""
+	for depth > 0 {
+		tok, ok := p.next()
+		if !ok {
+			return Statement{}, fmt.Errorf("表'%s'的列定义缺少右括号", tableName)
+		}
+		switch {
+		case tok.kind == tokenPunct && tok.value == "(":
+			depth++
+		case tok.kind == tokenPunct && tok.value == ")":
+			depth--
+		case tok.kind == tokenWord:
+			word := strings.ToUpper(tok.value)
+			// 外键的 ON DELETE / ON UPDATE SET NULL 是允许的
+			allowed := (word == "DELETE" || word == "UPDATE") && prev == "ON" ||
+				word == "SET" && (prev == "DELETE" || prev == "UPDATE" || prev == "CHARACTER")
+			if rules.forbidden[word] && !allowed {
+				return Statement{}, fmt.Errorf("表'%s'的列定义中不允许出现%s", tableName, word)
+			}
+			prev = word
+			continue
+		}
+		prev = ""
+	}
+
+	// 表选项
+	if !rules.options(p) {
+		return Statement{}, fmt.Errorf("表'%s'的表选项不合法: %s", tableName, p.snippet(sql))
+	}
+	if !p.done() {
+		return Statement{}, fmt.Errorf("表'%s'的建表语句之后不允许有其它内容: %s", tableName, p.snippet(sql))
+	}
+
+	return Statement{
+		TableName: tableName,
+		SQL:       sql[tokens[0].start:tokens[len(tokens)-1].end],
+	}, nil
+}
+
+// keyValueOptions 读取 名称 [=] 值 形式的表选项，names为允许的选项名，prefixes为可以出现在选项名之前的关键字
+func keyValueOptions(names, prefixes map[string]bool) func(p *tokenReader) bool {
+	return func(p *tokenReader) bool {
+		for !p.done() {
+			if p.punct(',') {
+				continue
+			}
+			tok, _ := p.next()
+			word := strings.ToUpper(tok.value)
+			if tok.kind != tokenWord {
+				return false
+			}
+			if prefixes[word] {
+				continue
+			}
+			if !names[word] {
+				return false
+			}
+			p.punct('=')
+			value, ok := p.next()
+			if !ok || value.kind == tokenPunct || value.kind == tokenBatch {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// containsFold 忽略大小写判断是否包含
+func containsFold(values []string, value string) bool {
+	for _, item := range values {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
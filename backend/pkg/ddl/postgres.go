@@ -0,0 +1,151 @@
+package ddl
+
+import (
+	"fmt"
+	"strings"
+)
+
+func init() {
+	register(postgres{})
+}
+
+// postgres PostgreSQL方言，表建在当前架构下，注释通过 COMMENT ON 保存（代码生成导入表时读取）
+type postgres struct{}
+
+// Name 方言名称
+func (postgres) Name() string {
+	return "postgres"
+}
+
+// CreateTable 渲染建表语句
+func (d postgres) CreateTable(table *Table) ([]string, error) {
+	if err := table.Validate(); err != nil {
+		return nil, err
+	}
+
+	tableName := d.quote(table.Name)
+	lines := make([]string, 0, len(table.Columns)+1)
+	for _, column := range table.Columns {
+		line, err := d.columnDefinition(&column)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, "  "+line)
+	}
+	if keys := table.PrimaryKeys(); len(keys) > 0 {
+		lines = append(lines, fmt.Sprintf("  CONSTRAINT %s PRIMARY KEY (%s)", d.quote("pk_"+table.Name), d.quoteList(keys)))
+	}
+
+	statements := []string{fmt.Sprintf("CREATE TABLE %s (\n%s\n)", tableName, strings.Join(lines, ",\n"))}
+	for _, index := range table.Indexes {
+		unique := ""
+		if index.Unique {
+			unique = "UNIQUE "
+		}
+		statements = append(statements, fmt.Sprintf("CREATE %sINDEX %s ON %s (%s)", unique, d.quote(index.Name), tableName, d.quoteList(index.Columns)))
+	}
+
+	if table.Comment != "" {
+		statements = append(statements, fmt.Sprintf("COMMENT ON TABLE %s IS %s", tableName, d.literal(table.Comment)))
+	}
+	for _, column := range table.Columns {
+		if column.Comment != "" {
+			statements = append(statements, fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s", tableName, d.quote(column.Name), d.literal(column.Comment)))
+		}
+	}
+	return statements, nil
+}
+
+// ColumnType 列的数据库类型，与 information_schema.columns 的 data_type 一致
+func (postgres) ColumnType(column *Column) string {
+	switch column.Type {
+	case TypeVarchar:
+		if column.Length > 0 {
+			return fmt.Sprintf("character varying(%d)", column.Length)
+		}
+		return "character varying"
+	case TypeChar:
+		return fmt.Sprintf("character(%d)", column.Length)
+	case TypeInt:
+		return "integer"
+	case TypeDecimal:
+		return fmt.Sprintf("numeric(%d,%d)", column.Length, column.Scale)
+	case TypeDatetime:
+		return "timestamp without time zone"
+	default:
+		// text、smallint、bigint、boolean、date 与逻辑类型同名
+		return column.Type
+	}
+}
+
+// columnDefinition 渲染列定义
+func (d postgres) columnDefinition(column *Column) (string, error) {
+	definition := d.quote(column.Name) + " " + strings.ToUpper(d.ColumnType(column))
+	if column.AutoIncrement {
+		definition += " GENERATED BY DEFAULT AS IDENTITY"
+	}
+	if column.Nullable {
+		definition += " NULL"
+	} else {
+		definition += " NOT NULL"
+	}
+
+	if column.Default != nil {
+		value, err := column.defaultValue()
+		if err != nil {
+			return "", err
+		}
+		switch column.Type {
+		case TypeVarchar, TypeChar:
+			value = d.literal(value)
+		case TypeBoolean:
+			value = map[string]string{"1": "TRUE", "0": "FALSE"}[value]
+		case TypeDate:
+			if value == DefaultCurrentTimestamp {
+				value = "CURRENT_DATE"
+			} else {
+				value = "'" + value + "'"
+			}
+		case TypeDatetime:
+			if value != DefaultCurrentTimestamp {
+				value = "'" + value + "'"
+			}
+		}
+		definition += " DEFAULT " + value
+	}
+	return definition, nil
+}
+
+// quote 引用标识符
+func (postgres) quote(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// quoteList 引用多个标识符
+func (d postgres) quoteList(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = d.quote(name)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// literal 字符串字面量
+func (postgres) literal(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// postgresRules PostgreSQL建表语句的校验规则
+var postgresRules = &createRules{
+	syntax:  postgresSyntax,
+	schemas: []string{"public"},
+	forbidden: forbiddenWords("EXECUTE", "PREPARE", "DO", "COPY", "PERFORM", "LISTEN", "NOTIFY", "VACUUM",
+		"LOCK", "PG_SLEEP", "PG_READ_FILE", "LO_IMPORT", "LO_EXPORT", "DBLINK"),
+	options: keyValueOptions(map[string]bool{"TABLESPACE": true}, nil),
+}
+
+// ParseCreateTable 拆分并校验手写的建表SQL
+// 语句之间用分号分隔；每条语句必须是 CREATE TABLE 名称 (列定义) [TABLESPACE 表空间]
+func (postgres) ParseCreateTable(sql string) ([]Statement, error) {
+	return parseCreateTable(sql, postgresRules)
+}
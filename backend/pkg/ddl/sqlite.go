@@ -0,0 +1,156 @@
+package ddl
+
+import (
+	"fmt"
+	"strings"
+)
+
+func init() {
+	register(sqlite{})
+}
+
+// sqlite SQLite方言，用于本地开发和测试；SQLite不保存表和列注释
+type sqlite struct{}
+
+// Name 方言名称
+func (sqlite) Name() string {
+	return "sqlite"
+}
+
+// CreateTable 渲染建表语句；自增列必须是唯一的主键，渲染为 INTEGER PRIMARY KEY AUTOINCREMENT
+func (d sqlite) CreateTable(table *Table) ([]string, error) {
+	if err := table.Validate(); err != nil {
+		return nil, err
+	}
+
+	keys := table.PrimaryKeys()
+	tableName := d.quote(table.Name)
+	lines := make([]string, 0, len(table.Columns)+1)
+	autoIncrement := false
+	for _, column := range table.Columns {
+		if column.AutoIncrement {
+			if len(keys) > 1 {
+				return nil, fmt.Errorf("自增列'%s'必须是唯一的主键", column.Name)
+			}
+			autoIncrement = true
+		}
+		line, err := d.columnDefinition(&column)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, "  "+line)
+	}
+	if len(keys) > 0 && !autoIncrement {
+		lines = append(lines, fmt.Sprintf("  PRIMARY KEY (%s)", d.quoteList(keys)))
+	}
+
+	statements := []string{fmt.Sprintf("CREATE TABLE %s (\n%s\n)", tableName, strings.Join(lines, ",\n"))}
+	for _, index := range table.Indexes {
+		unique := ""
+		if index.Unique {
+			unique = "UNIQUE "
+		}
+		statements = append(statements, fmt.Sprintf("CREATE %sINDEX %s ON %s (%s)", unique, d.quote(index.Name), tableName, d.quoteList(index.Columns)))
+	}
+	return statements, nil
+}
+
+// ColumnType 列的数据库类型，与 pragma_table_info 返回的声明类型一致
+func (sqlite) ColumnType(column *Column) string {
+	if column.AutoIncrement {
+		return "integer"
+	}
+	switch column.Type {
+	case TypeVarchar:
+		if column.Length > 0 {
+			return fmt.Sprintf("varchar(%d)", column.Length)
+		}
+		return "text"
+	case TypeChar:
+		return fmt.Sprintf("char(%d)", column.Length)
+	case TypeDecimal:
+		return fmt.Sprintf("decimal(%d,%d)", column.Length, column.Scale)
+	default:
+		// text、smallint、int、bigint、boolean、date、datetime 与逻辑类型同名
+		return column.Type
+	}
+}
+
+// columnDefinition 渲染列定义
+func (d sqlite) columnDefinition(column *Column) (string, error) {
+	definition := d.quote(column.Name) + " " + strings.ToUpper(d.ColumnType(column))
+	if column.AutoIncrement {
+		definition += " PRIMARY KEY AUTOINCREMENT"
+	}
+	if column.Nullable {
+		definition += " NULL"
+	} else {
+		definition += " NOT NULL"
+	}
+
+	if column.Default != nil {
+		value, err := column.defaultValue()
+		if err != nil {
+			return "", err
+		}
+		switch column.Type {
+		case TypeVarchar, TypeChar:
+			value = d.literal(value)
+		case TypeDate:
+			if value == DefaultCurrentTimestamp {
+				value = "(DATE('now', 'localtime'))"
+			} else {
+				value = "'" + value + "'"
+			}
+		case TypeDatetime:
+			// CURRENT_TIMESTAMP 是UTC时间，与应用写入的本地时间保持一致
+			if value == DefaultCurrentTimestamp {
+				value = "(DATETIME('now', 'localtime'))"
+			} else {
+				value = "'" + value + "'"
+			}
+		}
+		definition += " DEFAULT " + value
+	}
+	return definition, nil
+}
+
+// quote 引用标识符
+func (sqlite) quote(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// quoteList 引用多个标识符
+func (d sqlite) quoteList(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = d.quote(name)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// literal 字符串字面量
+func (sqlite) literal(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// sqliteRules SQLite建表语句的校验规则
+var sqliteRules = &createRules{
+	syntax:    sqliteSyntax,
+	schemas:   []string{"main"},
+	forbidden: forbiddenWords("ATTACH", "DETACH", "PRAGMA", "VACUUM", "REPLACE", "REINDEX", "LOAD_EXTENSION"),
+	options: func(p *tokenReader) bool {
+		for !p.done() {
+			if !p.punct(',') && !p.keyword("STRICT") && !(p.keyword("WITHOUT") && p.keyword("ROWID")) {
+				return false
+			}
+		}
+		return true
+	},
+}
+
+// ParseCreateTable 拆分并校验手写的建表SQL
+// 语句之间用分号分隔；每条语句必须是 CREATE TABLE 名称 (列定义) [WITHOUT ROWID] [STRICT]
+func (sqlite) ParseCreateTable(sql string) ([]Statement, error) {
+	return parseCreateTable(sql, sqliteRules)
+}
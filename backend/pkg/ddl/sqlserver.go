@@ -0,0 +1,160 @@
+package ddl
+
+import (
+	"fmt"
+	"strings"
+)
+
+func init() {
+	register(sqlServer{})
+}
+
+// sqlServer SQL Server方言，表建在dbo架构下，注释保存为 MS_Description 扩展属性（代码生成导入表时读取）
+type sqlServer struct{}
+
+// Name 方言名称
+func (sqlServer) Name() string {
+	return "sqlserver"
+}
+
+// CreateTable 渲染建表语句
+func (d sqlServer) CreateTable(table *Table) ([]string, error) {
+	if err := table.Validate(); err != nil {
+		return nil, err
+	}
+
+	tableName := "[dbo]." + d.quote(table.Name)
+	lines := make([]string, 0, len(table.Columns)+1)
+	for _, column := range table.Columns {
+		line, err := d.columnDefinition(&column)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, "  "+line)
+	}
+	if keys := table.PrimaryKeys(); len(keys) > 0 {
+		lines = append(lines, fmt.Sprintf("  CONSTRAINT %s PRIMARY KEY (%s)", d.quote("PK_"+table.Name), d.quoteList(keys)))
+	}
+
+	statements := []string{fmt.Sprintf("CREATE TABLE %s (\n%s\n)", tableName, strings.Join(lines, ",\n"))}
+	for _, index := range table.Indexes {
+		unique := ""
+		if index.Unique {
+			unique = "UNIQUE "
+		}
+		statements = append(statements, fmt.Sprintf("CREATE %sINDEX %s ON %s (%s)", unique, d.quote(index.Name), tableName, d.quoteList(index.Columns)))
+	}
+
+	if table.Comment != "" {
+		statements = append(statements, fmt.Sprintf("EXEC sp_addextendedproperty N'MS_Description', %s, N'SCHEMA', N'dbo', N'TABLE', %s",
+			d.literal(table.Comment), d.literal(table.Name)))
+	}
+	for _, column := range table.Columns {
+		if column.Comment != "" {
+			statements = append(statements, fmt.Sprintf("EXEC sp_addextendedproperty N'MS_Description', %s, N'SCHEMA', N'dbo', N'TABLE', %s, N'COLUMN', %s",
+				d.literal(column.Comment), d.literal(table.Name), d.literal(column.Name)))
+		}
+	}
+	return statements, nil
+}
+
+// ColumnType 列的数据库类型
+func (sqlServer) ColumnType(column *Column) string {
+	switch column.Type {
+	case TypeVarchar:
+		if column.Length > 0 {
+			return fmt.Sprintf("nvarchar(%d)", column.Length)
+		}
+		return "nvarchar(max)"
+	case TypeChar:
+		return fmt.Sprintf("nchar(%d)", column.Length)
+	case TypeText:
+		return "nvarchar(max)"
+	case TypeDecimal:
+		return fmt.Sprintf("decimal(%d,%d)", column.Length, column.Scale)
+	case TypeBoolean:
+		return "bit"
+	default:
+		// smallint、int、bigint、date、datetime 与逻辑类型同名
+		return column.Type
+	}
+}
+
+// columnDefinition 渲染列定义
+func (d sqlServer) columnDefinition(column *Column) (string, error) {
+	columnType := strings.ToUpper(d.ColumnType(column))
+
+	definition := d.quote(column.Name) + " " + columnType
+	if column.AutoIncrement {
+		definition += " IDENTITY(1,1)"
+	}
+	if column.Nullable {
+		definition += " NULL"
+	} else {
+		definition += " NOT NULL"
+	}
+
+	if column.Default != nil {
+		value, err := column.defaultValue()
+		if err != nil {
+			return "", err
+		}
+		switch column.Type {
+		case TypeVarchar, TypeChar:
+			value = d.literal(value)
+		case TypeDate, TypeDatetime:
+			if value == DefaultCurrentTimestamp {
+				value = "GETDATE()"
+			} else {
+				value = "'" + value + "'"
+			}
+		}
+		definition += " DEFAULT " + value
+	}
+	return definition, nil
+}
+
+// quote 引用标识符
+func (sqlServer) quote(name string) string {
+	return "[" + strings.ReplaceAll(name, "]", "]]") + "]"
+}
+
+// quoteList 引用多个标识符
+func (d sqlServer) quoteList(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = d.quote(name)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// literal Unicode字符串字面量
+func (sqlServer) literal(value string) string {
+	return "N'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// sqlServerRules SQL Server建表语句的校验规则
+var sqlServerRules = &createRules{
+	syntax:  sqlServerSyntax,
+	schemas: []string{"dbo"},
+	forbidden: forbiddenWords("EXEC", "EXECUTE", "DENY", "DECLARE", "USE", "BACKUP", "RESTORE",
+		"SHUTDOWN", "KILL", "BULK", "WAITFOR", "DBCC", "RECONFIGURE", "OPENROWSET", "OPENQUERY", "OPENDATASOURCE"),
+	options: sqlServerOptions,
+}
+
+// ParseCreateTable 拆分并校验手写的建表SQL
+// 语句之间用分号或单独一行的GO分隔；每条语句必须是 CREATE TABLE 名称 (列定义) [ON 文件组] [TEXTIMAGE_ON 文件组]
+func (sqlServer) ParseCreateTable(sql string) ([]Statement, error) {
+	return parseCreateTable(sql, sqlServerRules)
+}
+
+// sqlServerOptions 文件组选项
+func sqlServerOptions(p *tokenReader) bool {
+	for p.keyword("ON") || p.keyword("TEXTIMAGE_ON") {
+		tok, ok := p.next()
+		if !ok || (tok.kind != tokenWord && tok.kind != tokenQuoted) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,357 @@
+package ddl
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// tokenKind 词法单元类型
+type tokenKind int
+
+const (
+	tokenWord   tokenKind = iota // 关键字或未引用的标识符
+	tokenQuoted                  // 引用的标识符，value为去掉引号后的名称
+	tokenString                  // 字符串字面量
+	tokenNumber                  // 数字
+	tokenPunct                   // 标点符号
+	tokenBatch                   // 批处理分隔符（SQL Server的GO）
+)
+
+// token 词法单元，start/end为在原始SQL中的字节位置
+type token struct {
+	kind  tokenKind
+	value string
+	start int
+	end   int
+}
+
+// syntax 方言的词法规则
+type syntax struct {
+	bracketQuote    bool // 支持 [标识符]
+	backtickQuote   bool // 支持 `标识符`
+	batchGo         bool // 单独一行的GO为批处理分隔符
+	nestedBlock     bool // 块注释可以嵌套
+	backslashEscape bool // 字符串中的反斜杠是转义符（MySQL）
+	escapeString    bool // 支持 E'...' 转义字符串（PostgreSQL）
+	dollarQuote     bool // 支持 $标签$...$标签$ 字符串（PostgreSQL）
+	hashComment     bool // # 开始的单行注释（MySQL）
+}
+
+var (
+	sqlServerSyntax = syntax{bracketQuote: true, batchGo: true, nestedBlock: true}
+	mysqlSyntax     = syntax{backtickQuote: true, backslashEscape: true, hashComment: true}
+	postgresSyntax  = syntax{nestedBlock: true, escapeString: true, dollarQuote: true}
+	sqliteSyntax    = syntax{bracketQuote: true, backtickQuote: true}
+)
+
+// syntaxes 数据库驱动对应的词法规则
+var syntaxes = map[string]syntax{
+	"sqlserver": sqlServerSyntax,
+	"mysql":     mysqlSyntax,
+	"postgres":  postgresSyntax,
+	"sqlite":    sqliteSyntax,
+}
+
+// tokenize 词法分析，跳过注释
+func tokenize(sql string, rules syntax) ([]token, error) {
+	var tokens []token
+	runes := []rune(sql)
+	// 字节位置，用于截取原始语句
+	offsets := make([]int, len(runes)+1)
+	for i, pos := 0, 0; i < len(runes); i++ {
+		offsets[i] = pos
+		pos += len(string(runes[i]))
+		offsets[i+1] = pos
+	}
+
+	i := 0
+	for i < len(runes) {
+		r := runes[i]
+		start := i
+		switch {
+		case unicode.IsSpace(r):
+			i++
+			continue
+
+		case (r == '-' && i+1 < len(runes) && runes[i+1] == '-') || (r == '#' && rules.hashComment):
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+			continue
+
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			depth := 0
+			for {
+				if i+1 >= len(runes) {
+					return nil, fmt.Errorf("注释没有结束")
+				}
+				if runes[i] == '/' && runes[i+1] == '*' && (depth == 0 || rules.nestedBlock) {
+					depth++
+					i += 2
+					continue
+				}
+				if runes[i] == '*' && runes[i+1] == '/' {
+					depth--
+					i += 2
+					if depth == 0 {
+						break
+					}
+					continue
+				}
+				i++
+			}
+			continue
+
+		case r == '\'' || ((r == 'N' || r == 'n') && i+1 < len(runes) && runes[i+1] == '\''):
+			if r != '\'' {
+				i++
+			}
+			end, value, err := readQuoted(runes, i, '\'', rules.backslashEscape)
+			if err != nil {
+				return nil, fmt.Errorf("字符串没有结束")
+			}
+			i = end
+			tokens = append(tokens, token{kind: tokenString, value: value, start: offsets[start], end: offsets[i]})
+			continue
+
+		case rules.escapeString && (r == 'E' || r == 'e') && i+1 < len(runes) && runes[i+1] == '\'':
+			end, value, err := readQuoted(runes, i+1, '\'', true)
+			if err != nil {
+				return nil, fmt.Errorf("字符串没有结束")
+			}
+			i = end
+			tokens = append(tokens, token{kind: tokenString, value: value, start: offsets[start], end: offsets[i]})
+			continue
+
+		case rules.dollarQuote && r == '$' && dollarTag(runes, i) != "":
+			tag := []rune(dollarTag(runes, i))
+			i += len(tag)
+			begin := i
+			for !hasPrefix(runes[i:], tag) {
+				if i >= len(runes) {
+					return nil, fmt.Errorf("字符串没有结束")
+				}
+				i++
+			}
+			value := string(runes[begin:i])
+			i += len(tag)
+			tokens = append(tokens, token{kind: tokenString, value: value, start: offsets[start], end: offsets[i]})
+			continue
+
+		case r == '"' || (r == '[' && rules.bracketQuote) || (r == '`' && rules.backtickQuote):
+			closing := r
+			if r == '[' {
+				closing = ']'
+			}
+			end, value, err := readQuoted(runes, i, closing, rules.backslashEscape && r == '"')
+			if err != nil {
+				return nil, fmt.Errorf("标识符%c没有结束", r)
+			}
+			i = end
+			tokens = append(tokens, token{kind: tokenQuoted, value: value, start: offsets[start], end: offsets[i]})
+			continue
+
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, value: string(runes[start:i]), start: offsets[start], end: offsets[i]})
+			continue
+
+		case isWordRune(r):
+			for i < len(runes) && (isWordRune(runes[i]) || unicode.IsDigit(runes[i])) {
+				i++
+			}
+			word := string(runes[start:i])
+			kind := tokenWord
+			if rules.batchGo && strings.EqualFold(word, "GO") && aloneOnLine(runes, start, i) {
+				kind = tokenBatch
+			}
+			tokens = append(tokens, token{kind: kind, value: word, start: offsets[start], end: offsets[i]})
+			continue
+		}
+
+		i++
+		tokens = append(tokens, token{kind: tokenPunct, value: string(r), start: offsets[start], end: offsets[i]})
+	}
+	return tokens, nil
+}
+
+// readQuoted 读取引号包围的内容，连续两个结束引号表示转义，backslash为true时反斜杠也是转义符；返回结束引号之后的位置
+func readQuoted(runes []rune, start int, closing rune, backslash bool) (int, string, error) {
+	var b strings.Builder
+	i := start + 1
+	for i < len(runes) {
+		if backslash && runes[i] == '\\' && i+1 < len(runes) {
+			b.WriteRune(runes[i+1])
+			i += 2
+			continue
+		}
+		if runes[i] == closing {
+			if i+1 < len(runes) && runes[i+1] == closing {
+				b.WriteRune(closing)
+				i += 2
+				continue
+			}
+			return i + 1, b.String(), nil
+		}
+		b.WriteRune(runes[i])
+		i++
+	}
+	return 0, "", fmt.Errorf("没有结束")
+}
+
+// dollarTag 读取 $标签$ 形式的字符串开始标记，不是标记时返回空
+func dollarTag(runes []rune, start int) string {
+	for i := start + 1; i < len(runes); i++ {
+		if runes[i] == '$' {
+			return string(runes[start : i+1])
+		}
+		if !(runes[i] == '_' || unicode.IsLetter(runes[i]) || (i > start+1 && unicode.IsDigit(runes[i]))) {
+			return ""
+		}
+	}
+	return ""
+}
+
+// hasPrefix 判断runes是否以prefix开头
+func hasPrefix(runes, prefix []rune) bool {
+	if len(runes) < len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if runes[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// isWordRune 标识符字符（SQL Server允许 @ # $，由标识符校验拒绝）
+func isWordRune(r rune) bool {
+	return r == '_' || r == '@' || r == '#' || r == '$' || unicode.IsLetter(r)
+}
+
+// aloneOnLine 判断[start,end)是否独占一行
+func aloneOnLine(runes []rune, start, end int) bool {
+	for i := start - 1; i >= 0 && runes[i] != '\n'; i-- {
+		if !unicode.IsSpace(runes[i]) {
+			return false
+		}
+	}
+	for i := end; i < len(runes) && runes[i] != '\n'; i++ {
+		if !unicode.IsSpace(runes[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// splitStatements 按分号和批处理分隔符拆分语句，忽略空语句
+func splitStatements(tokens []token) [][]token {
+	var statements [][]token
+	var current []token
+	for _, tok := range tokens {
+		if tok.kind == tokenBatch || (tok.kind == tokenPunct && tok.value == ";") {
+			if len(current) > 0 {
+				statements = append(statements, current)
+			}
+			current = nil
+			continue
+		}
+		current = append(current, tok)
+	}
+	if len(current) > 0 {
+		statements = append(statements, current)
+	}
+	return statements
+}
+
+// SplitScript 将SQL脚本拆分为逐条执行的语句，保留语句原文，去掉语句之间的注释；driver 为空时使用SQL Server
+// SQL Server按单独一行的GO拆分批处理，批处理内的分号不拆分；其他数据库按分号拆分
+func SplitScript(driver, script string) ([]string, error) {
+	if driver == "" {
+		driver = "sqlserver"
+	}
+	rules, ok := syntaxes[driver]
+	if !ok {
+		return nil, fmt.Errorf("不支持的数据库类型: %s", driver)
+	}
+	tokens, err := tokenize(script, rules)
+	if err != nil {
+		return nil, err
+	}
+
+	var statements []string
+	var current []token
+	flush := func() {
+		if len(current) > 0 {
+			statements = append(statements, script[current[0].start:current[len(current)-1].end])
+		}
+		current = nil
+	}
+	for _, tok := range tokens {
+		if tok.kind == tokenBatch || (!rules.batchGo && tok.kind == tokenPunct && tok.value == ";") {
+			flush()
+			continue
+		}
+		current = append(current, tok)
+	}
+	flush()
+	return statements, nil
+}
+
+// tokenReader 顺序读取一条语句的词法单元
+type tokenReader struct {
+	tokens []token
+	pos    int
+}
+
+// next 读取下一个词法单元
+func (p *tokenReader) next() (token, bool) {
+	if p.pos >= len(p.tokens) {
+		return token{}, false
+	}
+	p.pos++
+	return p.tokens[p.pos-1], true
+}
+
+// keyword 下一个词法单元是指定关键字时读取并返回true
+func (p *tokenReader) keyword(word string) bool {
+	if p.pos < len(p.tokens) && p.tokens[p.pos].kind == tokenWord && strings.EqualFold(p.tokens[p.pos].value, word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// punct 下一个词法单元是指定标点时读取并返回true
+func (p *tokenReader) punct(r rune) bool {
+	if p.pos < len(p.tokens) && p.tokens[p.pos].kind == tokenPunct && p.tokens[p.pos].value == string(r) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// done 是否已读完
+func (p *tokenReader) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+// snippet 当前位置附近的原始SQL，用于错误提示
+func (p *tokenReader) snippet(sql string) string {
+	pos := p.pos
+	if pos >= len(p.tokens) {
+		pos = len(p.tokens) - 1
+	}
+	if pos < 0 {
+		return ""
+	}
+	text := []rune(sql[p.tokens[pos].start:])
+	if len(text) > 40 {
+		text = append(text[:40], []rune("...")...)
+	}
+	return strings.TrimSpace(string(text))
+}
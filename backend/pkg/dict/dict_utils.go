@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 	"wosm/internal/repository/model"
+	"wosm/pkg/database"
 	"wosm/pkg/redis"
 )
 
@@ -29,8 +30,17 @@ func GetInstance() *DictUtils {
 	return dictUtils
 }
 
+// tenantKey 字典缓存键名，context设置了租户时按租户缓存（租户可以覆盖平台租户的字典数据）
+func tenantKey(ctx context.Context, key string) string {
+	if tenantID := database.RequestTenant(ctx); tenantID > model.DefaultTenantID {
+		return fmt.Sprintf("t%d:%s", tenantID, key)
+	}
+	return key
+}
+
 // SetDictCache 设置字典缓存 对应Java后端的setDictCache
-func (d *DictUtils) SetDictCache(key string, dictDatas []model.SysDictData) {
+func (d *DictUtils) SetDictCache(ctx context.Context, key string, dictDatas []model.SysDictData) {
+	key = tenantKey(ctx, key)
 	fmt.Printf("DictUtils.SetDictCache: 设置字典缓存, Key=%s, 数量=%d\n", key, len(dictDatas))
 
 	// 设置本地缓存
@@ -47,7 +57,8 @@ func (d *DictUtils) SetDictCache(key string, dictDatas []model.SysDictData) {
 }
 
 // GetDictCache 获取字典缓存 对应Java后端的getDictCache
-func (d *DictUtils) GetDictCache(key string) []model.SysDictData {
+func (d *DictUtils) GetDictCache(ctx context.Context, key string) []model.SysDictData {
+	key = tenantKey(ctx, key)
 	// 先从本地缓存获取
 	if value, ok := d.cache.Load(key); ok {
 		if dictDatas, ok := value.([]model.SysDictData); ok {
@@ -76,16 +87,33 @@ func (d *DictUtils) GetDictCache(key string) []model.SysDictData {
 }
 
 // RemoveDictCache 删除字典缓存 对应Java后端的removeDictCache
-func (d *DictUtils) RemoveDictCache(key string) {
-	fmt.Printf("DictUtils.RemoveDictCache: 删除字典缓存, Key=%s\n", key)
+// 删除平台租户的字典缓存时同时删除各租户的缓存，未覆盖的租户缓存的是平台租户的字典数据
+func (d *DictUtils) RemoveDictCache(ctx context.Context, key string) {
+	cacheKey := tenantKey(ctx, key)
+	fmt.Printf("DictUtils.RemoveDictCache: 删除字典缓存, Key=%s\n", cacheKey)
 
 	// 删除本地缓存
-	d.cache.Delete(key)
+	d.cache.Delete(cacheKey)
+	platform := cacheKey == key
+	if platform {
+		suffix := ":" + key
+		d.cache.Range(func(k, value interface{}) bool {
+			if name := k.(string); strings.HasPrefix(name, "t") && strings.HasSuffix(name, suffix) {
+				d.cache.Delete(k)
+			}
+			return true
+		})
+	}
 
 	// 删除Redis缓存
 	if redis.GetRedis() != nil {
-		cacheKey := fmt.Sprintf("sys_dict:%s", key)
-		redis.Del(cacheKey)
+		redis.Del(fmt.Sprintf("sys_dict:%s", cacheKey))
+		if platform {
+			keys, err := redis.GetRedis().Keys(context.Background(), "sys_dict:t*:"+key).Result()
+			if err == nil && len(keys) > 0 {
+				redis.GetRedis().Del(context.Background(), keys...)
+			}
+		}
 	}
 }
 
@@ -111,8 +139,8 @@ func (d *DictUtils) ClearDictCache() {
 }
 
 // GetDictLabel 根据字典类型和字典值获取字典标签 对应Java后端的getDictLabel
-func (d *DictUtils) GetDictLabel(dictType, dictValue string) string {
-	dictDatas := d.GetDictCache(dictType)
+func (d *DictUtils) GetDictLabel(ctx context.Context, dictType, dictValue string) string {
+	dictDatas := d.GetDictCache(ctx, dictType)
 	if dictDatas == nil {
 		return ""
 	}
@@ -127,8 +155,8 @@ func (d *DictUtils) GetDictLabel(dictType, dictValue string) string {
 }
 
 // GetDictValue 根据字典类型和字典标签获取字典值 对应Java后端的getDictValue
-func (d *DictUtils) GetDictValue(dictType, dictLabel string) string {
-	dictDatas := d.GetDictCache(dictType)
+func (d *DictUtils) GetDictValue(ctx context.Context, dictType, dictLabel string) string {
+	dictDatas := d.GetDictCache(ctx, dictType)
 	if dictDatas == nil {
 		return ""
 	}
@@ -143,8 +171,8 @@ func (d *DictUtils) GetDictValue(dictType, dictLabel string) string {
 }
 
 // GetDictValues 根据字典类型获取字典所有值 对应Java后端的getDictValues
-func (d *DictUtils) GetDictValues(dictType string) string {
-	dictDatas := d.GetDictCache(dictType)
+func (d *DictUtils) GetDictValues(ctx context.Context, dictType string) string {
+	dictDatas := d.GetDictCache(ctx, dictType)
 	if dictDatas == nil {
 		return ""
 	}
@@ -158,8 +186,8 @@ func (d *DictUtils) GetDictValues(dictType string) string {
 }
 
 // GetDictLabels 根据字典类型获取字典所有标签 对应Java后端的getDictLabels
-func (d *DictUtils) GetDictLabels(dictType string) string {
-	dictDatas := d.GetDictCache(dictType)
+func (d *DictUtils) GetDictLabels(ctx context.Context, dictType string) string {
+	dictDatas := d.GetDictCache(ctx, dictType)
 	if dictDatas == nil {
 		return ""
 	}
@@ -175,18 +203,18 @@ func (d *DictUtils) GetDictLabels(dictType string) string {
 // 全局方法，方便调用
 
 // SetDictCache 设置字典缓存
-func SetDictCache(key string, dictDatas []model.SysDictData) {
-	GetInstance().SetDictCache(key, dictDatas)
+func SetDictCache(ctx context.Context, key string, dictDatas []model.SysDictData) {
+	GetInstance().SetDictCache(ctx, key, dictDatas)
 }
 
 // GetDictCache 获取字典缓存
-func GetDictCache(key string) []model.SysDictData {
-	return GetInstance().GetDictCache(key)
+func GetDictCache(ctx context.Context, key string) []model.SysDictData {
+	return GetInstance().GetDictCache(ctx, key)
 }
 
 // RemoveDictCache 删除字典缓存
-func RemoveDictCache(key string) {
-	GetInstance().RemoveDictCache(key)
+func RemoveDictCache(ctx context.Context, key string) {
+	GetInstance().RemoveDictCache(ctx, key)
 }
 
 // ClearDictCache 清空字典缓存
@@ -195,21 +223,21 @@ func ClearDictCache() {
 }
 
 // GetDictLabel 根据字典类型和字典值获取字典标签
-func GetDictLabel(dictType, dictValue string) string {
-	return GetInstance().GetDictLabel(dictType, dictValue)
+func GetDictLabel(ctx context.Context, dictType, dictValue string) string {
+	return GetInstance().GetDictLabel(ctx, dictType, dictValue)
 }
 
 // GetDictValue 根据字典类型和字典标签获取字典值
-func GetDictValue(dictType, dictLabel string) string {
-	return GetInstance().GetDictValue(dictType, dictLabel)
+func GetDictValue(ctx context.Context, dictType, dictLabel string) string {
+	return GetInstance().GetDictValue(ctx, dictType, dictLabel)
 }
 
 // GetDictValues 根据字典类型获取字典所有值 对应Java后端的getDictValues
-func GetDictValues(dictType string) string {
-	return GetInstance().GetDictValues(dictType)
+func GetDictValues(ctx context.Context, dictType string) string {
+	return GetInstance().GetDictValues(ctx, dictType)
 }
 
 // GetDictLabels 根据字典类型获取字典所有标签 对应Java后端的getDictLabels
-func GetDictLabels(dictType string) string {
-	return GetInstance().GetDictLabels(dictType)
+func GetDictLabels(ctx context.Context, dictType string) string {
+	return GetInstance().GetDictLabels(ctx, dictType)
 }
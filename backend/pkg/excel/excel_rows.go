@@ -0,0 +1,156 @@
+package excel
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ExportRows 导出没有对应结构体的数据（如运行时模块），headers为列名，rows中每行的值与列名一一对应
+func (e *ExcelUtil) ExportRows(headers []string, rows [][]interface{}, sheetName, title string) ([]byte, error) {
+	e.sheetName = sheetName
+	e.title = title
+	e.excelType = TypeExport
+	e.fields = make([]*ExcelField, len(headers))
+	for i, header := range headers {
+		e.fields[i] = &ExcelField{Name: header, IsExport: true, Type: TypeExport}
+	}
+
+	e.createWorkbook()
+	e.createTitle()
+	if err := e.createHead(); err != nil {
+		return nil, fmt.Errorf("创建表头失败: %v", err)
+	}
+
+	if err := e.fillRows(rows); err != nil {
+		return nil, err
+	}
+
+	e.setColumnWidth()
+
+	buffer, err := e.file.WriteToBuffer()
+	if err != nil {
+		return nil, fmt.Errorf("生成Excel文件失败: %v", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+// ImportTemplate 生成没有对应结构体的导入模板：表头上方可以有说明行（如表名），rows为示例数据，
+// 字段的 Combo 生成下拉选择，Prompt 生成输入提示
+func (e *ExcelUtil) ImportTemplate(fields []*ExcelField, preface [][]string, rows [][]interface{}, sheetName string) ([]byte, error) {
+	e.sheetName = sheetName
+	e.excelType = TypeImport
+	e.fields = fields
+	e.createWorkbook()
+
+	labelStyle, err := e.file.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return nil, fmt.Errorf("创建说明行失败: %v", err)
+	}
+	for _, line := range preface {
+		for j, value := range line {
+			cell := fmt.Sprintf("%s%d", getColumnName(j), e.rownum+1)
+			e.file.SetCellValue(e.sheetName, cell, value)
+			if j == 0 {
+				e.file.SetCellStyle(e.sheetName, cell, cell, labelStyle)
+			}
+		}
+		e.rownum++
+	}
+	if len(preface) > 0 {
+		// 说明行与表头之间空一行
+		e.rownum++
+	}
+
+	if err := e.createHead(); err != nil {
+		return nil, fmt.Errorf("创建表头失败: %v", err)
+	}
+	firstRow := e.rownum + 1
+	if err := e.fillRows(rows); err != nil {
+		return nil, err
+	}
+
+	for i, field := range fields {
+		if len(field.Combo) == 0 && field.Prompt == "" {
+			continue
+		}
+		column := getColumnName(i)
+		validation := excelize.NewDataValidation(true)
+		validation.Sqref = fmt.Sprintf("%s%d:%s%d", column, firstRow, column, firstRow+templateRows-1)
+		if len(field.Combo) > 0 {
+			if err := validation.SetDropList(field.Combo); err != nil {
+				return nil, fmt.Errorf("设置下拉选择失败: %v", err)
+			}
+		}
+		if field.Prompt != "" {
+			validation.SetInput(field.Name, field.Prompt)
+		}
+		if err := e.file.AddDataValidation(e.sheetName, validation); err != nil {
+			return nil, fmt.Errorf("设置下拉选择失败: %v", err)
+		}
+	}
+
+	e.setColumnWidth()
+
+	buffer, err := e.file.WriteToBuffer()
+	if err != nil {
+		return nil, fmt.Errorf("生成Excel文件失败: %v", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+// templateRows 导入模板中设置下拉选择的行数
+const templateRows = 200
+
+// ReadRows 读取第一个工作表的全部单元格文本，行尾的空单元格会被省略
+func (e *ExcelUtil) ReadRows(data []byte) ([][]string, error) {
+	file, err := excelize.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("读取Excel文件失败: %v", err)
+	}
+	defer file.Close()
+
+	sheets := file.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, fmt.Errorf("Excel文件中没有工作表")
+	}
+	rows, err := file.GetRows(sheets[0])
+	if err != nil {
+		return nil, fmt.Errorf("读取工作表失败: %v", err)
+	}
+	return rows, nil
+}
+
+// fillRows 从当前行开始填充数据，日期按 yyyy-MM-dd HH:mm:ss 格式输出
+func (e *ExcelUtil) fillRows(rows [][]interface{}) error {
+	dataStyle, err := e.file.NewStyle(&excelize.Style{
+		Alignment: &excelize.Alignment{
+			Horizontal: "center",
+			Vertical:   "center",
+		},
+		Border: []excelize.Border{
+			{Type: "left", Color: "000000", Style: 1},
+			{Type: "top", Color: "000000", Style: 1},
+			{Type: "bottom", Color: "000000", Style: 1},
+			{Type: "right", Color: "000000", Style: 1},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("填充数据失败: %v", err)
+	}
+	for _, row := range rows {
+		dataRow := e.rownum
+		e.rownum++
+		for j, value := range row {
+			if date, ok := value.(time.Time); ok {
+				value = date.Format("2006-01-02 15:04:05")
+			}
+			cell := fmt.Sprintf("%s%d", getColumnName(j), dataRow+1)
+			e.file.SetCellValue(e.sheetName, cell, value)
+			e.file.SetCellStyle(e.sheetName, cell, cell, dataStyle)
+		}
+	}
+	return nil
+}
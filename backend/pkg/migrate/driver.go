@@ -0,0 +1,212 @@
+package migrate
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// lockName 迁移锁名称
+const lockName = "wosm_schema_migrations"
+
+// postgresLockKey PostgreSQL咨询锁的键
+const postgresLockKey int64 = 0x776f736d6d6967 // "wosmmig"
+
+// lockRetryInterval 非阻塞获取锁失败后的重试间隔
+const lockRetryInterval = 500 * time.Millisecond
+
+// driver 迁移在各数据库上的差异
+type driver interface {
+	// name 数据库驱动名称，与 database.driver 配置一致
+	name() string
+	// createTable 不存在时创建迁移记录表
+	createTable() string
+	// lock 在当前连接上获取迁移锁，timeout内获取不到返回错误
+	lock(conn *gorm.DB, timeout time.Duration) error
+	// unlock 释放迁移锁，success表示迁移是否成功
+	unlock(conn *gorm.DB, success bool) error
+	// transactional 每个版本是否在单独的事务中执行
+	transactional() bool
+}
+
+var drivers = map[string]driver{
+	"sqlserver": sqlServerDriver{},
+	"mysql":     mysqlDriver{},
+	"postgres":  postgresDriver{},
+	"sqlite":    sqliteDriver{},
+}
+
+// errLockTimeout 获取迁移锁超时
+func errLockTimeout(timeout time.Duration) error {
+	return fmt.Errorf("等待迁移锁超时（%v），其他实例可能正在执行迁移", timeout)
+}
+
+// sqlServerDriver SQL Server：会话级应用锁，DDL支持事务
+type sqlServerDriver struct{}
+
+func (sqlServerDriver) name() string {
+	return "sqlserver"
+}
+
+func (sqlServerDriver) createTable() string {
+	return `IF OBJECT_ID(N'[dbo].[schema_migrations]', N'U') IS NULL
+CREATE TABLE [dbo].[schema_migrations] (
+  [version]      BIGINT        NOT NULL PRIMARY KEY,
+  [name]         NVARCHAR(200) NOT NULL,
+  [checksum]     VARCHAR(64)   NOT NULL,
+  [applied_at]   DATETIME      NOT NULL,
+  [execution_ms] BIGINT        NOT NULL
+)`
+}
+
+func (sqlServerDriver) lock(conn *gorm.DB, timeout time.Duration) error {
+	// sp_getapplock 返回值大于等于0表示获取成功
+	var result int
+	err := conn.Raw("DECLARE @result INT; EXEC @result = sp_getapplock @Resource = ?, @LockMode = 'Exclusive', @LockOwner = 'Session', @LockTimeout = ?; SELECT @result",
+		lockName, timeout.Milliseconds()).Row().Scan(&result)
+	if err != nil {
+		return fmt.Errorf("获取迁移锁失败: %v", err)
+	}
+	if result < 0 {
+		return errLockTimeout(timeout)
+	}
+	return nil
+}
+
+func (sqlServerDriver) unlock(conn *gorm.DB, success bool) error {
+	return conn.Exec("EXEC sp_releaseapplock @Resource = ?, @LockOwner = 'Session'", lockName).Error
+}
+
+func (sqlServerDriver) transactional() bool {
+	return true
+}
+
+// mysqlDriver MySQL：命名锁；DDL会隐式提交，不使用事务，执行失败需要按错误信息手工处理后重新执行
+type mysqlDriver struct{}
+
+func (mysqlDriver) name() string {
+	return "mysql"
+}
+
+func (mysqlDriver) createTable() string {
+	return `create table if not exists schema_migrations (
+  version      bigint(20)    not null comment '版本号',
+  name         varchar(200)  not null comment '名称',
+  checksum     varchar(64)   not null comment '脚本校验和',
+  applied_at   datetime      not null comment '执行时间',
+  execution_ms bigint(20)    not null comment '执行耗时（毫秒）',
+  primary key (version)
+) engine=innodb comment = '数据库迁移记录表'`
+}
+
+func (mysqlDriver) lock(conn *gorm.DB, timeout time.Duration) error {
+	// GET_LOCK 返回1表示获取成功，0表示超时
+	var result sql.NullInt64
+	if err := conn.Raw("SELECT GET_LOCK(?, ?)", lockName, int64(timeout.Seconds())).Row().Scan(&result); err != nil {
+		return fmt.Errorf("获取迁移锁失败: %v", err)
+	}
+	if result.Int64 != 1 {
+		return errLockTimeout(timeout)
+	}
+	return nil
+}
+
+func (mysqlDriver) unlock(conn *gorm.DB, success bool) error {
+	return conn.Exec("SELECT RELEASE_LOCK(?)", lockName).Error
+}
+
+func (mysqlDriver) transactional() bool {
+	return false
+}
+
+// postgresDriver PostgreSQL：会话级咨询锁，DDL支持事务
+type postgresDriver struct{}
+
+func (postgresDriver) name() string {
+	return "postgres"
+}
+
+func (postgresDriver) createTable() string {
+	return `create table if not exists schema_migrations (
+  version      bigint        not null primary key,
+  name         varchar(200)  not null,
+  checksum     varchar(64)   not null,
+  applied_at   timestamp     not null,
+  execution_ms bigint        not null
+)`
+}
+
+func (postgresDriver) lock(conn *gorm.DB, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		var locked bool
+		if err := conn.Raw("SELECT pg_try_advisory_lock(?)", postgresLockKey).Row().Scan(&locked); err != nil {
+			return fmt.Errorf("获取迁移锁失败: %v", err)
+		}
+		if locked {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errLockTimeout(timeout)
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
+
+func (postgresDriver) unlock(conn *gorm.DB, success bool) error {
+	return conn.Exec("SELECT pg_advisory_unlock(?)", postgresLockKey).Error
+}
+
+func (postgresDriver) transactional() bool {
+	return true
+}
+
+// sqliteDriver SQLite：没有命名锁，用 BEGIN IMMEDIATE 占用写锁作为迁移锁，
+// 本次执行的所有版本在这一个事务中，失败时全部回滚
+type sqliteDriver struct{}
+
+func (sqliteDriver) name() string {
+	return "sqlite"
+}
+
+func (sqliteDriver) createTable() string {
+	return `create table if not exists schema_migrations (
+  version      integer       not null primary key,
+  name         varchar(200)  not null,
+  checksum     varchar(64)   not null,
+  applied_at   datetime      not null,
+  execution_ms integer       not null
+)`
+}
+
+func (sqliteDriver) lock(conn *gorm.DB, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		err := conn.Exec("BEGIN IMMEDIATE").Error
+		if err == nil {
+			return nil
+		}
+		message := strings.ToLower(err.Error())
+		if !strings.Contains(message, "locked") && !strings.Contains(message, "busy") {
+			return fmt.Errorf("获取迁移锁失败: %v", err)
+		}
+		if time.Now().After(deadline) {
+			return errLockTimeout(timeout)
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
+
+func (sqliteDriver) unlock(conn *gorm.DB, success bool) error {
+	if success {
+		return conn.Exec("COMMIT").Error
+	}
+	return conn.Exec("ROLLBACK").Error
+}
+
+func (sqliteDriver) transactional() bool {
+	return false
+}
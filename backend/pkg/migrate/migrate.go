@@ -0,0 +1,404 @@
+// Package migrate 版本化的数据库迁移：各数据库的迁移脚本嵌入程序，
+// 按版本号顺序执行并记录在 schema_migrations 表中，执行前校验已执行脚本的校验和，
+// 并通过数据库锁保证多个实例同时启动时只有一个执行迁移
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"wosm/pkg/ddl"
+
+	"gorm.io/gorm"
+)
+
+// 迁移脚本放在 migrations/<数据库>/ 下，文件名为 版本号_名称.up.sql 和 版本号_名称.down.sql，
+// down脚本可以没有（不支持回滚）；脚本发布后不能再修改，表结构变更新增一个版本
+//
+//go:embed migrations
+var migrationFS embed.FS
+
+// TableName 迁移记录表
+const TableName = "schema_migrations"
+
+// DefaultLockTimeout 等待其他实例迁移完成的默认时间
+const DefaultLockTimeout = 5 * time.Minute
+
+// 迁移状态
+const (
+	StateApplied  = "已执行"
+	StatePending  = "未执行"
+	StateModified = "已修改"  // 执行后脚本被修改，校验和不一致
+	StateMissing  = "脚本缺失" // 数据库中有记录但程序中没有脚本（由更新版本的程序执行）
+)
+
+var fileNamePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration 一个版本的迁移脚本
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string // 为空表示不支持回滚
+	Checksum string // Up脚本的SHA-256
+}
+
+// String 版本号和名称，与脚本文件名一致
+func (m *Migration) String() string {
+	return fmt.Sprintf("%04d_%s", m.Version, m.Name)
+}
+
+// Record 迁移记录
+type Record struct {
+	Version     int64     `gorm:"column:version"`
+	Name        string    `gorm:"column:name"`
+	Checksum    string    `gorm:"column:checksum"`
+	AppliedAt   time.Time `gorm:"column:applied_at"`
+	ExecutionMs int64     `gorm:"column:execution_ms"`
+}
+
+// Status 一个版本的迁移状态
+type Status struct {
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	State     string     `json:"state"`
+	AppliedAt *time.Time `json:"appliedAt"`
+}
+
+// Load 读取数据库驱动对应的迁移脚本，按版本号排序
+func Load(driver string) ([]Migration, error) {
+	return load(migrationFS, path.Join("migrations", driver))
+}
+
+// load 读取目录中的迁移脚本
+func load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("没有数据库迁移脚本: %s", dir)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	downs := make(map[int64]string)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		matches := fileNamePattern.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			return nil, fmt.Errorf("迁移脚本文件名不正确: %s，应为 版本号_名称.up.sql 或 版本号_名称.down.sql", entry.Name())
+		}
+		version, _ := strconv.ParseInt(matches[1], 10, 64)
+		if version <= 0 {
+			return nil, fmt.Errorf("迁移脚本版本号必须大于0: %s", entry.Name())
+		}
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		script := strings.ReplaceAll(string(content), "\r\n", "\n")
+
+		if matches[3] == "down" {
+			downs[version] = script
+			continue
+		}
+		if existing, ok := byVersion[version]; ok {
+			return nil, fmt.Errorf("迁移脚本版本号重复: %s 和 %s", existing.String(), entry.Name())
+		}
+		sum := sha256.Sum256([]byte(script))
+		byVersion[version] = &Migration{Version: version, Name: matches[2], Up: script, Checksum: hex.EncodeToString(sum[:])}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for version, down := range downs {
+		if _, ok := byVersion[version]; !ok {
+			return nil, fmt.Errorf("迁移版本 %d 只有down脚本", version)
+		}
+		byVersion[version].Down = down
+	}
+	for _, migration := range byVersion {
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Migrator 执行迁移
+type Migrator struct {
+	db          *gorm.DB
+	driver      driver
+	migrations  []Migration
+	LockTimeout time.Duration // 等待迁移锁的时间
+}
+
+// New 创建当前数据库连接的迁移器
+func New(db *gorm.DB) (*Migrator, error) {
+	name := db.Dialector.Name()
+	driver, ok := drivers[name]
+	if !ok {
+		return nil, fmt.Errorf("数据库迁移不支持数据库类型: %s", name)
+	}
+	migrations, err := Load(name)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, driver: driver, migrations: migrations, LockTimeout: DefaultLockTimeout}, nil
+}
+
+// Migrations 程序中的迁移脚本
+func (m *Migrator) Migrations() []Migration {
+	return m.migrations
+}
+
+// Up 按版本顺序执行未执行的迁移，target大于0时只执行到该版本；返回本次执行的迁移
+func (m *Migrator) Up(ctx context.Context, target int64) ([]Migration, error) {
+	var applied []Migration
+	err := m.withLock(ctx, func(conn *gorm.DB) error {
+		records, err := m.records(conn)
+		if err != nil {
+			return err
+		}
+		if err := m.verify(records); err != nil {
+			return err
+		}
+		// 用旧的全量脚本建的库直接执行初始迁移会重复建表
+		if len(records) == 0 && conn.Migrator().HasTable("sys_user") {
+			return fmt.Errorf("数据库中已有业务表但没有迁移记录，请先执行 migrate baseline 标记已有表结构对应的版本")
+		}
+
+		done := make(map[int64]bool, len(records))
+		var latest int64
+		for _, record := range records {
+			done[record.Version] = true
+			if record.Version > latest {
+				latest = record.Version
+			}
+		}
+		for i := range m.migrations {
+			migration := &m.migrations[i]
+			if done[migration.Version] || (target > 0 && migration.Version > target) {
+				continue
+			}
+			if migration.Version < latest {
+				return fmt.Errorf("迁移 %s 的版本低于已执行的版本 %d，不能补执行，请改为新的版本号", migration.String(), latest)
+			}
+			if err := m.apply(conn, migration, true); err != nil {
+				return err
+			}
+			applied = append(applied, *migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down 按执行顺序倒序回滚最近执行的steps个迁移；返回本次回滚的迁移
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	if steps <= 0 {
+		return nil, fmt.Errorf("回滚步数必须大于0")
+	}
+	var reverted []Migration
+	err := m.withLock(ctx, func(conn *gorm.DB) error {
+		records, err := m.records(conn)
+		if err != nil {
+			return err
+		}
+		if err := m.verify(records); err != nil {
+			return err
+		}
+		for i := len(records) - 1; i >= 0 && len(reverted) < steps; i-- {
+			migration := m.find(records[i].Version)
+			if migration == nil {
+				return fmt.Errorf("迁移版本 %d 没有脚本，不能回滚", records[i].Version)
+			}
+			if strings.TrimSpace(migration.Down) == "" {
+				return fmt.Errorf("迁移 %s 没有down脚本，不能回滚", migration.String())
+			}
+			if err := m.apply(conn, migration, false); err != nil {
+				return err
+			}
+			reverted = append(reverted, *migration)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Baseline 将已有数据库标记为已迁移到version（不执行脚本），用于迁移功能上线前用旧的全量建表脚本建的库（相当于版本1）
+func (m *Migrator) Baseline(ctx context.Context, version int64) ([]Migration, error) {
+	if m.find(version) == nil {
+		return nil, fmt.Errorf("迁移版本 %d 不存在", version)
+	}
+	var marked []Migration
+	err := m.withLock(ctx, func(conn *gorm.DB) error {
+		records, err := m.records(conn)
+		if err != nil {
+			return err
+		}
+		if len(records) > 0 {
+			return fmt.Errorf("数据库已有迁移记录，不能再设置基线")
+		}
+		now := time.Now()
+		for _, migration := range m.migrations {
+			if migration.Version > version {
+				break
+			}
+			if err := m.insertRecord(conn, &migration, now, 0); err != nil {
+				return err
+			}
+			marked = append(marked, migration)
+		}
+		return nil
+	})
+	return marked, err
+}
+
+// Status 各版本的迁移状态，按版本号排序
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	db := m.db.WithContext(ctx)
+	var records []Record
+	if db.Migrator().HasTable(TableName) {
+		var err error
+		if records, err = m.records(db); err != nil {
+			return nil, err
+		}
+	}
+
+	byVersion := make(map[int64]*Record, len(records))
+	for i := range records {
+		byVersion[records[i].Version] = &records[i]
+	}
+	statuses := make([]Status, 0, len(m.migrations)+len(records))
+	for _, migration := range m.migrations {
+		status := Status{Version: migration.Version, Name: migration.Name, State: StatePending}
+		if record, ok := byVersion[migration.Version]; ok {
+			status.State = StateApplied
+			if record.Checksum != migration.Checksum {
+				status.State = StateModified
+			}
+			appliedAt := record.AppliedAt
+			status.AppliedAt = &appliedAt
+			delete(byVersion, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+	for _, record := range byVersion {
+		appliedAt := record.AppliedAt
+		statuses = append(statuses, Status{Version: record.Version, Name: record.Name, State: StateMissing, AppliedAt: &appliedAt})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
+
+// withLock 在独占的连接上获取迁移锁并创建迁移记录表后执行fn
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *gorm.DB) error) error {
+	return m.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		if err := m.driver.lock(conn, m.LockTimeout); err != nil {
+			return err
+		}
+		err := conn.Exec(m.driver.createTable()).Error
+		if err != nil {
+			err = fmt.Errorf("创建迁移记录表失败: %v", err)
+		} else {
+			err = fn(conn)
+		}
+		if unlockErr := m.driver.unlock(conn, err == nil); err == nil && unlockErr != nil {
+			err = fmt.Errorf("释放迁移锁失败: %v", unlockErr)
+		}
+		return err
+	})
+}
+
+// records 已执行的迁移，按版本号排序
+func (m *Migrator) records(db *gorm.DB) ([]Record, error) {
+	var records []Record
+	if err := db.Table(TableName).Order("version").Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("读取迁移记录失败: %v", err)
+	}
+	return records, nil
+}
+
+// verify 校验已执行的迁移脚本没有被修改
+func (m *Migrator) verify(records []Record) error {
+	for _, record := range records {
+		migration := m.find(record.Version)
+		if migration != nil && migration.Checksum != record.Checksum {
+			return fmt.Errorf("迁移 %s 执行后脚本被修改（校验和不一致），已发布的迁移脚本不能修改，表结构变更请新增版本", migration.String())
+		}
+	}
+	return nil
+}
+
+// find 按版本号查找迁移脚本
+func (m *Migrator) find(version int64) *Migration {
+	for i := range m.migrations {
+		if m.migrations[i].Version == version {
+			return &m.migrations[i]
+		}
+	}
+	return nil
+}
+
+// apply 执行迁移脚本并更新迁移记录；支持事务性DDL的数据库一个版本在一个事务中执行
+func (m *Migrator) apply(conn *gorm.DB, migration *Migration, up bool) error {
+	script, direction := migration.Up, "up"
+	if !up {
+		script, direction = migration.Down, "down"
+	}
+	statements, err := ddl.SplitScript(m.driver.name(), script)
+	if err != nil {
+		return fmt.Errorf("解析迁移脚本 %s.%s.sql 失败: %v", migration.String(), direction, err)
+	}
+
+	run := func(tx *gorm.DB) error {
+		start := time.Now()
+		// 脚本直接在连接上执行，不经过GORM的参数替换（脚本中的?是普通字符）
+		pool := tx.Statement.ConnPool
+		for i, statement := range statements {
+			if _, err := pool.ExecContext(tx.Statement.Context, statement); err != nil {
+				return fmt.Errorf("迁移 %s.%s.sql 第%d条语句执行失败: %v\n%s", migration.String(), direction, i+1, err, abbreviate(statement))
+			}
+		}
+		if up {
+			return m.insertRecord(tx, migration, time.Now(), time.Since(start).Milliseconds())
+		}
+		if err := tx.Exec("DELETE FROM "+TableName+" WHERE version = ?", migration.Version).Error; err != nil {
+			return fmt.Errorf("删除迁移记录失败: %v", err)
+		}
+		return nil
+	}
+
+	log.Printf("执行数据库迁移: %s.%s.sql", migration.String(), direction)
+	if m.driver.transactional() {
+		return conn.Transaction(run)
+	}
+	return run(conn)
+}
+
+// insertRecord 写入迁移记录
+func (m *Migrator) insertRecord(db *gorm.DB, migration *Migration, appliedAt time.Time, executionMs int64) error {
+	err := db.Exec("INSERT INTO "+TableName+" (version, name, checksum, applied_at, execution_ms) VALUES (?, ?, ?, ?, ?)",
+		migration.Version, migration.Name, migration.Checksum, appliedAt, executionMs).Error
+	if err != nil {
+		return fmt.Errorf("写入迁移记录失败: %v", err)
+	}
+	return nil
+}
+
+// abbreviate 截取出错语句的开头用于错误提示
+func abbreviate(statement string) string {
+	runes := []rune(statement)
+	if len(runes) > 200 {
+		return string(runes[:200]) + "..."
+	}
+	return statement
+}
@@ -0,0 +1,192 @@
+package migrate
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+	"wosm/pkg/sqlitetest"
+
+	"gorm.io/gorm"
+)
+
+func TestLoad(t *testing.T) {
+	for driver := range drivers {
+		migrations, err := Load(driver)
+		if err != nil {
+			t.Fatalf("读取%s迁移脚本失败: %v", driver, err)
+		}
+		if len(migrations) == 0 || migrations[0].Version != 1 || migrations[0].Down == "" {
+			t.Errorf("%s 初始迁移不正确", driver)
+		}
+	}
+
+	migrations, err := load(fstest.MapFS{
+		"m/0002_add_index.up.sql": {Data: []byte("create index i on t (a);\r\n")},
+		"m/0001_init.up.sql":      {Data: []byte("create table t (a int);\n")},
+		"m/0001_init.down.sql":    {Data: []byte("drop table t;\n")},
+	}, "m")
+	if err != nil {
+		t.Fatalf("读取迁移脚本失败: %v", err)
+	}
+	if len(migrations) != 2 || migrations[0].String() != "0001_init" || migrations[1].Down != "" || migrations[1].Up != "create index i on t (a);\n" {
+		t.Errorf("迁移脚本 = %+v", migrations)
+	}
+
+	for name, fsys := range map[string]fstest.MapFS{
+		"文件名不正确": {"m/init.sql": {}},
+		"版本号重复":  {"m/1_a.up.sql": {}, "m/01_b.up.sql": {}},
+		"只有down": {"m/0001_a.down.sql": {}},
+	} {
+		if _, err := load(fsys, "m"); err == nil {
+			t.Errorf("%s 应返回错误", name)
+		}
+	}
+}
+
+// TestMigrator 在SQLite上执行初始迁移、回滚和基线
+func TestMigrator(t *testing.T) {
+	ctx := context.Background()
+	db := sqlitetest.Open(t, "")
+	migrator, err := New(db)
+	if err != nil {
+		t.Fatalf("创建迁移器失败: %v", err)
+	}
+	// 在内置迁移之后追加一个测试迁移
+	embedded := len(migrator.migrations)
+	next := migrator.migrations[embedded-1].Version + 1
+	migrator.migrations = append(migrator.migrations, Migration{
+		Version: next, Name: "add_user_ext", Checksum: "c2",
+		Up:   "create table sys_user_ext (user_id bigint not null primary key, remark varchar(500) default '?');",
+		Down: "drop table sys_user_ext;",
+	})
+
+	statuses, err := migrator.Status(ctx)
+	if err != nil || len(statuses) != embedded+1 || statuses[0].State != StatePending {
+		t.Fatalf("迁移前状态 = %+v, %v", statuses, err)
+	}
+
+	// 只执行到版本1
+	applied, err := migrator.Up(ctx, 1)
+	if err != nil || len(applied) != 1 {
+		t.Fatalf("执行迁移 = %v, %v", applied, err)
+	}
+	var userName string
+	db.Raw("SELECT user_name FROM sys_user WHERE user_id = 1").Scan(&userName)
+	if userName != "admin" {
+		t.Errorf("初始数据 user_name = %q", userName)
+	}
+	if applied, err = migrator.Up(ctx, 0); err != nil || len(applied) != embedded || applied[len(applied)-1].Version != next {
+		t.Fatalf("执行剩余迁移 = %v, %v", applied, err)
+	}
+	if applied, err = migrator.Up(ctx, 0); err != nil || len(applied) != 0 {
+		t.Errorf("重复执行迁移 = %v, %v", applied, err)
+	}
+
+	statuses, _ = migrator.Status(ctx)
+	for _, status := range statuses {
+		if status.State != StateApplied || status.AppliedAt == nil {
+			t.Errorf("迁移后状态 = %+v", status)
+		}
+	}
+
+	// 已执行的脚本被修改
+	migrator.migrations[embedded].Checksum = "changed"
+	if _, err := migrator.Up(ctx, 0); err == nil || !strings.Contains(err.Error(), "校验和") {
+		t.Errorf("脚本被修改应返回错误: %v", err)
+	}
+	statuses, _ = migrator.Status(ctx)
+	if statuses[embedded].State != StateModified {
+		t.Errorf("修改后状态 = %s", statuses[embedded].State)
+	}
+	migrator.migrations[embedded].Checksum = "c2"
+
+	// 执行失败时整体回滚
+	broken := Migration{Version: next + 1, Name: "broken", Checksum: "c3", Up: "create table t3 (a int); insert into not_exists values (1);"}
+	migrator.migrations = append(migrator.migrations, broken)
+	if _, err := migrator.Up(ctx, 0); err == nil || !strings.Contains(err.Error(), broken.String()+".up.sql 第2条语句") {
+		t.Errorf("执行失败应返回出错的语句: %v", err)
+	}
+	if db.Migrator().HasTable("t3") {
+		t.Error("执行失败的迁移应回滚")
+	}
+	migrator.migrations = migrator.migrations[:embedded+1]
+
+	reverted, err := migrator.Down(ctx, embedded+1)
+	if err != nil || len(reverted) != embedded+1 || reverted[0].Version != next {
+		t.Fatalf("回滚迁移 = %v, %v", reverted, err)
+	}
+	if db.Migrator().HasTable("sys_user") || db.Migrator().HasTable("sys_user_ext") {
+		t.Error("回滚后业务表应已删除")
+	}
+	if _, err := migrator.Down(ctx, 1); err != nil {
+		t.Errorf("没有可回滚的迁移时不应返回错误: %v", err)
+	}
+}
+
+// TestMigratorBaseline 迁移功能上线前建的库
+func TestMigratorBaseline(t *testing.T) {
+	ctx := context.Background()
+	db := sqlitetest.Open(t, "")
+	db.Exec("create table sys_user (user_id integer primary key)")
+	migrator, err := New(db)
+	if err != nil {
+		t.Fatalf("创建迁移器失败: %v", err)
+	}
+
+	if _, err := migrator.Up(ctx, 0); err == nil || !strings.Contains(err.Error(), "baseline") {
+		t.Errorf("已有业务表时应提示设置基线: %v", err)
+	}
+	if _, err := migrator.Baseline(ctx, 99); err == nil {
+		t.Error("不存在的版本应返回错误")
+	}
+	marked, err := migrator.Baseline(ctx, 1)
+	if err != nil || len(marked) != 1 {
+		t.Fatalf("设置基线 = %v, %v", marked, err)
+	}
+	if _, err := migrator.Baseline(ctx, 1); err == nil {
+		t.Error("重复设置基线应返回错误")
+	}
+	if applied, err := migrator.Up(ctx, 1); err != nil || len(applied) != 0 {
+		t.Errorf("设置基线后执行迁移 = %v, %v", applied, err)
+	}
+
+	// 程序中没有的版本（由更新版本的程序执行）
+	db.Exec("INSERT INTO schema_migrations (version, name, checksum, applied_at, execution_ms) VALUES (9999, 'future', '', ?, 0)", time.Now())
+	statuses, _ := migrator.Status(ctx)
+	if last := statuses[len(statuses)-1]; last.Version != 9999 || last.State != StateMissing {
+		t.Errorf("脚本缺失的版本状态 = %+v", last)
+	}
+}
+
+// TestMigratorLock 其他实例持有迁移锁时等待超时
+func TestMigratorLock(t *testing.T) {
+	// 缩短SQLite等待写锁的时间，由迁移锁的重试控制超时
+	file := filepath.Join(t.TempDir(), "migrate.db") + "?_pragma=busy_timeout(100)"
+	holder := sqlitetest.Open(t, file)
+	migrator, err := New(sqlitetest.Open(t, file))
+	if err != nil {
+		t.Fatalf("创建迁移器失败: %v", err)
+	}
+	migrator.LockTimeout = time.Second
+
+	released := make(chan struct{})
+	locked := make(chan struct{})
+	go holder.Connection(func(conn *gorm.DB) error {
+		sqliteDriver{}.lock(conn, time.Second)
+		close(locked)
+		<-released
+		return sqliteDriver{}.unlock(conn, true)
+	})
+	<-locked
+
+	if _, err := migrator.Up(context.Background(), 0); err == nil || !strings.Contains(err.Error(), "迁移锁超时") {
+		t.Errorf("持有迁移锁时应超时: %v", err)
+	}
+	close(released)
+	if applied, err := migrator.Up(context.Background(), 0); err != nil || len(applied) == 0 {
+		t.Errorf("释放迁移锁后执行迁移 = %v, %v", applied, err)
+	}
+}
@@ -0,0 +1,6 @@
+-- 删除回收站
+
+delete from sys_job where invoke_target = 'recycleTask.purgeExpired';
+delete from sys_role_menu where menu_id in (select menu_id from sys_menu where perms in ('system:recycle:list', 'system:recycle:restore', 'system:recycle:remove'));
+delete from sys_menu where perms in ('system:recycle:list', 'system:recycle:restore', 'system:recycle:remove');
+drop table if exists sys_recycle;
@@ -0,0 +1,47 @@
+-- 回收站：记录删除的用户、部门和角色，支持恢复和彻底删除
+
+-- ----------------------------
+-- 1、回收站表
+-- ----------------------------
+
+create table sys_recycle (
+  recycle_id   bigint(20)      not null auto_increment comment '回收站ID',
+  object_type  varchar(20)     not null                comment '对象类型（user用户 dept部门 role角色）',
+  object_id    bigint(20)      not null                comment '对象ID',
+  object_name  varchar(100)    default ''              comment '对象名称',
+  snapshot     text                                    comment '删除时的关联数据（JSON）',
+  delete_by    varchar(64)     default ''              comment '删除者',
+  delete_time  datetime        default null            comment '删除时间',
+  primary key (recycle_id)
+) engine=innodb comment = '回收站表';
+
+create index idx_sys_recycle_type on sys_recycle (object_type, object_id);
+create index idx_sys_recycle_time on sys_recycle (delete_time);
+
+-- 迁移前已删除的数据（删除者和删除时间取最后更新信息，没有关联数据）
+insert into sys_recycle (object_type, object_id, object_name, delete_by, delete_time)
+select 'user', user_id, user_name, coalesce(update_by, ''), coalesce(update_time, create_time) from sys_user where del_flag = '2';
+insert into sys_recycle (object_type, object_id, object_name, delete_by, delete_time)
+select 'dept', dept_id, dept_name, coalesce(update_by, ''), coalesce(update_time, create_time) from sys_dept where del_flag = '2';
+insert into sys_recycle (object_type, object_id, object_name, delete_by, delete_time)
+select 'role', role_id, role_name, coalesce(update_by, ''), coalesce(update_time, create_time) from sys_role where del_flag = '2';
+
+-- ----------------------------
+-- 2、回收站菜单（不指定ID，由数据库生成；已存在相同权限标识的菜单时跳过）
+-- ----------------------------
+insert into sys_menu (menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark)
+select '回收站', 1, 10, 'recycle', 'system/recycle/index', '', '', 1, 0, 'C', '0', '0', 'system:recycle:list', 'clipboard', 'admin', sysdate(), '', NULL, '回收站菜单' from dual
+where not exists (select 1 from sys_menu where perms = 'system:recycle:list');
+insert into sys_menu (menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark)
+select '回收站恢复', m.menu_id, 1, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:recycle:restore', '#', 'admin', sysdate(), '', NULL, '' from sys_menu m
+where m.perms = 'system:recycle:list' and not exists (select 1 from sys_menu where perms = 'system:recycle:restore');
+insert into sys_menu (menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark)
+select '回收站删除', m.menu_id, 2, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:recycle:remove', '#', 'admin', sysdate(), '', NULL, '' from sys_menu m
+where m.perms = 'system:recycle:list' and not exists (select 1 from sys_menu where perms = 'system:recycle:remove');
+
+-- ----------------------------
+-- 3、回收站清理任务（保留天数见配置 recycle.retention_days）
+-- ----------------------------
+insert into sys_job (job_name, job_group, invoke_target, cron_expression, misfire_policy, concurrent, status, create_by, create_time, update_by, update_time, remark)
+select '回收站清理', 'SYSTEM', 'recycleTask.purgeExpired', '0 0 2 * * ?', '3', '1', '0', 'admin', sysdate(), '', NULL, '彻底删除超过保留天数的回收站数据' from dual
+where not exists (select 1 from sys_job where invoke_target = 'recycleTask.purgeExpired');
@@ -0,0 +1,32 @@
+-- 数据变更历史：记录用户、角色、部门、菜单、参数和字典数据修改前后的字段值
+
+-- ----------------------------
+-- 1、数据变更记录表
+-- ----------------------------
+
+create table sys_data_change (
+  change_id    bigint(20)      not null auto_increment comment '变更ID',
+  entity       varchar(50)     not null                comment '实体类型（user role dept menu config dict_data）',
+  entity_id    bigint(20)      not null                comment '实体ID',
+  field_name   varchar(100)    not null                comment '字段名',
+  old_value    text                                    comment '修改前的值',
+  new_value    text                                    comment '修改后的值',
+  oper_id      bigint(20)      default 0               comment '操作日志ID',
+  request_id   varchar(64)     default ''              comment '请求ID',
+  oper_name    varchar(64)     default ''              comment '操作人员',
+  change_time  datetime        default null            comment '变更时间',
+  primary key (change_id)
+) engine=innodb comment = '数据变更记录表';
+
+create index idx_sys_data_change_entity on sys_data_change (entity, entity_id, change_time);
+create index idx_sys_data_change_request on sys_data_change (request_id);
+
+-- ----------------------------
+-- 2、操作日志关联请求ID
+-- ----------------------------
+alter table sys_oper_log add column request_id varchar(64) default '' comment '请求ID';
+
+-- ----------------------------
+-- 3、变更历史按钮
+-- ----------------------------
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1063, '变更历史', 500, 4, '#', '', '', '', 1, 0, 'F', '0', '0', 'monitor:history:list', '#', 'admin', sysdate(), '', NULL, '');
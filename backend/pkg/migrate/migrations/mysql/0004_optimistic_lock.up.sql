@@ -0,0 +1,8 @@
+-- 乐观锁：核心表增加版本号，每次修改加1，修改时校验客户端读取时的版本号
+
+alter table sys_user add column version int not null default 1 comment '版本号';
+alter table sys_role add column version int not null default 1 comment '版本号';
+alter table sys_dept add column version int not null default 1 comment '版本号';
+alter table sys_menu add column version int not null default 1 comment '版本号';
+alter table sys_config add column version int not null default 1 comment '版本号';
+alter table sys_dict_data add column version int not null default 1 comment '版本号';
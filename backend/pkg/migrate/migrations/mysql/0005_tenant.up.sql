@@ -0,0 +1,101 @@
+-- 多租户：租户和租户套餐表，租户隔离的业务表增加租户ID，已有数据属于平台租户
+
+-- ----------------------------
+-- 1、租户表
+-- ----------------------------
+
+create table sys_tenant (
+  tenant_id          bigint(20)      not null auto_increment comment '租户ID',
+  tenant_name        varchar(50)     not null                comment '租户名称',
+  domain             varchar(100)    default ''              comment '绑定域名',
+  package_id         bigint(20)      default 0               comment '租户套餐ID（0不限制菜单）',
+  contact_user_name  varchar(30)     default ''              comment '联系人',
+  contact_phone      varchar(20)     default ''              comment '联系电话',
+  expire_time        datetime        default null            comment '过期时间',
+  status             char(1)         default '0'             comment '租户状态（0正常 1停用）',
+  del_flag           char(1)         default '0'             comment '删除标志（0代表存在 2代表删除）',
+  create_by          varchar(64)     default ''              comment '创建者',
+  create_time        datetime        default null            comment '创建时间',
+  update_by          varchar(64)     default ''              comment '更新者',
+  update_time        datetime        default null            comment '更新时间',
+  remark             varchar(500)    default null            comment '备注',
+  primary key (tenant_id)
+) engine=innodb comment = '租户表';
+
+insert into sys_tenant (tenant_id, tenant_name, domain, package_id, status, del_flag, create_by, create_time, remark) values (1, '平台租户', '', 0, '0', '0', 'admin', sysdate(), '平台租户，未开启租户模式时所有数据属于该租户');
+
+-- ----------------------------
+-- 2、租户套餐表和套餐菜单关联表
+-- ----------------------------
+
+create table sys_tenant_package (
+  package_id         bigint(20)      not null auto_increment comment '套餐ID',
+  package_name       varchar(50)     not null                comment '套餐名称',
+  status             char(1)         default '0'             comment '套餐状态（0正常 1停用）',
+  del_flag           char(1)         default '0'             comment '删除标志（0代表存在 2代表删除）',
+  create_by          varchar(64)     default ''              comment '创建者',
+  create_time        datetime        default null            comment '创建时间',
+  update_by          varchar(64)     default ''              comment '更新者',
+  update_time        datetime        default null            comment '更新时间',
+  remark             varchar(500)    default null            comment '备注',
+  primary key (package_id)
+) engine=innodb comment = '租户套餐表';
+
+create table sys_tenant_package_menu (
+  package_id         bigint(20)      not null                comment '套餐ID',
+  menu_id            bigint(20)      not null                comment '菜单ID',
+  primary key (package_id, menu_id)
+) engine=innodb comment = '租户套餐和菜单关联表';
+
+-- ----------------------------
+-- 3、租户隔离的业务表增加租户ID
+-- ----------------------------
+alter table sys_user add column tenant_id bigint(20) not null default 1 comment '租户ID';
+alter table sys_dept add column tenant_id bigint(20) not null default 1 comment '租户ID';
+alter table sys_role add column tenant_id bigint(20) not null default 1 comment '租户ID';
+alter table sys_post add column tenant_id bigint(20) not null default 1 comment '租户ID';
+alter table sys_config add column tenant_id bigint(20) not null default 1 comment '租户ID';
+alter table sys_dict_data add column tenant_id bigint(20) not null default 1 comment '租户ID';
+alter table sys_notice add column tenant_id bigint(20) not null default 1 comment '租户ID';
+alter table sys_oper_log add column tenant_id bigint(20) not null default 1 comment '租户ID';
+alter table sys_logininfor add column tenant_id bigint(20) not null default 1 comment '租户ID';
+alter table sys_recycle add column tenant_id bigint(20) not null default 1 comment '租户ID';
+alter table sys_data_change add column tenant_id bigint(20) not null default 1 comment '租户ID';
+
+create index idx_sys_user_tenant on sys_user (tenant_id, user_name);
+create index idx_sys_config_tenant on sys_config (tenant_id, config_key);
+create index idx_sys_dict_data_tenant on sys_dict_data (tenant_id, dict_type);
+
+-- ----------------------------
+-- 4、租户管理菜单（不指定ID，由数据库生成；已存在相同权限标识的菜单时跳过）
+-- ----------------------------
+insert into sys_menu (menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark)
+select '租户管理', 1, 11, 'tenant', 'system/tenant/index', '', '', 1, 0, 'C', '0', '0', 'system:tenant:list', 'peoples', 'admin', sysdate(), '', NULL, '租户管理菜单' from dual
+where not exists (select 1 from sys_menu where perms = 'system:tenant:list');
+insert into sys_menu (menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark)
+select '租户套餐', 1, 12, 'tenantPackage', 'system/tenantPackage/index', '', '', 1, 0, 'C', '0', '0', 'system:tenantPackage:list', 'shopping', 'admin', sysdate(), '', NULL, '租户套餐菜单' from dual
+where not exists (select 1 from sys_menu where perms = 'system:tenantPackage:list');
+insert into sys_menu (menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark)
+select '租户查询', m.menu_id, 1, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:tenant:query', '#', 'admin', sysdate(), '', NULL, '' from sys_menu m
+where m.perms = 'system:tenant:list' and not exists (select 1 from sys_menu where perms = 'system:tenant:query');
+insert into sys_menu (menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark)
+select '租户新增', m.menu_id, 2, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:tenant:add', '#', 'admin', sysdate(), '', NULL, '' from sys_menu m
+where m.perms = 'system:tenant:list' and not exists (select 1 from sys_menu where perms = 'system:tenant:add');
+insert into sys_menu (menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark)
+select '租户修改', m.menu_id, 3, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:tenant:edit', '#', 'admin', sysdate(), '', NULL, '' from sys_menu m
+where m.perms = 'system:tenant:list' and not exists (select 1 from sys_menu where perms = 'system:tenant:edit');
+insert into sys_menu (menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark)
+select '租户删除', m.menu_id, 4, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:tenant:remove', '#', 'admin', sysdate(), '', NULL, '' from sys_menu m
+where m.perms = 'system:tenant:list' and not exists (select 1 from sys_menu where perms = 'system:tenant:remove');
+insert into sys_menu (menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark)
+select '套餐查询', m.menu_id, 1, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:tenantPackage:query', '#', 'admin', sysdate(), '', NULL, '' from sys_menu m
+where m.perms = 'system:tenantPackage:list' and not exists (select 1 from sys_menu where perms = 'system:tenantPackage:query');
+insert into sys_menu (menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark)
+select '套餐新增', m.menu_id, 2, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:tenantPackage:add', '#', 'admin', sysdate(), '', NULL, '' from sys_menu m
+where m.perms = 'system:tenantPackage:list' and not exists (select 1 from sys_menu where perms = 'system:tenantPackage:add');
+insert into sys_menu (menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark)
+select '套餐修改', m.menu_id, 3, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:tenantPackage:edit', '#', 'admin', sysdate(), '', NULL, '' from sys_menu m
+where m.perms = 'system:tenantPackage:list' and not exists (select 1 from sys_menu where perms = 'system:tenantPackage:edit');
+insert into sys_menu (menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark)
+select '套餐删除', m.menu_id, 4, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:tenantPackage:remove', '#', 'admin', sysdate(), '', NULL, '' from sys_menu m
+where m.perms = 'system:tenantPackage:list' and not exists (select 1 from sys_menu where perms = 'system:tenantPackage:remove');
@@ -0,0 +1,27 @@
+-- 删除初始表结构
+
+drop table if exists gen_template_history;
+drop table if exists gen_template;
+drop table if exists gen_template_set;
+drop table if exists sys_job_calendar_rule;
+drop table if exists sys_job_calendar;
+drop table if exists sys_job_dependency;
+drop table if exists gen_table_column;
+drop table if exists gen_table;
+drop table if exists sys_notice;
+drop table if exists sys_job_log;
+drop table if exists sys_job;
+drop table if exists sys_logininfor;
+drop table if exists sys_config;
+drop table if exists sys_dict_data;
+drop table if exists sys_dict_type;
+drop table if exists sys_oper_log;
+drop table if exists sys_user_post;
+drop table if exists sys_role_dept;
+drop table if exists sys_role_menu;
+drop table if exists sys_user_role;
+drop table if exists sys_menu;
+drop table if exists sys_role;
+drop table if exists sys_post;
+drop table if exists sys_user;
+drop table if exists sys_dept;
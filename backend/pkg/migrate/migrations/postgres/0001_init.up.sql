@@ -0,0 +1,1152 @@
+-- 初始表结构和数据，与 sql/PostgreSQL_ry_20250522.sql 一致（去掉了删表语句）
+-- 已经用该脚本建好的库执行 migrate baseline 1 标记为已迁移
+
+-- ----------------------------
+-- PostgreSQL 版本（与 SqlServer_ry_20250522_COMPLETE.sql 表结构和初始数据一致）
+-- 配置 database.driver: "postgres"，表建在public架构下
+-- ----------------------------
+
+-- ----------------------------
+-- 1、部门表
+-- ----------------------------
+
+create table sys_dept (
+  dept_id      bigint          generated by default as identity (start with 200),
+  parent_id    bigint          default 0,
+  ancestors    varchar(50)     default '',
+  dept_name    varchar(30)     default '',
+  order_num    integer         default 0,
+  leader       varchar(20)     default null,
+  phone        varchar(11)     default null,
+  email        varchar(50)     default null,
+  status       char(1)         default '0',
+  del_flag     char(1)         default '0',
+  create_by    varchar(64)     default '',
+  create_time  timestamp       default null,
+  update_by    varchar(64)     default '',
+  update_time  timestamp       default null,
+  primary key (dept_id)
+);
+
+comment on table sys_dept is '部门表';
+comment on column sys_dept.dept_id is '部门id';
+comment on column sys_dept.parent_id is '父部门id';
+comment on column sys_dept.ancestors is '祖级列表';
+comment on column sys_dept.dept_name is '部门名称';
+comment on column sys_dept.order_num is '显示顺序';
+comment on column sys_dept.leader is '负责人';
+comment on column sys_dept.phone is '联系电话';
+comment on column sys_dept.email is '邮箱';
+comment on column sys_dept.status is '部门状态（0正常 1停用）';
+comment on column sys_dept.del_flag is '删除标志（0代表存在 2代表删除）';
+comment on column sys_dept.create_by is '创建者';
+comment on column sys_dept.create_time is '创建时间';
+comment on column sys_dept.update_by is '更新者';
+comment on column sys_dept.update_time is '更新时间';
+
+-- ----------------------------
+-- 初始化-部门表数据
+-- ----------------------------
+insert into sys_dept (dept_id, parent_id, ancestors, dept_name, order_num, leader, phone, email, status, del_flag, create_by, create_time, update_by, update_time) values (100, 0, '0', '若依科技', 0, '若依', '15888888888', 'ry@qq.com', '0', '0', 'admin', now(), '', NULL);
+insert into sys_dept (dept_id, parent_id, ancestors, dept_name, order_num, leader, phone, email, status, del_flag, create_by, create_time, update_by, update_time) values (101, 100, '0,100', '深圳总公司', 1, '若依', '15888888888', 'ry@qq.com', '0', '0', 'admin', now(), '', NULL);
+insert into sys_dept (dept_id, parent_id, ancestors, dept_name, order_num, leader, phone, email, status, del_flag, create_by, create_time, update_by, update_time) values (102, 100, '0,100', '长沙分公司', 2, '若依', '15888888888', 'ry@qq.com', '0', '0', 'admin', now(), '', NULL);
+insert into sys_dept (dept_id, parent_id, ancestors, dept_name, order_num, leader, phone, email, status, del_flag, create_by, create_time, update_by, update_time) values (103, 101, '0,100,101', '研发部门', 1, '若依', '15888888888', 'ry@qq.com', '0', '0', 'admin', now(), '', NULL);
+insert into sys_dept (dept_id, parent_id, ancestors, dept_name, order_num, leader, phone, email, status, del_flag, create_by, create_time, update_by, update_time) values (104, 101, '0,100,101', '市场部门', 2, '若依', '15888888888', 'ry@qq.com', '0', '0', 'admin', now(), '', NULL);
+insert into sys_dept (dept_id, parent_id, ancestors, dept_name, order_num, leader, phone, email, status, del_flag, create_by, create_time, update_by, update_time) values (105, 101, '0,100,101', '测试部门', 3, '若依', '15888888888', 'ry@qq.com', '0', '0', 'admin', now(), '', NULL);
+insert into sys_dept (dept_id, parent_id, ancestors, dept_name, order_num, leader, phone, email, status, del_flag, create_by, create_time, update_by, update_time) values (106, 101, '0,100,101', '财务部门', 4, '若依', '15888888888', 'ry@qq.com', '0', '0', 'admin', now(), '', NULL);
+insert into sys_dept (dept_id, parent_id, ancestors, dept_name, order_num, leader, phone, email, status, del_flag, create_by, create_time, update_by, update_time) values (107, 101, '0,100,101', '运维部门', 5, '若依', '15888888888', 'ry@qq.com', '0', '0', 'admin', now(), '', NULL);
+insert into sys_dept (dept_id, parent_id, ancestors, dept_name, order_num, leader, phone, email, status, del_flag, create_by, create_time, update_by, update_time) values (108, 102, '0,100,102', '市场部门', 1, '若依', '15888888888', 'ry@qq.com', '0', '0', 'admin', now(), '', NULL);
+insert into sys_dept (dept_id, parent_id, ancestors, dept_name, order_num, leader, phone, email, status, del_flag, create_by, create_time, update_by, update_time) values (109, 102, '0,100,102', '财务部门', 2, '若依', '15888888888', 'ry@qq.com', '0', '0', 'admin', now(), '', NULL);
+select setval(pg_get_serial_sequence('sys_dept', 'dept_id'), greatest(200, (select max(dept_id) + 1 from sys_dept)), false);
+
+-- ----------------------------
+-- 2、用户信息表
+-- ----------------------------
+
+create table sys_user (
+  user_id          bigint          generated by default as identity (start with 100),
+  dept_id          bigint          default null,
+  user_name        varchar(30)     not null,
+  nick_name        varchar(30)     not null,
+  user_type        varchar(2)      default '00',
+  email            varchar(50)     default '',
+  phonenumber      varchar(11)     default '',
+  sex              char(1)         default '0',
+  avatar           varchar(100)    default '',
+  password         varchar(100)    default '',
+  status           char(1)         default '0',
+  del_flag         char(1)         default '0',
+  login_ip         varchar(128)    default '',
+  login_date       timestamp       default null,
+  pwd_update_date  timestamp       default null,
+  create_by        varchar(64)     default '',
+  create_time      timestamp       default null,
+  update_by        varchar(64)     default '',
+  update_time      timestamp       default null,
+  remark           varchar(500)    default null,
+  primary key (user_id)
+);
+
+comment on table sys_user is '用户信息表';
+comment on column sys_user.user_id is '用户ID';
+comment on column sys_user.dept_id is '部门ID';
+comment on column sys_user.user_name is '用户账号';
+comment on column sys_user.nick_name is '用户昵称';
+comment on column sys_user.user_type is '用户类型（00系统用户）';
+comment on column sys_user.email is '用户邮箱';
+comment on column sys_user.phonenumber is '手机号码';
+comment on column sys_user.sex is '用户性别（0男 1女 2未知）';
+comment on column sys_user.avatar is '头像地址';
+comment on column sys_user.password is '密码';
+comment on column sys_user.status is '账号状态（0正常 1停用）';
+comment on column sys_user.del_flag is '删除标志（0代表存在 2代表删除）';
+comment on column sys_user.login_ip is '最后登录IP';
+comment on column sys_user.login_date is '最后登录时间';
+comment on column sys_user.pwd_update_date is '密码最后更新时间';
+comment on column sys_user.create_by is '创建者';
+comment on column sys_user.create_time is '创建时间';
+comment on column sys_user.update_by is '更新者';
+comment on column sys_user.update_time is '更新时间';
+comment on column sys_user.remark is '备注';
+
+-- ----------------------------
+-- 初始化-用户信息表数据
+-- ----------------------------
+insert into sys_user (user_id, dept_id, user_name, nick_name, user_type, email, phonenumber, sex, avatar, password, status, del_flag, login_ip, login_date, pwd_update_date, create_by, create_time, update_by, update_time, remark) values (1, 103, 'admin', '若依', '00', 'ry@163.com', '15888888888', '1', '', '$2a$10$7JB720yubVSZvUI0rEqK/.VqGOZTH.ulu33dHOiBE8ByOhJIrdAu2', '0', '0', '127.0.0.1', now(), now(), 'admin', now(), '', NULL, '管理员');
+insert into sys_user (user_id, dept_id, user_name, nick_name, user_type, email, phonenumber, sex, avatar, password, status, del_flag, login_ip, login_date, pwd_update_date, create_by, create_time, update_by, update_time, remark) values (2, 105, 'ry', '若依', '00', 'ry@qq.com', '15666666666', '1', '', '$2a$10$7JB720yubVSZvUI0rEqK/.VqGOZTH.ulu33dHOiBE8ByOhJIrdAu2', '0', '0', '127.0.0.1', now(), now(), 'admin', now(), '', NULL, '测试员');
+select setval(pg_get_serial_sequence('sys_user', 'user_id'), greatest(100, (select max(user_id) + 1 from sys_user)), false);
+
+-- ----------------------------
+-- 3、岗位信息表
+-- ----------------------------
+
+create table sys_post (
+  post_id      bigint          generated by default as identity,
+  post_code    varchar(64)     not null,
+  post_name    varchar(50)     not null,
+  post_sort    integer         not null,
+  status       char(1)         not null,
+  create_by    varchar(64)     default '',
+  create_time  timestamp       default null,
+  update_by    varchar(64)     default '',
+  update_time  timestamp       default null,
+  remark       varchar(500)    default null,
+  primary key (post_id)
+);
+
+comment on table sys_post is '岗位信息表';
+comment on column sys_post.post_id is '岗位ID';
+comment on column sys_post.post_code is '岗位编码';
+comment on column sys_post.post_name is '岗位名称';
+comment on column sys_post.post_sort is '显示顺序';
+comment on column sys_post.status is '状态（0正常 1停用）';
+comment on column sys_post.create_by is '创建者';
+comment on column sys_post.create_time is '创建时间';
+comment on column sys_post.update_by is '更新者';
+comment on column sys_post.update_time is '更新时间';
+comment on column sys_post.remark is '备注';
+
+-- ----------------------------
+-- 初始化-岗位信息表数据
+-- ----------------------------
+insert into sys_post (post_code, post_name, post_sort, status, create_by, create_time, update_by, update_time, remark) values ('ceo', '董事长', 1, '0', 'admin', now(), '', NULL, '');
+insert into sys_post (post_code, post_name, post_sort, status, create_by, create_time, update_by, update_time, remark) values ('se', '项目经理', 2, '0', 'admin', now(), '', NULL, '');
+insert into sys_post (post_code, post_name, post_sort, status, create_by, create_time, update_by, update_time, remark) values ('hr', '人力资源', 3, '0', 'admin', now(), '', NULL, '');
+insert into sys_post (post_code, post_name, post_sort, status, create_by, create_time, update_by, update_time, remark) values ('user', '普通员工', 4, '0', 'admin', now(), '', NULL, '');
+
+-- ----------------------------
+-- 4、角色信息表
+-- ----------------------------
+
+create table sys_role (
+  role_id              bigint          generated by default as identity (start with 100),
+  role_name            varchar(30)     not null,
+  role_key             varchar(100)    not null,
+  role_sort            integer         not null,
+  data_scope           char(1)         default '1',
+  menu_check_strictly  boolean         default true,
+  dept_check_strictly  boolean         default true,
+  status               char(1)         not null,
+  del_flag             char(1)         default '0',
+  create_by            varchar(64)     default '',
+  create_time          timestamp       default null,
+  update_by            varchar(64)     default '',
+  update_time          timestamp       default null,
+  remark               varchar(500)    default null,
+  primary key (role_id)
+);
+
+comment on table sys_role is '角色信息表';
+comment on column sys_role.role_id is '角色ID';
+comment on column sys_role.role_name is '角色名称';
+comment on column sys_role.role_key is '角色权限字符串';
+comment on column sys_role.role_sort is '显示顺序';
+comment on column sys_role.data_scope is '数据范围（1：全部数据权限 2：自定数据权限 3：本部门数据权限 4：本部门及以下数据权限）';
+comment on column sys_role.menu_check_strictly is '菜单树选择项是否关联显示';
+comment on column sys_role.dept_check_strictly is '部门树选择项是否关联显示';
+comment on column sys_role.status is '角色状态（0正常 1停用）';
+comment on column sys_role.del_flag is '删除标志（0代表存在 2代表删除）';
+comment on column sys_role.create_by is '创建者';
+comment on column sys_role.create_time is '创建时间';
+comment on column sys_role.update_by is '更新者';
+comment on column sys_role.update_time is '更新时间';
+comment on column sys_role.remark is '备注';
+
+-- ----------------------------
+-- 初始化-角色信息表数据
+-- ----------------------------
+insert into sys_role (role_id, role_name, role_key, role_sort, data_scope, menu_check_strictly, dept_check_strictly, status, del_flag, create_by, create_time, update_by, update_time, remark) values (1, '超级管理员', 'admin', 1, '1', true, true, '0', '0', 'admin', now(), '', NULL, '超级管理员');
+insert into sys_role (role_id, role_name, role_key, role_sort, data_scope, menu_check_strictly, dept_check_strictly, status, del_flag, create_by, create_time, update_by, update_time, remark) values (2, '普通角色', 'common', 2, '2', true, true, '0', '0', 'admin', now(), '', NULL, '普通角色');
+select setval(pg_get_serial_sequence('sys_role', 'role_id'), greatest(100, (select max(role_id) + 1 from sys_role)), false);
+
+-- ----------------------------
+-- 5、菜单权限表
+-- ----------------------------
+
+create table sys_menu (
+  menu_id      bigint          generated by default as identity (start with 2000),
+  menu_name    varchar(50)     not null,
+  parent_id    bigint          default 0,
+  order_num    integer         default 0,
+  path         varchar(200)    default '',
+  component    varchar(255)    default null,
+  query        varchar(255)    default null,
+  route_name   varchar(50)     default '',
+  is_frame     integer         default 1,
+  is_cache     integer         default 0,
+  menu_type    char(1)         default '',
+  visible      char(1)         default '0',
+  status       char(1)         default '0',
+  perms        varchar(100)    default null,
+  icon         varchar(100)    default '#',
+  create_by    varchar(64)     default '',
+  create_time  timestamp       default null,
+  update_by    varchar(64)     default '',
+  update_time  timestamp       default null,
+  remark       varchar(500)    default '',
+  primary key (menu_id)
+);
+
+comment on table sys_menu is '菜单权限表';
+comment on column sys_menu.menu_id is '菜单ID';
+comment on column sys_menu.menu_name is '菜单名称';
+comment on column sys_menu.parent_id is '父菜单ID';
+comment on column sys_menu.order_num is '显示顺序';
+comment on column sys_menu.path is '路由地址';
+comment on column sys_menu.component is '组件路径';
+comment on column sys_menu.query is '路由参数';
+comment on column sys_menu.route_name is '路由名称';
+comment on column sys_menu.is_frame is '是否为外链（0是 1否）';
+comment on column sys_menu.is_cache is '是否缓存（0缓存 1不缓存）';
+comment on column sys_menu.menu_type is '菜单类型（M目录 C菜单 F按钮）';
+comment on column sys_menu.visible is '菜单状态（0显示 1隐藏）';
+comment on column sys_menu.status is '菜单状态（0正常 1停用）';
+comment on column sys_menu.perms is '权限标识';
+comment on column sys_menu.icon is '菜单图标';
+comment on column sys_menu.create_by is '创建者';
+comment on column sys_menu.create_time is '创建时间';
+comment on column sys_menu.update_by is '更新者';
+comment on column sys_menu.update_time is '更新时间';
+comment on column sys_menu.remark is '备注';
+
+-- ----------------------------
+-- 初始化-菜单信息表数据 (完整85条数据)
+-- ----------------------------
+
+-- 一级菜单
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1, '系统管理', 0, 1, 'system', NULL, '', '', 1, 0, 'M', '0', '0', '', 'system', 'admin', now(), '', NULL, '系统管理目录');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (2, '系统监控', 0, 2, 'monitor', NULL, '', '', 1, 0, 'M', '0', '0', '', 'monitor', 'admin', now(), '', NULL, '系统监控目录');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (3, '系统工具', 0, 3, 'tool', NULL, '', '', 1, 0, 'M', '0', '0', '', 'tool', 'admin', now(), '', NULL, '系统工具目录');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (4, '若依官网', 0, 4, 'http://ruoyi.vip', NULL, '', '', 0, 0, 'M', '0', '0', '', 'guide', 'admin', now(), '', NULL, '若依官网地址');
+select setval(pg_get_serial_sequence('sys_menu', 'menu_id'), greatest(2000, (select max(menu_id) + 1 from sys_menu)), false);
+
+-- 二级菜单
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (100, '用户管理', 1, 1, 'user', 'system/user/index', '', '', 1, 0, 'C', '0', '0', 'system:user:list', 'user', 'admin', now(), '', NULL, '用户管理菜单');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (101, '角色管理', 1, 2, 'role', 'system/role/index', '', '', 1, 0, 'C', '0', '0', 'system:role:list', 'peoples', 'admin', now(), '', NULL, '角色管理菜单');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (102, '菜单管理', 1, 3, 'menu', 'system/menu/index', '', '', 1, 0, 'C', '0', '0', 'system:menu:list', 'tree-table', 'admin', now(), '', NULL, '菜单管理菜单');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (103, '部门管理', 1, 4, 'dept', 'system/dept/index', '', '', 1, 0, 'C', '0', '0', 'system:dept:list', 'tree', 'admin', now(), '', NULL, '部门管理菜单');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (104, '岗位管理', 1, 5, 'post', 'system/post/index', '', '', 1, 0, 'C', '0', '0', 'system:post:list', 'post', 'admin', now(), '', NULL, '岗位管理菜单');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (105, '字典管理', 1, 6, 'dict', 'system/dict/index', '', '', 1, 0, 'C', '0', '0', 'system:dict:list', 'dict', 'admin', now(), '', NULL, '字典管理菜单');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (106, '参数设置', 1, 7, 'config', 'system/config/index', '', '', 1, 0, 'C', '0', '0', 'system:config:list', 'edit', 'admin', now(), '', NULL, '参数设置菜单');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (107, '通知公告', 1, 8, 'notice', 'system/notice/index', '', '', 1, 0, 'C', '0', '0', 'system:notice:list', 'message', 'admin', now(), '', NULL, '通知公告菜单');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (108, '日志管理', 1, 9, 'log', '', '', '', 1, 0, 'M', '0', '0', '', 'log', 'admin', now(), '', NULL, '日志管理菜单');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (109, '在线用户', 2, 1, 'online', 'monitor/online/index', '', '', 1, 0, 'C', '0', '0', 'monitor:online:list', 'online', 'admin', now(), '', NULL, '在线用户菜单');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (110, '定时任务', 2, 2, 'job', 'monitor/job/index', '', '', 1, 0, 'C', '0', '0', 'monitor:job:list', 'job', 'admin', now(), '', NULL, '定时任务菜单');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (111, '数据监控', 2, 3, 'druid', 'monitor/druid/index', '', '', 1, 0, 'C', '0', '0', 'monitor:druid:list', 'druid', 'admin', now(), '', NULL, '数据监控菜单');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (112, '服务监控', 2, 4, 'server', 'monitor/server/index', '', '', 1, 0, 'C', '0', '0', 'monitor:server:list', 'server', 'admin', now(), '', NULL, '服务监控菜单');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (113, '缓存监控', 2, 5, 'cache', 'monitor/cache/index', '', '', 1, 0, 'C', '0', '0', 'monitor:cache:list', 'redis', 'admin', now(), '', NULL, '缓存监控菜单');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (114, '缓存列表', 2, 6, 'cacheList', 'monitor/cache/list', '', '', 1, 0, 'C', '0', '0', 'monitor:cache:list', 'redis-list', 'admin', now(), '', NULL, '缓存列表菜单');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (115, '表单构建', 3, 1, 'build', 'tool/build/index', '', '', 1, 0, 'C', '0', '0', 'tool:build:list', 'build', 'admin', now(), '', NULL, '表单构建菜单');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (116, '代码生成', 3, 2, 'gen', 'tool/gen/index', '', '', 1, 0, 'C', '0', '0', 'tool:gen:list', 'code', 'admin', now(), '', NULL, '代码生成菜单');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (117, '系统接口', 3, 3, 'swagger', 'tool/swagger/index', '', '', 1, 0, 'C', '0', '0', 'tool:swagger:list', 'swagger', 'admin', now(), '', NULL, '系统接口菜单');
+select setval(pg_get_serial_sequence('sys_menu', 'menu_id'), greatest(2000, (select max(menu_id) + 1 from sys_menu)), false);
+
+-- 三级菜单
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (500, '操作日志', 108, 1, 'operlog', 'monitor/operlog/index', '', '', 1, 0, 'C', '0', '0', 'monitor:operlog:list', 'form', 'admin', now(), '', NULL, '操作日志菜单');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (501, '登录日志', 108, 2, 'logininfor', 'monitor/logininfor/index', '', '', 1, 0, 'C', '0', '0', 'monitor:logininfor:list', 'logininfor', 'admin', now(), '', NULL, '登录日志菜单');
+select setval(pg_get_serial_sequence('sys_menu', 'menu_id'), greatest(2000, (select max(menu_id) + 1 from sys_menu)), false);
+
+-- 按钮权限 (完整61个按钮)
+-- 用户管理按钮
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1000, '用户查询', 100, 1, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:user:query', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1001, '用户新增', 100, 2, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:user:add', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1002, '用户修改', 100, 3, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:user:edit', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1003, '用户删除', 100, 4, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:user:remove', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1004, '用户导出', 100, 5, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:user:export', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1005, '用户导入', 100, 6, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:user:import', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1006, '重置密码', 100, 7, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:user:resetPwd', '#', 'admin', now(), '', NULL, '');
+-- 角色管理按钮
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1007, '角色查询', 101, 1, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:role:query', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1008, '角色新增', 101, 2, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:role:add', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1009, '角色修改', 101, 3, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:role:edit', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1010, '角色删除', 101, 4, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:role:remove', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1011, '角色导出', 101, 5, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:role:export', '#', 'admin', now(), '', NULL, '');
+-- 菜单管理按钮
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1012, '菜单查询', 102, 1, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:menu:query', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1013, '菜单新增', 102, 2, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:menu:add', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1014, '菜单修改', 102, 3, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:menu:edit', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1015, '菜单删除', 102, 4, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:menu:remove', '#', 'admin', now(), '', NULL, '');
+-- 部门管理按钮
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1016, '部门查询', 103, 1, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:dept:query', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1017, '部门新增', 103, 2, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:dept:add', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1018, '部门修改', 103, 3, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:dept:edit', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1019, '部门删除', 103, 4, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:dept:remove', '#', 'admin', now(), '', NULL, '');
+-- 岗位管理按钮
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1020, '岗位查询', 104, 1, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:post:query', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1021, '岗位新增', 104, 2, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:post:add', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1022, '岗位修改', 104, 3, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:post:edit', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1023, '岗位删除', 104, 4, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:post:remove', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1024, '岗位导出', 104, 5, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:post:export', '#', 'admin', now(), '', NULL, '');
+-- 字典管理按钮
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1025, '字典查询', 105, 1, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:dict:query', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1026, '字典新增', 105, 2, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:dict:add', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1027, '字典修改', 105, 3, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:dict:edit', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1028, '字典删除', 105, 4, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:dict:remove', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1029, '字典导出', 105, 5, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:dict:export', '#', 'admin', now(), '', NULL, '');
+-- 参数设置按钮
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1030, '参数查询', 106, 1, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:config:query', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1031, '参数新增', 106, 2, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:config:add', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1032, '参数修改', 106, 3, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:config:edit', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1033, '参数删除', 106, 4, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:config:remove', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1034, '参数导出', 106, 5, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:config:export', '#', 'admin', now(), '', NULL, '');
+-- 通知公告按钮
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1035, '公告查询', 107, 1, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:notice:query', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1036, '公告新增', 107, 2, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:notice:add', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1037, '公告修改', 107, 3, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:notice:edit', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1038, '公告删除', 107, 4, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:notice:remove', '#', 'admin', now(), '', NULL, '');
+-- 操作日志按钮
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1039, '操作查询', 500, 1, '#', '', '', '', 1, 0, 'F', '0', '0', 'monitor:operlog:query', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1040, '操作删除', 500, 2, '#', '', '', '', 1, 0, 'F', '0', '0', 'monitor:operlog:remove', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1041, '日志导出', 500, 3, '#', '', '', '', 1, 0, 'F', '0', '0', 'monitor:operlog:export', '#', 'admin', now(), '', NULL, '');
+-- 登录日志按钮
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1042, '登录查询', 501, 1, '#', '', '', '', 1, 0, 'F', '0', '0', 'monitor:logininfor:query', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1043, '登录删除', 501, 2, '#', '', '', '', 1, 0, 'F', '0', '0', 'monitor:logininfor:remove', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1044, '日志导出', 501, 3, '#', '', '', '', 1, 0, 'F', '0', '0', 'monitor:logininfor:export', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1045, '账户解锁', 501, 4, '#', '', '', '', 1, 0, 'F', '0', '0', 'monitor:logininfor:unlock', '#', 'admin', now(), '', NULL, '');
+-- 在线用户按钮
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1046, '在线查询', 109, 1, '#', '', '', '', 1, 0, 'F', '0', '0', 'monitor:online:query', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1047, '批量强退', 109, 2, '#', '', '', '', 1, 0, 'F', '0', '0', 'monitor:online:batchLogout', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1048, '单条强退', 109, 3, '#', '', '', '', 1, 0, 'F', '0', '0', 'monitor:online:forceLogout', '#', 'admin', now(), '', NULL, '');
+-- 定时任务按钮
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1049, '任务查询', 110, 1, '#', '', '', '', 1, 0, 'F', '0', '0', 'monitor:job:query', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1050, '任务新增', 110, 2, '#', '', '', '', 1, 0, 'F', '0', '0', 'monitor:job:add', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1051, '任务修改', 110, 3, '#', '', '', '', 1, 0, 'F', '0', '0', 'monitor:job:edit', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1052, '任务删除', 110, 4, '#', '', '', '', 1, 0, 'F', '0', '0', 'monitor:job:remove', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1053, '状态修改', 110, 5, '#', '', '', '', 1, 0, 'F', '0', '0', 'monitor:job:changeStatus', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1054, '任务导出', 110, 6, '#', '', '', '', 1, 0, 'F', '0', '0', 'monitor:job:export', '#', 'admin', now(), '', NULL, '');
+-- 代码生成按钮
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1055, '生成查询', 116, 1, '#', '', '', '', 1, 0, 'F', '0', '0', 'tool:gen:query', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1056, '生成修改', 116, 2, '#', '', '', '', 1, 0, 'F', '0', '0', 'tool:gen:edit', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1057, '生成删除', 116, 3, '#', '', '', '', 1, 0, 'F', '0', '0', 'tool:gen:remove', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1058, '导入代码', 116, 4, '#', '', '', '', 1, 0, 'F', '0', '0', 'tool:gen:import', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1059, '预览代码', 116, 5, '#', '', '', '', 1, 0, 'F', '0', '0', 'tool:gen:preview', '#', 'admin', now(), '', NULL, '');
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1060, '生成代码', 116, 6, '#', '', '', '', 1, 0, 'F', '0', '0', 'tool:gen:code', '#', 'admin', now(), '', NULL, '');
+select setval(pg_get_serial_sequence('sys_menu', 'menu_id'), greatest(2000, (select max(menu_id) + 1 from sys_menu)), false);
+
+-- ----------------------------
+-- 6、用户和角色关联表  用户N-1角色
+-- ----------------------------
+
+create table sys_user_role (
+  user_id  bigint          not null,
+  role_id  bigint          not null,
+  primary key (user_id, role_id)
+);
+
+comment on table sys_user_role is '用户和角色关联表  用户N-1角色';
+comment on column sys_user_role.user_id is '用户ID';
+comment on column sys_user_role.role_id is '角色ID';
+
+-- ----------------------------
+-- 初始化-用户和角色关联表数据
+-- ----------------------------
+insert into sys_user_role (user_id, role_id) values (1, 1);
+insert into sys_user_role (user_id, role_id) values (2, 2);
+
+-- ----------------------------
+-- 7、角色和菜单关联表  角色1-N菜单
+-- ----------------------------
+
+create table sys_role_menu (
+  role_id  bigint          not null,
+  menu_id  bigint          not null,
+  primary key (role_id, menu_id)
+);
+
+comment on table sys_role_menu is '角色和菜单关联表  角色1-N菜单';
+comment on column sys_role_menu.role_id is '角色ID';
+comment on column sys_role_menu.menu_id is '菜单ID';
+
+-- ----------------------------
+-- 初始化-角色和菜单关联表数据 (完整85条权限关联)
+-- ----------------------------
+insert into sys_role_menu (role_id, menu_id) values (2, 1);
+insert into sys_role_menu (role_id, menu_id) values (2, 2);
+insert into sys_role_menu (role_id, menu_id) values (2, 3);
+insert into sys_role_menu (role_id, menu_id) values (2, 4);
+insert into sys_role_menu (role_id, menu_id) values (2, 100);
+insert into sys_role_menu (role_id, menu_id) values (2, 101);
+insert into sys_role_menu (role_id, menu_id) values (2, 102);
+insert into sys_role_menu (role_id, menu_id) values (2, 103);
+insert into sys_role_menu (role_id, menu_id) values (2, 104);
+insert into sys_role_menu (role_id, menu_id) values (2, 105);
+insert into sys_role_menu (role_id, menu_id) values (2, 106);
+insert into sys_role_menu (role_id, menu_id) values (2, 107);
+insert into sys_role_menu (role_id, menu_id) values (2, 108);
+insert into sys_role_menu (role_id, menu_id) values (2, 109);
+insert into sys_role_menu (role_id, menu_id) values (2, 110);
+insert into sys_role_menu (role_id, menu_id) values (2, 111);
+insert into sys_role_menu (role_id, menu_id) values (2, 112);
+insert into sys_role_menu (role_id, menu_id) values (2, 113);
+insert into sys_role_menu (role_id, menu_id) values (2, 114);
+insert into sys_role_menu (role_id, menu_id) values (2, 115);
+insert into sys_role_menu (role_id, menu_id) values (2, 116);
+insert into sys_role_menu (role_id, menu_id) values (2, 117);
+insert into sys_role_menu (role_id, menu_id) values (2, 500);
+insert into sys_role_menu (role_id, menu_id) values (2, 501);
+insert into sys_role_menu (role_id, menu_id) values (2, 1000);
+insert into sys_role_menu (role_id, menu_id) values (2, 1001);
+insert into sys_role_menu (role_id, menu_id) values (2, 1002);
+insert into sys_role_menu (role_id, menu_id) values (2, 1003);
+insert into sys_role_menu (role_id, menu_id) values (2, 1004);
+insert into sys_role_menu (role_id, menu_id) values (2, 1005);
+insert into sys_role_menu (role_id, menu_id) values (2, 1006);
+insert into sys_role_menu (role_id, menu_id) values (2, 1007);
+insert into sys_role_menu (role_id, menu_id) values (2, 1008);
+insert into sys_role_menu (role_id, menu_id) values (2, 1009);
+insert into sys_role_menu (role_id, menu_id) values (2, 1010);
+insert into sys_role_menu (role_id, menu_id) values (2, 1011);
+insert into sys_role_menu (role_id, menu_id) values (2, 1012);
+insert into sys_role_menu (role_id, menu_id) values (2, 1013);
+insert into sys_role_menu (role_id, menu_id) values (2, 1014);
+insert into sys_role_menu (role_id, menu_id) values (2, 1015);
+insert into sys_role_menu (role_id, menu_id) values (2, 1016);
+insert into sys_role_menu (role_id, menu_id) values (2, 1017);
+insert into sys_role_menu (role_id, menu_id) values (2, 1018);
+insert into sys_role_menu (role_id, menu_id) values (2, 1019);
+insert into sys_role_menu (role_id, menu_id) values (2, 1020);
+insert into sys_role_menu (role_id, menu_id) values (2, 1021);
+insert into sys_role_menu (role_id, menu_id) values (2, 1022);
+insert into sys_role_menu (role_id, menu_id) values (2, 1023);
+insert into sys_role_menu (role_id, menu_id) values (2, 1024);
+insert into sys_role_menu (role_id, menu_id) values (2, 1025);
+insert into sys_role_menu (role_id, menu_id) values (2, 1026);
+insert into sys_role_menu (role_id, menu_id) values (2, 1027);
+insert into sys_role_menu (role_id, menu_id) values (2, 1028);
+insert into sys_role_menu (role_id, menu_id) values (2, 1029);
+insert into sys_role_menu (role_id, menu_id) values (2, 1030);
+insert into sys_role_menu (role_id, menu_id) values (2, 1031);
+insert into sys_role_menu (role_id, menu_id) values (2, 1032);
+insert into sys_role_menu (role_id, menu_id) values (2, 1033);
+insert into sys_role_menu (role_id, menu_id) values (2, 1034);
+insert into sys_role_menu (role_id, menu_id) values (2, 1035);
+insert into sys_role_menu (role_id, menu_id) values (2, 1036);
+insert into sys_role_menu (role_id, menu_id) values (2, 1037);
+insert into sys_role_menu (role_id, menu_id) values (2, 1038);
+insert into sys_role_menu (role_id, menu_id) values (2, 1039);
+insert into sys_role_menu (role_id, menu_id) values (2, 1040);
+insert into sys_role_menu (role_id, menu_id) values (2, 1041);
+insert into sys_role_menu (role_id, menu_id) values (2, 1042);
+insert into sys_role_menu (role_id, menu_id) values (2, 1043);
+insert into sys_role_menu (role_id, menu_id) values (2, 1044);
+insert into sys_role_menu (role_id, menu_id) values (2, 1045);
+insert into sys_role_menu (role_id, menu_id) values (2, 1046);
+insert into sys_role_menu (role_id, menu_id) values (2, 1047);
+insert into sys_role_menu (role_id, menu_id) values (2, 1048);
+insert into sys_role_menu (role_id, menu_id) values (2, 1049);
+insert into sys_role_menu (role_id, menu_id) values (2, 1050);
+insert into sys_role_menu (role_id, menu_id) values (2, 1051);
+insert into sys_role_menu (role_id, menu_id) values (2, 1052);
+insert into sys_role_menu (role_id, menu_id) values (2, 1053);
+insert into sys_role_menu (role_id, menu_id) values (2, 1054);
+insert into sys_role_menu (role_id, menu_id) values (2, 1055);
+insert into sys_role_menu (role_id, menu_id) values (2, 1056);
+insert into sys_role_menu (role_id, menu_id) values (2, 1057);
+insert into sys_role_menu (role_id, menu_id) values (2, 1058);
+insert into sys_role_menu (role_id, menu_id) values (2, 1059);
+insert into sys_role_menu (role_id, menu_id) values (2, 1060);
+
+-- ----------------------------
+-- 8、角色和部门关联表  角色1-N部门
+-- ----------------------------
+
+create table sys_role_dept (
+  role_id  bigint          not null,
+  dept_id  bigint          not null,
+  primary key (role_id, dept_id)
+);
+
+comment on table sys_role_dept is '角色和部门关联表  角色1-N部门';
+comment on column sys_role_dept.role_id is '角色ID';
+comment on column sys_role_dept.dept_id is '部门ID';
+
+-- ----------------------------
+-- 初始化-角色和部门关联表数据
+-- ----------------------------
+insert into sys_role_dept (role_id, dept_id) values (2, 100);
+insert into sys_role_dept (role_id, dept_id) values (2, 101);
+insert into sys_role_dept (role_id, dept_id) values (2, 105);
+
+-- ----------------------------
+-- 9、用户与岗位关联表  用户1-N岗位
+-- ----------------------------
+
+create table sys_user_post (
+  user_id  bigint          not null,
+  post_id  bigint          not null,
+  primary key (user_id, post_id)
+);
+
+comment on table sys_user_post is '用户与岗位关联表  用户1-N岗位';
+comment on column sys_user_post.user_id is '用户ID';
+comment on column sys_user_post.post_id is '岗位ID';
+
+-- ----------------------------
+-- 初始化-用户与岗位关联表数据
+-- ----------------------------
+insert into sys_user_post (user_id, post_id) values (1, 1);
+insert into sys_user_post (user_id, post_id) values (2, 2);
+
+-- ----------------------------
+-- 10、操作日志记录
+-- ----------------------------
+
+create table sys_oper_log (
+  oper_id         bigint          generated by default as identity (start with 100),
+  title           varchar(50)     default '',
+  business_type   integer         default 0,
+  method          varchar(200)    default '',
+  request_method  varchar(10)     default '',
+  operator_type   integer         default 0,
+  oper_name       varchar(50)     default '',
+  dept_name       varchar(50)     default '',
+  oper_url        varchar(255)    default '',
+  oper_ip         varchar(128)    default '',
+  oper_location   varchar(255)    default '',
+  oper_param      varchar(2000)   default '',
+  json_result     varchar(2000)   default '',
+  status          integer         default 0,
+  error_msg       varchar(2000)   default '',
+  oper_time       timestamp       default null,
+  cost_time       bigint          default 0,
+  primary key (oper_id)
+);
+
+comment on table sys_oper_log is '操作日志记录';
+comment on column sys_oper_log.oper_id is '日志主键';
+comment on column sys_oper_log.title is '模块标题';
+comment on column sys_oper_log.business_type is '业务类型（0其它 1新增 2修改 3删除）';
+comment on column sys_oper_log.method is '方法名称';
+comment on column sys_oper_log.request_method is '请求方式';
+comment on column sys_oper_log.operator_type is '操作类别（0其它 1后台用户 2手机端用户）';
+comment on column sys_oper_log.oper_name is '操作人员';
+comment on column sys_oper_log.dept_name is '部门名称';
+comment on column sys_oper_log.oper_url is '请求URL';
+comment on column sys_oper_log.oper_ip is '主机地址';
+comment on column sys_oper_log.oper_location is '操作地点';
+comment on column sys_oper_log.oper_param is '请求参数';
+comment on column sys_oper_log.json_result is '返回参数';
+comment on column sys_oper_log.status is '操作状态（0正常 1异常）';
+comment on column sys_oper_log.error_msg is '错误消息';
+comment on column sys_oper_log.oper_time is '操作时间';
+comment on column sys_oper_log.cost_time is '消耗时间';
+
+-- 添加索引
+create index idx_sys_oper_log_bt on sys_oper_log (business_type);
+create index idx_sys_oper_log_s on sys_oper_log (status);
+create index idx_sys_oper_log_ot on sys_oper_log (oper_time);
+
+-- ----------------------------
+-- 11、字典类型表
+-- ----------------------------
+
+create table sys_dict_type (
+  dict_id      bigint          generated by default as identity (start with 100),
+  dict_name    varchar(100)    default '',
+  dict_type    varchar(100)    default '',
+  status       char(1)         default '0',
+  create_by    varchar(64)     default '',
+  create_time  timestamp       default null,
+  update_by    varchar(64)     default '',
+  update_time  timestamp       default null,
+  remark       varchar(500)    default null,
+  primary key (dict_id),
+  unique (dict_type)
+);
+
+comment on table sys_dict_type is '字典类型表';
+comment on column sys_dict_type.dict_id is '字典主键';
+comment on column sys_dict_type.dict_name is '字典名称';
+comment on column sys_dict_type.dict_type is '字典类型';
+comment on column sys_dict_type.status is '状态（0正常 1停用）';
+comment on column sys_dict_type.create_by is '创建者';
+comment on column sys_dict_type.create_time is '创建时间';
+comment on column sys_dict_type.update_by is '更新者';
+comment on column sys_dict_type.update_time is '更新时间';
+comment on column sys_dict_type.remark is '备注';
+
+-- ----------------------------
+-- 初始化-字典类型表数据
+-- ----------------------------
+insert into sys_dict_type (dict_id, dict_name, dict_type, status, create_by, create_time, update_by, update_time, remark) values (1, '用户性别', 'sys_user_sex', '0', 'admin', now(), '', NULL, '用户性别列表');
+insert into sys_dict_type (dict_id, dict_name, dict_type, status, create_by, create_time, update_by, update_time, remark) values (2, '菜单状态', 'sys_show_hide', '0', 'admin', now(), '', NULL, '菜单状态列表');
+insert into sys_dict_type (dict_id, dict_name, dict_type, status, create_by, create_time, update_by, update_time, remark) values (3, '系统开关', 'sys_normal_disable', '0', 'admin', now(), '', NULL, '系统开关列表');
+insert into sys_dict_type (dict_id, dict_name, dict_type, status, create_by, create_time, update_by, update_time, remark) values (4, '任务状态', 'sys_job_status', '0', 'admin', now(), '', NULL, '任务状态列表');
+insert into sys_dict_type (dict_id, dict_name, dict_type, status, create_by, create_time, update_by, update_time, remark) values (5, '任务分组', 'sys_job_group', '0', 'admin', now(), '', NULL, '任务分组列表');
+insert into sys_dict_type (dict_id, dict_name, dict_type, status, create_by, create_time, update_by, update_time, remark) values (6, '系统是否', 'sys_yes_no', '0', 'admin', now(), '', NULL, '系统是否列表');
+insert into sys_dict_type (dict_id, dict_name, dict_type, status, create_by, create_time, update_by, update_time, remark) values (7, '通知类型', 'sys_notice_type', '0', 'admin', now(), '', NULL, '通知类型列表');
+insert into sys_dict_type (dict_id, dict_name, dict_type, status, create_by, create_time, update_by, update_time, remark) values (8, '通知状态', 'sys_notice_status', '0', 'admin', now(), '', NULL, '通知状态列表');
+insert into sys_dict_type (dict_id, dict_name, dict_type, status, create_by, create_time, update_by, update_time, remark) values (9, '操作类型', 'sys_oper_type', '0', 'admin', now(), '', NULL, '操作类型列表');
+insert into sys_dict_type (dict_id, dict_name, dict_type, status, create_by, create_time, update_by, update_time, remark) values (10, '系统状态', 'sys_common_status', '0', 'admin', now(), '', NULL, '登录状态列表');
+select setval(pg_get_serial_sequence('sys_dict_type', 'dict_id'), greatest(100, (select max(dict_id) + 1 from sys_dict_type)), false);
+
+-- ----------------------------
+-- 12、字典数据表
+-- ----------------------------
+
+create table sys_dict_data (
+  dict_code    bigint          generated by default as identity (start with 100),
+  dict_sort    integer         default 0,
+  dict_label   varchar(100)    default '',
+  dict_value   varchar(100)    default '',
+  dict_type    varchar(100)    default '',
+  css_class    varchar(100)    default null,
+  list_class   varchar(100)    default null,
+  is_default   char(1)         default 'N',
+  status       char(1)         default '0',
+  create_by    varchar(64)     default '',
+  create_time  timestamp       default null,
+  update_by    varchar(64)     default '',
+  update_time  timestamp       default null,
+  remark       varchar(500)    default null,
+  primary key (dict_code)
+);
+
+comment on table sys_dict_data is '字典数据表';
+comment on column sys_dict_data.dict_code is '字典编码';
+comment on column sys_dict_data.dict_sort is '字典排序';
+comment on column sys_dict_data.dict_label is '字典标签';
+comment on column sys_dict_data.dict_value is '字典键值';
+comment on column sys_dict_data.dict_type is '字典类型';
+comment on column sys_dict_data.css_class is '样式属性（其他样式扩展）';
+comment on column sys_dict_data.list_class is '表格回显样式';
+comment on column sys_dict_data.is_default is '是否默认（Y是 N否）';
+comment on column sys_dict_data.status is '状态（0正常 1停用）';
+comment on column sys_dict_data.create_by is '创建者';
+comment on column sys_dict_data.create_time is '创建时间';
+comment on column sys_dict_data.update_by is '更新者';
+comment on column sys_dict_data.update_time is '更新时间';
+comment on column sys_dict_data.remark is '备注';
+
+-- ----------------------------
+-- 初始化-字典数据表数据 (完整29条数据)
+-- ----------------------------
+insert into sys_dict_data (dict_code, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, create_time, update_by, update_time, remark) values (1, 1, '男', '0', 'sys_user_sex', '', '', 'Y', '0', 'admin', now(), '', NULL, '性别男');
+insert into sys_dict_data (dict_code, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, create_time, update_by, update_time, remark) values (2, 2, '女', '1', 'sys_user_sex', '', '', 'N', '0', 'admin', now(), '', NULL, '性别女');
+insert into sys_dict_data (dict_code, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, create_time, update_by, update_time, remark) values (3, 3, '未知', '2', 'sys_user_sex', '', '', 'N', '0', 'admin', now(), '', NULL, '性别未知');
+insert into sys_dict_data (dict_code, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, create_time, update_by, update_time, remark) values (4, 1, '显示', '0', 'sys_show_hide', '', 'primary', 'Y', '0', 'admin', now(), '', NULL, '显示菜单');
+insert into sys_dict_data (dict_code, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, create_time, update_by, update_time, remark) values (5, 2, '隐藏', '1', 'sys_show_hide', '', 'danger', 'N', '0', 'admin', now(), '', NULL, '隐藏菜单');
+insert into sys_dict_data (dict_code, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, create_time, update_by, update_time, remark) values (6, 1, '正常', '0', 'sys_normal_disable', '', 'primary', 'Y', '0', 'admin', now(), '', NULL, '正常状态');
+insert into sys_dict_data (dict_code, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, create_time, update_by, update_time, remark) values (7, 2, '停用', '1', 'sys_normal_disable', '', 'danger', 'N', '0', 'admin', now(), '', NULL, '停用状态');
+insert into sys_dict_data (dict_code, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, create_time, update_by, update_time, remark) values (8, 1, '正常', '0', 'sys_job_status', '', 'primary', 'Y', '0', 'admin', now(), '', NULL, '正常状态');
+insert into sys_dict_data (dict_code, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, create_time, update_by, update_time, remark) values (9, 2, '暂停', '1', 'sys_job_status', '', 'danger', 'N', '0', 'admin', now(), '', NULL, '停用状态');
+insert into sys_dict_data (dict_code, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, create_time, update_by, update_time, remark) values (10, 1, '默认', 'DEFAULT', 'sys_job_group', '', '', 'Y', '0', 'admin', now(), '', NULL, '默认分组');
+insert into sys_dict_data (dict_code, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, create_time, update_by, update_time, remark) values (11, 2, '系统', 'SYSTEM', 'sys_job_group', '', '', 'N', '0', 'admin', now(), '', NULL, '系统分组');
+insert into sys_dict_data (dict_code, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, create_time, update_by, update_time, remark) values (12, 1, '是', 'Y', 'sys_yes_no', '', 'primary', 'Y', '0', 'admin', now(), '', NULL, '系统默认是');
+insert into sys_dict_data (dict_code, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, create_time, update_by, update_time, remark) values (13, 2, '否', 'N', 'sys_yes_no', '', 'danger', 'N', '0', 'admin', now(), '', NULL, '系统默认否');
+insert into sys_dict_data (dict_code, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, create_time, update_by, update_time, remark) values (14, 1, '通知', '1', 'sys_notice_type', '', 'warning', 'Y', '0', 'admin', now(), '', NULL, '通知');
+insert into sys_dict_data (dict_code, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, create_time, update_by, update_time, remark) values (15, 2, '公告', '2', 'sys_notice_type', '', 'success', 'N', '0', 'admin', now(), '', NULL, '公告');
+insert into sys_dict_data (dict_code, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, create_time, update_by, update_time, remark) values (16, 1, '正常', '0', 'sys_notice_status', '', 'primary', 'Y', '0', 'admin', now(), '', NULL, '正常状态');
+insert into sys_dict_data (dict_code, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, create_time, update_by, update_time, remark) values (17, 2, '关闭', '1', 'sys_notice_status', '', 'danger', 'N', '0', 'admin', now(), '', NULL, '关闭状态');
+insert into sys_dict_data (dict_code, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, create_time, update_by, update_time, remark) values (18, 99, '其他', '0', 'sys_oper_type', '', 'info', 'N', '0', 'admin', now(), '', NULL, '其他操作');
+insert into sys_dict_data (dict_code, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, create_time, update_by, update_time, remark) values (19, 1, '新增', '1', 'sys_oper_type', '', 'info', 'N', '0', 'admin', now(), '', NULL, '新增操作');
+insert into sys_dict_data (dict_code, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, create_time, update_by, update_time, remark) values (20, 2, '修改', '2', 'sys_oper_type', '', 'info', 'N', '0', 'admin', now(), '', NULL, '修改操作');
+insert into sys_dict_data (dict_code, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, create_time, update_by, update_time, remark) values (21, 3, '删除', '3', 'sys_oper_type', '', 'danger', 'N', '0', 'admin', now(), '', NULL, '删除操作');
+insert into sys_dict_data (dict_code, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, create_time, update_by, update_time, remark) values (22, 4, '授权', '4', 'sys_oper_type', '', 'primary', 'N', '0', 'admin', now(), '', NULL, '授权操作');
+insert into sys_dict_data (dict_code, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, create_time, update_by, update_time, remark) values (23, 5, '导出', '5', 'sys_oper_type', '', 'warning', 'N', '0', 'admin', now(), '', NULL, '导出操作');
+insert into sys_dict_data (dict_code, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, create_time, update_by, update_time, remark) values (24, 6, '导入', '6', 'sys_oper_type', '', 'warning', 'N', '0', 'admin', now(), '', NULL, '导入操作');
+insert into sys_dict_data (dict_code, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, create_time, update_by, update_time, remark) values (25, 7, '强退', '7', 'sys_oper_type', '', 'danger', 'N', '0', 'admin', now(), '', NULL, '强退操作');
+insert into sys_dict_data (dict_code, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, create_time, update_by, update_time, remark) values (26, 8, '生成代码', '8', 'sys_oper_type', '', 'warning', 'N', '0', 'admin', now(), '', NULL, '生成操作');
+insert into sys_dict_data (dict_code, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, create_time, update_by, update_time, remark) values (27, 9, '清空数据', '9', 'sys_oper_type', '', 'danger', 'N', '0', 'admin', now(), '', NULL, '清空操作');
+insert into sys_dict_data (dict_code, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, create_time, update_by, update_time, remark) values (28, 1, '成功', '0', 'sys_common_status', '', 'primary', 'N', '0', 'admin', now(), '', NULL, '正常状态');
+insert into sys_dict_data (dict_code, dict_sort, dict_label, dict_value, dict_type, css_class, list_class, is_default, status, create_by, create_time, update_by, update_time, remark) values (29, 2, '失败', '1', 'sys_common_status', '', 'danger', 'N', '0', 'admin', now(), '', NULL, '停用状态');
+select setval(pg_get_serial_sequence('sys_dict_data', 'dict_code'), greatest(100, (select max(dict_code) + 1 from sys_dict_data)), false);
+
+-- ----------------------------
+-- 13、参数配置表
+-- ----------------------------
+
+create table sys_config (
+  config_id     integer         generated by default as identity (start with 100),
+  config_name   varchar(100)    default '',
+  config_key    varchar(100)    default '',
+  config_value  varchar(500)    default '',
+  config_type   char(1)         default 'N',
+  create_by     varchar(64)     default '',
+  create_time   timestamp       default null,
+  update_by     varchar(64)     default '',
+  update_time   timestamp       default null,
+  remark        varchar(500)    default null,
+  primary key (config_id)
+);
+
+comment on table sys_config is '参数配置表';
+comment on column sys_config.config_id is '参数主键';
+comment on column sys_config.config_name is '参数名称';
+comment on column sys_config.config_key is '参数键名';
+comment on column sys_config.config_value is '参数键值';
+comment on column sys_config.config_type is '系统内置（Y是 N否）';
+comment on column sys_config.create_by is '创建者';
+comment on column sys_config.create_time is '创建时间';
+comment on column sys_config.update_by is '更新者';
+comment on column sys_config.update_time is '更新时间';
+comment on column sys_config.remark is '备注';
+
+-- ----------------------------
+-- 初始化-参数配置表数据
+-- ----------------------------
+insert into sys_config (config_name, config_key, config_value, config_type, create_by, create_time, update_by, update_time, remark) values ('主框架页-默认皮肤样式名称', 'sys.index.skinName', 'skin-blue', 'Y', 'admin', now(), '', NULL, '蓝色 skin-blue、绿色 skin-green、紫色 skin-purple、红色 skin-red、黄色 skin-yellow');
+insert into sys_config (config_name, config_key, config_value, config_type, create_by, create_time, update_by, update_time, remark) values ('用户管理-账号初始密码', 'sys.user.initPassword', '123456', 'Y', 'admin', now(), '', NULL, '初始化密码 123456');
+insert into sys_config (config_name, config_key, config_value, config_type, create_by, create_time, update_by, update_time, remark) values ('主框架页-侧边栏主题', 'sys.index.sideTheme', 'theme-dark', 'Y', 'admin', now(), '', NULL, '深色主题theme-dark，浅色主题theme-light');
+insert into sys_config (config_name, config_key, config_value, config_type, create_by, create_time, update_by, update_time, remark) values ('账号自助-验证码开关', 'sys.account.captchaEnabled', 'true', 'Y', 'admin', now(), '', NULL, '是否开启验证码功能（true开启，false关闭）');
+insert into sys_config (config_name, config_key, config_value, config_type, create_by, create_time, update_by, update_time, remark) values ('账号自助-是否开启用户注册功能', 'sys.account.registerUser', 'false', 'Y', 'admin', now(), '', NULL, '是否开启注册用户功能（true开启，false关闭）');
+insert into sys_config (config_name, config_key, config_value, config_type, create_by, create_time, update_by, update_time, remark) values ('用户登录-黑名单列表', 'sys.login.blackIPList', '', 'Y', 'admin', now(), '', NULL, '设置登录IP黑名单限制，多个匹配项以;分隔，支持匹配（*通配、网段）');
+insert into sys_config (config_name, config_key, config_value, config_type, create_by, create_time, update_by, update_time, remark) values ('用户管理-初始密码修改策略', 'sys.account.initPasswordModify', '1', 'Y', 'admin', now(), '', NULL, '0：初始密码修改策略关闭，没有任何提示，1：提醒用户，如果未修改初始密码，则在登录时就会提醒修改密码对话框');
+insert into sys_config (config_name, config_key, config_value, config_type, create_by, create_time, update_by, update_time, remark) values ('用户管理-账号密码更新周期', 'sys.account.passwordValidateDays', '0', 'Y', 'admin', now(), '', NULL, '密码更新周期（填写数字，数据初始化值为0不限制，若修改必须为大于0小于365的正整数），如果超过这个周期登录系统时，则在登录时就会提醒修改密码对话框');
+
+-- ----------------------------
+-- 14、系统访问记录
+-- ----------------------------
+
+create table sys_logininfor (
+  info_id         bigint          generated by default as identity (start with 100),
+  user_name       varchar(50)     default '',
+  ipaddr          varchar(128)    default '',
+  login_location  varchar(255)    default '',
+  browser         varchar(50)     default '',
+  os              varchar(50)     default '',
+  status          char(1)         default '0',
+  msg             varchar(255)    default '',
+  login_time      timestamp       default null,
+  primary key (info_id)
+);
+
+comment on table sys_logininfor is '系统访问记录';
+comment on column sys_logininfor.info_id is '访问ID';
+comment on column sys_logininfor.user_name is '用户账号';
+comment on column sys_logininfor.ipaddr is '登录IP地址';
+comment on column sys_logininfor.login_location is '登录地点';
+comment on column sys_logininfor.browser is '浏览器类型';
+comment on column sys_logininfor.os is '操作系统';
+comment on column sys_logininfor.status is '登录状态（0成功 1失败）';
+comment on column sys_logininfor.msg is '提示消息';
+comment on column sys_logininfor.login_time is '访问时间';
+
+-- 添加索引
+create index idx_sys_logininfor_s on sys_logininfor (status);
+create index idx_sys_logininfor_lt on sys_logininfor (login_time);
+
+-- ----------------------------
+-- 15、定时任务调度表
+-- ----------------------------
+
+create table sys_job (
+  job_id           bigint          generated by default as identity (start with 100),
+  job_name         varchar(64)     default '',
+  job_group        varchar(64)     default 'DEFAULT',
+  invoke_target    varchar(500)    not null,
+  cron_expression  varchar(255)    default '',
+  misfire_policy   varchar(20)     default '3',
+  concurrent       char(1)         default '1',
+  status           char(1)         default '0',
+  create_by        varchar(64)     default '',
+  create_time      timestamp       default null,
+  update_by        varchar(64)     default '',
+  update_time      timestamp       default null,
+  remark           varchar(500)    default '',
+  job_type         char(1)         default '0',
+  job_config       text            default null,
+  calendar_id      bigint          default 0,
+  primary key (job_id, job_name, job_group)
+);
+
+comment on table sys_job is '定时任务调度表';
+comment on column sys_job.job_id is '任务ID';
+comment on column sys_job.job_name is '任务名称';
+comment on column sys_job.job_group is '任务组名';
+comment on column sys_job.invoke_target is '调用目标字符串';
+comment on column sys_job.cron_expression is 'cron执行表达式';
+comment on column sys_job.misfire_policy is '计划执行错误策略（1立即执行 2执行一次 3放弃执行）';
+comment on column sys_job.concurrent is '是否并发执行（0允许 1禁止）';
+comment on column sys_job.status is '状态（0正常 1暂停）';
+comment on column sys_job.create_by is '创建者';
+comment on column sys_job.create_time is '创建时间';
+comment on column sys_job.update_by is '更新者';
+comment on column sys_job.update_time is '更新时间';
+comment on column sys_job.remark is '备注信息';
+comment on column sys_job.job_type is '任务类型（0调用目标 1HTTP请求 2SQL）';
+comment on column sys_job.job_config is '任务类型配置（JSON）';
+comment on column sys_job.calendar_id is '排除日历ID（0表示不使用日历）';
+
+-- ----------------------------
+-- 初始化-定时任务调度表数据
+-- ----------------------------
+insert into sys_job (job_id, job_name, job_group, invoke_target, cron_expression, misfire_policy, concurrent, status, create_by, create_time, update_by, update_time, remark) values (1, '系统默认（无参）', 'DEFAULT', 'ryTask.ryNoParams', '0/10 * * * * ?', '3', '1', '1', 'admin', now(), '', NULL, '');
+insert into sys_job (job_id, job_name, job_group, invoke_target, cron_expression, misfire_policy, concurrent, status, create_by, create_time, update_by, update_time, remark) values (2, '系统默认（有参）', 'DEFAULT', 'ryTask.ryParams(''ry'')', '0/15 * * * * ?', '3', '1', '1', 'admin', now(), '', NULL, '');
+insert into sys_job (job_id, job_name, job_group, invoke_target, cron_expression, misfire_policy, concurrent, status, create_by, create_time, update_by, update_time, remark) values (3, '系统默认（多参）', 'DEFAULT', 'ryTask.ryMultipleParams(''ry'', true, 2000L, 316.50D, 100)', '0/20 * * * * ?', '3', '1', '1', 'admin', now(), '', NULL, '');
+select setval(pg_get_serial_sequence('sys_job', 'job_id'), greatest(100, (select max(job_id) + 1 from sys_job)), false);
+
+-- ----------------------------
+-- 16、定时任务调度日志表
+-- ----------------------------
+
+create table sys_job_log (
+  job_log_id      bigint          generated by default as identity,
+  job_name        varchar(64)     not null,
+  job_group       varchar(64)     not null,
+  invoke_target   varchar(500)    not null,
+  job_message     varchar(500)    default null,
+  status          char(1)         default '0',
+  exception_info  varchar(2000)   default '',
+  create_time     timestamp       default null,
+  log_content     bytea           default null,
+  primary key (job_log_id)
+);
+
+comment on table sys_job_log is '定时任务调度日志表';
+comment on column sys_job_log.job_log_id is '任务日志ID';
+comment on column sys_job_log.job_name is '任务名称';
+comment on column sys_job_log.job_group is '任务组名';
+comment on column sys_job_log.invoke_target is '调用目标字符串';
+comment on column sys_job_log.job_message is '日志信息';
+comment on column sys_job_log.status is '执行状态（0正常 1失败 2执行中）';
+comment on column sys_job_log.exception_info is '异常信息';
+comment on column sys_job_log.create_time is '创建时间';
+comment on column sys_job_log.log_content is '完整执行日志（gzip压缩）';
+
+-- ----------------------------
+-- 17、通知公告表
+-- ----------------------------
+
+create table sys_notice (
+  notice_id       integer         generated by default as identity (start with 10),
+  notice_title    varchar(50)     not null,
+  notice_type     char(1)         not null,
+  notice_content  text            default null,
+  status          char(1)         default '0',
+  create_by       varchar(64)     default '',
+  create_time     timestamp       default null,
+  update_by       varchar(64)     default '',
+  update_time     timestamp       default null,
+  remark          varchar(255)    default null,
+  primary key (notice_id)
+);
+
+comment on table sys_notice is '通知公告表';
+comment on column sys_notice.notice_id is '公告ID';
+comment on column sys_notice.notice_title is '公告标题';
+comment on column sys_notice.notice_type is '公告类型（1通知 2公告）';
+comment on column sys_notice.notice_content is '公告内容';
+comment on column sys_notice.status is '公告状态（0正常 1关闭）';
+comment on column sys_notice.create_by is '创建者';
+comment on column sys_notice.create_time is '创建时间';
+comment on column sys_notice.update_by is '更新者';
+comment on column sys_notice.update_time is '更新时间';
+comment on column sys_notice.remark is '备注';
+
+-- ----------------------------
+-- 初始化-公告信息表数据
+-- ----------------------------
+insert into sys_notice (notice_id, notice_title, notice_type, notice_content, status, create_by, create_time, update_by, update_time, remark) values (1, '温馨提醒：2018-07-01 若依新版本发布啦', '2', '新版本内容', '0', 'admin', now(), '', NULL, '管理员');
+insert into sys_notice (notice_id, notice_title, notice_type, notice_content, status, create_by, create_time, update_by, update_time, remark) values (2, '维护通知：2018-07-01 若依系统凌晨维护', '1', '维护内容', '0', 'admin', now(), '', NULL, '管理员');
+select setval(pg_get_serial_sequence('sys_notice', 'notice_id'), greatest(10, (select max(notice_id) + 1 from sys_notice)), false);
+
+-- ----------------------------
+-- 18、代码生成业务表
+-- ----------------------------
+
+create table gen_table (
+  table_id           bigint          generated by default as identity,
+  table_name         varchar(200)    default '',
+  table_comment      varchar(500)    default '',
+  sub_table_name     varchar(64)     default null,
+  sub_table_fk_name  varchar(64)     default null,
+  class_name         varchar(100)    default '',
+  tpl_category       varchar(200)    default 'crud',
+  tpl_web_type       varchar(30)     default '',
+  package_name       varchar(100)    default null,
+  module_name        varchar(30)     default null,
+  business_name      varchar(30)     default null,
+  function_name      varchar(50)     default null,
+  function_author    varchar(50)     default null,
+  gen_type           char(1)         default '0',
+  gen_path           varchar(200)    default '/',
+  options            varchar(1000)   default null,
+  create_by          varchar(64)     default '',
+  create_time        timestamp       default null,
+  update_by          varchar(64)     default '',
+  update_time        timestamp       default null,
+  remark             varchar(500)    default null,
+  primary key (table_id)
+);
+
+comment on table gen_table is '代码生成业务表';
+comment on column gen_table.table_id is '编号';
+comment on column gen_table.table_name is '表名称';
+comment on column gen_table.table_comment is '表描述';
+comment on column gen_table.sub_table_name is '关联子表的表名';
+comment on column gen_table.sub_table_fk_name is '子表关联的外键名';
+comment on column gen_table.class_name is '实体类名称';
+comment on column gen_table.tpl_category is '使用的模板（crud单表操作 tree树表操作）';
+comment on column gen_table.tpl_web_type is '前端模板类型（element-ui模版 element-plus模版）';
+comment on column gen_table.package_name is '生成包路径';
+comment on column gen_table.module_name is '生成模块名';
+comment on column gen_table.business_name is '生成业务名';
+comment on column gen_table.function_name is '生成功能名';
+comment on column gen_table.function_author is '生成功能作者';
+comment on column gen_table.gen_type is '生成代码方式（0zip压缩包 1自定义路径）';
+comment on column gen_table.gen_path is '生成路径（不填默认项目路径）';
+comment on column gen_table.options is '其它生成选项';
+comment on column gen_table.create_by is '创建者';
+comment on column gen_table.create_time is '创建时间';
+comment on column gen_table.update_by is '更新者';
+comment on column gen_table.update_time is '更新时间';
+comment on column gen_table.remark is '备注';
+
+-- ----------------------------
+-- 19、代码生成业务表字段
+-- ----------------------------
+
+create table gen_table_column (
+  column_id       bigint          generated by default as identity,
+  table_id        bigint          default null,
+  column_name     varchar(200)    default null,
+  column_comment  varchar(500)    default null,
+  column_type     varchar(100)    default null,
+  java_type       varchar(500)    default null,
+  java_field      varchar(200)    default null,
+  is_pk           char(1)         default null,
+  is_increment    char(1)         default null,
+  is_required     char(1)         default null,
+  is_insert       char(1)         default null,
+  is_edit         char(1)         default null,
+  is_list         char(1)         default null,
+  is_query        char(1)         default null,
+  query_type      varchar(200)    default 'EQ',
+  html_type       varchar(200)    default null,
+  dict_type       varchar(200)    default '',
+  sort            integer         default null,
+  create_by       varchar(64)     default '',
+  create_time     timestamp       default null,
+  update_by       varchar(64)     default '',
+  update_time     timestamp       default null,
+  primary key (column_id)
+);
+
+comment on table gen_table_column is '代码生成业务表字段';
+comment on column gen_table_column.column_id is '编号';
+comment on column gen_table_column.table_id is '归属表编号';
+comment on column gen_table_column.column_name is '列名称';
+comment on column gen_table_column.column_comment is '列描述';
+comment on column gen_table_column.column_type is '列类型';
+comment on column gen_table_column.java_type is 'JAVA类型';
+comment on column gen_table_column.java_field is 'JAVA字段名';
+comment on column gen_table_column.is_pk is '是否主键（1是）';
+comment on column gen_table_column.is_increment is '是否自增（1是）';
+comment on column gen_table_column.is_required is '是否必填（1是）';
+comment on column gen_table_column.is_insert is '是否为插入字段（1是）';
+comment on column gen_table_column.is_edit is '是否编辑字段（1是）';
+comment on column gen_table_column.is_list is '是否列表字段（1是）';
+comment on column gen_table_column.is_query is '是否查询字段（1是）';
+comment on column gen_table_column.query_type is '查询方式（等于、不等于、大于、小于、范围）';
+comment on column gen_table_column.html_type is '显示类型（文本框、文本域、下拉框、复选框、单选框、日期控件）';
+comment on column gen_table_column.dict_type is '字典类型';
+comment on column gen_table_column.sort is '排序';
+comment on column gen_table_column.create_by is '创建者';
+comment on column gen_table_column.create_time is '创建时间';
+comment on column gen_table_column.update_by is '更新者';
+comment on column gen_table_column.update_time is '更新时间';
+
+-- ----------------------------
+-- 20、定时任务依赖关系表
+-- ----------------------------
+
+create table sys_job_dependency (
+  dependency_id  bigint          generated by default as identity,
+  job_id         bigint          not null,
+  parent_job_id  bigint          not null,
+  trigger_type   char(1)         default '0',
+  create_by      varchar(64)     default '',
+  create_time    timestamp       default null,
+  primary key (dependency_id)
+);
+
+comment on table sys_job_dependency is '定时任务依赖关系表';
+comment on column sys_job_dependency.dependency_id is '依赖ID';
+comment on column sys_job_dependency.job_id is '下游任务ID';
+comment on column sys_job_dependency.parent_job_id is '上游任务ID';
+comment on column sys_job_dependency.trigger_type is '触发条件（0成功时 1失败时 2总是）';
+comment on column sys_job_dependency.create_by is '创建者';
+comment on column sys_job_dependency.create_time is '创建时间';
+
+-- 添加索引
+create unique index uk_sys_job_dependency on sys_job_dependency (job_id, parent_job_id);
+create index idx_sys_job_dependency_p on sys_job_dependency (parent_job_id);
+
+-- ----------------------------
+-- 21、定时任务排除日历表
+-- ----------------------------
+
+create table sys_job_calendar (
+  calendar_id    bigint          generated by default as identity,
+  calendar_name  varchar(64)     not null,
+  timezone       varchar(64)     default '',
+  status         char(1)         default '0',
+  create_by      varchar(64)     default '',
+  create_time    timestamp       default null,
+  update_by      varchar(64)     default '',
+  update_time    timestamp       default null,
+  remark         varchar(500)    default '',
+  primary key (calendar_id)
+);
+
+comment on table sys_job_calendar is '定时任务排除日历表';
+comment on column sys_job_calendar.calendar_id is '日历ID';
+comment on column sys_job_calendar.calendar_name is '日历名称';
+comment on column sys_job_calendar.timezone is '时区（为空时使用服务器时区）';
+comment on column sys_job_calendar.status is '状态（0正常 1停用）';
+comment on column sys_job_calendar.create_by is '创建者';
+comment on column sys_job_calendar.create_time is '创建时间';
+comment on column sys_job_calendar.update_by is '更新者';
+comment on column sys_job_calendar.update_time is '更新时间';
+comment on column sys_job_calendar.remark is '备注';
+
+create unique index uk_sys_job_calendar_name on sys_job_calendar (calendar_name);
+
+-- ----------------------------
+-- 22、定时任务排除日历规则表
+-- ----------------------------
+
+create table sys_job_calendar_rule (
+  rule_id      bigint          generated by default as identity,
+  calendar_id  bigint          not null,
+  rule_type    char(1)         not null,
+  start_time   timestamp       default null,
+  end_time     timestamp       default null,
+  weekdays     varchar(20)     default '',
+  summary      varchar(200)    default '',
+  primary key (rule_id)
+);
+
+comment on table sys_job_calendar_rule is '定时任务排除日历规则表';
+comment on column sys_job_calendar_rule.rule_id is '规则ID';
+comment on column sys_job_calendar_rule.calendar_id is '日历ID';
+comment on column sys_job_calendar_rule.rule_type is '规则类型（0日期 1每周 2时间段）';
+comment on column sys_job_calendar_rule.start_time is '日期或时间段开始时间';
+comment on column sys_job_calendar_rule.end_time is '时间段结束时间（不包含）';
+comment on column sys_job_calendar_rule.weekdays is '每周排除的星期（1周日 ... 7周六）';
+comment on column sys_job_calendar_rule.summary is '说明';
+
+create index idx_sys_job_calendar_rule_c on sys_job_calendar_rule (calendar_id);
+
+-- ----------------------------
+-- 23、代码生成模板组表
+-- ----------------------------
+
+create table gen_template_set (
+  set_id       bigint          generated by default as identity,
+  set_name     varchar(64)     not null,
+  status       char(1)         default '0',
+  create_by    varchar(64)     default '',
+  create_time  timestamp       default null,
+  update_by    varchar(64)     default '',
+  update_time  timestamp       default null,
+  remark       varchar(500)    default '',
+  primary key (set_id)
+);
+
+comment on table gen_template_set is '代码生成模板组表';
+comment on column gen_template_set.set_id is '模板组ID';
+comment on column gen_template_set.set_name is '模板组名称';
+comment on column gen_template_set.status is '状态（0正常 1停用）';
+comment on column gen_template_set.create_by is '创建者';
+comment on column gen_template_set.create_time is '创建时间';
+comment on column gen_template_set.update_by is '更新者';
+comment on column gen_template_set.update_time is '更新时间';
+comment on column gen_template_set.remark is '备注';
+
+create unique index uk_gen_template_set_name on gen_template_set (set_name);
+
+-- ----------------------------
+-- 24、代码生成模板表
+-- ----------------------------
+
+create table gen_template (
+  template_id    bigint          generated by default as identity,
+  set_id         bigint          not null,
+  template_name  varchar(64)     not null,
+  content        text            not null,
+  version        integer         default 1,
+  create_by      varchar(64)     default '',
+  create_time    timestamp       default null,
+  update_by      varchar(64)     default '',
+  update_time    timestamp       default null,
+  primary key (template_id)
+);
+
+comment on table gen_template is '代码生成模板表';
+comment on column gen_template.template_id is '模板ID';
+comment on column gen_template.set_id is '模板组ID';
+comment on column gen_template.template_name is '模板名称（与内置模板名一致）';
+comment on column gen_template.content is '模板内容';
+comment on column gen_template.version is '当前版本号';
+comment on column gen_template.create_by is '创建者';
+comment on column gen_template.create_time is '创建时间';
+comment on column gen_template.update_by is '更新者';
+comment on column gen_template.update_time is '更新时间';
+
+create unique index uk_gen_template_name on gen_template (set_id, template_name);
+
+-- ----------------------------
+-- 25、代码生成模板历史版本表
+-- ----------------------------
+
+create table gen_template_history (
+  history_id   bigint          generated by default as identity,
+  template_id  bigint          not null,
+  version      integer         not null,
+  content      text            not null,
+  create_by    varchar(64)     default '',
+  create_time  timestamp       default null,
+  primary key (history_id)
+);
+
+comment on table gen_template_history is '代码生成模板历史版本表';
+comment on column gen_template_history.history_id is '历史ID';
+comment on column gen_template_history.template_id is '模板ID';
+comment on column gen_template_history.version is '版本号';
+comment on column gen_template_history.content is '模板内容';
+comment on column gen_template_history.create_by is '保存者';
+comment on column gen_template_history.create_time is '保存时间';
+
+create unique index uk_gen_template_history_v on gen_template_history (template_id, version);
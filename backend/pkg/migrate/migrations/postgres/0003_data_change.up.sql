@@ -0,0 +1,45 @@
+-- 数据变更历史：记录用户、角色、部门、菜单、参数和字典数据修改前后的字段值
+
+-- ----------------------------
+-- 1、数据变更记录表
+-- ----------------------------
+
+create table sys_data_change (
+  change_id    bigint          generated by default as identity,
+  entity       varchar(50)     not null,
+  entity_id    bigint          not null,
+  field_name   varchar(100)    not null,
+  old_value    text            default null,
+  new_value    text            default null,
+  oper_id      bigint          default 0,
+  request_id   varchar(64)     default '',
+  oper_name    varchar(64)     default '',
+  change_time  timestamp       default null,
+  primary key (change_id)
+);
+
+comment on table sys_data_change is '数据变更记录表';
+comment on column sys_data_change.change_id is '变更ID';
+comment on column sys_data_change.entity is '实体类型（user role dept menu config dict_data）';
+comment on column sys_data_change.entity_id is '实体ID';
+comment on column sys_data_change.field_name is '字段名';
+comment on column sys_data_change.old_value is '修改前的值';
+comment on column sys_data_change.new_value is '修改后的值';
+comment on column sys_data_change.oper_id is '操作日志ID';
+comment on column sys_data_change.request_id is '请求ID';
+comment on column sys_data_change.oper_name is '操作人员';
+comment on column sys_data_change.change_time is '变更时间';
+
+create index idx_sys_data_change_entity on sys_data_change (entity, entity_id, change_time);
+create index idx_sys_data_change_request on sys_data_change (request_id);
+
+-- ----------------------------
+-- 2、操作日志关联请求ID
+-- ----------------------------
+alter table sys_oper_log add column request_id varchar(64) default '';
+comment on column sys_oper_log.request_id is '请求ID';
+
+-- ----------------------------
+-- 3、变更历史按钮
+-- ----------------------------
+insert into sys_menu (menu_id, menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark) values (1063, '变更历史', 500, 4, '#', '', '', '', 1, 0, 'F', '0', '0', 'monitor:history:list', '#', 'admin', now(), '', NULL, '');
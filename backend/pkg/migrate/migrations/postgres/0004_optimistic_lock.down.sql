@@ -0,0 +1,8 @@
+-- 删除乐观锁版本号
+
+alter table sys_user drop column version;
+alter table sys_role drop column version;
+alter table sys_dept drop column version;
+alter table sys_menu drop column version;
+alter table sys_config drop column version;
+alter table sys_dict_data drop column version;
@@ -0,0 +1,21 @@
+-- 删除多租户
+
+delete from sys_role_menu where menu_id in (select menu_id from sys_menu where perms in ('system:tenant:list', 'system:tenantPackage:list', 'system:tenant:query', 'system:tenant:add', 'system:tenant:edit', 'system:tenant:remove', 'system:tenantPackage:query', 'system:tenantPackage:add', 'system:tenantPackage:edit', 'system:tenantPackage:remove'));
+delete from sys_menu where perms in ('system:tenant:list', 'system:tenantPackage:list', 'system:tenant:query', 'system:tenant:add', 'system:tenant:edit', 'system:tenant:remove', 'system:tenantPackage:query', 'system:tenantPackage:add', 'system:tenantPackage:edit', 'system:tenantPackage:remove');
+drop index if exists idx_sys_user_tenant;
+drop index if exists idx_sys_config_tenant;
+drop index if exists idx_sys_dict_data_tenant;
+alter table sys_user drop column tenant_id;
+alter table sys_dept drop column tenant_id;
+alter table sys_role drop column tenant_id;
+alter table sys_post drop column tenant_id;
+alter table sys_config drop column tenant_id;
+alter table sys_dict_data drop column tenant_id;
+alter table sys_notice drop column tenant_id;
+alter table sys_oper_log drop column tenant_id;
+alter table sys_logininfor drop column tenant_id;
+alter table sys_recycle drop column tenant_id;
+alter table sys_data_change drop column tenant_id;
+drop table if exists sys_tenant_package_menu;
+drop table if exists sys_tenant_package;
+drop table if exists sys_tenant;
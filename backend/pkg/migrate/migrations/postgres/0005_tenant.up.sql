@@ -0,0 +1,144 @@
+-- 多租户：租户和租户套餐表，租户隔离的业务表增加租户ID，已有数据属于平台租户
+
+-- ----------------------------
+-- 1、租户表
+-- ----------------------------
+
+create table sys_tenant (
+  tenant_id          bigint          generated by default as identity,
+  tenant_name        varchar(50)     not null,
+  domain             varchar(100)    default '',
+  package_id         bigint          default 0,
+  contact_user_name  varchar(30)     default '',
+  contact_phone      varchar(20)     default '',
+  expire_time        timestamp       default null,
+  status             char(1)         default '0',
+  del_flag           char(1)         default '0',
+  create_by          varchar(64)     default '',
+  create_time        timestamp       default null,
+  update_by          varchar(64)     default '',
+  update_time        timestamp       default null,
+  remark             varchar(500)    default null,
+  primary key (tenant_id)
+);
+
+comment on table sys_tenant is '租户表';
+comment on column sys_tenant.tenant_id is '租户ID';
+comment on column sys_tenant.tenant_name is '租户名称';
+comment on column sys_tenant.domain is '绑定域名';
+comment on column sys_tenant.package_id is '租户套餐ID（0不限制菜单）';
+comment on column sys_tenant.contact_user_name is '联系人';
+comment on column sys_tenant.contact_phone is '联系电话';
+comment on column sys_tenant.expire_time is '过期时间';
+comment on column sys_tenant.status is '租户状态（0正常 1停用）';
+comment on column sys_tenant.del_flag is '删除标志（0代表存在 2代表删除）';
+comment on column sys_tenant.create_by is '创建者';
+comment on column sys_tenant.create_time is '创建时间';
+comment on column sys_tenant.update_by is '更新者';
+comment on column sys_tenant.update_time is '更新时间';
+comment on column sys_tenant.remark is '备注';
+
+insert into sys_tenant (tenant_id, tenant_name, domain, package_id, status, del_flag, create_by, create_time, remark) values (1, '平台租户', '', 0, '0', '0', 'admin', now(), '平台租户，未开启租户模式时所有数据属于该租户');
+select setval(pg_get_serial_sequence('sys_tenant', 'tenant_id'), greatest(100, (select max(tenant_id) + 1 from sys_tenant)), false);
+
+-- ----------------------------
+-- 2、租户套餐表和套餐菜单关联表
+-- ----------------------------
+
+create table sys_tenant_package (
+  package_id         bigint          generated by default as identity,
+  package_name       varchar(50)     not null,
+  status             char(1)         default '0',
+  del_flag           char(1)         default '0',
+  create_by          varchar(64)     default '',
+  create_time        timestamp       default null,
+  update_by          varchar(64)     default '',
+  update_time        timestamp       default null,
+  remark             varchar(500)    default null,
+  primary key (package_id)
+);
+
+comment on table sys_tenant_package is '租户套餐表';
+comment on column sys_tenant_package.package_id is '套餐ID';
+comment on column sys_tenant_package.package_name is '套餐名称';
+comment on column sys_tenant_package.status is '套餐状态（0正常 1停用）';
+comment on column sys_tenant_package.del_flag is '删除标志（0代表存在 2代表删除）';
+comment on column sys_tenant_package.create_by is '创建者';
+comment on column sys_tenant_package.create_time is '创建时间';
+comment on column sys_tenant_package.update_by is '更新者';
+comment on column sys_tenant_package.update_time is '更新时间';
+comment on column sys_tenant_package.remark is '备注';
+
+create table sys_tenant_package_menu (
+  package_id         bigint          not null,
+  menu_id            bigint          not null,
+  primary key (package_id, menu_id)
+);
+
+comment on table sys_tenant_package_menu is '租户套餐和菜单关联表';
+comment on column sys_tenant_package_menu.package_id is '套餐ID';
+comment on column sys_tenant_package_menu.menu_id is '菜单ID';
+
+-- ----------------------------
+-- 3、租户隔离的业务表增加租户ID
+-- ----------------------------
+alter table sys_user add column tenant_id bigint not null default 1;
+comment on column sys_user.tenant_id is '租户ID';
+alter table sys_dept add column tenant_id bigint not null default 1;
+comment on column sys_dept.tenant_id is '租户ID';
+alter table sys_role add column tenant_id bigint not null default 1;
+comment on column sys_role.tenant_id is '租户ID';
+alter table sys_post add column tenant_id bigint not null default 1;
+comment on column sys_post.tenant_id is '租户ID';
+alter table sys_config add column tenant_id bigint not null default 1;
+comment on column sys_config.tenant_id is '租户ID';
+alter table sys_dict_data add column tenant_id bigint not null default 1;
+comment on column sys_dict_data.tenant_id is '租户ID';
+alter table sys_notice add column tenant_id bigint not null default 1;
+comment on column sys_notice.tenant_id is '租户ID';
+alter table sys_oper_log add column tenant_id bigint not null default 1;
+comment on column sys_oper_log.tenant_id is '租户ID';
+alter table sys_logininfor add column tenant_id bigint not null default 1;
+comment on column sys_logininfor.tenant_id is '租户ID';
+alter table sys_recycle add column tenant_id bigint not null default 1;
+comment on column sys_recycle.tenant_id is '租户ID';
+alter table sys_data_change add column tenant_id bigint not null default 1;
+comment on column sys_data_change.tenant_id is '租户ID';
+
+create index idx_sys_user_tenant on sys_user (tenant_id, user_name);
+create index idx_sys_config_tenant on sys_config (tenant_id, config_key);
+create index idx_sys_dict_data_tenant on sys_dict_data (tenant_id, dict_type);
+
+-- ----------------------------
+-- 4、租户管理菜单（不指定ID，由数据库生成；已存在相同权限标识的菜单时跳过）
+-- ----------------------------
+insert into sys_menu (menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark)
+select '租户管理', 1, 11, 'tenant', 'system/tenant/index', '', '', 1, 0, 'C', '0', '0', 'system:tenant:list', 'peoples', 'admin', now(), '', NULL, '租户管理菜单'
+where not exists (select 1 from sys_menu where perms = 'system:tenant:list');
+insert into sys_menu (menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark)
+select '租户套餐', 1, 12, 'tenantPackage', 'system/tenantPackage/index', '', '', 1, 0, 'C', '0', '0', 'system:tenantPackage:list', 'shopping', 'admin', now(), '', NULL, '租户套餐菜单'
+where not exists (select 1 from sys_menu where perms = 'system:tenantPackage:list');
+insert into sys_menu (menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark)
+select '租户查询', m.menu_id, 1, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:tenant:query', '#', 'admin', now(), '', NULL, '' from sys_menu m
+where m.perms = 'system:tenant:list' and not exists (select 1 from sys_menu where perms = 'system:tenant:query');
+insert into sys_menu (menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark)
+select '租户新增', m.menu_id, 2, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:tenant:add', '#', 'admin', now(), '', NULL, '' from sys_menu m
+where m.perms = 'system:tenant:list' and not exists (select 1 from sys_menu where perms = 'system:tenant:add');
+insert into sys_menu (menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark)
+select '租户修改', m.menu_id, 3, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:tenant:edit', '#', 'admin', now(), '', NULL, '' from sys_menu m
+where m.perms = 'system:tenant:list' and not exists (select 1 from sys_menu where perms = 'system:tenant:edit');
+insert into sys_menu (menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark)
+select '租户删除', m.menu_id, 4, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:tenant:remove', '#', 'admin', now(), '', NULL, '' from sys_menu m
+where m.perms = 'system:tenant:list' and not exists (select 1 from sys_menu where perms = 'system:tenant:remove');
+insert into sys_menu (menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark)
+select '套餐查询', m.menu_id, 1, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:tenantPackage:query', '#', 'admin', now(), '', NULL, '' from sys_menu m
+where m.perms = 'system:tenantPackage:list' and not exists (select 1 from sys_menu where perms = 'system:tenantPackage:query');
+insert into sys_menu (menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark)
+select '套餐新增', m.menu_id, 2, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:tenantPackage:add', '#', 'admin', now(), '', NULL, '' from sys_menu m
+where m.perms = 'system:tenantPackage:list' and not exists (select 1 from sys_menu where perms = 'system:tenantPackage:add');
+insert into sys_menu (menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark)
+select '套餐修改', m.menu_id, 3, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:tenantPackage:edit', '#', 'admin', now(), '', NULL, '' from sys_menu m
+where m.perms = 'system:tenantPackage:list' and not exists (select 1 from sys_menu where perms = 'system:tenantPackage:edit');
+insert into sys_menu (menu_name, parent_id, order_num, path, component, query, route_name, is_frame, is_cache, menu_type, visible, status, perms, icon, create_by, create_time, update_by, update_time, remark)
+select '套餐删除', m.menu_id, 4, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:tenantPackage:remove', '#', 'admin', now(), '', NULL, '' from sys_menu m
+where m.perms = 'system:tenantPackage:list' and not exists (select 1 from sys_menu where perms = 'system:tenantPackage:remove');
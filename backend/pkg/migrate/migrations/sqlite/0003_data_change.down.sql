@@ -0,0 +1,6 @@
+-- 删除数据变更历史
+
+delete from sys_role_menu where menu_id = 1063;
+delete from sys_menu where menu_id = 1063;
+alter table sys_oper_log drop column request_id;
+drop table if exists sys_data_change;
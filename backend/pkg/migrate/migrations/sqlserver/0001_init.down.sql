@@ -0,0 +1,52 @@
+-- 删除初始表结构
+
+IF OBJECT_ID(N'[dbo].[gen_template_history]', N'U') IS NOT NULL DROP TABLE [dbo].[gen_template_history]
+GO
+IF OBJECT_ID(N'[dbo].[gen_template]', N'U') IS NOT NULL DROP TABLE [dbo].[gen_template]
+GO
+IF OBJECT_ID(N'[dbo].[gen_template_set]', N'U') IS NOT NULL DROP TABLE [dbo].[gen_template_set]
+GO
+IF OBJECT_ID(N'[dbo].[sys_job_calendar_rule]', N'U') IS NOT NULL DROP TABLE [dbo].[sys_job_calendar_rule]
+GO
+IF OBJECT_ID(N'[dbo].[sys_job_calendar]', N'U') IS NOT NULL DROP TABLE [dbo].[sys_job_calendar]
+GO
+IF OBJECT_ID(N'[dbo].[sys_job_dependency]', N'U') IS NOT NULL DROP TABLE [dbo].[sys_job_dependency]
+GO
+IF OBJECT_ID(N'[dbo].[gen_table_column]', N'U') IS NOT NULL DROP TABLE [dbo].[gen_table_column]
+GO
+IF OBJECT_ID(N'[dbo].[gen_table]', N'U') IS NOT NULL DROP TABLE [dbo].[gen_table]
+GO
+IF OBJECT_ID(N'[dbo].[sys_notice]', N'U') IS NOT NULL DROP TABLE [dbo].[sys_notice]
+GO
+IF OBJECT_ID(N'[dbo].[sys_job_log]', N'U') IS NOT NULL DROP TABLE [dbo].[sys_job_log]
+GO
+IF OBJECT_ID(N'[dbo].[sys_job]', N'U') IS NOT NULL DROP TABLE [dbo].[sys_job]
+GO
+IF OBJECT_ID(N'[dbo].[sys_logininfor]', N'U') IS NOT NULL DROP TABLE [dbo].[sys_logininfor]
+GO
+IF OBJECT_ID(N'[dbo].[sys_config]', N'U') IS NOT NULL DROP TABLE [dbo].[sys_config]
+GO
+IF OBJECT_ID(N'[dbo].[sys_dict_data]', N'U') IS NOT NULL DROP TABLE [dbo].[sys_dict_data]
+GO
+IF OBJECT_ID(N'[dbo].[sys_dict_type]', N'U') IS NOT NULL DROP TABLE [dbo].[sys_dict_type]
+GO
+IF OBJECT_ID(N'[dbo].[sys_oper_log]', N'U') IS NOT NULL DROP TABLE [dbo].[sys_oper_log]
+GO
+IF OBJECT_ID(N'[dbo].[sys_user_post]', N'U') IS NOT NULL DROP TABLE [dbo].[sys_user_post]
+GO
+IF OBJECT_ID(N'[dbo].[sys_role_dept]', N'U') IS NOT NULL DROP TABLE [dbo].[sys_role_dept]
+GO
+IF OBJECT_ID(N'[dbo].[sys_role_menu]', N'U') IS NOT NULL DROP TABLE [dbo].[sys_role_menu]
+GO
+IF OBJECT_ID(N'[dbo].[sys_user_role]', N'U') IS NOT NULL DROP TABLE [dbo].[sys_user_role]
+GO
+IF OBJECT_ID(N'[dbo].[sys_menu]', N'U') IS NOT NULL DROP TABLE [dbo].[sys_menu]
+GO
+IF OBJECT_ID(N'[dbo].[sys_role]', N'U') IS NOT NULL DROP TABLE [dbo].[sys_role]
+GO
+IF OBJECT_ID(N'[dbo].[sys_post]', N'U') IS NOT NULL DROP TABLE [dbo].[sys_post]
+GO
+IF OBJECT_ID(N'[dbo].[sys_user]', N'U') IS NOT NULL DROP TABLE [dbo].[sys_user]
+GO
+IF OBJECT_ID(N'[dbo].[sys_dept]', N'U') IS NOT NULL DROP TABLE [dbo].[sys_dept]
+GO
@@ -1,20 +1,15 @@
+-- 初始表结构和数据，与 sql/SqlServer_ry_20250522_COMPLETE.sql 一致（去掉了删表语句）
+-- 已经用该脚本建好的库执行 migrate baseline 1 标记为已迁移
+
 -- ===========================================================================================
 -- SQL Server 2012 完整版本 - 基于Java后端 ruoyi-java/sql/ry_20250522.sql 精确转换
 -- 转换日期: 2025-06-17
 -- 说明: 完全按照MySQL原文件逐行转换，确保数据100%准确
 -- ===========================================================================================
 
--- 设置数据库
-USE [wosm]
-GO
-
 -- ----------------------------
 -- 1、部门表
 -- ----------------------------
-IF EXISTS (SELECT * FROM sys.objects WHERE object_id = OBJECT_ID(N'[dbo].[sys_dept]') AND type in (N'U'))
-DROP TABLE [dbo].[sys_dept]
-GO
-
 CREATE TABLE [dbo].[sys_dept] (
   [dept_id]           BIGINT          IDENTITY(200,1) NOT NULL,    -- 部门id
   [parent_id]         BIGINT          DEFAULT 0,                  -- 父部门id
@@ -57,10 +52,6 @@ GO
 -- ----------------------------
 -- 2、用户信息表
 -- ----------------------------
-IF EXISTS (SELECT * FROM sys.objects WHERE object_id = OBJECT_ID(N'[dbo].[sys_user]') AND type in (N'U'))
-DROP TABLE [dbo].[sys_user]
-GO
-
 CREATE TABLE [dbo].[sys_user] (
   [user_id]           BIGINT          IDENTITY(100,1) NOT NULL,   -- 用户ID
   [dept_id]           BIGINT          DEFAULT NULL,               -- 部门ID
@@ -101,10 +92,6 @@ GO
 -- ----------------------------
 -- 3、岗位信息表
 -- ----------------------------
-IF EXISTS (SELECT * FROM sys.objects WHERE object_id = OBJECT_ID(N'[dbo].[sys_post]') AND type in (N'U'))
-DROP TABLE [dbo].[sys_post]
-GO
-
 CREATE TABLE [dbo].[sys_post] (
   [post_id]       BIGINT          IDENTITY(1,1) NOT NULL,        -- 岗位ID
   [post_code]     NVARCHAR(64)    NOT NULL,                      -- 岗位编码
@@ -133,10 +120,6 @@ GO
 -- ----------------------------
 -- 4、角色信息表
 -- ----------------------------
-IF EXISTS (SELECT * FROM sys.objects WHERE object_id = OBJECT_ID(N'[dbo].[sys_role]') AND type in (N'U'))
-DROP TABLE [dbo].[sys_role]
-GO
-
 CREATE TABLE [dbo].[sys_role] (
   [role_id]              BIGINT          IDENTITY(100,1) NOT NULL,  -- 角色ID
   [role_name]            NVARCHAR(30)    NOT NULL,                  -- 角色名称
@@ -171,10 +154,6 @@ GO
 -- ----------------------------
 -- 5、菜单权限表
 -- ----------------------------
-IF EXISTS (SELECT * FROM sys.objects WHERE object_id = OBJECT_ID(N'[dbo].[sys_menu]') AND type in (N'U'))
-DROP TABLE [dbo].[sys_menu]
-GO
-
 CREATE TABLE [dbo].[sys_menu] (
   [menu_id]           BIGINT          IDENTITY(2000,1) NOT NULL,  -- 菜单ID
   [menu_name]         NVARCHAR(50)    NOT NULL,                   -- 菜单名称
@@ -326,10 +305,6 @@ GO
 -- ----------------------------
 -- 6、用户和角色关联表  用户N-1角色
 -- ----------------------------
-IF EXISTS (SELECT * FROM sys.objects WHERE object_id = OBJECT_ID(N'[dbo].[sys_user_role]') AND type in (N'U'))
-DROP TABLE [dbo].[sys_user_role]
-GO
-
 CREATE TABLE [dbo].[sys_user_role] (
   [user_id]   BIGINT NOT NULL,    -- 用户ID
   [role_id]   BIGINT NOT NULL,    -- 角色ID
@@ -348,10 +323,6 @@ GO
 -- ----------------------------
 -- 7、角色和菜单关联表  角色1-N菜单
 -- ----------------------------
-IF EXISTS (SELECT * FROM sys.objects WHERE object_id = OBJECT_ID(N'[dbo].[sys_role_menu]') AND type in (N'U'))
-DROP TABLE [dbo].[sys_role_menu]
-GO
-
 CREATE TABLE [dbo].[sys_role_menu] (
   [role_id]   BIGINT NOT NULL,    -- 角色ID
   [menu_id]   BIGINT NOT NULL,    -- 菜单ID
@@ -375,10 +346,6 @@ GO
 -- ----------------------------
 -- 8、角色和部门关联表  角色1-N部门
 -- ----------------------------
-IF EXISTS (SELECT * FROM sys.objects WHERE object_id = OBJECT_ID(N'[dbo].[sys_role_dept]') AND type in (N'U'))
-DROP TABLE [dbo].[sys_role_dept]
-GO
-
 CREATE TABLE [dbo].[sys_role_dept] (
   [role_id]   BIGINT NOT NULL,    -- 角色ID
   [dept_id]   BIGINT NOT NULL,    -- 部门ID
@@ -396,10 +363,6 @@ GO
 -- ----------------------------
 -- 9、用户与岗位关联表  用户1-N岗位
 -- ----------------------------
-IF EXISTS (SELECT * FROM sys.objects WHERE object_id = OBJECT_ID(N'[dbo].[sys_user_post]') AND type in (N'U'))
-DROP TABLE [dbo].[sys_user_post]
-GO
-
 CREATE TABLE [dbo].[sys_user_post] (
   [user_id]   BIGINT NOT NULL,    -- 用户ID
   [post_id]   BIGINT NOT NULL,    -- 岗位ID
@@ -417,10 +380,6 @@ GO
 -- ----------------------------
 -- 10、操作日志记录
 -- ----------------------------
-IF EXISTS (SELECT * FROM sys.objects WHERE object_id = OBJECT_ID(N'[dbo].[sys_oper_log]') AND type in (N'U'))
-DROP TABLE [dbo].[sys_oper_log]
-GO
-
 CREATE TABLE [dbo].[sys_oper_log] (
   [oper_id]           BIGINT          IDENTITY(100,1) NOT NULL,   -- 日志主键
   [title]             NVARCHAR(50)    DEFAULT '',                 -- 模块标题
@@ -452,10 +411,6 @@ GO
 -- ----------------------------
 -- 11、字典类型表
 -- ----------------------------
-IF EXISTS (SELECT * FROM sys.objects WHERE object_id = OBJECT_ID(N'[dbo].[sys_dict_type]') AND type in (N'U'))
-DROP TABLE [dbo].[sys_dict_type]
-GO
-
 CREATE TABLE [dbo].[sys_dict_type] (
   [dict_id]          BIGINT          IDENTITY(100,1) NOT NULL,   -- 字典主键
   [dict_name]        NVARCHAR(100)   DEFAULT '',                 -- 字典名称
@@ -494,10 +449,6 @@ GO
 -- ----------------------------
 -- 12、字典数据表
 -- ----------------------------
-IF EXISTS (SELECT * FROM sys.objects WHERE object_id = OBJECT_ID(N'[dbo].[sys_dict_data]') AND type in (N'U'))
-DROP TABLE [dbo].[sys_dict_data]
-GO
-
 CREATE TABLE [dbo].[sys_dict_data] (
   [dict_code]        BIGINT          IDENTITY(100,1) NOT NULL,   -- 字典编码
   [dict_sort]        INT             DEFAULT 0,                  -- 字典排序
@@ -559,10 +510,6 @@ GO
 -- ----------------------------
 -- 13、参数配置表
 -- ----------------------------
-IF EXISTS (SELECT * FROM sys.objects WHERE object_id = OBJECT_ID(N'[dbo].[sys_config]') AND type in (N'U'))
-DROP TABLE [dbo].[sys_config]
-GO
-
 CREATE TABLE [dbo].[sys_config] (
   [config_id]         INT             IDENTITY(100,1) NOT NULL,   -- 参数主键
   [config_name]       NVARCHAR(100)   DEFAULT '',                 -- 参数名称
@@ -595,10 +542,6 @@ GO
 -- ----------------------------
 -- 14、系统访问记录
 -- ----------------------------
-IF EXISTS (SELECT * FROM sys.objects WHERE object_id = OBJECT_ID(N'[dbo].[sys_logininfor]') AND type in (N'U'))
-DROP TABLE [dbo].[sys_logininfor]
-GO
-
 CREATE TABLE [dbo].[sys_logininfor] (
   [info_id]        BIGINT          IDENTITY(100,1) NOT NULL,     -- 访问ID
   [user_name]      NVARCHAR(50)    DEFAULT '',                   -- 用户账号
@@ -621,10 +564,6 @@ GO
 -- ----------------------------
 -- 15、定时任务调度表
 -- ----------------------------
-IF EXISTS (SELECT * FROM sys.objects WHERE object_id = OBJECT_ID(N'[dbo].[sys_job]') AND type in (N'U'))
-DROP TABLE [dbo].[sys_job]
-GO
-
 CREATE TABLE [dbo].[sys_job] (
   [job_id]              BIGINT          IDENTITY(100,1) NOT NULL,  -- 任务ID
   [job_name]            NVARCHAR(64)    DEFAULT '',                -- 任务名称
@@ -639,6 +578,9 @@ CREATE TABLE [dbo].[sys_job] (
   [update_by]           NVARCHAR(64)    DEFAULT '',                -- 更新者
   [update_time]         DATETIME        DEFAULT NULL,              -- 更新时间
   [remark]              NVARCHAR(500)   DEFAULT '',                -- 备注信息
+  [job_type]            CHAR(1)         DEFAULT '0',               -- 任务类型（0调用目标 1HTTP请求 2SQL）
+  [job_config]          NVARCHAR(MAX)   DEFAULT NULL,              -- 任务类型配置（JSON）
+  [calendar_id]         BIGINT          DEFAULT 0,                 -- 排除日历ID（0表示不使用日历）
   PRIMARY KEY ([job_id], [job_name], [job_group])
 )
 GO
@@ -659,19 +601,16 @@ GO
 -- ----------------------------
 -- 16、定时任务调度日志表
 -- ----------------------------
-IF EXISTS (SELECT * FROM sys.objects WHERE object_id = OBJECT_ID(N'[dbo].[sys_job_log]') AND type in (N'U'))
-DROP TABLE [dbo].[sys_job_log]
-GO
-
 CREATE TABLE [dbo].[sys_job_log] (
   [job_log_id]          BIGINT          IDENTITY(1,1) NOT NULL,    -- 任务日志ID
   [job_name]            NVARCHAR(64)    NOT NULL,                  -- 任务名称
   [job_group]           NVARCHAR(64)    NOT NULL,                  -- 任务组名
   [invoke_target]       NVARCHAR(500)   NOT NULL,                  -- 调用目标字符串
   [job_message]         NVARCHAR(500)   DEFAULT NULL,              -- 日志信息
-  [status]              CHAR(1)         DEFAULT '0',               -- 执行状态（0正常 1失败）
+  [status]              CHAR(1)         DEFAULT '0',               -- 执行状态（0正常 1失败 2执行中）
   [exception_info]      NVARCHAR(2000)  DEFAULT '',                -- 异常信息
   [create_time]         DATETIME        DEFAULT NULL,              -- 创建时间
+  [log_content]         VARBINARY(MAX)  DEFAULT NULL,              -- 完整执行日志（gzip压缩）
   PRIMARY KEY ([job_log_id])
 )
 GO
@@ -679,10 +618,6 @@ GO
 -- ----------------------------
 -- 17、通知公告表
 -- ----------------------------
-IF EXISTS (SELECT * FROM sys.objects WHERE object_id = OBJECT_ID(N'[dbo].[sys_notice]') AND type in (N'U'))
-DROP TABLE [dbo].[sys_notice]
-GO
-
 CREATE TABLE [dbo].[sys_notice] (
   [notice_id]         INT             IDENTITY(10,1) NOT NULL,    -- 公告ID
   [notice_title]      NVARCHAR(50)    NOT NULL,                   -- 公告标题
@@ -713,10 +648,6 @@ GO
 -- ----------------------------
 -- 18、代码生成业务表
 -- ----------------------------
-IF EXISTS (SELECT * FROM sys.objects WHERE object_id = OBJECT_ID(N'[dbo].[gen_table]') AND type in (N'U'))
-DROP TABLE [dbo].[gen_table]
-GO
-
 CREATE TABLE [dbo].[gen_table] (
   [table_id]          BIGINT          IDENTITY(1,1) NOT NULL,     -- 编号
   [table_name]        NVARCHAR(200)   DEFAULT '',                 -- 表名称
@@ -746,10 +677,6 @@ GO
 -- ----------------------------
 -- 19、代码生成业务表字段
 -- ----------------------------
-IF EXISTS (SELECT * FROM sys.objects WHERE object_id = OBJECT_ID(N'[dbo].[gen_table_column]') AND type in (N'U'))
-DROP TABLE [dbo].[gen_table_column]
-GO
-
 CREATE TABLE [dbo].[gen_table_column] (
   [column_id]         BIGINT          IDENTITY(1,1) NOT NULL,     -- 编号
   [table_id]          BIGINT          DEFAULT NULL,               -- 归属表编号
@@ -777,6 +704,119 @@ CREATE TABLE [dbo].[gen_table_column] (
 )
 GO
 
+-- ----------------------------
+-- 20、定时任务依赖关系表
+-- ----------------------------
+CREATE TABLE [dbo].[sys_job_dependency] (
+  [dependency_id]       BIGINT          IDENTITY(1,1) NOT NULL,    -- 依赖ID
+  [job_id]              BIGINT          NOT NULL,                  -- 下游任务ID
+  [parent_job_id]       BIGINT          NOT NULL,                  -- 上游任务ID
+  [trigger_type]        CHAR(1)         DEFAULT '0',               -- 触发条件（0成功时 1失败时 2总是）
+  [create_by]           NVARCHAR(64)    DEFAULT '',                -- 创建者
+  [create_time]         DATETIME        DEFAULT NULL,              -- 创建时间
+  PRIMARY KEY ([dependency_id])
+)
+GO
+
+-- 添加索引
+CREATE UNIQUE INDEX [uk_sys_job_dependency] ON [dbo].[sys_job_dependency] ([job_id], [parent_job_id])
+CREATE INDEX [idx_sys_job_dependency_p] ON [dbo].[sys_job_dependency] ([parent_job_id])
+GO
+
+-- ----------------------------
+-- 21、定时任务排除日历表
+-- ----------------------------
+CREATE TABLE [dbo].[sys_job_calendar] (
+  [calendar_id]         BIGINT          IDENTITY(1,1) NOT NULL,    -- 日历ID
+  [calendar_name]       NVARCHAR(64)    NOT NULL,                  -- 日历名称
+  [timezone]            NVARCHAR(64)    DEFAULT '',                -- 时区（为空时使用服务器时区）
+  [status]              CHAR(1)         DEFAULT '0',               -- 状态（0正常 1停用）
+  [create_by]           NVARCHAR(64)    DEFAULT '',                -- 创建者
+  [create_time]         DATETIME        DEFAULT NULL,              -- 创建时间
+  [update_by]           NVARCHAR(64)    DEFAULT '',                -- 更新者
+  [update_time]         DATETIME        DEFAULT NULL,              -- 更新时间
+  [remark]              NVARCHAR(500)   DEFAULT '',                -- 备注
+  PRIMARY KEY ([calendar_id])
+)
+GO
+
+CREATE UNIQUE INDEX [uk_sys_job_calendar_name] ON [dbo].[sys_job_calendar] ([calendar_name])
+GO
+
+-- ----------------------------
+-- 22、定时任务排除日历规则表
+-- ----------------------------
+CREATE TABLE [dbo].[sys_job_calendar_rule] (
+  [rule_id]             BIGINT          IDENTITY(1,1) NOT NULL,    -- 规则ID
+  [calendar_id]         BIGINT          NOT NULL,                  -- 日历ID
+  [rule_type]           CHAR(1)         NOT NULL,                  -- 规则类型（0日期 1每周 2时间段）
+  [start_time]          DATETIME        DEFAULT NULL,              -- 日期或时间段开始时间
+  [end_time]            DATETIME        DEFAULT NULL,              -- 时间段结束时间（不包含）
+  [weekdays]            NVARCHAR(20)    DEFAULT '',                -- 每周排除的星期（1周日 ... 7周六）
+  [summary]             NVARCHAR(200)   DEFAULT '',                -- 说明
+  PRIMARY KEY ([rule_id])
+)
+GO
+
+CREATE INDEX [idx_sys_job_calendar_rule_c] ON [dbo].[sys_job_calendar_rule] ([calendar_id])
+GO
+
+-- ----------------------------
+-- 23、代码生成模板组表
+-- ----------------------------
+CREATE TABLE [dbo].[gen_template_set] (
+  [set_id]              BIGINT          IDENTITY(1,1) NOT NULL,    -- 模板组ID
+  [set_name]            NVARCHAR(64)    NOT NULL,                  -- 模板组名称
+  [status]              CHAR(1)         DEFAULT '0',               -- 状态（0正常 1停用）
+  [create_by]           NVARCHAR(64)    DEFAULT '',                -- 创建者
+  [create_time]         DATETIME        DEFAULT NULL,              -- 创建时间
+  [update_by]           NVARCHAR(64)    DEFAULT '',                -- 更新者
+  [update_time]         DATETIME        DEFAULT NULL,              -- 更新时间
+  [remark]              NVARCHAR(500)   DEFAULT '',                -- 备注
+  PRIMARY KEY ([set_id])
+)
+GO
+
+CREATE UNIQUE INDEX [uk_gen_template_set_name] ON [dbo].[gen_template_set] ([set_name])
+GO
+
+-- ----------------------------
+-- 24、代码生成模板表
+-- ----------------------------
+CREATE TABLE [dbo].[gen_template] (
+  [template_id]         BIGINT          IDENTITY(1,1) NOT NULL,    -- 模板ID
+  [set_id]              BIGINT          NOT NULL,                  -- 模板组ID
+  [template_name]       NVARCHAR(64)    NOT NULL,                  -- 模板名称（与内置模板名一致）
+  [content]             NVARCHAR(MAX)   NOT NULL,                  -- 模板内容
+  [version]             INT             DEFAULT 1,                 -- 当前版本号
+  [create_by]           NVARCHAR(64)    DEFAULT '',                -- 创建者
+  [create_time]         DATETIME        DEFAULT NULL,              -- 创建时间
+  [update_by]           NVARCHAR(64)    DEFAULT '',                -- 更新者
+  [update_time]         DATETIME        DEFAULT NULL,              -- 更新时间
+  PRIMARY KEY ([template_id])
+)
+GO
+
+CREATE UNIQUE INDEX [uk_gen_template_name] ON [dbo].[gen_template] ([set_id], [template_name])
+GO
+
+-- ----------------------------
+-- 25、代码生成模板历史版本表
+-- ----------------------------
+CREATE TABLE [dbo].[gen_template_history] (
+  [history_id]          BIGINT          IDENTITY(1,1) NOT NULL,    -- 历史ID
+  [template_id]         BIGINT          NOT NULL,                  -- 模板ID
+  [version]             INT             NOT NULL,                  -- 版本号
+  [content]             NVARCHAR(MAX)   NOT NULL,                  -- 模板内容
+  [create_by]           NVARCHAR(64)    DEFAULT '',                -- 保存者
+  [create_time]         DATETIME        DEFAULT NULL,              -- 保存时间
+  PRIMARY KEY ([history_id])
+)
+GO
+
+CREATE UNIQUE INDEX [uk_gen_template_history_v] ON [dbo].[gen_template_history] ([template_id], [version])
+GO
+
 -- ===========================================================================================
 -- 🎉 SQL Server 2012 完整转换成功完成！
 --
@@ -0,0 +1,10 @@
+-- 删除回收站
+
+DELETE FROM [dbo].[sys_job] WHERE [invoke_target] = 'recycleTask.purgeExpired'
+GO
+DELETE FROM [dbo].[sys_role_menu] WHERE [menu_id] IN (SELECT [menu_id] FROM [dbo].[sys_menu] WHERE [perms] IN ('system:recycle:list', 'system:recycle:restore', 'system:recycle:remove'))
+GO
+DELETE FROM [dbo].[sys_menu] WHERE [perms] IN ('system:recycle:list', 'system:recycle:restore', 'system:recycle:remove')
+GO
+IF OBJECT_ID(N'[dbo].[sys_recycle]', N'U') IS NOT NULL DROP TABLE [dbo].[sys_recycle]
+GO
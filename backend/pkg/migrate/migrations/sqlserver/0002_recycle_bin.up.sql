@@ -0,0 +1,56 @@
+-- 回收站：记录删除的用户、部门和角色，支持恢复和彻底删除
+
+-- ----------------------------
+-- 1、回收站表
+-- ----------------------------
+CREATE TABLE [dbo].[sys_recycle] (
+  [recycle_id]          BIGINT          IDENTITY(1,1) NOT NULL,    -- 回收站ID
+  [object_type]         NVARCHAR(20)    NOT NULL,                  -- 对象类型（user用户 dept部门 role角色）
+  [object_id]           BIGINT          NOT NULL,                  -- 对象ID
+  [object_name]         NVARCHAR(100)   DEFAULT '',                -- 对象名称
+  [snapshot]            NVARCHAR(MAX)   DEFAULT NULL,              -- 删除时的关联数据（JSON）
+  [delete_by]           NVARCHAR(64)    DEFAULT '',                -- 删除者
+  [delete_time]         DATETIME        DEFAULT NULL,              -- 删除时间
+  PRIMARY KEY ([recycle_id])
+)
+GO
+
+CREATE INDEX [idx_sys_recycle_type] ON [dbo].[sys_recycle] ([object_type], [object_id])
+GO
+CREATE INDEX [idx_sys_recycle_time] ON [dbo].[sys_recycle] ([delete_time])
+GO
+
+-- 迁移前已删除的数据（删除者和删除时间取最后更新信息，没有关联数据）
+INSERT INTO [dbo].[sys_recycle] ([object_type], [object_id], [object_name], [delete_by], [delete_time])
+SELECT 'user', [user_id], [user_name], ISNULL([update_by], ''), ISNULL([update_time], [create_time]) FROM [dbo].[sys_user] WHERE [del_flag] = '2'
+GO
+INSERT INTO [dbo].[sys_recycle] ([object_type], [object_id], [object_name], [delete_by], [delete_time])
+SELECT 'dept', [dept_id], [dept_name], ISNULL([update_by], ''), ISNULL([update_time], [create_time]) FROM [dbo].[sys_dept] WHERE [del_flag] = '2'
+GO
+INSERT INTO [dbo].[sys_recycle] ([object_type], [object_id], [object_name], [delete_by], [delete_time])
+SELECT 'role', [role_id], [role_name], ISNULL([update_by], ''), ISNULL([update_time], [create_time]) FROM [dbo].[sys_role] WHERE [del_flag] = '2'
+GO
+
+-- ----------------------------
+-- 2、回收站菜单（不指定ID，由数据库生成；已存在相同权限标识的菜单时跳过）
+-- ----------------------------
+INSERT INTO [dbo].[sys_menu] ([menu_name], [parent_id], [order_num], [path], [component], [query], [route_name], [is_frame], [is_cache], [menu_type], [visible], [status], [perms], [icon], [create_by], [create_time], [update_by], [update_time], [remark])
+SELECT N'回收站', 1, 10, 'recycle', 'system/recycle/index', '', '', 1, 0, 'C', '0', '0', 'system:recycle:list', 'clipboard', 'admin', GETDATE(), '', NULL, N'回收站菜单'
+WHERE NOT EXISTS (SELECT 1 FROM [dbo].[sys_menu] WHERE [perms] = 'system:recycle:list')
+GO
+INSERT INTO [dbo].[sys_menu] ([menu_name], [parent_id], [order_num], [path], [component], [query], [route_name], [is_frame], [is_cache], [menu_type], [visible], [status], [perms], [icon], [create_by], [create_time], [update_by], [update_time], [remark])
+SELECT N'回收站恢复', m.[menu_id], 1, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:recycle:restore', '#', 'admin', GETDATE(), '', NULL, '' FROM [dbo].[sys_menu] m
+WHERE m.[perms] = 'system:recycle:list' AND NOT EXISTS (SELECT 1 FROM [dbo].[sys_menu] WHERE [perms] = 'system:recycle:restore')
+GO
+INSERT INTO [dbo].[sys_menu] ([menu_name], [parent_id], [order_num], [path], [component], [query], [route_name], [is_frame], [is_cache], [menu_type], [visible], [status], [perms], [icon], [create_by], [create_time], [update_by], [update_time], [remark])
+SELECT N'回收站删除', m.[menu_id], 2, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:recycle:remove', '#', 'admin', GETDATE(), '', NULL, '' FROM [dbo].[sys_menu] m
+WHERE m.[perms] = 'system:recycle:list' AND NOT EXISTS (SELECT 1 FROM [dbo].[sys_menu] WHERE [perms] = 'system:recycle:remove')
+GO
+
+-- ----------------------------
+-- 3、回收站清理任务（保留天数见配置 recycle.retention_days）
+-- ----------------------------
+INSERT INTO [dbo].[sys_job] ([job_name], [job_group], [invoke_target], [cron_expression], [misfire_policy], [concurrent], [status], [create_by], [create_time], [update_by], [update_time], [remark])
+SELECT N'回收站清理', 'SYSTEM', 'recycleTask.purgeExpired', '0 0 2 * * ?', '3', '1', '0', 'admin', GETDATE(), '', NULL, N'彻底删除超过保留天数的回收站数据'
+WHERE NOT EXISTS (SELECT 1 FROM [dbo].[sys_job] WHERE [invoke_target] = 'recycleTask.purgeExpired')
+GO
@@ -0,0 +1,12 @@
+-- 删除数据变更历史
+
+DELETE FROM [dbo].[sys_role_menu] WHERE [menu_id] = 1063
+GO
+DELETE FROM [dbo].[sys_menu] WHERE [menu_id] = 1063
+GO
+ALTER TABLE [dbo].[sys_oper_log] DROP CONSTRAINT [DF_sys_oper_log_request_id]
+GO
+ALTER TABLE [dbo].[sys_oper_log] DROP COLUMN [request_id]
+GO
+IF OBJECT_ID(N'[dbo].[sys_data_change]', N'U') IS NOT NULL DROP TABLE [dbo].[sys_data_change]
+GO
@@ -0,0 +1,41 @@
+-- 数据变更历史：记录用户、角色、部门、菜单、参数和字典数据修改前后的字段值
+
+-- ----------------------------
+-- 1、数据变更记录表
+-- ----------------------------
+CREATE TABLE [dbo].[sys_data_change] (
+  [change_id]           BIGINT          IDENTITY(1,1) NOT NULL,    -- 变更ID
+  [entity]              NVARCHAR(50)    NOT NULL,                  -- 实体类型（user role dept menu config dict_data）
+  [entity_id]           BIGINT          NOT NULL,                  -- 实体ID
+  [field_name]          NVARCHAR(100)   NOT NULL,                  -- 字段名
+  [old_value]           NVARCHAR(MAX)   DEFAULT NULL,              -- 修改前的值
+  [new_value]           NVARCHAR(MAX)   DEFAULT NULL,              -- 修改后的值
+  [oper_id]             BIGINT          DEFAULT 0,                 -- 操作日志ID
+  [request_id]          NVARCHAR(64)    DEFAULT '',                -- 请求ID
+  [oper_name]           NVARCHAR(64)    DEFAULT '',                -- 操作人员
+  [change_time]         DATETIME        DEFAULT NULL,              -- 变更时间
+  PRIMARY KEY ([change_id])
+)
+GO
+
+CREATE INDEX [idx_sys_data_change_entity] ON [dbo].[sys_data_change] ([entity], [entity_id], [change_time])
+GO
+CREATE INDEX [idx_sys_data_change_request] ON [dbo].[sys_data_change] ([request_id])
+GO
+
+-- ----------------------------
+-- 2、操作日志关联请求ID
+-- ----------------------------
+ALTER TABLE [dbo].[sys_oper_log] ADD [request_id] NVARCHAR(64) CONSTRAINT [DF_sys_oper_log_request_id] DEFAULT '' -- 请求ID
+GO
+
+-- ----------------------------
+-- 3、变更历史按钮
+-- ----------------------------
+SET IDENTITY_INSERT [dbo].[sys_menu] ON
+GO
+INSERT INTO [dbo].[sys_menu] ([menu_id], [menu_name], [parent_id], [order_num], [path], [component], [query], [route_name], [is_frame], [is_cache], [menu_type], [visible], [status], [perms], [icon], [create_by], [create_time], [update_by], [update_time], [remark]) VALUES
+(1063, N'变更历史', 500, 4, '#', '', '', '', 1, 0, 'F', '0', '0', 'monitor:history:list', '#', 'admin', GETDATE(), '', NULL, '')
+GO
+SET IDENTITY_INSERT [dbo].[sys_menu] OFF
+GO
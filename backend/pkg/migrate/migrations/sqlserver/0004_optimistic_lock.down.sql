@@ -0,0 +1,26 @@
+-- 删除乐观锁版本号
+
+ALTER TABLE [dbo].[sys_user] DROP CONSTRAINT [DF_sys_user_version]
+GO
+ALTER TABLE [dbo].[sys_user] DROP COLUMN [version]
+GO
+ALTER TABLE [dbo].[sys_role] DROP CONSTRAINT [DF_sys_role_version]
+GO
+ALTER TABLE [dbo].[sys_role] DROP COLUMN [version]
+GO
+ALTER TABLE [dbo].[sys_dept] DROP CONSTRAINT [DF_sys_dept_version]
+GO
+ALTER TABLE [dbo].[sys_dept] DROP COLUMN [version]
+GO
+ALTER TABLE [dbo].[sys_menu] DROP CONSTRAINT [DF_sys_menu_version]
+GO
+ALTER TABLE [dbo].[sys_menu] DROP COLUMN [version]
+GO
+ALTER TABLE [dbo].[sys_config] DROP CONSTRAINT [DF_sys_config_version]
+GO
+ALTER TABLE [dbo].[sys_config] DROP COLUMN [version]
+GO
+ALTER TABLE [dbo].[sys_dict_data] DROP CONSTRAINT [DF_sys_dict_data_version]
+GO
+ALTER TABLE [dbo].[sys_dict_data] DROP COLUMN [version]
+GO
@@ -0,0 +1,14 @@
+-- 乐观锁：核心表增加版本号，每次修改加1，修改时校验客户端读取时的版本号
+
+ALTER TABLE [dbo].[sys_user] ADD [version] INT NOT NULL CONSTRAINT [DF_sys_user_version] DEFAULT 1 -- 版本号
+GO
+ALTER TABLE [dbo].[sys_role] ADD [version] INT NOT NULL CONSTRAINT [DF_sys_role_version] DEFAULT 1 -- 版本号
+GO
+ALTER TABLE [dbo].[sys_dept] ADD [version] INT NOT NULL CONSTRAINT [DF_sys_dept_version] DEFAULT 1 -- 版本号
+GO
+ALTER TABLE [dbo].[sys_menu] ADD [version] INT NOT NULL CONSTRAINT [DF_sys_menu_version] DEFAULT 1 -- 版本号
+GO
+ALTER TABLE [dbo].[sys_config] ADD [version] INT NOT NULL CONSTRAINT [DF_sys_config_version] DEFAULT 1 -- 版本号
+GO
+ALTER TABLE [dbo].[sys_dict_data] ADD [version] INT NOT NULL CONSTRAINT [DF_sys_dict_data_version] DEFAULT 1 -- 版本号
+GO
@@ -0,0 +1,62 @@
+-- 删除多租户
+
+DELETE FROM [dbo].[sys_role_menu] WHERE [menu_id] IN (SELECT [menu_id] FROM [dbo].[sys_menu] WHERE [perms] IN ('system:tenant:list', 'system:tenantPackage:list', 'system:tenant:query', 'system:tenant:add', 'system:tenant:edit', 'system:tenant:remove', 'system:tenantPackage:query', 'system:tenantPackage:add', 'system:tenantPackage:edit', 'system:tenantPackage:remove'))
+GO
+DELETE FROM [dbo].[sys_menu] WHERE [perms] IN ('system:tenant:list', 'system:tenantPackage:list', 'system:tenant:query', 'system:tenant:add', 'system:tenant:edit', 'system:tenant:remove', 'system:tenantPackage:query', 'system:tenantPackage:add', 'system:tenantPackage:edit', 'system:tenantPackage:remove')
+GO
+DROP INDEX [idx_sys_user_tenant] ON [dbo].[sys_user]
+GO
+DROP INDEX [idx_sys_config_tenant] ON [dbo].[sys_config]
+GO
+DROP INDEX [idx_sys_dict_data_tenant] ON [dbo].[sys_dict_data]
+GO
+ALTER TABLE [dbo].[sys_user] DROP CONSTRAINT [DF_sys_user_tenant_id]
+GO
+ALTER TABLE [dbo].[sys_user] DROP COLUMN [tenant_id]
+GO
+ALTER TABLE [dbo].[sys_dept] DROP CONSTRAINT [DF_sys_dept_tenant_id]
+GO
+ALTER TABLE [dbo].[sys_dept] DROP COLUMN [tenant_id]
+GO
+ALTER TABLE [dbo].[sys_role] DROP CONSTRAINT [DF_sys_role_tenant_id]
+GO
+ALTER TABLE [dbo].[sys_role] DROP COLUMN [tenant_id]
+GO
+ALTER TABLE [dbo].[sys_post] DROP CONSTRAINT [DF_sys_post_tenant_id]
+GO
+ALTER TABLE [dbo].[sys_post] DROP COLUMN [tenant_id]
+GO
+ALTER TABLE [dbo].[sys_config] DROP CONSTRAINT [DF_sys_config_tenant_id]
+GO
+ALTER TABLE [dbo].[sys_config] DROP COLUMN [tenant_id]
+GO
+ALTER TABLE [dbo].[sys_dict_data] DROP CONSTRAINT [DF_sys_dict_data_tenant_id]
+GO
+ALTER TABLE [dbo].[sys_dict_data] DROP COLUMN [tenant_id]
+GO
+ALTER TABLE [dbo].[sys_notice] DROP CONSTRAINT [DF_sys_notice_tenant_id]
+GO
+ALTER TABLE [dbo].[sys_notice] DROP COLUMN [tenant_id]
+GO
+ALTER TABLE [dbo].[sys_oper_log] DROP CONSTRAINT [DF_sys_oper_log_tenant_id]
+GO
+ALTER TABLE [dbo].[sys_oper_log] DROP COLUMN [tenant_id]
+GO
+ALTER TABLE [dbo].[sys_logininfor] DROP CONSTRAINT [DF_sys_logininfor_tenant_id]
+GO
+ALTER TABLE [dbo].[sys_logininfor] DROP COLUMN [tenant_id]
+GO
+ALTER TABLE [dbo].[sys_recycle] DROP CONSTRAINT [DF_sys_recycle_tenant_id]
+GO
+ALTER TABLE [dbo].[sys_recycle] DROP COLUMN [tenant_id]
+GO
+ALTER TABLE [dbo].[sys_data_change] DROP CONSTRAINT [DF_sys_data_change_tenant_id]
+GO
+ALTER TABLE [dbo].[sys_data_change] DROP COLUMN [tenant_id]
+GO
+IF OBJECT_ID(N'[dbo].[sys_tenant_package_menu]', N'U') IS NOT NULL DROP TABLE [dbo].[sys_tenant_package_menu]
+GO
+IF OBJECT_ID(N'[dbo].[sys_tenant_package]', N'U') IS NOT NULL DROP TABLE [dbo].[sys_tenant_package]
+GO
+IF OBJECT_ID(N'[dbo].[sys_tenant]', N'U') IS NOT NULL DROP TABLE [dbo].[sys_tenant]
+GO
@@ -0,0 +1,132 @@
+-- 多租户：租户和租户套餐表，租户隔离的业务表增加租户ID，已有数据属于平台租户
+
+-- ----------------------------
+-- 1、租户表
+-- ----------------------------
+CREATE TABLE [dbo].[sys_tenant] (
+  [tenant_id]           BIGINT          IDENTITY(1,1) NOT NULL,    -- 租户ID
+  [tenant_name]         NVARCHAR(50)    NOT NULL,                  -- 租户名称
+  [domain]              NVARCHAR(100)   DEFAULT '',                -- 绑定域名
+  [package_id]          BIGINT          DEFAULT 0,                 -- 租户套餐ID（0不限制菜单）
+  [contact_user_name]   NVARCHAR(30)    DEFAULT '',                -- 联系人
+  [contact_phone]       NVARCHAR(20)    DEFAULT '',                -- 联系电话
+  [expire_time]         DATETIME        DEFAULT NULL,              -- 过期时间
+  [status]              CHAR(1)         DEFAULT '0',               -- 租户状态（0正常 1停用）
+  [del_flag]            CHAR(1)         DEFAULT '0',               -- 删除标志（0代表存在 2代表删除）
+  [create_by]           NVARCHAR(64)    DEFAULT '',                -- 创建者
+  [create_time]         DATETIME        DEFAULT NULL,              -- 创建时间
+  [update_by]           NVARCHAR(64)    DEFAULT '',                -- 更新者
+  [update_time]         DATETIME        DEFAULT NULL,              -- 更新时间
+  [remark]              NVARCHAR(500)   DEFAULT NULL,              -- 备注
+  PRIMARY KEY ([tenant_id])
+)
+GO
+
+SET IDENTITY_INSERT [dbo].[sys_tenant] ON
+GO
+INSERT INTO [dbo].[sys_tenant] ([tenant_id], [tenant_name], [domain], [package_id], [status], [del_flag], [create_by], [create_time], [remark]) VALUES
+(1, N'平台租户', '', 0, '0', '0', 'admin', GETDATE(), N'平台租户，未开启租户模式时所有数据属于该租户')
+GO
+SET IDENTITY_INSERT [dbo].[sys_tenant] OFF
+GO
+
+-- ----------------------------
+-- 2、租户套餐表和套餐菜单关联表
+-- ----------------------------
+CREATE TABLE [dbo].[sys_tenant_package] (
+  [package_id]          BIGINT          IDENTITY(1,1) NOT NULL,    -- 套餐ID
+  [package_name]        NVARCHAR(50)    NOT NULL,                  -- 套餐名称
+  [status]              CHAR(1)         DEFAULT '0',               -- 套餐状态（0正常 1停用）
+  [del_flag]            CHAR(1)         DEFAULT '0',               -- 删除标志（0代表存在 2代表删除）
+  [create_by]           NVARCHAR(64)    DEFAULT '',                -- 创建者
+  [create_time]         DATETIME        DEFAULT NULL,              -- 创建时间
+  [update_by]           NVARCHAR(64)    DEFAULT '',                -- 更新者
+  [update_time]         DATETIME        DEFAULT NULL,              -- 更新时间
+  [remark]              NVARCHAR(500)   DEFAULT NULL,              -- 备注
+  PRIMARY KEY ([package_id])
+)
+GO
+
+CREATE TABLE [dbo].[sys_tenant_package_menu] (
+  [package_id]          BIGINT          NOT NULL,                  -- 套餐ID
+  [menu_id]             BIGINT          NOT NULL,                  -- 菜单ID
+  PRIMARY KEY ([package_id], [menu_id])
+)
+GO
+
+-- ----------------------------
+-- 3、租户隔离的业务表增加租户ID
+-- ----------------------------
+ALTER TABLE [dbo].[sys_user] ADD [tenant_id] BIGINT NOT NULL CONSTRAINT [DF_sys_user_tenant_id] DEFAULT 1 -- 租户ID
+GO
+ALTER TABLE [dbo].[sys_dept] ADD [tenant_id] BIGINT NOT NULL CONSTRAINT [DF_sys_dept_tenant_id] DEFAULT 1 -- 租户ID
+GO
+ALTER TABLE [dbo].[sys_role] ADD [tenant_id] BIGINT NOT NULL CONSTRAINT [DF_sys_role_tenant_id] DEFAULT 1 -- 租户ID
+GO
+ALTER TABLE [dbo].[sys_post] ADD [tenant_id] BIGINT NOT NULL CONSTRAINT [DF_sys_post_tenant_id] DEFAULT 1 -- 租户ID
+GO
+ALTER TABLE [dbo].[sys_config] ADD [tenant_id] BIGINT NOT NULL CONSTRAINT [DF_sys_config_tenant_id] DEFAULT 1 -- 租户ID
+GO
+ALTER TABLE [dbo].[sys_dict_data] ADD [tenant_id] BIGINT NOT NULL CONSTRAINT [DF_sys_dict_data_tenant_id] DEFAULT 1 -- 租户ID
+GO
+ALTER TABLE [dbo].[sys_notice] ADD [tenant_id] BIGINT NOT NULL CONSTRAINT [DF_sys_notice_tenant_id] DEFAULT 1 -- 租户ID
+GO
+ALTER TABLE [dbo].[sys_oper_log] ADD [tenant_id] BIGINT NOT NULL CONSTRAINT [DF_sys_oper_log_tenant_id] DEFAULT 1 -- 租户ID
+GO
+ALTER TABLE [dbo].[sys_logininfor] ADD [tenant_id] BIGINT NOT NULL CONSTRAINT [DF_sys_logininfor_tenant_id] DEFAULT 1 -- 租户ID
+GO
+ALTER TABLE [dbo].[sys_recycle] ADD [tenant_id] BIGINT NOT NULL CONSTRAINT [DF_sys_recycle_tenant_id] DEFAULT 1 -- 租户ID
+GO
+ALTER TABLE [dbo].[sys_data_change] ADD [tenant_id] BIGINT NOT NULL CONSTRAINT [DF_sys_data_change_tenant_id] DEFAULT 1 -- 租户ID
+GO
+
+CREATE INDEX [idx_sys_user_tenant] ON [dbo].[sys_user] ([tenant_id], [user_name])
+GO
+CREATE INDEX [idx_sys_config_tenant] ON [dbo].[sys_config] ([tenant_id], [config_key])
+GO
+CREATE INDEX [idx_sys_dict_data_tenant] ON [dbo].[sys_dict_data] ([tenant_id], [dict_type])
+GO
+
+-- ----------------------------
+-- 4、租户管理菜单（不指定ID，由数据库生成；已存在相同权限标识的菜单时跳过）
+-- ----------------------------
+INSERT INTO [dbo].[sys_menu] ([menu_name], [parent_id], [order_num], [path], [component], [query], [route_name], [is_frame], [is_cache], [menu_type], [visible], [status], [perms], [icon], [create_by], [create_time], [update_by], [update_time], [remark])
+SELECT N'租户管理', 1, 11, 'tenant', 'system/tenant/index', '', '', 1, 0, 'C', '0', '0', 'system:tenant:list', 'peoples', 'admin', GETDATE(), '', NULL, N'租户管理菜单'
+WHERE NOT EXISTS (SELECT 1 FROM [dbo].[sys_menu] WHERE [perms] = 'system:tenant:list')
+GO
+INSERT INTO [dbo].[sys_menu] ([menu_name], [parent_id], [order_num], [path], [component], [query], [route_name], [is_frame], [is_cache], [menu_type], [visible], [status], [perms], [icon], [create_by], [create_time], [update_by], [update_time], [remark])
+SELECT N'租户套餐', 1, 12, 'tenantPackage', 'system/tenantPackage/index', '', '', 1, 0, 'C', '0', '0', 'system:tenantPackage:list', 'shopping', 'admin', GETDATE(), '', NULL, N'租户套餐菜单'
+WHERE NOT EXISTS (SELECT 1 FROM [dbo].[sys_menu] WHERE [perms] = 'system:tenantPackage:list')
+GO
+INSERT INTO [dbo].[sys_menu] ([menu_name], [parent_id], [order_num], [path], [component], [query], [route_name], [is_frame], [is_cache], [menu_type], [visible], [status], [perms], [icon], [create_by], [create_time], [update_by], [update_time], [remark])
+SELECT N'租户查询', m.[menu_id], 1, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:tenant:query', '#', 'admin', GETDATE(), '', NULL, '' FROM [dbo].[sys_menu] m
+WHERE m.[perms] = 'system:tenant:list' AND NOT EXISTS (SELECT 1 FROM [dbo].[sys_menu] WHERE [perms] = 'system:tenant:query')
+GO
+INSERT INTO [dbo].[sys_menu] ([menu_name], [parent_id], [order_num], [path], [component], [query], [route_name], [is_frame], [is_cache], [menu_type], [visible], [status], [perms], [icon], [create_by], [create_time], [update_by], [update_time], [remark])
+SELECT N'租户新增', m.[menu_id], 2, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:tenant:add', '#', 'admin', GETDATE(), '', NULL, '' FROM [dbo].[sys_menu] m
+WHERE m.[perms] = 'system:tenant:list' AND NOT EXISTS (SELECT 1 FROM [dbo].[sys_menu] WHERE [perms] = 'system:tenant:add')
+GO
+INSERT INTO [dbo].[sys_menu] ([menu_name], [parent_id], [order_num], [path], [component], [query], [route_name], [is_frame], [is_cache], [menu_type], [visible], [status], [perms], [icon], [create_by], [create_time], [update_by], [update_time], [remark])
+SELECT N'租户修改', m.[menu_id], 3, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:tenant:edit', '#', 'admin', GETDATE(), '', NULL, '' FROM [dbo].[sys_menu] m
+WHERE m.[perms] = 'system:tenant:list' AND NOT EXISTS (SELECT 1 FROM [dbo].[sys_menu] WHERE [perms] = 'system:tenant:edit')
+GO
+INSERT INTO [dbo].[sys_menu] ([menu_name], [parent_id], [order_num], [path], [component], [query], [route_name], [is_frame], [is_cache], [menu_type], [visible], [status], [perms], [icon], [create_by], [create_time], [update_by], [update_time], [remark])
+SELECT N'租户删除', m.[menu_id], 4, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:tenant:remove', '#', 'admin', GETDATE(), '', NULL, '' FROM [dbo].[sys_menu] m
+WHERE m.[perms] = 'system:tenant:list' AND NOT EXISTS (SELECT 1 FROM [dbo].[sys_menu] WHERE [perms] = 'system:tenant:remove')
+GO
+INSERT INTO [dbo].[sys_menu] ([menu_name], [parent_id], [order_num], [path], [component], [query], [route_name], [is_frame], [is_cache], [menu_type], [visible], [status], [perms], [icon], [create_by], [create_time], [update_by], [update_time], [remark])
+SELECT N'套餐查询', m.[menu_id], 1, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:tenantPackage:query', '#', 'admin', GETDATE(), '', NULL, '' FROM [dbo].[sys_menu] m
+WHERE m.[perms] = 'system:tenantPackage:list' AND NOT EXISTS (SELECT 1 FROM [dbo].[sys_menu] WHERE [perms] = 'system:tenantPackage:query')
+GO
+INSERT INTO [dbo].[sys_menu] ([menu_name], [parent_id], [order_num], [path], [component], [query], [route_name], [is_frame], [is_cache], [menu_type], [visible], [status], [perms], [icon], [create_by], [create_time], [update_by], [update_time], [remark])
+SELECT N'套餐新增', m.[menu_id], 2, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:tenantPackage:add', '#', 'admin', GETDATE(), '', NULL, '' FROM [dbo].[sys_menu] m
+WHERE m.[perms] = 'system:tenantPackage:list' AND NOT EXISTS (SELECT 1 FROM [dbo].[sys_menu] WHERE [perms] = 'system:tenantPackage:add')
+GO
+INSERT INTO [dbo].[sys_menu] ([menu_name], [parent_id], [order_num], [path], [component], [query], [route_name], [is_frame], [is_cache], [menu_type], [visible], [status], [perms], [icon], [create_by], [create_time], [update_by], [update_time], [remark])
+SELECT N'套餐修改', m.[menu_id], 3, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:tenantPackage:edit', '#', 'admin', GETDATE(), '', NULL, '' FROM [dbo].[sys_menu] m
+WHERE m.[perms] = 'system:tenantPackage:list' AND NOT EXISTS (SELECT 1 FROM [dbo].[sys_menu] WHERE [perms] = 'system:tenantPackage:edit')
+GO
+INSERT INTO [dbo].[sys_menu] ([menu_name], [parent_id], [order_num], [path], [component], [query], [route_name], [is_frame], [is_cache], [menu_type], [visible], [status], [perms], [icon], [create_by], [create_time], [update_by], [update_time], [remark])
+SELECT N'套餐删除', m.[menu_id], 4, '#', '', '', '', 1, 0, 'F', '0', '0', 'system:tenantPackage:remove', '#', 'admin', GETDATE(), '', NULL, '' FROM [dbo].[sys_menu] m
+WHERE m.[perms] = 'system:tenantPackage:list' AND NOT EXISTS (SELECT 1 FROM [dbo].[sys_menu] WHERE [perms] = 'system:tenantPackage:remove')
+GO
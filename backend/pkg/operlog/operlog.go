@@ -54,15 +54,17 @@ func RecordOperLog(ctx *gin.Context, title, businessType, content string, succes
 		Status:        model.OperStatusFail, // 默认失败
 		ErrorMsg:      "",
 		OperTime:      &now,
+		RequestID:     ctx.GetString("requestId"),
 	}
 
 	if success {
 		operLog.Status = model.OperStatusSuccess // 成功
 	}
 
-	// 异步记录日志，不影响主流程
+	// 异步记录日志，不影响主流程；请求的context携带租户
+	requestCtx := ctx.Request.Context()
 	go func() {
-		operLogService := system.NewOperLogService()
+		operLogService := system.NewOperLogService().WithContext(requestCtx)
 		if err := operLogService.InsertOperLog(operLog); err != nil {
 			fmt.Printf("记录操作日志失败: %v\n", err)
 		}
@@ -13,6 +13,9 @@ import (
 var RDB *redis.Client
 var ctx = context.Background()
 
+// Nil 键不存在时返回的错误
+const Nil = redis.Nil
+
 // InitRedis 初始化Redis连接
 func InitRedis() error {
 	cfg := config.AppConfig.Redis
@@ -8,9 +8,10 @@ import (
 
 // 响应状态码常量 对应Java后端的HttpStatus
 const (
-	SUCCESS = 200 // 成功
-	ERROR   = 500 // 系统内部错误
-	WARN    = 601 // 系统警告消息
+	SUCCESS  = 200 // 成功
+	CONFLICT = 409 // 数据已被其他用户修改（乐观锁版本号不一致），前端提示刷新后重新修改
+	ERROR    = 500 // 系统内部错误
+	WARN     = 601 // 系统警告消息
 )
 
 // Result 统一响应结构 对应Java后端的AjaxResult
@@ -109,6 +110,14 @@ func ErrorWithCode(c *gin.Context, httpCode int, message string) {
 	})
 }
 
+// Conflict 版本冲突响应，数据已被其他用户修改
+func Conflict(c *gin.Context, message string) {
+	c.JSON(http.StatusOK, Result{
+		Code: CONFLICT,
+		Msg:  message,
+	})
+}
+
 // Warn 警告响应
 func Warn(c *gin.Context, message string) {
 	c.JSON(http.StatusOK, Result{
@@ -0,0 +1,26 @@
+// Package sqlitetest 测试使用的SQLite连接，不依赖项目中的其他包，迁移和数据库包的测试也可以使用
+package sqlitetest
+
+import (
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Open 打开SQLite数据库，dsn为空时使用内存数据库；只使用一个连接，内存库在同一连接上可见，测试结束时关闭
+func Open(t testing.TB, dsn string) *gorm.DB {
+	t.Helper()
+	if dsn == "" {
+		dsn = ":memory:"
+	}
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("打开SQLite失败: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	return db
+}
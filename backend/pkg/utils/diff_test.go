@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		old      string
+		new      string
+		context  int
+		expected string
+	}{
+		{"内容相同", "a\nb\n", "a\nb\n", 3, ""},
+		{"新文件", "", "a\nb\n", 3, "--- old\n+++ new\n@@ -0,0 +1,2 @@\n+a\n+b\n"},
+		{"删除文件", "a\n", "", 3, "--- old\n+++ new\n@@ -1 +0,0 @@\n-a\n"},
+		{"修改一行", "a\nb\nc\n", "a\nx\nc\n", 1, "--- old\n+++ new\n@@ -1,3 +1,3 @@\n a\n-b\n+x\n c\n"},
+		{
+			"相距较远的变更拆分为两个hunk",
+			"1\n2\n3\n4\n5\n6\n7\n8\n",
+			"1\nx\n3\n4\n5\n6\ny\n8\n",
+			1,
+			"--- old\n+++ new\n@@ -1,3 +1,3 @@\n 1\n-2\n+x\n 3\n@@ -6,3 +6,3 @@\n 6\n-7\n+y\n 8\n",
+		},
+		{
+			"相距较近的变更合并为一个hunk",
+			"1\n2\n3\n4\n",
+			"x\n2\n3\ny\n",
+			1,
+			"--- old\n+++ new\n@@ -1,4 +1,4 @@\n-1\n+x\n 2\n 3\n-4\n+y\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, UnifiedDiff("old", "new", tt.old, tt.new, tt.context))
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+	"wosm/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetVersionETag 将数据的版本号作为ETag返回，客户端修改时通过If-Match请求头或请求体的version字段带回
+func SetVersionETag(ctx *gin.Context, version int64) {
+	ctx.Header("ETag", `"`+strconv.FormatInt(version, 10)+`"`)
+}
+
+// IfMatchVersion 从If-Match请求头解析版本号，没有或不是版本号（如*）时返回0
+func IfMatchVersion(ctx *gin.Context) int64 {
+	etag := strings.TrimSpace(ctx.GetHeader("If-Match"))
+	etag = strings.TrimPrefix(etag, "W/")
+	version, err := strconv.ParseInt(strings.Trim(etag, `"`), 10, 64)
+	if err != nil || version < 0 {
+		return 0
+	}
+	return version
+}
+
+// RequireVersion 修改接口要求携带乐观锁版本号：请求体未传version时取If-Match请求头
+// 都没有时返回response.CONFLICT提示刷新后重新修改，并返回false
+func RequireVersion(ctx *gin.Context, version *int64) bool {
+	if *version == 0 {
+		*version = IfMatchVersion(ctx)
+	}
+	if *version == 0 {
+		response.Conflict(ctx, "缺少数据版本号，请刷新后重新修改")
+		return false
+	}
+	return true
+}
@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersionETag(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	SetVersionETag(ctx, 3)
+	assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+
+	tests := map[string]int64{
+		`"3"`:   3,
+		`W/"4"`: 4,
+		"5":     5,
+		"*":     0,
+		"":      0,
+		`"-1"`:  0,
+	}
+	for header, expected := range tests {
+		ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+		ctx.Request = httptest.NewRequest("PUT", "/system/role", nil)
+		ctx.Request.Header.Set("If-Match", header)
+		assert.Equal(t, expected, IfMatchVersion(ctx), "If-Match: %s", header)
+	}
+}
+
+func TestRequireVersion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name     string
+		version  int64
+		ifMatch  string
+		expected int64
+		ok       bool
+	}{
+		{"请求体携带版本号", 3, `"5"`, 3, true},
+		{"取If-Match请求头", 0, `"5"`, 5, true},
+		{"未携带版本号", 0, "", 0, false},
+		{"If-Match不是版本号", 0, "*", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request = httptest.NewRequest("PUT", "/system/role", nil)
+			if tt.ifMatch != "" {
+				ctx.Request.Header.Set("If-Match", tt.ifMatch)
+			}
+
+			version := tt.version
+			assert.Equal(t, tt.ok, RequireVersion(ctx, &version))
+			assert.Equal(t, tt.expected, version)
+			if !tt.ok {
+				// 返回冲突码，前端提示刷新后重新修改
+				assert.JSONEq(t, `{"code":409,"msg":"缺少数据版本号，请刷新后重新修改"}`, w.Body.String())
+			}
+		})
+	}
+}
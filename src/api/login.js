@@ -1,12 +1,13 @@
 import request from '@/utils/request'
 
 // 登录方法
-export function login(username, password, code, uuid) {
+export function login(username, password, code, uuid, tenantId) {
   const data = {
     username,
     password,
     code,
-    uuid
+    uuid,
+    tenantId
   }
   return request({
     url: '/login',
@@ -0,0 +1,44 @@
+import request from '@/utils/request'
+
+// 查询排除日历列表
+export function listCalendar(query) {
+  return request({
+    url: '/monitor/calendar/list',
+    method: 'get',
+    params: query
+  })
+}
+
+// 查询排除日历详细（包含排除规则）
+export function getCalendar(calendarId) {
+  return request({
+    url: '/monitor/calendar/' + calendarId,
+    method: 'get'
+  })
+}
+
+// 新增排除日历
+export function addCalendar(data) {
+  return request({
+    url: '/monitor/calendar',
+    method: 'post',
+    data: data
+  })
+}
+
+// 修改排除日历
+export function updateCalendar(data) {
+  return request({
+    url: '/monitor/calendar',
+    method: 'put',
+    data: data
+  })
+}
+
+// 删除排除日历
+export function delCalendar(calendarIds) {
+  return request({
+    url: '/monitor/calendar/' + calendarIds,
+    method: 'delete'
+  })
+}
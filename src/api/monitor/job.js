@@ -17,6 +17,14 @@ export function getJob(jobId) {
   })
 }
 
+// 查询任务依赖关系图
+export function getJobGraph() {
+  return request({
+    url: '/monitor/job/graph',
+    method: 'get'
+  })
+}
+
 // 新增定时任务调度
 export function addJob(data) {
   return request({
@@ -0,0 +1,9 @@
+import request from '@/utils/request'
+
+// 查询慢SQL和重复执行的SQL
+export function getSlowSql() {
+  return request({
+    url: '/monitor/sql/slow',
+    method: 'get'
+  })
+}
@@ -0,0 +1,26 @@
+import request from '@/utils/request'
+
+// 查询回收站列表
+export function listRecycle(query) {
+  return request({
+    url: '/system/recycle/list',
+    method: 'get',
+    params: query
+  })
+}
+
+// 恢复回收站数据
+export function restoreRecycle(recycleIds) {
+  return request({
+    url: '/system/recycle/restore/' + recycleIds,
+    method: 'put'
+  })
+}
+
+// 彻底删除回收站数据
+export function delRecycle(recycleIds) {
+  return request({
+    url: '/system/recycle/' + recycleIds,
+    method: 'delete'
+  })
+}
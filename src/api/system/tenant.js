@@ -0,0 +1,95 @@
+import request from '@/utils/request'
+
+// 查询租户列表
+export function listTenant(query) {
+  return request({
+    url: '/system/tenant/list',
+    method: 'get',
+    params: query
+  })
+}
+
+// 查询租户详细
+export function getTenant(tenantId) {
+  return request({
+    url: '/system/tenant/' + tenantId,
+    method: 'get'
+  })
+}
+
+// 新增租户
+export function addTenant(data) {
+  return request({
+    url: '/system/tenant',
+    method: 'post',
+    data: data
+  })
+}
+
+// 修改租户
+export function updateTenant(data) {
+  return request({
+    url: '/system/tenant',
+    method: 'put',
+    data: data
+  })
+}
+
+// 删除租户
+export function delTenant(tenantId) {
+  return request({
+    url: '/system/tenant/' + tenantId,
+    method: 'delete'
+  })
+}
+
+// 查询租户套餐列表
+export function listTenantPackage(query) {
+  return request({
+    url: '/system/tenant/package/list',
+    method: 'get',
+    params: query
+  })
+}
+
+// 查询租户套餐选择框列表
+export function optionselectTenantPackage() {
+  return request({
+    url: '/system/tenant/package/optionselect',
+    method: 'get'
+  })
+}
+
+// 查询租户套餐详细
+export function getTenantPackage(packageId) {
+  return request({
+    url: '/system/tenant/package/' + packageId,
+    method: 'get'
+  })
+}
+
+// 新增租户套餐
+export function addTenantPackage(data) {
+  return request({
+    url: '/system/tenant/package',
+    method: 'post',
+    data: data
+  })
+}
+
+// 修改租户套餐
+export function updateTenantPackage(data) {
+  return request({
+    url: '/system/tenant/package',
+    method: 'put',
+    data: data
+  })
+}
+
+// 删除租户套餐
+export function delTenantPackage(packageId) {
+  return request({
+    url: '/system/tenant/package/' + packageId,
+    method: 'delete'
+  })
+}
@@ -0,0 +1,44 @@
+import request from '@/utils/request'
+
+// 查询运行时模块列表
+export function listDyn(module, query) {
+  return request({
+    url: '/dyn/' + module + '/list',
+    method: 'get',
+    params: query
+  })
+}
+
+// 查询运行时模块详细
+export function getDyn(module, id) {
+  return request({
+    url: '/dyn/' + module + '/' + id,
+    method: 'get'
+  })
+}
+
+// 新增运行时模块数据
+export function addDyn(module, data) {
+  return request({
+    url: '/dyn/' + module,
+    method: 'post',
+    data: data
+  })
+}
+
+// 修改运行时模块数据
+export function updateDyn(module, data) {
+  return request({
+    url: '/dyn/' + module,
+    method: 'put',
+    data: data
+  })
+}
+
+// 删除运行时模块数据
+export function delDyn(module, ids) {
+  return request({
+    url: '/dyn/' + module + '/' + ids,
+    method: 'delete'
+  })
+}
@@ -43,12 +43,30 @@ export function importTable(data) {
   })
 }
 
+// 导入表定义（不创建数据库表）
+export function importDefinition(data) {
+  return request({
+    url: '/tool/gen/definition/import',
+    method: 'post',
+    data: data
+  })
+}
+
+// 预览建表语句
+export function previewCreateTable(data) {
+  return request({
+    url: '/tool/gen/createTable/preview',
+    method: 'post',
+    data: data
+  })
+}
+
 // 创建表
 export function createTable(data) {
   return request({
     url: '/tool/gen/createTable',
     method: 'post',
-    params: data
+    data: data
   })
 }
 
@@ -69,17 +87,77 @@ export function delTable(tableId) {
 }
 
 // 生成代码（自定义路径）
-export function genCode(tableName) {
+export function genCode(tableName, registerRoutes) {
   return request({
     url: '/tool/gen/genCode/' + tableName,
+    method: 'get',
+    params: { registerRoutes: registerRoutes }
+  })
+}
+
+// 预览生成到自定义路径的差异
+export function genDiff(tableName, registerRoutes) {
+  return request({
+    url: '/tool/gen/genDiff/' + tableName,
+    method: 'get',
+    params: { registerRoutes: registerRoutes }
+  })
+}
+
+// 查询表结构差异
+export function getSynchDrift(tableName) {
+  return request({
+    url: '/tool/gen/synchDb/' + tableName,
     method: 'get'
   })
 }
 
 // 同步数据库
-export function synchDb(tableName) {
+export function synchDb(tableName, data) {
   return request({
     url: '/tool/gen/synchDb/' + tableName,
+    method: 'post',
+    data: data
+  })
+}
+
+// 扫描表结构差异
+export function scanDrift() {
+  return request({
+    url: '/tool/gen/drift',
     method: 'get'
   })
 }
+
+// 安装模块菜单
+export function installMenu(tableId, roleIds) {
+  return request({
+    url: '/tool/gen/menu/' + tableId,
+    method: 'post',
+    data: { roleIds: roleIds }
+  })
+}
+
+// 卸载模块菜单
+export function uninstallMenu(tableId) {
+  return request({
+    url: '/tool/gen/menu/' + tableId,
+    method: 'delete'
+  })
+}
+
+// 发布运行时模块
+export function publishTable(tableId) {
+  return request({
+    url: '/tool/gen/publish/' + tableId,
+    method: 'put'
+  })
+}
+
+// 取消发布运行时模块
+export function unpublishTable(tableId) {
+  return request({
+    url: '/tool/gen/publish/' + tableId,
+    method: 'delete'
+  })
+}
@@ -0,0 +1,95 @@
+import request from '@/utils/request'
+
+// 查询模板组列表
+export function listTemplateSet(query) {
+  return request({
+    url: '/tool/gen/template/list',
+    method: 'get',
+    params: query
+  })
+}
+
+// 查询模板组详细（包含模板）
+export function getTemplateSet(setId) {
+  return request({
+    url: '/tool/gen/template/' + setId,
+    method: 'get'
+  })
+}
+
+// 查询内置模板
+export function listBuiltinTemplate() {
+  return request({
+    url: '/tool/gen/template/builtin',
+    method: 'get'
+  })
+}
+
+// 新增模板组
+export function addTemplateSet(data) {
+  return request({
+    url: '/tool/gen/template',
+    method: 'post',
+    data: data
+  })
+}
+
+// 修改模板组
+export function updateTemplateSet(data) {
+  return request({
+    url: '/tool/gen/template',
+    method: 'put',
+    data: data
+  })
+}
+
+// 删除模板组
+export function delTemplateSet(setIds) {
+  return request({
+    url: '/tool/gen/template/' + setIds,
+    method: 'delete'
+  })
+}
+
+// 保存模板
+export function saveTemplate(data) {
+  return request({
+    url: '/tool/gen/template/content',
+    method: 'put',
+    data: data
+  })
+}
+
+// 删除模板（恢复使用内置模板）
+export function delTemplate(templateId) {
+  return request({
+    url: '/tool/gen/template/content/' + templateId,
+    method: 'delete'
+  })
+}
+
+// 查询模板历史版本，指定version时返回该版本内容
+export function listTemplateHistory(templateId, version) {
+  return request({
+    url: '/tool/gen/template/history/' + templateId,
+    method: 'get',
+    params: { version: version }
+  })
+}
+
+// 回滚模板到指定版本
+export function rollbackTemplate(templateId, version) {
+  return request({
+    url: '/tool/gen/template/rollback/' + templateId + '/' + version,
+    method: 'put'
+  })
+}
+
+// 预览模板生成结果
+export function previewTemplate(data) {
+  return request({
+    url: '/tool/gen/template/preview',
+    method: 'post',
+    data: data
+  })
+}
@@ -144,6 +144,34 @@ export const dynamicRoutes = [
       }
     ]
   },
+  {
+    path: '/monitor/job-calendar',
+    component: Layout,
+    hidden: true,
+    permissions: ['monitor:job:list'],
+    children: [
+      {
+        path: 'index',
+        component: () => import('@/views/monitor/job/calendar'),
+        name: 'JobCalendar',
+        meta: { title: '排除日历', activeMenu: '/monitor/job' }
+      }
+    ]
+  },
+  {
+    path: '/monitor/job-graph',
+    component: Layout,
+    hidden: true,
+    permissions: ['monitor:job:list'],
+    children: [
+      {
+        path: 'index',
+        component: () => import('@/views/monitor/job/graph'),
+        name: 'JobGraph',
+        meta: { title: '依赖关系图', activeMenu: '/monitor/job' }
+      }
+    ]
+  },
   {
     path: '/tool/gen-edit',
     component: Layout,
@@ -157,6 +185,20 @@ export const dynamicRoutes = [
         meta: { title: '修改生成配置', activeMenu: '/tool/gen' }
       }
     ]
+  },
+  {
+    path: '/tool/gen-template',
+    component: Layout,
+    hidden: true,
+    permissions: ['tool:gen:edit'],
+    children: [
+      {
+        path: 'index',
+        component: () => import('@/views/tool/gen/template'),
+        name: 'GenTemplate',
+        meta: { title: '生成模板组', activeMenu: '/tool/gen' }
+      }
+    ]
   }
 ]
 
@@ -24,8 +24,9 @@ const useUserStore = defineStore(
         const password = userInfo.password
         const code = userInfo.code
         const uuid = userInfo.uuid
+        const tenantId = userInfo.tenantId ? Number(userInfo.tenantId) : undefined
         return new Promise((resolve, reject) => {
-          login(username, password, code, uuid).then(res => {
+          login(username, password, code, uuid, tenantId).then(res => {
             setToken(res.token)
             this.token = res.token
             resolve()
@@ -10,6 +10,8 @@ import useUserStore from '@/store/modules/user'
 let downloadLoadingInstance
 // 是否显示重新登录
 export let isRelogin = { show: false }
+// 是否显示重新加载（数据已被其他用户修改）
+export let isReload = { show: false }
 
 axios.defaults.headers['Content-Type'] = 'application/json;charset=utf-8'
 // 创建axios实例
@@ -94,6 +96,17 @@ service.interceptors.response.use(res => {
       })
     }
       return Promise.reject('无效的会话，或者会话已过期，请重新登录。')
+    } else if (code === 409) {
+      if (!isReload.show) {
+        isReload.show = true
+        ElMessageBox.confirm(msg + '。重新加载页面将获取最新数据，未保存的修改需要重新填写', '数据已变更', { confirmButtonText: '重新加载', cancelButtonText: '取消', type: 'warning' }).then(() => {
+          isReload.show = false
+          location.reload()
+        }).catch(() => {
+          isReload.show = false
+        })
+      }
+      return Promise.reject(new Error(msg))
     } else if (code === 500) {
       ElMessage({ message: msg, type: 'error' })
       return Promise.reject(new Error(msg))